@@ -0,0 +1,62 @@
+// Command f1gen generates the main.go of a scenario binary, registering all the functions matching
+// the signature of scenarios, func(*testing.T) testing.RunFn, in the packages given as arguments.
+//
+// It is meant to be run with go:generate from the package of the scenario binary:
+//
+//	//go:generate go run github.com/form3tech-oss/f1/v2/cmd/f1gen ./...
+//
+// Scenarios are registered with the name of their function, unless it is documented with an
+// f1:scenario comment naming the scenario, or skipping the function if the name is "-":
+//
+//	//f1:scenario create-payment
+//	func CreatePayment(t *testing.T) testing.RunFn {
+//
+// Only exported functions of packages other than main are registered.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/form3tech-oss/f1/v2/internal/scenariogen"
+)
+
+func main() {
+	output := flag.String("o", "main.go", "file to write the main package to")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: f1gen [-o main.go] [packages]\n\n"+
+			"Packages are directories relative to the output, or directories followed by /... for all "+
+			"the packages below them, and default to the directory of the output.\n\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if err := generate(*output, flag.Args()); err != nil {
+		fmt.Fprintf(os.Stderr, "f1gen: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func generate(output string, patterns []string) error {
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+
+	scenarios, err := scenariogen.Find(filepath.Dir(output), patterns)
+	if err != nil {
+		return fmt.Errorf("finding scenarios: %w", err)
+	}
+
+	source, err := scenariogen.Generate(scenarios)
+	if err != nil {
+		return fmt.Errorf("generating %s: %w", output, err)
+	}
+
+	if err := os.WriteFile(output, source, 0o644); err != nil { //nolint:gosec // generated source is not secret
+		return fmt.Errorf("writing %s: %w", output, err)
+	}
+
+	return nil
+}
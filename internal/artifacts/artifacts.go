@@ -0,0 +1,219 @@
+package artifacts
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/form3tech-oss/f1/v2/internal/artifacts/sigv4"
+)
+
+const azureBlobHostSuffix = ".blob.core.windows.net"
+
+var (
+	errUnsupportedScheme = errors.New("unsupported artifacts url scheme")
+	errNotAzureBlob      = errors.New("https artifacts url is not an Azure blob container")
+)
+
+// Store uploads the artifacts of runs to an S3 bucket, a GCS bucket or an Azure blob container.
+type Store struct {
+	client   *http.Client
+	provider provider
+	prefix   string
+}
+
+type provider interface {
+	// request returns a request uploading body to the object with the given key
+	request(ctx context.Context, key string, body []byte) (*http.Request, error)
+	// location returns the url of the object with the given key, without credentials
+	location(key string) string
+}
+
+// New returns a store for the destination url, which is one of:
+//
+//	s3://bucket/prefix
+//	gs://bucket/prefix
+//	azblob://account/container/prefix?<sas token>
+//	https://account.blob.core.windows.net/container/prefix?<sas token>
+//
+// Credentials are read with getenv from the environment variables of each provider's own
+// tools: AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, AWS_SESSION_TOKEN and AWS_REGION for S3 and
+// GOOGLE_OAUTH_ACCESS_TOKEN for GCS, while Azure uses the SAS token of the url. The endpoints of
+// azblob urls are overridden by AZURE_STORAGE_BLOB_ENDPOINT, such as for an emulator.
+func New(rawURL string, getenv func(string) string) (*Store, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing artifacts url: %w", err)
+	}
+
+	prefix := strings.Trim(u.Path, "/")
+
+	var p provider
+	switch u.Scheme {
+	case "s3":
+		p, err = newS3(u.Host, getenv)
+		if err != nil {
+			return nil, err
+		}
+	case "gs":
+		p = newGCS(u.Host, getenv)
+	case "azblob":
+		endpoint := &url.URL{Scheme: "https", Host: u.Host + azureBlobHostSuffix}
+		if override := getenv("AZURE_STORAGE_BLOB_ENDPOINT"); override != "" {
+			endpoint, err = url.Parse(override)
+			if err != nil {
+				return nil, fmt.Errorf("parsing AZURE_STORAGE_BLOB_ENDPOINT: %w", err)
+			}
+		}
+		p, prefix = newAzure(endpoint, prefix, u.RawQuery)
+	case "https":
+		if !strings.HasSuffix(u.Hostname(), azureBlobHostSuffix) {
+			return nil, fmt.Errorf("%w: %s", errNotAzureBlob, u.Host)
+		}
+		p, prefix = newAzure(&url.URL{Scheme: u.Scheme, Host: u.Host}, prefix, u.RawQuery)
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnsupportedScheme, u.Scheme)
+	}
+
+	return &Store{
+		client:   http.DefaultClient,
+		provider: p,
+		prefix:   prefix,
+	}, nil
+}
+
+// Upload uploads body as the object name under the prefix of the store, returning its url.
+func (s *Store) Upload(ctx context.Context, name string, body []byte) (string, error) {
+	key := name
+	if s.prefix != "" {
+		key = s.prefix + "/" + name
+	}
+
+	req, err := s.provider.request(ctx, key, body)
+	if err != nil {
+		return "", fmt.Errorf("creating upload request for '%s': %w", name, err)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("uploading '%s': %w", name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("uploading '%s': unexpected status %s: %s", name, resp.Status, message)
+	}
+
+	return s.provider.location(key), nil
+}
+
+// escapePath escapes each segment of an object key, keeping only unreserved characters.
+func escapePath(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = sigv4.Encode(segment)
+	}
+
+	return strings.Join(segments, "/")
+}
+
+func newPutRequest(ctx context.Context, u *url.URL, body []byte) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	return req, nil
+}
+
+type gcs struct {
+	endpoint *url.URL
+	bucket   string
+	token    string
+}
+
+func newGCS(bucket string, getenv func(string) string) *gcs {
+	endpoint := &url.URL{Scheme: "https", Host: "storage.googleapis.com"}
+	// the emulator host is used by the official clients to target a local emulator
+	if host := getenv("STORAGE_EMULATOR_HOST"); host != "" {
+		endpoint = &url.URL{Scheme: "http", Host: strings.TrimPrefix(host, "http://")}
+	}
+
+	return &gcs{
+		endpoint: endpoint,
+		bucket:   bucket,
+		token:    getenv("GOOGLE_OAUTH_ACCESS_TOKEN"),
+	}
+}
+
+func (g *gcs) request(ctx context.Context, key string, body []byte) (*http.Request, error) {
+	req, err := newPutRequest(ctx, g.objectURL(key), body)
+	if err != nil {
+		return nil, err
+	}
+
+	if g.token != "" {
+		req.Header.Set("Authorization", "Bearer "+g.token)
+	}
+
+	return req, nil
+}
+
+func (g *gcs) location(key string) string {
+	return g.objectURL(key).String()
+}
+
+func (g *gcs) objectURL(key string) *url.URL {
+	return g.endpoint.JoinPath(g.bucket, key)
+}
+
+type azure struct {
+	// endpoint is the blob endpoint of the storage account
+	endpoint  *url.URL
+	container string
+	// sasToken is the query of the destination url, authorizing the upload
+	sasToken string
+}
+
+// newAzure returns the container of the blob endpoint named by the first segment of path, with the
+// rest of path as the prefix of the store.
+func newAzure(endpoint *url.URL, path, sasToken string) (*azure, string) {
+	container, prefix, _ := strings.Cut(path, "/")
+
+	return &azure{endpoint: endpoint, container: container, sasToken: sasToken}, prefix
+}
+
+func (a *azure) request(ctx context.Context, key string, body []byte) (*http.Request, error) {
+	u := a.objectURL(key)
+	u.RawQuery = a.sasToken
+
+	req, err := newPutRequest(ctx, u, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Ms-Blob-Type", "BlockBlob")
+
+	return req, nil
+}
+
+func (a *azure) location(key string) string {
+	return a.objectURL(key).String()
+}
+
+func (a *azure) objectURL(key string) *url.URL {
+	base := strings.TrimSuffix(a.endpoint.Path, "/")
+	rawBase := strings.TrimSuffix(a.endpoint.EscapedPath(), "/")
+
+	return &url.URL{
+		Scheme:  a.endpoint.Scheme,
+		Host:    a.endpoint.Host,
+		Path:    base + "/" + a.container + "/" + key,
+		RawPath: rawBase + "/" + escapePath(a.container) + "/" + escapePath(key),
+	}
+}
@@ -0,0 +1,98 @@
+package artifacts
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/artifacts/sigv4"
+)
+
+const s3DefaultRegion = "us-east-1"
+
+var errMissingCredentials = errors.New("missing AWS_ACCESS_KEY_ID or AWS_SECRET_ACCESS_KEY")
+
+type s3 struct {
+	now      func() time.Time
+	endpoint *url.URL
+	bucket   string
+	region   string
+	creds    sigv4.Credentials
+	// pathStyle addresses the bucket in the path, as required by custom endpoints
+	pathStyle bool
+}
+
+func newS3(bucket string, getenv func(string) string) (*s3, error) {
+	creds := sigv4.Credentials{
+		AccessKeyID:     getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    getenv("AWS_SESSION_TOKEN"),
+	}
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return nil, fmt.Errorf("s3 artifacts: %w", errMissingCredentials)
+	}
+
+	region := getenv("AWS_REGION")
+	if region == "" {
+		region = getenv("AWS_DEFAULT_REGION")
+	}
+	if region == "" {
+		region = s3DefaultRegion
+	}
+
+	store := &s3{
+		now:      time.Now,
+		endpoint: &url.URL{Scheme: "https", Host: bucket + ".s3." + region + ".amazonaws.com"},
+		bucket:   bucket,
+		region:   region,
+		creds:    creds,
+	}
+
+	if endpoint := getenv("AWS_ENDPOINT_URL_S3"); endpoint != "" {
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("parsing AWS_ENDPOINT_URL_S3: %w", err)
+		}
+		store.endpoint = u
+		store.pathStyle = true
+	}
+
+	return store, nil
+}
+
+func (s *s3) request(ctx context.Context, key string, body []byte) (*http.Request, error) {
+	req, err := newPutRequest(ctx, s.objectURL(key), body)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha256.Sum256(body)
+	payloadHash := hex.EncodeToString(hash[:])
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	sigv4.Sign(req, payloadHash, s.creds, s.region, "s3", s.now())
+
+	return req, nil
+}
+
+func (s *s3) location(key string) string {
+	return s.objectURL(key).String()
+}
+
+func (s *s3) objectURL(key string) *url.URL {
+	path, rawPath := "/"+key, "/"+escapePath(key)
+	if s.pathStyle {
+		path, rawPath = "/"+s.bucket+path, "/"+escapePath(s.bucket)+rawPath
+	}
+
+	return &url.URL{
+		Scheme:  s.endpoint.Scheme,
+		Host:    s.endpoint.Host,
+		Path:    path,
+		RawPath: rawPath,
+	}
+}
@@ -0,0 +1,125 @@
+// Package sigv4 signs http requests to AWS with Signature Version 4, for the few requests f1 sends
+// to AWS without depending on its SDK.
+package sigv4
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// EmptyPayloadHash is the hash of the payload of requests without body
+	EmptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+	dateFormat = "20060102T150405Z"
+	dayFormat  = "20060102"
+	algorithm  = "AWS4-HMAC-SHA256"
+)
+
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// Sign signs the request to the service in the region at now, covering the host and every header
+// of the request, with the hex encoded sha256 of its payload. Services such as S3 also expect the
+// hash of the payload in the X-Amz-Content-Sha256 header, which is set by the caller.
+func Sign(req *http.Request, payloadHash string, creds Credentials, region, service string, now time.Time) {
+	now = now.UTC()
+	req.Header.Set("X-Amz-Date", now.Format(dateFormat))
+	if creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.Join(values, ",")
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(headers[name]) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := now.Format(dayFormat) + "/" + region + "/" + service + "/aws4_request"
+	canonicalRequestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		algorithm,
+		now.Format(dateFormat),
+		scope,
+		hex.EncodeToString(canonicalRequestHash[:]),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+creds.SecretAccessKey), now.Format(dayFormat))
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		algorithm, creds.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// canonicalQuery returns the parameters of the query of the request sorted by name, then value,
+// each encoded, with parameters without value, such as ?lifecycle, given an empty value.
+func canonicalQuery(req *http.Request) string {
+	query := req.URL.Query()
+
+	params := make([]string, 0, len(query))
+	for name, values := range query {
+		for _, value := range values {
+			params = append(params, Encode(name)+"="+Encode(value))
+		}
+	}
+	slices.Sort(params)
+
+	return strings.Join(params, "&")
+}
+
+// Encode encodes s as a segment of a path or a parameter of a query, escaping every byte but the
+// unreserved characters of RFC 3986.
+func Encode(s string) string {
+	var builder strings.Builder
+	for _, b := range []byte(s) {
+		if isUnreserved(b) {
+			builder.WriteByte(b)
+		} else {
+			fmt.Fprintf(&builder, "%%%02X", b)
+		}
+	}
+
+	return builder.String()
+}
+
+func isUnreserved(b byte) bool {
+	return ('A' <= b && b <= 'Z') || ('a' <= b && b <= 'z') || ('0' <= b && b <= '9') ||
+		b == '-' || b == '_' || b == '.' || b == '~'
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
@@ -0,0 +1,140 @@
+// Package audit writes the audit log of a run, a json line for every iteration, so that the load
+// generated by a run can be evidenced iteration by iteration.
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// BufferSize is the number of entries waiting to be written which the log holds, beyond which
+// entries are dropped rather than slowing iterations down.
+const BufferSize = 8192
+
+// Entry is the record of an iteration in the audit log.
+type Entry struct {
+	Iteration string `json:"iteration"`
+	// Operation is the operation the iteration was triggered for, see testing.T.Operation
+	Operation string `json:"operation,omitempty"`
+	// Worker is the worker of the pool which ran the iteration
+	Worker   string        `json:"worker"`
+	Start    time.Time     `json:"start"`
+	Duration time.Duration `json:"duration"`
+	Result   string        `json:"result"`
+	// Fields are the custom fields set by the iteration, see testing.T.AuditField
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+// Log writes entries to its file in the background, as newline delimited json.
+type Log struct {
+	file    *os.File
+	entries chan Entry
+	done    chan struct{}
+	// err is the first error writing the log, read once done is closed
+	err     error
+	dropped atomic.Uint64
+
+	// mu guards closed, so that iterations outliving the run don't record to a closed log
+	mu     sync.RWMutex
+	closed bool
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// Open creates the log file at path, and starts writing the entries recorded to it.
+func Open(path string, bufferSize int) (*Log, error) {
+	file, err := os.Create(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("creating audit log: %w", err)
+	}
+
+	l := &Log{
+		file:    file,
+		entries: make(chan Entry, bufferSize),
+		done:    make(chan struct{}),
+	}
+	go l.write()
+
+	return l, nil
+}
+
+// Record queues the entry to be written without waiting. The entry is dropped, and counted in
+// Dropped, when the buffer of the log is full or the log is closed.
+func (l *Log) Record(entry Entry) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if l.closed {
+		l.dropped.Add(1)
+		return
+	}
+
+	select {
+	case l.entries <- entry:
+	default:
+		l.dropped.Add(1)
+	}
+}
+
+// Dropped returns the number of entries which weren't written because the buffer was full.
+func (l *Log) Dropped() uint64 {
+	return l.dropped.Load()
+}
+
+// Path returns the path of the log file.
+func (l *Log) Path() string {
+	return l.file.Name()
+}
+
+// Close writes the entries still buffered and closes the file. It can be called more than once.
+func (l *Log) Close() error {
+	l.closeOnce.Do(func() {
+		l.mu.Lock()
+		l.closed = true
+		close(l.entries)
+		l.mu.Unlock()
+
+		<-l.done
+		if err := l.file.Close(); err != nil && l.err == nil {
+			l.err = fmt.Errorf("closing audit log: %w", err)
+		}
+		l.closeErr = l.err
+	})
+
+	return l.closeErr
+}
+
+func (l *Log) write() {
+	defer close(l.done)
+
+	writer := bufio.NewWriter(l.file)
+	encoder := json.NewEncoder(writer)
+	for entry := range l.entries {
+		if l.err != nil {
+			continue
+		}
+		if err := encoder.Encode(entry); err != nil {
+			l.err = fmt.Errorf("writing audit log: %w", err)
+			continue
+		}
+		// flush when the buffer is drained, so that the log is complete whenever the run is idle
+		if len(l.entries) == 0 {
+			if err := writer.Flush(); err != nil {
+				l.err = fmt.Errorf("writing audit log: %w", err)
+			}
+		}
+	}
+
+	if l.err == nil {
+		if err := writer.Flush(); err != nil {
+			l.err = fmt.Errorf("writing audit log: %w", err)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package audit_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/audit"
+)
+
+func readEntries(t *testing.T, path string) []audit.Entry {
+	t.Helper()
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var entries []audit.Entry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry audit.Entry
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		entries = append(entries, entry)
+	}
+	require.NoError(t, scanner.Err())
+
+	return entries
+}
+
+func TestLogWritesAnEntryPerLine(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	log, err := audit.Open(path, audit.BufferSize)
+	require.NoError(t, err)
+
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	entries := []audit.Entry{
+		{
+			Iteration: "0",
+			Worker:    "trigger-0",
+			Start:     start,
+			Duration:  20 * time.Millisecond,
+			Result:    "success",
+			Fields:    map[string]string{"payment_id": "1"},
+		},
+		{
+			Iteration: "1",
+			Operation: "refund",
+			Worker:    "trigger-1",
+			Start:     start.Add(time.Millisecond),
+			Duration:  time.Second,
+			Result:    "fail",
+		},
+	}
+	for _, entry := range entries {
+		log.Record(entry)
+	}
+	require.NoError(t, log.Close())
+
+	assert.Equal(t, entries, readEntries(t, path))
+	assert.Zero(t, log.Dropped())
+}
+
+func TestLogDropsEntriesRecordedOnceClosed(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	log, err := audit.Open(path, audit.BufferSize)
+	require.NoError(t, err)
+	require.NoError(t, log.Close())
+
+	log.Record(audit.Entry{Iteration: "0", Result: "success"})
+
+	require.NoError(t, log.Close())
+	assert.Empty(t, readEntries(t, path))
+	assert.Equal(t, uint64(1), log.Dropped())
+}
@@ -0,0 +1,242 @@
+// Package barrier synchronises the start of the runs of independent f1 processes, so that they
+// start triggering iterations at the same instant.
+package barrier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/control"
+)
+
+// WaitPath is the path processes wait at the barrier on.
+const WaitPath = "/wait"
+
+// Release is the response of the barrier to the processes waiting at it, once all have arrived.
+type Release struct {
+	// StartAt is when the processes start triggering iterations
+	StartAt time.Time `json:"start_at"`
+	// Round counts the times the barrier released processes, starting from 1
+	Round int `json:"round"`
+}
+
+// Server releases the processes waiting at it once the expected number of parties have arrived.
+// The processes are released with a start time lead after the last arrival, so that all of them
+// receive it before they start. The barrier is reusable: processes arriving after a release wait
+// for the next round.
+type Server struct {
+	control *control.Server
+	// onRelease is called with every release of the barrier, see OnRelease
+	onRelease func(Release, int)
+	// closed is closed on Shutdown, to turn away the processes waiting at the barrier
+	closed  chan struct{}
+	current *round
+	parties int
+	lead    time.Duration
+	mu      sync.Mutex
+}
+
+// round is the processes waiting at the barrier to be released together.
+type round struct {
+	// released is closed once release is set
+	released chan struct{}
+	// waiting are the contexts of the requests of the processes waiting in the round, so that
+	// processes which gave up aren't counted even before their handler noticed it
+	waiting []context.Context
+	// written counts the processes the release was written to, once responded is done
+	written   atomic.Int64
+	responded sync.WaitGroup
+	release   Release
+}
+
+// NewServer listens on addr for processes to wait at the barrier.
+func NewServer(addr string, parties int, lead time.Duration) (*Server, error) {
+	if parties < 1 {
+		return nil, fmt.Errorf("parties %d must be at least 1", parties)
+	}
+	if lead < 0 {
+		return nil, fmt.Errorf("lead %s can't be negative", lead)
+	}
+
+	server, err := control.New(addr)
+	if err != nil {
+		return nil, fmt.Errorf("starting barrier server: %w", err)
+	}
+
+	s := &Server{
+		control: server,
+		closed:  make(chan struct{}),
+		current: &round{released: make(chan struct{}), release: Release{Round: 1}},
+		parties: parties,
+		lead:    lead,
+	}
+	server.Handle("POST "+WaitPath, http.HandlerFunc(s.wait))
+
+	return s, nil
+}
+
+// Addr returns the address the server listens on.
+func (s *Server) Addr() string {
+	return s.control.Addr()
+}
+
+// OnRelease sets a function called every time processes are released, with the number of processes
+// the release was written to, before Start.
+func (s *Server) OnRelease(fn func(release Release, processes int)) {
+	s.onRelease = fn
+}
+
+// Start serves the barrier in the background until it is shut down.
+func (s *Server) Start() {
+	s.control.Start()
+}
+
+// Shutdown stops the server, turning away the processes waiting at the barrier.
+func (s *Server) Shutdown(ctx context.Context) error {
+	close(s.closed)
+	if err := s.control.Shutdown(ctx); err != nil {
+		return fmt.Errorf("shutting down barrier: %w", err)
+	}
+	return nil
+}
+
+func (s *Server) wait(w http.ResponseWriter, r *http.Request) {
+	current := s.arrive(r.Context())
+	if current == nil {
+		return
+	}
+
+	select {
+	case <-current.released:
+		if writeRelease(w, current.release) {
+			current.written.Add(1)
+		}
+		current.responded.Done()
+	case <-s.closed:
+		s.leave(r.Context(), current)
+		control.WriteJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "barrier shut down"})
+	case <-r.Context().Done():
+		s.leave(r.Context(), current)
+	}
+}
+
+// writeRelease writes the release to a process, and returns whether it was written.
+func writeRelease(w http.ResponseWriter, release Release) bool {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(release); err != nil {
+		return false
+	}
+
+	return http.NewResponseController(w).Flush() == nil
+}
+
+// arrive counts a process arriving at the barrier, releasing the round if it is the last party.
+// It returns the round of the process, or nil if the process gave up already.
+func (s *Server) arrive(ctx context.Context) *round {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	current := s.current
+	current.waiting = slices.DeleteFunc(current.waiting, func(waiting context.Context) bool {
+		return waiting.Err() != nil
+	})
+	current.waiting = append(current.waiting, ctx)
+	if len(current.waiting) < s.parties {
+		return current
+	}
+
+	current.release = Release{StartAt: time.Now().Add(s.lead), Round: current.release.Round}
+	current.responded.Add(len(current.waiting))
+	s.current = &round{released: make(chan struct{}), release: Release{Round: current.release.Round + 1}}
+	close(current.released)
+
+	if s.onRelease != nil {
+		go func() {
+			current.responded.Wait()
+			s.onRelease(current.release, int(current.written.Load()))
+		}()
+	}
+
+	return current
+}
+
+// leave uncounts a process which gave up waiting. If its round was released meanwhile, the process
+// is accounted as not written to.
+func (s *Server) leave(ctx context.Context, current *round) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := slices.Index(current.waiting, ctx)
+	if i < 0 {
+		return
+	}
+	if s.current == current {
+		current.waiting = slices.Delete(current.waiting, i, i+1)
+		return
+	}
+	current.responded.Done()
+}
+
+// Waiting returns the number of processes waiting at the barrier for the current round.
+func (s *Server) Waiting() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	waiting := 0
+	for _, ctx := range s.current.waiting {
+		if ctx.Err() == nil {
+			waiting++
+		}
+	}
+
+	return waiting
+}
+
+// URL returns the url to wait at the barrier on, for a url or a host:port address of a barrier.
+func URL(barrier string) string {
+	if !strings.Contains(barrier, "://") {
+		barrier = "http://" + barrier
+	}
+	if !strings.HasSuffix(barrier, WaitPath) {
+		barrier = strings.TrimSuffix(barrier, "/") + WaitPath
+	}
+
+	return barrier
+}
+
+// Wait waits at the barrier until all the parties have arrived, and returns when they start.
+func Wait(ctx context.Context, barrier string) (time.Time, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, URL(barrier), nil)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("creating barrier request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("waiting at barrier: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return time.Time{}, fmt.Errorf("waiting at barrier: unexpected status %s", resp.Status)
+	}
+
+	var release Release
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return time.Time{}, fmt.Errorf("decoding barrier release: %w", err)
+	}
+
+	return release.StartAt, nil
+}
@@ -0,0 +1,84 @@
+package barrier
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+)
+
+const (
+	flagListen  = "listen"
+	flagParties = "parties"
+	flagLead    = "lead"
+
+	shutdownTimeout = 5 * time.Second
+)
+
+// Cmd returns the barrier command, which serves a barrier for f1 processes run with
+// --sync-barrier to start at the same instant.
+func Cmd(output *ui.Output) *cobra.Command {
+	barrierCmd := &cobra.Command{
+		Use:   "barrier",
+		Short: "Serves a barrier synchronising the start of independent f1 processes",
+		Long: `Serves a barrier synchronising the start of independent f1 processes, for example on
+several hosts. Processes run with --sync-barrier wait at the barrier once their setup completed,
+until the number of parties have arrived. They are then released together with a start time
+--lead ahead, and start triggering iterations at that instant. The barrier serves rounds of
+processes until it is interrupted. For example, to start 3 processes together:
+
+  f1 barrier --listen :7070 --parties 3
+  f1 run constant myScenario --rate 100/s --sync-barrier barrier-host:7070`,
+		Args: cobra.NoArgs,
+		RunE: barrierCmdExecute(output),
+	}
+
+	barrierCmd.Flags().String(flagListen, ":7070", "`address` to listen for processes on")
+	barrierCmd.Flags().Int(flagParties, 2, "number of processes released together")
+	barrierCmd.Flags().Duration(flagLead, time.Second,
+		"how long after the last process arrived the processes start, so that all of them are released before")
+
+	return barrierCmd
+}
+
+func barrierCmdExecute(output *ui.Output) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, _ []string) error {
+		cmd.SilenceUsage = true
+
+		listen, err := cmd.Flags().GetString(flagListen)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		parties, err := cmd.Flags().GetInt(flagParties)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		lead, err := cmd.Flags().GetDuration(flagLead)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+
+		server, err := NewServer(listen, parties, lead)
+		if err != nil {
+			return err
+		}
+		server.OnRelease(func(release Release, processes int) {
+			output.Display(ui.InfoMessage{Message: fmt.Sprintf("round %d: %d processes start at %s",
+				release.Round, processes, release.StartAt.Format(time.RFC3339Nano))})
+		})
+		server.Start()
+		output.Display(ui.InfoMessage{
+			Message: fmt.Sprintf("barrier waiting for %d processes on %s", parties, server.Addr()),
+		})
+
+		<-cmd.Context().Done()
+
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+
+		return server.Shutdown(ctx)
+	}
+}
@@ -0,0 +1,177 @@
+package barrier_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/barrier"
+)
+
+func startServer(t *testing.T, parties int, lead time.Duration) *barrier.Server {
+	t.Helper()
+
+	server, err := barrier.NewServer("127.0.0.1:0", parties, lead)
+	require.NoError(t, err)
+	server.Start()
+	t.Cleanup(func() {
+		require.NoError(t, server.Shutdown(context.Background()))
+	})
+
+	return server
+}
+
+// waitAll waits at the barrier with the given number of processes, and returns their start times.
+func waitAll(ctx context.Context, t *testing.T, server *barrier.Server, processes int) []time.Time {
+	t.Helper()
+
+	starts := make([]time.Time, processes)
+	errs := make([]error, processes)
+	var wg sync.WaitGroup
+	for i := range processes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			starts[i], errs[i] = barrier.Wait(ctx, server.Addr())
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+
+	return starts
+}
+
+// releases collects the releases of the barrier with the number of processes they were written to.
+func releases(server *barrier.Server) <-chan releaseEvent {
+	events := make(chan releaseEvent, 10)
+	server.OnRelease(func(release barrier.Release, processes int) {
+		events <- releaseEvent{release: release, processes: processes}
+	})
+
+	return events
+}
+
+type releaseEvent struct {
+	release   barrier.Release
+	processes int
+}
+
+func nextRelease(t *testing.T, events <-chan releaseEvent) releaseEvent {
+	t.Helper()
+
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "the barrier did not release the processes")
+		return releaseEvent{}
+	}
+}
+
+func TestProcessesAreReleasedTogether(t *testing.T) {
+	t.Parallel()
+
+	server := startServer(t, 3, 200*time.Millisecond)
+	events := releases(server)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	before := time.Now()
+	first := waitAll(ctx, t, server, 3)
+	firstRelease := nextRelease(t, events)
+	second := waitAll(ctx, t, server, 3)
+	secondRelease := nextRelease(t, events)
+
+	assert.Equal(t, 1, firstRelease.release.Round)
+	assert.Equal(t, 2, secondRelease.release.Round)
+	assert.Equal(t, 3, firstRelease.processes)
+	assert.Equal(t, 3, secondRelease.processes)
+	for _, start := range first {
+		assert.True(t, start.Equal(firstRelease.release.StartAt))
+	}
+	for _, start := range second {
+		assert.True(t, start.Equal(secondRelease.release.StartAt))
+	}
+	assert.GreaterOrEqual(t, firstRelease.release.StartAt.Sub(before), 200*time.Millisecond)
+}
+
+func TestProcessesWhichGiveUpAreNotCounted(t *testing.T) {
+	t.Parallel()
+
+	server := startServer(t, 2, 0)
+	events := releases(server)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err := barrier.Wait(ctx, server.Addr())
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	// the server notices that the process gave up when its connection is closed
+	require.Eventually(t, func() bool { return server.Waiting() == 0 }, 5*time.Second, 10*time.Millisecond)
+
+	// the process which gave up doesn't release the next process on its own
+	ctx, cancel = context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	_, err = barrier.Wait(ctx, server.Addr())
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Eventually(t, func() bool { return server.Waiting() == 0 }, 5*time.Second, 10*time.Millisecond)
+
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	waitAll(ctx, t, server, 2)
+
+	release := nextRelease(t, events)
+	assert.Equal(t, 1, release.release.Round)
+	assert.Equal(t, 2, release.processes)
+}
+
+func TestWaitingProcessesAreTurnedAwayOnShutdown(t *testing.T) {
+	t.Parallel()
+
+	server, err := barrier.NewServer("127.0.0.1:0", 2, 0)
+	require.NoError(t, err)
+	server.Start()
+
+	waitErr := make(chan error)
+	go func() {
+		_, err := barrier.Wait(context.Background(), server.Addr())
+		waitErr <- err
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	require.NoError(t, server.Shutdown(context.Background()))
+	require.ErrorContains(t, <-waitErr, "503 Service Unavailable")
+}
+
+func TestURL(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		barrier  string
+		expected string
+	}{
+		{barrier: "barrier:7070", expected: "http://barrier:7070/wait"},
+		{barrier: "http://barrier:7070", expected: "http://barrier:7070/wait"},
+		{barrier: "https://barrier.example.com/", expected: "https://barrier.example.com/wait"},
+		{barrier: "https://barrier.example.com/wait", expected: "https://barrier.example.com/wait"},
+	} {
+		assert.Equal(t, test.expected, barrier.URL(test.barrier), test.barrier)
+	}
+}
+
+func TestInvalidBarriers(t *testing.T) {
+	t.Parallel()
+
+	_, err := barrier.NewServer("127.0.0.1:0", 0, time.Second)
+	require.ErrorContains(t, err, "parties 0 must be at least 1")
+
+	_, err = barrier.NewServer("127.0.0.1:0", 1, -time.Second)
+	require.ErrorContains(t, err, "lead -1s can't be negative")
+}
@@ -0,0 +1,81 @@
+package calibrate
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// MaxSchedulingError is the largest fraction of the expected iterations which a sustained rate
+// can miss or exceed.
+const MaxSchedulingError = 0.01
+
+// Step is the result of running the no-op scenario at one rate.
+type Step struct {
+	// Rate is the requested number of iterations per second
+	Rate     int
+	Started  uint64
+	Dropped  uint64
+	Duration time.Duration
+}
+
+// Expected returns the number of iterations the rate should have started during the step.
+func (s Step) Expected() float64 {
+	return float64(s.Rate) * s.Duration.Seconds()
+}
+
+// SchedulingError returns the fraction of the expected iterations which were not started, or
+// were started in excess of the rate.
+func (s Step) SchedulingError() float64 {
+	expected := s.Expected()
+	if expected == 0 {
+		return 0
+	}
+
+	return math.Abs(expected-float64(s.Started)) / expected
+}
+
+// Sustained reports whether the host kept up with the rate of the step.
+func (s Step) Sustained() bool {
+	return s.Dropped == 0 && s.SchedulingError() < MaxSchedulingError
+}
+
+// StepRunner runs the no-op scenario at the given rate.
+type StepRunner func(ctx context.Context, rate int) (Step, error)
+
+// Calibrate runs steps at rates doubling from startRate, until a rate is not sustained or the
+// next rate would exceed maxRate. onStep is called with the result of every step.
+func Calibrate(ctx context.Context, startRate, maxRate int, runStep StepRunner, onStep func(Step)) ([]Step, error) {
+	var steps []Step
+	for rate := startRate; rate <= maxRate && ctx.Err() == nil; rate *= 2 {
+		step, err := runStep(ctx, rate)
+		if err != nil {
+			return steps, err
+		}
+		if ctx.Err() != nil {
+			// the step was interrupted, so its rate can't be judged
+			break
+		}
+
+		steps = append(steps, step)
+		onStep(step)
+
+		if !step.Sustained() {
+			break
+		}
+	}
+
+	return steps, nil
+}
+
+// MaxSustainedRate returns the highest rate sustained by the steps, or 0 if none was.
+func MaxSustainedRate(steps []Step) int {
+	maxRate := 0
+	for _, step := range steps {
+		if step.Sustained() {
+			maxRate = max(maxRate, step.Rate)
+		}
+	}
+
+	return maxRate
+}
@@ -0,0 +1,148 @@
+package calibrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/spf13/cobra"
+
+	"github.com/form3tech-oss/f1/v2/internal/envsettings"
+	"github.com/form3tech-oss/f1/v2/internal/metrics"
+	"github.com/form3tech-oss/f1/v2/internal/options"
+	"github.com/form3tech-oss/f1/v2/internal/run"
+	"github.com/form3tech-oss/f1/v2/internal/trigger/api"
+	"github.com/form3tech-oss/f1/v2/internal/trigger/constant"
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+	"github.com/form3tech-oss/f1/v2/pkg/f1/scenarios"
+	"github.com/form3tech-oss/f1/v2/pkg/f1/testing"
+)
+
+const (
+	flagStartRate    = "start-rate"
+	flagMaxRate      = "max-rate"
+	flagStepDuration = "step-duration"
+	flagConcurrency  = "concurrency"
+
+	noopScenario             = "calibrate"
+	waitForCompletionTimeout = 5 * time.Second
+)
+
+// Cmd returns the calibrate command, which measures the highest rate the host can trigger
+// iterations at.
+func Cmd(output *ui.Output) *cobra.Command {
+	calibrateCmd := &cobra.Command{
+		Use:   "calibrate",
+		Short: "Measures the maximum iteration rate this host can sustain",
+		Long: fmt.Sprintf(`Runs a no-op scenario at doubling rates, and reports the maximum rate this host can
+sustain while starting the expected number of iterations within %.0f%%. This helps to size the
+instances generating the load before running the real tests.`, 100*MaxSchedulingError),
+		Args: cobra.NoArgs,
+		RunE: calibrateCmdExecute(output),
+	}
+
+	calibrateCmd.Flags().Int(flagStartRate, 1000, "rate of the first step, in iterations per second")
+	calibrateCmd.Flags().Int(flagMaxRate, 1_000_000, "highest rate to try, in iterations per second")
+	calibrateCmd.Flags().Duration(flagStepDuration, 5*time.Second, "duration of each step")
+	calibrateCmd.Flags().Int(flagConcurrency, 100, "number of workers running the iterations")
+
+	return calibrateCmd
+}
+
+func calibrateCmdExecute(output *ui.Output) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, _ []string) error {
+		cmd.SilenceUsage = true
+
+		startRate, err := cmd.Flags().GetInt(flagStartRate)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		maxRate, err := cmd.Flags().GetInt(flagMaxRate)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		stepDuration, err := cmd.Flags().GetDuration(flagStepDuration)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		concurrency, err := cmd.Flags().GetInt(flagConcurrency)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+
+		if startRate < 1 || maxRate < startRate {
+			return fmt.Errorf("invalid rates: start rate %d must be positive and not above max rate %d",
+				startRate, maxRate)
+		}
+		if concurrency < 1 {
+			return fmt.Errorf("concurrency %d can't be less than 1", concurrency)
+		}
+		if stepDuration < time.Second {
+			return errors.New("step duration can't be less than 1s")
+		}
+
+		runStep := NewStepRunner(stepDuration, concurrency)
+		steps, err := Calibrate(cmd.Context(), startRate, maxRate, runStep, func(step Step) {
+			output.Display(stepMessage{step: step})
+		})
+		if err != nil {
+			return err
+		}
+
+		output.Display(resultMessage{maxRate: MaxSustainedRate(steps), steps: len(steps)})
+
+		return nil
+	}
+}
+
+// NewStepRunner returns a StepRunner which runs a no-op scenario at a constant rate for the
+// duration of a step.
+func NewStepRunner(duration time.Duration, concurrency int) StepRunner {
+	scenarioList := scenarios.New().Add(&scenarios.Scenario{
+		Name: noopScenario,
+		ScenarioFn: func(*testing.T) testing.RunFn {
+			return func(*testing.T) {}
+		},
+	})
+
+	return func(ctx context.Context, rate int) (Step, error) {
+		rates, err := constant.CalculateConstantRate(
+			0, fmt.Sprintf("%d/s", rate), string(api.RegularDistribution), api.DefaultDistributionWindow,
+		)
+		if err != nil {
+			return Step{}, fmt.Errorf("calculating rate: %w", err)
+		}
+
+		trigger := api.NewRatesTrigger(rates, fmt.Sprintf("%d/s", rate))
+
+		r, err := run.NewRun(options.RunOptions{
+			Scenario:      noopScenario,
+			MaxDuration:   duration,
+			Concurrency:   concurrency,
+			Verbose:       true,
+			IgnoreDropped: true,
+		}, scenarioList, trigger, waitForCompletionTimeout, envsettings.Settings{},
+			metrics.NewInstance(prometheus.NewRegistry(), false), ui.NewDiscardOutput())
+		if err != nil {
+			return Step{}, fmt.Errorf("new run: %w", err)
+		}
+
+		result, err := r.Do(ctx)
+		if err != nil {
+			return Step{}, fmt.Errorf("running step at %d/s: %w", rate, err)
+		}
+
+		snapshot := result.Snapshot()
+
+		return Step{
+			Rate:    rate,
+			Started: snapshot.IterationsStarted(),
+			Dropped: snapshot.DroppedIterationCount,
+			// the load phase stops slightly before the planned duration to avoid starting another
+			// tick, after the iterations of the last tick have been triggered
+			Duration: duration,
+		}, nil
+	}
+}
@@ -0,0 +1,108 @@
+package calibrate_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/calibrate"
+)
+
+func TestStepSustained(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name            string
+		step            calibrate.Step
+		schedulingError float64
+		sustained       bool
+	}{
+		{
+			name:            "all iterations started",
+			step:            calibrate.Step{Rate: 1000, Started: 5000, Duration: 5 * time.Second},
+			schedulingError: 0,
+			sustained:       true,
+		},
+		{
+			name:            "within the scheduling error",
+			step:            calibrate.Step{Rate: 1000, Started: 4960, Duration: 5 * time.Second},
+			schedulingError: 0.008,
+			sustained:       true,
+		},
+		{
+			name:            "too few iterations started",
+			step:            calibrate.Step{Rate: 1000, Started: 4900, Duration: 5 * time.Second},
+			schedulingError: 0.02,
+			sustained:       false,
+		},
+		{
+			name:            "too many iterations started",
+			step:            calibrate.Step{Rate: 1000, Started: 5100, Duration: 5 * time.Second},
+			schedulingError: 0.02,
+			sustained:       false,
+		},
+		{
+			name:            "dropped iterations",
+			step:            calibrate.Step{Rate: 1000, Started: 5000, Dropped: 1, Duration: 5 * time.Second},
+			schedulingError: 0,
+			sustained:       false,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.InDelta(t, test.schedulingError, test.step.SchedulingError(), 1e-9)
+			assert.Equal(t, test.sustained, test.step.Sustained())
+		})
+	}
+}
+
+func TestCalibrateStopsAtTheFirstRateNotSustained(t *testing.T) {
+	t.Parallel()
+
+	const hostLimit = 5000
+
+	var rates []int
+	steps, err := calibrate.Calibrate(context.Background(), 1000, 100_000,
+		func(_ context.Context, rate int) (calibrate.Step, error) {
+			rates = append(rates, rate)
+			started := uint64(min(rate, hostLimit))
+			return calibrate.Step{Rate: rate, Started: started, Duration: time.Second}, nil
+		},
+		func(calibrate.Step) {},
+	)
+
+	require.NoError(t, err)
+	assert.Equal(t, []int{1000, 2000, 4000, 8000}, rates)
+	assert.Len(t, steps, 4)
+	assert.Equal(t, 4000, calibrate.MaxSustainedRate(steps))
+}
+
+func TestCalibrateStopsAtTheMaxRate(t *testing.T) {
+	t.Parallel()
+
+	steps, err := calibrate.Calibrate(context.Background(), 1000, 5000,
+		func(_ context.Context, rate int) (calibrate.Step, error) {
+			return calibrate.Step{Rate: rate, Started: uint64(rate), Duration: time.Second}, nil
+		},
+		func(calibrate.Step) {},
+	)
+
+	require.NoError(t, err)
+	assert.Len(t, steps, 3)
+	assert.Equal(t, 4000, calibrate.MaxSustainedRate(steps))
+}
+
+func TestStepRunnerRunsTheRate(t *testing.T) {
+	t.Parallel()
+
+	step, err := calibrate.NewStepRunner(time.Second, 10)(context.Background(), 100)
+
+	require.NoError(t, err)
+	assert.Equal(t, 100, step.Rate)
+	assert.Equal(t, time.Second, step.Duration)
+	assert.True(t, step.Sustained(), "started %d iterations", step.Started)
+}
@@ -0,0 +1,59 @@
+package calibrate
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+)
+
+var (
+	_ ui.Outputable = (*stepMessage)(nil)
+	_ ui.Outputable = (*resultMessage)(nil)
+)
+
+type stepMessage struct {
+	step Step
+}
+
+func (m stepMessage) Print(printer *ui.Printer) {
+	status := "sustained"
+	if !m.step.Sustained() {
+		status = "not sustained"
+	}
+
+	printer.Println(fmt.Sprintf("%10d/s  started %10d of %10.0f  dropped %8d  error %6.2f%%  %s",
+		m.step.Rate, m.step.Started, m.step.Expected(), m.step.Dropped, 100*m.step.SchedulingError(), status))
+}
+
+func (m stepMessage) Log(logger *slog.Logger) {
+	logger.Info("calibration step finished",
+		slog.Int("rate", m.step.Rate),
+		slog.Uint64("iterations_started", m.step.Started),
+		slog.Float64("iterations_expected", m.step.Expected()),
+		slog.Uint64("dropped_iterations", m.step.Dropped),
+		slog.Float64("scheduling_error", m.step.SchedulingError()),
+		slog.Bool("sustained", m.step.Sustained()),
+	)
+}
+
+type resultMessage struct {
+	maxRate int
+	steps   int
+}
+
+func (m resultMessage) Print(printer *ui.Printer) {
+	if m.maxRate == 0 {
+		printer.Println("\nNone of the rates tried could be sustained, try a lower --start-rate")
+		return
+	}
+
+	printer.Println(fmt.Sprintf("\nMaximum sustained rate: %d/s", m.maxRate))
+}
+
+func (m resultMessage) Log(logger *slog.Logger) {
+	logger.Info("calibration finished",
+		slog.Int("max_sustained_rate", m.maxRate),
+		slog.Int("steps", m.steps),
+	)
+}
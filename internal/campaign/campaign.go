@@ -0,0 +1,68 @@
+package campaign
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/form3tech-oss/f1/v2/internal/run"
+)
+
+// Manifest is an ordered set of runs executed by `f1 campaign`.
+type Manifest struct {
+	Runs []Run `yaml:"runs"`
+}
+
+// Run is a run of a campaign. Args are the arguments of `f1 run`, starting with the trigger.
+type Run struct {
+	Name string   `yaml:"name"`
+	Args []string `yaml:"args"`
+	SLO  SLO      `yaml:"slo"`
+}
+
+// SLO are the objectives a run must meet for the campaign to proceed to the next run.
+type SLO = run.SLO
+
+func ReadManifest(path string) (Manifest, error) {
+	manifest := Manifest{}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return manifest, fmt.Errorf("reading campaign file '%s': %w", path, err)
+	}
+
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return manifest, fmt.Errorf("parsing campaign file '%s': %w", path, err)
+	}
+
+	if err := manifest.validate(); err != nil {
+		return manifest, fmt.Errorf("invalid campaign file '%s': %w", path, err)
+	}
+
+	return manifest, nil
+}
+
+func (m Manifest) validate() error {
+	if len(m.Runs) == 0 {
+		return errors.New("no runs")
+	}
+
+	names := make(map[string]struct{}, len(m.Runs))
+	for i, r := range m.Runs {
+		if r.Name == "" {
+			return fmt.Errorf("run %d has no name", i)
+		}
+		if _, ok := names[r.Name]; ok {
+			return fmt.Errorf("duplicate run name '%s'", r.Name)
+		}
+		names[r.Name] = struct{}{}
+
+		if len(r.Args) == 0 {
+			return fmt.Errorf("run '%s' has no args", r.Name)
+		}
+	}
+
+	return nil
+}
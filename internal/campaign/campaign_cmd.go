@@ -0,0 +1,217 @@
+package campaign
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/spf13/cobra"
+
+	"github.com/form3tech-oss/f1/v2/internal/reportindex"
+	"github.com/form3tech-oss/f1/v2/internal/run"
+	"github.com/form3tech-oss/f1/v2/internal/triggerflags"
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+)
+
+const (
+	flagReportFile = "report-file"
+	flagReportDir  = "report-dir"
+)
+
+// Cmd returns the campaign command. newRunCmd returns a new `f1 run` command for every run of the
+// campaign, so that flags set for a run do not leak into the next run.
+func Cmd(newRunCmd func() *cobra.Command, output *ui.Output) *cobra.Command {
+	campaignCmd := &cobra.Command{
+		Use:   "campaign <file>",
+		Short: "Runs an ordered set of test scenarios, proceeding only while they meet their SLOs",
+		Long: `Runs an ordered set of test scenarios described by a yaml campaign file, and prints a
+consolidated report of the campaign. Each run proceeds only if the previous runs passed and met
+their SLOs; the remaining runs are skipped otherwise. For example:
+
+  runs:
+  - name: baseline
+    args: [constant, payments, --rate, 10/s, --max-duration, 1m]
+    slo:
+      max-p95: 250ms
+      max-error-rate: 0.1
+  - name: peak
+    args: [ramp, payments, --start-rate, 10/s, --end-rate, 100/s, --max-duration, 5m]
+    slo:
+      max-p99: 1s`,
+		Args: cobra.ExactArgs(1),
+		RunE: campaignCmdExecute(newRunCmd, output),
+	}
+
+	campaignCmd.Flags().String(flagReportFile, "", "write a json report of the campaign to `file`")
+	campaignCmd.Flags().String(flagReportDir, "",
+		"keep the json report of every run in `dir`, along with an html index of all the runs of the dir")
+
+	return campaignCmd
+}
+
+func campaignCmdExecute(
+	newRunCmd func() *cobra.Command,
+	output *ui.Output,
+) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		manifest, err := ReadManifest(args[0])
+		if err != nil {
+			return err
+		}
+
+		reportFile, err := cmd.Flags().GetString(flagReportFile)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+
+		reportDir, err := cmd.Flags().GetString(flagReportDir)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+
+		dir, err := campaignDir(reportDir)
+		if err != nil {
+			return fmt.Errorf("creating campaign directory: %w", err)
+		}
+		// the reports of the runs are only kept in the report directory
+		if reportDir == "" {
+			defer os.RemoveAll(dir)
+		}
+
+		c := &campaign{
+			manifest:  manifest,
+			newRunCmd: newRunCmd,
+			dir:       dir,
+			output:    output,
+		}
+
+		report, err := c.run(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		output.Display(summaryMessage{report: report})
+
+		if reportDir != "" {
+			if _, err := reportindex.Write(reportDir); err != nil {
+				return fmt.Errorf("writing report index: %w", err)
+			}
+		}
+
+		if reportFile != "" {
+			if err := report.Write(reportFile); err != nil {
+				return fmt.Errorf("writing campaign report: %w", err)
+			}
+		}
+
+		if !report.Passed {
+			return errors.New("campaign failed - see log for details")
+		}
+
+		return nil
+	}
+}
+
+// campaignDir returns the directory the reports of the runs are written to, reportDir if set.
+func campaignDir(reportDir string) (string, error) {
+	if reportDir == "" {
+		dir, err := os.MkdirTemp("", "f1-campaign-")
+		if err != nil {
+			return "", fmt.Errorf("creating temporary directory: %w", err)
+		}
+		return dir, nil
+	}
+
+	if err := os.MkdirAll(reportDir, 0o755); err != nil {
+		return "", fmt.Errorf("creating %s: %w", reportDir, err)
+	}
+
+	return reportDir, nil
+}
+
+type campaign struct {
+	output    *ui.Output
+	newRunCmd func() *cobra.Command
+	dir       string
+	manifest  Manifest
+}
+
+func (c *campaign) run(ctx context.Context) (Report, error) {
+	report := Report{Passed: true}
+
+	for i, r := range c.manifest.Runs {
+		if !report.Passed || ctx.Err() != nil {
+			report.Passed = false
+			report.Runs = append(report.Runs, RunReport{Name: r.Name, Status: StatusSkipped})
+			continue
+		}
+
+		c.output.Display(runStartedMessage{name: r.Name, index: i, total: len(c.manifest.Runs)})
+
+		runReport, err := c.execute(ctx, i, r)
+		if err != nil {
+			return report, err
+		}
+
+		c.output.Display(runFinishedMessage{run: runReport})
+
+		report.Runs = append(report.Runs, runReport)
+		report.Passed = runReport.Status == StatusPassed
+	}
+
+	return report, nil
+}
+
+func (c *campaign) execute(ctx context.Context, index int, r Run) (RunReport, error) {
+	reportPath := filepath.Join(c.dir, reportFileName(index, r.Name))
+	// a report left in a reused report directory by a previous campaign isn't the result of this run
+	if err := os.Remove(reportPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return RunReport{}, fmt.Errorf("removing previous report of run '%s': %w", r.Name, err)
+	}
+
+	runCmd := c.newRunCmd()
+	runCmd.SilenceErrors = true
+	runCmd.SilenceUsage = true
+	runCmd.SetArgs(slices.Concat(r.Args, []string{"--" + triggerflags.FlagReportFile, reportPath}))
+
+	// a failed run returns an error too, its result is read from the report
+	runErr := runCmd.ExecuteContext(ctx)
+
+	report, err := run.ReadReport(reportPath)
+	if err != nil {
+		return RunReport{}, fmt.Errorf("run '%s' did not complete: %w", r.Name, errors.Join(runErr, err))
+	}
+
+	runReport := RunReport{
+		Name:          r.Name,
+		Status:        StatusPassed,
+		Report:        &report,
+		SLOViolations: r.SLO.Violations(report),
+	}
+	if report.Failed || len(runReport.SLOViolations) > 0 {
+		runReport.Status = StatusFailed
+	}
+
+	return runReport, nil
+}
+
+// reportFileName returns the name of the report of a run, numbered in the order of the campaign and
+// named after the run, with characters other than letters, digits, '-', '_' and '.' replaced.
+func reportFileName(index int, name string) string {
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("-_.", r) {
+			return r
+		}
+		return '_'
+	}, name)
+
+	return fmt.Sprintf("%02d-%s.json", index+1, name)
+}
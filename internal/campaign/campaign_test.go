@@ -0,0 +1,139 @@
+package campaign_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/campaign"
+	"github.com/form3tech-oss/f1/v2/internal/run"
+)
+
+func TestReadManifest(t *testing.T) {
+	t.Parallel()
+
+	maxErrorRate := 0.5
+
+	for _, test := range []struct {
+		name             string
+		manifest         string
+		expectedErr      string
+		expectedManifest campaign.Manifest
+	}{
+		{
+			name: "valid",
+			manifest: `
+runs:
+- name: baseline
+  args: [constant, payments, --rate, 10/s]
+  slo:
+    max-p95: 250ms
+    max-error-rate: 0.5
+- name: peak
+  args: [ramp, payments]
+`,
+			expectedManifest: campaign.Manifest{Runs: []campaign.Run{
+				{
+					Name: "baseline",
+					Args: []string{"constant", "payments", "--rate", "10/s"},
+					SLO:  campaign.SLO{MaxP95: 250 * time.Millisecond, MaxErrorRate: &maxErrorRate},
+				},
+				{Name: "peak", Args: []string{"ramp", "payments"}},
+			}},
+		},
+		{
+			name:        "no runs",
+			manifest:    "runs: []",
+			expectedErr: "no runs",
+		},
+		{
+			name: "missing name",
+			manifest: `
+runs:
+- args: [constant, payments]
+`,
+			expectedErr: "run 0 has no name",
+		},
+		{
+			name: "duplicate name",
+			manifest: `
+runs:
+- name: baseline
+  args: [constant, payments]
+- name: baseline
+  args: [constant, payments]
+`,
+			expectedErr: "duplicate run name 'baseline'",
+		},
+		{
+			name: "missing args",
+			manifest: `
+runs:
+- name: baseline
+`,
+			expectedErr: "run 'baseline' has no args",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "campaign.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(test.manifest), 0o600))
+
+			manifest, err := campaign.ReadManifest(path)
+			if test.expectedErr != "" {
+				require.ErrorContains(t, err, test.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedManifest, manifest)
+		})
+	}
+}
+
+func TestSLOViolations(t *testing.T) {
+	t.Parallel()
+
+	zero := 0.0
+	report := run.Report{
+		SuccessfulIterationDurations: run.DurationsReport{
+			Count: 99, P95: 200 * time.Millisecond, P99: 500 * time.Millisecond,
+		},
+		FailedIterationDurations: run.DurationsReport{Count: 1},
+		IterationsStarted:        100,
+	}
+
+	for _, test := range []struct {
+		name     string
+		expected []string
+		slo      campaign.SLO
+	}{
+		{
+			name: "no objectives",
+		},
+		{
+			name: "objectives met",
+			slo:  campaign.SLO{MaxP95: 200 * time.Millisecond, MaxP99: time.Second},
+		},
+		{
+			name: "objectives missed",
+			slo:  campaign.SLO{MaxP95: 100 * time.Millisecond, MaxP99: 400 * time.Millisecond, MaxErrorRate: &zero},
+			expected: []string{
+				"p95 200ms above 100ms",
+				"p99 500ms above 400ms",
+				"error rate 1.00% above 0.00%",
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, test.slo.Violations(report))
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package campaign
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+)
+
+var (
+	_ ui.Outputable = (*runStartedMessage)(nil)
+	_ ui.Outputable = (*runFinishedMessage)(nil)
+	_ ui.Outputable = (*summaryMessage)(nil)
+)
+
+type runStartedMessage struct {
+	name  string
+	index int
+	total int
+}
+
+func (m runStartedMessage) Print(printer *ui.Printer) {
+	printer.Println(fmt.Sprintf("Campaign run %d/%d: %s", m.index+1, m.total, m.name))
+}
+
+func (m runStartedMessage) Log(logger *slog.Logger) {
+	logger.Info("campaign run started",
+		slog.String("run", m.name),
+		slog.Int("index", m.index+1),
+		slog.Int("total", m.total),
+	)
+}
+
+type runFinishedMessage struct {
+	run RunReport
+}
+
+func (m runFinishedMessage) Print(printer *ui.Printer) {
+	line := fmt.Sprintf("Campaign run %s %s", m.run.Name, m.run.Status)
+	if len(m.run.SLOViolations) > 0 {
+		line += ": " + strings.Join(m.run.SLOViolations, ", ")
+	}
+	printer.Println(line)
+}
+
+func (m runFinishedMessage) Log(logger *slog.Logger) {
+	logger.Info("campaign run finished",
+		slog.String("run", m.run.Name),
+		slog.String("status", string(m.run.Status)),
+		slog.Any("slo_violations", m.run.SLOViolations),
+	)
+}
+
+type summaryMessage struct {
+	report Report
+}
+
+func (m summaryMessage) Print(printer *ui.Printer) {
+	status := "Campaign Passed"
+	if !m.report.Passed {
+		status = "Campaign Failed"
+	}
+
+	lines := []string{"", status, fmt.Sprintf("%-20s %-8s %12s %10s %12s", "run", "status", "iterations", "errors", "p95")}
+	for _, r := range m.report.Runs {
+		if r.Report == nil {
+			lines = append(lines, fmt.Sprintf("%-20s %-8s", r.Name, r.Status))
+			continue
+		}
+
+		lines = append(lines, fmt.Sprintf("%-20s %-8s %12d %9.2f%% %12s",
+			r.Name, r.Status, r.Report.IterationsStarted, r.Report.ErrorRate(),
+			r.Report.SuccessfulIterationDurations.P95))
+	}
+
+	printer.Println(strings.Join(lines, "\n"))
+}
+
+func (m summaryMessage) Log(logger *slog.Logger) {
+	for _, r := range m.report.Runs {
+		attrs := []any{slog.String("run", r.Name), slog.String("status", string(r.Status))}
+		if r.Report != nil {
+			attrs = append(attrs,
+				slog.Uint64("iterations_started", r.Report.IterationsStarted),
+				slog.Float64("error_rate", r.Report.ErrorRate()),
+				slog.Duration("p95", r.Report.SuccessfulIterationDurations.P95),
+			)
+		}
+		logger.Info("campaign run summary", attrs...)
+	}
+	logger.Info("campaign finished", slog.Bool("passed", m.report.Passed))
+}
@@ -0,0 +1,43 @@
+package campaign
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/form3tech-oss/f1/v2/internal/run"
+)
+
+type Status string
+
+const (
+	StatusPassed  Status = "passed"
+	StatusFailed  Status = "failed"
+	StatusSkipped Status = "skipped"
+)
+
+// Report is the consolidated report of a campaign.
+type Report struct {
+	Runs   []RunReport `json:"runs"`
+	Passed bool        `json:"passed"`
+}
+
+type RunReport struct {
+	Report        *run.Report `json:"report,omitempty"`
+	Name          string      `json:"name"`
+	Status        Status      `json:"status"`
+	SLOViolations []string    `json:"slo_violations,omitempty"`
+}
+
+func (r Report) Write(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshalling campaign report: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("writing campaign report file '%s': %w", path, err)
+	}
+
+	return nil
+}
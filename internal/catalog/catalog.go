@@ -0,0 +1,84 @@
+// Package catalog lists the scenarios of f1 binaries, by running them with "scenarios ls --json",
+// so that tooling can discover the scenarios of many scenario repositories at once.
+package catalog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Parameter is a parameter documented by a scenario.
+type Parameter struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Default     string `json:"default,omitempty"`
+}
+
+// Scenario is a scenario registered by a binary, as printed by "scenarios ls --json".
+type Scenario struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	Parameters  []Parameter `json:"parameters,omitempty"`
+}
+
+// Entry is a scenario of the catalog, with the binary registering it.
+type Entry struct {
+	Binary string `json:"binary"`
+	Scenario
+}
+
+// List returns the scenarios registered by binary, a path or the name of an executable in the
+// PATH, by running it with "scenarios ls --json".
+func List(ctx context.Context, binary string) ([]Scenario, error) {
+	path, err := exec.LookPath(binary)
+	if err != nil {
+		return nil, fmt.Errorf("finding %s: %w", binary, err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, path, "scenarios", "ls", "--json")
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		// f1 binaries report errors on stdout, unless they fail before running the command
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = strings.TrimSpace(stdout.String())
+		}
+		if message != "" {
+			return nil, fmt.Errorf("running %s: %w: %s", binary, err, message)
+		}
+		return nil, fmt.Errorf("running %s: %w", binary, err)
+	}
+
+	var scenarios []Scenario
+	if err := json.Unmarshal(stdout.Bytes(), &scenarios); err != nil {
+		return nil, fmt.Errorf("parsing the scenarios of %s, which may not be an f1 binary: %w", binary, err)
+	}
+
+	return scenarios, nil
+}
+
+// Build returns the scenarios of all binaries, in the order of the binaries. The scenarios of the
+// binaries which couldn't be listed are skipped, and their errors returned.
+func Build(ctx context.Context, binaries []string) ([]Entry, error) {
+	var entries []Entry
+	var errs []error
+	for _, binary := range binaries {
+		scenarios, err := List(ctx, binary)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, scenario := range scenarios {
+			entries = append(entries, Entry{Binary: binary, Scenario: scenario})
+		}
+	}
+
+	return entries, errors.Join(errs...)
+}
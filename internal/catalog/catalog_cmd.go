@@ -0,0 +1,81 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagJSON    = "json"
+	flagTimeout = "timeout"
+)
+
+// Cmd returns the catalog command, which lists the scenarios of other f1 binaries.
+func Cmd() *cobra.Command {
+	catalogCmd := &cobra.Command{
+		Use:   "catalog <binary>...",
+		Short: "Lists the scenarios of f1 binaries",
+		Long: `Lists the scenarios registered by each of the given f1 binaries, paths or names of
+executables in the PATH, by running them with "scenarios ls --json". Binaries which can't be
+listed are reported after the scenarios of the others. For example, to list the scenarios of
+the binaries of all scenario repositories as json:
+
+  f1 catalog --json ./bin/*`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: catalogCmdExecute,
+	}
+
+	catalogCmd.Flags().Bool(flagJSON, false, "print the scenarios as a json array")
+	catalogCmd.Flags().Duration(flagTimeout, 30*time.Second, "how long to wait for all binaries to list their scenarios")
+
+	return catalogCmd
+}
+
+func catalogCmdExecute(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	asJSON, err := cmd.Flags().GetBool(flagJSON)
+	if err != nil {
+		return fmt.Errorf("getting flag: %w", err)
+	}
+	timeout, err := cmd.Flags().GetDuration(flagTimeout)
+	if err != nil {
+		return fmt.Errorf("getting flag: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+	defer cancel()
+
+	entries, listErr := Build(ctx, args)
+
+	if asJSON {
+		if entries == nil {
+			entries = []Entry{}
+		}
+		encoder := json.NewEncoder(cmd.OutOrStdout())
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(entries); err != nil {
+			return fmt.Errorf("writing catalog: %w", err)
+		}
+	} else if len(entries) > 0 {
+		writer := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "SCENARIO\tBINARY\tDESCRIPTION")
+		for _, entry := range entries {
+			fmt.Fprintf(writer, "%s\t%s\t%s\n", entry.Name, entry.Binary, entry.Description)
+		}
+		if err := writer.Flush(); err != nil {
+			return fmt.Errorf("writing catalog: %w", err)
+		}
+	}
+
+	if listErr != nil {
+		return fmt.Errorf("listing scenarios: %w", listErr)
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package catalog_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/catalog"
+)
+
+// writeBinary writes an executable script standing in for an f1 binary, which prints stdout to
+// "scenarios ls --json" and exits with status.
+func writeBinary(t *testing.T, name, stdout string, status int) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	script := "#!/bin/sh\n[ \"$*\" = \"scenarios ls --json\" ] || exit 2\ncat <<'EOF'\n" + stdout + "\nEOF\n" +
+		"exit " + strconv.Itoa(status) + "\n"
+	require.NoError(t, os.WriteFile(path, []byte(script), 0o700)) //nolint:gosec // the script must be executable
+
+	return path
+}
+
+func TestBuild(t *testing.T) {
+	t.Parallel()
+
+	payments := writeBinary(t, "payments",
+		`[{"name": "create-payment", "description": "creates payments", "parameters": [{"name": "region"}]}]`, 0)
+	accounts := writeBinary(t, "accounts", `[{"name": "create-account"}, {"name": "fetch-account"}]`, 0)
+
+	entries, err := catalog.Build(context.Background(), []string{payments, accounts})
+
+	require.NoError(t, err)
+	assert.Equal(t, []catalog.Entry{
+		{Binary: payments, Scenario: catalog.Scenario{
+			Name:        "create-payment",
+			Description: "creates payments",
+			Parameters:  []catalog.Parameter{{Name: "region"}},
+		}},
+		{Binary: accounts, Scenario: catalog.Scenario{Name: "create-account"}},
+		{Binary: accounts, Scenario: catalog.Scenario{Name: "fetch-account"}},
+	}, entries)
+}
+
+func TestBuildSkipsTheBinariesWhichCantBeListed(t *testing.T) {
+	t.Parallel()
+
+	payments := writeBinary(t, "payments", `[{"name": "create-payment"}]`, 0)
+	failing := writeBinary(t, "failing", "unknown flag: --json", 1)
+	notF1 := writeBinary(t, "not-f1", "hello", 0)
+
+	entries, err := catalog.Build(context.Background(), []string{failing, payments, notF1})
+
+	assert.Equal(t, []catalog.Entry{{Binary: payments, Scenario: catalog.Scenario{Name: "create-payment"}}}, entries)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "running "+failing+": exit status 1: unknown flag: --json")
+	assert.Contains(t, err.Error(), "parsing the scenarios of "+notF1+", which may not be an f1 binary")
+}
+
+func TestCmdPrintsTheCatalog(t *testing.T) {
+	t.Parallel()
+
+	payments := writeBinary(t, "payments", `[{"name": "create-payment", "description": "creates payments"}]`, 0)
+
+	var stdout bytes.Buffer
+	cmd := catalog.Cmd()
+	cmd.SetOut(&stdout)
+	cmd.SetArgs([]string{payments})
+
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, "SCENARIO        BINARY"+spaces(len(payments)-6+2)+"DESCRIPTION\n"+
+		"create-payment  "+payments+"  creates payments\n", stdout.String())
+}
+
+func spaces(n int) string {
+	return string(bytes.Repeat([]byte{' '}, n))
+}
@@ -35,7 +35,7 @@ func (s *ChartTestStage) and() *ChartTestStage {
 
 func (s *ChartTestStage) i_execute_the_chart_command() *ChartTestStage {
 	outputer := ui.NewDiscardOutput()
-	cmd := chart.Cmd(trigger.GetBuilders(outputer), outputer)
+	cmd := chart.Cmd(trigger.GetBuilders(outputer, nil), outputer)
 	cmd.SetArgs(s.args)
 	s.err = cmd.Execute()
 	return s
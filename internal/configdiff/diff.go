@@ -0,0 +1,112 @@
+// Package configdiff compares the load planned by two config files of the file trigger, to review
+// changes to load profiles.
+package configdiff
+
+import (
+	"fmt"
+	"io/fs"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/trigger/file"
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+)
+
+// Diff is the load planned by two config files.
+type Diff struct {
+	Before, After file.Profile
+}
+
+// Row is a figure of the profiles compared by a diff.
+type Row struct {
+	Name          string
+	Before, After string
+	// Change is empty when the figure didn't change
+	Change string
+}
+
+// Load reads both config files, from the file system or from the embedded profiles, and returns
+// the load they plan from start.
+func Load(before, after string, profiles fs.FS, output *ui.Output, start time.Time) (Diff, error) {
+	beforeProfile, err := loadProfile(before, profiles, output, start)
+	if err != nil {
+		return Diff{}, err
+	}
+	afterProfile, err := loadProfile(after, profiles, output, start)
+	if err != nil {
+		return Diff{}, err
+	}
+
+	return Diff{Before: beforeProfile, After: afterProfile}, nil
+}
+
+func loadProfile(filename string, profiles fs.FS, output *ui.Output, start time.Time) (file.Profile, error) {
+	fileContent, err := file.ReadConfigFile(filename, profiles, output)
+	if err != nil {
+		return file.Profile{}, fmt.Errorf("reading %s: %w", filename, err)
+	}
+	runnableStages, err := file.ParseConfigFile(fileContent, start)
+	if err != nil {
+		return file.Profile{}, fmt.Errorf("parsing %s: %w", filename, err)
+	}
+
+	return runnableStages.Profile(start), nil
+}
+
+// Rows returns the figures of both profiles, with their changes.
+func (d Diff) Rows() []Row {
+	rows := []Row{
+		durationRow("duration", d.Before.Duration, d.After.Duration),
+		countRow("peak rate", "/s", int64(d.Before.PeakRate()), int64(d.After.PeakRate())),
+		countRow("total iterations", "", int64(d.Before.TotalIterations()), int64(d.After.TotalIterations())),
+	}
+	if d.Before.PeakUsers > 0 || d.After.PeakUsers > 0 {
+		rows = append(rows, countRow("peak users", "", int64(d.Before.PeakUsers), int64(d.After.PeakUsers)))
+	}
+	if d.Before.Scenario != d.After.Scenario {
+		rows = append(rows, Row{Name: "scenario", Before: d.Before.Scenario, After: d.After.Scenario, Change: "changed"})
+	}
+
+	return rows
+}
+
+// Changed returns true if any figure of the profiles changed.
+func (d Diff) Changed() bool {
+	for _, row := range d.Rows() {
+		if row.Change != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func durationRow(name string, before, after time.Duration) Row {
+	row := Row{Name: name, Before: before.String(), After: after.String()}
+	if before != after {
+		sign := ""
+		if after > before {
+			sign = "+"
+		}
+		row.Change = sign + (after - before).String() + relativeChange(float64(before), float64(after))
+	}
+
+	return row
+}
+
+func countRow(name, unit string, before, after int64) Row {
+	row := Row{Name: name, Before: fmt.Sprintf("%d%s", before, unit), After: fmt.Sprintf("%d%s", after, unit)}
+	if before != after {
+		row.Change = fmt.Sprintf("%+d%s%s", after-before, unit, relativeChange(float64(before), float64(after)))
+	}
+
+	return row
+}
+
+// relativeChange returns the change from before to after as a percentage of before, if not 0.
+func relativeChange(before, after float64) string {
+	if before == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf(" (%+.1f%%)", 100*(after-before)/before)
+}
@@ -0,0 +1,89 @@
+package configdiff
+
+import (
+	"fmt"
+	"io/fs"
+	"text/tabwriter"
+	"time"
+
+	"github.com/guptarohit/asciigraph"
+	"github.com/spf13/cobra"
+
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+)
+
+const chartWidth = 160
+
+// Cmd returns the config command, whose diff subcommand compares the load planned by two config
+// files of the file trigger. Profiles are the config files embedded in the scenario binary.
+func Cmd(profiles fs.FS, output *ui.Output) *cobra.Command {
+	configCmd := &cobra.Command{
+		Use:   "config <subcommand>",
+		Short: "Works with the config files of the file trigger",
+	}
+
+	configCmd.AddCommand(&cobra.Command{
+		Use:   "diff <before> <after>",
+		Short: "Compares the load planned by two config files of the file trigger",
+		Long: `Plots the rate of iterations planned by two config files of the file trigger, paths or
+embedded profiles with "embedded://<name>", one over the other, and prints the differences in
+their duration, peak rate and total iterations, to review changes to load profiles. Jitter makes
+the planned rates vary slightly between runs. For example:
+
+  git show main:profiles/soak.yaml > /tmp/soak.yaml
+  f1 config diff /tmp/soak.yaml profiles/soak.yaml`,
+		Args: cobra.ExactArgs(2),
+		RunE: diffCmdExecute(profiles, output),
+	})
+
+	return configCmd
+}
+
+func diffCmdExecute(profiles fs.FS, output *ui.Output) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		diff, err := Load(args[0], args[1], profiles, output, time.Now().Truncate(time.Second))
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintln(cmd.OutOrStdout(), plot(diff, args[0], args[1]))
+		fmt.Fprintln(cmd.OutOrStdout())
+
+		writer := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+		fmt.Fprintf(writer, "\t%s\t%s\tCHANGE\n", args[0], args[1])
+		for _, row := range diff.Rows() {
+			change := row.Change
+			if change == "" {
+				change = "-"
+			}
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", row.Name, row.Before, row.After, change)
+		}
+		if err := writer.Flush(); err != nil {
+			return fmt.Errorf("writing diff: %w", err)
+		}
+
+		return nil
+	}
+}
+
+// plot returns the rates of both profiles, per second of the run, on one chart.
+func plot(diff Diff, before, after string) string {
+	length := max(len(diff.Before.Rates), len(diff.After.Rates), 1)
+	series := [][]float64{make([]float64, length), make([]float64, length)}
+	for i, rate := range diff.Before.Rates {
+		series[0][i] = float64(rate)
+	}
+	for i, rate := range diff.After.Rates {
+		series[1][i] = float64(rate)
+	}
+
+	return asciigraph.PlotMany(series,
+		asciigraph.Height(15),
+		asciigraph.Width(chartWidth),
+		asciigraph.Caption("iterations started per second"),
+		asciigraph.SeriesColors(asciigraph.Blue, asciigraph.Red),
+		asciigraph.SeriesLegends(before, after),
+	)
+}
@@ -0,0 +1,125 @@
+package configdiff_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/configdiff"
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+)
+
+const configHeader = `
+scenario: template
+default:
+  mode: constant
+  jitter: 0
+  distribution: none
+limits:
+  max-duration: 1h
+  concurrency: 50
+  max-iterations: 1000000
+  ignore-dropped: true
+stages:
+`
+
+func writeConfig(t *testing.T, dir, name, stages string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(configHeader+stages), 0o600))
+
+	return path
+}
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	before := writeConfig(t, dir, "before.yaml", `
+- duration: 10s
+  rate: 10/s
+`)
+	after := writeConfig(t, dir, "after.yaml", `
+- duration: 10s
+  rate: 10/s
+- duration: 5s
+  rate: 20/s
+- duration: 5s
+  mode: users
+  concurrency: 3
+`)
+
+	diff, err := configdiff.Load(before, after, nil, ui.NewDiscardOutput(), time.Now())
+	require.NoError(t, err)
+
+	assert.True(t, diff.Changed())
+	assert.Equal(t, []configdiff.Row{
+		{Name: "duration", Before: "10s", After: "20s", Change: "+10s (+100.0%)"},
+		{Name: "peak rate", Before: "10/s", After: "20/s", Change: "+10/s (+100.0%)"},
+		{Name: "total iterations", Before: "100", After: "200", Change: "+100 (+100.0%)"},
+		{Name: "peak users", Before: "0", After: "3", Change: "+3"},
+	}, diff.Rows())
+}
+
+func TestDiffUnchanged(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	stages := `
+- duration: 1m
+  mode: ramp
+  start-rate: 10/s
+  end-rate: 100/s
+`
+	before := writeConfig(t, dir, "before.yaml", stages)
+	after := writeConfig(t, dir, "after.yaml", stages)
+
+	diff, err := configdiff.Load(before, after, nil, ui.NewDiscardOutput(), time.Now())
+	require.NoError(t, err)
+
+	assert.False(t, diff.Changed())
+}
+
+func TestDiffCmd(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	before := writeConfig(t, dir, "before.yaml", `
+- duration: 10s
+  rate: 10/s
+`)
+	after := writeConfig(t, dir, "after.yaml", `
+- duration: 10s
+  rate: 5/s
+`)
+
+	var out bytes.Buffer
+	cmd := configdiff.Cmd(nil, ui.NewDiscardOutput())
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"diff", before, after})
+	require.NoError(t, cmd.Execute())
+
+	assert.Contains(t, out.String(), "iterations started per second")
+	assert.Regexp(t, `peak rate +10/s +5/s +-5/s \(-50.0%\)`, out.String())
+	assert.Regexp(t, `duration +10s +10s +-\n`, out.String())
+}
+
+func TestDiffInvalidConfig(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	before := writeConfig(t, dir, "before.yaml", `
+- duration: 10s
+  rate: 10/s
+`)
+
+	_, err := configdiff.Load(before, filepath.Join(dir, "missing.yaml"), nil, ui.NewDiscardOutput(), time.Now())
+
+	require.ErrorContains(t, err, "reading "+filepath.Join(dir, "missing.yaml"))
+}
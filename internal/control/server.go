@@ -0,0 +1,154 @@
+package control
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const readHeaderTimeout = 5 * time.Second
+
+// Server is the HTTP control endpoint of a run, used to inspect and control the run while it
+// is in progress.
+type Server struct {
+	mux      *http.ServeMux
+	server   *http.Server
+	listener net.Listener
+	// shutdown is closed when the server shuts down, to end the streams of events
+	shutdown     chan struct{}
+	shutdownOnce sync.Once
+}
+
+// New listens on addr, so that the address is known and can be reported before the run starts.
+func New(addr string) (*Server, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("listening on control address: %w", err)
+	}
+
+	mux := http.NewServeMux()
+
+	return &Server{
+		mux:      mux,
+		listener: listener,
+		server: &http.Server{
+			Handler:           mux,
+			ReadHeaderTimeout: readHeaderTimeout,
+		},
+		shutdown: make(chan struct{}),
+	}, nil
+}
+
+// Addr returns the address the server listens on.
+func (s *Server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Handle registers the handler for the given pattern, as with http.ServeMux.
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// HandleJSON registers a handler responding with the json encoding of the value returned by fn.
+func (s *Server) HandleJSON(pattern string, fn func() any) {
+	s.mux.HandleFunc(pattern, func(w http.ResponseWriter, _ *http.Request) {
+		WriteJSON(w, http.StatusOK, fn())
+	})
+}
+
+// HandleAction registers a handler for requests changing the run, responding with the json encoding
+// of the value returned by fn, or with a conflict and the error if the run can't be changed.
+func (s *Server) HandleAction(pattern string, fn func(r *http.Request) (any, error)) {
+	s.mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		value, err := fn(r)
+		if err != nil {
+			WriteJSON(w, http.StatusConflict, map[string]string{"error": err.Error()})
+			return
+		}
+		WriteJSON(w, http.StatusOK, value)
+	})
+}
+
+// HandleEvents registers a handler streaming the json encoding of the value returned by fn as
+// server-sent events named name, straight away and then every interval, so that browsers can follow
+// the value with an EventSource. Streams end when the client disconnects, or when the server shuts
+// down, after a last event named "end" with the latest value.
+func (s *Server) HandleEvents(pattern, name string, interval time.Duration, fn func() any) {
+	s.mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		controller := http.NewResponseController(w)
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		// dashboards are usually served from another origin
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.WriteHeader(http.StatusOK)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for id := 1; ; id++ {
+			select {
+			case <-s.shutdown:
+				_ = writeEvent(w, controller, id, "end", fn())
+				return
+			default:
+			}
+			if err := writeEvent(w, controller, id, name, fn()); err != nil {
+				return
+			}
+
+			select {
+			case <-r.Context().Done():
+				return
+			case <-s.shutdown:
+			case <-ticker.C:
+			}
+		}
+	})
+}
+
+// writeEvent writes the json encoding of value as a server-sent event, and flushes it to the client.
+func writeEvent(w http.ResponseWriter, controller *http.ResponseController, id int, name string, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("marshalling event: %w", err)
+	}
+	if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", id, name, data); err != nil {
+		return fmt.Errorf("writing event: %w", err)
+	}
+	if err := controller.Flush(); err != nil {
+		return fmt.Errorf("flushing event: %w", err)
+	}
+
+	return nil
+}
+
+// Start serves requests in the background until the server is shut down.
+func (s *Server) Start() {
+	go func() {
+		// the error is always ErrServerClosed or a listener error after the server is shut down
+		_ = s.server.Serve(s.listener)
+	}()
+}
+
+// Shutdown stops the server, ending the streams of events and waiting for active requests to
+// complete.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.shutdownOnce.Do(func() { close(s.shutdown) })
+	if err := s.server.Shutdown(ctx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("shutting down control server: %w", err)
+	}
+
+	return nil
+}
+
+// WriteJSON writes value as the json response body with the given status.
+func WriteJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(value)
+}
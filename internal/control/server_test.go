@@ -0,0 +1,151 @@
+package control_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/control"
+)
+
+func TestServerHandlesJSON(t *testing.T) {
+	t.Parallel()
+
+	server, err := control.New("127.0.0.1:0")
+	require.NoError(t, err)
+
+	server.HandleJSON("GET /progress", func() any {
+		return map[string]int{"successful_iterations": 3}
+	})
+	server.Start()
+	t.Cleanup(func() {
+		require.NoError(t, server.Shutdown(context.Background()))
+	})
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "http://"+server.Addr()+"/progress", nil)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+	var body map[string]int
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	require.Equal(t, map[string]int{"successful_iterations": 3}, body)
+}
+
+func TestServerRejectsOtherMethods(t *testing.T) {
+	t.Parallel()
+
+	server, err := control.New("127.0.0.1:0")
+	require.NoError(t, err)
+
+	server.HandleJSON("GET /progress", func() any { return nil })
+	server.Start()
+	t.Cleanup(func() {
+		require.NoError(t, server.Shutdown(context.Background()))
+	})
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, "http://"+server.Addr()+"/progress", nil)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
+
+func TestServerRespondsWithTheErrorOfActions(t *testing.T) {
+	t.Parallel()
+
+	server, err := control.New("127.0.0.1:0")
+	require.NoError(t, err)
+
+	server.HandleAction("POST /stages/jump", func(r *http.Request) (any, error) {
+		return nil, fmt.Errorf("unknown stage %q", r.URL.Query().Get("stage"))
+	})
+	server.Start()
+	t.Cleanup(func() {
+		require.NoError(t, server.Shutdown(context.Background()))
+	})
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost,
+		"http://"+server.Addr()+"/stages/jump?stage=soak", nil)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusConflict, resp.StatusCode)
+
+	var body map[string]string
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	require.Equal(t, map[string]string{"error": `unknown stage "soak"`}, body)
+}
+
+func TestServerStreamsEventsUntilItShutsDown(t *testing.T) {
+	t.Parallel()
+
+	server, err := control.New("127.0.0.1:0")
+	require.NoError(t, err)
+
+	var calls atomic.Int32
+	server.HandleEvents("GET /progress/events", "progress", 10*time.Millisecond, func() any {
+		return map[string]int32{"calls": calls.Add(1)}
+	})
+	server.Start()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet,
+		"http://"+server.Addr()+"/progress/events", nil)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	require.Equal(t, "*", resp.Header.Get("Access-Control-Allow-Origin"))
+
+	reader := bufio.NewReader(resp.Body)
+	readEvent := func() []string {
+		var lines []string
+		for {
+			line, err := reader.ReadString('\n')
+			require.NoError(t, err)
+			if line == "\n" {
+				return lines
+			}
+			lines = append(lines, strings.TrimSuffix(line, "\n"))
+		}
+	}
+	require.Equal(t, []string{"id: 1", "event: progress", `data: {"calls":1}`}, readEvent())
+	require.Equal(t, []string{"id: 2", "event: progress", `data: {"calls":2}`}, readEvent())
+
+	shutdownErr := make(chan error)
+	go func() {
+		shutdownErr <- server.Shutdown(context.Background())
+	}()
+
+	// events already written are read before the end event
+	for {
+		event := readEvent()
+		require.Len(t, event, 3)
+		if event[1] == "event: end" {
+			break
+		}
+	}
+	_, err = reader.ReadString('\n')
+	require.ErrorIs(t, err, io.EOF)
+	require.NoError(t, <-shutdownErr)
+}
@@ -0,0 +1,59 @@
+// Package cpupin pins goroutines to logical CPUs, so that workers partitioned across CPUs are not
+// moved between them by the scheduler. Pinning is only supported on Linux.
+package cpupin
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// All selects every CPU f1 is allowed to run on.
+const All = "all"
+
+// ErrUnsupported is returned when CPUs can't be pinned on the operating system.
+var ErrUnsupported = errors.New("pinning to cpus is only supported on linux")
+
+// Parse parses a list of CPUs, such as "0-3,6", or All, and checks that f1 is allowed to run on
+// them.
+func Parse(list string) ([]int, error) {
+	available, err := Available()
+	if err != nil {
+		return nil, err
+	}
+	if list == All {
+		return available, nil
+	}
+
+	var cpus []int
+	for _, part := range strings.Split(list, ",") {
+		first, last, isRange := strings.Cut(strings.TrimSpace(part), "-")
+		from, err := strconv.Atoi(first)
+		if err != nil {
+			return nil, fmt.Errorf("parsing cpu '%s': %w", part, err)
+		}
+		to := from
+		if isRange {
+			to, err = strconv.Atoi(last)
+			if err != nil {
+				return nil, fmt.Errorf("parsing cpu range '%s': %w", part, err)
+			}
+			if to < from {
+				return nil, fmt.Errorf("cpu range '%s' is descending", part)
+			}
+		}
+
+		for cpu := from; cpu <= to; cpu++ {
+			if !slices.Contains(available, cpu) {
+				return nil, fmt.Errorf("cpu %d is not available, f1 can run on cpus %v", cpu, available)
+			}
+			if !slices.Contains(cpus, cpu) {
+				cpus = append(cpus, cpu)
+			}
+		}
+	}
+
+	return cpus, nil
+}
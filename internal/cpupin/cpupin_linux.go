@@ -0,0 +1,39 @@
+package cpupin
+
+import (
+	"fmt"
+	"runtime"
+
+	"golang.org/x/sys/unix"
+)
+
+// Available returns the CPUs f1 is allowed to run on.
+func Available() ([]int, error) {
+	var set unix.CPUSet
+	if err := unix.SchedGetaffinity(0, &set); err != nil {
+		return nil, fmt.Errorf("getting cpu affinity: %w", err)
+	}
+
+	var cpus []int
+	for cpu := 0; len(cpus) < set.Count(); cpu++ {
+		if set.IsSet(cpu) {
+			cpus = append(cpus, cpu)
+		}
+	}
+
+	return cpus, nil
+}
+
+// Pin locks the calling goroutine to its OS thread and restricts the thread to cpu. The goroutine
+// stays locked, so that its thread, which can't be reused by other goroutines, exits with it.
+func Pin(cpu int) error {
+	runtime.LockOSThread()
+
+	var set unix.CPUSet
+	set.Set(cpu)
+	if err := unix.SchedSetaffinity(0, &set); err != nil {
+		return fmt.Errorf("pinning to cpu %d: %w", cpu, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+
+package cpupin
+
+// Available returns the CPUs f1 is allowed to run on.
+func Available() ([]int, error) {
+	return nil, ErrUnsupported
+}
+
+// Pin locks the calling goroutine to its OS thread and restricts the thread to cpu.
+func Pin(int) error {
+	return ErrUnsupported
+}
@@ -0,0 +1,79 @@
+package cpupin_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/cpupin"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	available, err := cpupin.Available()
+	if errors.Is(err, cpupin.ErrUnsupported) {
+		t.Skip(err)
+	}
+	require.NoError(t, err)
+	first, last := available[0], available[len(available)-1]
+
+	for _, test := range []struct {
+		name          string
+		list          string
+		expected      []int
+		expectedError string
+	}{
+		{
+			name:     "all",
+			list:     cpupin.All,
+			expected: available,
+		},
+		{
+			name:     "single cpu",
+			list:     fmt.Sprint(first),
+			expected: []int{first},
+		},
+		{
+			name:     "range",
+			list:     fmt.Sprintf("%d-%d", first, first),
+			expected: []int{first},
+		},
+		{
+			name:     "duplicates",
+			list:     fmt.Sprintf("%d,%d-%d", last, last, last),
+			expected: []int{last},
+		},
+		{
+			name:          "unavailable cpu",
+			list:          fmt.Sprint(last + 1),
+			expectedError: fmt.Sprintf("cpu %d is not available", last+1),
+		},
+		{
+			name:          "descending range",
+			list:          "3-1",
+			expectedError: "cpu range '3-1' is descending",
+		},
+		{
+			name:          "invalid cpu",
+			list:          "one",
+			expectedError: "parsing cpu 'one'",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			cpus, err := cpupin.Parse(test.list)
+
+			if test.expectedError != "" {
+				require.ErrorContains(t, err, test.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, cpus)
+		})
+	}
+}
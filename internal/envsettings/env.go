@@ -7,9 +7,13 @@ import (
 )
 
 const (
-	EnvPrometheusLabelID     = "PROMETHEUS_LABEL_ID"
-	EnvPrometheusNamespace   = "PROMETHEUS_NAMESPACE"
-	EnvPrometheusPushGateway = "PROMETHEUS_PUSH_GATEWAY"
+	EnvPrometheusLabelID       = "PROMETHEUS_LABEL_ID"
+	EnvPrometheusNamespace     = "PROMETHEUS_NAMESPACE"
+	EnvPrometheusPushGateway   = "PROMETHEUS_PUSH_GATEWAY"
+	EnvPrometheusPushInterval  = "PROMETHEUS_PUSH_INTERVAL"
+	EnvPrometheusPushHeartbeat = "PROMETHEUS_PUSH_HEARTBEAT"
+	EnvPrometheusMetricPrefix  = "PROMETHEUS_METRIC_PREFIX"
+	EnvPrometheusConstLabels   = "PROMETHEUS_CONST_LABELS"
 
 	EnvLogFilePath = "LOG_FILE_PATH"
 	EnvLogFormat   = "LOG_FORMAT"
@@ -17,12 +21,30 @@ const (
 
 	EnvFluentdHost = "FLUENTD_HOST"
 	EnvFluentdPort = "FLUENTD_PORT"
+
+	EnvHistoryDir = "HISTORY_DIR"
+
+	EnvTargetMetricsPrometheusURL = "TARGET_METRICS_PROMETHEUS_URL"
+	EnvTargetMetricsQueries       = "TARGET_METRICS_QUERIES"
+
+	EnvArtifactsURL = "ARTIFACTS_URL"
+
+	EnvConfirmAboveRate = "CONFIRM_ABOVE_RATE"
+
+	EnvOTLPMetricsEndpoint = "OTLP_METRICS_ENDPOINT"
 )
 
 type Prometheus struct {
 	LabelID     string
 	Namespace   string
 	PushGateway string
+	// PushInterval and PushHeartbeat are durations, such as 10s, or empty for the defaults
+	PushInterval  string
+	PushHeartbeat string
+	// MetricPrefix replaces the form3_loadtest prefix of the names of the metrics, if set
+	MetricPrefix string
+	// ConstLabels are name=value pairs, separated by commas, added to all the metrics
+	ConstLabels string
 }
 
 type Fluentd struct {
@@ -58,31 +80,110 @@ func (l Log) IsFormatJSON() bool {
 	return strings.EqualFold(l.Format, "json")
 }
 
+type History struct {
+	Dir string
+}
+
+func (h History) Enabled() bool {
+	return h.Dir != ""
+}
+
+type TargetMetrics struct {
+	PrometheusURL string
+	QueriesFile   string
+}
+
+func (t TargetMetrics) Enabled() bool {
+	return t.PrometheusURL != "" && t.QueriesFile != ""
+}
+
+type Artifacts struct {
+	URL string
+}
+
+func (a Artifacts) Enabled() bool {
+	return a.URL != ""
+}
+
+// Confirm are the settings of the confirmation of runs before they start.
+type Confirm struct {
+	// AboveRate is the default of --confirm-above, such as 500/s
+	AboveRate string
+}
+
+// OTLP are the settings of the export of the metrics to an OpenTelemetry collector.
+type OTLP struct {
+	// MetricsEndpoint is the url of the OTLP/HTTP metrics endpoint of the collector, such as
+	// http://collector:4318/v1/metrics
+	MetricsEndpoint string
+}
+
+func (o OTLP) Enabled() bool {
+	return o.MetricsEndpoint != ""
+}
+
 type Settings struct {
-	Prometheus Prometheus
-	Fluentd    Fluentd
-	Log        Log
+	Prometheus    Prometheus
+	Fluentd       Fluentd
+	Log           Log
+	History       History
+	TargetMetrics TargetMetrics
+	Artifacts     Artifacts
+	Confirm       Confirm
+	OTLP          OTLP
+	// File is the settings file the settings were loaded from, if any
+	File string
 }
 
 func (s *Settings) PrometheusEnabled() bool {
 	return s.Prometheus.PushGateway != ""
 }
 
+// MetricsEnabled reports whether the metrics of the run are pushed to a push gateway or exported to
+// an OpenTelemetry collector.
+func (s *Settings) MetricsEnabled() bool {
+	return s.PrometheusEnabled() || s.OTLP.Enabled()
+}
+
+// Get returns the settings of the environment variables.
 func Get() Settings {
-	return Settings{
-		Log: Log{
-			FilePath: os.Getenv(EnvLogFilePath),
-			Level:    os.Getenv(EnvLogLevel),
-			Format:   os.Getenv(EnvLogFormat),
-		},
-		Fluentd: Fluentd{
-			Host: os.Getenv(EnvFluentdHost),
-			Port: os.Getenv(EnvFluentdPort),
-		},
-		Prometheus: Prometheus{
-			LabelID:     os.Getenv(EnvPrometheusLabelID),
-			Namespace:   os.Getenv(EnvPrometheusNamespace),
-			PushGateway: os.Getenv(EnvPrometheusPushGateway),
-		},
+	return withEnv(Settings{})
+}
+
+// withEnv overrides settings with the environment variables which are set, even to an empty value,
+// so that a setting of a settings file can be unset from the environment.
+func withEnv(settings Settings) Settings {
+	lookupEnv(EnvLogFilePath, &settings.Log.FilePath)
+	lookupEnv(EnvLogLevel, &settings.Log.Level)
+	lookupEnv(EnvLogFormat, &settings.Log.Format)
+
+	lookupEnv(EnvFluentdHost, &settings.Fluentd.Host)
+	lookupEnv(EnvFluentdPort, &settings.Fluentd.Port)
+
+	lookupEnv(EnvPrometheusLabelID, &settings.Prometheus.LabelID)
+	lookupEnv(EnvPrometheusNamespace, &settings.Prometheus.Namespace)
+	lookupEnv(EnvPrometheusPushGateway, &settings.Prometheus.PushGateway)
+	lookupEnv(EnvPrometheusPushInterval, &settings.Prometheus.PushInterval)
+	lookupEnv(EnvPrometheusPushHeartbeat, &settings.Prometheus.PushHeartbeat)
+	lookupEnv(EnvPrometheusMetricPrefix, &settings.Prometheus.MetricPrefix)
+	lookupEnv(EnvPrometheusConstLabels, &settings.Prometheus.ConstLabels)
+
+	lookupEnv(EnvHistoryDir, &settings.History.Dir)
+
+	lookupEnv(EnvTargetMetricsPrometheusURL, &settings.TargetMetrics.PrometheusURL)
+	lookupEnv(EnvTargetMetricsQueries, &settings.TargetMetrics.QueriesFile)
+
+	lookupEnv(EnvArtifactsURL, &settings.Artifacts.URL)
+
+	lookupEnv(EnvConfirmAboveRate, &settings.Confirm.AboveRate)
+
+	lookupEnv(EnvOTLPMetricsEndpoint, &settings.OTLP.MetricsEndpoint)
+
+	return settings
+}
+
+func lookupEnv(name string, setting *string) {
+	if value, ok := os.LookupEnv(name); ok {
+		*setting = value
 	}
 }
@@ -0,0 +1,196 @@
+package envsettings
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+var errInvalidSettingsFile = errors.New("invalid settings file")
+
+// settingsFile is the content of a settings file, where the settings are grouped by section as in
+// Settings, with constant labels as a table rather than a list of pairs.
+type settingsFile struct {
+	Prometheus struct {
+		LabelID       string            `toml:"label-id"       yaml:"label-id"`
+		Namespace     string            `toml:"namespace"      yaml:"namespace"`
+		PushGateway   string            `toml:"push-gateway"   yaml:"push-gateway"`
+		PushInterval  string            `toml:"push-interval"  yaml:"push-interval"`
+		PushHeartbeat string            `toml:"push-heartbeat" yaml:"push-heartbeat"`
+		MetricPrefix  string            `toml:"metric-prefix"  yaml:"metric-prefix"`
+		ConstLabels   map[string]string `toml:"const-labels"   yaml:"const-labels"`
+	} `toml:"prometheus" yaml:"prometheus"`
+	Fluentd struct {
+		Host string `toml:"host" yaml:"host"`
+		Port string `toml:"port" yaml:"port"`
+	} `toml:"fluentd" yaml:"fluentd"`
+	Log struct {
+		FilePath string `toml:"file-path" yaml:"file-path"`
+		Level    string `toml:"level"     yaml:"level"`
+		Format   string `toml:"format"    yaml:"format"`
+	} `toml:"log" yaml:"log"`
+	History struct {
+		Dir string `toml:"dir" yaml:"dir"`
+	} `toml:"history" yaml:"history"`
+	TargetMetrics struct {
+		PrometheusURL string `toml:"prometheus-url" yaml:"prometheus-url"`
+		Queries       string `toml:"queries"        yaml:"queries"`
+	} `toml:"target-metrics" yaml:"target-metrics"`
+	Artifacts struct {
+		URL string `toml:"url" yaml:"url"`
+	} `toml:"artifacts" yaml:"artifacts"`
+	Confirm struct {
+		AboveRate string `toml:"above-rate" yaml:"above-rate"`
+	} `toml:"confirm" yaml:"confirm"`
+	OTLP struct {
+		MetricsEndpoint string `toml:"metrics-endpoint" yaml:"metrics-endpoint"`
+	} `toml:"otlp" yaml:"otlp"`
+}
+
+// Load returns the settings of the settings file at path, or of the settings file found in the
+// working directory if path is empty, overridden by the environment variables which are set. The
+// settings are those of the environment variables only without a settings file.
+func Load(path string) (Settings, error) {
+	if path == "" {
+		found, err := findFile(".")
+		if err != nil {
+			return Settings{}, err
+		}
+		if found == "" {
+			return Get(), nil
+		}
+		path = found
+	}
+
+	settings, err := readFile(path)
+	if err != nil {
+		return Settings{}, err
+	}
+
+	return withEnv(settings), nil
+}
+
+func findFile(dir string) (string, error) {
+	var found []string
+	for _, name := range []string{"f1.toml", "f1.yaml", "f1.yml"} {
+		_, err := os.Stat(filepath.Join(dir, name))
+		switch {
+		case err == nil:
+			found = append(found, name)
+		case !errors.Is(err, fs.ErrNotExist):
+			return "", fmt.Errorf("looking up settings file '%s': %w", name, err)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return "", nil
+	case 1:
+		return filepath.Join(dir, found[0]), nil
+	default:
+		return "", fmt.Errorf("%w: found %s in the working directory, expected one", errInvalidSettingsFile,
+			strings.Join(found, " and "))
+	}
+}
+
+func readFile(path string) (Settings, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Settings{}, fmt.Errorf("reading settings file '%s': %w", path, err)
+	}
+
+	var file settingsFile
+	switch ext := filepath.Ext(path); ext {
+	case ".toml":
+		err = decodeTOML(data, &file)
+	case ".yaml", ".yml":
+		err = decodeYAML(data, &file)
+	default:
+		err = fmt.Errorf("%w: unknown format '%s', expected .toml, .yaml or .yml", errInvalidSettingsFile, ext)
+	}
+	if err != nil {
+		return Settings{}, fmt.Errorf("parsing settings file '%s': %w", path, err)
+	}
+
+	settings := file.settings()
+	settings.File = path
+
+	return settings, nil
+}
+
+func decodeTOML(data []byte, file *settingsFile) error {
+	metadata, err := toml.NewDecoder(bytes.NewReader(data)).Decode(file)
+	if err != nil {
+		return fmt.Errorf("decoding toml: %w", err)
+	}
+
+	if undecoded := metadata.Undecoded(); len(undecoded) > 0 {
+		return fmt.Errorf("%w: unknown setting '%s'", errInvalidSettingsFile, undecoded[0])
+	}
+
+	return nil
+}
+
+func decodeYAML(data []byte, file *settingsFile) error {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(file); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("decoding yaml: %w", err)
+	}
+
+	return nil
+}
+
+func (f *settingsFile) settings() Settings {
+	constLabels := make([]string, 0, len(f.Prometheus.ConstLabels))
+	for name, value := range f.Prometheus.ConstLabels {
+		constLabels = append(constLabels, name+"="+value)
+	}
+	slices.Sort(constLabels)
+
+	return Settings{
+		Prometheus: Prometheus{
+			LabelID:       f.Prometheus.LabelID,
+			Namespace:     f.Prometheus.Namespace,
+			PushGateway:   f.Prometheus.PushGateway,
+			PushInterval:  f.Prometheus.PushInterval,
+			PushHeartbeat: f.Prometheus.PushHeartbeat,
+			MetricPrefix:  f.Prometheus.MetricPrefix,
+			ConstLabels:   strings.Join(constLabels, ","),
+		},
+		Fluentd: Fluentd{
+			Host: f.Fluentd.Host,
+			Port: f.Fluentd.Port,
+		},
+		Log: Log{
+			FilePath: f.Log.FilePath,
+			Level:    f.Log.Level,
+			Format:   f.Log.Format,
+		},
+		History: History{
+			Dir: f.History.Dir,
+		},
+		TargetMetrics: TargetMetrics{
+			PrometheusURL: f.TargetMetrics.PrometheusURL,
+			QueriesFile:   f.TargetMetrics.Queries,
+		},
+		Artifacts: Artifacts{
+			URL: f.Artifacts.URL,
+		},
+		Confirm: Confirm{
+			AboveRate: f.Confirm.AboveRate,
+		},
+		OTLP: OTLP{
+			MetricsEndpoint: f.OTLP.MetricsEndpoint,
+		},
+	}
+}
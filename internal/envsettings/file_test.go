@@ -0,0 +1,161 @@
+package envsettings_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/envsettings"
+)
+
+const tomlSettings = `
+[prometheus]
+push-gateway = "http://pushgateway:9091"
+namespace = "payments"
+const-labels = { team = "payments", region = "eu-west-1" }
+
+[log]
+level = "debug"
+format = "json"
+
+[history]
+dir = "/var/lib/f1/history"
+
+[target-metrics]
+prometheus-url = "http://prometheus:9090"
+queries = "queries.yaml"
+
+[otlp]
+metrics-endpoint = "http://collector:4318"
+`
+
+const yamlSettings = `
+prometheus:
+  push-gateway: http://pushgateway:9091
+  namespace: payments
+  const-labels:
+    team: payments
+    region: eu-west-1
+log:
+  level: debug
+  format: json
+history:
+  dir: /var/lib/f1/history
+target-metrics:
+  prometheus-url: http://prometheus:9090
+  queries: queries.yaml
+otlp:
+  metrics-endpoint: http://collector:4318
+`
+
+func writeSettingsFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{name: "toml", file: "f1.toml", content: tomlSettings},
+		{name: "yaml", file: "f1.yaml", content: yamlSettings},
+		{name: "yml", file: "settings.yml", content: yamlSettings},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := writeSettingsFile(t, test.file, test.content)
+
+			settings, err := envsettings.Load(path)
+			require.NoError(t, err)
+
+			assert.Equal(t, path, settings.File)
+			assert.Equal(t, "http://pushgateway:9091", settings.Prometheus.PushGateway)
+			assert.Equal(t, "payments", settings.Prometheus.Namespace)
+			assert.Equal(t, "region=eu-west-1,team=payments", settings.Prometheus.ConstLabels)
+			assert.Equal(t, "debug", settings.Log.Level)
+			assert.True(t, settings.Log.IsFormatJSON())
+			assert.Equal(t, "/var/lib/f1/history", settings.History.Dir)
+			assert.True(t, settings.TargetMetrics.Enabled())
+			assert.Equal(t, "queries.yaml", settings.TargetMetrics.QueriesFile)
+			assert.Equal(t, "http://collector:4318", settings.OTLP.MetricsEndpoint)
+		})
+	}
+}
+
+func TestLoadInvalidFiles(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name    string
+		file    string
+		content string
+		err     string
+	}{
+		{
+			name:    "unknown toml setting",
+			file:    "f1.toml",
+			content: "[prometheus]\npush-gatway = \"http://pushgateway:9091\"\n",
+			err:     "invalid settings file: unknown setting 'prometheus.push-gatway'",
+		},
+		{
+			name:    "unknown yaml setting",
+			file:    "f1.yaml",
+			content: "prometheus:\n  push-gatway: http://pushgateway:9091\n",
+			err:     "field push-gatway not found",
+		},
+		{
+			name:    "invalid toml",
+			file:    "f1.toml",
+			content: "[prometheus\n",
+			err:     "decoding toml",
+		},
+		{
+			name:    "unknown format",
+			file:    "f1.json",
+			content: "{}",
+			err:     "unknown format '.json', expected .toml, .yaml or .yml",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := writeSettingsFile(t, test.file, test.content)
+
+			_, err := envsettings.Load(path)
+			require.ErrorContains(t, err, "parsing settings file '"+path+"'")
+			require.ErrorContains(t, err, test.err)
+		})
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	t.Parallel()
+
+	_, err := envsettings.Load(filepath.Join(t.TempDir(), "f1.toml"))
+
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestEnvironmentVariablesOverrideTheSettingsFile(t *testing.T) {
+	t.Setenv(envsettings.EnvLogLevel, "warn")
+	t.Setenv(envsettings.EnvPrometheusPushGateway, "")
+
+	settings, err := envsettings.Load(writeSettingsFile(t, "f1.toml", tomlSettings))
+	require.NoError(t, err)
+
+	assert.Equal(t, "warn", settings.Log.Level)
+	assert.False(t, settings.PrometheusEnabled())
+	assert.Equal(t, "payments", settings.Prometheus.Namespace)
+}
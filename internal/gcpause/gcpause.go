@@ -0,0 +1,143 @@
+// Package gcpause counts the iterations which overlapped garbage collections of the Go runtime of
+// f1 itself, to tell latency spikes of the target apart from pauses of the load generator.
+package gcpause
+
+import (
+	"runtime/debug"
+	"runtime/metrics"
+	"sync/atomic"
+	"time"
+)
+
+const gcCyclesMetric = "/gc/cycles/total:gc-cycles"
+
+// Tracker records whether iterations overlapped a garbage collection. It is safe for concurrent use.
+type Tracker struct {
+	start              time.Time
+	startStats         debug.GCStats
+	iterations         atomic.Uint64
+	overlapped         atomic.Uint64
+	duration           atomic.Int64
+	overlappedDuration atomic.Int64
+}
+
+// Report summarises the garbage collections of a run and the iterations they overlapped.
+type Report struct {
+	Collections uint32        `json:"collections"`
+	PauseTotal  time.Duration `json:"pause_total"`
+	MaxPause    time.Duration `json:"max_pause"`
+	Iterations  uint64        `json:"iterations"`
+	// OverlappedIterations are the iterations running while a garbage collection completed
+	OverlappedIterations uint64 `json:"overlapped_iterations"`
+	// OverlappedAverage and OtherAverage are the average durations of the iterations which did
+	// and didn't overlap a garbage collection
+	OverlappedAverage time.Duration `json:"overlapped_average"`
+	OtherAverage      time.Duration `json:"other_average"`
+}
+
+// New returns a tracker of the garbage collections from now on.
+func New() *Tracker {
+	t := &Tracker{start: time.Now()}
+	debug.ReadGCStats(&t.startStats)
+
+	return t
+}
+
+// Cycles reads the number of completed garbage collection cycles with a sample which is reused
+// across calls so that reading it doesn't allocate.
+type Cycles struct {
+	sample []metrics.Sample
+}
+
+// NewCycles returns a reader of the number of completed garbage collection cycles, to be used by a
+// single goroutine.
+func NewCycles() *Cycles {
+	return &Cycles{sample: []metrics.Sample{{Name: gcCyclesMetric}}}
+}
+
+// Read returns the number of garbage collection cycles completed so far.
+func (c *Cycles) Read() uint64 {
+	metrics.Read(c.sample)
+	if c.sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+
+	return c.sample[0].Value.Uint64()
+}
+
+// Record records the duration of an iteration, which overlapped a garbage collection if the
+// number of completed cycles changed while it ran.
+func (t *Tracker) Record(duration time.Duration, cyclesAtStart, cyclesAtEnd uint64) {
+	t.iterations.Add(1)
+	t.duration.Add(int64(duration))
+	if cyclesAtEnd != cyclesAtStart {
+		t.overlapped.Add(1)
+		t.overlappedDuration.Add(int64(duration))
+	}
+}
+
+// Combine returns the report of the garbage collections of both reports, of separate processes.
+// other may be nil.
+func (r Report) Combine(other *Report) Report {
+	if other == nil {
+		return r
+	}
+
+	combined := Report{
+		Collections:          r.Collections + other.Collections,
+		PauseTotal:           r.PauseTotal + other.PauseTotal,
+		MaxPause:             max(r.MaxPause, other.MaxPause),
+		Iterations:           r.Iterations + other.Iterations,
+		OverlappedIterations: r.OverlappedIterations + other.OverlappedIterations,
+	}
+	combined.OverlappedAverage = weightedAverage(
+		r.OverlappedAverage, r.OverlappedIterations,
+		other.OverlappedAverage, other.OverlappedIterations,
+	)
+	combined.OtherAverage = weightedAverage(
+		r.OtherAverage, r.Iterations-r.OverlappedIterations,
+		other.OtherAverage, other.Iterations-other.OverlappedIterations,
+	)
+
+	return combined
+}
+
+func weightedAverage(a time.Duration, aCount uint64, b time.Duration, bCount uint64) time.Duration {
+	if aCount+bCount == 0 {
+		return 0
+	}
+
+	return time.Duration((float64(a)*float64(aCount) + float64(b)*float64(bCount)) / float64(aCount+bCount))
+}
+
+// Report returns the garbage collections since the tracker was created and the iterations they
+// overlapped.
+func (t *Tracker) Report() Report {
+	var stats debug.GCStats
+	debug.ReadGCStats(&stats)
+
+	report := Report{
+		Collections:          uint32(stats.NumGC - t.startStats.NumGC),
+		PauseTotal:           stats.PauseTotal - t.startStats.PauseTotal,
+		Iterations:           t.iterations.Load(),
+		OverlappedIterations: t.overlapped.Load(),
+	}
+
+	// the pauses of the most recent collections are kept, latest first
+	for i, pause := range stats.Pause {
+		if i < len(stats.PauseEnd) && stats.PauseEnd[i].Before(t.start) {
+			break
+		}
+		report.MaxPause = max(report.MaxPause, pause)
+	}
+
+	overlappedDuration := t.overlappedDuration.Load()
+	if report.OverlappedIterations > 0 {
+		report.OverlappedAverage = time.Duration(overlappedDuration / int64(report.OverlappedIterations))
+	}
+	if others := report.Iterations - report.OverlappedIterations; others > 0 {
+		report.OtherAverage = time.Duration((t.duration.Load() - overlappedDuration) / int64(others))
+	}
+
+	return report
+}
@@ -0,0 +1,82 @@
+package gcpause_test
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/form3tech-oss/f1/v2/internal/gcpause"
+)
+
+func TestReport(t *testing.T) {
+	t.Parallel()
+
+	tracker := gcpause.New()
+	cycles := gcpause.NewCycles()
+
+	start := cycles.Read()
+	runtime.GC()
+	end := cycles.Read()
+
+	tracker.Record(30*time.Millisecond, start, end)
+	tracker.Record(10*time.Millisecond, end, end)
+	tracker.Record(20*time.Millisecond, end, end)
+
+	report := tracker.Report()
+
+	assert.Greater(t, end, start)
+	assert.GreaterOrEqual(t, report.Collections, uint32(1))
+	assert.Positive(t, report.PauseTotal)
+	assert.Positive(t, report.MaxPause)
+	assert.LessOrEqual(t, report.MaxPause, report.PauseTotal)
+	assert.Equal(t, uint64(3), report.Iterations)
+	assert.Equal(t, uint64(1), report.OverlappedIterations)
+	assert.Equal(t, 30*time.Millisecond, report.OverlappedAverage)
+	assert.Equal(t, 15*time.Millisecond, report.OtherAverage)
+}
+
+func TestReportWithoutIterations(t *testing.T) {
+	t.Parallel()
+
+	report := gcpause.New().Report()
+
+	assert.Zero(t, report.Iterations)
+	assert.Zero(t, report.OverlappedAverage)
+	assert.Zero(t, report.OtherAverage)
+}
+
+func TestCombine(t *testing.T) {
+	t.Parallel()
+
+	a := gcpause.Report{
+		Collections:          2,
+		PauseTotal:           time.Millisecond,
+		MaxPause:             600 * time.Microsecond,
+		Iterations:           10,
+		OverlappedIterations: 2,
+		OverlappedAverage:    20 * time.Millisecond,
+		OtherAverage:         10 * time.Millisecond,
+	}
+	b := gcpause.Report{
+		Collections:          3,
+		PauseTotal:           2 * time.Millisecond,
+		MaxPause:             900 * time.Microsecond,
+		Iterations:           10,
+		OverlappedIterations: 6,
+		OverlappedAverage:    40 * time.Millisecond,
+		OtherAverage:         25 * time.Millisecond,
+	}
+
+	assert.Equal(t, a, a.Combine(nil))
+	assert.Equal(t, gcpause.Report{
+		Collections:          5,
+		PauseTotal:           3 * time.Millisecond,
+		MaxPause:             900 * time.Microsecond,
+		Iterations:           20,
+		OverlappedIterations: 8,
+		OverlappedAverage:    35 * time.Millisecond,
+		OtherAverage:         15 * time.Millisecond,
+	}, a.Combine(&b))
+}
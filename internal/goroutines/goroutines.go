@@ -0,0 +1,69 @@
+// Package goroutines names the goroutines of f1 with pprof labels, so that the CPU and goroutine
+// profiles captured while a run is in progress tell the iterations of the scenario, run by the
+// workers, from the goroutines of f1 itself. Profiles can be filtered by label with
+// `go tool pprof -tagfocus`.
+package goroutines
+
+import (
+	"context"
+	"runtime/debug"
+	"runtime/pprof"
+)
+
+// Labels of the goroutines of a run. Goroutines inherit the labels of the goroutine starting them,
+// and of the context they are labelled with.
+const (
+	// LabelName names what the goroutine does, Worker for the goroutines running iterations
+	LabelName = "f1_goroutine"
+	// LabelScenario is the scenario of the run
+	LabelScenario = "scenario"
+	// LabelStage is the stage of the run the worker is running iterations of, for triggers with stages
+	LabelStage = "stage"
+	// LabelWorker is the worker of its pool, as named in the audit log
+	LabelWorker = "worker"
+	// LabelOperation is the operation the iterations of the worker are triggered for, if any
+	LabelOperation = "operation"
+)
+
+// Worker is the name of the goroutines running the iterations of the scenario.
+const Worker = "worker"
+
+// PanicHandler is passed the panics of the goroutines started with Go, with their stack.
+type PanicHandler func(recovered any, stack []byte)
+
+type panicHandlerKey struct{}
+
+// WithPanicHandler returns ctx with a handler recovering the panics of the goroutines started with
+// Go from it, or from the goroutines they start, rather than letting them crash f1.
+func WithPanicHandler(ctx context.Context, handler PanicHandler) context.Context {
+	return context.WithValue(ctx, panicHandlerKey{}, handler)
+}
+
+// Go runs fn in a new goroutine named name, labelled with the labels of ctx and the key value pairs
+// of labels. fn is passed ctx with the labels, so that the goroutines it starts with Go or Do are
+// labelled with them too. Panics of fn are passed to the panic handler of ctx, if any.
+func Go(ctx context.Context, name string, fn func(ctx context.Context), labels ...string) {
+	handler, _ := ctx.Value(panicHandlerKey{}).(PanicHandler)
+	go func() {
+		if handler != nil {
+			defer func() {
+				if recovered := recover(); recovered != nil {
+					handler(recovered, debug.Stack())
+				}
+			}()
+		}
+		Do(ctx, name, fn, labels...)
+	}()
+}
+
+// Do runs fn in the current goroutine named name, labelled as with Go, and restores the labels of
+// the goroutine once fn returns.
+func Do(ctx context.Context, name string, fn func(ctx context.Context), labels ...string) {
+	pprof.Do(ctx, pprof.Labels(append([]string{LabelName, name}, labels...)...), fn)
+}
+
+// Relabel replaces the labels of the current goroutine with those of ctx and the key value pairs of
+// labels, for labels which change while the goroutine runs, such as the stage of a worker.
+func Relabel(ctx context.Context, labels ...string) {
+	pprof.SetGoroutineLabels(pprof.WithLabels(ctx, pprof.Labels(labels...)))
+}
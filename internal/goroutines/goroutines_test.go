@@ -0,0 +1,75 @@
+package goroutines_test
+
+import (
+	"context"
+	"runtime/pprof"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/form3tech-oss/f1/v2/internal/goroutines"
+)
+
+func TestGoroutinesInheritTheLabelsOfTheirContext(t *testing.T) {
+	t.Parallel()
+
+	profile := make(chan string)
+	goroutines.Go(context.Background(), "run", func(ctx context.Context) {
+		goroutines.Go(ctx, goroutines.Worker, func(ctx context.Context) {
+			goroutines.Relabel(ctx, goroutines.LabelStage, "warm-up")
+			var goroutineProfile strings.Builder
+			_ = pprof.Lookup("goroutine").WriteTo(&goroutineProfile, 1)
+			profile <- goroutineProfile.String()
+		}, goroutines.LabelWorker, "trigger-0")
+	}, goroutines.LabelScenario, "checkout")
+
+	assert.Contains(t, <-profile,
+		`# labels: {"f1_goroutine":"worker", "scenario":"checkout", "stage":"warm-up", "worker":"trigger-0"}`)
+}
+
+func TestDoRestoresTheLabelsOfTheGoroutine(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	var inside map[string]string
+	goroutines.Do(ctx, "trigger", func(ctx context.Context) {
+		inside = contextLabels(ctx)
+	})
+
+	assert.Equal(t, map[string]string{goroutines.LabelName: "trigger"}, inside)
+	assert.Empty(t, contextLabels(ctx))
+}
+
+func TestPanicsOfGoroutinesArePassedToThePanicHandler(t *testing.T) {
+	t.Parallel()
+
+	type recoveredPanic struct {
+		recovered any
+		stack     string
+	}
+	panics := make(chan recoveredPanic, 1)
+	ctx := goroutines.WithPanicHandler(context.Background(), func(recovered any, stack []byte) {
+		panics <- recoveredPanic{recovered: recovered, stack: string(stack)}
+	})
+
+	goroutines.Go(ctx, "run", func(ctx context.Context) {
+		goroutines.Go(ctx, "memory-guard", func(context.Context) {
+			panic("guard failed")
+		})
+	})
+
+	recovered := <-panics
+	assert.Equal(t, "guard failed", recovered.recovered)
+	assert.Contains(t, recovered.stack, "goroutines_test.go")
+}
+
+func contextLabels(ctx context.Context) map[string]string {
+	labels := map[string]string{}
+	pprof.ForLabels(ctx, func(key, value string) bool {
+		labels[key] = value
+		return true
+	})
+
+	return labels
+}
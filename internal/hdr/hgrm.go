@@ -0,0 +1,97 @@
+package hdr
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+)
+
+const (
+	// ticksPerHalfDistance is the number of percentiles written for every halving of the distance
+	// to 100%, matching the defaults of HdrHistogram
+	ticksPerHalfDistance = 5
+	// unitsPerMillisecond scales the written values to milliseconds, the unit expected by plotting tools
+	unitsPerMillisecond = 1000.0
+)
+
+// WriteHgrm writes the percentile distribution of the histogram to w, in the format of the
+// outputPercentileDistribution method of HdrHistogram, with values in milliseconds.
+func (h *Histogram) WriteHgrm(w io.Writer) error {
+	counts := h.snapshot()
+
+	var total uint64
+	var sum float64
+	var maxDuration int64
+	for i, count := range counts {
+		if count == 0 {
+			continue
+		}
+		total += count
+		lowest, size := h.valueRange(i)
+		sum += float64(count) * float64(lowest+size/2)
+		maxDuration = lowest + size - 1
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "%12s %14s %10s %14s\n\n", "Value", "Percentile", "TotalCount", "1/(1-Percentile)")
+
+	mean, stdDeviation := 0.0, 0.0
+	if total > 0 {
+		mean = sum / float64(total)
+		stdDeviation = h.stdDeviation(counts, mean, total)
+		h.writePercentiles(bw, counts, total)
+	}
+
+	fmt.Fprintf(bw, "#[Mean    = %12.3f, StdDeviation   = %12.3f]\n",
+		mean/unitsPerMillisecond, stdDeviation/unitsPerMillisecond)
+	fmt.Fprintf(bw, "#[Max     = %12.3f, Total count    = %12d]\n", float64(maxDuration)/unitsPerMillisecond, total)
+	fmt.Fprintf(bw, "#[Buckets = %12d, SubBuckets     = %12d]\n", h.bucketCount, h.subBucketCount)
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("writing histogram: %w", err)
+	}
+
+	return nil
+}
+
+// writePercentiles writes a line for percentiles which get closer to 100% as the distance to 100%
+// halves, each with the highest value counted below the percentile.
+func (h *Histogram) writePercentiles(w io.Writer, counts []uint64, total uint64) {
+	percentile := 0.0
+	var cumulative uint64
+	for i, count := range counts {
+		if count == 0 {
+			continue
+		}
+		cumulative += count
+		lowest, size := h.valueRange(i)
+		value := float64(lowest+size-1) / unitsPerMillisecond
+
+		if cumulative == total {
+			fmt.Fprintf(w, "%12.3f %2.12f %10d\n", value, 1.0, cumulative)
+			return
+		}
+
+		for 100*float64(cumulative)/float64(total) >= percentile {
+			fmt.Fprintf(w, "%12.3f %2.12f %10d %14.2f\n", value, percentile/100, cumulative, 1/(1-percentile/100))
+
+			halvings := math.Floor(math.Log2(100 / (100 - percentile)))
+			percentile += 100 / (ticksPerHalfDistance * math.Pow(2, halvings+1))
+		}
+	}
+}
+
+func (h *Histogram) stdDeviation(counts []uint64, mean float64, total uint64) float64 {
+	var squaredDeviations float64
+	for i, count := range counts {
+		if count == 0 {
+			continue
+		}
+		lowest, size := h.valueRange(i)
+		deviation := float64(lowest+size/2) - mean
+		squaredDeviations += float64(count) * deviation * deviation
+	}
+
+	return math.Sqrt(squaredDeviations / float64(total))
+}
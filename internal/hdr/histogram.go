@@ -0,0 +1,209 @@
+// Package hdr implements a high dynamic range histogram of durations, recording every value with
+// a fixed number of significant digits, and writing it in the percentile distribution format of
+// HdrHistogram (.hgrm files) so that standard latency tooling can plot and compare runs.
+package hdr
+
+import (
+	"math"
+	"math/bits"
+	"slices"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// significantDigits bounds the relative error of recorded values to 0.1%
+	significantDigits = 3
+	// Unit is the resolution of recorded durations
+	Unit = time.Microsecond
+	// maxValue is the highest recorded value, in units, higher values are recorded as maxValue
+	maxValue = int64(time.Hour / Unit)
+)
+
+// Layout is the layout of the counts of histograms recording durations with a number of
+// significant digits, which the indexes of their Counts refer to.
+type Layout struct {
+	subBucketHalfCountMagnitude int
+	subBucketHalfCount          int
+	subBucketMask               int64
+	subBucketCount              int
+	bucketCount                 int
+}
+
+// NewLayout returns the layout of histograms recording durations between 1µs and an hour with the
+// given number of significant digits.
+func NewLayout(significantDigits int) Layout {
+	largestValueWithSingleUnitResolution := 2 * int64(math.Pow10(significantDigits))
+	subBucketCountMagnitude := int(math.Ceil(math.Log2(float64(largestValueWithSingleUnitResolution))))
+	subBucketCount := 1 << subBucketCountMagnitude
+
+	// every bucket doubles the range of the previous one
+	bucketCount := 1
+	for smallestUntrackable := int64(subBucketCount); smallestUntrackable <= maxValue; smallestUntrackable <<= 1 {
+		bucketCount++
+	}
+
+	return Layout{
+		subBucketHalfCountMagnitude: subBucketCountMagnitude - 1,
+		subBucketHalfCount:          subBucketCount / 2,
+		subBucketMask:               int64(subBucketCount - 1),
+		subBucketCount:              subBucketCount,
+		bucketCount:                 bucketCount,
+	}
+}
+
+func (l Layout) countsLen() int {
+	return (l.bucketCount + 1) * l.subBucketHalfCount
+}
+
+func (l Layout) countsIndex(value int64) int {
+	bucketIndex := l.bucketIndex(value)
+	subBucketIndex := int(value >> bucketIndex)
+
+	return ((bucketIndex + 1) << l.subBucketHalfCountMagnitude) + (subBucketIndex - l.subBucketHalfCount)
+}
+
+func (l Layout) bucketIndex(value int64) int {
+	pow2Ceiling := 64 - bits.LeadingZeros64(uint64(value|l.subBucketMask))
+	return pow2Ceiling - (l.subBucketHalfCountMagnitude + 1)
+}
+
+// valueRange returns the lowest value counted at the counts index, and the size of the range of
+// values counted there.
+func (l Layout) valueRange(index int) (int64, int64) {
+	bucketIndex := (index >> l.subBucketHalfCountMagnitude) - 1
+	subBucketIndex := (index & (l.subBucketHalfCount - 1)) + l.subBucketHalfCount
+	if bucketIndex < 0 {
+		subBucketIndex -= l.subBucketHalfCount
+		bucketIndex = 0
+	}
+
+	return int64(subBucketIndex) << bucketIndex, int64(1) << bucketIndex
+}
+
+// Quantile returns the highest duration counted with the lowest durations making up the
+// q-quantile of the counts. Indexes outside of the layout are ignored.
+func (l Layout) Quantile(counts Counts, q float64) time.Duration {
+	indexes := make([]int, 0, len(counts))
+	var total uint64
+	for i, count := range counts {
+		if i >= 0 && i < l.countsLen() && count > 0 {
+			indexes = append(indexes, i)
+			total += count
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	slices.Sort(indexes)
+
+	target := max(uint64(math.Ceil(q*float64(total))), 1)
+	var cumulative uint64
+	for _, i := range indexes {
+		cumulative += counts[i]
+		if cumulative >= target {
+			lowest, size := l.valueRange(i)
+			return time.Duration(lowest+size-1) * Unit
+		}
+	}
+
+	return time.Duration(maxValue) * Unit
+}
+
+// Counts are the non zero counts of a histogram by index, which can be serialized and merged with
+// the counts of other histograms of the same layout.
+type Counts map[int]uint64
+
+// Merge returns the counts of both histograms.
+func (c Counts) Merge(other Counts) Counts {
+	merged := make(Counts, max(len(c), len(other)))
+	for i, count := range c {
+		merged[i] += count
+	}
+	for i, count := range other {
+		merged[i] += count
+	}
+
+	return merged
+}
+
+// Sub returns the counts of c which aren't counted by other, such as the durations counted
+// between an earlier snapshot other and a later snapshot c.
+func (c Counts) Sub(other Counts) Counts {
+	diff := make(Counts, len(c))
+	for i, count := range c {
+		if previous := other[i]; previous < count {
+			diff[i] = count - previous
+		}
+	}
+
+	return diff
+}
+
+// Histogram counts durations between 1µs and an hour with 3 significant digits, or the layout it
+// was created with. Values are recorded atomically, so that durations can be recorded from
+// multiple goroutines.
+type Histogram struct {
+	Layout
+	counts []atomic.Uint64
+}
+
+// New returns an empty histogram.
+func New() *Histogram {
+	return NewWithLayout(NewLayout(significantDigits))
+}
+
+// NewWithLayout returns an empty histogram with the layout.
+func NewWithLayout(layout Layout) *Histogram {
+	return &Histogram{
+		Layout: layout,
+		counts: make([]atomic.Uint64, layout.countsLen()),
+	}
+}
+
+// Record counts a duration.
+func (h *Histogram) Record(duration time.Duration) {
+	value := min(max(int64(duration/Unit), 0), maxValue)
+	h.counts[h.countsIndex(value)].Add(1)
+}
+
+// snapshot returns the counts of the histogram.
+func (h *Histogram) snapshot() []uint64 {
+	counts := make([]uint64, len(h.counts))
+	for i := range h.counts {
+		counts[i] = h.counts[i].Load()
+	}
+
+	return counts
+}
+
+// Counts returns the counts of the histogram.
+func (h *Histogram) Counts() Counts {
+	counts := Counts{}
+	for i := range h.counts {
+		if count := h.counts[i].Load(); count > 0 {
+			counts[i] = count
+		}
+	}
+
+	return counts
+}
+
+// Swap returns the counts of the histogram and resets them. Every count is swapped with zero, so
+// that durations recorded concurrently are counted by the next swap rather than lost.
+func (h *Histogram) Swap() Counts {
+	counts := Counts{}
+	for i := range h.counts {
+		if count := h.counts[i].Swap(0); count > 0 {
+			counts[i] = count
+		}
+	}
+
+	return counts
+}
+
+// ValueAtPercentile returns the highest duration counted with the lowest durations making up
+// the given percentage of all durations.
+func (h *Histogram) ValueAtPercentile(percentile float64) time.Duration {
+	return h.Quantile(h.Counts(), percentile/100)
+}
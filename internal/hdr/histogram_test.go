@@ -0,0 +1,81 @@
+package hdr_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/hdr"
+)
+
+func TestValueAtPercentile(t *testing.T) {
+	t.Parallel()
+
+	h := hdr.New()
+	for i := 1; i <= 10000; i++ {
+		h.Record(time.Duration(i) * time.Millisecond)
+	}
+
+	for _, test := range []struct {
+		percentile float64
+		expected   time.Duration
+	}{
+		{percentile: 0, expected: time.Millisecond},
+		{percentile: 50, expected: 5 * time.Second},
+		{percentile: 99, expected: 9900 * time.Millisecond},
+		{percentile: 99.9, expected: 9990 * time.Millisecond},
+		{percentile: 100, expected: 10 * time.Second},
+	} {
+		actual := h.ValueAtPercentile(test.percentile)
+		// values are recorded with 3 significant digits
+		assert.InEpsilon(t, test.expected, actual, 0.001, "percentile %v", test.percentile)
+	}
+}
+
+func TestValueAtPercentileOfEmptyHistogram(t *testing.T) {
+	t.Parallel()
+
+	assert.Zero(t, hdr.New().ValueAtPercentile(99))
+}
+
+func TestRecordClampsValues(t *testing.T) {
+	t.Parallel()
+
+	h := hdr.New()
+	h.Record(-time.Second)
+	h.Record(2 * time.Hour)
+
+	assert.Zero(t, h.ValueAtPercentile(50))
+	assert.InEpsilon(t, time.Hour, h.ValueAtPercentile(100), 0.001)
+}
+
+func TestWriteHgrm(t *testing.T) {
+	t.Parallel()
+
+	h := hdr.New()
+	for range 90 {
+		h.Record(time.Millisecond)
+	}
+	for range 10 {
+		h.Record(100 * time.Millisecond)
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, h.WriteHgrm(&out))
+
+	lines := strings.Split(strings.TrimRight(out.String(), "\n"), "\n")
+	assert.Equal(t, "       Value     Percentile TotalCount 1/(1-Percentile)", lines[0])
+	assert.Empty(t, lines[1])
+	assert.Equal(t, "       1.000 0.000000000000         90           1.00", lines[2])
+	assert.Contains(t, lines, "       1.000 0.900000000000         90          10.00")
+	assert.Contains(t, lines, "     100.031 1.000000000000        100")
+	assert.Equal(t, []string{
+		"#[Mean    =       10.900, StdDeviation   =       29.700]",
+		"#[Max     =      100.031, Total count    =          100]",
+		"#[Buckets =           22, SubBuckets     =         2048]",
+	}, lines[len(lines)-3:])
+}
@@ -0,0 +1,115 @@
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+// Entry is the summary of a finished run kept in the history of its scenario.
+type Entry struct {
+	Time              time.Time     `json:"time"`
+	Scenario          string        `json:"scenario"`
+	Duration          time.Duration `json:"duration"`
+	IterationsStarted uint64        `json:"iterations_started"`
+	FailedIterations  uint64        `json:"failed_iterations"`
+	P95               time.Duration `json:"p95"`
+	Failed            bool          `json:"failed"`
+}
+
+// ErrorRate returns the percentage of started iterations which failed.
+func (e Entry) ErrorRate() float64 {
+	if e.IterationsStarted == 0 {
+		return 0
+	}
+
+	return 100 * float64(e.FailedIterations) / float64(e.IterationsStarted)
+}
+
+// Throughput returns the number of iterations started per second.
+func (e Entry) Throughput() float64 {
+	if e.Duration <= 0 {
+		return 0
+	}
+
+	return float64(e.IterationsStarted) / e.Duration.Seconds()
+}
+
+// Store keeps the history of runs in a directory, with one json lines file per scenario.
+type Store struct {
+	dir string
+}
+
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Append adds the entry to the history of its scenario.
+func (s *Store) Append(entry Entry) error {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("creating history directory: %w", err)
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("encoding history entry: %w", err)
+	}
+
+	file, err := os.OpenFile(s.path(entry.Scenario), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening history: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("writing history: %w", err)
+	}
+
+	return nil
+}
+
+// Entries returns the history of the scenario, oldest first.
+func (s *Store) Entries(scenario string) ([]Entry, error) {
+	content, err := os.ReadFile(s.path(scenario))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading history: %w", err)
+	}
+
+	var entries []Entry
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		entry := Entry{}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("parsing history: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// Previous returns the latest run of the scenario. It returns false if the scenario has no history.
+func (s *Store) Previous(scenario string) (Entry, bool, error) {
+	entries, err := s.Entries(scenario)
+	if err != nil || len(entries) == 0 {
+		return Entry{}, false, err
+	}
+
+	return entries[len(entries)-1], true, nil
+}
+
+var unsafeFileNameChars = regexp.MustCompile(`[^a-zA-Z0-9_.-]`)
+
+func (s *Store) path(scenario string) string {
+	return filepath.Join(s.dir, unsafeFileNameChars.ReplaceAllString(scenario, "_")+".jsonl")
+}
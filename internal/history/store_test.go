@@ -0,0 +1,110 @@
+package history_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/history"
+)
+
+func TestEntriesAreAppendedByScenario(t *testing.T) {
+	t.Parallel()
+
+	store := history.NewStore(filepath.Join(t.TempDir(), "history"))
+	now := time.Now().UTC().Truncate(time.Second)
+	first := history.Entry{Time: now.Add(-time.Hour), Scenario: "payments", P95: 200 * time.Millisecond}
+	second := history.Entry{Time: now, Scenario: "payments", P95: 100 * time.Millisecond, Failed: true}
+	other := history.Entry{Time: now, Scenario: "refunds", IterationsStarted: 10}
+
+	require.NoError(t, store.Append(first))
+	require.NoError(t, store.Append(other))
+	require.NoError(t, store.Append(second))
+
+	entries, err := store.Entries("payments")
+	require.NoError(t, err)
+	assert.Equal(t, []history.Entry{first, second}, entries)
+
+	previous, ok, err := store.Previous("refunds")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, other, previous)
+}
+
+func TestScenarioWithoutHistory(t *testing.T) {
+	t.Parallel()
+
+	store := history.NewStore(t.TempDir())
+
+	entries, err := store.Entries("payments")
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	_, ok, err := store.Previous("payments")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestScenarioNamesAreSafeFileNames(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	store := history.NewStore(dir)
+	require.NoError(t, store.Append(history.Entry{Scenario: "../payments/v2 run"}))
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, ".._payments_v2_run.jsonl", files[0].Name())
+
+	entries, err := store.Entries("../payments/v2 run")
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestCorruptHistory(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "payments.jsonl"), []byte("not json\n"), 0o600))
+
+	_, _, err := history.NewStore(dir).Previous("payments")
+
+	require.ErrorContains(t, err, "parsing history")
+}
+
+func TestEntryRates(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name               string
+		entry              history.Entry
+		expectedErrorRate  float64
+		expectedThroughput float64
+	}{
+		{
+			name: "iterations",
+			entry: history.Entry{
+				Duration:          10 * time.Second,
+				IterationsStarted: 200,
+				FailedIterations:  5,
+			},
+			expectedErrorRate:  2.5,
+			expectedThroughput: 20,
+		},
+		{
+			name: "no iterations",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.InDelta(t, test.expectedErrorRate, test.entry.ErrorRate(), 0.001)
+			assert.InDelta(t, test.expectedThroughput, test.entry.Throughput(), 0.001)
+		})
+	}
+}
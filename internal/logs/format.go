@@ -0,0 +1,138 @@
+package logs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/form3tech-oss/f1/v2/internal/termcolor"
+)
+
+const (
+	jsonTimeKey    = "@timestamp"
+	jsonLevelKey   = "level"
+	jsonMessageKey = "message"
+
+	textLevelKey = "level="
+)
+
+// Formatter pretty-prints the lines of a log file written in the text or json format.
+type Formatter struct {
+	color bool
+}
+
+// NewFormatter returns a formatter, which colors the levels of log lines when color is set.
+func NewFormatter(color bool) *Formatter {
+	return &Formatter{color: color}
+}
+
+// Format returns the line pretty-printed. Lines of json logs are printed as the time, level and
+// message followed by the remaining attributes, lines of text logs have their level colored.
+// Lines which are in neither format are returned as they are.
+func (f *Formatter) Format(line string) string {
+	if strings.HasPrefix(line, "{") {
+		if formatted, ok := f.formatJSON(line); ok {
+			return formatted
+		}
+		return line
+	}
+
+	return f.formatText(line)
+}
+
+func (f *Formatter) formatJSON(line string) (string, bool) {
+	decoder := json.NewDecoder(strings.NewReader(line))
+	decoder.UseNumber()
+
+	var entry map[string]any
+	if err := decoder.Decode(&entry); err != nil {
+		return "", false
+	}
+
+	var b strings.Builder
+	if timestamp, ok := entry[jsonTimeKey]; ok {
+		fmt.Fprintf(&b, "%v ", timestamp)
+	}
+	if level, ok := entry[jsonLevelKey]; ok {
+		b.WriteString(f.level(strings.ToUpper(fmt.Sprint(level))))
+		b.WriteString(" ")
+	}
+	fmt.Fprintf(&b, "%v", entry[jsonMessageKey])
+
+	keys := make([]string, 0, len(entry))
+	for key := range entry {
+		if key != jsonTimeKey && key != jsonLevelKey && key != jsonMessageKey {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		fmt.Fprintf(&b, " %s=%s", f.key(key), jsonValue(entry[key]))
+	}
+
+	return b.String(), true
+}
+
+func (f *Formatter) formatText(line string) string {
+	start := strings.Index(line, textLevelKey)
+	if start < 0 || (start > 0 && line[start-1] != ' ') {
+		return line
+	}
+
+	start += len(textLevelKey)
+	end := strings.IndexByte(line[start:], ' ')
+	if end < 0 {
+		end = len(line) - start
+	}
+
+	return line[:start] + f.level(line[start:start+end]) + line[start+end:]
+}
+
+func (f *Formatter) level(level string) string {
+	if !f.color {
+		return level
+	}
+
+	switch level {
+	case "DEBUG":
+		return termcolor.BrightBlack + level + termcolor.Reset
+	case "INFO":
+		return termcolor.Cyan + level + termcolor.Reset
+	case "WARN", "WARNING":
+		return termcolor.Yellow + level + termcolor.Reset
+	case "ERROR":
+		return termcolor.Red + level + termcolor.Reset
+	default:
+		return level
+	}
+}
+
+func (f *Formatter) key(key string) string {
+	if !f.color {
+		return key
+	}
+
+	return termcolor.BrightBlack + key + termcolor.Reset
+}
+
+func jsonValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		if strings.ContainsAny(v, " \"=") {
+			return fmt.Sprintf("%q", v)
+		}
+		return v
+	case nil:
+		return "null"
+	case json.Number, bool:
+		return fmt.Sprint(v)
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(bytes.TrimSpace(encoded))
+	}
+}
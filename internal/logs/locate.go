@@ -0,0 +1,56 @@
+package logs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNoLogFile is returned when no log file matches the run being looked for.
+var ErrNoLogFile = errors.New("no log file found")
+
+// filePattern matches the log files f1 generates when LOG_FILE_PATH isn't set.
+const filePattern = "f1-*.log"
+
+// Locate returns the path of the log file of a run. runIDOrPath is either the path of a log file,
+// or a part of the name of the generated log files in dir, such as the scenario name or the
+// random id of a run, in which case the most recently written matching file is returned. When
+// runIDOrPath is empty, the most recently written log file is returned.
+func Locate(dir, runIDOrPath string) (string, error) {
+	if runIDOrPath != "" {
+		if info, err := os.Stat(runIDOrPath); err == nil && !info.IsDir() {
+			return runIDOrPath, nil
+		}
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, filePattern))
+	if err != nil {
+		return "", fmt.Errorf("listing log files: %w", err)
+	}
+
+	var latest string
+	var latestInfo os.FileInfo
+	for _, path := range paths {
+		if !strings.Contains(filepath.Base(path), runIDOrPath) {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		if latestInfo == nil || info.ModTime().After(latestInfo.ModTime()) {
+			latest, latestInfo = path, info
+		}
+	}
+
+	if latest == "" {
+		if runIDOrPath == "" {
+			return "", fmt.Errorf("%w in %s", ErrNoLogFile, dir)
+		}
+		return "", fmt.Errorf("%w for '%s' in %s", ErrNoLogFile, runIDOrPath, dir)
+	}
+
+	return latest, nil
+}
@@ -0,0 +1,68 @@
+package logs
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+
+	"github.com/form3tech-oss/f1/v2/internal/envsettings"
+)
+
+const (
+	flagFollow  = "follow"
+	flagNoColor = "no-color"
+)
+
+// Cmd returns the logs command, which prints the log file a run redirected its logs to.
+func Cmd(settings envsettings.Settings) *cobra.Command {
+	logsCmd := &cobra.Command{
+		Use:   "logs [run-id|path]",
+		Short: "Prints the log file of a run",
+		Long: `Prints the log file a run redirected its logs to, with the levels colored and json logs
+pretty-printed. The log file is either the given path, or the most recently written log file
+generated in the temp directory whose name contains run-id, such as the scenario name or the
+random id in the "Saving logs to" path of the run. Without arguments, the LOG_FILE_PATH if set,
+or else the most recent generated log file is printed. For example, to follow the logs of a
+running scenario:
+
+  f1 logs --follow myScenario`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: logsCmdExecute(settings),
+	}
+
+	logsCmd.Flags().BoolP(flagFollow, "f", false, "keep printing the lines appended to the log file")
+	logsCmd.Flags().Bool(flagNoColor, false, "don't color the levels of log lines")
+
+	return logsCmd
+}
+
+func logsCmdExecute(settings envsettings.Settings) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		follow, err := cmd.Flags().GetBool(flagFollow)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		noColor, err := cmd.Flags().GetBool(flagNoColor)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+
+		runIDOrPath := settings.Log.FilePath
+		if len(args) > 0 {
+			runIDOrPath = args[0]
+		}
+
+		path, err := Locate(os.TempDir(), runIDOrPath)
+		if err != nil {
+			return fmt.Errorf("locating log file: %w", err)
+		}
+
+		color := !noColor && isatty.IsTerminal(os.Stdout.Fd())
+
+		return Tail(cmd.Context(), path, follow, NewFormatter(color), cmd.OutOrStdout())
+	}
+}
@@ -0,0 +1,183 @@
+package logs_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/logs"
+	"github.com/form3tech-oss/f1/v2/internal/termcolor"
+)
+
+func writeLogFile(t *testing.T, path, content string, modTime time.Time) {
+	t.Helper()
+
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+func TestLocate(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	now := time.Now()
+	older := filepath.Join(dir, "f1-scenarioA-1a2b-2024-01-01_10-00-00.log")
+	newer := filepath.Join(dir, "f1-scenarioB-3c4d-2024-01-01_11-00-00.log")
+	newerA := filepath.Join(dir, "f1-scenarioA-5e6f-2024-01-01_09-00-00.log")
+	writeLogFile(t, older, "", now.Add(-3*time.Hour))
+	writeLogFile(t, newer, "", now.Add(-time.Hour))
+	writeLogFile(t, newerA, "", now.Add(-2*time.Hour))
+	custom := filepath.Join(dir, "custom.log")
+	writeLogFile(t, custom, "", now.Add(-4*time.Hour))
+
+	for name, test := range map[string]struct {
+		runIDOrPath string
+		expected    string
+	}{
+		"most recent log file":               {runIDOrPath: "", expected: newer},
+		"most recent log file of a scenario": {runIDOrPath: "scenarioA", expected: newerA},
+		"log file of a run id":               {runIDOrPath: "1a2b", expected: older},
+		"path of a log file":                 {runIDOrPath: custom, expected: custom},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			path, err := logs.Locate(dir, test.runIDOrPath)
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, path)
+		})
+	}
+}
+
+func TestLocateFailsWithoutAMatchingLogFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeLogFile(t, filepath.Join(dir, "f1-scenarioA-1a2b-2024-01-01_10-00-00.log"), "", time.Now())
+
+	_, err := logs.Locate(dir, "scenarioB")
+	require.ErrorIs(t, err, logs.ErrNoLogFile)
+
+	_, err = logs.Locate(t.TempDir(), "")
+	require.ErrorIs(t, err, logs.ErrNoLogFile)
+}
+
+func TestFormat(t *testing.T) {
+	t.Parallel()
+
+	for name, test := range map[string]struct {
+		line     string
+		color    bool
+		expected string
+	}{
+		"text line": {
+			line:     `time=2024-01-01T10:00:00.000Z level=WARN msg="slow response" scenario=s`,
+			expected: `time=2024-01-01T10:00:00.000Z level=WARN msg="slow response" scenario=s`,
+		},
+		"colored text line": {
+			line:  `time=2024-01-01T10:00:00.000Z level=ERROR msg=failed scenario=s`,
+			color: true,
+			expected: `time=2024-01-01T10:00:00.000Z level=` + termcolor.Red + "ERROR" + termcolor.Reset +
+				` msg=failed scenario=s`,
+		},
+		"json line": {
+			line: `{"@timestamp":"2024-01-01T10:00:00.000Z","level":"info","message":"iteration done",` +
+				`"scenario":"s","iteration":12,"error":"connection refused"}`,
+			expected: `2024-01-01T10:00:00.000Z INFO iteration done error="connection refused" iteration=12 scenario=s`,
+		},
+		"colored json line": {
+			line:  `{"@timestamp":"2024-01-01T10:00:00.000Z","level":"warning","message":"slow","scenario":"s"}`,
+			color: true,
+			expected: "2024-01-01T10:00:00.000Z " + termcolor.Yellow + "WARNING" + termcolor.Reset + " slow " +
+				termcolor.BrightBlack + "scenario" + termcolor.Reset + "=s",
+		},
+		"other line": {
+			line:     "panic: runtime error",
+			color:    true,
+			expected: "panic: runtime error",
+		},
+		"invalid json line": {
+			line:     `{"message":`,
+			expected: `{"message":`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, logs.NewFormatter(test.color).Format(test.line))
+		})
+	}
+}
+
+func TestTailPrintsTheLogFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "f1.log")
+	writeLogFile(t, path, "level=INFO msg=first\nlevel=INFO msg=second", time.Now())
+
+	var out bytes.Buffer
+	err := logs.Tail(context.Background(), path, false, logs.NewFormatter(false), &out)
+
+	require.NoError(t, err)
+	assert.Equal(t, "level=INFO msg=first\nlevel=INFO msg=second\n", out.String())
+}
+
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.String()
+}
+
+func TestTailFollowsTheLinesAppendedToTheLogFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "f1.log")
+	writeLogFile(t, path, "level=INFO msg=first\n", time.Now())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	out := &syncBuffer{}
+	done := make(chan error)
+	go func() {
+		done <- logs.Tail(ctx, path, true, logs.NewFormatter(false), out)
+	}()
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o600)
+	require.NoError(t, err)
+	defer file.Close()
+
+	_, err = file.WriteString("level=INFO msg=sec")
+	require.NoError(t, err)
+	time.Sleep(300 * time.Millisecond)
+	_, err = file.WriteString("ond\n")
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return strings.Contains(out.String(), "msg=second")
+	}, 2*time.Second, 10*time.Millisecond)
+
+	cancel()
+	require.NoError(t, <-done)
+	assert.Equal(t, "level=INFO msg=first\nlevel=INFO msg=second\n", out.String())
+}
@@ -0,0 +1,64 @@
+package logs
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// pollInterval is how often a followed log file is checked for lines written since.
+const pollInterval = 200 * time.Millisecond
+
+// Tail writes the lines of the log file at path to w, pretty-printed by formatter. When follow
+// is set, it keeps writing the lines appended to the file until ctx is done, like tail -f.
+func Tail(ctx context.Context, path string, follow bool, formatter *Formatter, w io.Writer) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("opening log file: %w", err)
+	}
+	defer file.Close()
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	reader := bufio.NewReader(file)
+	var partial strings.Builder
+	for {
+		chunk, err := reader.ReadString('\n')
+		partial.WriteString(chunk)
+
+		if err == nil {
+			line := strings.TrimRight(partial.String(), "\r\n")
+			partial.Reset()
+			if _, err := fmt.Fprintln(w, formatter.Format(line)); err != nil {
+				return fmt.Errorf("writing log line: %w", err)
+			}
+			continue
+		}
+		if !errors.Is(err, io.EOF) {
+			return fmt.Errorf("reading log file: %w", err)
+		}
+
+		if !follow {
+			// the last line of a finished log file may not be terminated
+			if partial.Len() > 0 {
+				if _, err := fmt.Fprintln(w, formatter.Format(partial.String())); err != nil {
+					return fmt.Errorf("writing log line: %w", err)
+				}
+			}
+			return nil
+		}
+
+		// a line being written is kept until the rest of it is appended
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package memory
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/procfs"
+)
+
+//nolint:gochecknoglobals // lookup table of size units
+var units = []struct {
+	suffix     string
+	multiplier uint64
+}{
+	{"KiB", 1 << 10},
+	{"MiB", 1 << 20},
+	{"GiB", 1 << 30},
+	{"KB", 1_000},
+	{"MB", 1_000_000},
+	{"GB", 1_000_000_000},
+	{"K", 1 << 10},
+	{"M", 1 << 20},
+	{"G", 1 << 30},
+	{"B", 1},
+}
+
+// ParseSize parses a size in bytes with an optional unit, for example "512MiB", "2GB" or "1024".
+func ParseSize(size string) (uint64, error) {
+	value := strings.TrimSpace(size)
+	multiplier := uint64(1)
+	for _, unit := range units {
+		if trimmed, ok := strings.CutSuffix(value, unit.suffix); ok {
+			value = strings.TrimSpace(trimmed)
+			multiplier = unit.multiplier
+			break
+		}
+	}
+
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil || number < 0 {
+		return 0, fmt.Errorf("invalid size %q", size)
+	}
+
+	return uint64(number * float64(multiplier)), nil
+}
+
+// FormatSize formats a size in bytes using binary units.
+func FormatSize(size uint64) string {
+	switch {
+	case size >= 1<<30:
+		return fmt.Sprintf("%.1fGiB", float64(size)/(1<<30))
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1fMiB", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.1fKiB", float64(size)/(1<<10))
+	default:
+		return fmt.Sprintf("%dB", size)
+	}
+}
+
+// ProcessMemory returns the resident set size of the current process. Where it is not available,
+// it falls back to the memory obtained from the operating system by the go runtime.
+func ProcessMemory() uint64 {
+	if proc, err := procfs.Self(); err == nil {
+		if stat, err := proc.Stat(); err == nil {
+			return uint64(stat.ResidentMemory())
+		}
+	}
+
+	stats := runtime.MemStats{}
+	runtime.ReadMemStats(&stats)
+	return stats.Sys
+}
@@ -0,0 +1,48 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/memory"
+)
+
+func TestParseSize(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		size          string
+		expected      uint64
+		expectedError string
+	}{
+		{size: "1024", expected: 1024},
+		{size: "10B", expected: 10},
+		{size: "512KiB", expected: 512 << 10},
+		{size: "512MiB", expected: 512 << 20},
+		{size: "1.5GiB", expected: 3 << 29},
+		{size: "2GB", expected: 2_000_000_000},
+		{size: "100M", expected: 100 << 20},
+		{size: "lots", expectedError: `invalid size "lots"`},
+		{size: "-1MiB", expectedError: `invalid size "-1MiB"`},
+	} {
+		t.Run(test.size, func(t *testing.T) {
+			t.Parallel()
+
+			size, err := memory.ParseSize(test.size)
+			if test.expectedError != "" {
+				require.EqualError(t, err, test.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expected, size)
+		})
+	}
+}
+
+func TestProcessMemory(t *testing.T) {
+	t.Parallel()
+
+	require.Positive(t, memory.ProcessMemory())
+}
@@ -2,9 +2,13 @@ package metrics
 
 import (
 	"errors"
+	"fmt"
+	"hash/fnv"
+	"strings"
 	"sync"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/expfmt"
 )
 
 const (
@@ -12,21 +16,71 @@ const (
 	metricSubsystem = "loadtest"
 )
 
-const IterationMetricName = "form3_loadtest_iteration"
+// IterationMetricName is the name of the iteration metric, unless its prefix is overridden by Naming.
+const IterationMetricName = DefaultPrefix + "_iteration"
 
 const (
-	TestNameLabel = "test"
-	StageLabel    = "stage"
-	ResultLabel   = "result"
+	TestNameLabel   = "test"
+	StageLabel      = "stage"
+	ResultLabel     = "result"
+	LabelNameLabel  = "label"
+	LabelValueLabel = "value"
+)
+
+// ConnectionEventLabel is the event counted by the connection events metric.
+const ConnectionEventLabel = "event"
+
+// BytesDirectionLabel is the direction of the bytes counted by the iteration bytes metric, either
+// BytesSent or BytesReceived.
+const (
+	BytesDirectionLabel = "direction"
+	BytesSent           = "sent"
+	BytesReceived       = "received"
+)
+
+// FixturePoolLabel is the fixture pool of the fixture pool metric, and FixturePoolStateLabel the
+// state of its entities, either FixturesAvailable or FixturesLeased.
+const (
+	FixturePoolLabel      = "pool"
+	FixturePoolStateLabel = "state"
+	FixturesAvailable     = "available"
+	FixturesLeased        = "leased"
+)
+
+// MaxLabelValues is the number of distinct values recorded for every custom iteration label, and
+// MaxLabelNames the number of distinct custom labels recorded for every scenario. Further values
+// are recorded as OtherLabelValue, and the values of further labels as OtherLabelValue labels, to
+// limit the cardinality of the metric.
+const (
+	MaxLabelValues  = 20
+	MaxLabelNames   = 10
+	OtherLabelValue = "other"
 )
 
 const IterationStage = "iteration"
 
 type Metrics struct {
-	Setup                   *prometheus.SummaryVec
-	Iteration               *prometheus.SummaryVec
+	Setup          *prometheus.SummaryVec
+	Iteration      *prometheus.SummaryVec
+	IterationLabel *prometheus.SummaryVec
+	// MetricsPushFailures counts the pushes to the push gateway which failed, pushed with the
+	// next successful push
+	MetricsPushFailures *prometheus.CounterVec
+	// IterationsTriggered and IterationsStarted count the iterations planned by the trigger and
+	// those which started, so that the target and the achieved rates of the run can be compared
+	IterationsTriggered     *prometheus.CounterVec
+	IterationsStarted       *prometheus.CounterVec
 	Registry                *prometheus.Registry
+	labelValues             map[string]map[string]struct{}
+	labelNames              map[string]map[string]struct{}
+	labelValuesMu           sync.Mutex
 	IterationMetricsEnabled bool
+	// ConnectionEvents counts the connections opened, closed and dropped by the connections trigger
+	ConnectionEvents *prometheus.CounterVec
+	// IterationBytes counts the bytes sent and received by iterations, recorded by the scenario
+	IterationBytes *prometheus.CounterVec
+	// FixturePools counts the available and leased entities of the fixture pools of the scenario
+	FixturePools *prometheus.GaugeVec
 }
 
 //nolint:gochecknoglobals // removing the global Instance is a breaking change
@@ -35,49 +89,106 @@ var (
 	once sync.Once
 )
 
-func buildMetrics() *Metrics {
+func buildMetrics(naming Naming) *Metrics {
 	percentileObjectives := map[float64]float64{
 		0.5: 0.05, 0.75: 0.05, 0.9: 0.01, 0.95: 0.001, 0.99: 0.001, 0.9999: 0.00001, 1.0: 0.00001,
 	}
 
 	return &Metrics{
 		Setup: prometheus.NewSummaryVec(prometheus.SummaryOpts{
-			Namespace:  metricNamespace,
-			Subsystem:  metricSubsystem,
-			Name:       "setup",
-			Help:       "Duration of setup functions.",
-			Objectives: percentileObjectives,
+			Namespace:   naming.prefix(),
+			ConstLabels: naming.ConstLabels,
+			Name:        "setup",
+			Help:        "Duration of setup functions.",
+			Objectives:  percentileObjectives,
 		}, []string{TestNameLabel, ResultLabel}),
 		Iteration: prometheus.NewSummaryVec(prometheus.SummaryOpts{
-			Namespace:  metricNamespace,
-			Subsystem:  metricSubsystem,
-			Name:       "iteration",
-			Help:       "Duration of iteration functions.",
-			Objectives: percentileObjectives,
+			Namespace:   naming.prefix(),
+			ConstLabels: naming.ConstLabels,
+			Name:        "iteration",
+			Help:        "Duration of iteration functions.",
+			Objectives:  percentileObjectives,
 		}, []string{TestNameLabel, StageLabel, ResultLabel}),
+		IterationLabel: prometheus.NewSummaryVec(prometheus.SummaryOpts{
+			Namespace:   naming.prefix(),
+			ConstLabels: naming.ConstLabels,
+			Name:        "iteration_label",
+			Help:        "Duration of iteration functions by custom label.",
+			Objectives:  percentileObjectives,
+		}, []string{TestNameLabel, LabelNameLabel, LabelValueLabel, ResultLabel}),
+		MetricsPushFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   naming.prefix(),
+			ConstLabels: naming.ConstLabels,
+			Name:        "metrics_push_failures_total",
+			Help:        "Number of pushes of metrics to the push gateway which failed.",
+		}, []string{TestNameLabel}),
+		IterationsTriggered: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   naming.prefix(),
+			ConstLabels: naming.ConstLabels,
+			Name:        "iterations_triggered_total",
+			Help:        "Number of iterations the trigger planned to start, the target rate of the run.",
+		}, []string{TestNameLabel}),
+		IterationsStarted: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   naming.prefix(),
+			ConstLabels: naming.ConstLabels,
+			Name:        "iterations_started_total",
+			Help:        "Number of iterations which started, the achieved rate of the run.",
+		}, []string{TestNameLabel}),
+		ConnectionEvents: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   naming.prefix(),
+			ConstLabels: naming.ConstLabels,
+			Name:        "connection_events_total",
+			Help:        "Number of connections opened, closed and dropped by the connections trigger.",
+		}, []string{TestNameLabel, ConnectionEventLabel}),
+		IterationBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   naming.prefix(),
+			ConstLabels: naming.ConstLabels,
+			Name:        "iteration_bytes_total",
+			Help:        "Number of bytes sent and received by iterations, as recorded by the scenario.",
+		}, []string{TestNameLabel, BytesDirectionLabel}),
+		FixturePools: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace:   naming.prefix(),
+			ConstLabels: naming.ConstLabels,
+			Name:        "fixture_pool_entities",
+			Help:        "Number of entities of fixture pools available to iterations or leased by them.",
+		}, []string{TestNameLabel, FixturePoolLabel, FixturePoolStateLabel}),
+		labelValues: make(map[string]map[string]struct{}),
+		labelNames:  make(map[string]map[string]struct{}),
 	}
 }
 
 func NewInstance(registry *prometheus.Registry, iterationMetricsEnabled bool) *Metrics {
-	i := buildMetrics()
+	return NewNamedInstance(registry, iterationMetricsEnabled, Naming{})
+}
+
+// NewNamedInstance is NewInstance with the names and constant labels of the metrics set by naming.
+func NewNamedInstance(registry *prometheus.Registry, iterationMetricsEnabled bool, naming Naming) *Metrics {
+	i := buildMetrics(naming)
 	i.Registry = registry
 
 	i.Registry.MustRegister(
 		i.Setup,
 		i.Iteration,
+		i.IterationLabel,
+		i.MetricsPushFailures,
+		i.IterationsTriggered,
+		i.IterationsStarted,
+		i.ConnectionEvents,
+		i.IterationBytes,
+		i.FixturePools,
 	)
 	i.IterationMetricsEnabled = iterationMetricsEnabled
 
 	return i
 }
 
-func Init(iterationMetricsEnabled bool) {
+func Init(iterationMetricsEnabled bool, naming Naming) {
 	once.Do(func() {
 		defaultRegistry, ok := prometheus.DefaultRegisterer.(*prometheus.Registry)
 		if !ok {
 			panic(errors.New("casting prometheus.DefaultRegisterer to Registry"))
 		}
-		m = NewInstance(defaultRegistry, iterationMetricsEnabled)
+		m = NewNamedInstance(defaultRegistry, iterationMetricsEnabled, naming)
 	})
 	m.IterationMetricsEnabled = iterationMetricsEnabled
 }
@@ -86,9 +197,50 @@ func Instance() *Metrics {
 	return m
 }
 
+// Reset removes all series recorded by previous runs. It is called at the start of every run, so
+// that runs executed sequentially in the same process don't push each other's series.
 func (metrics *Metrics) Reset() {
 	metrics.Iteration.Reset()
+	metrics.IterationLabel.Reset()
 	metrics.Setup.Reset()
+	metrics.MetricsPushFailures.Reset()
+	metrics.IterationsTriggered.Reset()
+	metrics.IterationsStarted.Reset()
+	metrics.ConnectionEvents.Reset()
+	metrics.IterationBytes.Reset()
+	metrics.FixturePools.Reset()
+
+	metrics.labelValuesMu.Lock()
+	defer metrics.labelValuesMu.Unlock()
+	metrics.labelValues = make(map[string]map[string]struct{})
+	metrics.labelNames = make(map[string]map[string]struct{})
+}
+
+// Digest returns a hash of the current values of the metrics of the registry, to tell whether they
+// changed since a previous digest. The metrics of the Go runtime and the process are left out.
+func (metrics *Metrics) Digest() (uint64, error) {
+	families, err := metrics.Registry.Gather()
+	if err != nil {
+		return 0, fmt.Errorf("gathering metrics: %w", err)
+	}
+
+	hash := fnv.New64a()
+	for _, family := range families {
+		if isRuntimeMetric(family.GetName()) {
+			continue
+		}
+		if _, err := expfmt.MetricFamilyToText(hash, family); err != nil {
+			return 0, fmt.Errorf("encoding metric %s: %w", family.GetName(), err)
+		}
+	}
+
+	return hash.Sum64(), nil
+}
+
+// isRuntimeMetric tells the metrics of the Go runtime and process collectors of the default
+// registry, which change constantly whether the load test progresses or not.
+func isRuntimeMetric(name string) bool {
+	return strings.HasPrefix(name, "go_") || strings.HasPrefix(name, "process_")
 }
 
 func (metrics *Metrics) RecordSetupResult(name string, result ResultType, nanoseconds int64) {
@@ -110,3 +262,107 @@ func (metrics *Metrics) RecordIterationStage(name string, stage string, result R
 
 	metrics.Iteration.WithLabelValues(name, stage, result.String()).Observe(float64(nanoseconds))
 }
+
+// RecordMetricsPushFailure counts a push of metrics to the push gateway which failed.
+func (metrics *Metrics) RecordMetricsPushFailure(name string) {
+	metrics.MetricsPushFailures.WithLabelValues(name).Inc()
+}
+
+// RecordIterationsTriggered counts iterations the trigger planned to start.
+func (metrics *Metrics) RecordIterationsTriggered(name string, iterations int) {
+	if !metrics.IterationMetricsEnabled {
+		return
+	}
+
+	metrics.IterationsTriggered.WithLabelValues(name).Add(float64(iterations))
+}
+
+// RecordIterationStarted counts an iteration which started.
+func (metrics *Metrics) RecordIterationStarted(name string) {
+	if !metrics.IterationMetricsEnabled {
+		return
+	}
+
+	metrics.IterationsStarted.WithLabelValues(name).Inc()
+}
+
+// RecordConnectionEvent counts an event of a connection of the connections trigger.
+func (metrics *Metrics) RecordConnectionEvent(name, event string) {
+	if !metrics.IterationMetricsEnabled {
+		return
+	}
+
+	metrics.ConnectionEvents.WithLabelValues(name, event).Inc()
+}
+
+// RecordIterationBytes counts the bytes sent and received by an iteration.
+func (metrics *Metrics) RecordIterationBytes(name string, sent, received uint64) {
+	if !metrics.IterationMetricsEnabled {
+		return
+	}
+
+	metrics.IterationBytes.WithLabelValues(name, BytesSent).Add(float64(sent))
+	metrics.IterationBytes.WithLabelValues(name, BytesReceived).Add(float64(received))
+}
+
+// RecordFixturePool sets the available and leased entities of a fixture pool.
+func (metrics *Metrics) RecordFixturePool(name, pool string, available, leased int) {
+	if !metrics.IterationMetricsEnabled {
+		return
+	}
+
+	metrics.FixturePools.WithLabelValues(name, pool, FixturesAvailable).Set(float64(available))
+	metrics.FixturePools.WithLabelValues(name, pool, FixturesLeased).Set(float64(leased))
+}
+
+// RecordIterationLabel records the duration of an iteration by a custom label set by the scenario.
+func (metrics *Metrics) RecordIterationLabel(name, label, value string, result ResultType, nanoseconds int64) {
+	if !metrics.IterationMetricsEnabled {
+		return
+	}
+
+	label, value = metrics.guardLabel(name, label, value)
+	metrics.IterationLabel.WithLabelValues(name, label, value, result.String()).Observe(float64(nanoseconds))
+}
+
+// guardLabel limits the number of distinct labels of a scenario to MaxLabelNames, and the number of
+// distinct values of a label to MaxLabelValues.
+func (metrics *Metrics) guardLabel(name, label, value string) (string, string) {
+	metrics.labelValuesMu.Lock()
+	defer metrics.labelValuesMu.Unlock()
+
+	labels, ok := metrics.labelNames[name]
+	if !ok {
+		labels = make(map[string]struct{})
+		metrics.labelNames[name] = labels
+	}
+	if _, ok := labels[label]; !ok {
+		if len(labels) >= MaxLabelNames {
+			return OtherLabelValue, OtherLabelValue
+		}
+		labels[label] = struct{}{}
+	}
+
+	return label, metrics.guardLabelValue(name, label, value)
+}
+
+// guardLabelValue limits the number of distinct values of a label to MaxLabelValues. It must be
+// called with labelValuesMu held.
+func (metrics *Metrics) guardLabelValue(name, label, value string) string {
+	key := name + "/" + label
+	values, ok := metrics.labelValues[key]
+	if !ok {
+		values = make(map[string]struct{})
+		metrics.labelValues[key] = values
+	}
+
+	if _, ok := values[value]; ok {
+		return value
+	}
+	if len(values) >= MaxLabelValues {
+		return OtherLabelValue
+	}
+
+	values[value] = struct{}{}
+	return value
+}
@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DefaultPrefix is the prefix of the names of the metrics of f1, unless overridden by Naming.
+const DefaultPrefix = metricNamespace + "_" + metricSubsystem
+
+var (
+	metricPrefixPattern = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+	labelNamePattern    = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+)
+
+// Naming customises the names of the metrics of f1 to the conventions of the organisation running
+// it, so that the metrics of different teams don't collide.
+type Naming struct {
+	// Prefix replaces DefaultPrefix in the names of all the metrics, if set
+	Prefix string
+	// ConstLabels are added to all the metrics
+	ConstLabels prometheus.Labels
+}
+
+// NewNaming validates a metric prefix, empty for DefaultPrefix, and constant labels as a comma
+// separated list of name=value pairs, such as team=payments,env=staging.
+func NewNaming(prefix, constLabels string) (Naming, error) {
+	if prefix != "" && !metricPrefixPattern.MatchString(prefix) {
+		return Naming{}, fmt.Errorf("invalid metric prefix %q", prefix)
+	}
+
+	labels, err := parseConstLabels(constLabels)
+	if err != nil {
+		return Naming{}, err
+	}
+
+	return Naming{Prefix: prefix, ConstLabels: labels}, nil
+}
+
+func parseConstLabels(value string) (prometheus.Labels, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	// the labels of the metrics can't also be constant labels
+	reserved := []string{
+		TestNameLabel, StageLabel, ResultLabel, LabelNameLabel, LabelValueLabel, ConnectionEventLabel, BytesDirectionLabel,
+		FixturePoolLabel, FixturePoolStateLabel,
+	}
+	labels := prometheus.Labels{}
+	for _, pair := range strings.Split(value, ",") {
+		name, labelValue, found := strings.Cut(strings.TrimSpace(pair), "=")
+		name = strings.TrimSpace(name)
+		if !found || !labelNamePattern.MatchString(name) || strings.HasPrefix(name, "__") {
+			return nil, fmt.Errorf("invalid constant label %q, expected name=value", pair)
+		}
+		if slices.Contains(reserved, name) {
+			return nil, fmt.Errorf("constant label %s is already a label of the metrics", name)
+		}
+		if _, ok := labels[name]; ok {
+			return nil, fmt.Errorf("constant label %s is set more than once", name)
+		}
+		labels[name] = strings.TrimSpace(labelValue)
+	}
+
+	return labels, nil
+}
+
+func (n Naming) prefix() string {
+	if n.Prefix == "" {
+		return DefaultPrefix
+	}
+
+	return n.Prefix
+}
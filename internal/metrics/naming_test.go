@@ -0,0 +1,101 @@
+package metrics_test
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/metrics"
+)
+
+func TestMetricsAreNamedWithThePrefixAndConstantLabels(t *testing.T) {
+	t.Parallel()
+
+	naming, err := metrics.NewNaming("payments_loadtest", "team=payments, env=staging")
+	require.NoError(t, err)
+	instance := metrics.NewNamedInstance(prometheus.NewRegistry(), true, naming)
+
+	instance.RecordIterationResult("scenario", metrics.SucessResult, 1)
+
+	families, err := instance.Registry.Gather()
+	require.NoError(t, err)
+	names := make([]string, 0, len(families))
+	for _, family := range families {
+		names = append(names, family.GetName())
+		if family.GetName() != "payments_loadtest_iteration" {
+			continue
+		}
+		labels := map[string]string{}
+		for _, label := range family.GetMetric()[0].GetLabel() {
+			labels[label.GetName()] = label.GetValue()
+		}
+		assert.Equal(t, "payments", labels["team"])
+		assert.Equal(t, "staging", labels["env"])
+		assert.Equal(t, "scenario", labels[metrics.TestNameLabel])
+	}
+	assert.Contains(t, names, "payments_loadtest_iteration")
+}
+
+func TestMetricsAreNamedWithTheDefaultPrefix(t *testing.T) {
+	t.Parallel()
+
+	naming, err := metrics.NewNaming("", "")
+	require.NoError(t, err)
+	instance := metrics.NewNamedInstance(prometheus.NewRegistry(), true, naming)
+
+	instance.RecordIterationResult("scenario", metrics.SucessResult, 1)
+
+	families, err := instance.Registry.Gather()
+	require.NoError(t, err)
+	require.Len(t, families, 1)
+	assert.Equal(t, metrics.IterationMetricName, families[0].GetName())
+	// only the labels of the metric, without constant labels
+	assert.Len(t, families[0].GetMetric()[0].GetLabel(), 3)
+}
+
+func TestNewNamingFails(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name        string
+		prefix      string
+		constLabels string
+		expected    string
+	}{
+		{
+			name:     "invalid prefix",
+			prefix:   "payments-loadtest",
+			expected: `invalid metric prefix "payments-loadtest"`,
+		},
+		{
+			name:        "missing value",
+			constLabels: "team",
+			expected:    `invalid constant label "team", expected name=value`,
+		},
+		{
+			name:        "invalid label name",
+			constLabels: "team-name=payments",
+			expected:    `invalid constant label "team-name=payments", expected name=value`,
+		},
+		{
+			name:        "label of the metrics",
+			constLabels: "result=success",
+			expected:    "constant label result is already a label of the metrics",
+		},
+		{
+			name:        "duplicate label",
+			constLabels: "team=payments,team=accounts",
+			expected:    "constant label team is set more than once",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := metrics.NewNaming(test.prefix, test.constLabels)
+
+			require.EqualError(t, err, test.expected)
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+
+	"github.com/prometheus/client_golang/prometheus"
+	otelprometheus "go.opentelemetry.io/contrib/bridges/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// OTLPMetricsPath is the path of the OTLP/HTTP metrics endpoint of a collector, used for endpoints
+// without a path.
+const OTLPMetricsPath = "/v1/metrics"
+
+var errInvalidOTLPEndpoint = errors.New("invalid OTLP metrics endpoint")
+
+// OTLPExporter exports the metrics of a registry to the OTLP/HTTP metrics endpoint of an
+// OpenTelemetry collector whenever Export is called, as metrics are pushed to a push gateway.
+type OTLPExporter struct {
+	reader   *sdkmetric.ManualReader
+	provider *sdkmetric.MeterProvider
+	exporter sdkmetric.Exporter
+}
+
+// NewOTLPExporter returns an exporter of the metrics of gatherer to endpoint, such as
+// http://collector:4318, describing them with the resource attributes. The headers and TLS
+// settings of the export are read from the OTEL_EXPORTER_OTLP environment variables.
+func NewOTLPExporter(
+	endpoint string,
+	gatherer prometheus.Gatherer,
+	attributes map[string]string,
+) (*OTLPExporter, error) {
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil || endpointURL.Host == "" {
+		return nil, fmt.Errorf("%w: %s, expected a url such as http://collector:4318", errInvalidOTLPEndpoint, endpoint)
+	}
+	if endpointURL.Path == "" {
+		endpointURL.Path = OTLPMetricsPath
+	}
+
+	// failed exports are counted and retried with the next export, as failed pushes are, rather than
+	// retried in the background for up to a minute
+	exporter, err := otlpmetrichttp.New(context.Background(),
+		otlpmetrichttp.WithEndpointURL(endpointURL.String()),
+		otlpmetrichttp.WithRetry(otlpmetrichttp.RetryConfig{Enabled: false}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("creating OTLP metrics exporter: %w", err)
+	}
+
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	resourceAttributes := make([]attribute.KeyValue, 0, len(names))
+	for _, name := range names {
+		resourceAttributes = append(resourceAttributes, attribute.String(name, attributes[name]))
+	}
+
+	reader := sdkmetric.NewManualReader(sdkmetric.WithProducer(
+		otelprometheus.NewMetricProducer(otelprometheus.WithGatherer(gatherer)),
+	))
+
+	return &OTLPExporter{
+		reader: reader,
+		provider: sdkmetric.NewMeterProvider(
+			sdkmetric.WithReader(reader),
+			sdkmetric.WithResource(resource.NewSchemaless(resourceAttributes...)),
+		),
+		exporter: exporter,
+	}, nil
+}
+
+// Export exports the current values of the metrics.
+func (e *OTLPExporter) Export(ctx context.Context) error {
+	var metrics metricdata.ResourceMetrics
+	if err := e.reader.Collect(ctx, &metrics); err != nil {
+		return fmt.Errorf("collecting metrics: %w", err)
+	}
+
+	if err := e.exporter.Export(ctx, &metrics); err != nil {
+		return fmt.Errorf("exporting metrics: %w", err)
+	}
+
+	return nil
+}
+
+// Shutdown releases the connections of the exporter, which can't export metrics afterwards.
+func (e *OTLPExporter) Shutdown(ctx context.Context) error {
+	if err := errors.Join(e.provider.Shutdown(ctx), e.exporter.Shutdown(ctx)); err != nil {
+		return fmt.Errorf("shutting down OTLP metrics exporter: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package metrics_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/metrics"
+)
+
+func TestOTLPExporterExportsToTheMetricsPath(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name     string
+		path     string
+		expected string
+	}{
+		{name: "default path", path: "", expected: metrics.OTLPMetricsPath},
+		{name: "custom path", path: "/otlp/v1/metrics", expected: "/otlp/v1/metrics"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			paths := make(chan string, 1)
+			collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				paths <- req.URL.Path
+				w.WriteHeader(http.StatusOK)
+			}))
+			t.Cleanup(collector.Close)
+
+			instance := metrics.NewInstance(prometheus.NewRegistry(), true)
+			instance.RecordIterationStarted("scenario")
+
+			exporter, err := metrics.NewOTLPExporter(collector.URL+test.path, instance.Registry,
+				map[string]string{"service.name": "f1-scenario"})
+			require.NoError(t, err)
+
+			require.NoError(t, exporter.Export(context.Background()))
+			require.NoError(t, exporter.Shutdown(context.Background()))
+
+			assert.Equal(t, test.expected, <-paths)
+		})
+	}
+}
+
+func TestOTLPExporterInvalidEndpoint(t *testing.T) {
+	t.Parallel()
+
+	for _, endpoint := range []string{"collector:4318", "://collector"} {
+		_, err := metrics.NewOTLPExporter(endpoint, prometheus.NewRegistry(), nil)
+		require.ErrorContains(t, err, "invalid OTLP metrics endpoint", endpoint)
+	}
+}
@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/prometheus/common/expfmt"
+)
+
+// Formats of WriteSummary.
+const (
+	// SummaryOpenMetrics is the OpenMetrics text format
+	SummaryOpenMetrics = "openmetrics"
+	// SummaryText is the classic text format of Prometheus, read by the textfile collector of node_exporter
+	SummaryText = "text"
+)
+
+var errUnknownSummaryFormat = errors.New("unknown summary metrics format")
+
+// SummaryFormat returns the exposition format of the summary format name, see WriteSummary.
+func SummaryFormat(name string) (expfmt.Format, error) {
+	switch name {
+	case SummaryOpenMetrics, "":
+		return expfmt.NewFormat(expfmt.TypeOpenMetrics), nil
+	case SummaryText:
+		return expfmt.NewFormat(expfmt.TypeTextPlain), nil
+	default:
+		return "", fmt.Errorf("%w: %s, expected one of %s or %s",
+			errUnknownSummaryFormat, name, SummaryOpenMetrics, SummaryText)
+	}
+}
+
+// WriteSummary writes the current values of the metrics of the registry to w in format, leaving
+// out the metrics of the Go runtime and the process, which describe f1 rather than the run.
+func (metrics *Metrics) WriteSummary(w io.Writer, format expfmt.Format) error {
+	families, err := metrics.Registry.Gather()
+	if err != nil {
+		return fmt.Errorf("gathering metrics: %w", err)
+	}
+
+	encoder := expfmt.NewEncoder(w, format)
+	for _, family := range families {
+		if isRuntimeMetric(family.GetName()) {
+			continue
+		}
+		if err := encoder.Encode(family); err != nil {
+			return fmt.Errorf("encoding metric %s: %w", family.GetName(), err)
+		}
+	}
+
+	if closer, ok := encoder.(expfmt.Closer); ok {
+		if err := closer.Close(); err != nil {
+			return fmt.Errorf("completing metrics: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package metrics_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/metrics"
+)
+
+func TestWriteSummaryLeavesOutTheRuntimeMetrics(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collectors.NewGoCollector())
+	instance := metrics.NewInstance(registry, true)
+	instance.RecordIterationStarted("scenario")
+
+	format, err := metrics.SummaryFormat(metrics.SummaryOpenMetrics)
+	require.NoError(t, err)
+
+	var summary bytes.Buffer
+	require.NoError(t, instance.WriteSummary(&summary, format))
+
+	assert.Contains(t, summary.String(), `form3_loadtest_iterations_started_total{test="scenario"} 1.0`)
+	assert.NotContains(t, summary.String(), "go_goroutines")
+	assert.Equal(t, "# EOF\n", summary.String()[summary.Len()-len("# EOF\n"):])
+}
+
+func TestSummaryFormat(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{"", metrics.SummaryOpenMetrics, metrics.SummaryText} {
+		_, err := metrics.SummaryFormat(name)
+		require.NoError(t, err, name)
+	}
+
+	_, err := metrics.SummaryFormat("json")
+	require.ErrorContains(t, err, "unknown summary metrics format: json, expected one of openmetrics or text")
+}
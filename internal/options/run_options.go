@@ -5,14 +5,103 @@ import (
 )
 
 type RunOptions struct {
-	Scenario        string
+	Scenario string
+	// ScenarioArgs are the arguments passed to the scenario after `--`, see testing.T.Args
+	ScenarioArgs    []string
 	MaxDuration     time.Duration
 	Concurrency     int
 	MaxIterations   uint64
 	MaxFailures     uint64
 	MaxFailuresRate int
-	Verbose         bool
-	IgnoreDropped   bool
+	// MaxMemory is the resident memory in bytes above which the run is stopped, or 0 for no limit
+	MaxMemory uint64
+	// StateFile is the file used to save the progress of the run, so that it can be resumed
+	StateFile string
+	// Elapsed is the duration of the run completed before it was resumed
+	Elapsed time.Duration
+	// Trace is the backend tracing the internals of the run, see tracing.New
+	Trace string
+	// TraceFile is the file written by the file trace backend
+	TraceFile string
+	// SnapshotFailureRate is the failure rate, in percent, above which a diagnostic snapshot is
+	// written to SnapshotDir, or 0 for no snapshots
+	SnapshotFailureRate int
+	SnapshotDir         string
+	// PprofCapture is how long CPU profiles are captured for at the peak of the run, into a
+	// directory of SnapshotDir, or 0 for no profiles
+	PprofCapture time.Duration
+	// HistogramFile is the file the HDR histogram of iteration durations is written to, if set
+	HistogramFile string
+	// AuditLog is the file every iteration is recorded in as a json line, if set
+	AuditLog string
+	// AnnotateGC reports the iterations which overlapped garbage collections of f1
+	AnnotateGC bool
+	// ShowSlowest displays the slowest iterations of the run in its summary
+	ShowSlowest bool
+	// Endless runs run until they are stopped, see run.EndlessDuration
+	Endless bool
+	// SLOMaxP95, SLOMaxP99 and SLOMaxErrorRate stop the run when they are set and exceeded
+	SLOMaxP95       time.Duration
+	SLOMaxP99       time.Duration
+	SLOMaxErrorRate *float64
+	// ReportSnapshotFile is written with the report of the run every ReportSnapshotInterval, if set
+	ReportSnapshotFile     string
+	ReportSnapshotInterval time.Duration
+	// RequireMetrics is the number of pushes of metrics to the push gateway which may fail in a
+	// row before the run is stopped and failed, or 0 to never fail the run for missing metrics
+	RequireMetrics int
+	// StartAt is when the run starts triggering iterations after its setup, if set
+	StartAt time.Time
+	// SyncBarrier is the url or address of the barrier the run waits at after its setup, to start
+	// triggering iterations together with other processes, if set
+	SyncBarrier string
+	// ReadyURL is checked with a GET before iterations are started and between stages, if set, as is
+	// the readiness function of the scenario. The run waits up to ReadyTimeout for the target to be
+	// ready, checking it every ReadyInterval, and fails if it doesn't become ready.
+	ReadyURL      string
+	ReadyTimeout  time.Duration
+	ReadyInterval time.Duration
+	// WorkerCPUs are the CPUs the workers are pinned to in turn, or nil for unpinned workers
+	WorkerCPUs []int
+	// WorkerRamp is how long the workers of each pool take to start, all at once if 0
+	WorkerRamp time.Duration
+	// AutotuneConcurrency adjusts the workers taking iterations to the rate and latency of the
+	// iterations while the run is in progress, up to Concurrency
+	AutotuneConcurrency bool
+	// WorkerPacing is the shortest interval between the iterations started by a worker, from the
+	// rate limit of the workers, unlimited if 0
+	WorkerPacing time.Duration
+	// RateOverrideFile is watched during the run for overrides of the rate of the trigger, if set
+	RateOverrideFile string
+	// UniqueIterations numbers iterations uniquely across the resumes of the run of StateFile
+	UniqueIterations bool
+	// Redact are the patterns whose matches are redacted from the logs, error summaries, report and
+	// artifacts of the run, see redact.New
+	Redact []string
+	// SummaryMetricsFile is written with the metrics of the run once it completes, if set, in the
+	// SummaryMetricsFormat, see metrics.SummaryFormat
+	SummaryMetricsFile   string
+	SummaryMetricsFormat string
+	// HardDeadline is when the run is terminated if it is still in progress, whether in its setup,
+	// load or teardown, if set
+	HardDeadline time.Time
+	// TemplateVariables are the variables read by the templates of the output, with those of the run,
+	// and TemplateDir the directory of the templates replacing the built-in ones, see views.NewCustom
+	TemplateVariables map[string]string
+	TemplateDir       string
+	// OutputFormat is text or json, which also writes the final result of the run as a json report
+	// to OutputFile, or to stdout with the rest of the output written to stderr if it isn't set
+	OutputFormat string
+	OutputFile   string
+	// JUnitReport is the file the result of the run is written to as a JUnit XML report, if set
+	JUnitReport string
+	// SLAFile is a yaml file with the objectives of the operations of the scenario, which fail the
+	// run when they aren't met, see run.SLA
+	SLAFile string
+	// Progress is the style of the progress of the run, see ui.ProgressStyle
+	Progress      string
+	Verbose       bool
+	IgnoreDropped bool
 }
 
 func (o *RunOptions) LogToFile() bool {
@@ -0,0 +1,32 @@
+package orchestrate
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/log"
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+)
+
+var _ ui.Outputable = (*progressMessage)(nil)
+
+type progressMessage struct {
+	duration   time.Duration
+	processes  int
+	successful uint64
+	failed     uint64
+	dropped    uint64
+}
+
+func (m progressMessage) Print(printer *ui.Printer) {
+	printer.Println(fmt.Sprintf("[%5s]  %d processes  ✔ %5d  ⦸ %5d  ✘ %5d",
+		m.duration.Round(time.Second), m.processes, m.successful, m.dropped, m.failed))
+}
+
+func (m progressMessage) Log(logger *slog.Logger) {
+	logger.Info("progress",
+		slog.Int("processes", m.processes),
+		log.IterationStatsGroup(0, m.successful, m.failed, m.dropped, m.duration),
+	)
+}
@@ -0,0 +1,320 @@
+package orchestrate
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/form3tech-oss/f1/v2/internal/envsettings"
+	"github.com/form3tech-oss/f1/v2/internal/run"
+	"github.com/form3tech-oss/f1/v2/internal/run/views"
+	"github.com/form3tech-oss/f1/v2/internal/triggerflags"
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+)
+
+const (
+	flagProcesses = "processes"
+	flagKeepLogs  = "keep-logs"
+
+	progressInterval = time.Second
+	// stopTimeout is how long child processes are given to complete after being interrupted
+	stopTimeout = 30 * time.Second
+)
+
+func Cmd(settings envsettings.Settings, output *ui.Output) *cobra.Command {
+	orchestrateCmd := &cobra.Command{
+		Use:   "orchestrate <trigger> <scenario> [flags]",
+		Short: "Runs a test scenario in multiple local f1 processes and combines their results",
+		Long: `Runs a test scenario in multiple local f1 processes and combines their results.
+
+All arguments after the trigger are passed to each process as they would be to "f1 run", so
+rates and concurrency apply to every process individually. For example, to run 4 processes
+each starting 250 iterations per second:
+
+  f1 orchestrate --processes 4 constant myScenario --rate 250/s --max-duration 1m`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: orchestrateCmdExecute(settings, output),
+	}
+
+	orchestrateCmd.Flags().IntP(flagProcesses, "n", 2, "number of f1 processes to run")
+	orchestrateCmd.Flags().Bool(flagKeepLogs, false,
+		"keep the logs and reports of the processes in a temporary directory once the run completes")
+	// flags after the trigger belong to the child processes
+	orchestrateCmd.Flags().SetInterspersed(false)
+
+	return orchestrateCmd
+}
+
+func orchestrateCmdExecute(
+	settings envsettings.Settings,
+	output *ui.Output,
+) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		processes, err := cmd.Flags().GetInt(flagProcesses)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		if processes < 1 {
+			return fmt.Errorf("processes %d can't be less than 1", processes)
+		}
+
+		executable, err := os.Executable()
+		if err != nil {
+			return fmt.Errorf("finding f1 executable: %w", err)
+		}
+
+		keepLogs, err := cmd.Flags().GetBool(flagKeepLogs)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+
+		dir, err := os.MkdirTemp("", "f1-orchestrate-")
+		if err != nil {
+			return fmt.Errorf("creating orchestration directory: %w", err)
+		}
+		var logFilePath string
+		if keepLogs {
+			logFilePath = dir
+		} else {
+			defer os.RemoveAll(dir)
+		}
+
+		o := &orchestration{
+			executable: executable,
+			args:       args,
+			dir:        dir,
+			keepLogs:   keepLogs,
+			settings:   settings,
+			output:     output,
+			progress:   make([]processProgress, processes),
+		}
+
+		report, err := o.run(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		output.Display(views.New().Result(views.ResultData{
+			Error:                        reportError(report),
+			LogFilePath:                  logFilePath,
+			SuccessfulIterationDurations: report.SuccessfulIterationDurations.Snapshot(),
+			FailedIterationDurations:     report.FailedIterationDurations.Snapshot(),
+			IterationsStarted:            report.IterationsStarted,
+			Duration:                     report.Duration,
+			SetupDuration:                report.SetupDuration,
+			TeardownDuration:             report.TeardownDuration,
+			SuccessfulIterationCount:     report.SuccessfulIterationDurations.Count,
+			Iterations:                   report.IterationsStarted + report.DroppedIterationCount,
+			FailedIterationCount:         report.FailedIterationDurations.Count,
+			DroppedIterationCount:        report.DroppedIterationCount,
+			Failed:                       report.Failed,
+			BytesSent:                    report.BytesSent,
+			BytesReceived:                report.BytesReceived,
+		}))
+
+		if report.Failed {
+			return errors.New("load test failed - see log for details")
+		}
+
+		return nil
+	}
+}
+
+func reportError(report run.Report) error {
+	if report.Error == "" {
+		return nil
+	}
+
+	return errors.New(report.Error)
+}
+
+type processProgress struct {
+	successful uint64
+	failed     uint64
+	dropped    uint64
+}
+
+type orchestration struct {
+	output     *ui.Output
+	executable string
+	dir        string
+	args       []string
+	progress   []processProgress
+	settings   envsettings.Settings
+	mu         sync.Mutex
+	// keepLogs keeps dir once the run completes, see --keep-logs
+	keepLogs bool
+}
+
+func (o *orchestration) run(ctx context.Context) (run.Report, error) {
+	start := time.Now()
+	message := fmt.Sprintf("Starting %d f1 processes", len(o.progress))
+	if o.keepLogs {
+		message += ", saving logs and reports to " + o.dir
+	}
+	o.output.Display(ui.InfoMessage{Message: message})
+
+	progressCtx, progressCancel := context.WithCancel(ctx)
+	defer progressCancel()
+	go o.displayProgress(progressCtx, start)
+
+	errs := make([]error, len(o.progress))
+	wg := sync.WaitGroup{}
+	for i := range o.progress {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = o.runProcess(ctx, i)
+		}()
+	}
+	wg.Wait()
+
+	reports := make([]run.Report, 0, len(o.progress))
+	for i := range o.progress {
+		report, err := run.ReadReport(o.reportPath(i))
+		if err != nil {
+			return run.Report{}, fmt.Errorf("process %d did not complete: %w", i, errors.Join(errs[i], err))
+		}
+		reports = append(reports, report)
+	}
+
+	return run.CombineReports(reports...), nil
+}
+
+func (o *orchestration) reportPath(process int) string {
+	return filepath.Join(o.dir, fmt.Sprintf("process-%d.json", process))
+}
+
+func (o *orchestration) runProcess(ctx context.Context, process int) error {
+	args := []string{"run"}
+	// the processes read the settings file of the orchestration, which may have been given with --config
+	if o.settings.File != "" {
+		args = append(args, "--config", o.settings.File)
+	}
+	args = append(args, o.args...)
+	args = append(args, "--"+triggerflags.FlagReportFile, o.reportPath(process))
+
+	cmd := exec.CommandContext(ctx, o.executable, args...)
+	cmd.Env = append(os.Environ(), o.processEnv(process)...)
+	cmd.Stderr = o.output.Printer.ErrWriter
+	// interrupt children so that they can complete active iterations and teardown
+	cmd.Cancel = func() error {
+		return cmd.Process.Signal(os.Interrupt)
+	}
+	cmd.WaitDelay = stopTimeout
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("creating stdout pipe: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("starting process %d: %w", process, err)
+	}
+
+	o.readEvents(process, stdout)
+
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("waiting for process %d: %w", process, err)
+	}
+
+	return nil
+}
+
+func (o *orchestration) processEnv(process int) []string {
+	// distinguish the metrics pushed by every process
+	labelID := "process-" + strconv.Itoa(process)
+	if o.settings.Prometheus.LabelID != "" {
+		labelID = o.settings.Prometheus.LabelID + "-" + labelID
+	}
+
+	return []string{
+		envsettings.EnvLogFormat + "=json",
+		envsettings.EnvLogFilePath + "=" + filepath.Join(o.dir, fmt.Sprintf("process-%d.log", process)),
+		envsettings.EnvPrometheusLabelID + "=" + labelID,
+	}
+}
+
+// event is the subset of the json log output of a child process used by the orchestration.
+type event struct {
+	Message        string `json:"message"`
+	Level          string `json:"level"`
+	Error          string `json:"error"`
+	IterationStats struct {
+		Successful uint64 `json:"successful"`
+		Failed     uint64 `json:"failed"`
+		Dropped    uint64 `json:"dropped"`
+	} `json:"iteration_stats"`
+}
+
+func (o *orchestration) readEvents(process int, r io.Reader) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		e := event{}
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+
+		switch {
+		case e.Message == "progress":
+			o.mu.Lock()
+			o.progress[process] = processProgress{
+				successful: e.IterationStats.Successful,
+				failed:     e.IterationStats.Failed,
+				dropped:    e.IterationStats.Dropped,
+			}
+			o.mu.Unlock()
+		case e.Level == "error" && e.Error != "":
+			o.output.Display(ui.ErrorMessage{
+				Message: fmt.Sprintf("process %d: %s", process, e.Message),
+				Error:   errors.New(e.Error),
+			})
+		case e.Level == "error" || e.Level == "warning":
+			o.output.Display(ui.WarningMessage{Message: fmt.Sprintf("process %d: %s", process, e.Message)})
+		}
+	}
+}
+
+func (o *orchestration) displayProgress(ctx context.Context, start time.Time) {
+	ticker := time.NewTicker(progressInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			o.output.Display(o.progressMessage(time.Since(start)))
+		}
+	}
+}
+
+func (o *orchestration) progressMessage(duration time.Duration) progressMessage {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	message := progressMessage{
+		duration:  duration,
+		processes: len(o.progress),
+	}
+	for _, p := range o.progress {
+		message.successful += p.successful
+		message.failed += p.failed
+		message.dropped += p.dropped
+	}
+
+	return message
+}
@@ -0,0 +1,156 @@
+package orchestrate_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/envsettings"
+	"github.com/form3tech-oss/f1/v2/internal/log"
+	"github.com/form3tech-oss/f1/v2/internal/orchestrate"
+	"github.com/form3tech-oss/f1/v2/internal/run"
+	"github.com/form3tech-oss/f1/v2/internal/triggerflags"
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+)
+
+// fakeProcessEnv makes the test binary act as the f1 processes of the orchestration, which run the
+// executable of the orchestration, failing without a report if it is set to fakeProcessFails.
+const (
+	fakeProcessEnv   = "F1_ORCHESTRATE_FAKE_PROCESS"
+	fakeProcessFails = "fail"
+)
+
+func TestMain(m *testing.M) {
+	switch os.Getenv(fakeProcessEnv) {
+	case "":
+	case fakeProcessFails:
+		fmt.Fprintln(os.Stderr, "scenario not found")
+		os.Exit(1)
+	default:
+		os.Exit(fakeProcess(os.Args[1:]))
+	}
+
+	os.Exit(m.Run())
+}
+
+// fakeProcess reports a run of 10 iterations, one of which failed, to the report file of its args.
+func fakeProcess(args []string) int {
+	i := slices.Index(args, "--"+triggerflags.FlagReportFile)
+	if i < 0 || i+1 >= len(args) {
+		fmt.Fprintln(os.Stderr, "missing report file")
+		return 1
+	}
+
+	data, err := json.Marshal(run.Report{
+		Scenario:                     "scenario",
+		Duration:                     time.Second,
+		IterationsStarted:            10,
+		SuccessfulIterationDurations: run.DurationsReport{Count: 9},
+		FailedIterationDurations:     run.DurationsReport{Count: 1},
+	})
+	if err == nil {
+		err = os.WriteFile(args[i+1], data, 0o600)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	fmt.Println(`{"message":"progress","iteration_stats":{"successful":9,"failed":1}}`)
+	return 0
+}
+
+// syncBuffer is written to by the output of the orchestration and by the stderr of its processes.
+type syncBuffer struct {
+	buffer bytes.Buffer
+	mu     sync.Mutex
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buffer.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buffer.String()
+}
+
+func executeOrchestration(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+
+	var out syncBuffer
+	output := ui.NewOutput(log.NewTestLogger(&out), ui.NewPrinter(&out, &out), false, false)
+	cmd := orchestrate.Cmd(envsettings.Settings{}, output)
+	cmd.SetArgs(args)
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+func TestOrchestrationCombinesTheReportsOfItsProcesses(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+	t.Setenv(fakeProcessEnv, "1")
+
+	output, err := executeOrchestration(t, "--processes", "3", "constant", "scenario")
+
+	require.NoError(t, err)
+	assert.Contains(t, output, "Starting 3 f1 processes")
+	assert.Contains(t, output, "iteration_stats.started=30 iteration_stats.successful=27 iteration_stats.failed=3")
+
+	entries, err := os.ReadDir(tmp)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "the orchestration directory was not removed")
+}
+
+func TestOrchestrationKeepsTheLogsOfItsProcesses(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+	t.Setenv(fakeProcessEnv, "1")
+
+	output, err := executeOrchestration(t, "--processes", "2", "--keep-logs", "constant", "scenario")
+
+	require.NoError(t, err)
+	entries, err := os.ReadDir(tmp)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Contains(t, output, "saving logs and reports to "+tmp)
+
+	reports, err := os.ReadDir(filepath.Join(tmp, entries[0].Name()))
+	require.NoError(t, err)
+	names := make([]string, 0, len(reports))
+	for _, report := range reports {
+		names = append(names, report.Name())
+	}
+	assert.Equal(t, []string{"process-0.json", "process-1.json"}, names)
+}
+
+func TestOrchestrationFailsIfAProcessDoesNotReport(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+	t.Setenv(fakeProcessEnv, fakeProcessFails)
+
+	output, err := executeOrchestration(t, "--processes", "1", "constant", "scenario")
+
+	require.ErrorContains(t, err, "process 0 did not complete")
+	assert.Contains(t, output, "scenario not found")
+	entries, err := os.ReadDir(tmp)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "the orchestration directory was not removed")
+}
@@ -0,0 +1,44 @@
+// Package platform integrates f1 with the environment it runs in, the signals of the operating
+// system and the files logs are written to, so that the run engine can be compiled for constrained
+// targets, such as WebAssembly, where they are not available. The environment of each target is
+// selected by build tags.
+package platform
+
+import (
+	"errors"
+	"os"
+	"os/signal"
+	"runtime"
+)
+
+// ErrUnsupported is returned when a feature of the environment is not available on the target f1
+// is compiled for.
+var ErrUnsupported = errors.New("not supported on " + runtime.GOOS)
+
+// Environment is the integration of f1 with the environment it runs in.
+type Environment struct {
+	// InterruptSignals ask the process to stop the run gracefully, none where it can't be signalled
+	InterruptSignals []os.Signal
+	// VerboseToggleSignals toggle verbose logging while a run is in progress
+	VerboseToggleSignals []os.Signal
+	// LogFiles is true where the logs of scenarios can be written to files
+	LogFiles bool
+}
+
+// Current returns the environment of the target f1 is compiled for.
+func Current() Environment {
+	return current()
+}
+
+// Notify relays the signals to c until the returned function is called. Unlike signal.Notify, no
+// signal is relayed when signals is empty, as on targets without signals.
+func Notify(c chan<- os.Signal, signals []os.Signal) func() {
+	if len(signals) == 0 {
+		return func() {}
+	}
+
+	signal.Notify(c, signals...)
+	return func() {
+		signal.Stop(c)
+	}
+}
@@ -0,0 +1,66 @@
+package platform_test
+
+import (
+	"os"
+	"os/signal"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/platform"
+)
+
+func TestNotifyRelaysTheSignalsOfTheEnvironment(t *testing.T) {
+	t.Parallel()
+
+	toggleSignals := platform.Current().VerboseToggleSignals
+	if len(toggleSignals) == 0 {
+		t.Skip("no verbose toggle signals on this target")
+	}
+
+	signals := make(chan os.Signal, 1)
+	stop := platform.Notify(signals, toggleSignals)
+	defer stop()
+
+	process, err := os.FindProcess(os.Getpid())
+	require.NoError(t, err)
+	require.NoError(t, process.Signal(toggleSignals[0]))
+
+	select {
+	case received := <-signals:
+		assert.Equal(t, toggleSignals[0], received)
+	case <-time.After(time.Second):
+		t.Fatal("signal not relayed")
+	}
+}
+
+func TestNotifyWithoutSignalsRelaysNothing(t *testing.T) {
+	t.Parallel()
+
+	toggleSignals := platform.Current().VerboseToggleSignals
+	if len(toggleSignals) == 0 {
+		t.Skip("no verbose toggle signals on this target")
+	}
+
+	// the signal is relayed to another channel, as it would otherwise stop the process
+	relayed := make(chan os.Signal, 1)
+	signal.Notify(relayed, toggleSignals[0])
+	defer signal.Stop(relayed)
+
+	signals := make(chan os.Signal, 1)
+	stop := platform.Notify(signals, nil)
+	defer stop()
+
+	process, err := os.FindProcess(os.Getpid())
+	require.NoError(t, err)
+	require.NoError(t, process.Signal(toggleSignals[0]))
+
+	<-relayed
+	select {
+	case received := <-signals:
+		t.Fatalf("unexpected signal %s", received)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
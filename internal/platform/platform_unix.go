@@ -0,0 +1,16 @@
+//go:build !windows && !js
+
+package platform
+
+import (
+	"os"
+	"syscall"
+)
+
+func current() Environment {
+	return Environment{
+		InterruptSignals:     []os.Signal{os.Interrupt, syscall.SIGTERM},
+		VerboseToggleSignals: []os.Signal{syscall.SIGUSR1},
+		LogFiles:             true,
+	}
+}
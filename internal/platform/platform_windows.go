@@ -0,0 +1,17 @@
+//go:build windows
+
+package platform
+
+import (
+	"os"
+	"syscall"
+)
+
+// current has no verbose toggle signals as there is no user defined signal on windows, where
+// verbose logging is only toggled by the control API.
+func current() Environment {
+	return Environment{
+		InterruptSignals: []os.Signal{os.Interrupt, syscall.SIGTERM},
+		LogFiles:         true,
+	}
+}
@@ -1,8 +1,11 @@
 package progress
 
 import (
+	"sync"
 	"sync/atomic"
 	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/hdr"
 )
 
 type IterationDurationsSnapshot struct {
@@ -10,6 +13,12 @@ type IterationDurationsSnapshot struct {
 	Count   uint64
 	Min     time.Duration
 	Max     time.Duration
+	// P50, P95 and P99 are estimated quantiles of the durations
+	P50 time.Duration
+	P95 time.Duration
+	P99 time.Duration
+	// Histogram counts the durations, so that quantiles can be estimated over several snapshots
+	Histogram Histogram
 }
 
 func (s IterationDurationsSnapshot) String() string {
@@ -24,18 +33,22 @@ func (s IterationDurationsSnapshot) String() string {
 //	This leads to various inconsistencies when reading values, however
 //	for the use case of progress reporting we prefer performance over perfect correctness.
 type IterationDurations struct {
-	// int64 can hold ~290 years of total execution durations in nanoseconds,
-	// which should be enough for almost all realistic use cases.
+	// int64 holds ~290 years of total execution durations in nanoseconds, which is enough for
+	// the durations of a single progress period. Totals over the lifetime of a run are kept in
+	// durationTotals, which don't overflow.
 	sum   atomic.Int64
-	count atomic.Int64
+	count atomic.Uint64
 
 	min atomic.Int64
 	max atomic.Int64
+
+	histogram durationHistogram
 }
 
 func (i *IterationDurations) Add(nanoseconds int64) {
 	i.sum.Add(nanoseconds)
 	i.count.Add(1)
+	i.histogram.add(nanoseconds)
 
 	if nanoseconds > i.max.Load() {
 		i.max.Store(nanoseconds)
@@ -48,51 +61,83 @@ func (i *IterationDurations) Add(nanoseconds int64) {
 }
 
 func (i *IterationDurations) Snapshot() IterationDurationsSnapshot {
-	average, count := i.average()
-
-	return IterationDurationsSnapshot{
-		Average: time.Duration(average),
-		Count:   count,
-		Min:     time.Duration(i.min.Load()),
-		Max:     time.Duration(i.max.Load()),
+	totals := durationTotals{
+		sum:   float64(i.sum.Load()),
+		count: i.count.Load(),
+		min:   i.min.Load(),
+		max:   i.max.Load(),
 	}
+	totals.histogram = i.histogram.load()
+
+	return totals.snapshot()
 }
 
-func (i *IterationDurations) average() (int64, uint64) {
-	count := i.count.Load()
-	if count == 0 {
-		return 0, 0
+// collect returns the totals of the durations added so far and resets them. Every counter is
+// swapped with zero, so that durations added concurrently are counted in the next collection
+// rather than lost.
+func (i *IterationDurations) collect() *durationTotals {
+	totals := &durationTotals{
+		sum:   float64(i.sum.Swap(0)),
+		count: i.count.Swap(0),
+		min:   i.min.Swap(0),
+		max:   i.max.Swap(0),
 	}
-	sum := i.sum.Load()
+	totals.histogram = i.histogram.swap()
+
+	return totals
+}
 
-	return sum / count, uint64(count)
+// durationTotals are the totals of durations collected from IterationDurations. The sum is a
+// float64, which can't overflow on multi-billion iteration runs and whose precision loss is
+// negligible for averages.
+type durationTotals struct {
+	histogram hdr.Counts
+	sum       float64
+	count     uint64
+	min       int64
+	max       int64
 }
 
-func (i *IterationDurations) Update(other *IterationDurations) {
-	i.sum.Add(other.sum.Load())
-	i.count.Add(other.count.Load())
+func (t *durationTotals) add(other *durationTotals) {
+	t.sum += other.sum
+	t.count += other.count
+	t.histogram = t.histogram.Merge(other.histogram)
+
+	if t.min == 0 || (other.min > 0 && other.min < t.min) {
+		t.min = other.min
+	}
+	t.max = max(t.max, other.max)
+}
 
-	currentMin := other.min.Load()
-	if i.min.Load() == 0 || (i.min.Load() > currentMin && currentMin > 0) {
-		i.min.Store(currentMin)
+func (t *durationTotals) snapshot() IterationDurationsSnapshot {
+	if t.count == 0 {
+		return IterationDurationsSnapshot{}
 	}
 
-	currentMax := other.max.Load()
-	if i.max.Load() < currentMax {
-		i.max.Store(currentMax)
+	minDuration := time.Duration(t.min)
+	maxDuration := time.Duration(t.max)
+
+	return IterationDurationsSnapshot{
+		Average:   time.Duration(t.sum / float64(t.count)),
+		Count:     t.count,
+		Min:       minDuration,
+		Max:       maxDuration,
+		P50:       t.quantile(0.5, minDuration, maxDuration),
+		P95:       t.quantile(0.95, minDuration, maxDuration),
+		P99:       t.quantile(0.99, minDuration, maxDuration),
+		Histogram: Histogram(t.histogram),
 	}
 }
 
-func (i *IterationDurations) Reset() {
-	i.sum.Store(0)
-	i.count.Store(0)
-	i.max.Store(0)
-	i.min.Store(0)
+// quantile estimates a quantile from the histogram, bounded by the recorded min and max durations.
+func (t *durationTotals) quantile(q float64, minDuration, maxDuration time.Duration) time.Duration {
+	return min(max(histogramLayout.Quantile(t.histogram, q), minDuration), maxDuration)
 }
 
 type DurationStats struct {
 	running  IterationDurations
-	lifetime IterationDurations
+	lifetime durationTotals
+	mu       sync.Mutex
 }
 
 func (d *DurationStats) Record(nanoseconds int64) {
@@ -100,9 +145,20 @@ func (d *DurationStats) Record(nanoseconds int64) {
 }
 
 func (d *DurationStats) CollectLifetime() (IterationDurationsSnapshot, IterationDurationsSnapshot) {
-	running := d.running.Snapshot()
-	d.lifetime.Update(&d.running)
-	d.running.Reset()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	running := d.running.collect()
+	d.lifetime.add(running)
+
+	return running.snapshot(), d.lifetime.snapshot()
+}
+
+// totals returns the number and the total duration of the durations recorded so far.
+func (d *DurationStats) totals() (uint64, time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
 
-	return running, d.lifetime.Snapshot()
+	return d.lifetime.count + d.running.count.Load(),
+		time.Duration(d.lifetime.sum + float64(d.running.sum.Load()))
 }
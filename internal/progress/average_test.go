@@ -0,0 +1,202 @@
+package progress_test
+
+import (
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/form3tech-oss/f1/v2/internal/metrics"
+	"github.com/form3tech-oss/f1/v2/internal/progress"
+)
+
+func TestIterationDurationsQuantiles(t *testing.T) {
+	t.Parallel()
+
+	durations := progress.IterationDurations{}
+	for i := 1; i <= 100; i++ {
+		durations.Add((time.Duration(i) * time.Millisecond).Nanoseconds())
+	}
+
+	snapshot := durations.Snapshot()
+
+	assert.InEpsilon(t, 50*time.Millisecond, snapshot.P50, 0.1)
+	assert.InEpsilon(t, 95*time.Millisecond, snapshot.P95, 0.1)
+	assert.InEpsilon(t, 99*time.Millisecond, snapshot.P99, 0.1)
+	assert.LessOrEqual(t, snapshot.P99, snapshot.Max)
+}
+
+func TestIterationDurationsQuantilesAreCollectedOverLifetime(t *testing.T) {
+	t.Parallel()
+
+	stats := progress.Stats{}
+	for i := 1; i <= 100; i++ {
+		stats.Record(metrics.SucessResult, (time.Duration(i) * time.Millisecond).Nanoseconds())
+		if i == 50 {
+			stats.Snapshot(time.Second)
+		}
+	}
+
+	snapshot := stats.Total()
+
+	assert.InEpsilon(t, 95*time.Millisecond, snapshot.SuccessfulIterationDurations.P95, 0.1)
+}
+
+func TestLifetimeDurationsDoNotOverflow(t *testing.T) {
+	t.Parallel()
+
+	// four iterations of ~73 years each, which overflow an int64 sum of nanoseconds
+	duration := int64(math.MaxInt64 / 4)
+
+	stats := progress.Stats{}
+	for range 4 {
+		stats.Record(metrics.SucessResult, duration)
+		stats.Snapshot(time.Second)
+	}
+	stats.Record(metrics.SucessResult, duration)
+
+	snapshot := stats.Total()
+
+	assert.Equal(t, uint64(5), snapshot.SuccessfulIterationDurations.Count)
+	assert.InEpsilon(t, duration, int64(snapshot.SuccessfulIterationDurations.Average), 1e-9)
+}
+
+func TestCollectingDurationsDoesNotLoseConcurrentDurations(t *testing.T) {
+	t.Parallel()
+
+	const (
+		goroutines    = 8
+		perGoroutine  = 10_000
+		totalExpected = goroutines * perGoroutine
+	)
+
+	stats := progress.Stats{}
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range perGoroutine {
+				stats.Record(metrics.SucessResult, time.Millisecond.Nanoseconds())
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	for collecting := true; collecting; {
+		select {
+		case <-done:
+			collecting = false
+		default:
+			stats.Snapshot(time.Millisecond)
+		}
+	}
+
+	snapshot := stats.Total()
+	assert.Equal(t, uint64(totalExpected), snapshot.SuccessfulIterationDurations.Count)
+	assert.Equal(t, time.Millisecond, snapshot.SuccessfulIterationDurations.Average)
+}
+
+func TestCompletedIterationsAreCountedAcrossSnapshots(t *testing.T) {
+	t.Parallel()
+
+	stats := progress.Stats{}
+	stats.Record(metrics.SucessResult, (10 * time.Millisecond).Nanoseconds())
+	stats.Record(metrics.FailedResult, (30 * time.Millisecond).Nanoseconds())
+	stats.Snapshot(time.Second)
+	stats.Record(metrics.SucessResult, (20 * time.Millisecond).Nanoseconds())
+	stats.Record(metrics.DroppedResult, 0)
+	stats.RecordTriggered(time.Now(), 5)
+
+	completed, duration := stats.Completed()
+	assert.Equal(t, uint64(3), completed)
+	assert.Equal(t, 60*time.Millisecond, duration)
+	assert.Equal(t, uint64(5), stats.Triggered())
+	assert.Equal(t, uint64(1), stats.Dropped())
+
+	// reading the completed iterations doesn't collect the durations of the progress period
+	assert.Equal(t, uint64(1), stats.Snapshot(time.Second).SuccessfulIterationDurationsForPeriod.Count)
+}
+
+func TestFailedIterationsRate(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name     string
+		snapshot progress.Snapshot
+		expected float64
+	}{
+		{
+			name:     "no iterations",
+			snapshot: progress.Snapshot{},
+			expected: 0,
+		},
+		{
+			name: "fractional rate",
+			snapshot: progress.Snapshot{
+				SuccessfulIterationDurations: progress.IterationDurationsSnapshot{Count: 941},
+				FailedIterationDurations:     progress.IterationDurationsSnapshot{Count: 59},
+			},
+			expected: 5.9,
+		},
+		{
+			name: "billions of iterations",
+			snapshot: progress.Snapshot{
+				SuccessfulIterationDurations: progress.IterationDurationsSnapshot{Count: math.MaxUint64 / 2},
+				FailedIterationDurations:     progress.IterationDurationsSnapshot{Count: math.MaxUint64 / 2},
+			},
+			expected: 50,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.InDelta(t, test.expected, test.snapshot.FailedIterationsRate(), 1e-9)
+		})
+	}
+}
+
+func TestHistogramMerge(t *testing.T) {
+	t.Parallel()
+
+	first := &progress.Stats{}
+	second := &progress.Stats{}
+	for range 90 {
+		first.Record(metrics.SucessResult, time.Millisecond.Nanoseconds())
+	}
+	for range 10 {
+		second.Record(metrics.SucessResult, time.Second.Nanoseconds())
+	}
+
+	merged := first.Total().SuccessfulIterationDurations.Histogram.
+		Merge(second.Total().SuccessfulIterationDurations.Histogram)
+
+	assert.InEpsilon(t, time.Millisecond, merged.Quantile(0.9), 0.1)
+	assert.InEpsilon(t, time.Second, merged.Quantile(0.91), 0.1)
+	assert.Zero(t, progress.Histogram{}.Quantile(0.5))
+}
+
+func TestHistogramSub(t *testing.T) {
+	t.Parallel()
+
+	stats := &progress.Stats{}
+	for range 10 {
+		stats.Record(metrics.SucessResult, time.Second.Nanoseconds())
+	}
+	before := stats.Total().SuccessfulIterationDurations.Histogram
+	for range 10 {
+		stats.Record(metrics.SucessResult, time.Millisecond.Nanoseconds())
+	}
+
+	interval := stats.Total().SuccessfulIterationDurations.Histogram.Sub(before)
+
+	assert.InEpsilon(t, time.Millisecond, interval.Quantile(0.99), 0.1)
+	assert.Zero(t, before.Sub(before).Quantile(0.5))
+}
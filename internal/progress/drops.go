@@ -0,0 +1,62 @@
+package progress
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+	"time"
+)
+
+// DroppedIterations counts the iterations dropped, because all the workers were busy, which were
+// planned to start in a second of the run.
+type DroppedIterations struct {
+	// Offset is the start of the second of the run the iterations were planned to start in
+	Offset time.Duration
+	Count  uint64
+}
+
+// dropTimeline counts the dropped iterations by the second of the run they were planned to start
+// in, so that its size depends on the duration of the run rather than on the number of drops.
+type dropTimeline struct {
+	mu     sync.Mutex
+	start  time.Time
+	counts map[time.Duration]uint64
+}
+
+func (t *dropTimeline) setStart(start time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.start = start
+}
+
+func (t *dropTimeline) record(planned time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.counts == nil {
+		t.counts = map[time.Duration]uint64{}
+	}
+	// iterations planned before the start of the run are counted in its first second
+	offset := max(planned.Sub(t.start), 0).Truncate(time.Second)
+	t.counts[offset]++
+}
+
+func (t *dropTimeline) snapshot() []DroppedIterations {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.counts) == 0 {
+		return nil
+	}
+
+	drops := make([]DroppedIterations, 0, len(t.counts))
+	for offset, count := range t.counts {
+		drops = append(drops, DroppedIterations{Offset: offset, Count: count})
+	}
+	slices.SortFunc(drops, func(a, b DroppedIterations) int {
+		return cmp.Compare(a.Offset, b.Offset)
+	})
+
+	return drops
+}
@@ -0,0 +1,85 @@
+package progress_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/form3tech-oss/f1/v2/internal/progress"
+)
+
+func TestDropsAreCountedBySecondOfTheRun(t *testing.T) {
+	t.Parallel()
+
+	start := time.Now()
+	stats := &progress.Stats{}
+	stats.Start(start)
+
+	// an iteration planned before the run started is counted in its first second
+	stats.RecordDropped(start.Add(-time.Millisecond))
+	stats.RecordDropped(start.Add(2500 * time.Millisecond))
+	stats.RecordDropped(start.Add(200 * time.Millisecond))
+	stats.RecordDropped(start.Add(2999 * time.Millisecond))
+
+	snapshot := stats.Total()
+
+	assert.Equal(t, uint64(4), snapshot.DroppedIterationCount)
+	assert.Equal(t, []progress.DroppedIterations{
+		{Offset: 0, Count: 2},
+		{Offset: 2 * time.Second, Count: 2},
+	}, snapshot.Drops)
+	assert.Equal(t, snapshot.Drops, stats.Snapshot(time.Second).Drops)
+}
+
+func TestNoDrops(t *testing.T) {
+	t.Parallel()
+
+	stats := &progress.Stats{}
+	stats.Start(time.Now())
+
+	assert.Nil(t, stats.Total().Drops)
+}
+
+func TestTargetAndAchievedRatesAreCountedBySecondOfTheRun(t *testing.T) {
+	t.Parallel()
+
+	start := time.Now()
+	stats := &progress.Stats{}
+	stats.Start(start)
+
+	stats.RecordTriggered(start.Add(-time.Millisecond), 3)
+	stats.RecordStarted(start)
+	stats.RecordStarted(start.Add(900 * time.Millisecond))
+	stats.RecordTriggered(start.Add(1500*time.Millisecond), 2)
+	stats.RecordTriggered(start.Add(1600*time.Millisecond), 0)
+	stats.RecordStarted(start.Add(1999 * time.Millisecond))
+
+	assert.Equal(t, []progress.RateSample{
+		{Offset: 0, Target: 3, Achieved: 2},
+		{Offset: time.Second, Target: 2, Achieved: 1},
+	}, stats.Total().Rates)
+}
+
+func TestRatesOfTheCurrentSecondAreUpdatedBySnapshots(t *testing.T) {
+	t.Parallel()
+
+	start := time.Now().Add(-10 * time.Second)
+	stats := &progress.Stats{}
+	stats.Start(start)
+
+	stats.RecordTriggered(start.Add(2*time.Second), 1)
+	stats.RecordStarted(start.Add(10 * time.Second))
+	assert.Equal(t, []progress.RateSample{
+		{Offset: 2 * time.Second, Target: 1},
+		{Offset: 10 * time.Second, Achieved: 1},
+	}, stats.Snapshot(time.Second).Rates)
+
+	stats.RecordStarted(start.Add(10 * time.Second))
+	stats.RecordTriggered(start.Add(200*time.Second), 4)
+	assert.Equal(t, []progress.RateSample{
+		{Offset: 2 * time.Second, Target: 1},
+		{Offset: 10 * time.Second, Achieved: 2},
+		{Offset: 200 * time.Second, Target: 4},
+	}, stats.Total().Rates)
+}
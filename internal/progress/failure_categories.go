@@ -0,0 +1,64 @@
+package progress
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+)
+
+// FailureCategoryDurations are the durations of the iterations which failed in a category, such as
+// timeouts or server errors.
+type FailureCategoryDurations struct {
+	Category  string
+	Durations IterationDurationsSnapshot
+}
+
+// failureCategories records the durations of failed iterations by their category, to tell fast
+// rejections apart from slow timeouts.
+type failureCategories struct {
+	categories map[string]*IterationDurations
+	mu         sync.RWMutex
+}
+
+func (c *failureCategories) record(category string, nanoseconds int64) {
+	c.mu.RLock()
+	durations, ok := c.categories[category]
+	c.mu.RUnlock()
+
+	if !ok {
+		c.mu.Lock()
+		if durations, ok = c.categories[category]; !ok {
+			if c.categories == nil {
+				c.categories = map[string]*IterationDurations{}
+			}
+			durations = &IterationDurations{}
+			c.categories[category] = durations
+		}
+		c.mu.Unlock()
+	}
+
+	durations.Add(nanoseconds)
+}
+
+// snapshot returns the categories by decreasing number of failures.
+func (c *failureCategories) snapshot() []FailureCategoryDurations {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if len(c.categories) == 0 {
+		return nil
+	}
+
+	categories := make([]FailureCategoryDurations, 0, len(c.categories))
+	for category, durations := range c.categories {
+		categories = append(categories, FailureCategoryDurations{
+			Category:  category,
+			Durations: durations.Snapshot(),
+		})
+	}
+	slices.SortFunc(categories, func(a, b FailureCategoryDurations) int {
+		return cmp.Or(cmp.Compare(b.Durations.Count, a.Durations.Count), cmp.Compare(a.Category, b.Category))
+	})
+
+	return categories
+}
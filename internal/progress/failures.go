@@ -0,0 +1,69 @@
+package progress
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// FailuresKept is the number of first and of last failed iterations kept by Stats.
+const FailuresKept = 5
+
+// Failure is a failed iteration, kept to tell when the iterations started and stopped failing.
+type Failure struct {
+	Time time.Time
+	// Iteration is the number of the iteration
+	Iteration string
+	// Error summarises why the iteration failed
+	Error string
+	// Category is the kind of failure, such as a timeout or a server error, whose durations are
+	// reported together
+	Category string
+	// Duration is how long the iteration ran for before it failed
+	Duration time.Duration
+	// Worker is the worker the iteration ran on, whose failures are counted
+	Worker string
+}
+
+// failureLog keeps the first and the last failed iterations, so that its size doesn't depend on
+// the number of failures.
+type failureLog struct {
+	first []Failure
+	// last is a ring of the failures following the first ones, whose oldest failure is at next
+	last []Failure
+	next int
+	mu   sync.Mutex
+}
+
+func (l *failureLog) record(failure Failure) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	switch {
+	case len(l.first) < FailuresKept:
+		l.first = append(l.first, failure)
+	case len(l.last) < FailuresKept:
+		l.last = append(l.last, failure)
+	default:
+		l.last[l.next] = failure
+		l.next = (l.next + 1) % FailuresKept
+	}
+}
+
+// snapshot returns the first failures, and the last failures following them, in the order they
+// failed.
+func (l *failureLog) snapshot() ([]Failure, []Failure) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.first) == 0 {
+		return nil, nil
+	}
+
+	var last []Failure
+	if len(l.last) > 0 {
+		last = slices.Concat(l.last[l.next:], l.last[:l.next])
+	}
+
+	return slices.Clone(l.first), last
+}
@@ -0,0 +1,122 @@
+package progress_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/progress"
+)
+
+func TestFirstAndLastFailuresAreKept(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name          string
+		failures      int
+		expectedFirst []string
+		expectedLast  []string
+	}{
+		{
+			name: "no failures",
+		},
+		{
+			name:          "fewer failures than kept",
+			failures:      3,
+			expectedFirst: []string{"0", "1", "2"},
+		},
+		{
+			name:          "first and some last failures",
+			failures:      7,
+			expectedFirst: []string{"0", "1", "2", "3", "4"},
+			expectedLast:  []string{"5", "6"},
+		},
+		{
+			name:          "more failures than kept",
+			failures:      23,
+			expectedFirst: []string{"0", "1", "2", "3", "4"},
+			expectedLast:  []string{"18", "19", "20", "21", "22"},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			stats := &progress.Stats{}
+			start := time.Now()
+			stats.Start(start)
+			for i := range test.failures {
+				stats.RecordFailure(progress.Failure{
+					Iteration: strconv.Itoa(i),
+					Time:      start.Add(time.Duration(i) * time.Second),
+					Error:     "failed",
+				})
+			}
+
+			total := stats.Total()
+
+			assert.Equal(t, test.expectedFirst, iterations(total.FirstFailures))
+			assert.Equal(t, test.expectedLast, iterations(total.LastFailures))
+			assert.Equal(t, total.FirstFailures, stats.Snapshot(time.Second).FirstFailures)
+			assert.Equal(t, total.LastFailures, stats.Snapshot(time.Second).LastFailures)
+			for _, failure := range total.LastFailures {
+				i, err := strconv.Atoi(failure.Iteration)
+				require.NoError(t, err)
+				assert.Equal(t, start.Add(time.Duration(i)*time.Second), failure.Time)
+			}
+		})
+	}
+}
+
+func iterations(failures []progress.Failure) []string {
+	var iterations []string
+	for _, failure := range failures {
+		iterations = append(iterations, failure.Iteration)
+	}
+	return iterations
+}
+
+func TestFailureCategoriesAreSortedByCount(t *testing.T) {
+	t.Parallel()
+
+	stats := &progress.Stats{}
+	record := func(category string, duration time.Duration, count int) {
+		for range count {
+			stats.RecordFailure(progress.Failure{Category: category, Duration: duration})
+		}
+	}
+	record("timeout", 5*time.Second, 2)
+	record("5xx", 10*time.Millisecond, 10)
+	record("", time.Second, 3)
+
+	categories := stats.Total().FailureCategories
+
+	require.Len(t, categories, 2)
+	assert.Equal(t, "5xx", categories[0].Category)
+	assert.Equal(t, uint64(10), categories[0].Durations.Count)
+	assert.Equal(t, "timeout", categories[1].Category)
+	assert.Equal(t, uint64(2), categories[1].Durations.Count)
+	assert.Equal(t, 5*time.Second, categories[1].Durations.Max)
+	assert.Equal(t, categories, stats.Snapshot(time.Second).FailureCategories)
+}
+
+func TestWorkersWithTheMostFailuresAreKept(t *testing.T) {
+	t.Parallel()
+
+	stats := &progress.Stats{}
+	for worker := range progress.WorkersWithMostFailuresKept + 2 {
+		for range worker + 1 {
+			stats.RecordFailure(progress.Failure{Worker: "trigger-" + strconv.Itoa(worker)})
+		}
+	}
+	stats.RecordFailure(progress.Failure{})
+
+	workers := stats.Total().WorkerFailures
+
+	require.Len(t, workers, progress.WorkersWithMostFailuresKept)
+	assert.Equal(t, progress.WorkerFailures{Worker: "trigger-6", Count: 7}, workers[0])
+	assert.Equal(t, progress.WorkerFailures{Worker: "trigger-2", Count: 3}, workers[4])
+	assert.Equal(t, workers, stats.Snapshot(time.Second).WorkerFailures)
+}
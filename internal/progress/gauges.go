@@ -0,0 +1,7 @@
+package progress
+
+// Gauge is the latest value of a gauge registered by the scenario, displayed in the progress of the run.
+type Gauge struct {
+	Name  string  `json:"name"`
+	Value float64 `json:"value"`
+}
@@ -0,0 +1,71 @@
+package progress
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/hdr"
+)
+
+// histogramSignificantDigits limits the relative error of estimated quantiles to about 1%, with
+// histograms small enough to be kept for every stage and operation of the run.
+const histogramSignificantDigits = 2
+
+//nolint:gochecknoglobals // precomputed constant
+var histogramLayout = hdr.NewLayout(histogramSignificantDigits)
+
+// durationHistogram counts durations in a histogram allocated by the first duration, so that
+// quantiles can be estimated without storing every duration.
+type durationHistogram struct {
+	histogram atomic.Pointer[hdr.Histogram]
+}
+
+func (h *durationHistogram) add(nanoseconds int64) {
+	histogram := h.histogram.Load()
+	if histogram == nil {
+		h.histogram.CompareAndSwap(nil, hdr.NewWithLayout(histogramLayout))
+		histogram = h.histogram.Load()
+	}
+
+	histogram.Record(time.Duration(nanoseconds))
+}
+
+func (h *durationHistogram) load() hdr.Counts {
+	histogram := h.histogram.Load()
+	if histogram == nil {
+		return nil
+	}
+
+	return histogram.Counts()
+}
+
+// swap returns the counts of the histogram, resetting the histogram.
+func (h *durationHistogram) swap() hdr.Counts {
+	histogram := h.histogram.Load()
+	if histogram == nil {
+		return nil
+	}
+
+	return histogram.Swap()
+}
+
+// Histogram is a sparse copy of the counts of a duration histogram, keyed by bucket index. It can be
+// serialized and merged with the histograms of other runs, to estimate the quantiles of the durations
+// of all the runs rather than combining their quantiles, which can't be done exactly.
+type Histogram map[int]uint64
+
+// Merge returns the counts of both histograms.
+func (h Histogram) Merge(other Histogram) Histogram {
+	return Histogram(hdr.Counts(h).Merge(hdr.Counts(other)))
+}
+
+// Sub returns the counts of h which aren't counted by other, such as the durations counted
+// between an earlier snapshot other and a later snapshot h.
+func (h Histogram) Sub(other Histogram) Histogram {
+	return Histogram(hdr.Counts(h).Sub(hdr.Counts(other)))
+}
+
+// Quantile estimates the q-quantile of the counted durations, see IterationDurationsSnapshot.
+func (h Histogram) Quantile(q float64) time.Duration {
+	return histogramLayout.Quantile(hdr.Counts(h), q)
+}
@@ -0,0 +1,70 @@
+package progress
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+)
+
+// OperationDurations are the durations of an operation of the iterations, timed with the name of
+// the operation by testing.T.Time, as the operations of a Mix or of Operations are.
+type OperationDurations struct {
+	Operation                    string
+	SuccessfulIterationDurations IterationDurationsSnapshot
+	FailedIterationDurations     IterationDurationsSnapshot
+}
+
+// operationTimings records the durations of the operations of iterations by their name, so that
+// operations can be evaluated individually.
+type operationTimings struct {
+	operations map[string]*stageIterations
+	mu         sync.RWMutex
+}
+
+func (o *operationTimings) record(operation string, successful bool, nanoseconds int64) {
+	o.mu.RLock()
+	iterations, ok := o.operations[operation]
+	o.mu.RUnlock()
+
+	if !ok {
+		o.mu.Lock()
+		if iterations, ok = o.operations[operation]; !ok {
+			if o.operations == nil {
+				o.operations = map[string]*stageIterations{}
+			}
+			iterations = &stageIterations{}
+			o.operations[operation] = iterations
+		}
+		o.mu.Unlock()
+	}
+
+	if successful {
+		iterations.successful.Add(nanoseconds)
+	} else {
+		iterations.failed.Add(nanoseconds)
+	}
+}
+
+// snapshot returns the operations by name.
+func (o *operationTimings) snapshot() []OperationDurations {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	if len(o.operations) == 0 {
+		return nil
+	}
+
+	operations := make([]OperationDurations, 0, len(o.operations))
+	for operation, iterations := range o.operations {
+		operations = append(operations, OperationDurations{
+			Operation:                    operation,
+			SuccessfulIterationDurations: iterations.successful.Snapshot(),
+			FailedIterationDurations:     iterations.failed.Snapshot(),
+		})
+	}
+	slices.SortFunc(operations, func(a, b OperationDurations) int {
+		return cmp.Compare(a.Operation, b.Operation)
+	})
+
+	return operations
+}
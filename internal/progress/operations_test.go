@@ -0,0 +1,32 @@
+package progress_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/metrics"
+	"github.com/form3tech-oss/f1/v2/internal/progress"
+)
+
+func TestOperationsAreRecordedByName(t *testing.T) {
+	t.Parallel()
+
+	stats := &progress.Stats{}
+	stats.RecordOperation("fetch", metrics.SucessResult, int64(time.Millisecond))
+	stats.RecordOperation("create", metrics.SucessResult, int64(2*time.Millisecond))
+	stats.RecordOperation("create", metrics.FailedResult, int64(5*time.Millisecond))
+
+	operations := stats.Total().Operations
+
+	require.Len(t, operations, 2)
+	assert.Equal(t, "create", operations[0].Operation)
+	assert.Equal(t, uint64(1), operations[0].SuccessfulIterationDurations.Count)
+	assert.Equal(t, uint64(1), operations[0].FailedIterationDurations.Count)
+	assert.Equal(t, 5*time.Millisecond, operations[0].FailedIterationDurations.Max)
+	assert.Equal(t, "fetch", operations[1].Operation)
+	assert.Equal(t, uint64(1), operations[1].SuccessfulIterationDurations.Count)
+	assert.Equal(t, operations, stats.Snapshot(time.Second).Operations)
+}
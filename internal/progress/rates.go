@@ -0,0 +1,145 @@
+package progress
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RateSample counts the iterations planned by the trigger in a second of the run, its target rate,
+// and the iterations which actually started in that second, its achieved rate.
+type RateSample struct {
+	// Offset is the start of the second of the run
+	Offset   time.Duration
+	Target   uint64
+	Achieved uint64
+}
+
+// rateBucket counts the iterations triggered and started in a second of the run.
+type rateBucket struct {
+	target   atomic.Uint64
+	achieved atomic.Uint64
+}
+
+// rateTimeline counts the iterations triggered and started by the second of the run, so that its
+// size depends on the duration of the run rather than on the number of iterations. Iterations are
+// counted in the bucket of their second without locking, as every iteration is recorded: the buckets
+// are indexed by the second of the run, and only grow, with the seconds up to the capacity of the
+// buckets allocated ahead.
+type rateTimeline struct {
+	// start is when the run started, in unix nanoseconds, or 0 until it did
+	start   atomic.Int64
+	buckets atomic.Pointer[[]*rateBucket]
+	// grow serialises the growth of the buckets
+	grow sync.Mutex
+
+	// complete are the samples of the completedSeconds seconds which completed before the latest
+	// snapshot, which snapshots don't read again, guarded by snapshotMu
+	complete         []RateSample
+	completedSeconds int
+	snapshotMu       sync.Mutex
+}
+
+// rateSnapshotGrace is how long after a second of the run its iterations may still be recorded,
+// before snapshots consider it complete.
+const rateSnapshotGrace = time.Second
+
+func (t *rateTimeline) setStart(start time.Time) {
+	t.start.Store(start.UnixNano())
+}
+
+func (t *rateTimeline) record(at time.Time, target, achieved uint64) {
+	bucket := t.bucket(t.second(at))
+	if target > 0 {
+		bucket.target.Add(target)
+	}
+	if achieved > 0 {
+		bucket.achieved.Add(achieved)
+	}
+}
+
+// second returns the second of the run at. Iterations before the start of the run are counted in
+// its first second.
+func (t *rateTimeline) second(at time.Time) int {
+	start := t.start.Load()
+	if start == 0 {
+		return 0
+	}
+
+	return int(max(at.UnixNano()-start, 0) / int64(time.Second))
+}
+
+// bucket returns the bucket of the second of the run, growing the buckets if it is beyond them.
+func (t *rateTimeline) bucket(second int) *rateBucket {
+	if buckets := t.buckets.Load(); buckets != nil && second < len(*buckets) {
+		return (*buckets)[second]
+	}
+
+	t.grow.Lock()
+	defer t.grow.Unlock()
+
+	var buckets []*rateBucket
+	if current := t.buckets.Load(); current != nil {
+		buckets = *current
+	}
+	if second < len(buckets) {
+		return buckets[second]
+	}
+
+	// the buckets are shared with the previous slice, so that concurrent records aren't lost
+	grown := make([]*rateBucket, max(2*len(buckets), second+1, 60))
+	copy(grown, buckets)
+	for i := len(buckets); i < len(grown); i++ {
+		grown[i] = &rateBucket{}
+	}
+	t.buckets.Store(&grown)
+
+	return grown[second]
+}
+
+// snapshot returns the samples of the seconds of the run in which iterations were triggered or
+// started. The samples of the seconds which completed are only read once.
+func (t *rateTimeline) snapshot() []RateSample {
+	buckets := t.buckets.Load()
+	if buckets == nil {
+		return nil
+	}
+
+	t.snapshotMu.Lock()
+	defer t.snapshotMu.Unlock()
+
+	completed := min(t.second(time.Now().Add(-rateSnapshotGrace)), len(*buckets))
+	if t.start.Load() == 0 {
+		completed = 0
+	}
+
+	for ; t.completedSeconds < completed; t.completedSeconds++ {
+		if sample, ok := (*buckets)[t.completedSeconds].sample(t.completedSeconds); ok {
+			t.complete = append(t.complete, sample)
+		}
+	}
+
+	var samples []RateSample
+	for second := t.completedSeconds; second < len(*buckets); second++ {
+		if sample, ok := (*buckets)[second].sample(second); ok {
+			samples = append(samples, sample)
+		}
+	}
+
+	if len(t.complete) == 0 && len(samples) == 0 {
+		return nil
+	}
+
+	return append(append(make([]RateSample, 0, len(t.complete)+len(samples)), t.complete...), samples...)
+}
+
+// sample returns the sample of the bucket, and whether iterations were counted in it.
+func (b *rateBucket) sample(second int) (RateSample, bool) {
+	sample := RateSample{
+		Offset:   time.Duration(second) * time.Second,
+		Target:   b.target.Load(),
+		Achieved: b.achieved.Load(),
+	}
+
+	return sample, sample.Target > 0 || sample.Achieved > 0
+}
@@ -0,0 +1,89 @@
+package progress
+
+import (
+	"cmp"
+	"container/heap"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SlowestKept is the number of slowest iterations kept by Stats.
+const SlowestKept = 5
+
+// SlowIteration is one of the slowest iterations of the run, kept to investigate outliers.
+type SlowIteration struct {
+	Start time.Time
+	// Iteration is the number of the iteration
+	Iteration string
+	// Worker is the worker which ran the iteration
+	Worker   string
+	Duration time.Duration
+}
+
+// slowestHeap is a min-heap of iterations by duration, whose fastest iteration is the first to go
+// when a slower iteration is recorded.
+type slowestHeap []SlowIteration
+
+func (h slowestHeap) Len() int           { return len(h) }
+func (h slowestHeap) Less(i, j int) bool { return h[i].Duration < h[j].Duration }
+func (h slowestHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *slowestHeap) Push(x any) {
+	*h = append(*h, x.(SlowIteration)) //nolint:forcetypeassert // only SlowIteration are pushed
+}
+
+func (h *slowestHeap) Pop() any {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return last
+}
+
+// slowestIterations keeps the slowest iterations in a heap bounded to SlowestKept, so that its
+// size doesn't depend on the number of iterations.
+type slowestIterations struct {
+	// threshold is the duration of the fastest iteration kept once the heap is full, so that the
+	// iterations faster than it are skipped without taking the lock
+	threshold atomic.Int64
+	heap      slowestHeap
+	mu        sync.Mutex
+}
+
+func (s *slowestIterations) record(iteration SlowIteration) {
+	if int64(iteration.Duration) <= s.threshold.Load() {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case len(s.heap) < SlowestKept:
+		heap.Push(&s.heap, iteration)
+	case iteration.Duration > s.heap[0].Duration:
+		s.heap[0] = iteration
+		heap.Fix(&s.heap, 0)
+	default:
+		return
+	}
+	if len(s.heap) == SlowestKept {
+		s.threshold.Store(int64(s.heap[0].Duration))
+	}
+}
+
+// snapshot returns the slowest iterations, from the slowest.
+func (s *slowestIterations) snapshot() []SlowIteration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.heap) == 0 {
+		return nil
+	}
+
+	slowest := slices.Clone(s.heap)
+	slices.SortStableFunc(slowest, func(a, b SlowIteration) int { return cmp.Compare(b.Duration, a.Duration) })
+
+	return slowest
+}
@@ -0,0 +1,51 @@
+package progress_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/progress"
+)
+
+func TestSlowestIterationsAreKept(t *testing.T) {
+	t.Parallel()
+
+	start := time.Now()
+	stats := &progress.Stats{}
+	stats.Start(start)
+
+	durations := []time.Duration{3, 9, 1, 12, 7, 2, 15, 7, 4, 11}
+	for i, duration := range durations {
+		stats.RecordSlowest(progress.SlowIteration{
+			Start:     start.Add(time.Duration(i) * time.Second),
+			Iteration: strconv.Itoa(i),
+			Worker:    "trigger-" + strconv.Itoa(i%3),
+			Duration:  duration * time.Millisecond,
+		})
+	}
+
+	slowest := stats.Total().Slowest
+	require.Len(t, slowest, progress.SlowestKept)
+	iterations := make([]string, 0, len(slowest))
+	for _, iteration := range slowest {
+		iterations = append(iterations, iteration.Iteration)
+	}
+	assert.Equal(t, []string{"6", "3", "9", "1", "4"}, iterations)
+	assert.Equal(t, progress.SlowIteration{
+		Start:     start.Add(6 * time.Second),
+		Iteration: "6",
+		Worker:    "trigger-0",
+		Duration:  15 * time.Millisecond,
+	}, slowest[0])
+	assert.Equal(t, slowest, stats.Snapshot(time.Second).Slowest)
+}
+
+func TestNoSlowestIterations(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, (&progress.Stats{}).Total().Slowest)
+}
@@ -0,0 +1,110 @@
+package progress
+
+import (
+	"sync"
+	"time"
+)
+
+// StageDurations are the durations of the iterations started in a stage of the run.
+type StageDurations struct {
+	Stage                        string
+	SuccessfulIterationDurations IterationDurationsSnapshot
+	FailedIterationDurations     IterationDurationsSnapshot
+}
+
+type stageIterations struct {
+	successful IterationDurations
+	failed     IterationDurations
+}
+
+// stageTimeline records the durations of iterations by the stage they started in, so that stages
+// can be reported individually. Stages are added on their first iteration, and kept in that order.
+type stageTimeline struct {
+	// stageAt returns the name of the stage running after a duration of the run
+	stageAt func(elapsed time.Duration) string
+	start   time.Time
+	stages  map[string]*stageIterations
+	order   []string
+	mu      sync.RWMutex
+}
+
+func (t *stageTimeline) track(stageAt func(elapsed time.Duration) string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.stageAt = stageAt
+}
+
+func (t *stageTimeline) setStart(start time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.start = start
+}
+
+// iterations returns the iterations of the stage an iteration which completed now after the given
+// duration started in, or nil if stages aren't tracked.
+func (t *stageTimeline) iterations(nanoseconds int64) *stageIterations {
+	t.mu.RLock()
+	if t.stageAt == nil {
+		t.mu.RUnlock()
+		return nil
+	}
+	elapsed := max(time.Since(t.start)-time.Duration(nanoseconds), 0)
+	stage := t.stageAt(elapsed)
+	iterations, ok := t.stages[stage]
+	t.mu.RUnlock()
+
+	if ok || stage == "" {
+		return iterations
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if iterations, ok := t.stages[stage]; ok {
+		return iterations
+	}
+	if t.stages == nil {
+		t.stages = map[string]*stageIterations{}
+	}
+	iterations = &stageIterations{}
+	t.stages[stage] = iterations
+	t.order = append(t.order, stage)
+
+	return iterations
+}
+
+func (t *stageTimeline) record(successful bool, nanoseconds int64) {
+	iterations := t.iterations(nanoseconds)
+	if iterations == nil {
+		return
+	}
+
+	if successful {
+		iterations.successful.Add(nanoseconds)
+	} else {
+		iterations.failed.Add(nanoseconds)
+	}
+}
+
+func (t *stageTimeline) snapshot() []StageDurations {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if len(t.order) == 0 {
+		return nil
+	}
+
+	stages := make([]StageDurations, 0, len(t.order))
+	for _, stage := range t.order {
+		iterations := t.stages[stage]
+		stages = append(stages, StageDurations{
+			Stage:                        stage,
+			SuccessfulIterationDurations: iterations.successful.Snapshot(),
+			FailedIterationDurations:     iterations.failed.Snapshot(),
+		})
+	}
+
+	return stages
+}
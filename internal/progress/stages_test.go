@@ -0,0 +1,52 @@
+package progress_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/metrics"
+	"github.com/form3tech-oss/f1/v2/internal/progress"
+)
+
+func TestIterationsAreRecordedByTheStageTheyStartedIn(t *testing.T) {
+	t.Parallel()
+
+	stats := &progress.Stats{}
+	stats.Start(time.Now().Add(-10 * time.Second))
+	stats.TrackStages(func(elapsed time.Duration) string {
+		if elapsed < 5*time.Second {
+			return "first"
+		}
+		return "second"
+	})
+
+	// iterations are recorded as they complete, so the stage is that of the start of the iteration
+	stats.Record(metrics.SucessResult, int64(time.Millisecond))
+	stats.Record(metrics.SucessResult, int64(8*time.Second))
+	stats.Record(metrics.FailedResult, int64(6*time.Second))
+
+	stages := stats.Total().Stages
+
+	require.Len(t, stages, 2)
+	assert.Equal(t, "second", stages[0].Stage)
+	assert.Equal(t, uint64(1), stages[0].SuccessfulIterationDurations.Count)
+	assert.Equal(t, uint64(0), stages[0].FailedIterationDurations.Count)
+	assert.Equal(t, "first", stages[1].Stage)
+	assert.Equal(t, uint64(1), stages[1].SuccessfulIterationDurations.Count)
+	assert.Equal(t, 8*time.Second, stages[1].SuccessfulIterationDurations.Max)
+	assert.Equal(t, uint64(1), stages[1].FailedIterationDurations.Count)
+	assert.Equal(t, stages, stats.Snapshot(time.Second).Stages)
+}
+
+func TestStagesAreNotRecordedUnlessTracked(t *testing.T) {
+	t.Parallel()
+
+	stats := &progress.Stats{}
+	stats.Start(time.Now())
+	stats.Record(metrics.SucessResult, int64(time.Millisecond))
+
+	assert.Nil(t, stats.Total().Stages)
+}
@@ -12,14 +12,115 @@ type Stats struct {
 	failedIterationDurations     DurationStats
 
 	droppedIterationCount atomic.Uint64
+	drops                 dropTimeline
+	rates                 rateTimeline
+	stages                stageTimeline
+	failures              failureLog
+	failureCategories     failureCategories
+	workerFailures        workerFailures
+	slowest               slowestIterations
+	// triggeredIterationCount is the number of iterations the trigger planned to start
+	triggeredIterationCount atomic.Uint64
+	// bytesSent and bytesReceived are the bytes recorded by the iterations, see RecordBytes
+	bytesSent     atomic.Uint64
+	bytesReceived atomic.Uint64
+	// operations are the durations of the operations timed by the iterations, see RecordOperation
+	operations operationTimings
+}
+
+// Start sets the start of the run, which the planned start of dropped iterations and the start of
+// iterations by stage are relative to.
+func (s *Stats) Start(start time.Time) {
+	s.drops.setStart(start)
+	s.rates.setStart(start)
+	s.stages.setStart(start)
+}
+
+// TrackStages records the durations of iterations by the stage they started in, as returned by
+// stageAt for the duration of the run when they started.
+func (s *Stats) TrackStages(stageAt func(elapsed time.Duration) string) {
+	s.stages.track(stageAt)
+}
+
+// RecordDropped records an iteration dropped because all the workers were busy when it was planned
+// to start.
+func (s *Stats) RecordDropped(planned time.Time) {
+	s.droppedIterationCount.Add(1)
+	s.drops.record(planned)
+}
+
+// RecordTriggered records iterations the trigger planned to start at the given time, the target
+// rate of the run.
+func (s *Stats) RecordTriggered(at time.Time, iterations int) {
+	if iterations <= 0 {
+		return
+	}
+	s.triggeredIterationCount.Add(uint64(iterations))
+	s.rates.record(at, uint64(iterations), 0)
+}
+
+// Triggered returns the number of iterations the trigger planned to start so far.
+func (s *Stats) Triggered() uint64 {
+	return s.triggeredIterationCount.Load()
+}
+
+// Dropped returns the number of iterations dropped so far.
+func (s *Stats) Dropped() uint64 {
+	return s.droppedIterationCount.Load()
+}
+
+// Completed returns the number of iterations completed so far, successful or failed, and their
+// total duration. Unlike Snapshot and Total, it doesn't collect the durations of the progress period.
+func (s *Stats) Completed() (uint64, time.Duration) {
+	successful, successfulDuration := s.successfulIterationDurations.totals()
+	failed, failedDuration := s.failedIterationDurations.totals()
+
+	return successful + failed, successfulDuration + failedDuration
+}
+
+// RecordStarted records an iteration which started at the given time, the achieved rate of the run.
+func (s *Stats) RecordStarted(at time.Time) {
+	s.rates.record(at, 0, 1)
+}
+
+// RecordBytes records the bytes sent and received by an iteration, the throughput of the run.
+func (s *Stats) RecordBytes(sent, received uint64) {
+	s.bytesSent.Add(sent)
+	s.bytesReceived.Add(received)
+}
+
+// RecordSlowest keeps an iteration if it is one of the SlowestKept slowest iterations of the run.
+func (s *Stats) RecordSlowest(iteration SlowIteration) {
+	s.slowest.record(iteration)
+}
+
+// RecordFailure keeps a failed iteration, if it is one of the first or the last FailuresKept
+// failures of the run, and records its duration under its category and counts it under its worker,
+// if it has them. The duration of the iteration is also recorded by Record.
+func (s *Stats) RecordFailure(failure Failure) {
+	s.failures.record(failure)
+	if failure.Category != "" {
+		s.failureCategories.record(failure.Category, failure.Duration.Nanoseconds())
+	}
+	if failure.Worker != "" {
+		s.workerFailures.record(failure.Worker)
+	}
+}
+
+// RecordOperation records the duration of an operation of an iteration, timed with the name of the
+// operation, with the result of the iteration when the operation completed.
+func (s *Stats) RecordOperation(operation string, result metrics.ResultType, nanoseconds int64) {
+	s.operations.record(operation, result == metrics.SucessResult, nanoseconds)
 }
 
 func (s *Stats) Record(result metrics.ResultType, nanoseconds int64) {
 	switch result {
 	case metrics.SucessResult:
 		s.successfulIterationDurations.Record(nanoseconds)
+		s.stages.record(true, nanoseconds)
 	case metrics.FailedResult:
 		s.failedIterationDurations.Record(nanoseconds)
+		s.stages.record(false, nanoseconds)
 	case metrics.DroppedResult:
 		s.droppedIterationCount.Add(1)
 	case metrics.UnknownResult:
@@ -29,33 +130,78 @@ func (s *Stats) Record(result metrics.ResultType, nanoseconds int64) {
 func (s *Stats) Snapshot(period time.Duration) Snapshot {
 	recentSufessfull, lifetimeSuccessful := s.successfulIterationDurations.CollectLifetime()
 	_, lifetimeFailed := s.failedIterationDurations.CollectLifetime()
+	firstFailures, lastFailures := s.failures.snapshot()
 
 	return Snapshot{
 		Period:                                period,
 		DroppedIterationCount:                 s.droppedIterationCount.Load(),
+		Drops:                                 s.drops.snapshot(),
+		Rates:                                 s.rates.snapshot(),
+		Stages:                                s.stages.snapshot(),
+		FirstFailures:                         firstFailures,
+		LastFailures:                          lastFailures,
+		FailureCategories:                     s.failureCategories.snapshot(),
+		WorkerFailures:                        s.workerFailures.snapshot(),
+		Slowest:                               s.slowest.snapshot(),
 		SuccessfulIterationDurationsForPeriod: recentSufessfull,
 		SuccessfulIterationDurations:          lifetimeSuccessful,
 		FailedIterationDurations:              lifetimeFailed,
+		BytesSent:                             s.bytesSent.Load(),
+		BytesReceived:                         s.bytesReceived.Load(),
+		Operations:                            s.operations.snapshot(),
 	}
 }
 
 func (s *Stats) Total() Snapshot {
 	_, lifetimeSuccessful := s.successfulIterationDurations.CollectLifetime()
 	_, lifetimeFailed := s.failedIterationDurations.CollectLifetime()
+	firstFailures, lastFailures := s.failures.snapshot()
 
 	return Snapshot{
 		DroppedIterationCount:        s.droppedIterationCount.Load(),
+		Drops:                        s.drops.snapshot(),
+		Rates:                        s.rates.snapshot(),
+		Stages:                       s.stages.snapshot(),
+		FirstFailures:                firstFailures,
+		LastFailures:                 lastFailures,
+		FailureCategories:            s.failureCategories.snapshot(),
+		WorkerFailures:               s.workerFailures.snapshot(),
+		Slowest:                      s.slowest.snapshot(),
 		SuccessfulIterationDurations: lifetimeSuccessful,
 		FailedIterationDurations:     lifetimeFailed,
+		BytesSent:                    s.bytesSent.Load(),
+		BytesReceived:                s.bytesReceived.Load(),
+		Operations:                   s.operations.snapshot(),
 	}
 }
 
 type Snapshot struct {
-	DroppedIterationCount                 uint64
+	DroppedIterationCount uint64
+	// Drops are the dropped iterations by the second of the run they were planned to start in
+	Drops []DroppedIterations
+	// Rates are the target and achieved rates of the run, by the second of the run
+	Rates []RateSample
+	// Stages are the durations of the iterations by the stage they started in, if stages are tracked
+	Stages []StageDurations
+	// FailureCategories are the durations of the failed iterations by their category
+	FailureCategories []FailureCategoryDurations
+	// WorkerFailures are the workers with the most failed iterations, see WorkersWithMostFailuresKept
+	WorkerFailures []WorkerFailures
+	// Slowest are the slowest iterations of the run, from the slowest
+	Slowest []SlowIteration
+	// FirstFailures and LastFailures are the first and the last failed iterations of the run, which
+	// don't overlap
+	FirstFailures                         []Failure
+	LastFailures                          []Failure
 	SuccessfulIterationDurationsForPeriod IterationDurationsSnapshot
 	SuccessfulIterationDurations          IterationDurationsSnapshot
 	FailedIterationDurations              IterationDurationsSnapshot
 	Period                                time.Duration
+	// BytesSent and BytesReceived are the bytes recorded by the iterations of the scenario
+	BytesSent     uint64
+	BytesReceived uint64
+	// Operations are the durations of the operations timed by the iterations, by name
+	Operations []OperationDurations
 }
 
 func (s *Snapshot) Iterations() uint64 {
@@ -66,6 +212,13 @@ func (s *Snapshot) IterationsStarted() uint64 {
 	return s.SuccessfulIterationDurations.Count + s.FailedIterationDurations.Count
 }
 
-func (s *Snapshot) FailedIterationsRate() uint64 {
-	return s.FailedIterationDurations.Count * 100 / s.Iterations()
+// FailedIterationsRate returns the percentage of iterations which failed. It is a float64 so that
+// the rate isn't truncated to a whole percentage, and doesn't overflow on very large counts.
+func (s *Snapshot) FailedIterationsRate() float64 {
+	iterations := s.Iterations()
+	if iterations == 0 {
+		return 0
+	}
+
+	return 100 * float64(s.FailedIterationDurations.Count) / float64(iterations)
 }
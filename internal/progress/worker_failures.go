@@ -0,0 +1,65 @@
+package progress
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// WorkersWithMostFailuresKept is the number of workers with the most failed iterations reported.
+const WorkersWithMostFailuresKept = 5
+
+// WorkerFailures is the number of iterations which failed on a worker.
+type WorkerFailures struct {
+	Worker string
+	Count  uint64
+}
+
+// workerFailures counts failed iterations by the worker they ran on, to tell failures isolated to a
+// worker, such as those of a poisoned connection, apart from failures of the target.
+type workerFailures struct {
+	workers map[string]*atomic.Uint64
+	mu      sync.RWMutex
+}
+
+func (w *workerFailures) record(worker string) {
+	w.mu.RLock()
+	count, ok := w.workers[worker]
+	w.mu.RUnlock()
+
+	if !ok {
+		w.mu.Lock()
+		if count, ok = w.workers[worker]; !ok {
+			if w.workers == nil {
+				w.workers = map[string]*atomic.Uint64{}
+			}
+			count = &atomic.Uint64{}
+			w.workers[worker] = count
+		}
+		w.mu.Unlock()
+	}
+
+	count.Add(1)
+}
+
+// snapshot returns the WorkersWithMostFailuresKept workers with the most failures, by decreasing
+// number of failures.
+func (w *workerFailures) snapshot() []WorkerFailures {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if len(w.workers) == 0 {
+		return nil
+	}
+
+	workers := make([]WorkerFailures, 0, len(w.workers))
+	for worker, count := range w.workers {
+		workers = append(workers, WorkerFailures{Worker: worker, Count: count.Load()})
+	}
+	slices.SortFunc(workers, func(a, b WorkerFailures) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Worker, b.Worker))
+	})
+
+	return workers[:min(len(workers), WorkersWithMostFailuresKept)]
+}
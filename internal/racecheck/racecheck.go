@@ -0,0 +1,164 @@
+// Package racecheck runs a scenario for a short time in a binary built with the race detector,
+// so that scenarios sharing state across workers without synchronisation fail fast, before a
+// high rate run produces results corrupted by the races.
+package racecheck
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/form3tech-oss/f1/v2/internal/envsettings"
+)
+
+const (
+	// DefaultDuration is how long the scenario is run for by the race check
+	DefaultDuration = 10 * time.Second
+
+	// raceExitCode is the exit code of binaries built with the race detector which found races
+	raceExitCode = 66
+	// stopTimeout is how long the check is given to stop once interrupted
+	stopTimeout = 30 * time.Second
+
+	reportSeparator = "=================="
+	raceWarning     = "WARNING: DATA RACE"
+)
+
+// ErrDataRace is returned when the race detector found a data race during the check.
+var ErrDataRace = errors.New("data race")
+
+// Enabled returns true if the running binary was built with the race detector.
+func Enabled() bool {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return false
+	}
+	for _, setting := range info.Settings {
+		if setting.Key == "-race" {
+			return setting.Value == "true"
+		}
+	}
+
+	return false
+}
+
+// Binary returns a binary of the running scenarios built with the race detector: the running
+// binary if it was, or else a build of its main package with go build -race in dir, which needs
+// the go toolchain and the sources of the module in the working directory.
+func Binary(ctx context.Context, dir string) (string, error) {
+	if Enabled() {
+		executable, err := os.Executable()
+		if err != nil {
+			return "", fmt.Errorf("finding executable: %w", err)
+		}
+		return executable, nil
+	}
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok || info.Path == "" || info.Path == "command-line-arguments" {
+		return "", errors.New("the main package of the binary is unknown, build it with go build -race instead")
+	}
+
+	binary := filepath.Join(dir, "f1-race")
+	build := exec.CommandContext(ctx, "go", "build", "-race", "-o", binary, info.Path)
+	if output, err := build.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("building %s with the race detector: %w: %s", info.Path, err,
+			strings.TrimSpace(string(output)))
+	}
+
+	return binary, nil
+}
+
+// Run runs binary with args for duration, interrupting it if it is still running by then, and
+// returns ErrDataRace with the report of the first data race if the race detector found one.
+func Run(ctx context.Context, binary string, args []string, duration time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, duration)
+	defer cancel()
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, binary, args...)
+	cmd.Stderr = &stderr
+	// the check stops at the first race, and doesn't push metrics or write logs to the files of the run
+	cmd.Env = append(os.Environ(), "GORACE=halt_on_error=1 "+os.Getenv("GORACE"),
+		envsettings.EnvPrometheusPushGateway+"=", envsettings.EnvOTLPMetricsEndpoint+"=", envsettings.EnvLogFilePath+"=")
+	cmd.Cancel = func() error { return cmd.Process.Signal(os.Interrupt) }
+	cmd.WaitDelay = stopTimeout
+
+	err := cmd.Run()
+	if report, found := firstRace(stderr.String()); found {
+		return fmt.Errorf("%w found by the race check:\n%s", ErrDataRace, report)
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == raceExitCode {
+		return fmt.Errorf("%w found by the race check:\n%s", ErrDataRace, strings.TrimSpace(stderr.String()))
+	}
+	// runs interrupted at the end of the check fail, which doesn't matter to the check
+	if err != nil && ctx.Err() == nil {
+		if line := lastLine(stderr.String()); line != "" {
+			return fmt.Errorf("running the race check: %w: %s", err, line)
+		}
+		return fmt.Errorf("running the race check: %w", err)
+	}
+
+	return nil
+}
+
+// firstRace returns the first report of a data race in the output of the race detector.
+func firstRace(output string) (string, bool) {
+	start := strings.Index(output, raceWarning)
+	if start < 0 {
+		return "", false
+	}
+	report := output[start:]
+	if end := strings.Index(report, reportSeparator); end >= 0 {
+		report = report[:end]
+	}
+
+	return strings.TrimSpace(report), true
+}
+
+func lastLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	return lines[len(lines)-1]
+}
+
+// Args returns the arguments running cmd again with the flags set on it and args, except the
+// omitted flags, such as the flags of the race check itself or writing the files of the run.
+func Args(cmd *cobra.Command, args []string, omitted ...string) []string {
+	// the path of the command without the name of the binary
+	checkArgs := strings.Fields(cmd.CommandPath())[1:]
+
+	cmd.Flags().Visit(func(flag *pflag.Flag) {
+		for _, name := range omitted {
+			if flag.Name == name {
+				return
+			}
+		}
+		if values, ok := flag.Value.(pflag.SliceValue); ok {
+			for _, value := range values.GetSlice() {
+				checkArgs = append(checkArgs, "--"+flag.Name+"="+value)
+			}
+			return
+		}
+		checkArgs = append(checkArgs, "--"+flag.Name+"="+flag.Value.String())
+	})
+
+	dash := cmd.ArgsLenAtDash()
+	if dash < 0 {
+		return append(checkArgs, args...)
+	}
+	checkArgs = append(checkArgs, args[:dash]...)
+	checkArgs = append(checkArgs, "--")
+
+	return append(checkArgs, args[dash:]...)
+}
@@ -0,0 +1,111 @@
+package racecheck_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/racecheck"
+)
+
+const raceReport = `==================
+WARNING: DATA RACE
+Write at 0x00c000012345 by goroutine 8:
+  main.scenario.func1()
+      /src/scenario.go:12 +0x44
+
+Previous write at 0x00c000012345 by goroutine 7:
+  main.scenario.func1()
+      /src/scenario.go:12 +0x44
+==================
+`
+
+// writeBinary writes an executable script standing in for the binary of the race check.
+func writeBinary(t *testing.T, script string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "f1-race")
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0o700)) //nolint:gosec // must be executable
+
+	return path
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name     string
+		script   string
+		expected string
+	}{
+		{
+			name:   "interrupted without races",
+			script: "exec sleep 5\n",
+		},
+		{
+			name:   "completed without races",
+			script: "exit 0\n",
+		},
+		{
+			name:   "data race",
+			script: "cat >&2 <<'EOF'\n" + raceReport + "EOF\nexit 66\n",
+			expected: "data race found by the race check:\nWARNING: DATA RACE\n" +
+				"Write at 0x00c000012345 by goroutine 8:\n  main.scenario.func1()\n      /src/scenario.go:12 +0x44\n\n" +
+				"Previous write at 0x00c000012345 by goroutine 7:\n  main.scenario.func1()\n" +
+				"      /src/scenario.go:12 +0x44",
+		},
+		{
+			name:     "failed without races",
+			script:   "echo 'setting up\nscenario not defined' >&2\nexit 1\n",
+			expected: "running the race check: exit status 1: scenario not defined",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := racecheck.Run(context.Background(), writeBinary(t, test.script), nil, 100*time.Millisecond)
+
+			if test.expected == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, test.expected)
+		})
+	}
+}
+
+func TestArgs(t *testing.T) {
+	t.Parallel()
+
+	var args []string
+	trigger := &cobra.Command{
+		Use: "constant",
+		Run: func(cmd *cobra.Command, positional []string) {
+			args = racecheck.Args(cmd, positional, "race-check", "report-file")
+		},
+	}
+	trigger.Flags().String("rate", "1/s", "")
+	trigger.Flags().Bool("race-check", false, "")
+	trigger.Flags().String("report-file", "", "")
+	trigger.Flags().StringSlice("tag", nil, "")
+	trigger.Flags().Int("concurrency", 100, "")
+	run := &cobra.Command{Use: "run"}
+	run.AddCommand(trigger)
+	root := &cobra.Command{Use: "f1"}
+	root.AddCommand(run)
+	root.SetArgs([]string{
+		"run", "constant", "myScenario", "--rate", "5/s", "--race-check", "--report-file", "report.json",
+		"--tag", "a,b", "--", "--region", "eu",
+	})
+
+	require.NoError(t, root.Execute())
+	assert.Equal(t, []string{
+		"run", "constant", "--rate=5/s", "--tag=a", "--tag=b", "myScenario", "--", "--region", "eu",
+	}, args)
+}
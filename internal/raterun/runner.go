@@ -3,12 +3,17 @@ package raterun
 import (
 	"context"
 	"errors"
+	"fmt"
+	"math/rand/v2"
 	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/goroutines"
 )
 
 // RunFunction is a function type that represents the function to be executed by the Runner.
 //
-// It will be called with the frequency at which the function is executed.
+// It will be called with the interval since the previous execution, which is the frequency of the
+// current Schedule unless the schedule is jittered.
 type RunFunction func(frequency time.Duration)
 
 // Schedule configures when and how frequent the Runner will execute the function
@@ -17,18 +22,22 @@ type Schedule struct {
 	StartDelay time.Duration
 	// Frequency configures how often the function will be executed during this Schedule
 	Frequency time.Duration
+	// Jitter randomly varies each interval between executions by up to this percentage of the
+	// Frequency, so that runners started together don't execute their functions at the same time
+	Jitter float64
 }
 
 // New creates a new runner that will execute fn as defined by the provided schedules
 //
 // Each Schedule in schedules defines how often fn should be executed at any given point in time.
 func New(fn RunFunction, schedules []Schedule) (*Runner, error) {
-	if len(schedules) == 0 {
-		return nil, errors.New("empty schedules")
+	if err := validateSchedules(schedules); err != nil {
+		return nil, err
 	}
 
 	rateRunner := &Runner{
 		restart:     make(chan struct{}, 1),
+		reschedule:  make(chan []Schedule, 1),
 		runFunction: fn,
 		schedules:   newSchedules(schedules),
 		stopped:     make(chan struct{}),
@@ -37,8 +46,26 @@ func New(fn RunFunction, schedules []Schedule) (*Runner, error) {
 	return rateRunner, nil
 }
 
+func validateSchedules(schedules []Schedule) error {
+	if len(schedules) == 0 {
+		return errors.New("empty schedules")
+	}
+
+	for i, schedule := range schedules {
+		if schedule.Frequency <= 0 {
+			return fmt.Errorf("frequency %s of schedule %d must be positive", schedule.Frequency, i)
+		}
+		if schedule.Jitter < 0 || schedule.Jitter >= 100 {
+			return fmt.Errorf("jitter %g%% of schedule %d must be at least 0 and below 100", schedule.Jitter, i)
+		}
+	}
+
+	return nil
+}
+
 type Runner struct {
 	restart     chan struct{}
+	reschedule  chan []Schedule
 	runFunction RunFunction
 
 	schedules *schedules
@@ -51,6 +78,28 @@ func (r *Runner) Restart() {
 	r.restart <- struct{}{}
 }
 
+// Reschedule replaces the schedules of the runner and starts from the first of them, for example
+// to execute the function more often for a while. It doesn't block, so it may be called by the
+// function itself, and only the latest schedules are kept if it is called again before they start.
+func (r *Runner) Reschedule(schedules []Schedule) error {
+	if err := validateSchedules(schedules); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case r.reschedule <- schedules:
+			return nil
+		default:
+			// replace the schedules which haven't started yet
+			select {
+			case <-r.reschedule:
+			default:
+			}
+		}
+	}
+}
+
 // Start starts the execution of the runner.
 //
 // Start is non-blockig and runs in a go routine. The provided context can be used to manage the
@@ -61,21 +110,25 @@ func (r *Runner) Start(ctx context.Context) {
 	schedulesCtx, schedulesCtxCancel := context.WithCancel(ctx)
 	r.cancel = schedulesCtxCancel
 
-	go func() {
+	goroutines.Go(schedulesCtx, "progress", func(context.Context) {
 		for {
 			select {
 			case <-r.restart:
 				r.schedules.startFirst()
+			case list := <-r.reschedule:
+				r.schedules.replace(list)
 			case <-r.schedules.timeUntilNextSchedule():
 				r.schedules.startNext()
-			case <-r.schedules.currentScheduleTicker():
-				r.runFunction(r.schedules.currentFrequency())
+			case <-r.schedules.currentScheduleTimer():
+				interval := r.schedules.currentInterval()
+				r.schedules.scheduleNextRun()
+				r.runFunction(interval)
 			case <-schedulesCtx.Done():
 				r.schedules.stop()
 				return
 			}
 		}
-	}()
+	})
 }
 
 // Stop stopps the runner and will block until the runner is stopped
@@ -85,17 +138,24 @@ func (r *Runner) Stop() {
 }
 
 type schedules struct {
-	ticker               *time.Ticker
-	nextScheduleTimer    *time.Timer
+	runTimer          *time.Timer
+	nextScheduleTimer *time.Timer
+	// nextRun is when the function is executed next, so that the time the function takes doesn't
+	// delay the following executions
+	nextRun              time.Time
 	list                 []Schedule
+	interval             time.Duration
 	currentScheduleIndex int
 }
 
 func newSchedules(list []Schedule) *schedules {
+	runTimer := time.NewTimer(time.Hour)
+	runTimer.Stop()
+
 	return &schedules{
 		list:                 list,
 		currentScheduleIndex: -1,
-		ticker:               time.NewTicker(time.Hour),
+		runTimer:             runTimer,
 		nextScheduleTimer:    time.NewTimer(list[0].StartDelay),
 	}
 }
@@ -105,9 +165,9 @@ func (s *schedules) start(index int) {
 		return
 	}
 
-	s.ticker.Stop()
 	s.currentScheduleIndex = index
-	s.ticker = time.NewTicker(s.list[s.currentScheduleIndex].Frequency)
+	s.nextRun = time.Now()
+	s.scheduleNextRun()
 
 	nextIndex := s.currentScheduleIndex + 1
 	s.nextScheduleTimer.Stop()
@@ -126,12 +186,39 @@ func (s *schedules) startNext() {
 	s.start(s.currentScheduleIndex + 1)
 }
 
-func (s *schedules) currentFrequency() time.Duration {
-	return s.list[s.currentScheduleIndex].Frequency
+// replace replaces the schedules with list, starting from its first schedule after its start delay.
+func (s *schedules) replace(list []Schedule) {
+	s.stop()
+	s.list = list
+	s.currentScheduleIndex = -1
+	s.nextScheduleTimer = time.NewTimer(list[0].StartDelay)
+}
+
+// scheduleNextRun schedules the next execution of the function, one interval of the current
+// schedule after the previous one.
+func (s *schedules) scheduleNextRun() {
+	schedule := s.list[s.currentScheduleIndex]
+	s.interval = schedule.Frequency
+	if schedule.Jitter > 0 {
+		deviation := (2*rand.Float64() - 1) * schedule.Jitter / 100 //nolint:gosec // jitter isn't security sensitive
+		s.interval += time.Duration(deviation * float64(schedule.Frequency))
+	}
+
+	s.nextRun = s.nextRun.Add(s.interval)
+	if now := time.Now(); s.nextRun.Before(now) {
+		// executions missed while the function was running are skipped, as by a ticker
+		s.nextRun = now
+	}
+	s.runTimer.Stop()
+	s.runTimer = time.NewTimer(max(time.Until(s.nextRun), 0))
+}
+
+func (s *schedules) currentInterval() time.Duration {
+	return s.interval
 }
 
 func (s *schedules) stop() {
-	s.ticker.Stop()
+	s.runTimer.Stop()
 	s.nextScheduleTimer.Stop()
 }
 
@@ -139,6 +226,6 @@ func (s *schedules) timeUntilNextSchedule() <-chan time.Time {
 	return s.nextScheduleTimer.C
 }
 
-func (s *schedules) currentScheduleTicker() <-chan time.Time {
-	return s.ticker.C
+func (s *schedules) currentScheduleTimer() <-chan time.Time {
+	return s.runTimer.C
 }
@@ -89,6 +89,35 @@ func (s *RatedRunnerStage) runner_is_reset() *RatedRunnerStage {
 	return s
 }
 
+func (s *RatedRunnerStage) runner_is_rescheduled(rates []raterun.Schedule) *RatedRunnerStage {
+	require.NoError(s.t, s.runner.Reschedule(rates))
+	return s
+}
+
+func (s *RatedRunnerStage) function_ran_times_at_frequency(expectedRuns int, frequency time.Duration) *RatedRunnerStage {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	assert.Equal(s.t, expectedRuns, s.funcRuns[frequency])
+	return s
+}
+
+func (s *RatedRunnerStage) function_ran_at_intervals_within(
+	frequency time.Duration, jitter float64,
+) *RatedRunnerStage {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	deviation := time.Duration(float64(frequency) * jitter / 100)
+	require.NotEmpty(s.t, s.funcRuns)
+	for interval := range s.funcRuns {
+		assert.GreaterOrEqual(s.t, interval, frequency-deviation)
+		assert.LessOrEqual(s.t, interval, frequency+deviation)
+	}
+	assert.Greater(s.t, len(s.funcRuns), 1, "intervals were not jittered")
+	return s
+}
+
 func (s *RatedRunnerStage) a_go_leak_is_found() *RatedRunnerStage {
 	err := goleak.Find()
 	assert.Error(s.t, err, "should have found a go leak")
@@ -5,6 +5,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
+
 	"github.com/form3tech-oss/f1/v2/internal/raterun"
 )
 
@@ -55,6 +57,62 @@ func Test_FunctionIsExecutedAtSpecifiedRatesWhenRatesAreReset(t *testing.T) {
 		a_go_leak_is_not_found()
 }
 
+func Test_FunctionIsExecutedAtTheRatesOfNewSchedules(t *testing.T) {
+	given, when, then := NewRatedRunnerStage(t)
+
+	given.some_rates([]raterun.Schedule{
+		{StartDelay: time.Nanosecond, Frequency: time.Millisecond * 250},
+	}).
+		and().
+		a_rate_runner()
+
+	when.runner_is_run().and().
+		// allow 2 runs of the function
+		time_passes(time.Millisecond * 600).and().
+		runner_is_rescheduled([]raterun.Schedule{
+			{StartDelay: time.Nanosecond, Frequency: time.Millisecond * 80},
+		}).and().
+		// allow 5 runs of the function at the new rate
+		time_passes(time.Millisecond * 440).and().
+		runner_is_terminated()
+
+	then.function_ran_times_at_frequency(2, time.Millisecond*250).and().
+		function_ran_times_at_frequency(5, time.Millisecond*80).and().
+		a_go_leak_is_not_found()
+}
+
+func Test_FunctionIsExecutedAtJitteredIntervals(t *testing.T) {
+	given, when, then := NewRatedRunnerStage(t)
+
+	given.some_rates([]raterun.Schedule{
+		{StartDelay: time.Nanosecond, Frequency: time.Millisecond * 20, Jitter: 50},
+	}).
+		and().
+		a_rate_runner()
+
+	when.runner_is_run().and().
+		time_passes(time.Millisecond * 500).and().
+		runner_is_terminated()
+
+	then.function_ran_at_intervals_within(time.Millisecond*20, 50).and().
+		a_go_leak_is_not_found()
+}
+
+func Test_InvalidSchedules(t *testing.T) {
+	for name, schedules := range map[string][]raterun.Schedule{
+		"empty":             nil,
+		"zero frequency":    {{Frequency: 0}},
+		"negative jitter":   {{Frequency: time.Second, Jitter: -1}},
+		"jitter of 100%":    {{Frequency: time.Second, Jitter: 100}},
+		"second is invalid": {{Frequency: time.Second}, {StartDelay: time.Second}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := raterun.New(func(time.Duration) {}, schedules)
+			assert.Error(t, err)
+		})
+	}
+}
+
 func Test_RunnerLeaksWhenNotTerminated(t *testing.T) {
 	given, when, then := NewRatedRunnerStage(t)
 
@@ -0,0 +1,219 @@
+// Package redact replaces the parts of logs, reports and artifacts matching configured patterns, so
+// that runs against environments holding personal data can share them safely.
+package redact
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Replacement replaces the redacted parts of text.
+const Replacement = "[REDACTED]"
+
+// Redactor redacts the matches of its patterns, and the values of the secrets added to it. Patterns
+// with capture groups redact the text of their groups only, so that the context of a match, such as
+// the name of a field, is kept. A nil Redactor redacts nothing.
+type Redactor struct {
+	patterns []*regexp.Regexp
+	// secrets are redacted wherever they appear, see AddSecret
+	secrets   atomic.Pointer[[]string]
+	secretsMu sync.Mutex
+}
+
+// New compiles the patterns into a Redactor.
+func New(patterns []string) (*Redactor, error) {
+	r := &Redactor{patterns: make([]*regexp.Regexp, 0, len(patterns))}
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", pattern, err)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+
+	return r, nil
+}
+
+// AddSecret redacts the value of a secret, such as a credential fetched by the scenario, from then
+// on. It may be called from any goroutine.
+func (r *Redactor) AddSecret(value string) {
+	if r == nil || value == "" {
+		return
+	}
+
+	r.secretsMu.Lock()
+	defer r.secretsMu.Unlock()
+
+	var secrets []string
+	if current := r.secrets.Load(); current != nil {
+		if slices.Contains(*current, value) {
+			return
+		}
+		secrets = slices.Clone(*current)
+	}
+	secrets = append(secrets, value)
+	r.secrets.Store(&secrets)
+}
+
+// String returns s with the secrets and the matches of the patterns redacted.
+func (r *Redactor) String(s string) string {
+	if r == nil {
+		return s
+	}
+
+	if secrets := r.secrets.Load(); secrets != nil {
+		for _, secret := range *secrets {
+			s = strings.ReplaceAll(s, secret, Replacement)
+		}
+	}
+	for _, re := range r.patterns {
+		s = redact(re, s)
+	}
+
+	return s
+}
+
+// Bytes returns data with the matches of the patterns redacted.
+func (r *Redactor) Bytes(data []byte) []byte {
+	if r == nil {
+		return data
+	}
+
+	return []byte(r.String(string(data)))
+}
+
+func redact(re *regexp.Regexp, s string) string {
+	if re.NumSubexp() == 0 {
+		return re.ReplaceAllLiteralString(s, Replacement)
+	}
+
+	matches := re.FindAllStringSubmatchIndex(s, -1)
+	if len(matches) == 0 {
+		return s
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, match := range matches {
+		// groups are in order of their opening parenthesis, nested groups are redacted with their parent
+		for i := 2; i < len(match); i += 2 {
+			start, end := match[i], match[i+1]
+			if start < last || start < 0 {
+				continue
+			}
+			b.WriteString(s[last:start])
+			b.WriteString(Replacement)
+			last = end
+		}
+	}
+	b.WriteString(s[last:])
+
+	return b.String()
+}
+
+// Error returns err with the matches of the patterns redacted from its message. The redacted error
+// wraps err, so that it can still be inspected with errors.Is and errors.As.
+func (r *Redactor) Error(err error) error {
+	if r == nil || err == nil {
+		return err
+	}
+
+	message := err.Error()
+	redacted := r.String(message)
+	if redacted == message {
+		return err
+	}
+
+	return &redactedError{err: err, message: redacted}
+}
+
+type redactedError struct {
+	err     error
+	message string
+}
+
+func (e *redactedError) Error() string {
+	return e.message
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
+// Handler returns a handler redacting the messages and the attributes of records before they are
+// passed to handler, or handler itself if r is nil.
+func (r *Redactor) Handler(handler slog.Handler) slog.Handler {
+	if r == nil {
+		return handler
+	}
+
+	return &redactHandler{redactor: r, handler: handler}
+}
+
+type redactHandler struct {
+	redactor *Redactor
+	handler  slog.Handler
+}
+
+func (h *redactHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *redactHandler) Handle(ctx context.Context, record slog.Record) error {
+	redacted := slog.NewRecord(record.Time, record.Level, h.redactor.String(record.Message), record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		redacted.AddAttrs(h.redactAttr(attr))
+		return true
+	})
+
+	if err := h.handler.Handle(ctx, redacted); err != nil {
+		return fmt.Errorf("handling log record: %w", err)
+	}
+
+	return nil
+}
+
+func (h *redactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, 0, len(attrs))
+	for _, attr := range attrs {
+		redacted = append(redacted, h.redactAttr(attr))
+	}
+
+	return &redactHandler{redactor: h.redactor, handler: h.handler.WithAttrs(redacted)}
+}
+
+func (h *redactHandler) WithGroup(name string) slog.Handler {
+	return &redactHandler{redactor: h.redactor, handler: h.handler.WithGroup(name)}
+}
+
+// redactAttr redacts string values, and other values as they are formatted, which are replaced
+// with the redacted string when they contain a match.
+func (h *redactHandler) redactAttr(attr slog.Attr) slog.Attr {
+	attr.Value = attr.Value.Resolve()
+
+	switch attr.Value.Kind() {
+	case slog.KindString:
+		attr.Value = slog.StringValue(h.redactor.String(attr.Value.String()))
+	case slog.KindGroup:
+		group := attr.Value.Group()
+		redacted := make([]slog.Attr, 0, len(group))
+		for _, groupAttr := range group {
+			redacted = append(redacted, h.redactAttr(groupAttr))
+		}
+		attr.Value = slog.GroupValue(redacted...)
+	case slog.KindAny:
+		formatted := fmt.Sprint(attr.Value.Any())
+		if redacted := h.redactor.String(formatted); redacted != formatted {
+			attr.Value = slog.StringValue(redacted)
+		}
+	default:
+	}
+
+	return attr
+}
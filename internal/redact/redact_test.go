@@ -0,0 +1,154 @@
+package redact_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/redact"
+)
+
+func TestString(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name     string
+		patterns []string
+		input    string
+		expected string
+	}{
+		{
+			name:     "no patterns",
+			input:    "email jane@example.com",
+			expected: "email jane@example.com",
+		},
+		{
+			name:     "whole matches",
+			patterns: []string{`[\w.]+@[\w.]+`},
+			input:    "email jane@example.com and john@example.com",
+			expected: "email [REDACTED] and [REDACTED]",
+		},
+		{
+			name:     "capture groups",
+			patterns: []string{`card=(\d+)`},
+			input:    "declined card=4111111111111111, retrying card=5500000000000004",
+			expected: "declined card=[REDACTED], retrying card=[REDACTED]",
+		},
+		{
+			name:     "several groups",
+			patterns: []string{`user=(\w+) pass=(\w+)`},
+			input:    "login user=jane pass=secret failed",
+			expected: "login user=[REDACTED] pass=[REDACTED] failed",
+		},
+		{
+			name:     "nested groups",
+			patterns: []string{`iban=((\w\w)\d+)`},
+			input:    "iban=GB82123456",
+			expected: "iban=[REDACTED]",
+		},
+		{
+			name:     "optional groups",
+			patterns: []string{`token(=(\w+))?`},
+			input:    "token and token=abc",
+			expected: "token and token[REDACTED]",
+		},
+		{
+			name:     "several patterns",
+			patterns: []string{`card=(\d+)`, `[\w.]+@[\w.]+`},
+			input:    "card=4111111111111111 of jane@example.com",
+			expected: "card=[REDACTED] of [REDACTED]",
+		},
+		{
+			name:     "no matches",
+			patterns: []string{`card=(\d+)`},
+			input:    "iteration failed",
+			expected: "iteration failed",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			redactor, err := redact.New(test.patterns)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, redactor.String(test.input))
+			assert.Equal(t, []byte(test.expected), redactor.Bytes([]byte(test.input)))
+		})
+	}
+}
+
+func TestAddSecret(t *testing.T) {
+	t.Parallel()
+
+	redactor, err := redact.New([]string{`card=(\d+)`})
+	require.NoError(t, err)
+
+	redactor.AddSecret("s3cr3t")
+	redactor.AddSecret("s3cr3t")
+	redactor.AddSecret("")
+
+	assert.Equal(t, "token=[REDACTED] card=[REDACTED]", redactor.String("token=s3cr3t card=4111"))
+
+	var nilRedactor *redact.Redactor
+	nilRedactor.AddSecret("s3cr3t")
+	assert.Equal(t, "token=s3cr3t", nilRedactor.String("token=s3cr3t"))
+}
+
+func TestNewWithAnInvalidPattern(t *testing.T) {
+	t.Parallel()
+
+	_, err := redact.New([]string{`card=(\d+`})
+
+	require.ErrorContains(t, err, `invalid redaction pattern "card=(\\d+"`)
+}
+
+func TestError(t *testing.T) {
+	t.Parallel()
+
+	redactor, err := redact.New([]string{`card=(\d+)`})
+	require.NoError(t, err)
+
+	cause := errors.New("declined")
+	redacted := redactor.Error(fmt.Errorf("card=4111111111111111: %w", cause))
+	unchanged := errors.New("timeout")
+
+	require.EqualError(t, redacted, "card=[REDACTED]: declined")
+	require.ErrorIs(t, redacted, cause)
+	require.Same(t, unchanged, redactor.Error(unchanged))
+	require.NoError(t, redactor.Error(nil))
+
+	var nilRedactor *redact.Redactor
+	require.Same(t, unchanged, nilRedactor.Error(unchanged))
+}
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+
+	redactor, err := redact.New([]string{`card=(\d+)`, `\d{16}`})
+	require.NoError(t, err)
+
+	var logs bytes.Buffer
+	handler := slog.NewTextHandler(&logs, &slog.HandlerOptions{
+		ReplaceAttr: func(_ []string, attr slog.Attr) slog.Attr {
+			if attr.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return attr
+		},
+	})
+	logger := slog.New(redactor.Handler(handler)).With(slog.String("card", "4111111111111111"))
+
+	logger.Info("declined card=4111111111111111",
+		slog.Any("error", errors.New("card=5500000000000004 declined")),
+		slog.Group("payment", slog.String("note", "card=5500000000000004")),
+		slog.Int("attempt", 2),
+	)
+
+	assert.Equal(t, `level=INFO msg="declined card=[REDACTED]" card=[REDACTED] `+
+		`error="card=[REDACTED] declined" payment.note="card=[REDACTED]" attempt=2`+"\n", logs.String())
+}
@@ -0,0 +1,197 @@
+// Package reportindex turns a directory of json run reports, such as those written by --report-file
+// or by the runs of a campaign, into an index.html listing the runs with their key figures and the
+// trends of each scenario, so that the directory can be browsed as a lightweight results portal.
+package reportindex
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/run"
+)
+
+// FileName is the name of the index written to the directory of the reports.
+const FileName = "index.html"
+
+const (
+	sparklineWidth  = 120
+	sparklineHeight = 24
+)
+
+// ErrNoReports is returned when the directory has no run reports to index.
+var ErrNoReports = errors.New("no run reports found")
+
+// Run is a run report of the directory, with the key figures listed in the index.
+type Run struct {
+	// Name is the name of the report file, without its extension
+	Name string
+	// File is the name of the report file, which the index links to
+	File string
+	// Time is when the report was written, at the end of the run
+	Time      time.Time
+	Scenario  string
+	Failed    bool
+	Error     string
+	Duration  time.Duration
+	Started   uint64
+	ErrorRate float64
+	// Throughput is the number of iterations started per second
+	Throughput float64
+	P95        time.Duration
+	P99        time.Duration
+	// TargetRate and AchievedRate are the points of the sparklines of the target and achieved rates
+	// of the run by the second of the run, as the points of svg polylines, so that their divergence
+	// shows where the load generator couldn't keep up with the trigger
+	TargetRate   string
+	AchievedRate string
+}
+
+// Trend is the p95 of the successful iterations of the runs of a scenario, from the oldest run.
+type Trend struct {
+	Scenario string
+	Runs     int
+	Latest   time.Duration
+	// Points are the points of the sparkline of the trend, as the points of an svg polyline
+	Points string
+}
+
+// Load reads the run reports of dir, from the most recent. Json files which aren't run reports, such
+// as campaign reports, are ignored.
+func Load(dir string) ([]Run, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", dir, err)
+	}
+
+	var runs []Run
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		var report run.Report
+		if err := json.Unmarshal(data, &report); err != nil || report.Scenario == "" {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+
+		runs = append(runs, newRun(entry.Name(), info.ModTime(), report))
+	}
+
+	slices.SortStableFunc(runs, func(a, b Run) int {
+		return cmp.Or(b.Time.Compare(a.Time), cmp.Compare(a.Name, b.Name))
+	})
+
+	return runs, nil
+}
+
+func newRun(file string, written time.Time, report run.Report) Run {
+	r := Run{
+		Name:      strings.TrimSuffix(file, ".json"),
+		File:      file,
+		Time:      written,
+		Scenario:  report.Scenario,
+		Failed:    report.Failed,
+		Error:     report.Error,
+		Duration:  report.Duration,
+		Started:   report.IterationsStarted,
+		ErrorRate: report.ErrorRate(),
+		P95:       report.SuccessfulIterationDurations.P95,
+		P99:       report.SuccessfulIterationDurations.P99,
+	}
+	if report.Duration > 0 {
+		r.Throughput = float64(report.IterationsStarted) / report.Duration.Seconds()
+	}
+	r.TargetRate, r.AchievedRate = run.RatePoints(report.Rates, sparklineWidth, sparklineHeight)
+
+	return r
+}
+
+// Trends returns the trends of the p95 of each scenario of runs, by scenario name.
+func Trends(runs []Run) []Trend {
+	byScenario := map[string][]Run{}
+	for _, r := range runs {
+		byScenario[r.Scenario] = append(byScenario[r.Scenario], r)
+	}
+
+	trends := make([]Trend, 0, len(byScenario))
+	for scenario, scenarioRuns := range byScenario {
+		slices.SortStableFunc(scenarioRuns, func(a, b Run) int { return a.Time.Compare(b.Time) })
+		trends = append(trends, Trend{
+			Scenario: scenario,
+			Runs:     len(scenarioRuns),
+			Latest:   scenarioRuns[len(scenarioRuns)-1].P95,
+			Points:   sparkline(scenarioRuns),
+		})
+	}
+	slices.SortFunc(trends, func(a, b Trend) int { return cmp.Compare(a.Scenario, b.Scenario) })
+
+	return trends
+}
+
+// sparkline returns the points of the p95 of runs, scaled to the sparkline from 0 to the highest p95.
+func sparkline(runs []Run) string {
+	highest := slices.MaxFunc(runs, func(a, b Run) int { return cmp.Compare(a.P95, b.P95) }).P95
+
+	points := make([]string, 0, len(runs))
+	for i, r := range runs {
+		x := float64(sparklineWidth) / 2
+		if len(runs) > 1 {
+			x = float64(i) * sparklineWidth / float64(len(runs)-1)
+		}
+		y := float64(sparklineHeight)
+		if highest > 0 {
+			y -= float64(r.P95) / float64(highest) * sparklineHeight
+		}
+		points = append(points, strconv.FormatFloat(x, 'f', 1, 64)+","+strconv.FormatFloat(y, 'f', 1, 64))
+	}
+
+	return strings.Join(points, " ")
+}
+
+// Write writes the index of the run reports of dir to its index.html, returning the number of runs
+// indexed.
+func Write(dir string) (int, error) {
+	runs, err := Load(dir)
+	if err != nil {
+		return 0, err
+	}
+	if len(runs) == 0 {
+		return 0, fmt.Errorf("%w in %s", ErrNoReports, dir)
+	}
+
+	var index bytes.Buffer
+	err = indexTemplate.Execute(&index, struct {
+		Runs   []Run
+		Trends []Trend
+		Width  int
+		Height int
+	}{Runs: runs, Trends: Trends(runs), Width: sparklineWidth, Height: sparklineHeight})
+	if err != nil {
+		return 0, fmt.Errorf("executing template: %w", err)
+	}
+
+	path := filepath.Join(dir, FileName)
+	if err := os.WriteFile(path, index.Bytes(), 0o644); err != nil { //nolint:gosec // the index is meant to be served
+		return 0, fmt.Errorf("writing %s: %w", path, err)
+	}
+
+	return len(runs), nil
+}
@@ -0,0 +1,38 @@
+package reportindex
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+// Cmd returns the report-index command, which writes the index of a directory of run reports.
+func Cmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "report-index <dir>",
+		Short: "Writes an html index of the run reports of a directory",
+		Long: `Writes an index.html to a directory of json run reports, such as those written with
+--report-file or by "f1 campaign --report-dir", listing every run with its result, throughput,
+error rate and latency percentiles, with links to the reports, and the trend of the p95 of each
+scenario across its runs. For example:
+
+  f1 run constant payments --rate 10/s --max-duration 1m --report-file reports/$(date +%s).json
+  f1 report-index reports`,
+		Args: cobra.ExactArgs(1),
+		RunE: indexCmdExecute,
+	}
+}
+
+func indexCmdExecute(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	runs, err := Write(args[0])
+	if err != nil {
+		return fmt.Errorf("writing report index: %w", err)
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Indexed %d runs in %s\n", runs, filepath.Join(args[0], FileName))
+
+	return nil
+}
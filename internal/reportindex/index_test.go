@@ -0,0 +1,123 @@
+package reportindex_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/reportindex"
+	"github.com/form3tech-oss/f1/v2/internal/run"
+)
+
+func writeReport(t *testing.T, path string, report any, written time.Time) {
+	t.Helper()
+
+	data, err := json.Marshal(report)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	require.NoError(t, os.Chtimes(path, written, written))
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	now := time.Now()
+
+	writeReport(t, filepath.Join(dir, "first.json"), run.Report{
+		Scenario:          "payments",
+		Duration:          10 * time.Second,
+		IterationsStarted: 100,
+		FailedIterationDurations: run.DurationsReport{
+			Count: 5,
+		},
+		SuccessfulIterationDurations: run.DurationsReport{
+			P95: 200 * time.Millisecond,
+			P99: 300 * time.Millisecond,
+		},
+		Rates: []run.Rate{
+			{Offset: 0, Target: 10, Achieved: 10},
+			{Offset: 2 * time.Second, Target: 20, Achieved: 5},
+		},
+	}, now.Add(-time.Hour))
+	writeReport(t, filepath.Join(dir, "second.json"), run.Report{
+		Scenario: "payments",
+		Failed:   true,
+		Error:    "max failures reached",
+	}, now)
+	writeReport(t, filepath.Join(dir, "campaign.json"), map[string]any{"passed": true}, now)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("notes"), 0o600))
+
+	runs, err := reportindex.Load(dir)
+	require.NoError(t, err)
+
+	require.Len(t, runs, 2)
+	assert.Equal(t, "second", runs[0].Name)
+	assert.True(t, runs[0].Failed)
+	assert.Equal(t, "max failures reached", runs[0].Error)
+
+	assert.Equal(t, "first", runs[1].Name)
+	assert.Equal(t, "first.json", runs[1].File)
+	assert.False(t, runs[1].Failed)
+	assert.InDelta(t, 5.0, runs[1].ErrorRate, 0.001)
+	assert.InDelta(t, 10.0, runs[1].Throughput, 0.001)
+	assert.Equal(t, 200*time.Millisecond, runs[1].P95)
+	assert.Equal(t, 300*time.Millisecond, runs[1].P99)
+	assert.Equal(t, "0.0,12.0 120.0,0.0", runs[1].TargetRate)
+	assert.Equal(t, "0.0,12.0 120.0,18.0", runs[1].AchievedRate)
+	assert.Empty(t, runs[0].TargetRate)
+}
+
+func TestTrends(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	runs := []reportindex.Run{
+		{Scenario: "refunds", Time: now, P95: 50 * time.Millisecond},
+		{Scenario: "payments", Time: now, P95: 100 * time.Millisecond},
+		{Scenario: "payments", Time: now.Add(-time.Hour), P95: 200 * time.Millisecond},
+	}
+
+	trends := reportindex.Trends(runs)
+
+	assert.Equal(t, []reportindex.Trend{
+		{Scenario: "payments", Runs: 2, Latest: 100 * time.Millisecond, Points: "0.0,0.0 120.0,12.0"},
+		{Scenario: "refunds", Runs: 1, Latest: 50 * time.Millisecond, Points: "60.0,0.0"},
+	}, trends)
+}
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeReport(t, filepath.Join(dir, "baseline.json"), run.Report{
+		Scenario: "payments",
+		Rates:    []run.Rate{{Target: 10, Achieved: 8}},
+	}, time.Now())
+	writeReport(t, filepath.Join(dir, "peak.json"), run.Report{Scenario: "<payments>", Failed: true}, time.Now())
+
+	indexed, err := reportindex.Write(dir)
+	require.NoError(t, err)
+	assert.Equal(t, 2, indexed)
+
+	index, err := os.ReadFile(filepath.Join(dir, reportindex.FileName))
+	require.NoError(t, err)
+	assert.Contains(t, string(index), `<a href="baseline.json">baseline</a>`)
+	assert.Contains(t, string(index), `<a href="peak.json">peak</a>`)
+	assert.Contains(t, string(index), "&lt;payments&gt;")
+	assert.Contains(t, string(index), "<polyline")
+	assert.Contains(t, string(index), `<polyline class="target" points="60.0,0.0"/>`)
+}
+
+func TestWriteWithoutReports(t *testing.T) {
+	t.Parallel()
+
+	_, err := reportindex.Write(t.TempDir())
+
+	require.ErrorIs(t, err, reportindex.ErrNoReports)
+}
@@ -0,0 +1,75 @@
+package reportindex
+
+import (
+	"html/template"
+	"strconv"
+	"time"
+)
+
+var indexTemplate = template.Must(template.New("index").Funcs(template.FuncMap{
+	"duration": func(d time.Duration) string { return d.Round(time.Millisecond).String() },
+	"percent":  func(rate float64) string { return strconv.FormatFloat(rate, 'f', 2, 64) + "%" },
+	"rate":     func(rate float64) string { return strconv.FormatFloat(rate, 'f', 1, 64) + "/s" },
+	"time":     func(t time.Time) string { return t.Format(time.RFC3339) },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>f1 runs</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { padding: 4px 12px; text-align: left; border-bottom: 1px solid #ddd; }
+td.number { text-align: right; }
+.failed { color: #c00; }
+.passed { color: #080; }
+polyline { fill: none; stroke: #06c; stroke-width: 1.5; }
+polyline.target { stroke: #999; stroke-dasharray: 3 2; }
+</style>
+</head>
+<body>
+<h1>f1 runs</h1>
+<h2>Scenarios</h2>
+<table>
+<tr><th>Scenario</th><th>Runs</th><th>p95 trend</th><th>Latest p95</th></tr>
+{{- range .Trends}}
+<tr>
+<td>{{.Scenario}}</td>
+<td class="number">{{.Runs}}</td>
+<td><svg width="{{$.Width}}" height="{{$.Height}}"><polyline points="{{.Points}}"/></svg></td>
+<td class="number">{{duration .Latest}}</td>
+</tr>
+{{- end}}
+</table>
+<h2>Runs</h2>
+<table>
+<tr><th>Run</th><th>Scenario</th><th>Finished</th><th>Result</th><th>Duration</th><th>Started</th>
+<th>Throughput</th><th>Target and achieved rate</th><th>Error rate</th><th>p95</th><th>p99</th></tr>
+{{- range .Runs}}
+<tr>
+<td><a href="{{.File}}">{{.Name}}</a></td>
+<td>{{.Scenario}}</td>
+<td>{{time .Time}}</td>
+{{- if .Failed}}
+<td class="failed" title="{{.Error}}">failed</td>
+{{- else}}
+<td class="passed">passed</td>
+{{- end}}
+<td class="number">{{duration .Duration}}</td>
+<td class="number">{{.Started}}</td>
+<td class="number">{{rate .Throughput}}</td>
+{{- if .TargetRate}}
+<td><svg width="{{$.Width}}" height="{{$.Height}}"><polyline class="target" points="{{.TargetRate}}"/>
+<polyline points="{{.AchievedRate}}"/></svg></td>
+{{- else}}
+<td></td>
+{{- end}}
+<td class="number">{{percent .ErrorRate}}</td>
+<td class="number">{{duration .P95}}</td>
+<td class="number">{{duration .P99}}</td>
+</tr>
+{{- end}}
+</table>
+</body>
+</html>
+`))
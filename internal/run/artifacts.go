@@ -0,0 +1,96 @@
+package run
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/artifacts"
+	"github.com/form3tech-oss/f1/v2/internal/run/views"
+)
+
+const artifactsTimeFormat = "20060102T150405Z"
+
+type artifact struct {
+	name string
+	data []byte
+}
+
+// UploadArtifacts uploads the json and html reports, the log file, the failure snapshots and the
+// profiles of the run to the store, under a directory named after the scenario and the time of the
+// upload. The artifacts but the profiles, which are binary, are redacted before they are uploaded.
+// It returns the urls of the uploaded artifacts.
+func (r *Result) UploadArtifacts(ctx context.Context, store *artifacts.Store, now time.Time) ([]string, error) {
+	report := r.Report()
+
+	reportData, err := json.Marshal(report)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling report: %w", err)
+	}
+	htmlData, err := report.HTML()
+	if err != nil {
+		return nil, fmt.Errorf("rendering html report: %w", err)
+	}
+	files := []artifact{
+		{name: "report.json", data: r.redactor.Bytes(reportData)},
+		{name: htmlReportName, data: r.redactor.Bytes(htmlData)},
+	}
+
+	if r.LogFilePath != "" {
+		logData, err := os.ReadFile(r.LogFilePath)
+		if err != nil {
+			return nil, fmt.Errorf("reading log file: %w", err)
+		}
+		files = append(files, artifact{name: filepath.Base(r.LogFilePath), data: r.redactor.Bytes(logData)})
+	}
+
+	for _, snapshotDir := range r.FailureSnapshots() {
+		entries, err := os.ReadDir(snapshotDir)
+		if err != nil {
+			return nil, fmt.Errorf("reading failure snapshot: %w", err)
+		}
+		for _, entry := range entries {
+			data, err := os.ReadFile(filepath.Join(snapshotDir, entry.Name()))
+			if err != nil {
+				return nil, fmt.Errorf("reading failure snapshot: %w", err)
+			}
+			name := path.Join("snapshots", filepath.Base(snapshotDir), entry.Name())
+			files = append(files, artifact{name: name, data: r.redactor.Bytes(data)})
+		}
+	}
+
+	if profilesDir := r.Profiles(); profilesDir != "" {
+		entries, err := os.ReadDir(profilesDir)
+		if err != nil {
+			return nil, fmt.Errorf("reading profiles: %w", err)
+		}
+		for _, entry := range entries {
+			data, err := os.ReadFile(filepath.Join(profilesDir, entry.Name()))
+			if err != nil {
+				return nil, fmt.Errorf("reading profiles: %w", err)
+			}
+			files = append(files, artifact{name: path.Join("profiles", entry.Name()), data: data})
+		}
+	}
+
+	dir := path.Join(report.Scenario, now.UTC().Format(artifactsTimeFormat))
+	locations := make([]string, 0, len(files))
+	for _, file := range files {
+		location, err := store.Upload(ctx, path.Join(dir, file.name), file.data)
+		if err != nil {
+			return locations, fmt.Errorf("uploading artifacts: %w", err)
+		}
+		locations = append(locations, location)
+	}
+
+	return locations, nil
+}
+
+// Artifacts returns the urls of the uploaded artifacts of the run, shown after its summary.
+func (r *Result) Artifacts(locations []string) *views.ViewContext[views.ArtifactsData] {
+	return r.views.Artifacts(views.ArtifactsData{URLs: locations})
+}
@@ -0,0 +1,49 @@
+package run
+
+import (
+	"fmt"
+
+	"github.com/form3tech-oss/f1/v2/internal/audit"
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+)
+
+// openAuditLog opens the audit log option, if set, and records all the iterations of the run in it.
+func (r *Run) openAuditLog() error {
+	if r.options.AuditLog == "" {
+		return nil
+	}
+
+	log, err := audit.Open(r.options.AuditLog, audit.BufferSize)
+	if err != nil {
+		return fmt.Errorf("opening audit log: %w", err)
+	}
+	r.auditLog = log
+	r.activeScenario.RecordAudit(log)
+
+	return nil
+}
+
+// closeAuditLog writes the iterations still buffered to the audit log, and records the iterations
+// which couldn't be written in the result, as the log is then incomplete.
+func (r *Run) closeAuditLog() {
+	if r.auditLog == nil {
+		return
+	}
+	log := r.auditLog
+	r.auditLog = nil
+
+	if err := log.Close(); err != nil {
+		r.result.AddError(err)
+		r.output.Display(ui.ErrorMessage{Message: "unable to write the audit log", Error: err})
+		return
+	}
+
+	dropped := log.Dropped()
+	r.result.RecordAuditLogDropped(dropped)
+	if dropped > 0 {
+		r.output.Display(ui.WarningMessage{Message: fmt.Sprintf(
+			"%d iterations are missing from the audit log, as it couldn't be written fast enough", dropped)})
+	}
+
+	r.output.Display(ui.InfoMessage{Message: "Audit log of the iterations written to " + log.Path()})
+}
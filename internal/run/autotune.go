@@ -0,0 +1,128 @@
+package run
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+	"github.com/form3tech-oss/f1/v2/internal/workers"
+)
+
+const (
+	autotuneInterval = time.Second
+	// autotuneHeadroom is the concurrency kept above the concurrency required by Little's law, as
+	// iterations don't start evenly and their latency varies
+	autotuneHeadroom = 1.25
+)
+
+// concurrencyDecision is the concurrency set by the autotune of a run, from the rate iterations
+// were triggered at and their latency over the latest interval.
+type concurrencyDecision struct {
+	Rate    float64
+	Latency time.Duration
+	// Required is the concurrency required by Little's law with headroom, which Workers are
+	// capped at the max concurrency of the run
+	Required int
+	Workers  int
+}
+
+var _ ui.Outputable = (*concurrencyDecision)(nil)
+
+func (d concurrencyDecision) Print(printer *ui.Printer) {
+	message := fmt.Sprintf("Autotuned concurrency to %d workers (%s/s x %s latency)",
+		d.Workers, formatRate(d.Rate), d.Latency.Round(time.Millisecond))
+	if d.capped() {
+		printer.Warn(fmt.Sprintf("%s, capped at the max concurrency, %d workers are required", message, d.Required))
+		return
+	}
+
+	printer.Println(message)
+}
+
+// capped returns true if the workers are capped at the max concurrency of the run.
+func (d concurrencyDecision) capped() bool {
+	return d.Required > d.Workers
+}
+
+func (d concurrencyDecision) Log(logger *slog.Logger) {
+	logger.Info("autotuned concurrency",
+		slog.Int("workers", d.Workers),
+		slog.Int("required", d.Required),
+		slog.Float64("rate", d.Rate),
+		slog.Duration("latency", d.Latency),
+	)
+}
+
+// requiredConcurrency returns the concurrency needed to start iterations at rate per second when
+// they take latency to complete, by Little's law, with headroom.
+func requiredConcurrency(rate float64, latency time.Duration) int {
+	return max(int(math.Ceil(rate*latency.Seconds()*autotuneHeadroom)), 1)
+}
+
+// autotuneConcurrency adjusts the workers of the run taking iterations every autotuneInterval to
+// the concurrency required by the rate iterations are triggered at and their latency, up to the
+// concurrency of the run. Intervals without completed iterations leave the concurrency unchanged,
+// and intervals where iterations were dropped double it, never to be lowered again below that.
+func (r *Run) autotuneConcurrency(ctx context.Context, poolManager *workers.PoolManager) {
+	if !r.options.AutotuneConcurrency {
+		return
+	}
+
+	ticker := time.NewTicker(autotuneInterval)
+	defer ticker.Stop()
+
+	stats := r.result.progressStats
+	workers := r.options.Concurrency
+	capped := false
+	// floor is the concurrency the workers are kept at once fewer workers dropped iterations
+	floor := 0
+	last := time.Now()
+	triggered := stats.Triggered()
+	dropped := stats.Dropped()
+	completed, duration := stats.Completed()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			nowTriggered := stats.Triggered()
+			nowCompleted, nowDuration := stats.Completed()
+			nowDropped := stats.Dropped()
+			if nowCompleted == completed {
+				last, triggered, dropped = now, nowTriggered, nowDropped
+				continue
+			}
+
+			rate := float64(nowTriggered-triggered) / now.Sub(last).Seconds()
+			latency := (nowDuration - duration) / time.Duration(nowCompleted-completed)
+			decision := concurrencyDecision{Rate: rate, Latency: latency, Required: requiredConcurrency(rate, latency)}
+			if nowDropped > dropped {
+				// the workers were too few for the iterations triggered, whatever their latency, such as
+				// when iterations are triggered in bursts
+				floor = min(2*workers, r.options.Concurrency)
+			}
+			decision.Workers = min(max(decision.Required, floor), r.options.Concurrency)
+			last, triggered, dropped = now, nowTriggered, nowDropped
+			completed, duration = nowCompleted, nowDuration
+
+			// the decision is displayed when the workers change, or the max concurrency starts or stops
+			// capping them
+			if decision.Workers == workers && decision.capped() == capped {
+				continue
+			}
+			workers, capped = decision.Workers, decision.capped()
+			poolManager.SetActiveWorkers(workers)
+			r.tracer.Event("concurrency autotuned",
+				slog.Int("workers", workers),
+				slog.Int("required", decision.Required),
+				slog.Float64("rate", rate),
+				slog.Duration("latency", latency),
+			)
+			r.output.Display(decision)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package run
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/trigger/api"
+	"github.com/form3tech-oss/f1/v2/internal/trigger/rate"
+	"github.com/form3tech-oss/f1/v2/internal/triggerflags"
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+	"github.com/form3tech-oss/f1/v2/pkg/f1/scenarios"
+)
+
+var errNotConfirmed = errors.New("the run was not confirmed")
+
+// blastRadius is the load a run plans to apply, and the reasons it must be confirmed before it
+// starts.
+type blastRadius struct {
+	Scenario    string
+	Environment string
+	Duration    time.Duration
+	Concurrency int
+	// PeakRate is the most iterations the trigger plans to start in a second, 0 if it doesn't tell
+	PeakRate int
+	Reasons  []string
+}
+
+var _ ui.Outputable = (*blastRadius)(nil)
+
+// newBlastRadius returns the load planned by a run of the scenario. The peak rate is only planned
+// against aboveRate, the rate in iterations per second above which runs must be confirmed, if set,
+// with a new trigger from builder so that the rates of the trigger of the run are left unstarted.
+func newBlastRadius(
+	scenario *scenarios.Scenario,
+	builder api.Builder,
+	newTrigger func() (*api.Trigger, error),
+	duration time.Duration,
+	concurrency int,
+	aboveRate float64,
+) (blastRadius, error) {
+	radius := blastRadius{Duration: duration, Concurrency: concurrency}
+	if scenario != nil {
+		radius.Scenario = scenario.Name
+		radius.Environment = scenario.Annotations[scenarios.AnnotationEnvironment]
+		if scenario.IsProduction() {
+			radius.Reasons = append(radius.Reasons, "the scenario targets "+radius.Environment)
+		}
+	}
+
+	if aboveRate > 0 {
+		planned, err := newTrigger()
+		if err != nil {
+			return radius, fmt.Errorf("planning the peak rate: %w", err)
+		}
+		if planned.PeakRate != nil {
+			radius.PeakRate = planned.PeakRate(duration)
+		}
+		if float64(radius.PeakRate) > aboveRate {
+			radius.Reasons = append(radius.Reasons,
+				fmt.Sprintf("the peak rate exceeds %s/s", formatRate(aboveRate)))
+		}
+		if planned.PeakRate == nil {
+			radius.Reasons = append(radius.Reasons,
+				fmt.Sprintf("the peak rate of %s can't be planned", builder.Name))
+		}
+	}
+
+	return radius, nil
+}
+
+// Confirm returns true if the run must be confirmed before it starts.
+func (b blastRadius) Confirm() bool {
+	return len(b.Reasons) > 0
+}
+
+func (b blastRadius) Print(printer *ui.Printer) {
+	lines := []string{
+		"Blast radius of the run - " + strings.Join(b.Reasons, ", "),
+		"  scenario:    " + b.Scenario,
+	}
+	if b.Environment != "" {
+		lines = append(lines, "  environment: "+b.Environment)
+	}
+	if b.PeakRate > 0 {
+		lines = append(lines, fmt.Sprintf("  peak rate:   %d/s", b.PeakRate))
+	}
+	lines = append(lines,
+		"  duration:    "+b.Duration.String(),
+		fmt.Sprintf("  concurrency: %d", b.Concurrency),
+	)
+
+	printer.Warn(strings.Join(lines, "\n"))
+}
+
+func (b blastRadius) Log(logger *slog.Logger) {
+	logger.Warn("Blast radius of the run",
+		slog.String("scenario", b.Scenario),
+		slog.String("environment", b.Environment),
+		slog.Int("peak_rate", b.PeakRate),
+		slog.Duration("duration", b.Duration),
+		slog.Int("concurrency", b.Concurrency),
+		slog.Any("reasons", b.Reasons),
+	)
+}
+
+// confirmRun shows the blast radius of a run which must be confirmed, and asks for confirmation on
+// in when output is interactive, unless the run was confirmed with --yes.
+func confirmRun(radius blastRadius, yes bool, in io.Reader, output *ui.Output) error {
+	if !radius.Confirm() {
+		return nil
+	}
+
+	output.Display(radius)
+	if yes {
+		output.Display(ui.InfoMessage{Message: "Run confirmed with --yes"})
+		return nil
+	}
+	if !output.Interactive {
+		return fmt.Errorf("%w: confirm it with --yes", errNotConfirmed)
+	}
+
+	output.Display(ui.InteractiveMessage{Message: "Start the run? [y/N]"})
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("reading confirmation: %w", err)
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return nil
+	default:
+		return errNotConfirmed
+	}
+}
+
+// parseConfirmAbove parses the rate of --confirm-above into iterations per second, 0 if unset.
+func parseConfirmAbove(confirmAbove string) (float64, error) {
+	if confirmAbove == "" {
+		return 0, nil
+	}
+
+	iterations, unit, err := rate.ParseRate(confirmAbove)
+	if err != nil {
+		return 0, fmt.Errorf("parsing --%s: %w", triggerflags.FlagConfirmAbove, err)
+	}
+
+	return float64(iterations) / unit.Seconds(), nil
+}
+
+func formatRate(perSecond float64) string {
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.2f", perSecond), "0"), ".")
+}
@@ -0,0 +1,83 @@
+package run
+
+import (
+	"runtime/debug"
+)
+
+const (
+	// develVersion is the version of main modules built from a working copy rather than a module
+	// version, which doesn't identify the code
+	develVersion = "(devel)"
+
+	// BuildVersionLabel and BuildRevisionLabel group the metrics pushed to the push gateway by the
+	// build of the scenario binary
+	BuildVersionLabel  = "build_version"
+	BuildRevisionLabel = "build_revision"
+)
+
+// Build identifies the test code of a run, the main module of the scenario binary, so that the
+// results of the run can be tied to the version of the scenarios which produced them.
+type Build struct {
+	// Module is the path of the main module of the scenario binary
+	Module string `json:"module,omitempty"`
+	// Version is the version of the main module, when built from a module version such as v1.2.3
+	Version string `json:"version,omitempty"`
+	// Revision is the VCS revision the scenario binary was built from
+	Revision string `json:"revision,omitempty"`
+	// Modified is set when the working copy had changes not committed in Revision
+	Modified bool `json:"modified,omitempty"`
+}
+
+// ReadBuild returns the build of the running scenario binary, or nil if the binary wasn't built
+// with module support.
+func ReadBuild() *Build {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return nil
+	}
+
+	return NewBuild(info)
+}
+
+// NewBuild returns the build of the main module of info, or nil if it has neither a version nor a
+// VCS revision.
+func NewBuild(info *debug.BuildInfo) *Build {
+	build := &Build{Module: info.Main.Path}
+	if info.Main.Version != develVersion {
+		build.Version = info.Main.Version
+	}
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			build.Revision = setting.Value
+		case "vcs.modified":
+			build.Modified = setting.Value == "true"
+		}
+	}
+
+	if build.Version == "" && build.Revision == "" {
+		return nil
+	}
+
+	return build
+}
+
+// labels returns the labels grouping the metrics of the build.
+func (b *Build) labels() map[string]string {
+	labels := map[string]string{}
+	if b == nil {
+		return labels
+	}
+	if b.Version != "" {
+		labels[BuildVersionLabel] = b.Version
+	}
+	if b.Revision != "" {
+		revision := b.Revision
+		if b.Modified {
+			revision += "-modified"
+		}
+		labels[BuildRevisionLabel] = revision
+	}
+
+	return labels
+}
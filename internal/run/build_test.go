@@ -0,0 +1,49 @@
+package run_test
+
+import (
+	"runtime/debug"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/form3tech-oss/f1/v2/internal/run"
+)
+
+func TestNewBuild(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name     string
+		info     *debug.BuildInfo
+		expected *run.Build
+	}{
+		{
+			name:     "module version",
+			info:     &debug.BuildInfo{Main: debug.Module{Path: "example.com/loadtests", Version: "v1.2.3"}},
+			expected: &run.Build{Module: "example.com/loadtests", Version: "v1.2.3"},
+		},
+		{
+			name: "vcs revision of a working copy",
+			info: &debug.BuildInfo{
+				Main: debug.Module{Path: "example.com/loadtests", Version: "(devel)"},
+				Settings: []debug.BuildSetting{
+					{Key: "vcs", Value: "git"},
+					{Key: "vcs.revision", Value: "4c1d2e3f"},
+					{Key: "vcs.modified", Value: "true"},
+				},
+			},
+			expected: &run.Build{Module: "example.com/loadtests", Revision: "4c1d2e3f", Modified: true},
+		},
+		{
+			name:     "neither version nor revision",
+			info:     &debug.BuildInfo{Main: debug.Module{Path: "example.com/loadtests", Version: "(devel)"}},
+			expected: nil,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, run.NewBuild(test.info))
+		})
+	}
+}
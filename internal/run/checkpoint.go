@@ -0,0 +1,168 @@
+package run
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+)
+
+// checkpointInterval is how often the progress of a resumable run is saved.
+const checkpointInterval = 5 * time.Second
+
+// Checkpoint records the progress of a run, so that an interrupted run can be resumed from
+// where it stopped.
+type Checkpoint struct {
+	Scenario          string        `json:"scenario"`
+	Elapsed           time.Duration `json:"elapsed"`
+	IterationsStarted uint64        `json:"iterations_started"`
+	// UniqueIterations is set for runs which number their iterations uniquely across resumes, by
+	// reserving the numbers up to IterationsReserved before starting them
+	UniqueIterations   bool   `json:"unique_iterations,omitempty"`
+	IterationsReserved uint64 `json:"iterations_reserved,omitempty"`
+	// LastIteration is the highest number of the iterations started, for unique iterations
+	LastIteration uint64 `json:"last_iteration,omitempty"`
+	// IterationGaps are the numbers skipped when the run was resumed, for unique iterations
+	IterationGaps []IterationGap `json:"iteration_gaps,omitempty"`
+}
+
+// ReadCheckpoint reads the checkpoint saved at path. It returns false if there is no checkpoint.
+func ReadCheckpoint(path string) (Checkpoint, bool, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Checkpoint{}, false, nil
+	}
+	if err != nil {
+		return Checkpoint{}, false, fmt.Errorf("reading checkpoint: %w", err)
+	}
+
+	checkpoint := Checkpoint{}
+	if err := json.Unmarshal(content, &checkpoint); err != nil {
+		return Checkpoint{}, false, fmt.Errorf("parsing checkpoint: %w", err)
+	}
+
+	return checkpoint, true, nil
+}
+
+// WriteCheckpoint saves the checkpoint at path, replacing any previous checkpoint.
+func WriteCheckpoint(path string, checkpoint Checkpoint) error {
+	content, err := json.MarshalIndent(checkpoint, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding checkpoint: %w", err)
+	}
+
+	// write to a temporary file first so that an interruption never leaves a partial checkpoint
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return fmt.Errorf("creating checkpoint: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing checkpoint: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing checkpoint: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("replacing checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+// resume restores the progress of a previous run from the checkpoint in the state file, reducing
+// the limits of the run by the progress already made.
+func (r *Run) resume() error {
+	checkpoint, ok, err := ReadCheckpoint(r.options.StateFile)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		r.checkpoint = Checkpoint{Scenario: r.options.Scenario, UniqueIterations: r.options.UniqueIterations}
+		return nil
+	}
+
+	if checkpoint.Scenario != r.options.Scenario {
+		return fmt.Errorf("checkpoint %s belongs to scenario %s", r.options.StateFile, checkpoint.Scenario)
+	}
+	if checkpoint.Elapsed >= r.options.MaxDuration ||
+		(r.trigger.Duration > 0 && checkpoint.Elapsed >= r.trigger.Duration) ||
+		(r.options.MaxIterations > 0 && checkpoint.IterationsStarted >= r.options.MaxIterations) {
+		return fmt.Errorf("run in checkpoint %s has already completed", r.options.StateFile)
+	}
+	if err := r.resumeUniqueIterations(&checkpoint); err != nil {
+		return err
+	}
+
+	r.checkpoint = checkpoint
+	r.iterationsReserved = checkpoint.IterationsReserved
+	r.result.RecordIterationGaps(checkpoint.IterationGaps)
+	r.options.Elapsed = checkpoint.Elapsed
+	r.options.MaxDuration -= checkpoint.Elapsed
+	if r.options.MaxIterations > 0 {
+		r.options.MaxIterations -= checkpoint.IterationsStarted
+	}
+
+	return nil
+}
+
+// saveCheckpoint saves the progress of the run, including the progress of the resumed runs.
+func (r *Run) saveCheckpoint() {
+	if r.options.StateFile == "" {
+		return
+	}
+
+	r.checkpointMu.Lock()
+	defer r.checkpointMu.Unlock()
+
+	if err := WriteCheckpoint(r.options.StateFile, r.currentCheckpoint()); err != nil {
+		r.output.Display(ui.ErrorMessage{Message: "unable to save checkpoint", Error: err})
+	}
+}
+
+// currentCheckpoint returns the progress of the run, including the progress of the resumed runs.
+// It must be called with checkpointMu held, as the checkpoint is also saved by the reservations of
+// unique iterations.
+func (r *Run) currentCheckpoint() Checkpoint {
+	r.result.mu.RLock()
+	elapsed := r.result.duration()
+	r.result.mu.RUnlock()
+	total := r.result.progressStats.Total()
+
+	checkpoint := Checkpoint{
+		Scenario:          r.options.Scenario,
+		Elapsed:           r.checkpoint.Elapsed + elapsed,
+		IterationsStarted: r.checkpoint.IterationsStarted + total.IterationsStarted(),
+	}
+	if r.options.UniqueIterations {
+		checkpoint.UniqueIterations = true
+		checkpoint.IterationsReserved = r.iterationsReserved
+		checkpoint.LastIteration = r.checkpoint.LastIteration
+		if poolManager := r.poolManager.Load(); poolManager != nil {
+			checkpoint.LastIteration = poolManager.LastIteration()
+		}
+		checkpoint.IterationGaps = r.checkpoint.IterationGaps
+	}
+
+	return checkpoint
+}
+
+// completeCheckpoint removes the state file once the run has completed, so that it is not
+// resumed again.
+func (r *Run) completeCheckpoint() {
+	if r.options.StateFile == "" {
+		return
+	}
+
+	if err := os.Remove(r.options.StateFile); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		r.output.Display(ui.ErrorMessage{Message: "unable to remove checkpoint", Error: err})
+	}
+}
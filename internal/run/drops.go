@@ -0,0 +1,87 @@
+package run
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/run/views"
+)
+
+// Drop counts the iterations dropped, because all the workers were busy, which were planned to
+// start in a second of the run.
+type Drop struct {
+	// Offset is the start of the second of the run the iterations were planned to start in
+	Offset time.Duration `json:"offset"`
+	// Stage is the name of the stage planned at Offset, for triggers which run in stages
+	Stage string `json:"stage,omitempty"`
+	Count uint64 `json:"count"`
+}
+
+// drops returns the dropped iterations of the latest snapshot, with the stage they were planned in.
+// The offsets of resumed runs include the duration of the run before it was resumed, as for stages.
+func (r *Result) drops() []Drop {
+	if len(r.snapshot.Drops) == 0 {
+		return nil
+	}
+
+	drops := make([]Drop, 0, len(r.snapshot.Drops))
+	for _, dropped := range r.snapshot.Drops {
+		drop := Drop{Offset: r.runOptions.Elapsed + dropped.Offset, Count: dropped.Count}
+		if r.stageAt != nil {
+			drop.Stage = r.stageAt(drop.Offset)
+		}
+		drops = append(drops, drop)
+	}
+
+	return drops
+}
+
+// Drops returns a summary of when the dropped iterations were planned to start, by stage.
+func (r *Result) Drops() *views.ViewContext[views.DropsData] {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	data := views.DropsData{Quotas: r.quotaDropsData()}
+	for _, drop := range r.drops() {
+		last := len(data.Stages) - 1
+		if last >= 0 && data.Stages[last].Stage == drop.Stage {
+			data.Stages[last].Count += drop.Count
+			data.Stages[last].To = drop.Offset + time.Second
+			continue
+		}
+		data.Stages = append(data.Stages, views.StageDrops{
+			Stage: drop.Stage,
+			Count: drop.Count,
+			From:  drop.Offset,
+			To:    drop.Offset + time.Second,
+		})
+	}
+
+	return r.views.Drops(data)
+}
+
+// combineDrops sums the dropped iterations of runs planned in the same second and stage.
+func combineDrops(a, b []Drop) []Drop {
+	type key struct {
+		offset time.Duration
+		stage  string
+	}
+	counts := map[key]uint64{}
+	for _, drop := range slices.Concat(a, b) {
+		counts[key{offset: drop.Offset, stage: drop.Stage}] += drop.Count
+	}
+	if len(counts) == 0 {
+		return nil
+	}
+
+	combined := make([]Drop, 0, len(counts))
+	for k, count := range counts {
+		combined = append(combined, Drop{Offset: k.offset, Stage: k.stage, Count: count})
+	}
+	slices.SortFunc(combined, func(a, b Drop) int {
+		return cmp.Or(cmp.Compare(a.Offset, b.Offset), cmp.Compare(a.Stage, b.Stage))
+	})
+
+	return combined
+}
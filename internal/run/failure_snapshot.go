@@ -0,0 +1,171 @@
+package run
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime/pprof"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/progress"
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+)
+
+const (
+	// DefaultSnapshotDir is the directory failure snapshots are written to when no directory is given.
+	DefaultSnapshotDir = "f1-snapshots"
+	// maxFailureSnapshots bounds the snapshots of a run whose failure rate keeps crossing the threshold
+	maxFailureSnapshots = 5
+	snapshotLogLines    = 100
+	// snapshotLogTailSize bounds how much of the log file is read to find its last lines
+	snapshotLogTailSize = 64 * 1024
+)
+
+// failureSnapshots tracks the failure rate between progress updates, to take a snapshot when it
+// crosses the threshold of the run.
+type failureSnapshots struct {
+	previous progress.Snapshot
+	dirs     []string
+	// crossed is set while the failure rate is above the threshold, so that a single snapshot is
+	// taken each time the threshold is crossed
+	crossed bool
+}
+
+type failureSnapshot struct {
+	Time      time.Time `json:"time"`
+	Progress  Progress  `json:"progress"`
+	Threshold int       `json:"threshold"`
+	// FailureRate is the percentage of iterations which failed since the previous progress update
+	FailureRate float64 `json:"failure_rate"`
+}
+
+// checkFailureRate writes a diagnostic snapshot of the run when the failure rate since the
+// previous progress update crosses the snapshot failure rate option. It is called on every
+// progress update.
+func (r *Run) checkFailureRate() {
+	if r.options.SnapshotFailureRate == 0 {
+		return
+	}
+
+	snapshot := r.result.Snapshot()
+	previous := r.failureSnapshots.previous
+	r.failureSnapshots.previous = snapshot
+
+	failed := snapshot.FailedIterationDurations.Count - previous.FailedIterationDurations.Count
+	successful := snapshot.SuccessfulIterationDurations.Count - previous.SuccessfulIterationDurations.Count
+	if failed+successful == 0 {
+		return
+	}
+
+	failureRate := 100 * float64(failed) / float64(failed+successful)
+	if failureRate < float64(r.options.SnapshotFailureRate) {
+		r.failureSnapshots.crossed = false
+		return
+	}
+	if r.failureSnapshots.crossed || len(r.failureSnapshots.dirs) >= maxFailureSnapshots {
+		return
+	}
+	r.failureSnapshots.crossed = true
+
+	dir, err := r.writeFailureSnapshot(failureRate, time.Now())
+	if err != nil {
+		r.output.Display(ui.ErrorMessage{Message: "unable to write failure snapshot", Error: err})
+		return
+	}
+
+	r.failureSnapshots.dirs = append(r.failureSnapshots.dirs, dir)
+	r.result.AddFailureSnapshot(dir)
+	r.output.Display(ui.WarningMessage{
+		Message: fmt.Sprintf("Failure rate of %.1f%% crossed %d%%, diagnostic snapshot written to %s",
+			failureRate, r.options.SnapshotFailureRate, dir),
+	})
+}
+
+// writeFailureSnapshot writes the progress of the run, a dump of its goroutines and the last lines
+// of its log file to a new directory of the snapshot directory.
+func (r *Run) writeFailureSnapshot(failureRate float64, now time.Time) (string, error) {
+	dir := filepath.Join(r.options.SnapshotDir, fmt.Sprintf("%s-%s-%d",
+		r.options.Scenario, now.UTC().Format(artifactsTimeFormat), len(r.failureSnapshots.dirs)+1))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("creating snapshot directory: %w", err)
+	}
+
+	progressData, err := json.MarshalIndent(failureSnapshot{
+		Time:        now,
+		Progress:    r.Progress(),
+		Threshold:   r.options.SnapshotFailureRate,
+		FailureRate: failureRate,
+	}, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshalling progress: %w", err)
+	}
+	progressData = r.result.redactor.Bytes(progressData)
+	if err := os.WriteFile(filepath.Join(dir, "progress.json"), progressData, 0o600); err != nil {
+		return "", fmt.Errorf("writing progress: %w", err)
+	}
+
+	var goroutines bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&goroutines, 2); err != nil {
+		return "", fmt.Errorf("dumping goroutines: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "goroutines.txt"), goroutines.Bytes(), 0o600); err != nil {
+		return "", fmt.Errorf("writing goroutines: %w", err)
+	}
+
+	// verbose runs log to stdout rather than to a file
+	if logFilePath := r.scenarioLogger.FilePath(); logFilePath != "" {
+		logs, err := lastLines(logFilePath, snapshotLogLines)
+		if err != nil {
+			return "", fmt.Errorf("reading log file: %w", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "logs.txt"), r.result.redactor.Bytes(logs), 0o600); err != nil {
+			return "", fmt.Errorf("writing logs: %w", err)
+		}
+	}
+
+	return dir, nil
+}
+
+// lastLines returns the last n complete lines of the file, reading at most snapshotLogTailSize bytes.
+func lastLines(path string, n int) ([]byte, error) {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("opening file: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("reading file info: %w", err)
+	}
+
+	offset := max(info.Size()-snapshotLogTailSize, 0)
+	data, err := io.ReadAll(io.NewSectionReader(f, offset, info.Size()-offset))
+	if err != nil {
+		return nil, fmt.Errorf("reading file: %w", err)
+	}
+
+	// drop the partial first line of the tail, and the partial last line still being written
+	if offset > 0 {
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			data = data[i+1:]
+		}
+	}
+	if i := bytes.LastIndexByte(data, '\n'); i >= 0 {
+		data = data[:i+1]
+	} else {
+		data = nil
+	}
+
+	lines := bytes.SplitAfter(data, []byte("\n"))
+	// SplitAfter returns an empty last element after the final new line
+	lines = lines[:len(lines)-1]
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+
+	return bytes.Join(lines, nil), nil
+}
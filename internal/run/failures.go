@@ -0,0 +1,167 @@
+package run
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/progress"
+	"github.com/form3tech-oss/f1/v2/internal/run/views"
+)
+
+// Failure is a failed iteration, one of the first or the last failed iterations of the run.
+type Failure struct {
+	Time time.Time `json:"time"`
+	// Iteration is the number of the iteration
+	Iteration string `json:"iteration"`
+	// Error summarises why the iteration failed
+	Error string `json:"error"`
+	// Offset is when the iteration failed, from the start of the run
+	Offset time.Duration `json:"offset"`
+}
+
+// FailureCategoryReport are the durations of the iterations which failed in a category.
+type FailureCategoryReport struct {
+	Category  string          `json:"category"`
+	Durations DurationsReport `json:"durations"`
+}
+
+// failures returns the first and the last failed iterations of the latest snapshot. The offsets of
+// resumed runs include the duration of the run before it was resumed, as for drops.
+func (r *Result) failures() ([]Failure, []Failure) {
+	return r.newFailures(r.snapshot.FirstFailures), r.newFailures(r.snapshot.LastFailures)
+}
+
+func (r *Result) newFailures(failed []progress.Failure) []Failure {
+	if len(failed) == 0 {
+		return nil
+	}
+
+	failures := make([]Failure, 0, len(failed))
+	for _, failure := range failed {
+		failures = append(failures, Failure{
+			Time:      failure.Time,
+			Iteration: failure.Iteration,
+			Error:     r.redactor.String(failure.Error),
+			Offset:    r.runOptions.Elapsed + failure.Time.Sub(r.startTime),
+		})
+	}
+
+	return failures
+}
+
+// failureCategories returns the failure categories of the latest snapshot, by decreasing number
+// of failures.
+func (r *Result) failureCategories() []FailureCategoryReport {
+	if len(r.snapshot.FailureCategories) == 0 {
+		return nil
+	}
+
+	categories := make([]FailureCategoryReport, 0, len(r.snapshot.FailureCategories))
+	for _, category := range r.snapshot.FailureCategories {
+		categories = append(categories, FailureCategoryReport{
+			Category:  r.redactor.String(category.Category),
+			Durations: newDurationsReport(category.Durations),
+		})
+	}
+
+	return categories
+}
+
+func (r *Result) hasFailures() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.snapshot.FirstFailures) > 0
+}
+
+// Failures returns the first and the last failed iterations, to tell when iterations started and
+// stopped failing.
+func (r *Result) Failures() *views.ViewContext[views.FailuresData] {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	first, last := r.failures()
+
+	return r.views.Failures(views.FailuresData{
+		Categories: viewFailureCategories(r.failureCategories()),
+		First:      viewFailures(first),
+		Last:       viewFailures(last),
+		Isolated:   r.isolatedFailures(),
+	})
+}
+
+func viewFailureCategories(categories []FailureCategoryReport) []views.FailureCategory {
+	if len(categories) == 0 {
+		return nil
+	}
+
+	viewed := make([]views.FailureCategory, 0, len(categories))
+	for _, category := range categories {
+		viewed = append(viewed, views.FailureCategory{
+			Category: category.Category,
+			Count:    category.Durations.Count,
+			P95:      category.Durations.P95,
+		})
+	}
+
+	return viewed
+}
+
+func viewFailures(failures []Failure) []views.Failure {
+	if len(failures) == 0 {
+		return nil
+	}
+
+	viewed := make([]views.Failure, 0, len(failures))
+	for _, failure := range failures {
+		viewed = append(viewed, views.Failure{
+			Time:      failure.Time,
+			Iteration: failure.Iteration,
+			Error:     failure.Error,
+			Offset:    failure.Offset.Round(time.Millisecond),
+		})
+	}
+
+	return viewed
+}
+
+// combineFailureCategories merges the failure categories of runs, by decreasing number of failures.
+func combineFailureCategories(a, b []FailureCategoryReport) []FailureCategoryReport {
+	combined := slices.Clone(a)
+
+	for _, category := range b {
+		i := slices.IndexFunc(combined, func(c FailureCategoryReport) bool { return c.Category == category.Category })
+		if i < 0 {
+			combined = append(combined, category)
+			continue
+		}
+		combined[i].Durations = combined[i].Durations.combine(category.Durations)
+	}
+
+	slices.SortStableFunc(combined, func(a, b FailureCategoryReport) int {
+		return cmp.Compare(b.Durations.Count, a.Durations.Count)
+	})
+
+	return combined
+}
+
+// combineFailures keeps the first and the last failed iterations of runs, by the time they failed.
+func combineFailures(first, last []Failure, report Report) ([]Failure, []Failure) {
+	failures := slices.Concat(first, last, report.FirstFailures, report.LastFailures)
+	if len(failures) == 0 {
+		return nil, nil
+	}
+
+	slices.SortStableFunc(failures, func(a, b Failure) int {
+		return a.Time.Compare(b.Time)
+	})
+
+	kept := min(len(failures), progress.FailuresKept)
+	first, failures = failures[:kept], failures[kept:]
+	if len(failures) == 0 {
+		return first, nil
+	}
+
+	return first, failures[max(len(failures)-progress.FailuresKept, 0):]
+}
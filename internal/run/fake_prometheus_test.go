@@ -1,6 +1,8 @@
 package run_test
 
 import (
+	"errors"
+	"io"
 	"net/http"
 	"strings"
 	"sync"
@@ -49,26 +51,32 @@ func FakePrometheusHandler(t *testing.T, metricData *MetricData) http.HandlerFun
 
 		defer request.Body.Close()
 
-		metricFamily := &io_prometheus_client.MetricFamily{}
-		err := expfmt.NewDecoder(request.Body, expfmt.ResponseFormat(request.Header)).Decode(metricFamily)
-		if err != nil {
-			t.Errorf("error decoding request body '%s' : %s", request.Body, err)
-			responseWriter.WriteHeader(http.StatusInternalServerError)
-			return
-		}
+		decoder := expfmt.NewDecoder(request.Body, expfmt.ResponseFormat(request.Header))
+		for {
+			metricFamily := &io_prometheus_client.MetricFamily{}
+			err := decoder.Decode(metricFamily)
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				t.Errorf("error decoding request body '%s' : %s", request.Body, err)
+				responseWriter.WriteHeader(http.StatusInternalServerError)
+				return
+			}
 
-		if metricFamily.GetMetric() != nil {
-			groupedLabels := parseGroupLabels(request.RequestURI)
-			for _, m := range metricFamily.GetMetric() {
-				m.Label = append(m.GetLabel(), groupedLabels...)
+			if metricFamily.GetMetric() != nil {
+				groupedLabels := parseGroupLabels(request.RequestURI)
+				for _, m := range metricFamily.GetMetric() {
+					m.Label = append(m.GetLabel(), groupedLabels...)
+				}
 			}
-		}
 
-		mf := metricData.GetMetricFamily(metricFamily.GetName())
-		if mf == nil {
-			metricData.SetMetricFamily(metricFamily.GetName(), metricFamily)
-		} else {
-			mf.Metric = append(mf.Metric, metricFamily.GetMetric()...)
+			mf := metricData.GetMetricFamily(metricFamily.GetName())
+			if mf == nil {
+				metricData.SetMetricFamily(metricFamily.GetName(), metricFamily)
+			} else {
+				mf.Metric = append(mf.Metric, metricFamily.GetMetric()...)
+			}
 		}
 
 		responseWriter.WriteHeader(http.StatusAccepted)
@@ -0,0 +1,38 @@
+package run
+
+import (
+	"github.com/form3tech-oss/f1/v2/internal/gcpause"
+	"github.com/form3tech-oss/f1/v2/internal/run/views"
+)
+
+// recordGCPauses records the garbage collections of the run and the iterations they overlapped,
+// when the iterations are annotated with them.
+func (r *Run) recordGCPauses() {
+	if r.gc == nil {
+		return
+	}
+
+	report := r.gc.Report()
+	r.result.RecordGCPauses(&report)
+}
+
+func (r *Result) RecordGCPauses(report *gcpause.Report) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.gcPauses = report
+}
+
+func (r *Result) GCPauses() *views.ViewContext[views.GCPausesData] {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.views.GCPauses(views.GCPausesData{Report: *r.gcPauses})
+}
+
+func (r *Result) hasGCPauses() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.gcPauses != nil
+}
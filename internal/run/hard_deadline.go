@@ -0,0 +1,64 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+)
+
+// ErrHardDeadlineExceeded fails runs which were terminated because they were still in progress at
+// the --hard-deadline, however far their setup, load or teardown got.
+var ErrHardDeadlineExceeded = errors.New("hard deadline exceeded")
+
+// hardDeadlineGracePeriod is how long f1 is given to exit with the error of a run terminated at
+// its hard deadline, before it exits regardless of what still hangs, see exitAfterHardDeadline.
+const hardDeadlineGracePeriod = 3 * time.Second
+
+// doUntilHardDeadline runs do, returning once it completes or at the hard deadline, whichever comes
+// first. A run still in progress at the deadline is cancelled and abandoned rather than waited for,
+// in whatever it hangs in, and f1 exits with the error once it is returned.
+func (r *Run) doUntilHardDeadline(ctx context.Context, do func(context.Context) (*Result, error)) (*Result, error) {
+	if r.options.HardDeadline.IsZero() {
+		return do(ctx)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type outcome struct {
+		result *Result
+		err    error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		result, err := do(ctx)
+		done <- outcome{result: result, err: err}
+	}()
+
+	deadline := time.NewTimer(time.Until(r.options.HardDeadline))
+	defer deadline.Stop()
+
+	select {
+	case o := <-done:
+		return o.result, o.err
+	case <-deadline.C:
+		cancel()
+		err := fmt.Errorf("%w: the run was still in progress at %s", ErrHardDeadlineExceeded,
+			r.options.HardDeadline.Format(time.RFC3339))
+		r.output.Display(ui.ErrorMessage{Message: "terminating the run", Error: err})
+		return nil, err
+	}
+}
+
+// exitAfterHardDeadline exits f1 once the grace period elapsed, should the command still not have
+// returned the error of a run terminated at its hard deadline, for example because deferred
+// cleanups hang too.
+func exitAfterHardDeadline(output *ui.Output, exit func(int)) {
+	time.AfterFunc(hardDeadlineGracePeriod, func() {
+		output.Display(ui.ErrorMessage{Message: "exiting after the hard deadline", Error: ErrHardDeadlineExceeded})
+		exit(1)
+	})
+}
@@ -0,0 +1,42 @@
+package run
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+)
+
+// writeHistogram writes the HDR histogram of the durations of the iterations to the histogram
+// file option, in the .hgrm format read by latency tooling.
+func (r *Run) writeHistogram() {
+	if r.histogram == nil {
+		return
+	}
+
+	if err := r.createHistogramFile(); err != nil {
+		r.output.Display(ui.ErrorMessage{Message: "unable to write the histogram of iteration durations", Error: err})
+		return
+	}
+
+	r.output.Display(ui.InfoMessage{Message: "Histogram of iteration durations written to " + r.options.HistogramFile})
+}
+
+func (r *Run) createHistogramFile() error {
+	f, err := os.Create(filepath.Clean(r.options.HistogramFile))
+	if err != nil {
+		return fmt.Errorf("creating histogram file: %w", err)
+	}
+
+	if err := r.histogram.WriteHgrm(f); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("closing histogram file: %w", err)
+	}
+
+	return nil
+}
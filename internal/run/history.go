@@ -0,0 +1,48 @@
+package run
+
+import (
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/history"
+	"github.com/form3tech-oss/f1/v2/internal/run/views"
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+)
+
+// recordHistory compares the run with the previous run of the scenario and adds it to the history.
+func (r *Run) recordHistory() {
+	if r.history == nil {
+		return
+	}
+
+	entry := r.result.historyEntry(time.Now())
+
+	previous, ok, err := r.history.Previous(entry.Scenario)
+	if err != nil {
+		r.output.Display(ui.ErrorMessage{Message: "unable to read run history", Error: err})
+	} else if ok {
+		r.output.Display(r.views.Comparison(views.ComparisonData{
+			PreviousTime:    previous.Time,
+			P95Delta:        entry.P95 - previous.P95,
+			ErrorRateDelta:  entry.ErrorRate() - previous.ErrorRate(),
+			ThroughputDelta: entry.Throughput() - previous.Throughput(),
+		}))
+	}
+
+	if err := r.history.Append(entry); err != nil {
+		r.output.Display(ui.ErrorMessage{Message: "unable to save run history", Error: err})
+	}
+}
+
+func (r *Result) historyEntry(now time.Time) history.Entry {
+	report := r.Report()
+
+	return history.Entry{
+		Time:              now,
+		Scenario:          report.Scenario,
+		Duration:          report.Duration,
+		IterationsStarted: report.IterationsStarted,
+		FailedIterations:  report.FailedIterationDurations.Count,
+		P95:               report.SuccessfulIterationDurations.P95,
+		Failed:            report.Failed,
+	}
+}
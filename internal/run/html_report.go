@@ -0,0 +1,131 @@
+package run
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strconv"
+	"time"
+)
+
+const (
+	htmlReportName = "report.html"
+
+	ratePlotWidth  = 600
+	ratePlotHeight = 120
+)
+
+var htmlReportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"duration": func(d time.Duration) string { return d.Round(time.Microsecond).String() },
+	"percent":  func(rate float64) string { return strconv.FormatFloat(rate, 'f', 2, 64) + "%" },
+	"rate":     func(rate float64) string { return strconv.FormatFloat(rate, 'f', 1, 64) + "/s" },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>f1 run of {{.Scenario}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { padding: 4px 12px; text-align: left; border-bottom: 1px solid #ddd; }
+td.number { text-align: right; }
+.failed { color: #c00; }
+.passed { color: #080; }
+polyline { fill: none; stroke: #06c; stroke-width: 1.5; }
+polyline.target { stroke: #999; stroke-dasharray: 3 2; }
+</style>
+</head>
+<body>
+<h1>{{.Scenario}}</h1>
+<table>
+{{- if .Failed}}
+<tr><th>Result</th><td class="failed">failed{{with .Error}}: {{.}}{{end}}</td></tr>
+{{- else}}
+<tr><th>Result</th><td class="passed">passed</td></tr>
+{{- end}}
+<tr><th>Duration</th><td class="number">{{duration .Duration}}</td></tr>
+<tr><th>Iterations started</th><td class="number">{{.IterationsStarted}}</td></tr>
+<tr><th>Iterations dropped</th><td class="number">{{.DroppedIterationCount}}</td></tr>
+<tr><th>Throughput</th><td class="number">{{rate .Throughput}}</td></tr>
+<tr><th>Error rate</th><td class="number">{{percent .ErrorRate}}</td></tr>
+</table>
+<h2>Iteration durations</h2>
+<table>
+<tr><th>Iterations</th><th>Count</th><th>Min</th><th>Average</th><th>p50</th><th>p95</th><th>p99</th><th>Max</th></tr>
+{{- range .Durations}}
+<tr>
+<td>{{.Name}}</td>
+<td class="number">{{.Count}}</td>
+<td class="number">{{duration .Min}}</td>
+<td class="number">{{duration .Average}}</td>
+<td class="number">{{duration .P50}}</td>
+<td class="number">{{duration .P95}}</td>
+<td class="number">{{duration .P99}}</td>
+<td class="number">{{duration .Max}}</td>
+</tr>
+{{- end}}
+</table>
+{{- if .TargetRate}}
+<h2>Target and achieved rate</h2>
+<p>Iterations planned by the trigger (dashed) and started by the second of the run.</p>
+<svg width="{{.Width}}" height="{{.Height}}"><polyline class="target" points="{{.TargetRate}}"/>
+<polyline points="{{.AchievedRate}}"/></svg>
+{{- end}}
+{{- if .SLA}}
+<h2>SLA compliance</h2>
+<table>
+<tr><th>Operation</th><th>Result</th><th>Violations</th></tr>
+{{- range .SLA}}
+<tr>
+<td>{{.Operation}}</td>
+{{- if .Compliant}}
+<td class="passed">met</td>
+{{- else}}
+<td class="failed">breached</td>
+{{- end}}
+<td>{{range $i, $violation := .Violations}}{{if $i}}, {{end}}{{$violation}}{{end}}</td>
+</tr>
+{{- end}}
+</table>
+{{- end}}
+</body>
+</html>
+`))
+
+type htmlDurations struct {
+	DurationsReport
+	Name string
+}
+
+// HTML returns the report as a standalone html page, with the key figures of the run, the
+// durations of its iterations, its target and achieved rates and its SLA compliance.
+func (r Report) HTML() ([]byte, error) {
+	data := struct {
+		Report
+		Durations    []htmlDurations
+		Throughput   float64
+		TargetRate   string
+		AchievedRate string
+		Width        int
+		Height       int
+	}{
+		Report: r,
+		Durations: []htmlDurations{
+			{Name: "successful", DurationsReport: r.SuccessfulIterationDurations},
+			{Name: "failed", DurationsReport: r.FailedIterationDurations},
+		},
+		Width:  ratePlotWidth,
+		Height: ratePlotHeight,
+	}
+	if r.Duration > 0 {
+		data.Throughput = float64(r.IterationsStarted) / r.Duration.Seconds()
+	}
+	data.TargetRate, data.AchievedRate = RatePoints(r.Rates, ratePlotWidth, ratePlotHeight)
+
+	var page bytes.Buffer
+	if err := htmlReportTemplate.Execute(&page, data); err != nil {
+		return nil, fmt.Errorf("executing html report template: %w", err)
+	}
+
+	return page.Bytes(), nil
+}
@@ -0,0 +1,162 @@
+package run
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+)
+
+// The test cases of the JUnit report, one for each phase of the run.
+const (
+	JUnitSetup      = "setup"
+	JUnitIterations = "iterations"
+	JUnitTeardown   = "teardown"
+)
+
+var (
+	errIterationsFailed  = errors.New("iterations failed")
+	errIterationsDropped = errors.New("iterations dropped")
+	errTeardownFailed    = errors.New("teardown failed")
+)
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+//nolint:tagliatelle // the names of the JUnit XML format
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// recordJUnitCase records a phase of the run as a test case of the JUnit report option, failed with
+// err if it isn't nil.
+func (r *Run) recordJUnitCase(name string, duration time.Duration, err error) {
+	if r.options.JUnitReport == "" {
+		return
+	}
+
+	testCase := junitTestCase{Name: name, ClassName: r.options.Scenario, Time: junitSeconds(duration)}
+	if err != nil {
+		testCase.Failure = &junitMessage{Message: name + " failed", Text: r.result.redactor.String(err.Error())}
+	}
+
+	r.junitCasesMu.Lock()
+	defer r.junitCasesMu.Unlock()
+	r.junitCases = append(r.junitCases, testCase)
+}
+
+// skipJUnitCase records a phase of the run which didn't run as a skipped test case.
+func (r *Run) skipJUnitCase(name, reason string) {
+	if r.options.JUnitReport == "" {
+		return
+	}
+
+	r.junitCasesMu.Lock()
+	defer r.junitCasesMu.Unlock()
+	r.junitCases = append(r.junitCases, junitTestCase{
+		Name:      name,
+		ClassName: r.options.Scenario,
+		Time:      junitSeconds(0),
+		Skipped:   &junitMessage{Message: reason},
+	})
+}
+
+// writeJUnitReport writes the test cases of the phases of the run to the JUnit report option, with
+// the counts of the iterations as their output.
+func (r *Run) writeJUnitReport() {
+	if r.options.JUnitReport == "" {
+		return
+	}
+
+	r.junitCasesMu.Lock()
+	suite := junitTestSuite{
+		Name:      r.options.Scenario,
+		Tests:     len(r.junitCases),
+		TestCases: r.junitCases,
+	}
+	r.junitCasesMu.Unlock()
+
+	for i, testCase := range suite.TestCases {
+		switch {
+		case testCase.Failure != nil:
+			suite.Failures++
+		case testCase.Skipped != nil:
+			suite.Skipped++
+		}
+		if testCase.Name == JUnitIterations && testCase.Skipped == nil {
+			suite.TestCases[i].SystemOut = r.result.iterationCounts()
+		}
+	}
+	suite.Time = junitSeconds(r.result.SetupDuration + r.result.TestDuration + r.result.TeardownDuration)
+
+	data, err := xml.MarshalIndent(junitTestSuites{Suites: []junitTestSuite{suite}}, "", "  ")
+	if err != nil {
+		r.output.Display(ui.ErrorMessage{Message: "unable to write the JUnit report", Error: err})
+		return
+	}
+
+	if err := replaceFile(r.options.JUnitReport, append([]byte(xml.Header), data...)); err != nil {
+		r.output.Display(ui.ErrorMessage{Message: "unable to write the JUnit report", Error: err})
+		return
+	}
+
+	r.output.Display(ui.InfoMessage{Message: "JUnit report written to " + r.options.JUnitReport})
+}
+
+// iterationsFailure returns why the iterations of the run failed, if they did.
+func (r *Result) iterationsFailure() error {
+	if !r.Failed() {
+		return nil
+	}
+	if err := r.Error(); err != nil {
+		return err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.snapshot.FailedIterationDurations.Count > 0 {
+		return fmt.Errorf("%w: %d of %d", errIterationsFailed,
+			r.snapshot.FailedIterationDurations.Count, r.snapshot.Iterations())
+	}
+
+	return fmt.Errorf("%w: %d of %d", errIterationsDropped, r.snapshot.DroppedIterationCount, r.snapshot.Iterations())
+}
+
+// iterationCounts describes the iterations of the run by their result.
+func (r *Result) iterationCounts() string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return fmt.Sprintf("%d iterations: %d successful, %d failed, %d dropped", r.snapshot.Iterations(),
+		r.snapshot.SuccessfulIterationDurations.Count, r.snapshot.FailedIterationDurations.Count,
+		r.snapshot.DroppedIterationCount)
+}
+
+func junitSeconds(duration time.Duration) string {
+	return strconv.FormatFloat(duration.Seconds(), 'f', 3, 64)
+}
@@ -0,0 +1,48 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/memory"
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+)
+
+const memoryCheckInterval = 500 * time.Millisecond
+
+// ErrMaxMemoryExceeded fails runs which were stopped because f1 used more memory than allowed
+// by --max-memory.
+var ErrMaxMemoryExceeded = errors.New("resource exhausted: max memory exceeded")
+
+// guardMemory stops triggering new iterations when the memory used by the process exceeds
+// the max memory option, so that the run completes and reports its results rather than
+// being killed by the operating system.
+func (r *Run) guardMemory(ctx context.Context, stopTrigger context.CancelFunc) {
+	if r.options.MaxMemory == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(memoryCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			used := memory.ProcessMemory()
+			if used <= r.options.MaxMemory {
+				continue
+			}
+
+			err := fmt.Errorf("%w: using %s of %s", ErrMaxMemoryExceeded,
+				memory.FormatSize(used), memory.FormatSize(r.options.MaxMemory))
+			r.result.AddError(err)
+			r.output.Display(ui.ErrorMessage{Message: "stopping the run", Error: err})
+			stopTrigger()
+			return
+		}
+	}
+}
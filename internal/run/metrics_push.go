@@ -0,0 +1,248 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/push"
+
+	"github.com/form3tech-oss/f1/v2/internal/envsettings"
+	"github.com/form3tech-oss/f1/v2/internal/metrics"
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+)
+
+// ErrMetricsPushFailed fails runs whose metrics couldn't be pushed to the push gateway more times
+// in a row than allowed by --require-metrics.
+var ErrMetricsPushFailed = errors.New("metrics push failed")
+
+// defaultMetricsPushInterval is how often metrics are pushed during a run, unless configured by
+// PROMETHEUS_PUSH_INTERVAL.
+const defaultMetricsPushInterval = 5 * time.Second
+
+// metricsPushes tracks the pushes of metrics which failed in a row, and the metrics last pushed
+// when only changed metrics are pushed.
+type metricsPushes struct {
+	// lastPushed is when the metrics with the lastDigest were pushed, guarded by mu
+	lastPushed time.Time
+	lastDigest uint64
+	// interval is how often metrics are pushed during the run
+	interval time.Duration
+	// heartbeat is how often unchanged metrics are pushed, or 0 to push them every interval
+	heartbeat           time.Duration
+	consecutiveFailures atomic.Int64
+	// required is set once the run failed for missing metrics, so that it fails only once
+	required atomic.Bool
+	mu       sync.Mutex
+}
+
+// configure sets how often metrics are pushed from PROMETHEUS_PUSH_INTERVAL and
+// PROMETHEUS_PUSH_HEARTBEAT.
+func (p *metricsPushes) configure(settings envsettings.Prometheus) error {
+	p.interval = defaultMetricsPushInterval
+	if settings.PushInterval != "" {
+		interval, err := time.ParseDuration(settings.PushInterval)
+		if err != nil {
+			return fmt.Errorf("parsing %s: %w", envsettings.EnvPrometheusPushInterval, err)
+		}
+		if interval <= 0 {
+			return fmt.Errorf("%s %s must be positive", envsettings.EnvPrometheusPushInterval, interval)
+		}
+		p.interval = interval
+	}
+
+	if settings.PushHeartbeat != "" {
+		heartbeat, err := time.ParseDuration(settings.PushHeartbeat)
+		if err != nil {
+			return fmt.Errorf("parsing %s: %w", envsettings.EnvPrometheusPushHeartbeat, err)
+		}
+		if heartbeat < p.interval {
+			return fmt.Errorf("%s %s must be at least the push interval of %s",
+				envsettings.EnvPrometheusPushHeartbeat, heartbeat, p.interval)
+		}
+		p.heartbeat = heartbeat
+	}
+
+	return nil
+}
+
+// unchanged reports whether the metrics with the given digest were already pushed within the
+// heartbeat, so that pushing them again can be skipped.
+func (p *metricsPushes) unchanged(digest uint64) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return !p.lastPushed.IsZero() && digest == p.lastDigest && time.Since(p.lastPushed) < p.heartbeat
+}
+
+func (p *metricsPushes) pushed(digest uint64, at time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.lastDigest = digest
+	p.lastPushed = at
+}
+
+func newMetricsPusher(
+	settings envsettings.Settings,
+	scenarioName string,
+	metricsInstance *metrics.Metrics,
+	build *Build,
+) *push.Pusher {
+	if settings.Prometheus.PushGateway == "" {
+		return nil
+	}
+
+	pusher := push.New(settings.Prometheus.PushGateway, "f1-"+scenarioName).
+		Gatherer(metricsInstance.Registry)
+
+	if settings.Prometheus.Namespace != "" {
+		pusher = pusher.Grouping("namespace", settings.Prometheus.Namespace)
+	}
+
+	if settings.Prometheus.LabelID != "" {
+		pusher = pusher.Grouping("id", settings.Prometheus.LabelID)
+	}
+
+	// the labels are added in a stable order, as they form the path of the group
+	for _, label := range []string{BuildVersionLabel, BuildRevisionLabel} {
+		if value, ok := build.labels()[label]; ok {
+			pusher = pusher.Grouping(label, value)
+		}
+	}
+
+	return pusher
+}
+
+// newOTLPExporter returns the exporter of the metrics to the OpenTelemetry collector of
+// OTLP_METRICS_ENDPOINT, if set, whose resource is described as the group of the push gateway.
+func newOTLPExporter(
+	settings envsettings.Settings,
+	scenarioName string,
+	metricsInstance *metrics.Metrics,
+	build *Build,
+) (*metrics.OTLPExporter, error) {
+	if !settings.OTLP.Enabled() {
+		return nil, nil
+	}
+
+	attributes := build.labels()
+	attributes["service.name"] = "f1-" + scenarioName
+	if settings.Prometheus.Namespace != "" {
+		attributes["namespace"] = settings.Prometheus.Namespace
+	}
+	if settings.Prometheus.LabelID != "" {
+		attributes["id"] = settings.Prometheus.LabelID
+	}
+
+	exporter, err := metrics.NewOTLPExporter(settings.OTLP.MetricsEndpoint, metricsInstance.Registry, attributes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", envsettings.EnvOTLPMetricsEndpoint, err)
+	}
+
+	return exporter, nil
+}
+
+// shutdownOTLPExporter releases the exporter of the metrics once they were last exported.
+func (r *Run) shutdownOTLPExporter() {
+	if r.otlpExporter == nil {
+		return
+	}
+
+	if err := r.otlpExporter.Shutdown(context.Background()); err != nil {
+		r.output.Display(ui.WarningMessage{Message: err.Error()})
+	}
+}
+
+// pushesMetrics reports whether the metrics are pushed to a push gateway or exported to an
+// OpenTelemetry collector.
+func (r *Run) pushesMetrics() bool {
+	return r.pusher != nil || r.otlpExporter != nil
+}
+
+// pushChangedMetrics pushes the metrics periodically during the run. When a heartbeat is set,
+// metrics unchanged since the last push are only pushed once per heartbeat, to reduce the load on
+// push gateways shared by many runs.
+func (r *Run) pushChangedMetrics(ctx context.Context) {
+	if !r.pushesMetrics() {
+		return
+	}
+
+	if r.metricsPushes.heartbeat == 0 {
+		r.pushMetrics(ctx)
+		return
+	}
+
+	// the digest is taken before pushing, so that metrics changed during the push are pushed next.
+	// Metrics which can't be compared are pushed.
+	digest, err := r.metrics.Digest()
+	if err != nil {
+		r.pushMetrics(ctx)
+		return
+	}
+	if r.metricsPushes.unchanged(digest) {
+		r.tracer.Event("unchanged metrics push skipped")
+		return
+	}
+
+	pushedAt := time.Now()
+	if r.pushMetrics(ctx) {
+		r.metricsPushes.pushed(digest, pushedAt)
+	}
+}
+
+// pushMetrics pushes the metrics to the push gateway and exports them to the OpenTelemetry
+// collector, if configured. Failed pushes are counted in the result and in a metric pushed with the
+// next push, and stop and fail the run when more than the --require-metrics pushes fail in a row,
+// as the gaps they leave invalidate the run. A push fails if either of them fails. It returns
+// whether the metrics were pushed.
+func (r *Run) pushMetrics(ctx context.Context) bool {
+	if !r.pushesMetrics() {
+		return false
+	}
+
+	failed := false
+	if r.pusher != nil {
+		if err := r.pusher.PushContext(ctx); err != nil {
+			failed = true
+			r.output.Display(ui.ErrorMessage{
+				Message: "unable to push metrics to prometheus",
+				Error:   err,
+			})
+		}
+	}
+	if r.otlpExporter != nil {
+		if err := r.otlpExporter.Export(ctx); err != nil {
+			failed = true
+			r.output.Display(ui.ErrorMessage{
+				Message: "unable to export metrics to the OpenTelemetry collector",
+				Error:   err,
+			})
+		}
+	}
+	if !failed {
+		r.metricsPushes.consecutiveFailures.Store(0)
+		return true
+	}
+
+	r.metrics.RecordMetricsPushFailure(r.options.Scenario)
+	r.result.RecordMetricsPushFailure()
+
+	failures := r.metricsPushes.consecutiveFailures.Add(1)
+	if r.options.RequireMetrics == 0 || failures <= int64(r.options.RequireMetrics) {
+		return false
+	}
+	if !r.metricsPushes.required.CompareAndSwap(false, true) {
+		return false
+	}
+
+	err := fmt.Errorf("%w: %d pushes failed in a row", ErrMetricsPushFailed, failures)
+	r.result.AddError(err)
+	r.output.Display(ui.ErrorMessage{Message: "stopping the run", Error: err})
+	r.stop("Metrics Push Failed")
+
+	return false
+}
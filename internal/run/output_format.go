@@ -0,0 +1,84 @@
+package run
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/form3tech-oss/f1/v2/internal/log"
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+)
+
+const (
+	// OutputFormatText prints the result of the run as the human-readable summary only
+	OutputFormatText = "text"
+	// OutputFormatJSON also writes the result of the run as a json report, to stdout or to the
+	// output file option
+	OutputFormatJSON = "json"
+)
+
+var (
+	errUnknownOutputFormat   = errors.New("unknown output format")
+	errOutputFileWithoutJSON = errors.New("the output file is only written with the json output format")
+)
+
+// jsonOutputFormat returns whether the result of the run is written as json, for the output format
+// option.
+func jsonOutputFormat(format string) (bool, error) {
+	switch format {
+	case OutputFormatText, "":
+		return false, nil
+	case OutputFormatJSON:
+		return true, nil
+	default:
+		return false, fmt.Errorf("%w '%s', expected %s or %s", errUnknownOutputFormat, format,
+			OutputFormatText, OutputFormatJSON)
+	}
+}
+
+// stderrOutput returns parent writing to stderr, so that the json result of the run is the only
+// output on stdout.
+func stderrOutput(parent *ui.Output, config *log.Config) *ui.Output {
+	return ui.NewOutput(
+		log.NewLogger(parent.Printer.ErrWriter, config),
+		ui.NewPrinter(parent.Printer.ErrWriter, parent.Printer.ErrWriter),
+		parent.Interactive,
+		parent.AllowPrinting,
+	)
+}
+
+// writeJSONResult writes the report of the run as json to the output file option, or as a single
+// line to stdout, when the output format is json.
+func (r *Run) writeJSONResult() {
+	if !r.jsonOutput {
+		return
+	}
+
+	if r.options.OutputFile != "" {
+		if err := r.result.WriteReport(r.options.OutputFile); err != nil {
+			r.output.Display(ui.ErrorMessage{Message: "unable to write the json result", Error: err})
+			return
+		}
+
+		r.output.Display(ui.InfoMessage{Message: "Result written to " + r.options.OutputFile})
+		return
+	}
+
+	if err := writeJSONLine(r.jsonStdout, r.result.Report()); err != nil {
+		r.output.Display(ui.ErrorMessage{Message: "unable to write the json result", Error: err})
+	}
+}
+
+func writeJSONLine(writer io.Writer, report Report) error {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("marshalling report: %w", err)
+	}
+
+	if _, err := writer.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("writing report: %w", err)
+	}
+
+	return nil
+}
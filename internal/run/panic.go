@@ -0,0 +1,74 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"sync/atomic"
+
+	"github.com/form3tech-oss/f1/v2/internal/goroutines"
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+	"github.com/form3tech-oss/f1/v2/internal/xcontext"
+)
+
+// ErrPanicked is the error of runs which were abandoned because f1 itself panicked, as opposed to
+// the scenario, whose panics fail the iteration or the setup they happened in.
+var ErrPanicked = errors.New("f1 panicked")
+
+// recoverPanics runs do, recovering a panic of f1 so that the run still completes as a failed run:
+// do tears the scenario down on its way out, and the result up to the panic is reported. The panics
+// of the goroutines of the run started with goroutines.Go are forwarded to the run, which is
+// cancelled and then completes the same way.
+func (r *Run) recoverPanics(ctx context.Context, do func(context.Context) (*Result, error)) (*Result, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var forwarded atomic.Pointer[recoveredPanic]
+	ctx = goroutines.WithPanicHandler(ctx, func(recovered any, stack []byte) {
+		if forwarded.CompareAndSwap(nil, &recoveredPanic{recovered: recovered, stack: stack}) {
+			cancel()
+		}
+	})
+
+	var result *Result
+	var err error
+
+	func() {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				forwarded.CompareAndSwap(nil, &recoveredPanic{recovered: recovered, stack: debug.Stack()})
+			}
+		}()
+
+		result, err = do(ctx)
+	}()
+
+	if panicked := forwarded.Load(); panicked != nil {
+		r.recordPanic(ctx, panicked.recovered, panicked.stack)
+		return r.result, nil
+	}
+
+	return result, err
+}
+
+// recoveredPanic is the first panic of the run, in the goroutine of the run or another one.
+type recoveredPanic struct {
+	recovered any
+	stack     []byte
+}
+
+// recordPanic fails the run with the panic, and saves the progress of the run up to the panic:
+// its metrics, report snapshot and checkpoint.
+func (r *Run) recordPanic(ctx context.Context, recovered any, stack []byte) {
+	err := fmt.Errorf("%w: %v", ErrPanicked, recovered)
+	r.tracer.Event("run panicked", slog.String("panic", fmt.Sprint(recovered)))
+	r.output.Display(ui.ErrorMessage{Message: "recovered a panic of f1, abandoning the run", Error: err})
+
+	r.result.GetTotals()
+	r.result.RecordPanic(err, string(stack))
+	r.pushMetrics(xcontext.Detach(ctx))
+	r.writeReportSnapshot(true)
+	r.saveCheckpoint()
+}
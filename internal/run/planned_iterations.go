@@ -0,0 +1,37 @@
+package run
+
+// RecordPlannedIterations records the number of iterations the trigger planned to start over the
+// load of the run.
+func (r *Result) RecordPlannedIterations(planned uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.plannedIterations = planned
+}
+
+// planAchieved returns the percentage of the planned iterations which started, 0 without a plan.
+func planAchieved(started, planned uint64) float64 {
+	if planned == 0 {
+		return 0
+	}
+
+	return 100 * float64(started) / float64(planned)
+}
+
+// recordPlannedIterations counts the iterations the trigger planned to start over the load of the
+// run, up to when it stopped or its planned duration, so that runs stopped early are compared with
+// the part of the plan they ran, and resumed runs with the part of the plan since they resumed. Runs
+// with max iterations plan no more than those.
+func (r *Run) recordPlannedIterations() {
+	if r.trigger.PlannedIterations == nil {
+		return
+	}
+
+	from := r.options.Elapsed
+	planned := r.trigger.PlannedIterations(from, from+min(r.result.TestDuration, r.plannedDuration()))
+	if r.options.MaxIterations > 0 {
+		planned = min(planned, r.options.MaxIterations)
+	}
+
+	r.result.RecordPlannedIterations(planned)
+}
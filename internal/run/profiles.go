@@ -0,0 +1,167 @@
+package run
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime/pprof"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+	"github.com/form3tech-oss/f1/v2/internal/workers"
+)
+
+const (
+	profileRateInterval = time.Second
+	// profilePlateauRatio is how much the rate may still grow in a second for the run to be at the
+	// top of a stage, rather than ramping up towards it
+	profilePlateauRatio = 1.1
+	// profileRecaptureRatio is how much higher than the rate of the kept profiles the rate must be
+	// to capture them again
+	profileRecaptureRatio = 1.2
+)
+
+// profiles is the description of the profiles written next to them, to tell at which point of the
+// run they were captured.
+type profiles struct {
+	Time     time.Time     `json:"time"`
+	Stage    string        `json:"stage,omitempty"`
+	Duration time.Duration `json:"duration"`
+	// Rate is the number of iterations started per second while the CPU profile was captured
+	Rate float64 `json:"rate"`
+}
+
+// captureProfiles captures CPU and heap profiles of f1 for the pprof capture duration once the rate
+// iterations are started at stops growing, at the top of the first stage. The profiles are captured
+// again whenever the run reaches a higher rate, so that the profiles kept are those of the peak of
+// the run.
+func (r *Run) captureProfiles(ctx context.Context, poolManager *workers.PoolManager) {
+	if r.options.PprofCapture == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(profileRateInterval)
+	defer ticker.Stop()
+
+	started := poolManager.IterationsStarted()
+	last := time.Now()
+	previous := 0.0
+	captured := 0.0
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			current := poolManager.IterationsStarted()
+			rate := float64(current-started) / now.Sub(last).Seconds()
+			started, last = current, now
+
+			plateau := previous > 0 && rate <= previous*profilePlateauRatio
+			previous = rate
+			if !plateau || rate <= captured*profileRecaptureRatio {
+				continue
+			}
+
+			rate, err := r.captureProfile(ctx, poolManager)
+			if err != nil {
+				r.output.Display(ui.WarningMessage{Message: "unable to capture profiles: " + err.Error()})
+				return
+			}
+			if ctx.Err() != nil {
+				// the run ended during the capture
+				return
+			}
+
+			captured = rate
+			started, last = poolManager.IterationsStarted(), time.Now()
+			ticker.Reset(profileRateInterval)
+		}
+	}
+}
+
+// captureProfile captures a CPU profile for the pprof capture duration followed by a heap profile,
+// and replaces the profiles of the run with them. It returns the rate iterations were started at
+// during the capture, or 0 if the run ended before the capture completed.
+func (r *Run) captureProfile(ctx context.Context, poolManager *workers.PoolManager) (float64, error) {
+	var cpu bytes.Buffer
+	if err := pprof.StartCPUProfile(&cpu); err != nil {
+		return 0, fmt.Errorf("starting cpu profile: %w", err)
+	}
+
+	start := time.Now()
+	startedBefore := poolManager.IterationsStarted()
+	r.tracer.Event("profile capture started", slog.Duration("duration", r.options.PprofCapture))
+
+	timer := time.NewTimer(r.options.PprofCapture)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		pprof.StopCPUProfile()
+		return 0, nil
+	case <-timer.C:
+		pprof.StopCPUProfile()
+	}
+
+	rate := float64(poolManager.IterationsStarted()-startedBefore) / time.Since(start).Seconds()
+	r.tracer.Event("profile capture completed", slog.Float64("rate", rate))
+
+	var heap bytes.Buffer
+	if err := pprof.Lookup("heap").WriteTo(&heap, 0); err != nil {
+		return 0, fmt.Errorf("writing heap profile: %w", err)
+	}
+
+	description, err := json.MarshalIndent(profiles{
+		Time:     start,
+		Stage:    r.Progress().Stage,
+		Duration: r.options.PprofCapture,
+		Rate:     rate,
+	}, "", "  ")
+	if err != nil {
+		return 0, fmt.Errorf("marshalling profiles description: %w", err)
+	}
+
+	dir := r.result.Profiles()
+	if dir == "" {
+		dir = filepath.Join(r.options.SnapshotDir, fmt.Sprintf("%s-%s-profiles",
+			r.options.Scenario, start.UTC().Format(artifactsTimeFormat)))
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return 0, fmt.Errorf("creating profiles directory: %w", err)
+		}
+	}
+
+	for name, data := range map[string][]byte{
+		"cpu.pprof":     cpu.Bytes(),
+		"heap.pprof":    heap.Bytes(),
+		"profiles.json": description,
+	} {
+		if err := replaceFile(filepath.Join(dir, name), data); err != nil {
+			return 0, err
+		}
+	}
+
+	r.result.RecordProfiles(dir)
+	r.output.Display(ui.InfoMessage{
+		Message: fmt.Sprintf("Profiles captured at %.1f iterations/s written to %s", rate, dir),
+	})
+
+	return rate, nil
+}
+
+// replaceFile writes data to path through a temporary file, so that readers never see a partial file.
+func replaceFile(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("writing '%s': %w", tmp, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("replacing '%s': %w", path, err)
+	}
+
+	return nil
+}
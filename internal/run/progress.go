@@ -0,0 +1,126 @@
+package run
+
+import (
+	"sync"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/progress"
+)
+
+// Progress is a snapshot of the progress of a run in progress, for tools built on top of f1.
+type Progress struct {
+	Scenario string `json:"scenario"`
+	// Stage is the name of the running stage, for triggers which run in stages
+	Stage   string        `json:"stage,omitempty"`
+	Elapsed time.Duration `json:"elapsed"`
+	// ETA is the remaining duration of the run, unless it completes early by reaching its max iterations.
+	// It is zero for endless runs, which run until they are stopped.
+	ETA time.Duration `json:"eta"`
+	// Rate is the number of successful iterations per second during the latest progress period
+	Rate                 float64 `json:"rate"`
+	SuccessfulIterations uint64  `json:"successful_iterations"`
+	FailedIterations     uint64  `json:"failed_iterations"`
+	DroppedIterations    uint64  `json:"dropped_iterations"`
+	// Drops are the dropped iterations by the second of the run and stage they were planned in
+	Drops []Drop        `json:"drops,omitempty"`
+	P50   time.Duration `json:"p50"`
+	P95   time.Duration `json:"p95"`
+	P99   time.Duration `json:"p99"`
+	// Gauges are the latest values of the progress gauges registered by the scenario
+	Gauges []progress.Gauge `json:"gauges,omitempty"`
+}
+
+// Progress returns a snapshot of the progress of the run, as of the latest progress update.
+func (r *Run) Progress() Progress {
+	r.result.mu.RLock()
+	elapsed := r.result.duration()
+	if r.result.TestDuration > 0 {
+		elapsed = r.result.TestDuration
+	}
+	snapshot := r.result.snapshot
+	drops := r.result.drops()
+	gauges := r.result.gauges
+	r.result.mu.RUnlock()
+
+	progress := Progress{
+		Scenario:             r.options.Scenario,
+		Elapsed:              elapsed,
+		ETA:                  r.eta(elapsed),
+		SuccessfulIterations: snapshot.SuccessfulIterationDurations.Count,
+		FailedIterations:     snapshot.FailedIterationDurations.Count,
+		DroppedIterations:    snapshot.DroppedIterationCount,
+		Drops:                drops,
+		P50:                  snapshot.SuccessfulIterationDurations.P50,
+		P95:                  snapshot.SuccessfulIterationDurations.P95,
+		P99:                  snapshot.SuccessfulIterationDurations.P99,
+		Gauges:               gauges,
+	}
+
+	if snapshot.Period > 0 {
+		progress.Rate = float64(snapshot.SuccessfulIterationDurationsForPeriod.Count) / snapshot.Period.Seconds()
+	}
+	if r.trigger.StageAt != nil {
+		progress.Stage = r.trigger.StageAt(r.options.Elapsed + elapsed)
+	}
+
+	return progress
+}
+
+// eta returns how long the run will keep triggering iterations for once elapsed has passed, or 0
+// if an endless run has no planned end.
+func (r *Run) eta(elapsed time.Duration) time.Duration {
+	duration := r.plannedDuration()
+	if r.options.Endless && duration == r.options.MaxDuration {
+		return 0
+	}
+
+	return max(duration-elapsed, 0)
+}
+
+// plannedDuration returns how long the run will trigger iterations for.
+func (r *Run) plannedDuration() time.Duration {
+	duration := r.options.MaxDuration
+	// a resumed run only runs the remaining duration of the trigger.
+	triggerDuration := r.trigger.Duration - r.options.Elapsed
+	if r.trigger.Duration > 0 && triggerDuration < r.options.MaxDuration {
+		duration = triggerDuration
+	}
+
+	return duration
+}
+
+// Tracker keeps track of the run in progress, so that its progress can be read while it runs.
+type Tracker struct {
+	run *Run
+	mu  sync.RWMutex
+}
+
+func NewTracker() *Tracker {
+	return &Tracker{}
+}
+
+func (t *Tracker) track(r *Run) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.run = r
+}
+
+func (t *Tracker) untrack(r *Run) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.run == r {
+		t.run = nil
+	}
+}
+
+// Progress returns the progress of the run in progress. It returns false if no run is in progress.
+func (t *Tracker) Progress() (Progress, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.run == nil {
+		return Progress{}, false
+	}
+
+	return t.run.Progress(), true
+}
@@ -0,0 +1,67 @@
+package run
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/form3tech-oss/f1/v2/internal/run/views"
+	"github.com/form3tech-oss/f1/v2/internal/workers"
+)
+
+// QuotaDropsReport counts the iterations of an operation dropped while its pools were capped by
+// the concurrency quota of the operation.
+type QuotaDropsReport struct {
+	Operation string `json:"operation"`
+	// Workers is the concurrency quota of the operation
+	Workers int    `json:"workers"`
+	Count   uint64 `json:"count"`
+}
+
+// RecordQuotaDrops records the iterations dropped by operations capped by their concurrency quotas.
+func (r *Result) RecordQuotaDrops(drops []workers.QuotaDrops) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.quotaDrops = r.quotaDrops[:0]
+	for _, drop := range drops {
+		r.quotaDrops = append(r.quotaDrops, QuotaDropsReport{
+			Operation: drop.Operation,
+			Workers:   drop.Workers,
+			Count:     drop.Count,
+		})
+	}
+}
+
+// quotaDropsData returns the iterations dropped by the concurrency quotas of operations, for the
+// summary of the dropped iterations.
+func (r *Result) quotaDropsData() []views.QuotaDrops {
+	if len(r.quotaDrops) == 0 {
+		return nil
+	}
+
+	data := make([]views.QuotaDrops, 0, len(r.quotaDrops))
+	for _, drop := range r.quotaDrops {
+		data = append(data, views.QuotaDrops{Operation: drop.Operation, Workers: drop.Workers, Count: drop.Count})
+	}
+
+	return data
+}
+
+// combineQuotaDrops sums the iterations dropped by the concurrency quotas of the same operations
+// of runs.
+func combineQuotaDrops(a, b []QuotaDropsReport) []QuotaDropsReport {
+	combined := slices.Clone(a)
+	for _, drop := range b {
+		i := slices.IndexFunc(combined, func(c QuotaDropsReport) bool { return c.Operation == drop.Operation })
+		if i < 0 {
+			combined = append(combined, drop)
+			continue
+		}
+		combined[i].Count += drop.Count
+	}
+	slices.SortFunc(combined, func(a, b QuotaDropsReport) int {
+		return cmp.Compare(a.Operation, b.Operation)
+	})
+
+	return combined
+}
@@ -0,0 +1,78 @@
+package run
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/form3tech-oss/f1/v2/internal/racecheck"
+	"github.com/form3tech-oss/f1/v2/internal/triggerflags"
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+)
+
+// raceCheckOmittedFlags are not passed to the race check, which must not write the files of the
+// run, serve its ports or wait for other processes.
+var raceCheckOmittedFlags = []string{
+	triggerflags.FlagRaceCheck,
+	triggerflags.FlagRaceCheckDuration,
+	triggerflags.FlagReportFile,
+	triggerflags.FlagReportSnapshot,
+	triggerflags.FlagResume,
+	triggerflags.FlagControlAddr,
+	triggerflags.FlagPprofPort,
+	triggerflags.FlagPprofCapture,
+	triggerflags.FlagSnapshotFailure,
+	triggerflags.FlagHistogramFile,
+	triggerflags.FlagSummaryMetricsFile,
+	triggerflags.FlagAuditLog,
+	triggerflags.FlagTrace,
+	triggerflags.FlagTraceFile,
+	triggerflags.FlagStartAt,
+	triggerflags.FlagSyncBarrier,
+	triggerflags.FlagRateOverride,
+	triggerflags.FlagRequireMetrics,
+	triggerflags.FlagYes,
+	triggerflags.FlagHardDeadline,
+	triggerflags.FlagOutputFormat,
+	triggerflags.FlagOutputFile,
+	triggerflags.FlagJUnitReport,
+	triggerflags.FlagSLA,
+}
+
+// checkRaces runs the scenario for duration in a binary built with the race detector, and fails
+// if it finds a data race. Failures of the check other than races are only reported, as the run
+// itself would fail the same way.
+func checkRaces(cmd *cobra.Command, args, scenarioArgs []string, duration time.Duration, output *ui.Output) error {
+	dir, err := os.MkdirTemp("", "f1-race-check-")
+	if err != nil {
+		return fmt.Errorf("creating race check directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	output.Display(ui.InfoMessage{Message: fmt.Sprintf("Running the race check for %s", duration)})
+
+	binary, err := racecheck.Binary(cmd.Context(), dir)
+	if err != nil {
+		return fmt.Errorf("race check: %w", err)
+	}
+
+	checkArgs := racecheck.Args(cmd, slices.Concat(args, scenarioArgs), raceCheckOmittedFlags...)
+	// the run was confirmed before the race check, which must not ask again
+	checkArgs = slices.Insert(checkArgs, len(strings.Fields(cmd.CommandPath()))-1, "--"+triggerflags.FlagYes)
+	err = racecheck.Run(cmd.Context(), binary, checkArgs, duration)
+	switch {
+	case errors.Is(err, racecheck.ErrDataRace):
+		return err
+	case err != nil:
+		output.Display(ui.WarningMessage{Message: "The race check failed without data races: " + err.Error()})
+	default:
+		output.Display(ui.InfoMessage{Message: "The race check found no data races"})
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package run
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/workers"
+	"github.com/form3tech-oss/f1/v2/pkg/f1/testing"
+)
+
+const (
+	rateDropInterval = time.Second
+	// rateDropRatio is the fraction of the highest rate the rate must drop to, to call the hooks
+	rateDropRatio = 0.5
+	// minRateDropPeak ignores the fluctuations of low rates, which don't warm up pools
+	minRateDropPeak = 10
+)
+
+// watchRateDrops measures the rate iterations are started at, and calls the rate drop hooks of the
+// scenario when it drops to a fraction of its highest rate since the previous drop.
+func (r *Run) watchRateDrops(ctx context.Context, poolManager *workers.PoolManager) {
+	if !r.activeScenario.ObservesRateDrops() {
+		return
+	}
+
+	ticker := time.NewTicker(rateDropInterval)
+	defer ticker.Stop()
+
+	started := poolManager.IterationsStarted()
+	last := time.Now()
+	peak := 0.0
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			current := poolManager.IterationsStarted()
+			rate := float64(current-started) / now.Sub(last).Seconds()
+			started, last = current, now
+
+			if rate > peak {
+				peak = rate
+				continue
+			}
+			if peak < minRateDropPeak || rate > peak*rateDropRatio {
+				continue
+			}
+
+			drop := testing.RateDrop{Stage: r.Progress().Stage, From: peak, To: rate}
+			r.tracer.Event("rate dropped",
+				slog.Float64("from", drop.From),
+				slog.Float64("to", drop.To),
+				slog.String("stage", drop.Stage),
+			)
+			r.activeScenario.RateDropped(drop)
+			peak = rate
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package run
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/form3tech-oss/f1/v2/internal/goroutines"
+	"github.com/form3tech-oss/f1/v2/internal/tracing"
+	"github.com/form3tech-oss/f1/v2/internal/trigger/rate"
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+	"github.com/form3tech-oss/f1/v2/internal/workers"
+)
+
+const rateOverrideCheckInterval = time.Second
+
+var errInvalidRateOverride = errors.New("invalid rate override")
+
+// rateOverrideFile applies the rate override file of a run to the rates of its trigger when the
+// file changes, restoring the planned rates when the file is removed.
+type rateOverrideFile struct {
+	path        string
+	poolManager *workers.PoolManager
+	output      *ui.Output
+	tracer      tracing.Tracer
+	// content is the content of the file last checked, nil if it didn't exist
+	content []byte
+}
+
+// ParseRateOverride parses a rate override from the yaml of a file setting either a multiplier of the
+// planned rate or an absolute rate, e.g.
+//
+//	multiplier: 1.5
+//
+// or
+//
+//	rate: 50/s
+func ParseRateOverride(content []byte) (*workers.RateOverride, error) {
+	var file struct {
+		Multiplier *float64 `yaml:"multiplier"`
+		Rate       string   `yaml:"rate"`
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parsing rate override: %w", err)
+	}
+
+	switch {
+	case file.Multiplier != nil && file.Rate != "":
+		return nil, fmt.Errorf("%w: set either multiplier or rate", errInvalidRateOverride)
+	case file.Multiplier != nil:
+		if *file.Multiplier < 0 {
+			return nil, fmt.Errorf("%w: multiplier %g can't be negative", errInvalidRateOverride, *file.Multiplier)
+		}
+		return &workers.RateOverride{Multiplier: *file.Multiplier}, nil
+	case file.Rate != "":
+		iterations, interval, err := rate.ParseRate(file.Rate)
+		if err != nil {
+			return nil, fmt.Errorf("parsing rate override: %w", err)
+		}
+		if interval <= 0 {
+			return nil, fmt.Errorf("%w: rate %s must have a positive interval", errInvalidRateOverride, file.Rate)
+		}
+		return &workers.RateOverride{Rate: iterations, Interval: interval}, nil
+	default:
+		return nil, fmt.Errorf("%w: missing multiplier or rate", errInvalidRateOverride)
+	}
+}
+
+// check applies the override of the file if it changed since it was last checked.
+func (f *rateOverrideFile) check() {
+	content, err := os.ReadFile(f.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		if f.content != nil {
+			f.content = nil
+			f.poolManager.SetRateOverride(nil)
+			f.tracer.Event("rate override removed")
+			f.output.Display(ui.InfoMessage{
+				Message: fmt.Sprintf("Rate override %s removed, restoring the planned rate", f.path),
+			})
+		}
+		return
+	}
+	if err != nil {
+		f.output.Display(ui.WarningMessage{Message: fmt.Sprintf("unable to read rate override: %s", err)})
+		return
+	}
+	if f.content != nil && bytes.Equal(content, f.content) {
+		return
+	}
+
+	// an invalid override is reported once, and the previous override is kept until it is fixed
+	f.content = content
+	override, err := ParseRateOverride(content)
+	if err != nil {
+		f.output.Display(ui.WarningMessage{Message: fmt.Sprintf("ignoring rate override %s: %s", f.path, err)})
+		return
+	}
+
+	f.poolManager.SetRateOverride(override)
+	f.tracer.Event("rate overridden", slog.String("rate", override.String()))
+	f.output.Display(ui.InfoMessage{Message: fmt.Sprintf("Rate overridden by %s: %s", f.path, override)})
+}
+
+// watch checks the file for changes until the context is done.
+func (f *rateOverrideFile) watch(ctx context.Context) {
+	ticker := time.NewTicker(rateOverrideCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			f.check()
+		}
+	}
+}
+
+// overrideRates applies the rate override file of the run, if set, before the first iterations are
+// triggered and whenever it changes until the context is done.
+func (r *Run) overrideRates(ctx context.Context, poolManager *workers.PoolManager) {
+	if r.options.RateOverrideFile == "" {
+		return
+	}
+
+	file := &rateOverrideFile{
+		path:        r.options.RateOverrideFile,
+		poolManager: poolManager,
+		output:      r.output,
+		tracer:      r.tracer,
+	}
+	file.check()
+	goroutines.Go(ctx, "rate-override", file.watch)
+}
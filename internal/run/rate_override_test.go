@@ -0,0 +1,93 @@
+package run_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/run"
+	"github.com/form3tech-oss/f1/v2/internal/workers"
+)
+
+func TestParseRateOverride(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name     string
+		content  string
+		expected *workers.RateOverride
+		err      string
+	}{
+		{
+			name:     "multiplier",
+			content:  "multiplier: 1.5",
+			expected: &workers.RateOverride{Multiplier: 1.5},
+		},
+		{
+			name:     "zero multiplier",
+			content:  "multiplier: 0",
+			expected: &workers.RateOverride{},
+		},
+		{
+			name:     "rate",
+			content:  "rate: 50/s",
+			expected: &workers.RateOverride{Rate: 50, Interval: time.Second},
+		},
+		{
+			name:     "rate with interval",
+			content:  "rate: 5/100ms",
+			expected: &workers.RateOverride{Rate: 5, Interval: 100 * time.Millisecond},
+		},
+		{
+			name:    "multiplier and rate",
+			content: "multiplier: 2\nrate: 50/s",
+			err:     "invalid rate override: set either multiplier or rate",
+		},
+		{
+			name:    "negative multiplier",
+			content: "multiplier: -1",
+			err:     "invalid rate override: multiplier -1 can't be negative",
+		},
+		{
+			name:    "invalid rate",
+			content: "rate: fast",
+			err:     "parsing rate override: unable to parse rate fast: strconv.Atoi: parsing \"fast\": invalid syntax",
+		},
+		{
+			name:    "zero interval",
+			content: "rate: 5/0s",
+			err:     "invalid rate override: rate 5/0s must have a positive interval",
+		},
+		{
+			name:    "unknown field",
+			content: "multipler: 2",
+			err:     "parsing rate override: yaml: unmarshal errors:\n  line 1: field multipler not found in type struct",
+		},
+		{
+			name:    "empty",
+			content: "# no override",
+			err:     "invalid rate override: missing multiplier or rate",
+		},
+		{
+			name:    "neither multiplier nor rate",
+			content: "rate: ''",
+			err:     "invalid rate override: missing multiplier or rate",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			override, err := run.ParseRateOverride([]byte(test.content))
+			if test.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, override)
+		})
+	}
+}
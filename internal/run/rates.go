@@ -0,0 +1,97 @@
+package run
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Rate counts the iterations planned by the trigger in a second of the run, its target rate, and
+// the iterations which started in that second, its achieved rate. An achieved rate below the
+// target shows the load generator couldn't keep up with the trigger.
+type Rate struct {
+	// Offset is the start of the second of the run
+	Offset   time.Duration `json:"offset"`
+	Target   uint64        `json:"target"`
+	Achieved uint64        `json:"achieved"`
+}
+
+// rates returns the target and achieved rates of the latest snapshot. The offsets of resumed runs
+// include the duration of the run before it was resumed, as for drops.
+func (r *Result) rates() []Rate {
+	if len(r.snapshot.Rates) == 0 {
+		return nil
+	}
+
+	rates := make([]Rate, len(r.snapshot.Rates))
+	for i, sample := range r.snapshot.Rates {
+		rates[i] = Rate{
+			Offset:   r.runOptions.Elapsed + sample.Offset,
+			Target:   sample.Target,
+			Achieved: sample.Achieved,
+		}
+	}
+
+	return rates
+}
+
+// combineRates adds up the rates of runs by the second of the run.
+func combineRates(a, b []Rate) []Rate {
+	byOffset := map[time.Duration]Rate{}
+	for _, rate := range slices.Concat(a, b) {
+		combined := byOffset[rate.Offset]
+		combined.Offset = rate.Offset
+		combined.Target += rate.Target
+		combined.Achieved += rate.Achieved
+		byOffset[rate.Offset] = combined
+	}
+	if len(byOffset) == 0 {
+		return nil
+	}
+
+	combined := make([]Rate, 0, len(byOffset))
+	for _, rate := range byOffset {
+		combined = append(combined, rate)
+	}
+	slices.SortFunc(combined, func(a, b Rate) int { return cmp.Compare(a.Offset, b.Offset) })
+
+	return combined
+}
+
+// RatePoints returns the target and achieved rates as the points of two svg polylines of the given
+// size, by the offset of their second of the run, scaled from 0 to the highest rate.
+func RatePoints(rates []Rate, width, height float64) (string, string) {
+	if len(rates) == 0 {
+		return "", ""
+	}
+
+	last := rates[len(rates)-1].Offset
+	var highest uint64
+	for _, rate := range rates {
+		highest = max(highest, rate.Target, rate.Achieved)
+	}
+
+	point := func(offset time.Duration, count uint64) string {
+		x := width / 2
+		if last > 0 {
+			x = float64(offset) * width / float64(last)
+		}
+		y := height
+		if highest > 0 {
+			y -= float64(count) / float64(highest) * height
+		}
+
+		return strconv.FormatFloat(x, 'f', 1, 64) + "," + strconv.FormatFloat(y, 'f', 1, 64)
+	}
+
+	target := make([]string, 0, len(rates))
+	achieved := make([]string, 0, len(rates))
+	for _, rate := range rates {
+		target = append(target, point(rate.Offset, rate.Target))
+		achieved = append(achieved, point(rate.Offset, rate.Achieved))
+	}
+
+	return strings.Join(target, " "), strings.Join(achieved, " ")
+}
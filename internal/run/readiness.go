@@ -0,0 +1,165 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+	"github.com/form3tech-oss/f1/v2/internal/workers"
+)
+
+// ErrTargetNotReady fails runs whose target didn't become ready within --ready-timeout.
+var ErrTargetNotReady = errors.New("target not ready")
+
+const (
+	// DefaultReadyTimeout is how long runs wait for their target to be ready by default.
+	DefaultReadyTimeout = 5 * time.Minute
+	// DefaultReadyInterval is how often the readiness of the target is checked by default.
+	DefaultReadyInterval = 5 * time.Second
+	// stageCheckInterval is how often the stage of the run is read, to check the readiness of the
+	// target when it changes
+	stageCheckInterval = 100 * time.Millisecond
+)
+
+// checksReadiness returns true if the run checks that its target is ready, with --ready-url or
+// the readiness function of the scenario.
+func (r *Run) checksReadiness() bool {
+	return r.options.ReadyURL != "" || r.readiness != nil
+}
+
+// checkReady returns why the target isn't ready, or nil if it is.
+func (r *Run) checkReady(ctx context.Context) error {
+	if r.options.ReadyURL != "" {
+		if err := checkReadyURL(ctx, r.options.ReadyURL, r.options.ReadyInterval); err != nil {
+			return err
+		}
+	}
+	if r.readiness != nil {
+		if err := r.readiness(ctx); err != nil {
+			return fmt.Errorf("checking the readiness of the scenario: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// checkReadyURL returns an error unless a GET of url succeeds within timeout.
+func checkReadyURL(ctx context.Context, url string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("creating readiness request: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("checking the readiness of %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("checking the readiness of %s: %s", url, resp.Status)
+	}
+
+	return nil
+}
+
+// waitUntilReady waits after the setup until the target is ready, so that a run started while the
+// target deploys or restarts doesn't produce meaningless results.
+func (r *Run) waitUntilReady(ctx context.Context) error {
+	if !r.checksReadiness() {
+		return nil
+	}
+
+	err := r.checkReady(ctx)
+	if err == nil {
+		return nil
+	}
+
+	return r.waitForReadiness(ctx, err)
+}
+
+// waitForReadiness checks the target every --ready-interval, after it wasn't ready because of
+// notReady, and returns ErrTargetNotReady if it still isn't ready after --ready-timeout. It
+// returns nil if the run is interrupted or stopped while waiting.
+func (r *Run) waitForReadiness(ctx context.Context, notReady error) error {
+	timeout := r.options.ReadyTimeout
+	if timeout == 0 {
+		return fmt.Errorf("%w: %w", ErrTargetNotReady, notReady)
+	}
+
+	r.output.Display(ui.WarningMessage{
+		Message: fmt.Sprintf("target not ready, waiting up to %s: %s", timeout, notReady),
+	})
+	r.tracer.Event("waiting for target readiness")
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(r.options.ReadyInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-deadline.C:
+			return fmt.Errorf("%w after %s: %w", ErrTargetNotReady, timeout, notReady)
+		case <-ctx.Done():
+			return nil
+		case <-r.stopCh:
+			return nil
+		case <-ticker.C:
+			if notReady = r.checkReady(ctx); notReady == nil {
+				r.output.Display(ui.InfoMessage{Message: "target ready"})
+				return nil
+			}
+		}
+	}
+}
+
+// gateStages checks that the target is ready whenever the stage of the run changes, and pauses the
+// triggers while waiting for it to be ready. The run is stopped and failed if it doesn't become
+// ready within --ready-timeout.
+func (r *Run) gateStages(ctx context.Context, poolManager *workers.PoolManager) {
+	if !r.checksReadiness() || r.trigger.StageAt == nil {
+		return
+	}
+
+	ticker := time.NewTicker(stageCheckInterval)
+	defer ticker.Stop()
+
+	start := time.Now()
+	stage := r.trigger.StageAt(r.options.Elapsed)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// the stage is empty once all the stages completed
+		current := r.trigger.StageAt(r.options.Elapsed + time.Since(start))
+		if current == stage || current == "" {
+			continue
+		}
+		stage = current
+
+		notReady := r.checkReady(ctx)
+		if notReady == nil {
+			continue
+		}
+
+		poolManager.Pause(true)
+		err := r.waitForReadiness(ctx, notReady)
+		poolManager.Pause(false)
+		if err != nil {
+			r.result.AddError(err)
+			r.stop("Target Not Ready")
+			return
+		}
+	}
+}
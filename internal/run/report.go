@@ -0,0 +1,306 @@
+package run
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/gcpause"
+	"github.com/form3tech-oss/f1/v2/internal/progress"
+	"github.com/form3tech-oss/f1/v2/internal/targetmetrics"
+)
+
+// Report is a machine-readable summary of a finished run. It is written by child processes of
+// `f1 orchestrate` so the parent process can combine their results.
+type Report struct {
+	Error                        string          `json:"error,omitempty"`
+	Scenario                     string          `json:"scenario"`
+	SuccessfulIterationDurations DurationsReport `json:"successful_iteration_durations"`
+	FailedIterationDurations     DurationsReport `json:"failed_iteration_durations"`
+	Duration                     time.Duration   `json:"duration"`
+	SetupDuration                time.Duration   `json:"setup_duration"`
+	TeardownDuration             time.Duration   `json:"teardown_duration"`
+	IterationsStarted            uint64          `json:"iterations_started"`
+	DroppedIterationCount        uint64          `json:"dropped_iteration_count"`
+	// Drops are the dropped iterations by the second of the run and stage they were planned in
+	Drops []Drop `json:"drops,omitempty"`
+	// Rates are the target and achieved rates by the second of the run
+	Rates []Rate `json:"rates,omitempty"`
+	// Stages are the iterations by the stage they started in, for triggers which run in stages
+	Stages []StageReport `json:"stages,omitempty"`
+	Failed bool          `json:"failed"`
+	// TargetMetrics are the metrics of the target system over the run window, if configured
+	TargetMetrics []targetmetrics.Metric `json:"target_metrics,omitempty"`
+	// GCPauses are the garbage collections of f1 and the iterations they overlapped, if annotated
+	GCPauses *gcpause.Report `json:"gc_pauses,omitempty"`
+	// MetricsPushFailures is the number of pushes of metrics to the push gateway which failed
+	MetricsPushFailures uint64 `json:"metrics_push_failures,omitempty"`
+	// AuditLogDropped is the number of iterations missing from the audit log of --audit-log
+	AuditLogDropped uint64 `json:"audit_log_dropped,omitempty"`
+	// FailureCategories are the failed iterations by their category, such as timeouts or server errors
+	FailureCategories []FailureCategoryReport `json:"failure_categories,omitempty"`
+	// FailuresByWorker are the workers with the most failed iterations, from the most failures
+	FailuresByWorker []WorkerFailuresReport `json:"failures_by_worker,omitempty"`
+	// FirstFailures and LastFailures are the first and the last failed iterations of the run
+	FirstFailures []Failure `json:"first_failures,omitempty"`
+	LastFailures  []Failure `json:"last_failures,omitempty"`
+	// Slowest are the slowest iterations of the run, from the slowest
+	Slowest []SlowIteration `json:"slowest_iterations,omitempty"`
+	// ScenarioSummary are the lines added by the scenario to document the side effects of the run
+	ScenarioSummary []string `json:"scenario_summary,omitempty"`
+	// IterationGaps are the iteration numbers skipped when the run was resumed with unique iterations
+	IterationGaps []IterationGap `json:"iteration_gaps,omitempty"`
+	// Build identifies the scenario binary which ran, if it was built from a version or revision
+	Build *Build `json:"build,omitempty"`
+	// Partial is set on the report snapshots written while the run is in progress, and on the
+	// reports of runs abandoned by a panic of f1, which only cover the run up to that time
+	Partial bool `json:"partial,omitempty"`
+	// PanicStack is the stack of the panic of f1 which abandoned the run, if any
+	PanicStack string `json:"panic_stack,omitempty"`
+	// QuotaDrops are the iterations dropped by operations capped by their concurrency quotas
+	QuotaDrops []QuotaDropsReport `json:"quota_drops,omitempty"`
+	// IterationsPlanned is the number of iterations the trigger planned to start over the load of
+	// the run, and PlanAchieved the percentage of them which started, for triggers which plan them
+	IterationsPlanned uint64  `json:"iterations_planned,omitempty"`
+	PlanAchieved      float64 `json:"plan_achieved,omitempty"`
+	// BytesSent and BytesReceived are the bytes recorded by the iterations of the scenario, see
+	// testing.T.RecordBytes
+	BytesSent     uint64 `json:"bytes_sent,omitempty"`
+	BytesReceived uint64 `json:"bytes_received,omitempty"`
+	// SLA are the operations of the run evaluated against their objectives in the SLA of the run
+	SLA []OperationCompliance `json:"sla,omitempty"`
+}
+
+type DurationsReport struct {
+	Count   uint64        `json:"count"`
+	Average time.Duration `json:"average"`
+	Min     time.Duration `json:"min"`
+	Max     time.Duration `json:"max"`
+	P50     time.Duration `json:"p50"`
+	P95     time.Duration `json:"p95"`
+	P99     time.Duration `json:"p99"`
+	// Histogram counts the durations, so that the quantiles of combined reports are estimated
+	// from the durations of all the runs
+	Histogram progress.Histogram `json:"histogram,omitempty"`
+}
+
+// ErrorRate returns the percentage of started iterations which failed.
+func (r Report) ErrorRate() float64 {
+	if r.IterationsStarted == 0 {
+		return 0
+	}
+
+	return 100 * float64(r.FailedIterationDurations.Count) / float64(r.IterationsStarted)
+}
+
+func newDurationsReport(s progress.IterationDurationsSnapshot) DurationsReport {
+	return DurationsReport{
+		Count:     s.Count,
+		Average:   s.Average,
+		Min:       s.Min,
+		Max:       s.Max,
+		P50:       s.P50,
+		P95:       s.P95,
+		P99:       s.P99,
+		Histogram: s.Histogram,
+	}
+}
+
+func (d DurationsReport) Snapshot() progress.IterationDurationsSnapshot {
+	return progress.IterationDurationsSnapshot{
+		Count:     d.Count,
+		Average:   d.Average,
+		Min:       d.Min,
+		Max:       d.Max,
+		P50:       d.P50,
+		P95:       d.P95,
+		P99:       d.P99,
+		Histogram: d.Histogram,
+	}
+}
+
+func (r *Result) Report() Report {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	drops := r.drops()
+	firstFailures, lastFailures := r.failures()
+	report := Report{
+		Scenario:                     r.runOptions.Scenario,
+		Build:                        r.build,
+		SuccessfulIterationDurations: newDurationsReport(r.snapshot.SuccessfulIterationDurations),
+		FailedIterationDurations:     newDurationsReport(r.snapshot.FailedIterationDurations),
+		Duration:                     r.loadDuration(),
+		SetupDuration:                r.SetupDuration,
+		TeardownDuration:             r.TeardownDuration,
+		IterationsStarted:            r.snapshot.IterationsStarted(),
+		DroppedIterationCount:        r.snapshot.DroppedIterationCount,
+		Drops:                        drops,
+		Rates:                        r.rates(),
+		Stages:                       r.stages(drops),
+		Failed:                       r.Failed(),
+		TargetMetrics:                r.targetMetrics,
+		GCPauses:                     r.gcPauses,
+		MetricsPushFailures:          r.metricsPushFailures,
+		AuditLogDropped:              r.auditLogDropped,
+		FailureCategories:            r.failureCategories(),
+		FailuresByWorker:             r.workerFailures(),
+		FirstFailures:                firstFailures,
+		LastFailures:                 lastFailures,
+		Slowest:                      r.slowest(),
+		ScenarioSummary:              slices.Clone(r.scenarioSummary),
+		Partial:                      r.panicStack != "",
+		PanicStack:                   r.panicStack,
+		IterationGaps:                slices.Clone(r.iterationGaps),
+		QuotaDrops:                   slices.Clone(r.quotaDrops),
+		IterationsPlanned:            r.plannedIterations,
+		PlanAchieved:                 planAchieved(r.snapshot.IterationsStarted(), r.plannedIterations),
+		BytesSent:                    r.snapshot.BytesSent,
+		BytesReceived:                r.snapshot.BytesReceived,
+		SLA:                          r.slaCompliance(),
+	}
+
+	if err := r.Error(); err != nil {
+		report.Error = err.Error()
+	}
+
+	return report
+}
+
+func (r *Result) WriteReport(path string) error {
+	data, err := json.Marshal(r.Report())
+	if err != nil {
+		return fmt.Errorf("marshalling report: %w", err)
+	}
+
+	if err := replaceFile(path, data); err != nil {
+		return fmt.Errorf("writing report file: %w", err)
+	}
+
+	return nil
+}
+
+func ReadReport(path string) (Report, error) {
+	report := Report{}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return report, fmt.Errorf("reading report file '%s': %w", path, err)
+	}
+
+	if err := json.Unmarshal(data, &report); err != nil {
+		return report, fmt.Errorf("parsing report file '%s': %w", path, err)
+	}
+
+	return report, nil
+}
+
+// CombineReports merges reports from runs executed in parallel into a single report.
+//
+// Counts are summed and averages are weighted by the number of iterations, the durations of the
+// load, setup and teardown phases are the longest of all runs. Quantiles are estimated from the
+// merged histograms of all runs, or are the highest quantiles of all runs, an upper bound of the
+// quantiles of the combined runs, when a report has no histogram. Every run captures the same
+// target metrics, so those of the first run capturing them are kept.
+func CombineReports(reports ...Report) Report {
+	combined := Report{}
+	var errs []string
+
+	for _, report := range reports {
+		if combined.Scenario == "" {
+			combined.Scenario = report.Scenario
+		}
+		if combined.Build == nil {
+			combined.Build = report.Build
+		}
+		if report.Error != "" {
+			errs = append(errs, report.Error)
+		}
+
+		combined.SuccessfulIterationDurations = combined.SuccessfulIterationDurations.combine(
+			report.SuccessfulIterationDurations)
+		combined.FailedIterationDurations = combined.FailedIterationDurations.combine(report.FailedIterationDurations)
+		combined.Duration = max(combined.Duration, report.Duration)
+		combined.SetupDuration = max(combined.SetupDuration, report.SetupDuration)
+		combined.TeardownDuration = max(combined.TeardownDuration, report.TeardownDuration)
+		combined.IterationsStarted += report.IterationsStarted
+		combined.DroppedIterationCount += report.DroppedIterationCount
+		combined.Drops = combineDrops(combined.Drops, report.Drops)
+		combined.Rates = combineRates(combined.Rates, report.Rates)
+		combined.Stages = combineStages(combined.Stages, report.Stages)
+		combined.Failed = combined.Failed || report.Failed
+		combined.MetricsPushFailures += report.MetricsPushFailures
+		combined.AuditLogDropped += report.AuditLogDropped
+		combined.FailureCategories = combineFailureCategories(combined.FailureCategories, report.FailureCategories)
+		combined.FailuresByWorker = combineWorkerFailures(combined.FailuresByWorker, report.FailuresByWorker)
+		combined.FirstFailures, combined.LastFailures = combineFailures(
+			combined.FirstFailures, combined.LastFailures, report)
+		combined.Slowest = combineSlowest(combined.Slowest, report.Slowest)
+		combined.ScenarioSummary = append(combined.ScenarioSummary, report.ScenarioSummary...)
+		combined.IterationGaps = append(combined.IterationGaps, report.IterationGaps...)
+		combined.QuotaDrops = combineQuotaDrops(combined.QuotaDrops, report.QuotaDrops)
+		combined.IterationsPlanned += report.IterationsPlanned
+		combined.BytesSent += report.BytesSent
+		combined.BytesReceived += report.BytesReceived
+		combined.SLA = combineSLACompliance(combined.SLA, report.SLA)
+		if len(combined.TargetMetrics) == 0 {
+			combined.TargetMetrics = report.TargetMetrics
+		}
+		if report.GCPauses != nil {
+			gcPauses := report.GCPauses.Combine(combined.GCPauses)
+			combined.GCPauses = &gcPauses
+		}
+	}
+
+	combined.Error = strings.Join(errs, "; ")
+	combined.PlanAchieved = planAchieved(combined.IterationsStarted, combined.IterationsPlanned)
+
+	return combined
+}
+
+func (d DurationsReport) combine(other DurationsReport) DurationsReport {
+	count := d.Count + other.Count
+	if count == 0 {
+		return d
+	}
+
+	minDuration := d.Min
+	if minDuration == 0 || (other.Min > 0 && other.Min < minDuration) {
+		minDuration = other.Min
+	}
+
+	weightedSum := float64(d.Average)*float64(d.Count) + float64(other.Average)*float64(other.Count)
+
+	combined := DurationsReport{
+		Count:   count,
+		Average: time.Duration(weightedSum / float64(count)),
+		Min:     minDuration,
+		Max:     max(d.Max, other.Max),
+		P50:     max(d.P50, other.P50),
+		P95:     max(d.P95, other.P95),
+		P99:     max(d.P99, other.P99),
+	}
+
+	if d.hasHistogram() && other.hasHistogram() {
+		combined.Histogram = d.Histogram.Merge(other.Histogram)
+		combined.P50 = combined.quantile(0.5)
+		combined.P95 = combined.quantile(0.95)
+		combined.P99 = combined.quantile(0.99)
+	}
+
+	return combined
+}
+
+// hasHistogram returns false for reports with durations but no histogram, which are written by
+// earlier versions of f1.
+func (d DurationsReport) hasHistogram() bool {
+	return d.Count == 0 || len(d.Histogram) > 0
+}
+
+// quantile estimates a quantile from the histogram, bounded by the min and max durations.
+func (d DurationsReport) quantile(q float64) time.Duration {
+	return min(max(d.Histogram.Quantile(q), d.Min), d.Max)
+}
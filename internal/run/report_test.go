@@ -0,0 +1,322 @@
+package run_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/metrics"
+	"github.com/form3tech-oss/f1/v2/internal/progress"
+	"github.com/form3tech-oss/f1/v2/internal/run"
+)
+
+func TestCombineReports(t *testing.T) {
+	t.Parallel()
+
+	combined := run.CombineReports(
+		run.Report{
+			Scenario: "scenario",
+			SuccessfulIterationDurations: run.DurationsReport{
+				Count: 10, Average: 2 * time.Millisecond, Min: time.Millisecond, Max: 3 * time.Millisecond,
+				P95: 3 * time.Millisecond,
+			},
+			Duration:          time.Second,
+			SetupDuration:     time.Second,
+			IterationsStarted: 10,
+		},
+		run.Report{
+			Scenario: "scenario",
+			SuccessfulIterationDurations: run.DurationsReport{
+				Count: 30, Average: 4 * time.Millisecond, Min: 2 * time.Millisecond, Max: 5 * time.Millisecond,
+				P95: 4 * time.Millisecond,
+			},
+			FailedIterationDurations: run.DurationsReport{
+				Count: 2, Average: time.Millisecond, Min: time.Millisecond, Max: time.Millisecond,
+			},
+			Duration:              2 * time.Second,
+			TeardownDuration:      time.Second,
+			IterationsStarted:     32,
+			DroppedIterationCount: 3,
+			Failed:                true,
+			Error:                 "teardown failed",
+		},
+	)
+
+	assert.Equal(t, run.Report{
+		Scenario: "scenario",
+		SuccessfulIterationDurations: run.DurationsReport{
+			Count: 40, Average: 3500 * time.Microsecond, Min: time.Millisecond, Max: 5 * time.Millisecond,
+			P95: 4 * time.Millisecond,
+		},
+		FailedIterationDurations: run.DurationsReport{
+			Count: 2, Average: time.Millisecond, Min: time.Millisecond, Max: time.Millisecond,
+		},
+		Duration:              2 * time.Second,
+		SetupDuration:         time.Second,
+		TeardownDuration:      time.Second,
+		IterationsStarted:     42,
+		DroppedIterationCount: 3,
+		Failed:                true,
+		Error:                 "teardown failed",
+	}, combined)
+}
+
+func TestCombineReportsMergesHistograms(t *testing.T) {
+	t.Parallel()
+
+	// one run is fast and the other one slow: the median of the combined runs is fast, while the
+	// highest median of the runs is slow
+	fast := &progress.Stats{}
+	slow := &progress.Stats{}
+	for range 60 {
+		fast.Record(metrics.SucessResult, time.Millisecond.Nanoseconds())
+	}
+	for range 40 {
+		slow.Record(metrics.SucessResult, 100*time.Millisecond.Nanoseconds())
+	}
+
+	report := func(stats *progress.Stats) run.Report {
+		snapshot := stats.Total().SuccessfulIterationDurations
+		return run.Report{
+			SuccessfulIterationDurations: run.DurationsReport{
+				Count: snapshot.Count, Average: snapshot.Average, Min: snapshot.Min, Max: snapshot.Max,
+				P50: snapshot.P50, P95: snapshot.P95, P99: snapshot.P99, Histogram: snapshot.Histogram,
+			},
+			IterationsStarted: snapshot.Count,
+		}
+	}
+
+	combined := run.CombineReports(report(fast), report(slow)).SuccessfulIterationDurations
+
+	assert.Equal(t, uint64(100), combined.Count)
+	// quantiles are estimated within 10% of the durations
+	assert.InEpsilon(t, time.Millisecond, combined.P50, 0.1)
+	assert.Equal(t, 100*time.Millisecond, combined.P95)
+	assert.Equal(t, 100*time.Millisecond, combined.P99)
+	assert.Len(t, combined.Histogram, 2)
+}
+
+func TestCombineReportsSumsDropsOfTheSameSecondAndStage(t *testing.T) {
+	t.Parallel()
+
+	combined := run.CombineReports(
+		run.Report{
+			DroppedIterationCount: 5,
+			Drops: []run.Drop{
+				{Offset: time.Second, Stage: "stage 0 (constant)", Count: 2},
+				{Offset: 2 * time.Second, Stage: "stage 1 (ramp)", Count: 3},
+			},
+		},
+		run.Report{},
+		run.Report{
+			DroppedIterationCount: 5,
+			Drops: []run.Drop{
+				{Offset: 0, Stage: "stage 0 (constant)", Count: 1},
+				{Offset: 2 * time.Second, Stage: "stage 1 (ramp)", Count: 4},
+			},
+		},
+	)
+
+	assert.Equal(t, uint64(10), combined.DroppedIterationCount)
+	assert.Equal(t, []run.Drop{
+		{Offset: 0, Stage: "stage 0 (constant)", Count: 1},
+		{Offset: time.Second, Stage: "stage 0 (constant)", Count: 2},
+		{Offset: 2 * time.Second, Stage: "stage 1 (ramp)", Count: 7},
+	}, combined.Drops)
+	assert.Empty(t, run.CombineReports(run.Report{}, run.Report{}).Drops)
+}
+
+func TestCombineReportsMergesStagesByName(t *testing.T) {
+	t.Parallel()
+
+	combined := run.CombineReports(
+		run.Report{
+			Stages: []run.StageReport{
+				{
+					Stage:                        "stage 0 (constant)",
+					SuccessfulIterationDurations: run.DurationsReport{Count: 10, Average: 10 * time.Millisecond},
+					IterationsStarted:            10,
+				},
+				{
+					Stage:                    "stage 1 (ramp)",
+					FailedIterationDurations: run.DurationsReport{Count: 1, Average: time.Second},
+					IterationsStarted:        1,
+					SLOViolations:            []string{"error rate 100.00% above 1.00%"},
+				},
+			},
+		},
+		run.Report{
+			Stages: []run.StageReport{
+				{
+					Stage:                        "stage 0 (constant)",
+					SuccessfulIterationDurations: run.DurationsReport{Count: 30, Average: 20 * time.Millisecond},
+					IterationsStarted:            30,
+					DroppedIterationCount:        2,
+				},
+			},
+		},
+	)
+
+	require.Len(t, combined.Stages, 2)
+	assert.Equal(t, "stage 0 (constant)", combined.Stages[0].Stage)
+	assert.Equal(t, uint64(40), combined.Stages[0].IterationsStarted)
+	assert.Equal(t, uint64(2), combined.Stages[0].DroppedIterationCount)
+	assert.Equal(t, 17500*time.Microsecond, combined.Stages[0].SuccessfulIterationDurations.Average)
+	assert.Empty(t, combined.Stages[0].SLOViolations)
+	assert.Equal(t, "stage 1 (ramp)", combined.Stages[1].Stage)
+	assert.Equal(t, []string{"error rate 100.00% above 1.00%"}, combined.Stages[1].SLOViolations)
+	assert.Empty(t, run.CombineReports(run.Report{}, run.Report{}).Stages)
+}
+
+func TestCombineReportsKeepsTheFirstAndLastFailures(t *testing.T) {
+	t.Parallel()
+
+	start := time.Now()
+	failures := func(iterations ...int) []run.Failure {
+		var failures []run.Failure
+		for _, i := range iterations {
+			failures = append(failures, run.Failure{
+				Iteration: strconv.Itoa(i),
+				Time:      start.Add(time.Duration(i) * time.Second),
+			})
+		}
+		return failures
+	}
+
+	combined := run.CombineReports(
+		run.Report{FirstFailures: failures(0, 2, 4, 6, 8), LastFailures: failures(20, 22)},
+		run.Report{FirstFailures: failures(1, 3, 5), LastFailures: nil},
+		run.Report{FirstFailures: failures(9, 11, 13, 15, 17), LastFailures: failures(19, 21, 23)},
+	)
+
+	assert.Equal(t, failures(0, 1, 2, 3, 4), combined.FirstFailures)
+	assert.Equal(t, failures(19, 20, 21, 22, 23), combined.LastFailures)
+	assert.Empty(t, run.CombineReports(run.Report{}, run.Report{}).FirstFailures)
+}
+
+func TestCombineReportsKeepsTheSummariesOfAllScenarios(t *testing.T) {
+	t.Parallel()
+
+	combined := run.CombineReports(
+		run.Report{ScenarioSummary: []string{"created 10 accounts", "deleted 10 accounts"}},
+		run.Report{},
+		run.Report{ScenarioSummary: []string{"created 12 accounts"}},
+	)
+
+	assert.Equal(t, []string{"created 10 accounts", "deleted 10 accounts", "created 12 accounts"}, combined.ScenarioSummary)
+}
+
+func TestCombineReportsKeepsTheWorkersWithTheMostFailures(t *testing.T) {
+	t.Parallel()
+
+	worker := func(name string, failures uint64) run.WorkerFailuresReport {
+		return run.WorkerFailuresReport{Worker: name, Failures: failures}
+	}
+
+	combined := run.CombineReports(
+		run.Report{FailuresByWorker: []run.WorkerFailuresReport{
+			worker("trigger-1", 40), worker("trigger-2", 4), worker("trigger-3", 3), worker("trigger-4", 2),
+		}},
+		run.Report{FailuresByWorker: []run.WorkerFailuresReport{worker("trigger-1", 5), worker("trigger-2", 1)}},
+	)
+
+	assert.Equal(t, []run.WorkerFailuresReport{
+		worker("trigger-1", 40), worker("trigger-1", 5), worker("trigger-2", 4), worker("trigger-3", 3), worker("trigger-4", 2),
+	}, combined.FailuresByWorker)
+}
+
+func TestCombineReportsMergesFailureCategories(t *testing.T) {
+	t.Parallel()
+
+	category := func(name string, count uint64, p95 time.Duration) run.FailureCategoryReport {
+		return run.FailureCategoryReport{
+			Category:  name,
+			Durations: run.DurationsReport{Count: count, Average: p95, Min: p95, Max: p95, P50: p95, P95: p95, P99: p95},
+		}
+	}
+
+	combined := run.CombineReports(
+		run.Report{FailureCategories: []run.FailureCategoryReport{
+			category("timeout", 4, 5*time.Second), category("5xx", 3, 10*time.Millisecond),
+		}},
+		run.Report{FailureCategories: []run.FailureCategoryReport{category("5xx", 6, 20*time.Millisecond)}},
+	)
+
+	require.Len(t, combined.FailureCategories, 2)
+	assert.Equal(t, "5xx", combined.FailureCategories[0].Category)
+	assert.Equal(t, uint64(9), combined.FailureCategories[0].Durations.Count)
+	assert.Equal(t, 20*time.Millisecond, combined.FailureCategories[0].Durations.P95)
+	assert.Equal(t, category("timeout", 4, 5*time.Second), combined.FailureCategories[1])
+	assert.Empty(t, run.CombineReports(run.Report{}, run.Report{}).FailureCategories)
+}
+
+func TestCombineReportsEvaluatesTheSLAOfTheMergedOperations(t *testing.T) {
+	t.Parallel()
+
+	maxErrorRate := 10.0
+	operation := func(successful, failed uint64) run.OperationCompliance {
+		return run.OperationCompliance{
+			Operation:                    "create",
+			SuccessfulIterationDurations: run.DurationsReport{Count: successful},
+			FailedIterationDurations:     run.DurationsReport{Count: failed},
+			SLO:                          run.SLO{MaxErrorRate: &maxErrorRate},
+			Compliant:                    true,
+		}
+	}
+
+	combined := run.CombineReports(
+		run.Report{SLA: []run.OperationCompliance{operation(9, 1)}},
+		run.Report{SLA: []run.OperationCompliance{operation(6, 4)}},
+	)
+
+	require.Len(t, combined.SLA, 1)
+	assert.Equal(t, uint64(15), combined.SLA[0].SuccessfulIterationDurations.Count)
+	assert.Equal(t, uint64(5), combined.SLA[0].FailedIterationDurations.Count)
+	assert.False(t, combined.SLA[0].Compliant)
+	assert.Equal(t, []string{"error rate 25.00% above 10.00%"}, combined.SLA[0].Violations)
+	assert.Empty(t, run.CombineReports(run.Report{}, run.Report{}).SLA)
+}
+
+func TestReportHTML(t *testing.T) {
+	t.Parallel()
+
+	page, err := run.Report{
+		Scenario:          "<payments>",
+		Failed:            true,
+		Error:             "max failures reached",
+		Duration:          2 * time.Second,
+		IterationsStarted: 30,
+		SuccessfulIterationDurations: run.DurationsReport{
+			Count: 27,
+			P95:   200 * time.Millisecond,
+		},
+		FailedIterationDurations: run.DurationsReport{Count: 3},
+		Rates: []run.Rate{
+			{Offset: 0, Target: 20, Achieved: 20},
+			{Offset: time.Second, Target: 20, Achieved: 10},
+		},
+	}.HTML()
+	require.NoError(t, err)
+
+	html := string(page)
+	assert.Contains(t, html, "<h1>&lt;payments&gt;</h1>")
+	assert.Contains(t, html, `<td class="failed">failed: max failures reached</td>`)
+	assert.Contains(t, html, `<td class="number">15.0/s</td>`)
+	assert.Contains(t, html, `<td class="number">10.00%</td>`)
+	assert.Contains(t, html, `<td class="number">200ms</td>`)
+	assert.Contains(t, html, `<polyline class="target" points="0.0,0.0 600.0,0.0"/>`)
+	assert.Contains(t, html, `<polyline points="0.0,0.0 600.0,60.0"/>`)
+}
+
+func TestReportHTMLWithoutRates(t *testing.T) {
+	t.Parallel()
+
+	page, err := run.Report{Scenario: "payments"}.HTML()
+	require.NoError(t, err)
+
+	assert.Contains(t, string(page), `<td class="passed">passed</td>`)
+	assert.NotContains(t, string(page), "<svg")
+}
@@ -3,25 +3,65 @@ package run
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/form3tech-oss/f1/v2/internal/gcpause"
 	"github.com/form3tech-oss/f1/v2/internal/options"
 	"github.com/form3tech-oss/f1/v2/internal/progress"
+	"github.com/form3tech-oss/f1/v2/internal/redact"
 	"github.com/form3tech-oss/f1/v2/internal/run/views"
+	"github.com/form3tech-oss/f1/v2/internal/targetmetrics"
 )
 
 type Result struct {
 	startTime     time.Time
 	progressStats *progress.Stats
+	// stageAt optionally returns the name of the stage planned after a duration of the run
+	stageAt       func(elapsed time.Duration) string
 	views         *views.Views
 	LogFilePath   string
 	errors        []error
 	runOptions    options.RunOptions
 	snapshot      progress.Snapshot
-	TestDuration  time.Duration
-	mu            sync.RWMutex
+	targetMetrics []targetmetrics.Metric
+	// gauges are the latest values of the progress gauges registered by the scenario
+	gauges []progress.Gauge
+	// gcPauses are the garbage collections of f1 during the run, if annotated
+	gcPauses *gcpause.Report
+	// failureSnapshots are the directories of the snapshots taken when the failure rate crossed
+	// the snapshot failure rate
+	failureSnapshots []string
+	// profiles is the directory of the profiles captured at the peak of the run, if any
+	profiles string
+	// build identifies the scenario binary of the run, if it was built from a version or revision
+	build *Build
+	// metricsPushFailures is the number of pushes of metrics to the push gateway which failed
+	metricsPushFailures uint64
+	// auditLogDropped is the number of iterations which couldn't be written to the audit log
+	auditLogDropped uint64
+	// scenarioSummary are the lines added to the summary of the run by the scenario
+	scenarioSummary []string
+	// iterationGaps are the iteration numbers skipped by the run and the runs it resumed
+	iterationGaps []IterationGap
+	// TestDuration is the duration of the load phase, excluding setup and teardown
+	TestDuration     time.Duration
+	SetupDuration    time.Duration
+	TeardownDuration time.Duration
+	mu               sync.RWMutex
+	// panicStack is the stack of the panic of f1 which abandoned the run, if any
+	panicStack string
+	// quotaDrops are the iterations dropped by operations capped by their concurrency quotas
+	quotaDrops []QuotaDropsReport
+	// redactor redacts the errors, failures and scenario summary of the run, see options.RunOptions.Redact
+	redactor *redact.Redactor
+	// plannedIterations is the number of iterations the trigger planned to start over the load of
+	// the run, 0 for triggers which don't plan them
+	plannedIterations uint64
+	// sla has the objectives the operations of the run are evaluated against, if set
+	sla *SLA
 }
 
 func NewResult(
@@ -43,6 +83,61 @@ func (r *Result) SnapshotProgress(period time.Duration) {
 	r.snapshot = r.progressStats.Snapshot(period)
 }
 
+// RecordGauges records the latest values of the progress gauges of the scenario.
+func (r *Result) RecordGauges(gauges []progress.Gauge) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.gauges = gauges
+}
+
+func (r *Result) AddFailureSnapshot(dir string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.failureSnapshots = append(r.failureSnapshots, dir)
+}
+
+// RecordProfiles records the directory of the profiles captured during the run.
+func (r *Result) RecordProfiles(dir string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.profiles = dir
+}
+
+// RecordMetricsPushFailure counts a push of metrics to the push gateway which failed.
+func (r *Result) RecordMetricsPushFailure() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.metricsPushFailures++
+}
+
+// RecordAuditLogDropped records the number of iterations which couldn't be written to the audit log.
+func (r *Result) RecordAuditLogDropped(dropped uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.auditLogDropped = dropped
+}
+
+// Profiles returns the directory of the profiles captured during the run, or an empty string.
+func (r *Result) Profiles() string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.profiles
+}
+
+// FailureSnapshots returns the directories of the diagnostic snapshots taken during the run.
+func (r *Result) FailureSnapshots() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return slices.Clone(r.failureSnapshots)
+}
+
 func (r *Result) GetTotals() {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -65,6 +160,15 @@ func (r *Result) AddError(err error) *Result {
 	return r
 }
 
+// RecordPanic fails the run with the error of a panic of f1, keeping its stack for the report.
+func (r *Result) RecordPanic(err error, stack string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.errors = append(r.errors, err)
+	r.panicStack = r.redactor.String(stack)
+}
+
 func (r *Result) Error() error {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -74,7 +178,7 @@ func (r *Result) Error() error {
 	}
 
 	if len(r.errors) == 1 {
-		return r.errors[0]
+		return r.redactor.Error(r.errors[0])
 	}
 
 	errorStrings := make([]string, len(r.errors))
@@ -82,7 +186,7 @@ func (r *Result) Error() error {
 		errorStrings[i] = fmt.Sprintf("Error %d: %s", i, r.errors[i].Error())
 	}
 
-	return errors.New(strings.Join(errorStrings, "; "))
+	return r.redactor.Error(errors.New(strings.Join(errorStrings, "; ")))
 }
 
 func (r *Result) Summary() *views.ViewContext[views.ResultData] {
@@ -94,13 +198,19 @@ func (r *Result) Summary() *views.ViewContext[views.ResultData] {
 		DroppedIterationCount:        r.snapshot.DroppedIterationCount,
 		FailedIterationCount:         r.snapshot.FailedIterationDurations.Count,
 		SuccessfulIterationDurations: r.snapshot.SuccessfulIterationDurations,
-		Duration:                     r.duration(),
+		Duration:                     r.loadDuration(),
+		SetupDuration:                r.SetupDuration,
+		TeardownDuration:             r.TeardownDuration,
 		FailedIterationDurations:     r.snapshot.FailedIterationDurations,
 		Error:                        r.Error(),
 		Failed:                       r.Failed(),
 		LogFilePath:                  r.LogFilePath,
 		Iterations:                   r.snapshot.Iterations(),
 		IterationsStarted:            r.snapshot.IterationsStarted(),
+		MetricsPushFailures:          r.metricsPushFailures,
+		PlannedIterations:            r.plannedIterations,
+		BytesSent:                    r.snapshot.BytesSent,
+		BytesReceived:                r.snapshot.BytesReceived,
 	})
 }
 
@@ -113,22 +223,43 @@ func (r *Result) Failed() bool {
 	return r.Error() != nil ||
 		(!opts.IgnoreDropped && r.snapshot.DroppedIterationCount > 0) ||
 		(opts.MaxFailures == 0 && opts.MaxFailuresRate == 0 && r.snapshot.FailedIterationDurations.Count > 0) ||
-		(opts.MaxFailures > 0 && r.snapshot.FailedIterationDurations.Count > opts.MaxFailures) ||
-		(opts.MaxFailuresRate > 0 && (r.snapshot.FailedIterationsRate() > uint64(opts.MaxFailuresRate)))
+		r.exceedsMaxFailures()
+}
+
+// maxFailuresExceeded returns true if the iterations failed more than allowed by the max failures
+// or the max failures rate, when they are set.
+func (r *Result) maxFailuresExceeded() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.exceedsMaxFailures()
+}
+
+func (r *Result) exceedsMaxFailures() bool {
+	opts := r.runOptions
+
+	return (opts.MaxFailures > 0 && r.snapshot.FailedIterationDurations.Count > opts.MaxFailures) ||
+		(opts.MaxFailuresRate > 0 && (r.snapshot.FailedIterationsRate() > float64(opts.MaxFailuresRate)))
 }
 
 func (r *Result) Progress() *views.ViewContext[views.ProgressData] {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	return r.views.Progress(views.ProgressData{
+	data := views.ProgressData{
 		Duration:                              r.duration(),
 		SuccessfulIterationDurationsForPeriod: r.snapshot.SuccessfulIterationDurationsForPeriod,
 		Period:                                r.snapshot.Period,
 		FailedIterationCount:                  r.snapshot.FailedIterationDurations.Count,
 		DroppedIterationCount:                 r.snapshot.DroppedIterationCount,
 		SuccessfulIterationCount:              r.snapshot.SuccessfulIterationDurations.Count,
-	})
+		Gauges:                                r.gauges,
+	}
+	if r.stageAt != nil {
+		data.Stage = r.stageAt(r.runOptions.Elapsed + data.Duration)
+	}
+
+	return r.views.Progress(data)
 }
 
 func (r *Result) HasDroppedIterations() bool {
@@ -174,10 +305,21 @@ func (r *Result) Interrupted() *views.ViewContext[views.InterruptData] {
 	})
 }
 
+func (r *Result) Stopped(reason string) *views.ViewContext[views.StoppedData] {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.views.Stopped(views.StoppedData{
+		Reason:   reason,
+		Duration: r.duration(),
+	})
+}
+
 func (r *Result) RecordStarted() {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	r.startTime = time.Now()
+	r.progressStats.Start(r.startTime)
 }
 
 func (r *Result) RecordTestFinished() {
@@ -187,6 +329,20 @@ func (r *Result) RecordTestFinished() {
 	r.TestDuration = time.Since(r.startTime)
 }
 
+func (r *Result) RecordSetupDuration(duration time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.SetupDuration = duration
+}
+
+func (r *Result) RecordTeardownDuration(duration time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.TeardownDuration = duration
+}
+
 func (r *Result) MaxIterationsReached() *views.ViewContext[views.MaxIterationsReachedData] {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -196,6 +352,15 @@ func (r *Result) MaxIterationsReached() *views.ViewContext[views.MaxIterationsRe
 	})
 }
 
+// loadDuration returns the duration of the load phase, which is still running until the test finished.
+func (r *Result) loadDuration() time.Duration {
+	if r.TestDuration > 0 {
+		return r.TestDuration
+	}
+
+	return r.duration()
+}
+
 func (r *Result) duration() time.Duration {
 	if r.startTime.IsZero() {
 		return 0
@@ -1,28 +1,45 @@
 package run
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/http/pprof"
+	"os"
 	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/form3tech-oss/f1/v2/internal/artifacts"
+	"github.com/form3tech-oss/f1/v2/internal/control"
+	"github.com/form3tech-oss/f1/v2/internal/cpupin"
 	"github.com/form3tech-oss/f1/v2/internal/envsettings"
+	"github.com/form3tech-oss/f1/v2/internal/memory"
 	"github.com/form3tech-oss/f1/v2/internal/metrics"
 	"github.com/form3tech-oss/f1/v2/internal/options"
+	"github.com/form3tech-oss/f1/v2/internal/racecheck"
+	"github.com/form3tech-oss/f1/v2/internal/tracing"
 	"github.com/form3tech-oss/f1/v2/internal/trigger/api"
 	"github.com/form3tech-oss/f1/v2/internal/triggerflags"
 	"github.com/form3tech-oss/f1/v2/internal/ui"
+	"github.com/form3tech-oss/f1/v2/internal/xcontext"
 	"github.com/form3tech-oss/f1/v2/pkg/f1/scenarios"
 )
 
-const waitForCompletionTimeout = 10 * time.Second
+const (
+	waitForCompletionTimeout = 10 * time.Second
+	artifactsUploadTimeout   = time.Minute
+	// progressEventsInterval is how often the control server streams the progress of the run
+	progressEventsInterval = time.Second
+)
 
 func Cmd(
 	s *scenarios.Scenarios,
 	builders []api.Builder,
 	settings envsettings.Settings,
 	metricsInstance *metrics.Metrics,
+	tracker *Tracker,
 	output *ui.Output,
 ) *cobra.Command {
 	runCmd := &cobra.Command{
@@ -34,12 +51,122 @@ func Cmd(
 		triggerCmd := &cobra.Command{
 			Use:   t.Name,
 			Short: t.Description,
-			RunE:  runCmdExecute(s, t, settings, metricsInstance, output),
-			Args:  cobra.MatchAll(cobra.ExactArgs(1)),
+			RunE:  runCmdExecute(s, t, settings, metricsInstance, tracker, output),
+			Args:  triggerArgs,
 		}
 
 		triggerCmd.Flags().BoolP(triggerflags.FlagVerbose, "v", false, "enables log output to stdout")
 		triggerCmd.Flags().Bool(triggerflags.FlagVerboseFail, false, "DEPRECATED: log output to stdout on failure")
+		triggerCmd.Flags().String(triggerflags.FlagReportFile, "", "write a json report of the run result to `file`")
+		triggerCmd.Flags().Lookup(triggerflags.FlagReportFile).Hidden = true
+		triggerCmd.Flags().String(triggerflags.FlagMaxMemory, "",
+			"--max-memory 2GiB (stop the run and fail if the memory used by f1 exceeds 2GiB)")
+		triggerCmd.Flags().String(triggerflags.FlagResume, "",
+			"save the progress of the run to `state-file` and resume it from there if it was interrupted")
+		triggerCmd.Flags().Bool(triggerflags.FlagUniqueIterations, false,
+			"with --resume, never start two iterations with the same number across resumes, by reserving the numbers "+
+				"in the state file before starting them, and report the numbers skipped")
+		triggerCmd.Flags().String(triggerflags.FlagControlAddr, "",
+			"serve the progress of the run as json on http://`address`/progress while it runs")
+		triggerCmd.Flags().String(triggerflags.FlagTrace, tracing.Off,
+			"trace the internals of the run to diagnose the scheduling of iterations, one of console|file|otlp|off")
+		triggerCmd.Flags().String(triggerflags.FlagTraceFile, tracing.DefaultFile,
+			"write the events of the file trace backend as json lines to `file`")
+		triggerCmd.Flags().String(triggerflags.FlagProgress, ui.ProgressAuto,
+			"show the progress updated live on a single line or as periodic full lines, one of auto|live|lines")
+		triggerCmd.Flags().Int(triggerflags.FlagSnapshotFailure, 0,
+			"--snapshot-failure-rate 10 (write a diagnostic snapshot when more than 10\\% iterations fail, default is 0)")
+		triggerCmd.Flags().String(triggerflags.FlagSnapshotDir, DefaultSnapshotDir,
+			"write the diagnostic snapshots of --snapshot-failure-rate and the profiles of --pprof-capture to `directory`")
+		triggerCmd.Flags().Int(triggerflags.FlagPprofPort, 0,
+			"serve the pprof profiles of f1 on http://localhost:`port`/debug/pprof/ while it runs")
+		triggerCmd.Flags().Duration(triggerflags.FlagPprofCapture, 0,
+			"--pprof-capture 30s (capture CPU and heap profiles of f1 for 30s at the peak rate of the run)")
+		triggerCmd.Flags().Int(triggerflags.FlagRequireMetrics, 0,
+			"--require-metrics 3 (stop the run and fail if more than 3 pushes of metrics to the push gateway fail in a row)")
+		triggerCmd.Flags().String(triggerflags.FlagStartAt, "",
+			"--start-at 2024-05-01T10:30:00Z (wait after the setup until the RFC3339 `time` to start triggering iterations)")
+		triggerCmd.Flags().String(triggerflags.FlagSyncBarrier, "",
+			"wait after the setup at the `barrier` served by `f1 barrier`, as host:port or url, to start triggering "+
+				"iterations together with the other processes waiting at it")
+		triggerCmd.Flags().String(triggerflags.FlagReadyURL, "",
+			"wait until a GET of `url` succeeds before starting iterations and between stages, so that load "+
+				"isn't applied while the target deploys or restarts")
+		triggerCmd.Flags().Duration(triggerflags.FlagReadyTimeout, DefaultReadyTimeout,
+			"how long to wait for the target to be ready before failing the run, 0 fails it if the target isn't ready")
+		triggerCmd.Flags().Duration(triggerflags.FlagReadyInterval, DefaultReadyInterval,
+			"how often the readiness of the target is checked while waiting for it")
+		triggerCmd.Flags().String(triggerflags.FlagPinWorkers, "",
+			"EXPERIMENTAL: pin each worker to one of `cpus` in turn, such as 2-7 or all, so that the scheduler "+
+				"doesn't move workers between cpus (linux only)")
+		triggerCmd.Flags().Duration(triggerflags.FlagWorkerRamp, 0,
+			"--worker-ramp 30s (start the workers gradually over 30s rather than all at once)")
+		triggerCmd.Flags().String(triggerflags.FlagWorkerRateLimit, "",
+			"--worker-rate-limit 5/s (each worker starts at most 5 iterations per second whatever the rate, "+
+				"like clients rate limited by the target)")
+		triggerCmd.Flags().Bool(triggerflags.FlagAutotuneConcurrency, false,
+			"adjust the workers taking iterations every second to the rate and latency of the iterations, "+
+				"by Little's law, up to --concurrency")
+		triggerCmd.Flags().String(triggerflags.FlagHistogramFile, "",
+			"record the durations of all iterations in an HDR histogram and write it to `file` (.hgrm)")
+		triggerCmd.Flags().String(triggerflags.FlagSummaryMetricsFile, "",
+			"write the metrics of the run to `file` once it completes, such as for the textfile collector of "+
+				"node_exporter or to compare runs offline")
+		triggerCmd.Flags().String(triggerflags.FlagSummaryMetricsFormat, metrics.SummaryOpenMetrics,
+			"the format of --summary-metrics-file, one of openmetrics|text, where text is the classic "+
+				"Prometheus text format")
+		triggerCmd.Flags().Duration(triggerflags.FlagHardDeadline, 0,
+			"--hard-deadline 30m (terminate f1 30 minutes after it started, even in the setup or teardown of the "+
+				"scenario, no deadline if 0)")
+		triggerCmd.Flags().StringArray(triggerflags.FlagTemplateVar, nil,
+			"--template-var team=payments (set the variable team read by templates with {{var \"team\"}}, "+
+				"can be repeated)")
+		triggerCmd.Flags().String(triggerflags.FlagTemplateDir, "",
+			"--template-dir templates (replace the built-in templates of the output, such as the summary, with "+
+				"the templates/<view>.tmpl files)")
+		triggerCmd.Flags().String(triggerflags.FlagOutputFormat, OutputFormatText,
+			"the format of the result of the run, one of text|json, where json writes the result as a json report "+
+				"to stdout, with the rest of the output on stderr, or to --output-file")
+		triggerCmd.Flags().String(triggerflags.FlagOutputFile, "",
+			"with --output-format json, write the json report of the result to `file` rather than to stdout")
+		triggerCmd.Flags().String(triggerflags.FlagJUnitReport, "",
+			"write the result of the run to `file` as a JUnit XML report, with the setup, iterations and teardown "+
+				"as test cases, for CI systems")
+		triggerCmd.Flags().String(triggerflags.FlagSLA, "",
+			"--sla sla.yaml (evaluate the operations of the scenario against their p95, p99 and error rate objectives "+
+				"in the yaml `file` once the run completes, failing the run if any operation doesn't meet them)")
+		triggerCmd.Flags().String(triggerflags.FlagAuditLog, "",
+			"record every iteration, with its id, start, duration, result, worker and audit fields, as a json line in `file`")
+		triggerCmd.Flags().Bool(triggerflags.FlagAnnotateGC, false,
+			"report the iterations which overlapped garbage collections of f1, to tell them apart from latency of the target")
+		triggerCmd.Flags().Bool(triggerflags.FlagShowSlowest, false,
+			"show the slowest iterations of the run, with when they started and their worker, in the summary")
+		triggerCmd.Flags().String(triggerflags.FlagRateOverride, "",
+			"watch `file` during the run for a multiplier or absolute rate overriding the rate of the trigger")
+		triggerCmd.Flags().StringArray(triggerflags.FlagRedact, nil,
+			"--redact 'card=(\\d+)' (replace the matches of the `regexp`, or of its groups, in the logs, errors, report "+
+				"and artifacts of the run with [REDACTED], repeatable)")
+		triggerCmd.Flags().Duration(triggerflags.FlagSLOMaxP95, 0,
+			"--slo-max-p95 500ms (stop the run when the p95 of successful iterations exceeds 500ms)")
+		triggerCmd.Flags().Duration(triggerflags.FlagSLOMaxP99, 0,
+			"--slo-max-p99 1s (stop the run when the p99 of successful iterations exceeds 1s)")
+		triggerCmd.Flags().Float64(triggerflags.FlagSLOMaxErrorRate, 0,
+			"--slo-max-error-rate 1.5 (stop the run when more than 1.5\\% of started iterations fail)")
+		triggerCmd.Flags().String(triggerflags.FlagReportSnapshot, "",
+			"write the json report of the run to `file` periodically while it runs")
+		triggerCmd.Flags().Duration(triggerflags.FlagReportInterval, DefaultReportSnapshotInterval,
+			"how often to write the report of --report-snapshot-file")
+		triggerCmd.Flags().Bool(triggerflags.FlagRaceCheck, false,
+			"before the run, run the scenario for --race-check-duration in a binary built with the race detector, "+
+				"and fail if it finds data races (builds the binary with go build -race unless f1 was)")
+		triggerCmd.Flags().Duration(triggerflags.FlagRaceCheckDuration, racecheck.DefaultDuration,
+			"how long to run the scenario for with --race-check")
+		triggerCmd.Flags().String(triggerflags.FlagConfirmAbove, settings.Confirm.AboveRate,
+			"--confirm-above 500/s (show the blast radius of runs planning a peak rate above 500/s and ask for "+
+				"confirmation before starting them)")
+		triggerCmd.Flags().BoolP(triggerflags.FlagYes, "y", false,
+			"confirm runs above --confirm-above or of scenarios annotated with a production environment "+
+				"without asking")
 
 		if !t.IgnoreCommonFlags {
 			triggerCmd.ValidArgs = s.GetScenarioNames()
@@ -47,6 +174,9 @@ func Cmd(
 			triggerCmd.Flags().Bool(triggerflags.FlagIgnoreDropped, false, "dropped requests will not fail the run")
 			triggerCmd.Flags().DurationP(triggerflags.FlagMaxDuration, "d", time.Second,
 				"--max-duration 1s (stop after 1 second)")
+			triggerCmd.Flags().Bool(triggerflags.FlagEndless, false,
+				"run until stopped by the control API, an interrupt, the --slo flags or the max failures, "+
+					"instead of for --max-duration")
 			triggerCmd.Flags().IntP(triggerflags.FlagConcurrency, "c", 100,
 				"--concurrency 2 (allow at most 2 groups of iterations to run concurrently)")
 			triggerCmd.Flags().Uint64P(triggerflags.FlagMaxIterations, "i", 0,
@@ -64,21 +194,52 @@ func Cmd(
 	return runCmd
 }
 
+// triggerArgs accepts the scenario, or the config file of triggers which ignore the common flags,
+// followed by any arguments passed to the scenario after `--`.
+func triggerArgs(cmd *cobra.Command, args []string) error {
+	args, _ = splitScenarioArgs(cmd, args)
+	return cobra.ExactArgs(1)(cmd, args)
+}
+
+// splitScenarioArgs splits the arguments of a trigger command from the arguments passed to the
+// scenario after `--`.
+func splitScenarioArgs(cmd *cobra.Command, args []string) ([]string, []string) {
+	dash := cmd.ArgsLenAtDash()
+	if dash < 0 {
+		return args, nil
+	}
+
+	return args[:dash], args[dash:]
+}
+
 func runCmdExecute(
 	s *scenarios.Scenarios,
 	t api.Builder,
 	settings envsettings.Settings,
 	metricsInstance *metrics.Metrics,
+	tracker *Tracker,
 	output *ui.Output,
 ) func(cmd *cobra.Command, args []string) error {
 	return func(cmd *cobra.Command, args []string) error {
 		cmd.SilenceUsage = true
 		cmd.SilenceErrors = true
+		started := time.Now()
+
+		args, scenarioArgs := splitScenarioArgs(cmd, args)
 
 		trig, err := t.New(cmd.Flags())
 		if err != nil {
 			return fmt.Errorf("creating trigger command: %w", err)
 		}
+		// the iterations planned by the trigger are counted once the run completes, with the rates of
+		// another trigger, as counting them runs the rates
+		if trig.PlannedIterations != nil {
+			planned, err := t.New(cmd.Flags())
+			if err != nil {
+				return fmt.Errorf("planning the iterations of the trigger: %w", err)
+			}
+			trig.PlannedIterations = planned.PlannedIterations
+		}
 
 		var scenarioName string
 		var duration time.Duration
@@ -87,6 +248,7 @@ func runCmdExecute(
 		var maxFailures uint64
 		var maxFailuresRate int
 		var ignoreDropped bool
+		var endless bool
 		if t.IgnoreCommonFlags {
 			scenarioName = trig.Options.Scenario
 			duration = trig.Options.MaxDuration
@@ -125,6 +287,16 @@ func runCmdExecute(
 			if err != nil {
 				return fmt.Errorf("getting flag: %w", err)
 			}
+			endless, err = cmd.Flags().GetBool(triggerflags.FlagEndless)
+			if err != nil {
+				return fmt.Errorf("getting flag: %w", err)
+			}
+			if endless {
+				if cmd.Flags().Changed(triggerflags.FlagMaxDuration) {
+					return fmt.Errorf("--%s can't be used with --%s", triggerflags.FlagEndless, triggerflags.FlagMaxDuration)
+				}
+				duration = EndlessDuration
+			}
 		}
 
 		verbose, err := cmd.Flags().GetBool(triggerflags.FlagVerbose)
@@ -140,6 +312,268 @@ func runCmdExecute(
 			output.Display(ui.WarningMessage{Message: "--verbose-fail option has been removed"})
 		}
 
+		stateFile, err := cmd.Flags().GetString(triggerflags.FlagResume)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		uniqueIterations, err := cmd.Flags().GetBool(triggerflags.FlagUniqueIterations)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		if uniqueIterations && stateFile == "" {
+			return fmt.Errorf("--%s requires --%s", triggerflags.FlagUniqueIterations, triggerflags.FlagResume)
+		}
+
+		maxMemoryFlag, err := cmd.Flags().GetString(triggerflags.FlagMaxMemory)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		var maxMemory uint64
+		if maxMemoryFlag != "" {
+			maxMemory, err = memory.ParseSize(maxMemoryFlag)
+			if err != nil {
+				return fmt.Errorf("parsing max memory: %w", err)
+			}
+		}
+
+		trace, err := cmd.Flags().GetString(triggerflags.FlagTrace)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		traceFile, err := cmd.Flags().GetString(triggerflags.FlagTraceFile)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		progressStyle, err := cmd.Flags().GetString(triggerflags.FlagProgress)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		snapshotFailureRate, err := cmd.Flags().GetInt(triggerflags.FlagSnapshotFailure)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		snapshotDir, err := cmd.Flags().GetString(triggerflags.FlagSnapshotDir)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		pprofCapture, err := cmd.Flags().GetDuration(triggerflags.FlagPprofCapture)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		requireMetrics, err := cmd.Flags().GetInt(triggerflags.FlagRequireMetrics)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		if requireMetrics < 0 {
+			return fmt.Errorf("--%s %d can't be negative", triggerflags.FlagRequireMetrics, requireMetrics)
+		}
+		startAtFlag, err := cmd.Flags().GetString(triggerflags.FlagStartAt)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		var startAt time.Time
+		if startAtFlag != "" {
+			startAt, err = time.Parse(time.RFC3339Nano, startAtFlag)
+			if err != nil {
+				return fmt.Errorf("parsing --%s: %w", triggerflags.FlagStartAt, err)
+			}
+			if startAt.Before(time.Now()) {
+				return fmt.Errorf("--%s %s has passed", triggerflags.FlagStartAt, startAtFlag)
+			}
+		}
+		syncBarrier, err := cmd.Flags().GetString(triggerflags.FlagSyncBarrier)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		if syncBarrier != "" && !startAt.IsZero() {
+			return fmt.Errorf("--%s and --%s can't be combined", triggerflags.FlagStartAt, triggerflags.FlagSyncBarrier)
+		}
+		readyURL, err := cmd.Flags().GetString(triggerflags.FlagReadyURL)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		readyTimeout, err := cmd.Flags().GetDuration(triggerflags.FlagReadyTimeout)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		if readyTimeout < 0 {
+			return fmt.Errorf("--%s %s can't be negative", triggerflags.FlagReadyTimeout, readyTimeout)
+		}
+		readyInterval, err := cmd.Flags().GetDuration(triggerflags.FlagReadyInterval)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		if readyInterval <= 0 {
+			return fmt.Errorf("--%s %s must be positive", triggerflags.FlagReadyInterval, readyInterval)
+		}
+		pinWorkers, err := cmd.Flags().GetString(triggerflags.FlagPinWorkers)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		var workerCPUs []int
+		if pinWorkers != "" {
+			workerCPUs, err = cpupin.Parse(pinWorkers)
+			if err != nil {
+				return fmt.Errorf("parsing --%s: %w", triggerflags.FlagPinWorkers, err)
+			}
+		}
+		workerRamp, err := cmd.Flags().GetDuration(triggerflags.FlagWorkerRamp)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		if workerRamp < 0 {
+			return fmt.Errorf("--%s %s can't be negative", triggerflags.FlagWorkerRamp, workerRamp)
+		}
+		workerRateLimit, err := cmd.Flags().GetString(triggerflags.FlagWorkerRateLimit)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		workerPacing, err := parseWorkerRateLimit(workerRateLimit)
+		if err != nil {
+			return err
+		}
+		autotuneConcurrency, err := cmd.Flags().GetBool(triggerflags.FlagAutotuneConcurrency)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		histogramFile, err := cmd.Flags().GetString(triggerflags.FlagHistogramFile)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		summaryMetricsFile, err := cmd.Flags().GetString(triggerflags.FlagSummaryMetricsFile)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		summaryMetricsFormat, err := cmd.Flags().GetString(triggerflags.FlagSummaryMetricsFormat)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		hardDeadlineFlag, err := cmd.Flags().GetDuration(triggerflags.FlagHardDeadline)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		if hardDeadlineFlag < 0 {
+			return fmt.Errorf("--%s %s can't be negative", triggerflags.FlagHardDeadline, hardDeadlineFlag)
+		}
+		var hardDeadline time.Time
+		if hardDeadlineFlag > 0 {
+			hardDeadline = started.Add(hardDeadlineFlag)
+		}
+		templateVars, err := cmd.Flags().GetStringArray(triggerflags.FlagTemplateVar)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		templateVariables, err := parseTemplateVariables(templateVars)
+		if err != nil {
+			return err
+		}
+		templateDir, err := cmd.Flags().GetString(triggerflags.FlagTemplateDir)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		outputFormat, err := cmd.Flags().GetString(triggerflags.FlagOutputFormat)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		outputFile, err := cmd.Flags().GetString(triggerflags.FlagOutputFile)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		junitReport, err := cmd.Flags().GetString(triggerflags.FlagJUnitReport)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		slaFile, err := cmd.Flags().GetString(triggerflags.FlagSLA)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		auditLog, err := cmd.Flags().GetString(triggerflags.FlagAuditLog)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		annotateGC, err := cmd.Flags().GetBool(triggerflags.FlagAnnotateGC)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		showSlowest, err := cmd.Flags().GetBool(triggerflags.FlagShowSlowest)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		rateOverrideFile, err := cmd.Flags().GetString(triggerflags.FlagRateOverride)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		redactPatterns, err := cmd.Flags().GetStringArray(triggerflags.FlagRedact)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		sloMaxP95, err := cmd.Flags().GetDuration(triggerflags.FlagSLOMaxP95)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		sloMaxP99, err := cmd.Flags().GetDuration(triggerflags.FlagSLOMaxP99)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		var sloMaxErrorRate *float64
+		if cmd.Flags().Changed(triggerflags.FlagSLOMaxErrorRate) {
+			maxErrorRate, err := cmd.Flags().GetFloat64(triggerflags.FlagSLOMaxErrorRate)
+			if err != nil {
+				return fmt.Errorf("getting flag: %w", err)
+			}
+			sloMaxErrorRate = &maxErrorRate
+		}
+		reportSnapshotFile, err := cmd.Flags().GetString(triggerflags.FlagReportSnapshot)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		reportSnapshotInterval, err := cmd.Flags().GetDuration(triggerflags.FlagReportInterval)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		if reportSnapshotFile != "" && reportSnapshotInterval <= 0 {
+			return fmt.Errorf("--%s must be positive", triggerflags.FlagReportInterval)
+		}
+
+		confirmAboveFlag, err := cmd.Flags().GetString(triggerflags.FlagConfirmAbove)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		confirmAbove, err := parseConfirmAbove(confirmAboveFlag)
+		if err != nil {
+			return err
+		}
+		yes, err := cmd.Flags().GetBool(triggerflags.FlagYes)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		radius, err := newBlastRadius(s.GetScenario(scenarioName), t, func() (*api.Trigger, error) {
+			return t.New(cmd.Flags())
+		}, duration, concurrency, confirmAbove)
+		if err != nil {
+			return err
+		}
+		if err := confirmRun(radius, yes, cmd.InOrStdin(), output); err != nil {
+			return err
+		}
+
+		raceCheck, err := cmd.Flags().GetBool(triggerflags.FlagRaceCheck)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		raceCheckDuration, err := cmd.Flags().GetDuration(triggerflags.FlagRaceCheckDuration)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		if raceCheck {
+			if raceCheckDuration <= 0 {
+				return fmt.Errorf("--%s must be positive", triggerflags.FlagRaceCheckDuration)
+			}
+			if err := checkRaces(cmd, args, scenarioArgs, raceCheckDuration, output); err != nil {
+				return err
+			}
+		}
+
 		if settings.Fluentd.Present() {
 			output.Display(ui.WarningMessage{
 				Message: fmt.Sprintf("WARNING: fluentd integration has been removed. %s and %s have no effect.",
@@ -152,22 +586,169 @@ func runCmdExecute(
 
 		run, err := NewRun(options.RunOptions{
 			Scenario:        scenarioName,
+			ScenarioArgs:    scenarioArgs,
 			MaxDuration:     duration,
 			Concurrency:     concurrency,
 			Verbose:         verbose,
 			MaxIterations:   maxIterations,
 			MaxFailures:     maxFailures,
 			MaxFailuresRate: maxFailuresRate,
+			MaxMemory:       maxMemory,
 			IgnoreDropped:   ignoreDropped,
+			StateFile:       stateFile,
+			Trace:           trace,
+			TraceFile:       traceFile,
+			Progress:        progressStyle,
+			HistogramFile:   histogramFile,
+			AuditLog:        auditLog,
+			AnnotateGC:      annotateGC,
+			ShowSlowest:     showSlowest,
+
+			RateOverrideFile: rateOverrideFile,
+			UniqueIterations: uniqueIterations,
+			RequireMetrics:   requireMetrics,
+			WorkerCPUs:       workerCPUs,
+			WorkerRamp:       workerRamp,
+			WorkerPacing:     workerPacing,
+			StartAt:          startAt,
+			SyncBarrier:      syncBarrier,
+			ReadyURL:         readyURL,
+			ReadyTimeout:     readyTimeout,
+			ReadyInterval:    readyInterval,
+			Endless:          endless,
+
+			AutotuneConcurrency: autotuneConcurrency,
+
+			Redact: redactPatterns,
+
+			SummaryMetricsFile:   summaryMetricsFile,
+			SummaryMetricsFormat: summaryMetricsFormat,
+
+			HardDeadline: hardDeadline,
+
+			TemplateVariables: templateVariables,
+			TemplateDir:       templateDir,
+
+			OutputFormat: outputFormat,
+			OutputFile:   outputFile,
+
+			JUnitReport: junitReport,
+
+			SLAFile: slaFile,
+
+			SLOMaxP95:       sloMaxP95,
+			SLOMaxP99:       sloMaxP99,
+			SLOMaxErrorRate: sloMaxErrorRate,
+
+			ReportSnapshotFile:     reportSnapshotFile,
+			ReportSnapshotInterval: reportSnapshotInterval,
+
+			SnapshotFailureRate: snapshotFailureRate,
+			SnapshotDir:         snapshotDir,
+			PprofCapture:        pprofCapture,
 		}, s, trig, waitForCompletionTimeout, settings, metricsInstance, output)
 		if err != nil {
 			return fmt.Errorf("new run: %w", err)
 		}
+		// with the json result on stdout, the messages of the command are written to stderr too
+		runOutput := run.parentOutput
+
+		controlAddr, err := cmd.Flags().GetString(triggerflags.FlagControlAddr)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		if controlAddr != "" {
+			server, err := control.New(controlAddr)
+			if err != nil {
+				return fmt.Errorf("starting control server: %w", err)
+			}
+			server.HandleJSON("GET /progress", func() any { return run.Progress() })
+			server.HandleEvents("GET /progress/events", "progress", progressEventsInterval, func() any {
+				return run.Progress()
+			})
+			server.HandleJSON("POST /stop", func() any {
+				run.Stop()
+				return run.Progress()
+			})
+			server.HandleAction("POST /stages/skip", func(*http.Request) (any, error) {
+				if err := run.SkipStage(); err != nil {
+					return nil, err
+				}
+				return run.Progress(), nil
+			})
+			server.HandleAction("POST /stages/jump", func(req *http.Request) (any, error) {
+				if err := run.JumpToStage(req.URL.Query().Get("stage")); err != nil {
+					return nil, err
+				}
+				return run.Progress(), nil
+			})
+			server.HandleAction("POST /verbose", func(*http.Request) (any, error) {
+				verbose, err := run.ToggleVerbose()
+				if err != nil {
+					return nil, err
+				}
+				return map[string]bool{"verbose": verbose}, nil
+			})
+			server.Start()
+			defer shutdownControlServer(server, runOutput)
+
+			runOutput.Display(ui.InfoMessage{Message: "Serving run progress on http://" + server.Addr() +
+				"/progress, streamed as server-sent events on http://" + server.Addr() + "/progress/events"})
+			if endless {
+				runOutput.Display(ui.InfoMessage{Message: "Stop the run with POST http://" + server.Addr() + "/stop"})
+			}
+			if len(trig.Stages) > 0 {
+				runOutput.Display(ui.InfoMessage{Message: "Skip the current stage with POST http://" + server.Addr() +
+					"/stages/skip, or jump to a stage with POST http://" + server.Addr() + "/stages/jump?stage=<name>"})
+			}
+		}
+
+		pprofPort, err := cmd.Flags().GetInt(triggerflags.FlagPprofPort)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		if pprofPort != 0 {
+			server, err := control.New(fmt.Sprintf("localhost:%d", pprofPort))
+			if err != nil {
+				return fmt.Errorf("starting pprof server: %w", err)
+			}
+			server.Handle("/debug/pprof/", http.HandlerFunc(pprof.Index))
+			server.Handle("/debug/pprof/cmdline", http.HandlerFunc(pprof.Cmdline))
+			server.Handle("/debug/pprof/profile", http.HandlerFunc(pprof.Profile))
+			server.Handle("/debug/pprof/symbol", http.HandlerFunc(pprof.Symbol))
+			server.Handle("/debug/pprof/trace", http.HandlerFunc(pprof.Trace))
+			server.Start()
+			defer shutdownControlServer(server, runOutput)
+
+			runOutput.Display(ui.InfoMessage{Message: "Serving pprof profiles on http://" + server.Addr() + "/debug/pprof/"})
+		}
+
+		tracker.track(run)
+		defer tracker.untrack(run)
+
 		result, err := run.Do(cmd.Context())
+		if errors.Is(err, ErrHardDeadlineExceeded) {
+			exitAfterHardDeadline(runOutput, os.Exit)
+			return err
+		}
 		if err != nil {
 			return fmt.Errorf("internal error on run: %w", err)
 		}
 
+		reportFile, err := cmd.Flags().GetString(triggerflags.FlagReportFile)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		if reportFile != "" {
+			if err := result.WriteReport(reportFile); err != nil {
+				return fmt.Errorf("writing report: %w", err)
+			}
+		}
+
+		if settings.Artifacts.Enabled() {
+			uploadArtifacts(cmd.Context(), settings.Artifacts, result, runOutput)
+		}
+
 		if result.Error() != nil {
 			return result.Error()
 		} else if result.Failed() {
@@ -177,3 +758,33 @@ func runCmdExecute(
 		return nil
 	}
 }
+
+func shutdownControlServer(server *control.Server, output *ui.Output) {
+	ctx, cancel := context.WithTimeout(context.Background(), waitForCompletionTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(ctx); err != nil {
+		output.Display(ui.ErrorMessage{Message: "shutting down control server", Error: err})
+	}
+}
+
+// uploadArtifacts uploads the artifacts of the run, a failed upload does not fail the run.
+func uploadArtifacts(ctx context.Context, settings envsettings.Artifacts, result *Result, output *ui.Output) {
+	store, err := artifacts.New(settings.URL, os.Getenv)
+	if err != nil {
+		output.Display(ui.ErrorMessage{Message: "unable to upload artifacts", Error: err})
+		return
+	}
+
+	// upload the artifacts of interrupted runs too
+	ctx, cancel := context.WithTimeout(xcontext.Detach(ctx), artifactsUploadTimeout)
+	defer cancel()
+
+	locations, err := result.UploadArtifacts(ctx, store, time.Now())
+	if len(locations) > 0 {
+		output.Display(result.Artifacts(locations))
+	}
+	if err != nil {
+		output.Display(ui.ErrorMessage{Message: "unable to upload artifacts", Error: err})
+	}
+}
@@ -3,6 +3,11 @@ package run_test
 import (
 	"testing"
 	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/metrics"
+	"github.com/form3tech-oss/f1/v2/internal/run"
+	"github.com/form3tech-oss/f1/v2/internal/trigger/file"
+	"github.com/form3tech-oss/f1/v2/internal/workers"
 )
 
 const Any = int64(-1)
@@ -671,6 +676,24 @@ func TestSetupMetricsAreRecorded(t *testing.T) {
 		there_is_a_metric_called("form3_loadtest_setup")
 }
 
+func TestIterationStageMetricsAreRecordedInRunMetrics(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+
+	given.
+		a_rate_of("10/s").and().
+		a_duration_of(500 * time.Millisecond).and().
+		a_scenario_where_each_iteration_times_stage("custom_stage")
+
+	when.the_run_command_is_executed()
+
+	then.
+		the_command_finished_successfully().and().
+		metrics_are_pushed_to_prometheus().and().
+		the_iteration_metric_has_stage("custom_stage")
+}
+
 func TestGroupedLabels(t *testing.T) {
 	t.Parallel()
 
@@ -688,143 +711,1623 @@ func TestGroupedLabels(t *testing.T) {
 		all_exported_metrics_contain_label("id", fakePrometheusID)
 }
 
-func TestFailureCounts(t *testing.T) {
+func TestRunScenarioWithClassifier(t *testing.T) {
 	t.Parallel()
 
 	given, when, then := NewRunTestStage(t)
-
 	given.
-		a_rate_of("10/s").and().
+		a_scenario_where_every_other_iteration_is_slower_than(20 * time.Millisecond).and().
+		a_rate_of("10/1s").and().
 		a_duration_of(500 * time.Millisecond).and().
-		a_test_scenario_that_fails_intermittently().and().
 		a_distribution_type("none")
 
 	when.the_run_command_is_executed()
 
+	then.the_results_should_show_n_failures(5).and().
+		the_results_should_show_n_successful_iterations(5).and().
+		setup_teardown_is_called().and().
+		iteration_teardown_is_called_n_times(10)
+}
+
+func TestResumeInterruptedRun(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_scenario_where_each_iteration_takes(10 * time.Millisecond).and().
+		a_rate_of("10/100ms").and().
+		a_duration_of(2 * time.Second).and().
+		a_distribution_type("none").and().
+		a_state_file()
+
+	when.the_run_command_is_executed_and_cancelled_after(1 * time.Second)
+
+	then.the_checkpoint_has_elapsed_approx(1 * time.Second)
+
+	when.the_run_command_is_executed()
+
+	then.the_command_should_have_run_for_approx(1 * time.Second).and().
+		the_state_file_is_removed()
+}
+
+func TestResumedRunDoesNotReuseIterationNumbers(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_scenario_recording_the_numbers_of_its_iterations().and().
+		a_rate_of("10/100ms").and().
+		a_duration_of(2 * time.Second).and().
+		a_distribution_type("none").and().
+		a_state_file().and().
+		unique_iterations()
+
+	when.the_run_command_is_executed_and_cancelled_after(500 * time.Millisecond)
+
+	then.the_checkpoint_reserves_iterations_up_to(workers.ReservedIterationsBlock)
+
+	when.the_run_command_is_executed()
+
+	then.the_resumed_run_numbers_its_iterations_after(workers.ReservedIterationsBlock).and().
+		the_state_file_is_removed()
+}
+
+func TestStageFailureBudgetStopsTheRun(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_trigger_type_of(File).and().
+		a_config_file_location_of("../testdata/config-file-failure-budget.yaml").and().
+		a_duration_of(10 * time.Second).and().
+		dropped_iterations_are_ignored().and().
+		a_test_scenario_that_always_fails()
+
+	when.a_timer_is_started().and().
+		the_run_command_is_executed()
+
+	// the stages would take 4s without the failure budget stopping the run
 	then.
-		metrics_are_pushed_to_prometheus().and().
-		there_is_a_metric_called("form3_loadtest_iteration").and().
-		the_iteration_metric_has_n_results(5, "success").and().
-		the_iteration_metric_has_n_results(5, "fail")
+		the_command_should_fail().and().
+		the_run_error_is(file.ErrFailureBudgetExceeded).and().
+		setup_teardown_is_called_within(3 * time.Second)
 }
 
-func TestParameterisedMaxFailures(t *testing.T) {
+func TestStagesCanBeSkippedAndJumpedTo(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_trigger_type_of(File).and().
+		a_config_file_location_of("../testdata/config-file-stage-jumps.yaml").and().
+		a_duration_of(10 * time.Second).and().
+		dropped_iterations_are_ignored().and().
+		a_scenario_where_each_iteration_takes(10 * time.Millisecond)
+
+	// the jumps are a second apart, so that the skip is taken by the trigger before the jump
+	when.a_timer_is_started().and().
+		the_run_command_is_executed_and_jumps_to_stages(
+			stageJump{after: 500 * time.Millisecond},
+			stageJump{after: 1500 * time.Millisecond, stage: "stage 2 (constant)"},
+		)
+
+	// the stages would take 5s without the jumps
+	then.
+		the_command_finished_successfully().and().
+		the_report_has_the_stages("stage 0 (constant)", "stage 1 (constant)", "stage 2 (constant)").and().
+		setup_teardown_is_called_within(4 * time.Second)
+}
+
+func TestJumpingToAnUnknownStageFails(t *testing.T) {
+	t.Parallel()
+
+	given, when, _ := NewRunTestStage(t)
+	given.
+		a_trigger_type_of(File).and().
+		a_config_file_location_of("../testdata/config-file-stage-jumps.yaml").and().
+		a_duration_of(10 * time.Second).and().
+		a_scenario_where_each_iteration_takes(10 * time.Millisecond)
+
+	when.jumping_to_stage_fails_with("stage 3 (constant)", run.ErrUnknownStage)
+}
+
+func TestStagesOfTriggersWithoutStagesCantBeSkipped(t *testing.T) {
+	t.Parallel()
+
+	given, when, _ := NewRunTestStage(t)
+	given.
+		a_rate_of("1/s").and().
+		a_trigger_type_of(Constant).and().
+		a_duration_of(time.Second).and().
+		a_scenario_where_each_iteration_takes(10 * time.Millisecond)
+
+	when.jumping_to_stage_fails_with("stage 0 (constant)", run.ErrStagesNotSkippable)
+}
+
+func TestProgressDuringRun(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_trigger_type_of(File).and().
+		a_config_file_location_of("../testdata/config-file.yaml").and().
+		a_duration_of(5 * time.Second).and().
+		a_scenario_where_each_iteration_takes(10 * time.Millisecond)
+
+	when.the_run_command_is_executed_and_progress_is_read_after(1100 * time.Millisecond)
+
+	then.
+		the_progress_is_at_stage("stage 3 (staged)").and().
+		the_progress_shows_successful_iterations()
+}
+
+func TestProgressShowsTheNamedStages(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_trigger_type_of(File).and().
+		a_config_file_location_of("../testdata/config-file-named-stages.yaml").and().
+		a_duration_of(5 * time.Second).and().
+		a_scenario_where_each_iteration_takes(10 * time.Millisecond)
+
+	when.the_run_command_is_executed_and_progress_is_read_after(1500 * time.Millisecond)
+
+	then.
+		the_progress_is_at_stage("stage 2/2: black-friday peak").and().
+		the_report_has_the_stages("stage 1/2: warm-up", "stage 2/2: black-friday peak").and().
+		expect_the_stdout_output_to_include([]string{
+			`msg="Starting stage 2/2: black-friday peak (ten times the usual traffic)"`,
+		})
+}
+
+func TestProfilesLabelTheGoroutinesOfTheRun(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_trigger_type_of(File).and().
+		a_config_file_location_of("../testdata/config-file-named-stages.yaml").and().
+		a_duration_of(5 * time.Second).and().
+		a_scenario_capturing_goroutine_profiles()
+
+	when.the_run_command_is_executed()
+
+	then.the_goroutines_are_labelled_in_the_profiles(
+		`"f1_goroutine":"worker", "scenario":"scenario_capturing_goroutine_profiles", `+
+			`"stage":"stage 1/2: warm-up", "worker":"trigger-`,
+		`"f1_goroutine":"worker", "scenario":"scenario_capturing_goroutine_profiles", `+
+			`"stage":"stage 2/2: black-friday peak", "worker":"trigger-`,
+		`"f1_goroutine":"progress", "scenario":"scenario_capturing_goroutine_profiles"`,
+		`"f1_goroutine":"trigger", "scenario":"scenario_capturing_goroutine_profiles"`,
+	)
+}
+
+func TestSleepsOfIterationsAreCutShortWhenTheRunStops(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_scenario_where_each_iteration_thinks_for(time.Minute).and().
+		a_rate_of("1/500ms").and().
+		a_duration_of(1 * time.Second).and().
+		a_timer_is_started()
+
+	when.the_run_command_is_executed()
+
+	then.the_command_finished_successfully().and().
+		the_sleeps_of_the_iterations_were_cut_short_within(5 * time.Second)
+}
+
+func TestProgressShowsTheGaugesOfTheScenario(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_rate_of("5/100ms").and().
+		a_duration_of(2 * time.Second).and().
+		a_distribution_type("none").and().
+		a_scenario_with_a_progress_gauge_of_accounts_created()
+
+	when.the_run_command_is_executed_and_progress_is_read_after(1500 * time.Millisecond)
+
+	then.the_progress_shows_the_accounts_created()
+}
+
+func TestIterationsLeaseEntitiesOfAFixturePool(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_rate_of("5/100ms").and().
+		a_duration_of(500*time.Millisecond).and().
+		a_distribution_type("none").and().
+		a_scenario_leasing_accounts_from_a_fixture_pool_of(3, 5)
+
+	when.the_run_command_is_executed()
+
+	then.the_fixture_pool_was_leased_one_iteration_at_a_time_discarding_every(5)
+}
+
+func TestMaxIterationsStartsTheExactNumberOfIterations(t *testing.T) {
 	t.Parallel()
 
 	for _, test := range []testParam{
 		{
-			name:            "pass with 5 max failures",
-			maxFailures:     5,
-			expectedFailure: false,
-		},
-		{
-			name:            "pass with superior max failures",
-			maxFailures:     6,
-			expectedFailure: false,
-		},
-		{
-			name:            "fails with inferior max failures",
-			maxFailures:     3,
-			expectedFailure: true,
-		},
-		{
-			name:            "pass with 50% max failures rate",
-			maxFailuresRate: 50,
-			expectedFailure: false,
-		},
-		{
-			name:            "pass with superior max failures rate",
-			maxFailuresRate: 60,
-			expectedFailure: false,
-		},
-		{
-			name:            "fails with inferior max failures rate",
-			maxFailuresRate: 30,
-			expectedFailure: true,
-		},
-		{
-			name:            "pass with inferior max failures and max failures rate",
-			maxFailures:     3,
-			maxFailuresRate: 30,
-			expectedFailure: true,
+			name:                   "constant rate above the max iterations",
+			triggerType:            Constant,
+			constantRate:           "200/10ms",
+			testDuration:           5 * time.Second,
+			concurrency:            500,
+			iterationDuration:      time.Millisecond,
+			maxIterations:          1234,
+			distributionType:       "none",
+			expectedRunTime:        60 * time.Millisecond,
+			expectedCompletedTests: 1234,
 		},
 		{
-			name:            "fails with inferior max failures rate and superior max failures",
-			maxFailures:     6,
-			maxFailuresRate: 30,
-			expectedFailure: true,
+			name:                   "users",
+			triggerType:            Users,
+			testDuration:           5 * time.Second,
+			concurrency:            64,
+			maxIterations:          9999,
+			expectedRunTime:        0,
+			expectedCompletedTests: 9999,
 		},
 		{
-			name:            "fails with inferior max failures and superior max failures rate",
-			maxFailures:     3,
-			maxFailuresRate: 60,
-			expectedFailure: true,
+			name:                   "config file with stages after the max iterations",
+			triggerType:            File,
+			configFile:             "../testdata/config-file-max-iterations.yaml",
+			testDuration:           5 * time.Second,
+			concurrency:            100,
+			maxIterations:          123,
+			expectedRunTime:        100 * time.Millisecond,
+			expectedCompletedTests: 123,
 		},
 	} {
 		t.Run(test.name, func(t *testing.T) {
 			t.Parallel()
 
 			given, when, then := NewRunTestStage(t)
-
 			given.
-				a_rate_of("10/100ms").and().
-				a_max_failures_of(test.maxFailures).and().
-				a_max_failures_rate_of(test.maxFailuresRate).and().
-				a_duration_of(100 * time.Millisecond).and().
-				a_test_scenario_that_fails_intermittently().and().
-				a_distribution_type("none")
+				a_trigger_type_of(test.triggerType).and().
+				a_rate_of(test.constantRate).and().
+				a_distribution_type(test.distributionType).and().
+				a_duration_of(test.testDuration).and().
+				a_concurrency_of(test.concurrency).and().
+				an_iteration_limit_of(test.maxIterations).and().
+				a_scenario_where_each_iteration_takes(test.iterationDuration).and().
+				a_config_file_location_of(test.configFile)
 
-			when.the_run_command_is_executed()
+			when.a_timer_is_started().and().
+				the_run_command_is_executed()
 
 			then.
-				the_iteration_metric_has_n_results(5, "success").and().
-				the_iteration_metric_has_n_results(5, "fail").and().
-				the_command_finished_with_failure_of(test.expectedFailure)
+				the_command_should_have_run_for_approx(test.expectedRunTime).and().
+				the_number_of_started_iterations_should_be(test.expectedCompletedTests).and().
+				the_results_should_show_n_successful_iterations(uint64(test.expectedCompletedTests)).and().
+				iteration_teardown_is_called_n_times(test.expectedCompletedTests)
 		})
 	}
 }
 
-func TestOutput_JSONLogging(t *testing.T) {
+func TestOperationsRunWithTheirOwnRates(t *testing.T) {
 	t.Parallel()
 
-	uiOnlyLogs := []logFieldMatchers{
-		{
-			"message":  anyValue,
-			"level":    "info",
-			"scenario": "scenario_where_each_iteration_takes_200ms",
-		},
-		{
-			"message":  "Running scenario_where_each_iteration_takes_200ms for up to 10 iterations or up to 10s at a rate of Makes requests from a set of users specified by --concurrency",
-			"level":    "info",
-			"scenario": "scenario_where_each_iteration_takes_200ms",
-		},
-		{
-			"message":         "progress",
-			"level":           "info",
-			"scenario":        "scenario_where_each_iteration_takes_200ms",
-			"iteration_stats": anyValue,
-		},
-		{
-			"message":         "progress",
-			"level":           "info",
-			"scenario":        "scenario_where_each_iteration_takes_200ms",
-			"iteration_stats": anyValue,
-		},
-		{
-			"message":  "Max Iterations Reached - waiting for active tests to complete",
-			"level":    "info",
-			"scenario": "scenario_where_each_iteration_takes_200ms",
-			"duration": anyValue,
-		},
-		{
-			"message":  "teardown completed",
-			"level":    "info",
-			"scenario": "scenario_where_each_iteration_takes_200ms",
-		},
-		{
-			"message":         "Load Test Passed",
-			"level":           "info",
-			"scenario":        "scenario_where_each_iteration_takes_200ms",
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_trigger_type_of(File).and().
+		a_config_file_location_of("../testdata/config-file-operations.yaml").and().
+		a_duration_of(5*time.Second).and().
+		a_scenario_with_operations("read", "write")
+
+	when.a_timer_is_started().and().
+		the_run_command_is_executed()
+
+	then.
+		the_command_finished_with_failure_of(false).and().
+		the_command_should_have_run_for_approx(500*time.Millisecond).and().
+		the_operation_should_have_run_n_times("read", 50).and().
+		the_operation_should_have_run_n_times("write", 10).and().
+		setup_teardown_is_called().and().
+		iteration_teardown_is_called_n_times(60).and().
+		the_iteration_metric_has_stage("write")
+}
+
+func TestOperationsAreCappedByTheirConcurrencyQuotas(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_trigger_type_of(File).and().
+		a_config_file_location_of("../testdata/config-file-operations-quota.yaml").and().
+		a_duration_of(5*time.Second).and().
+		a_scenario_with_operations("read", "write").and().
+		each_operation_takes(150 * time.Millisecond)
+
+	when.the_run_command_is_executed()
+
+	then.
+		the_operation_should_have_run_n_times("read", 25).and().
+		// 2 workers run at most 2 iterations every 150ms
+		the_operation_ran_at_most_n_times("write", 10).and().
+		the_iterations_of_the_operation_were_dropped_by_its_quota("write", 2)
+}
+
+func TestOperationsShareTheConcurrencyOfTheRun(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_trigger_type_of(File).and().
+		a_config_file_location_of("../testdata/config-file-operations-shared.yaml").and().
+		a_concurrency_of(6).and().
+		a_duration_of(5*time.Second).and().
+		a_scenario_with_operations("read", "write").and().
+		each_operation_takes(150 * time.Millisecond)
+
+	when.the_run_command_is_executed()
+
+	then.
+		at_most_n_operations_were_in_progress_at_once(6).and().
+		some_iterations_were_dropped()
+}
+
+func TestRateDropHook(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_trigger_type_of(File).and().
+		a_config_file_location_of("../testdata/config-file-rate-drop.yaml").and().
+		a_duration_of(5 * time.Second).and().
+		a_scenario_with_a_rate_drop_hook()
+
+	when.the_run_command_is_executed()
+
+	then.the_rate_drop_hook_is_called_once_at_stage("stage 1 (constant)")
+}
+
+func TestTheAchievedRateFollowsTheTargetRate(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_trigger_type_of(Constant).and().
+		a_rate_of("10/100ms").and().
+		a_duration_of(2 * time.Second).and().
+		a_concurrency_of(50).and().
+		a_scenario_where_each_iteration_takes(time.Millisecond)
+
+	when.the_run_command_is_executed()
+
+	then.the_achieved_rate_is_the_target_rate_every_second()
+}
+
+func TestTheAchievedRateFallsBelowTheTargetRateWithoutFreeWorkers(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_trigger_type_of(Constant).and().
+		a_rate_of("10/100ms").and().
+		a_duration_of(2 * time.Second).and().
+		a_concurrency_of(1).and().
+		a_scenario_where_each_iteration_takes(300 * time.Millisecond)
+
+	when.the_run_command_is_executed()
+
+	then.the_achieved_rate_is_below_the_target_rate_every_second()
+}
+
+func TestSlowestIterationsAreReported(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_trigger_type_of(Constant).and().
+		a_rate_of("10/100ms").and().
+		a_duration_of(500 * time.Millisecond).and().
+		a_concurrency_of(50).and().
+		the_slowest_iterations_are_shown().and().
+		a_scenario_timing_each_iteration_taking(time.Millisecond)
+
+	when.the_run_command_is_executed()
+
+	then.the_slowest_iterations_are_reported()
+}
+
+func TestDroppedIterationsAreRecordedByStage(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_trigger_type_of(File).and().
+		a_config_file_location_of("../testdata/config-file-drops.yaml").and().
+		a_duration_of(2 * time.Second).and().
+		a_concurrency_of(5).and().
+		a_scenario_where_each_iteration_takes(300 * time.Millisecond)
+
+	when.the_run_command_is_executed()
+
+	then.
+		the_iterations_were_only_dropped_in_stage_between("stage 1 (constant)", time.Second, 2*time.Second).and().
+		the_dropped_iterations_are_reported_by_stage("stage 1 (constant)")
+}
+
+func TestReportHasTheResultsOfEachStage(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_trigger_type_of(File).and().
+		a_config_file_location_of("../testdata/config-file-drops.yaml").and().
+		a_duration_of(2 * time.Second).and().
+		a_concurrency_of(5).and().
+		a_scenario_where_each_iteration_takes(10 * time.Millisecond).and().
+		an_slo_max_p95_of(time.Second).and().
+		dropped_iterations_are_ignored()
+
+	when.the_run_command_is_executed()
+
+	then.
+		the_command_finished_successfully().and().
+		the_report_has_the_stages("stage 0 (constant)", "stage 1 (constant)")
+}
+
+func TestParallelCleanupErrorsAreReported(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_rate_of("5/100ms").and().
+		a_duration_of(200 * time.Millisecond).and().
+		a_distribution_type("none").and().
+		a_scenario_with_parallel_cleanups_where_one_hangs_and_one_fails()
+
+	when.the_run_command_is_executed()
+
+	then.the_teardown_errors_are_reported_without_waiting_for_the_hanging_cleanup()
+}
+
+func TestTheSummaryOfTheScenarioIsReported(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_rate_of("5/100ms").and().
+		a_duration_of(200 * time.Millisecond).and().
+		a_distribution_type("none").and().
+		a_scenario_which_summarizes_its_side_effects_in_its_cleanup()
+
+	when.the_run_command_is_executed()
+
+	then.the_summary_of_the_scenario_is_reported()
+}
+
+func TestEndlessRunIsStopped(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_rate_of("5/100ms").and().
+		an_endless_run().and().
+		a_distribution_type("none").and().
+		a_scenario_where_each_iteration_takes(1 * time.Millisecond)
+
+	when.the_run_command_is_executed_and_stopped_after(500 * time.Millisecond)
+
+	then.the_command_finished_successfully().and().
+		the_command_should_have_run_for_approx(500 * time.Millisecond).and().
+		the_run_is_reported_as_stopped_because("Stop Requested").and().
+		setup_teardown_is_called()
+}
+
+func TestEndlessRunStopsWhenMaxFailuresAreExceeded(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_rate_of("5/100ms").and().
+		an_endless_run().and().
+		a_distribution_type("none").and().
+		a_max_failures_of(5).and().
+		a_test_scenario_that_always_fails()
+
+	// cancel the run if it isn't stopped, rather than running it endlessly
+	when.the_run_command_is_executed_and_cancelled_after(10 * time.Second)
+
+	then.the_command_should_fail().and().
+		the_command_should_have_run_for_approx(1 * time.Second).and().
+		the_run_is_reported_as_stopped_because("Max Failures Exceeded")
+}
+
+func TestRunStartsAtTheStartTime(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_rate_of("10/100ms").and().
+		a_duration_of(500 * time.Millisecond).and().
+		a_start_time_in(500 * time.Millisecond).and().
+		a_scenario_where_each_iteration_takes(1 * time.Millisecond)
+
+	when.the_run_command_is_executed()
+
+	then.the_command_finished_successfully().and().
+		no_iteration_started_before_the_start_time()
+}
+
+func TestRunWaitsForTheTargetToBeReady(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_rate_of("10/100ms").and().
+		a_duration_of(300 * time.Millisecond).and().
+		a_target_ready_in(300 * time.Millisecond).and().
+		a_scenario_where_each_iteration_takes(1 * time.Millisecond)
+
+	when.the_run_command_is_executed()
+
+	then.the_command_finished_successfully().and().
+		no_iteration_started_before_the_target_was_ready()
+}
+
+func TestRunWaitsForTheReadinessOfTheScenario(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_rate_of("10/100ms").and().
+		a_duration_of(300 * time.Millisecond).and().
+		a_scenario_ready_in(300 * time.Millisecond)
+
+	when.the_run_command_is_executed()
+
+	then.the_command_finished_successfully().and().
+		no_iteration_started_before_the_target_was_ready()
+}
+
+func TestRunFailsWhenTheTargetIsNotReadyInTime(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_rate_of("10/100ms").and().
+		a_duration_of(300 * time.Millisecond).and().
+		a_target_never_ready_within(200 * time.Millisecond).and().
+		a_scenario_where_each_iteration_takes(1 * time.Millisecond)
+
+	when.the_run_command_is_executed()
+
+	then.the_command_should_fail().and().
+		the_run_error_is(run.ErrTargetNotReady).and().
+		setup_teardown_is_called()
+}
+
+func TestTriggersArePausedBetweenStagesUntilTheTargetIsReady(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_trigger_type_of(Staged).and().
+		a_stage_of("300ms:5, 600ms:5").and().
+		an_iteration_frequency_of("50ms").and().
+		a_duration_of(time.Second).and().
+		a_target_restarting_for(300 * time.Millisecond).and().
+		a_scenario_where_each_iteration_takes(1 * time.Millisecond)
+
+	when.the_run_command_is_executed()
+
+	then.the_command_finished_successfully().and().
+		no_iteration_started_while_the_target_restarted()
+}
+
+func TestRunFailsWhenTheStartTimeIsMissed(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_rate_of("10/100ms").and().
+		a_duration_of(500 * time.Millisecond).and().
+		a_start_time_in(-time.Second).and().
+		a_scenario_where_each_iteration_takes(1 * time.Millisecond)
+
+	when.the_run_command_is_executed()
+
+	then.the_command_should_fail().and().
+		the_run_error_is(run.ErrStartMissed).and().
+		setup_teardown_is_called()
+}
+
+func TestRunStartsWhenReleasedByTheBarrier(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_rate_of("10/100ms").and().
+		a_duration_of(500 * time.Millisecond).and().
+		a_barrier_releasing_every_process_after(300 * time.Millisecond).and().
+		a_scenario_where_each_iteration_takes(1 * time.Millisecond)
+
+	when.the_run_command_is_executed()
+
+	then.the_command_finished_successfully().and().
+		no_iteration_started_before_the_start_time()
+}
+
+func TestWorkersArePinnedToCPUs(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_rate_of("10/100ms").and().
+		a_duration_of(500 * time.Millisecond).and().
+		a_concurrency_of(4).and().
+		workers_pinned_to_the_available_cpus().and().
+		a_scenario_which_records_the_cpus_it_runs_on()
+
+	when.the_run_command_is_executed()
+
+	then.the_command_finished_successfully().and().
+		each_iteration_ran_on_one_of_the_worker_cpus()
+}
+
+func TestWorkerLocalValuesAreKeptPerWorker(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_rate_of("20/100ms").and().
+		a_duration_of(500 * time.Millisecond).and().
+		a_concurrency_of(4).and().
+		a_scenario_where_each_iteration_reads_a_worker_local_value()
+
+	when.the_run_command_is_executed()
+
+	then.the_command_finished_successfully().and().
+		each_worker_created_one_value_closed_when_it_stopped()
+}
+
+func TestWorkersAreStartedGraduallyOverTheWorkerRamp(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_trigger_type_of(Users).and().
+		a_duration_of(time.Second).and().
+		a_concurrency_of(10).and().
+		workers_ramped_over(500 * time.Millisecond).and().
+		a_scenario_recording_when_each_worker_starts()
+
+	when.the_run_command_is_executed()
+
+	then.the_command_finished_successfully().and().
+		the_workers_started_gradually_over(500 * time.Millisecond)
+}
+
+func TestWorkersArePacedByTheirRateLimit(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_rate_of("10/100ms").and().
+		a_duration_of(time.Second).and().
+		a_concurrency_of(5).and().
+		a_distribution_type("none").and().
+		workers_paced_every(500 * time.Millisecond).and().
+		a_scenario_where_each_iteration_takes(time.Millisecond)
+
+	when.the_run_command_is_executed()
+
+	// every worker starts its iterations 500ms apart, at most 3 times in a run of 1s
+	then.the_command_finished_with_failure_of(true).and().
+		the_number_of_started_iterations_should_be_between(5, 15).and().
+		some_iterations_were_dropped()
+}
+
+func TestUsersArePacedByTheirRateLimit(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_trigger_type_of(Users).and().
+		a_duration_of(time.Second).and().
+		a_concurrency_of(2).and().
+		workers_paced_every(200 * time.Millisecond).and().
+		a_scenario_where_each_iteration_takes(time.Millisecond)
+
+	when.the_run_command_is_executed()
+
+	then.the_command_finished_successfully().and().
+		the_number_of_started_iterations_should_be_between(8, 12)
+}
+
+func TestConcurrencyIsAutotunedToTheRateAndLatency(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_rate_of("10/100ms").and().
+		a_duration_of(3 * time.Second).and().
+		a_concurrency_of(100).and().
+		the_concurrency_is_autotuned().and().
+		dropped_iterations_are_ignored().and().
+		a_scenario_where_each_iteration_takes(50 * time.Millisecond)
+
+	when.the_run_command_is_executed()
+
+	// 100 iterations per second taking 50ms require 5 workers, 7 with headroom, while iterations
+	// may be dropped as the workers shrink
+	then.the_command_finished_successfully().and().
+		the_concurrency_was_autotuned_to_between(5, 14)
+}
+
+func TestLogsFailuresAndReportAreRedacted(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_log_file().and().
+		a_rate_of("5/100ms").and().
+		a_duration_of(300*time.Millisecond).and().
+		the_patterns_are_redacted(`card=(\d+)`, `[\w.]+@[\w.]+`).and().
+		a_scenario_failing_with_card_numbers()
+
+	when.the_run_command_is_executed()
+
+	then.the_command_should_fail().and().
+		the_card_numbers_are_redacted_from_the_logs_and_the_report()
+}
+
+func TestVerboseLoggingIsToggledWhileTheRunIsInProgress(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		verbose_flag_is(true).and().
+		a_log_file().and().
+		a_trigger_type_of(Users).and().
+		a_concurrency_of(1).and().
+		a_duration_of(500 * time.Millisecond).and().
+		a_scenario_logging_its_iterations()
+
+	when.the_run_command_is_executed_and_logs_are_toggled_after(200 * time.Millisecond)
+
+	then.the_command_finished_successfully().and().
+		the_iterations_were_logged_to_the_console_and_then_to_the_log_file()
+}
+
+func TestIterationsReadThePhaseOfTheRun(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_trigger_type_of(Staged).and().
+		a_stage_of("400ms:10, 400ms:10").and().
+		an_iteration_frequency_of("50ms").and().
+		a_duration_of(800 * time.Millisecond).and().
+		a_scenario_recording_the_phase_of_its_iterations()
+
+	when.the_run_command_is_executed()
+
+	then.the_command_finished_successfully().and().
+		the_iterations_ran_in_stages_starting_at(map[string]time.Duration{
+			"stage 0 (0 to 10)":  0,
+			"stage 1 (10 to 10)": 400 * time.Millisecond,
+		})
+}
+
+func TestFailuresIsolatedToAWorkerAreReported(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_trigger_type_of(Users).and().
+		a_concurrency_of(4).and().
+		a_duration_of(300 * time.Millisecond).and().
+		a_scenario_where_the_iterations_of_one_worker_fail()
+
+	when.the_run_command_is_executed()
+
+	then.the_command_should_fail().and().
+		the_failures_are_reported_as_isolated_to_one_worker()
+}
+
+func TestSearchFindsTheHighestRateMeetingTheTarget(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_search_from("1/100ms", "32/100ms", 300*time.Millisecond).and().
+		a_duration_of(10 * time.Second).and().
+		a_concurrency_of(50).and().
+		a_scenario_failing_bursts_larger_than(10)
+
+	when.the_run_command_is_executed()
+
+	then.the_search_found_a_capacity_of("10/100ms").and().
+		the_run_completed_within(5 * time.Second)
+}
+
+func TestTokenBucketStartsABurstThenTheRate(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_token_bucket_of("1/100ms", 20).and().
+		a_duration_of(time.Second).and().
+		a_concurrency_of(50).and().
+		a_scenario_where_each_iteration_takes(time.Millisecond)
+
+	when.the_run_command_is_executed()
+
+	then.the_first_iterations_started_in_a_burst_of(20).and().
+		the_number_of_started_iterations_should_be_between(27, 31)
+}
+
+func TestConnectionsAreRampedAndHeldOpen(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		connections_ramped_from(0, 10, 500*time.Millisecond).and().
+		a_duration_of(time.Second).and().
+		a_scenario_holding_connections_open_where_the_first_is_dropped()
+
+	when.the_run_command_is_executed()
+
+	then.the_command_should_have_run_for_approx(time.Second).and().
+		the_connections_were_held_open_and_closed_at_the_end_of_the_run(10)
+}
+
+func TestFailuresAreSummarisedByCategory(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_rate_of("10/100ms").and().
+		a_duration_of(500 * time.Millisecond).and().
+		a_distribution_type("none").and().
+		a_scenario_failing_with_fast_server_errors_and_slow_timeouts(50 * time.Millisecond)
+
+	when.the_run_command_is_executed()
+
+	then.
+		the_command_should_fail().and().
+		the_failures_are_summarised_by_category(50*time.Millisecond, "5xx", "timeout")
+}
+
+func TestFirstAndLastFailuresAreReported(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_rate_of("5/100ms").and().
+		a_duration_of(500 * time.Millisecond).and().
+		a_distribution_type("none").and().
+		a_test_scenario_that_always_fails()
+
+	when.the_run_command_is_executed()
+
+	then.the_command_should_fail().and().
+		the_first_and_last_failed_iterations_are_reported()
+}
+
+func TestRunStopsWhenTheSLOIsBreached(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_scenario_where_each_iteration_takes(20 * time.Millisecond).and().
+		a_rate_of("50/100ms").and().
+		a_duration_of(5 * time.Second).and().
+		a_distribution_type("none").and().
+		an_slo_max_p95_of(10 * time.Millisecond)
+
+	when.the_run_command_is_executed()
+
+	then.the_command_should_fail().and().
+		the_command_should_have_run_for_approx(1 * time.Second).and().
+		the_run_error_is(run.ErrSLOBreached).and().
+		the_run_is_reported_as_stopped_because("SLO Breached")
+}
+
+func TestRunIsNotStoppedWhenTheSLOIsMet(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_scenario_where_each_iteration_takes(1 * time.Millisecond).and().
+		a_rate_of("50/100ms").and().
+		a_duration_of(1500 * time.Millisecond).and().
+		a_distribution_type("none").and().
+		an_slo_max_p95_of(time.Second).and().
+		an_slo_max_error_rate_of(0)
+
+	when.the_run_command_is_executed()
+
+	then.the_command_finished_successfully().and().
+		the_command_should_have_run_for_approx(1500 * time.Millisecond)
+}
+
+func TestFailedMetricsPushesAreReported(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_rate_of("5/100ms").and().
+		a_duration_of(500 * time.Millisecond).and().
+		a_scenario_where_each_iteration_takes(1 * time.Millisecond).and().
+		a_push_gateway_which_fails()
+
+	when.the_run_command_is_executed()
+
+	then.the_command_finished_successfully().and().
+		the_failed_metrics_pushes_are_reported(2)
+}
+
+func TestMetricsArePushedEveryPushInterval(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_rate_of("1/1s").and().
+		a_duration_of(1 * time.Second).and().
+		a_scenario_where_each_iteration_takes(1 * time.Millisecond).and().
+		a_push_gateway_which_counts_the_pushes().and().
+		metrics_pushed_every(50 * time.Millisecond)
+
+	when.the_run_command_is_executed()
+
+	// the setup and teardown pushes, and a push every interval
+	then.the_command_finished_successfully().and().
+		the_metrics_were_pushed_between(12, 22)
+}
+
+func TestUnchangedMetricsArePushedOnlyOnHeartbeat(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_rate_of("1/1s").and().
+		a_duration_of(1 * time.Second).and().
+		a_scenario_where_each_iteration_takes(1 * time.Millisecond).and().
+		a_push_gateway_which_counts_the_pushes().and().
+		metrics_pushed_every(50 * time.Millisecond).and().
+		unchanged_metrics_pushed_every(time.Minute)
+
+	when.the_run_command_is_executed()
+
+	// the setup and teardown pushes, and the pushes of the metrics of the only iteration
+	then.the_command_finished_successfully().and().
+		the_metrics_were_pushed_between(3, 5)
+}
+
+func TestRunFailsWhenRequiredMetricsCantBePushed(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_rate_of("5/100ms").and().
+		a_duration_of(500 * time.Millisecond).and().
+		a_scenario_where_each_iteration_takes(1 * time.Millisecond).and().
+		a_push_gateway_which_fails().and().
+		metrics_required_with_at_most_failed_pushes_in_a_row(1)
+
+	when.the_run_command_is_executed()
+
+	then.the_command_should_fail().and().
+		the_run_error_is(run.ErrMetricsPushFailed).and().
+		the_failed_metrics_pushes_are_reported(2)
+}
+
+func TestReportSnapshotFile(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_rate_of("5/100ms").and().
+		a_duration_of(2 * time.Second).and().
+		a_distribution_type("none").and().
+		a_scenario_where_each_iteration_takes(1 * time.Millisecond).and().
+		a_report_snapshot_file_written_every(200 * time.Millisecond)
+
+	// snapshots report the progress of the run, which is updated every second
+	when.the_report_snapshot_file_is_read_after(1500 * time.Millisecond).and().
+		the_run_command_is_executed()
+
+	then.the_report_snapshot_was_written_during_the_run().and().
+		the_report_snapshot_file_has_the_final_report()
+}
+
+func TestPanicOfTheRunIsRecovered(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_rate_of("5/100ms").and().
+		a_duration_of(2 * time.Second).and().
+		a_distribution_type("none").and().
+		a_scenario_tracking_the_iterations_in_progress_taking(200 * time.Millisecond).and().
+		a_report_snapshot_file_written_every(time.Minute).and().
+		a_trigger_that_panics_after(300 * time.Millisecond)
+
+	when.the_run_command_is_executed()
+
+	then.the_run_reports_the_panic().and().
+		setup_teardown_is_called().and().
+		no_iteration_was_in_progress_at_teardown().and().
+		the_report_snapshot_file_has_the_partial_report()
+}
+
+func TestPanicOfAGoroutineOfTheRunIsRecovered(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_rate_of("5/100ms").and().
+		a_duration_of(2 * time.Second).and().
+		a_distribution_type("none").and().
+		a_scenario_tracking_the_iterations_in_progress_taking(200 * time.Millisecond).and().
+		a_report_snapshot_file_written_every(time.Minute).and().
+		a_goroutine_of_the_trigger_that_panics_after(300 * time.Millisecond)
+
+	when.the_run_command_is_executed()
+
+	then.the_run_reports_the_panic().and().
+		setup_teardown_is_called().and().
+		no_iteration_was_in_progress_at_teardown().and().
+		the_report_snapshot_file_has_the_partial_report()
+}
+
+func TestHistogramFile(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_rate_of("5/100ms").and().
+		a_duration_of(500 * time.Millisecond).and().
+		a_distribution_type("none").and().
+		a_scenario_where_each_iteration_takes(time.Millisecond).and().
+		a_histogram_file()
+
+	when.the_run_command_is_executed()
+
+	then.the_histogram_file_counts_every_iteration()
+}
+
+func TestSummaryMetricsFile(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		format   string
+		expected []string
+	}{
+		{
+			format: "openmetrics",
+			expected: []string{
+				"# TYPE form3_loadtest_iterations_started counter",
+				`form3_loadtest_iterations_started_total{test="scenario_where_each_iteration_takes_1ms"} 25.0`,
+				"# EOF",
+			},
+		},
+		{
+			format: "text",
+			expected: []string{
+				"# TYPE form3_loadtest_iterations_started_total counter",
+				`form3_loadtest_iterations_started_total{test="scenario_where_each_iteration_takes_1ms"} 25`,
+			},
+		},
+	} {
+		t.Run(test.format, func(t *testing.T) {
+			t.Parallel()
+
+			given, when, then := NewRunTestStage(t)
+			given.
+				a_rate_of("5/100ms").and().
+				a_duration_of(500 * time.Millisecond).and().
+				a_distribution_type("none").and().
+				a_scenario_where_each_iteration_takes(time.Millisecond).and().
+				a_summary_metrics_file_in_format(test.format)
+
+			when.the_run_command_is_executed()
+
+			then.the_summary_metrics_file_contains(test.expected...)
+		})
+	}
+}
+
+func TestAuditLog(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_rate_of("5/100ms").and().
+		a_duration_of(500 * time.Millisecond).and().
+		a_distribution_type("none").and().
+		a_scenario_setting_the_audit_field("payment_id").and().
+		an_audit_log()
+
+	when.the_run_command_is_executed()
+
+	then.the_audit_log_records_n_iterations_with_the_field(25, "payment_id")
+}
+
+func TestThroughputOfIterationBytes(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_rate_of("5/100ms").and().
+		a_duration_of(500*time.Millisecond).and().
+		a_distribution_type("none").and().
+		a_scenario_recording_the_bytes_of_each_iteration(262144, 100)
+
+	when.the_run_command_is_executed()
+
+	then.the_throughput_of_n_iterations_is_reported(25, 262144, 100)
+}
+
+func TestGCPauses(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_rate_of("5/100ms").and().
+		a_duration_of(500 * time.Millisecond).and().
+		a_distribution_type("none").and().
+		a_scenario_where_each_iteration_collects_garbage().and().
+		iterations_are_annotated_with_gc_pauses()
+
+	when.the_run_command_is_executed()
+
+	then.n_of_m_iterations_overlapped_a_collection(25, 25)
+}
+
+func TestGCPausesAreNotReportedByDefault(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_rate_of("5/100ms").and().
+		a_duration_of(500 * time.Millisecond).and().
+		a_distribution_type("none").and().
+		a_scenario_where_each_iteration_collects_garbage()
+
+	when.the_run_command_is_executed()
+
+	then.gc_pauses_are_not_reported()
+}
+
+func TestRateOverride(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name       string
+		override   string
+		iterations int64
+		output     string
+	}{
+		{
+			name:       "multiplier",
+			override:   "multiplier: 2",
+			iterations: 50,
+			output:     "Rate overridden by %s: x2",
+		},
+		{
+			name:       "absolute rate",
+			override:   "rate: 20/100ms",
+			iterations: 100,
+			output:     "Rate overridden by %s: 20/100ms",
+		},
+		{
+			name:       "invalid override",
+			override:   "multiplier: -2",
+			iterations: 25,
+			output:     "ignoring rate override %s: invalid rate override: multiplier -2 can't be negative",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			given, when, then := NewRunTestStage(t)
+			given.
+				a_rate_of("5/100ms").and().
+				a_duration_of(500 * time.Millisecond).and().
+				a_distribution_type("none").and().
+				a_scenario_where_each_iteration_takes(time.Millisecond).and().
+				a_rate_override_file_of(test.override)
+
+			when.the_run_command_is_executed()
+
+			then.
+				the_number_of_started_iterations_should_be(test.iterations).and().
+				the_rate_override_is_reported(test.output)
+		})
+	}
+}
+
+func TestRateOverrideRemovedDuringTheRun(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_rate_of("5/100ms").and().
+		a_duration_of(2 * time.Second).and().
+		a_distribution_type("none").and().
+		a_scenario_where_each_iteration_takes(time.Millisecond).and().
+		a_rate_override_file_of("multiplier: 0").and().
+		the_rate_override_file_is_removed_after(200 * time.Millisecond)
+
+	when.the_run_command_is_executed()
+
+	then.some_iterations_started_after_the_override_was_removed()
+}
+
+func TestFailureSnapshot(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_rate_of("10/100ms").and().
+		a_duration_of(1500 * time.Millisecond).and().
+		a_distribution_type("none").and().
+		a_test_scenario_that_fails_intermittently().and().
+		a_snapshot_failure_rate_of(10)
+
+	when.the_run_command_is_executed()
+
+	then.a_failure_snapshot_is_written()
+}
+
+func TestNoFailureSnapshotBelowTheFailureRate(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_rate_of("10/100ms").and().
+		a_duration_of(1500 * time.Millisecond).and().
+		a_distribution_type("none").and().
+		a_scenario_where_each_iteration_takes(time.Millisecond).and().
+		a_snapshot_failure_rate_of(10)
+
+	when.the_run_command_is_executed()
+
+	then.no_failure_snapshot_is_written()
+}
+
+func TestTraceToFile(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_rate_of("5/100ms").and().
+		a_duration_of(200 * time.Millisecond).and().
+		an_iteration_limit_of(8).and().
+		a_scenario_where_each_iteration_takes(time.Millisecond).and().
+		a_trace_file()
+
+	when.the_run_command_is_executed()
+
+	then.the_trace_file_has_events(
+		"setup started",
+		"setup completed",
+		"trigger started",
+		"pool started",
+		"jobs triggered",
+		"worker waiting for jobs",
+		"worker woken",
+		"iteration started",
+		"iteration completed",
+		"max iterations reached",
+		"pool stopped",
+		"trigger stopped",
+		"teardown completed",
+	)
+}
+
+func TestUploadArtifacts(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_rate_of("5/100ms").and().
+		a_duration_of(200 * time.Millisecond).and().
+		a_scenario_where_each_iteration_takes(time.Millisecond)
+
+	when.
+		the_run_command_is_executed().and().
+		the_artifacts_are_uploaded()
+
+	then.the_reports_and_log_file_are_uploaded()
+}
+
+func TestProfilesAreCapturedAtThePeakRate(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_rate_of("5/100ms").and().
+		a_duration_of(3 * time.Second).and().
+		a_distribution_type("none").and().
+		a_scenario_where_each_iteration_takes(time.Millisecond).and().
+		profiles_captured_for(500 * time.Millisecond)
+
+	when.
+		the_run_command_is_executed().and().
+		the_artifacts_are_uploaded()
+
+	then.
+		the_profiles_are_written().and().
+		the_profiles_are_uploaded()
+}
+
+func TestProfilesAreNotCapturedByDefault(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_rate_of("5/100ms").and().
+		a_duration_of(2 * time.Second).and().
+		a_distribution_type("none").and().
+		a_scenario_where_each_iteration_takes(time.Millisecond)
+
+	when.the_run_command_is_executed()
+
+	then.no_profiles_are_written()
+}
+
+func TestSetupAndTeardownAreExcludedFromLoadDuration(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_rate_of("5/100ms").and().
+		a_duration_of(500 * time.Millisecond).and().
+		a_scenario_where_setup_and_teardown_take(300 * time.Millisecond).and().
+		terminal_is_interactive(true)
+
+	when.the_run_command_is_executed()
+
+	then.the_phase_durations_are_reported_separately(300*time.Millisecond, 500*time.Millisecond)
+}
+
+func TestIterationLabelsAreRecorded(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+
+	given.
+		a_rate_of("10/100ms").and().
+		a_duration_of(500 * time.Millisecond).and().
+		a_scenario_where_each_iteration_labels_one_of_n_endpoints(3).and().
+		a_distribution_type("none")
+
+	when.the_run_command_is_executed()
+
+	then.
+		metrics_are_pushed_to_prometheus().and().
+		the_label_metric_has_n_values(3)
+}
+
+func TestIterationLabelValuesAreLimited(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+
+	given.
+		a_rate_of("10/100ms").and().
+		a_duration_of(500 * time.Millisecond).and().
+		a_scenario_where_each_iteration_labels_one_of_n_endpoints(metrics.MaxLabelValues * 2).and().
+		a_distribution_type("none")
+
+	when.the_run_command_is_executed()
+
+	then.
+		metrics_are_pushed_to_prometheus().and().
+		the_label_metric_has_n_values(metrics.MaxLabelValues + 1)
+}
+
+func TestIterationLabelNamesAreLimited(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+
+	given.
+		a_rate_of("10/100ms").and().
+		a_duration_of(500 * time.Millisecond).and().
+		a_scenario_where_each_iteration_sets_one_of_n_labels(metrics.MaxLabelNames * 2).and().
+		a_distribution_type("none")
+
+	when.the_run_command_is_executed()
+
+	then.
+		metrics_are_pushed_to_prometheus().and().
+		the_label_metric_has_n_labels(metrics.MaxLabelNames + 1)
+}
+
+func TestSummaryIsComparedWithPreviousRun(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_scenario_where_each_iteration_takes(10 * time.Millisecond).and().
+		a_rate_of("10/100ms").and().
+		a_duration_of(200 * time.Millisecond).and().
+		a_distribution_type("none").and().
+		a_history_dir()
+
+	when.the_run_command_is_executed()
+
+	then.the_history_has_n_runs(1)
+
+	when.the_run_command_is_executed()
+
+	then.the_history_has_n_runs(2).and().
+		expect_the_stdout_output_to_include([]string{
+			"Compared to previous run",
+		})
+}
+
+func TestRunStopsWhenMaxMemoryIsExceeded(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_scenario_where_each_iteration_takes(10 * time.Millisecond).and().
+		a_rate_of("10/100ms").and().
+		a_duration_of(5 * time.Second).and().
+		a_distribution_type("none").and().
+		a_max_memory_of(1)
+
+	when.the_run_command_is_executed()
+
+	then.the_command_should_fail().and().
+		the_command_should_have_run_for_approx(500 * time.Millisecond).and().
+		the_run_error_is(run.ErrMaxMemoryExceeded).and().
+		setup_teardown_is_called().and().
+		metrics_are_pushed_to_prometheus()
+}
+
+func TestFailureCounts(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+
+	given.
+		a_rate_of("10/s").and().
+		a_duration_of(500 * time.Millisecond).and().
+		a_test_scenario_that_fails_intermittently().and().
+		a_distribution_type("none")
+
+	when.the_run_command_is_executed()
+
+	then.
+		metrics_are_pushed_to_prometheus().and().
+		there_is_a_metric_called("form3_loadtest_iteration").and().
+		the_iteration_metric_has_n_results(5, "success").and().
+		the_iteration_metric_has_n_results(5, "fail")
+}
+
+func TestParameterisedMaxFailures(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []testParam{
+		{
+			name:            "pass with 5 max failures",
+			maxFailures:     5,
+			expectedFailure: false,
+		},
+		{
+			name:            "pass with superior max failures",
+			maxFailures:     6,
+			expectedFailure: false,
+		},
+		{
+			name:            "fails with inferior max failures",
+			maxFailures:     3,
+			expectedFailure: true,
+		},
+		{
+			name:            "pass with 50% max failures rate",
+			maxFailuresRate: 50,
+			expectedFailure: false,
+		},
+		{
+			name:            "pass with superior max failures rate",
+			maxFailuresRate: 60,
+			expectedFailure: false,
+		},
+		{
+			name:            "fails with inferior max failures rate",
+			maxFailuresRate: 30,
+			expectedFailure: true,
+		},
+		{
+			name:            "pass with inferior max failures and max failures rate",
+			maxFailures:     3,
+			maxFailuresRate: 30,
+			expectedFailure: true,
+		},
+		{
+			name:            "fails with inferior max failures rate and superior max failures",
+			maxFailures:     6,
+			maxFailuresRate: 30,
+			expectedFailure: true,
+		},
+		{
+			name:            "fails with inferior max failures and superior max failures rate",
+			maxFailures:     3,
+			maxFailuresRate: 60,
+			expectedFailure: true,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			given, when, then := NewRunTestStage(t)
+
+			given.
+				a_rate_of("10/100ms").and().
+				a_max_failures_of(test.maxFailures).and().
+				a_max_failures_rate_of(test.maxFailuresRate).and().
+				a_duration_of(100 * time.Millisecond).and().
+				a_test_scenario_that_fails_intermittently().and().
+				a_distribution_type("none")
+
+			when.the_run_command_is_executed()
+
+			then.
+				the_iteration_metric_has_n_results(5, "success").and().
+				the_iteration_metric_has_n_results(5, "fail").and().
+				the_command_finished_with_failure_of(test.expectedFailure)
+		})
+	}
+}
+
+func TestOutput_JSONLogging(t *testing.T) {
+	t.Parallel()
+
+	uiOnlyLogs := []logFieldMatchers{
+		{
+			"message":  anyValue,
+			"level":    "info",
+			"scenario": "scenario_where_each_iteration_takes_200ms",
+		},
+		{
+			"message":  "Running scenario_where_each_iteration_takes_200ms for up to 10 iterations or up to 10s at a rate of Makes requests from a set of users specified by --concurrency",
+			"level":    "info",
+			"scenario": "scenario_where_each_iteration_takes_200ms",
+		},
+		{
+			"message":         "progress",
+			"level":           "info",
+			"scenario":        "scenario_where_each_iteration_takes_200ms",
+			"iteration_stats": anyValue,
+		},
+		{
+			"message":         "progress",
+			"level":           "info",
+			"scenario":        "scenario_where_each_iteration_takes_200ms",
+			"iteration_stats": anyValue,
+		},
+		{
+			"message":  "Max Iterations Reached - waiting for active tests to complete",
+			"level":    "info",
+			"scenario": "scenario_where_each_iteration_takes_200ms",
+			"duration": anyValue,
+		},
+		{
+			"message":  "teardown completed",
+			"level":    "info",
+			"scenario": "scenario_where_each_iteration_takes_200ms",
+		},
+		{
+			"message":         "Load Test Passed",
+			"level":           "info",
+			"scenario":        "scenario_where_each_iteration_takes_200ms",
 			"iteration_stats": anyValue,
 		},
 	}
@@ -996,3 +2499,237 @@ func TestOutput_JSONLogging(t *testing.T) {
 		})
 	}
 }
+
+func TestSummaryComparesTheIterationsStartedWithThePlan(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name          string
+		maxIterations uint64
+		planned       uint64
+	}{
+		{name: "plan of the trigger", planned: 25},
+		{name: "plan capped by max iterations", maxIterations: 10, planned: 10},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			given, when, then := NewRunTestStage(t)
+			given.
+				a_rate_of("5/100ms").and().
+				a_duration_of(500 * time.Millisecond).and().
+				a_distribution_type("none").and().
+				a_scenario_where_each_iteration_takes(time.Millisecond).and().
+				an_iteration_limit_of(test.maxIterations)
+
+			when.the_run_command_is_executed()
+
+			then.the_plan_was_achieved(test.planned, 100)
+		})
+	}
+}
+
+func TestRunIsTerminatedAtTheHardDeadlineWhenTheTeardownHangs(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_rate_of("1/100ms").and().
+		a_duration_of(200 * time.Millisecond).and().
+		a_scenario_where_teardown_hangs().and().
+		a_hard_deadline_of(500 * time.Millisecond)
+
+	when.the_run_command_is_executed_until_it_fails()
+
+	then.
+		the_run_is_terminated_at_the_hard_deadline_within(time.Second).and().
+		the_abandoned_run_completes_once_the_teardown_is_released()
+}
+
+func TestRunIsCancelledAtTheHardDeadline(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_rate_of("1/100ms").and().
+		a_duration_of(10 * time.Second).and().
+		a_scenario_where_each_iteration_takes(1 * time.Millisecond).and().
+		a_hard_deadline_of(500 * time.Millisecond)
+
+	when.the_run_command_is_executed_until_it_fails()
+
+	then.
+		the_run_is_terminated_at_the_hard_deadline_within(time.Second).and().
+		the_abandoned_run_is_cancelled_and_completes()
+}
+
+func TestSummaryOfCustomTemplateWithTemplateVariables(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_rate_of("1/100ms").and().
+		a_duration_of(200*time.Millisecond).and().
+		a_scenario_annotated_with("environment", "staging").and().
+		a_template_variable("team", "payments").and().
+		a_template_variable("environment", "staging-eu").and().
+		a_template_dir_with("result.tmpl", `{{var "team"}} ran {{var "scenario"}} in {{var "environment"}} `+
+			`({{var "annotation.environment"}}) as {{var "run_id"}}: {{if .Failed}}failed{{else}}passed{{end}}`)
+
+	when.the_run_command_is_executed()
+
+	then.the_summary_is("payments ran scenario_annotated_with_environment in staging-eu (staging) as {run_id}: " +
+		"passed")
+}
+
+func TestMetricsAreExportedToTheOpenTelemetryCollectorEveryPushInterval(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_rate_of("1/1s").and().
+		a_duration_of(1 * time.Second).and().
+		a_scenario_where_each_iteration_takes(1 * time.Millisecond).and().
+		an_opentelemetry_collector().and().
+		metrics_pushed_every(50 * time.Millisecond)
+
+	when.the_run_command_is_executed()
+
+	// the setup and teardown exports, and an export every interval
+	then.the_command_finished_successfully().and().
+		the_metrics_were_pushed_between(12, 22).and().
+		the_metrics_were_exported_with(map[string]string{
+			"service.name": "f1-scenario_where_each_iteration_takes_1ms",
+			"namespace":    "test-namespace",
+			"id":           "test-run-name",
+		})
+}
+
+func TestFailedMetricsExportsAreReported(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+	given.
+		a_rate_of("5/100ms").and().
+		a_duration_of(500 * time.Millisecond).and().
+		a_scenario_where_each_iteration_takes(1 * time.Millisecond).and().
+		an_opentelemetry_collector_which_fails()
+
+	when.the_run_command_is_executed()
+
+	then.the_command_finished_successfully().and().
+		the_failed_metrics_pushes_are_reported(2)
+}
+
+func TestResultIsWrittenAsJSON(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name       string
+		outputFile bool
+	}{
+		{name: "to stdout"},
+		{name: "to the output file", outputFile: true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			given, when, then := NewRunTestStage(t)
+			given.
+				a_rate_of("5/100ms").and().
+				a_duration_of(500 * time.Millisecond).and().
+				a_distribution_type("none").and().
+				a_scenario_where_each_iteration_takes(time.Millisecond).and().
+				an_output_format(run.OutputFormatJSON)
+			if test.outputFile {
+				given.an_output_file()
+			}
+
+			when.the_run_command_is_executed()
+
+			then.the_json_result_is_written()
+		})
+	}
+}
+
+func TestResultIsWrittenAsJUnitReport(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name     string
+		scenario func(s *RunTestStage) *RunTestStage
+		failed   []string
+		skipped  []string
+	}{
+		{
+			name: "passing run",
+			scenario: func(s *RunTestStage) *RunTestStage {
+				return s.a_scenario_where_each_iteration_takes(time.Millisecond)
+			},
+		},
+		{
+			name:     "failing iterations",
+			scenario: (*RunTestStage).a_test_scenario_that_always_fails,
+			failed:   []string{run.JUnitIterations},
+		},
+		{
+			name:     "failing setup",
+			scenario: (*RunTestStage).a_test_scenario_that_always_fails_setup,
+			failed:   []string{run.JUnitSetup},
+			skipped:  []string{run.JUnitIterations},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			given, when, then := NewRunTestStage(t)
+			test.scenario(given).and().
+				a_rate_of("5/100ms").and().
+				a_duration_of(300 * time.Millisecond).and().
+				a_junit_report()
+
+			when.the_run_command_is_executed()
+
+			then.the_junit_report_is_written(test.failed, test.skipped)
+		})
+	}
+}
+
+func TestOperationsAreEvaluatedAgainstTheSLA(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name       string
+		sla        string
+		violations map[string][]string
+	}{
+		{
+			name:       "met",
+			sla:        "operations:\n  create:\n    max-p99: 1s\n    max-error-rate: 1\n",
+			violations: map[string][]string{"create": nil},
+		},
+		{
+			name: "operation not run",
+			sla:  "operations:\n  create:\n    max-p99: 1s\n  fetch:\n    max-error-rate: 1\n",
+			violations: map[string][]string{
+				"create": nil,
+				"fetch":  {"not run"},
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			given, when, then := NewRunTestStage(t)
+			given.
+				a_rate_of("5/100ms").and().
+				a_duration_of(300 * time.Millisecond).and().
+				a_scenario_where_each_iteration_times_stage("create").and().
+				an_sla_of(test.sla)
+
+			when.the_run_command_is_executed()
+
+			then.the_sla_compliance_is_reported(test.violations)
+		})
+	}
+}
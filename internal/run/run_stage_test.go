@@ -1,14 +1,24 @@
 package run_test
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
+	"encoding/xml"
+	"errors"
 	"fmt"
+	"io"
 	"math"
+	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"runtime/pprof"
 	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -20,21 +30,37 @@ import (
 	"github.com/prometheus/common/expfmt"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	collectormetrics "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	"google.golang.org/protobuf/proto"
 
+	"github.com/form3tech-oss/f1/v2/internal/artifacts"
+	"github.com/form3tech-oss/f1/v2/internal/audit"
+	"github.com/form3tech-oss/f1/v2/internal/barrier"
+	"github.com/form3tech-oss/f1/v2/internal/cpupin"
 	"github.com/form3tech-oss/f1/v2/internal/envsettings"
+	"github.com/form3tech-oss/f1/v2/internal/goroutines"
+	"github.com/form3tech-oss/f1/v2/internal/history"
 	"github.com/form3tech-oss/f1/v2/internal/log"
 	"github.com/form3tech-oss/f1/v2/internal/logutils"
+	"github.com/form3tech-oss/f1/v2/internal/memory"
 	"github.com/form3tech-oss/f1/v2/internal/metrics"
 	"github.com/form3tech-oss/f1/v2/internal/options"
+	"github.com/form3tech-oss/f1/v2/internal/progress"
 	"github.com/form3tech-oss/f1/v2/internal/run"
+	"github.com/form3tech-oss/f1/v2/internal/tracing"
 	"github.com/form3tech-oss/f1/v2/internal/trigger/api"
+	"github.com/form3tech-oss/f1/v2/internal/trigger/connections"
 	"github.com/form3tech-oss/f1/v2/internal/trigger/constant"
 	"github.com/form3tech-oss/f1/v2/internal/trigger/file"
 	"github.com/form3tech-oss/f1/v2/internal/trigger/ramp"
+	"github.com/form3tech-oss/f1/v2/internal/trigger/search"
 	"github.com/form3tech-oss/f1/v2/internal/trigger/staged"
+	"github.com/form3tech-oss/f1/v2/internal/trigger/tokenbucket"
 	"github.com/form3tech-oss/f1/v2/internal/trigger/users"
 	"github.com/form3tech-oss/f1/v2/internal/ui"
+	"github.com/form3tech-oss/f1/v2/internal/workers"
 	"github.com/form3tech-oss/f1/v2/pkg/f1"
+	"github.com/form3tech-oss/f1/v2/pkg/f1/scenarios"
 	f1_testing "github.com/form3tech-oss/f1/v2/pkg/f1/testing"
 )
 
@@ -42,6 +68,7 @@ const (
 	fakePrometheusNamespace = "test-namespace"
 	fakePrometheusID        = "test-run-name"
 	iterationMetricFamily   = "form3_loadtest_iteration"
+	labelMetricFamily       = "form3_loadtest_iteration_label"
 )
 
 type TriggerType int
@@ -52,6 +79,9 @@ const (
 	Users
 	Ramp
 	File
+	Search
+	TokenBucket
+	Connections
 )
 
 const anyValue = "{__any__}"
@@ -72,6 +102,9 @@ type RunTestStage struct {
 	metrics                  *metrics.Metrics
 	output                   *ui.Output
 	runInstance              *run.Run
+	progress                 run.Progress
+	uploadedArtifacts        []string
+	artifactLocations        []string
 	runResult                *run.Result
 	t                        *testing.T
 	require                  *require.Assertions
@@ -90,21 +123,120 @@ type RunTestStage struct {
 	endRate                  string
 	rampDuration             string
 	scenario                 string
+	stateFile                string
+	traceFile                string
+	snapshotDir              string
+	histogramFile            string
+	auditLog                 string
+	annotateGC               bool
+	rateOverrideFile         string
+	reportSnapshotFile       string
+	reportSnapshot           chan *run.Report
+	sloMaxErrorRate          *float64
 	settings                 envsettings.Settings
+	requireMetrics           int
+	metricsPushes            atomic.Int32
+	workerCPUs               []int
+	startAt                  time.Time
+	syncBarrier              string
+	barrierReleases          chan barrier.Release
+	readyURL                 string
+	readyTimeout             time.Duration
+	readyAt                  atomic.Pointer[time.Time]
+	unreadyAt                atomic.Pointer[time.Time]
+	iterationCPUs            sync.Map
+	workerValuesCreated      atomic.Int32
+	workerValuesClosed       atomic.Int32
+	searchFlags              map[string]string
+	bucketFlags              map[string]string
 	maxFailures              uint64
 	maxIterations            uint64
 	maxFailuresRate          int
+	snapshotFailureRate      int
+	maxMemory                uint64
 	duration                 time.Duration
 	waitForCompletionTimeout time.Duration
+	sloMaxP95                time.Duration
+	reportSnapshotInterval   time.Duration
+	pprofCapture             time.Duration
 	concurrency              int
 	triggerType              TriggerType
 	iterationTeardownCount   atomic.Uint32
 	setupTeardownCount       atomic.Uint32
 	runCount                 atomic.Uint32
+	operationRunCounts       sync.Map
+	rateDrops                []f1_testing.RateDrop
+	rateDropsMu              sync.Mutex
 	stdout                   syncWriter
 	stderr                   syncWriter
 	interactive              bool
 	verbose                  bool
+	endless                  bool
+	uniqueIterations         bool
+	ignoreDropped            bool
+	showSlowest              bool
+	iterationNumbers         sync.Map
+	connectionsFlags         map[string]string
+	openConnections          atomic.Int32
+	peakConnections          atomic.Int32
+	closedConnections        atomic.Int32
+	workerRamp               time.Duration
+	workerStarts             []time.Time
+	workersSetUp             time.Time
+	workerStartsMu           sync.Mutex
+	phases                   []f1_testing.Phase
+	phasesMu                 sync.Mutex
+	// triggerPanicAfter makes the trigger panic once it ran for the duration, when set
+	triggerPanicAfter time.Duration
+	// operationDuration is how long the iterations of the operations of a_scenario_with_operations take
+	operationDuration time.Duration
+	workerPacing      time.Duration
+	autotune          bool
+	// redact are the patterns redacted from the logs, failures and report of the run
+	redact []string
+	// goroutineProfiles are the goroutine profiles captured by the iterations of the scenario
+	goroutineProfiles   []string
+	goroutineProfilesMu sync.Mutex
+	// sleepsCutShort counts the sleeps of the iterations which were cut short by the end of the run
+	sleepsCutShort atomic.Int32
+	// summaryMetricsFile is written in the summaryMetricsFormat once the run completes
+	summaryMetricsFile   string
+	summaryMetricsFormat string
+	// hardDeadline is how long after the run is set up it is terminated, releaseTeardown releases the
+	// teardown of a_scenario_where_teardown_hangs, and runErr is the error the run returned
+	hardDeadline    time.Duration
+	releaseTeardown chan struct{}
+	runErr          error
+	runDuration     time.Duration
+	// templateVariables and templateDir customize the templates of the output of the run
+	templateVariables map[string]string
+	templateDir       string
+	// otlpExports are the exports of the metrics received by an_opentelemetry_collector
+	otlpExports   []*collectormetrics.ExportMetricsServiceRequest
+	otlpExportsMu sync.Mutex
+	// outputFormat and outputFile are the output format and file of the json result of the run
+	outputFormat string
+	outputFile   string
+	// fixturePoolStats reads the stats of the fixture pool of the scenario, if it has one
+	fixturePoolStats func() f1_testing.FixturePoolStats
+	// junitReport is the file of the JUnit report of the run
+	junitReport string
+	// slaFile is the file of the SLA the operations of the run are evaluated against
+	slaFile string
+	// goroutinePanicAfter makes a goroutine of the trigger panic once it ran for the duration, when set
+	goroutinePanicAfter time.Duration
+	// iterationsInProgress counts the iterations in progress, and iterationsInProgressAtTeardown
+	// those still in progress when the scenario was torn down
+	iterationsInProgress           atomic.Int32
+	iterationsInProgressAtTeardown atomic.Int32
+	// operationsInProgress counts the iterations of a_scenario_with_operations in progress, and
+	// maxOperationsInProgress the most which were in progress at once
+	operationsInProgress    atomic.Int32
+	maxOperationsInProgress atomic.Int32
+	// iterationDurations are the exact durations of the iterations of
+	// a_scenario_timing_each_iteration_taking, as timed by the iterations
+	iterationDurations   []time.Duration
+	iterationDurationsMu sync.Mutex
 }
 
 func NewRunTestStage(t *testing.T) (*RunTestStage, *RunTestStage, *RunTestStage) {
@@ -172,68 +304,1528 @@ func (s *RunTestStage) a_max_failures_rate_of(maxFailuresRate int) *RunTestStage
 	return s
 }
 
+func (s *RunTestStage) a_max_memory_of(maxMemory uint64) *RunTestStage {
+	s.maxMemory = maxMemory
+	return s
+}
+
+func (s *RunTestStage) a_start_time_in(delay time.Duration) *RunTestStage {
+	s.startAt = time.Now().Add(delay)
+	return s
+}
+
+func (s *RunTestStage) a_barrier_releasing_every_process_after(lead time.Duration) *RunTestStage {
+	server, err := barrier.NewServer("127.0.0.1:0", 1, lead)
+	s.require.NoError(err)
+	s.barrierReleases = make(chan barrier.Release, 1)
+	server.OnRelease(func(release barrier.Release, _ int) {
+		s.barrierReleases <- release
+	})
+	server.Start()
+	s.t.Cleanup(func() {
+		s.assert.NoError(server.Shutdown(context.Background()))
+	})
+
+	s.syncBarrier = server.Addr()
+	return s
+}
+
+func (s *RunTestStage) no_iteration_started_before_the_start_time() *RunTestStage {
+	if s.barrierReleases != nil {
+		select {
+		case release := <-s.barrierReleases:
+			s.startAt = release.StartAt
+		case <-time.After(5 * time.Second):
+			s.require.Fail("the barrier did not release the run")
+		}
+	}
+	s.assertNoIterationStartedBetween(time.Time{}, s.startAt)
+	return s
+}
+
+// assertNoIterationStartedBetween asserts that iterations started, but none between from and to.
+func (s *RunTestStage) assertNoIterationStartedBetween(from, to time.Time) {
+	iterations := 0
+	s.durations.Range(func(key, _ any) bool {
+		iterations++
+		started, ok := key.(time.Time)
+		s.require.True(ok)
+		s.assert.False(started.After(from) && started.Before(to),
+			"iteration started at %s between %s and %s", started, from, to)
+		return true
+	})
+	s.assert.Positive(iterations)
+}
+
+func (s *RunTestStage) a_target_ready_in(delay time.Duration) *RunTestStage {
+	readyAt := time.Now().Add(delay)
+	s.readyAt.Store(&readyAt)
+	s.readyTimeout = time.Minute
+
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if time.Now().Before(readyAt) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	s.t.Cleanup(target.Close)
+
+	s.readyURL = target.URL
+	return s
+}
+
+// a_target_restarting_for serves a target which is ready when the run starts, and then restarts
+// for the given duration when its readiness is checked again.
+func (s *RunTestStage) a_target_restarting_for(restart time.Duration) *RunTestStage {
+	s.readyTimeout = time.Minute
+
+	var checks atomic.Int32
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if checks.Add(1) == 1 {
+			return
+		}
+
+		now := time.Now()
+		if s.unreadyAt.CompareAndSwap(nil, &now) {
+			readyAt := now.Add(restart)
+			s.readyAt.Store(&readyAt)
+		}
+		if now.Before(*s.readyAt.Load()) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	s.t.Cleanup(target.Close)
+
+	s.readyURL = target.URL
+	return s
+}
+
+func (s *RunTestStage) a_target_never_ready_within(timeout time.Duration) *RunTestStage {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	s.t.Cleanup(target.Close)
+
+	s.readyURL = target.URL
+	s.readyTimeout = timeout
+	return s
+}
+
+func (s *RunTestStage) a_scenario_ready_in(delay time.Duration) *RunTestStage {
+	readyAt := time.Now().Add(delay)
+	s.readyAt.Store(&readyAt)
+	s.readyTimeout = time.Minute
+
+	s.scenario = "scenario_ready_in"
+	s.f1.Add(s.scenario, func(*f1_testing.T) f1_testing.RunFn {
+		return func(*f1_testing.T) {
+			s.durations.Store(time.Now(), time.Since(s.startTime))
+		}
+	}, scenarios.Readiness(func(context.Context) error {
+		if time.Now().Before(readyAt) {
+			return errors.New("deploying")
+		}
+		return nil
+	}))
+	return s
+}
+
+func (s *RunTestStage) no_iteration_started_before_the_target_was_ready() *RunTestStage {
+	s.assertNoIterationStartedBetween(time.Time{}, *s.readyAt.Load())
+	return s
+}
+
+func (s *RunTestStage) no_iteration_started_while_the_target_restarted() *RunTestStage {
+	unreadyAt := s.unreadyAt.Load()
+	s.require.NotNil(unreadyAt, "the readiness of the target wasn't checked between stages")
+
+	// an iteration may be started by a tick of the trigger racing with the check of the target
+	s.assertNoIterationStartedBetween(unreadyAt.Add(50*time.Millisecond), *s.readyAt.Load())
+	return s
+}
+
+func (s *RunTestStage) the_run_error_is(expected error) *RunTestStage {
+	s.assert.ErrorIs(s.runResult.Error(), expected)
+	return s
+}
+
 func (s *RunTestStage) a_config_file_location_of(commandsFile string) *RunTestStage {
 	s.configFile = commandsFile
 	return s
 }
 
-func (s *RunTestStage) a_start_rate_of(startRate string) *RunTestStage {
-	s.startRate = startRate
+func (s *RunTestStage) a_start_rate_of(startRate string) *RunTestStage {
+	s.startRate = startRate
+	return s
+}
+
+func (s *RunTestStage) a_end_rate_of(endRate string) *RunTestStage {
+	s.endRate = endRate
+	return s
+}
+
+func (s *RunTestStage) a_ramp_duration_of(rampDuration string) *RunTestStage {
+	s.rampDuration = rampDuration
+	return s
+}
+
+func (s *RunTestStage) setupRun() {
+	var hardDeadline time.Time
+	if s.hardDeadline > 0 {
+		hardDeadline = time.Now().Add(s.hardDeadline)
+	}
+	printer := ui.NewPrinter(&s.stdout, &s.stderr)
+	logger := log.NewLogger(&s.stdout, logutils.NewLogConfigFromSettings(s.settings))
+	outputer := ui.NewOutput(logger, printer, s.interactive, false)
+
+	r, err := run.NewRun(options.RunOptions{
+		Scenario:        s.scenario,
+		MaxDuration:     s.duration,
+		Concurrency:     s.concurrency,
+		MaxIterations:   s.maxIterations,
+		MaxFailures:     s.maxFailures,
+		MaxFailuresRate: s.maxFailuresRate,
+		MaxMemory:       s.maxMemory,
+		Verbose:         s.verbose,
+		StateFile:       s.stateFile,
+		Trace:           s.trace(),
+		TraceFile:       s.traceFile,
+
+		SnapshotFailureRate: s.snapshotFailureRate,
+		SnapshotDir:         s.snapshotDir,
+		PprofCapture:        s.pprofCapture,
+		HistogramFile:       s.histogramFile,
+		AuditLog:            s.auditLog,
+		AnnotateGC:          s.annotateGC,
+		ShowSlowest:         s.showSlowest,
+		RateOverrideFile:    s.rateOverrideFile,
+		UniqueIterations:    s.uniqueIterations,
+		RequireMetrics:      s.requireMetrics,
+		WorkerCPUs:          s.workerCPUs,
+		WorkerRamp:          s.workerRamp,
+		WorkerPacing:        s.workerPacing,
+		AutotuneConcurrency: s.autotune,
+		StartAt:             s.startAt,
+		SyncBarrier:         s.syncBarrier,
+		ReadyURL:            s.readyURL,
+		ReadyTimeout:        s.readyTimeout,
+		ReadyInterval:       20 * time.Millisecond,
+		Endless:             s.endless,
+
+		SLOMaxP95:       s.sloMaxP95,
+		SLOMaxErrorRate: s.sloMaxErrorRate,
+
+		ReportSnapshotFile:     s.reportSnapshotFile,
+		ReportSnapshotInterval: s.reportSnapshotInterval,
+
+		Redact: s.redact,
+
+		SummaryMetricsFile:   s.summaryMetricsFile,
+		SummaryMetricsFormat: s.summaryMetricsFormat,
+
+		HardDeadline: hardDeadline,
+
+		TemplateVariables: s.templateVariables,
+		TemplateDir:       s.templateDir,
+
+		OutputFormat: s.outputFormat,
+		OutputFile:   s.outputFile,
+
+		JUnitReport: s.junitReport,
+
+		SLAFile: s.slaFile,
+
+		IgnoreDropped: s.ignoreDropped,
+	}, s.f1.GetScenarios(), s.build_plannedTrigger(), s.waitForCompletionTimeout, s.settings, s.metrics, outputer)
+
+	s.require.NoError(err)
+	s.runInstance = r
+}
+
+func (s *RunTestStage) the_run_command_is_executed() *RunTestStage {
+	s.setupRun()
+
+	var err error
+	s.runResult, err = s.runInstance.Do(context.TODO())
+	s.require.NoError(err)
+
+	return s
+}
+
+func (s *RunTestStage) the_run_command_is_executed_and_cancelled_after(duration time.Duration) *RunTestStage {
+	s.setupRun()
+
+	var err error
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	timer := time.AfterFunc(duration, cancel)
+	defer timer.Stop()
+
+	s.runResult, err = s.runInstance.Do(ctx)
+	s.require.NoError(err)
+
+	return s
+}
+
+func (s *RunTestStage) the_run_command_is_executed_until_it_fails() *RunTestStage {
+	s.setupRun()
+
+	start := time.Now()
+	s.runResult, s.runErr = s.runInstance.Do(context.TODO())
+	s.runDuration = time.Since(start)
+
+	return s
+}
+
+func (s *RunTestStage) the_run_command_is_executed_and_stopped_after(duration time.Duration) *RunTestStage {
+	s.setupRun()
+
+	timer := time.AfterFunc(duration, s.runInstance.Stop)
+	defer timer.Stop()
+
+	var err error
+	s.runResult, err = s.runInstance.Do(context.TODO())
+	s.require.NoError(err)
+
+	return s
+}
+
+func (s *RunTestStage) the_run_command_is_executed_and_logs_are_toggled_after(duration time.Duration) *RunTestStage {
+	s.setupRun()
+
+	timer := time.AfterFunc(duration, func() {
+		verbose, err := s.runInstance.ToggleVerbose()
+		s.assert.NoError(err)
+		s.assert.Equal(!s.verbose, verbose)
+	})
+	defer timer.Stop()
+
+	var err error
+	s.runResult, err = s.runInstance.Do(context.TODO())
+	s.require.NoError(err)
+
+	return s
+}
+
+// stageJump skips the current stage of a run after a duration, or jumps to stage if it isn't empty.
+type stageJump struct {
+	stage string
+	after time.Duration
+}
+
+func (s *RunTestStage) the_run_command_is_executed_and_jumps_to_stages(jumps ...stageJump) *RunTestStage {
+	s.setupRun()
+
+	for _, jump := range jumps {
+		timer := time.AfterFunc(jump.after, func() {
+			if jump.stage == "" {
+				s.assert.NoError(s.runInstance.SkipStage())
+				return
+			}
+			s.assert.NoError(s.runInstance.JumpToStage(jump.stage))
+		})
+		defer timer.Stop()
+	}
+
+	var err error
+	s.runResult, err = s.runInstance.Do(context.TODO())
+	s.require.NoError(err)
+
+	return s
+}
+
+func (s *RunTestStage) jumping_to_stage_fails_with(stage string, expected error) *RunTestStage {
+	s.setupRun()
+
+	s.require.ErrorIs(s.runInstance.JumpToStage(stage), expected)
+	return s
+}
+
+func (s *RunTestStage) the_run_command_is_executed_and_progress_is_read_after(duration time.Duration) *RunTestStage {
+	s.setupRun()
+
+	progressRead := make(chan struct{})
+	go func() {
+		defer close(progressRead)
+		<-time.After(duration)
+		s.progress = s.runInstance.Progress()
+	}()
+
+	var err error
+	s.runResult, err = s.runInstance.Do(context.TODO())
+	s.require.NoError(err)
+	<-progressRead
+
+	return s
+}
+
+func (s *RunTestStage) the_progress_is_at_stage(stage string) *RunTestStage {
+	s.assert.Equal(stage, s.progress.Stage)
+	return s
+}
+
+func (s *RunTestStage) the_progress_shows_successful_iterations() *RunTestStage {
+	s.assert.Equal(s.scenario, s.progress.Scenario)
+	s.assert.Positive(s.progress.SuccessfulIterations)
+	s.assert.Positive(s.progress.Rate)
+	s.assert.Positive(s.progress.ETA)
+	return s
+}
+
+func (s *RunTestStage) a_scenario_with_a_progress_gauge_of_accounts_created() *RunTestStage {
+	s.scenario = "scenario_with_a_progress_gauge"
+	s.f1.Add(s.scenario, func(scenarioT *f1_testing.T) f1_testing.RunFn {
+		scenarioT.ProgressGauge("accounts created", func() float64 { return float64(s.runCount.Load()) })
+
+		return func(*f1_testing.T) {
+			s.runCount.Add(1)
+		}
+	})
+	return s
+}
+
+// a_scenario_leasing_accounts_from_a_fixture_pool_of leases an account of a fixture pool of size
+// accounts in every iteration, discarding the account in every discardEvery iterations, and fails
+// iterations leasing an account already leased by another iteration.
+func (s *RunTestStage) a_scenario_leasing_accounts_from_a_fixture_pool_of(size int, discardEvery uint32) *RunTestStage {
+	s.scenario = "scenario_leasing_accounts_from_a_fixture_pool"
+	s.f1.Add(s.scenario, func(scenarioT *f1_testing.T) f1_testing.RunFn {
+		var created atomic.Int64
+		pool, err := f1_testing.NewFixturePool(scenarioT, "accounts", size, func(context.Context) (int64, error) {
+			return created.Add(1), nil
+		})
+		scenarioT.Require().NoError(err)
+		s.fixturePoolStats = pool.Stats
+
+		var inUse sync.Map
+		return func(t *f1_testing.T) {
+			lease, err := pool.Lease(t)
+			if errors.Is(err, f1_testing.ErrFixturePoolStopping) {
+				return
+			}
+			t.Require().NoError(err)
+			if _, leased := inUse.LoadOrStore(lease.Value, true); leased {
+				t.Errorf("account %d is leased by another iteration", lease.Value)
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+			inUse.Delete(lease.Value)
+
+			if s.runCount.Add(1)%discardEvery == 0 {
+				lease.Discard()
+			}
+		}
+	})
+	return s
+}
+
+func (s *RunTestStage) the_fixture_pool_was_leased_one_iteration_at_a_time_discarding_every(
+	discardEvery uint32,
+) *RunTestStage {
+	report := s.runResult.Report()
+	s.assert.Positive(report.SuccessfulIterationDurations.Count)
+	s.assert.Zero(report.FailedIterationDurations.Count, "accounts leased by more than one iteration")
+
+	stats := s.fixturePoolStats()
+	s.assert.Equal(uint64(s.runCount.Load()/discardEvery), stats.Discarded)
+	s.assert.Zero(stats.Leased)
+	s.assert.Zero(stats.CreateFailures)
+	// the accounts which aren't available are discarded accounts not replaced before the teardown
+	s.assert.Equal(stats.Created, stats.Discarded+uint64(stats.Available))
+	s.assert.LessOrEqual(stats.Created, uint64(stats.Size)+stats.Discarded)
+	return s
+}
+
+func (s *RunTestStage) the_progress_shows_the_accounts_created() *RunTestStage {
+	s.require.Len(s.progress.Gauges, 1)
+	s.assert.Equal("accounts created", s.progress.Gauges[0].Name)
+	s.assert.Positive(s.progress.Gauges[0].Value)
+	s.assert.Contains(s.stdout.String(), `"gauges.accounts created"=`)
+	return s
+}
+
+func (s *RunTestStage) the_artifacts_are_uploaded() *RunTestStage {
+	var mu sync.Mutex
+	storage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		s.uploadedArtifacts = append(s.uploadedArtifacts, r.Method+" "+r.URL.Path)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	s.t.Cleanup(storage.Close)
+
+	store, err := artifacts.New("azblob://account/artifacts/load-tests", func(name string) string {
+		if name == "AZURE_STORAGE_BLOB_ENDPOINT" {
+			return storage.URL
+		}
+		return ""
+	})
+	s.require.NoError(err)
+
+	now := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+	s.artifactLocations, err = s.runResult.UploadArtifacts(context.TODO(), store, now)
+	s.require.NoError(err)
+
+	return s
+}
+
+func (s *RunTestStage) the_reports_and_log_file_are_uploaded() *RunTestStage {
+	dir := "/artifacts/load-tests/" + s.scenario + "/20240501T103000Z/"
+	logFile := filepath.Base(s.runResult.LogFilePath)
+
+	s.assert.Equal([]string{"PUT " + dir + "report.json", "PUT " + dir + "report.html", "PUT " + dir + logFile},
+		s.uploadedArtifacts)
+	s.assert.Len(s.artifactLocations, 3)
+	return s
+}
+
+func (s *RunTestStage) a_state_file() *RunTestStage {
+	s.stateFile = filepath.Join(s.t.TempDir(), "state.json")
+	return s
+}
+
+func (s *RunTestStage) the_checkpoint_has_elapsed_approx(expected time.Duration) *RunTestStage {
+	checkpoint, ok, err := run.ReadCheckpoint(s.stateFile)
+	s.require.NoError(err)
+	s.require.True(ok, "checkpoint was not saved")
+
+	s.assert.Equal(s.scenario, checkpoint.Scenario)
+	s.assert.InDelta(expected.Seconds(), checkpoint.Elapsed.Seconds(), 0.1)
+	s.assert.Positive(checkpoint.IterationsStarted)
+	return s
+}
+
+func (s *RunTestStage) the_state_file_is_removed() *RunTestStage {
+	s.assert.NoFileExists(s.stateFile)
+	return s
+}
+
+func (s *RunTestStage) unique_iterations() *RunTestStage {
+	s.uniqueIterations = true
+	return s
+}
+
+func (s *RunTestStage) a_scenario_recording_the_numbers_of_its_iterations() *RunTestStage {
+	s.scenario = "scenario_recording_iteration_numbers"
+	s.f1.Add(s.scenario, func(*f1_testing.T) f1_testing.RunFn {
+		return func(t *f1_testing.T) {
+			iteration, err := strconv.ParseUint(t.Iteration, 10, 64)
+			s.require.NoError(err)
+			_, loaded := s.iterationNumbers.LoadOrStore(iteration, true)
+			s.assert.False(loaded, "iteration %d started twice", iteration)
+		}
+	})
+	return s
+}
+
+func (s *RunTestStage) the_checkpoint_reserves_iterations_up_to(reserved uint64) *RunTestStage {
+	checkpoint, ok, err := run.ReadCheckpoint(s.stateFile)
+	s.require.NoError(err)
+	s.require.True(ok, "checkpoint was not saved")
+
+	s.assert.True(checkpoint.UniqueIterations)
+	s.assert.Equal(reserved, checkpoint.IterationsReserved)
+	s.assert.Positive(checkpoint.LastIteration)
+	s.assert.Less(checkpoint.LastIteration, reserved)
+	return s
+}
+
+func (s *RunTestStage) the_resumed_run_numbers_its_iterations_after(
+	reserved uint64,
+) *RunTestStage {
+	report := s.runResult.Report()
+	s.require.Len(report.IterationGaps, 1)
+	gap := report.IterationGaps[0]
+	s.assert.Equal(reserved, gap.Last)
+
+	started := 0
+	s.iterationNumbers.Range(func(key, _ any) bool {
+		iteration, _ := key.(uint64)
+		if iteration >= gap.First {
+			s.assert.Greater(iteration, reserved)
+			started++
+		}
+		return true
+	})
+	s.assert.Equal(int(report.IterationsStarted), started)
+	s.assert.Contains(s.stdout.String(),
+		fmt.Sprintf("skipping iterations %d to %d, which were reserved by the interrupted run", gap.First, gap.Last))
+	return s
+}
+
+func (s *RunTestStage) a_trace_file() *RunTestStage {
+	s.traceFile = filepath.Join(s.t.TempDir(), "trace.jsonl")
+	return s
+}
+
+func (s *RunTestStage) profiles_captured_for(duration time.Duration) *RunTestStage {
+	s.pprofCapture = duration
+	s.snapshotDir = filepath.Join(s.t.TempDir(), "snapshots")
+	return s
+}
+
+func (s *RunTestStage) the_profiles_are_written() *RunTestStage {
+	dir := s.runResult.Profiles()
+	s.require.NotEmpty(dir)
+	s.assert.Equal(s.snapshotDir, filepath.Dir(dir))
+
+	for _, name := range []string{"cpu.pprof", "heap.pprof"} {
+		info, err := os.Stat(filepath.Join(dir, name))
+		if s.assert.NoError(err) {
+			s.assert.Positive(info.Size(), name)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "profiles.json"))
+	s.require.NoError(err)
+	var description struct {
+		Duration time.Duration `json:"duration"`
+		Rate     float64       `json:"rate"`
+	}
+	s.require.NoError(json.Unmarshal(data, &description))
+	s.assert.Equal(s.pprofCapture, description.Duration)
+	s.assert.Positive(description.Rate)
+	s.assert.Contains(s.stdout.String(), "Profiles captured at")
+	return s
+}
+
+func (s *RunTestStage) the_profiles_are_uploaded() *RunTestStage {
+	s.assert.Contains(s.uploadedArtifacts,
+		"PUT /artifacts/load-tests/"+s.scenario+"/20240501T103000Z/profiles/cpu.pprof")
+	return s
+}
+
+func (s *RunTestStage) no_profiles_are_written() *RunTestStage {
+	s.assert.Empty(s.runResult.Profiles())
+	return s
+}
+
+func (s *RunTestStage) a_snapshot_failure_rate_of(rate int) *RunTestStage {
+	s.snapshotFailureRate = rate
+	s.snapshotDir = filepath.Join(s.t.TempDir(), "snapshots")
+	return s
+}
+
+func (s *RunTestStage) a_failure_snapshot_is_written() *RunTestStage {
+	dirs := s.runResult.FailureSnapshots()
+	s.require.Len(dirs, 1)
+	s.assert.Equal(s.snapshotDir, filepath.Dir(dirs[0]))
+
+	progressData, err := os.ReadFile(filepath.Join(dirs[0], "progress.json"))
+	s.require.NoError(err)
+	snapshot := struct {
+		Progress    run.Progress `json:"progress"`
+		FailureRate float64      `json:"failure_rate"`
+	}{}
+	s.require.NoError(json.Unmarshal(progressData, &snapshot))
+	s.assert.Equal(s.scenario, snapshot.Progress.Scenario)
+	s.assert.GreaterOrEqual(snapshot.FailureRate, float64(s.snapshotFailureRate))
+	s.assert.Positive(snapshot.Progress.FailedIterations)
+
+	goroutines, err := os.ReadFile(filepath.Join(dirs[0], "goroutines.txt"))
+	s.require.NoError(err)
+	s.assert.Contains(string(goroutines), "goroutine ")
+
+	s.assert.FileExists(filepath.Join(dirs[0], "logs.txt"))
+	return s
+}
+
+func (s *RunTestStage) no_failure_snapshot_is_written() *RunTestStage {
+	s.assert.Empty(s.runResult.FailureSnapshots())
+	s.assert.NoDirExists(s.snapshotDir)
+	return s
+}
+
+func (s *RunTestStage) a_histogram_file() *RunTestStage {
+	s.histogramFile = filepath.Join(s.t.TempDir(), "durations.hgrm")
+	return s
+}
+
+func (s *RunTestStage) the_histogram_file_counts_every_iteration() *RunTestStage {
+	report := s.runResult.Report()
+	n := report.SuccessfulIterationDurations.Count + report.FailedIterationDurations.Count
+	s.require.Positive(n)
+
+	content, err := os.ReadFile(s.histogramFile)
+	s.require.NoError(err)
+
+	lines := strings.Split(strings.TrimRight(string(content), "\n"), "\n")
+	s.require.GreaterOrEqual(len(lines), 5)
+	s.assert.Equal("       Value     Percentile TotalCount 1/(1-Percentile)", lines[0])
+	s.assert.Contains(lines[len(lines)-2], fmt.Sprintf("Total count    = %12d]", n))
+	return s
+}
+
+func (s *RunTestStage) a_summary_metrics_file_in_format(format string) *RunTestStage {
+	s.summaryMetricsFile = filepath.Join(s.t.TempDir(), "f1.prom")
+	s.summaryMetricsFormat = format
+	return s
+}
+
+func (s *RunTestStage) the_summary_metrics_file_contains(expected ...string) *RunTestStage {
+	content, err := os.ReadFile(s.summaryMetricsFile)
+	s.require.NoError(err)
+
+	for _, line := range expected {
+		s.assert.Contains(strings.Split(string(content), "\n"), line)
+	}
+	return s
+}
+
+func (s *RunTestStage) the_plan_was_achieved(planned uint64, percent float64) *RunTestStage {
+	report := s.runResult.Report()
+	s.assert.Equal(planned, report.IterationsPlanned)
+	s.assert.InDelta(percent, report.PlanAchieved, 0.01)
+	s.assert.Contains(s.runResult.Summary().Render(), fmt.Sprintf("of %d started", planned))
+	return s
+}
+
+func (s *RunTestStage) an_audit_log() *RunTestStage {
+	s.auditLog = filepath.Join(s.t.TempDir(), "audit.jsonl")
+	return s
+}
+
+func (s *RunTestStage) a_scenario_setting_the_audit_field(name string) *RunTestStage {
+	s.scenario = "scenario_setting_the_audit_field_" + name
+	s.f1.Add(s.scenario, func(*f1_testing.T) f1_testing.RunFn {
+		return func(t *f1_testing.T) {
+			t.AuditField(name, "payment-"+t.Iteration)
+		}
+	})
+	return s
+}
+
+func (s *RunTestStage) a_scenario_recording_the_bytes_of_each_iteration(sent, received int) *RunTestStage {
+	s.scenario = "scenario_recording_the_bytes_of_each_iteration"
+	s.f1.Add(s.scenario, func(*f1_testing.T) f1_testing.RunFn {
+		return func(t *f1_testing.T) {
+			t.RecordBytes(sent, 0)
+			t.RecordBytes(0, received)
+		}
+	})
+	return s
+}
+
+func (s *RunTestStage) the_throughput_of_n_iterations_is_reported(n, sent, received int) *RunTestStage {
+	report := s.runResult.Report()
+	s.require.Equal(uint64(n), report.IterationsStarted)
+	s.assert.Equal(uint64(n*sent), report.BytesSent)
+	s.assert.Equal(uint64(n*received), report.BytesReceived)
+	s.assert.Contains(s.runResult.Summary().Render(), fmt.Sprintf("Throughput: %s sent (",
+		memory.FormatSize(uint64(n*sent))))
+
+	for direction, expected := range map[string]int{metrics.BytesSent: n * sent, metrics.BytesReceived: n * received} {
+		metric := &io_prometheus_client.Metric{}
+		s.require.NoError(s.metrics.IterationBytes.WithLabelValues(s.scenario, direction).Write(metric))
+		s.assert.InDelta(float64(expected), metric.GetCounter().GetValue(), 0, direction)
+	}
+	return s
+}
+
+func (s *RunTestStage) the_audit_log_records_n_iterations_with_the_field(n int, name string) *RunTestStage {
+	file, err := os.Open(s.auditLog)
+	s.require.NoError(err)
+	defer file.Close()
+
+	iterations := map[string]bool{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry audit.Entry
+		s.require.NoError(json.Unmarshal(scanner.Bytes(), &entry))
+		s.assert.Equal("success", entry.Result)
+		s.assert.True(strings.HasPrefix(entry.Worker, "trigger-"), entry.Worker)
+		s.assert.WithinDuration(time.Now(), entry.Start, 10*time.Second)
+		s.assert.Equal(map[string]string{name: "payment-" + entry.Iteration}, entry.Fields)
+		iterations[entry.Iteration] = true
+	}
+	s.require.NoError(scanner.Err())
+
+	s.assert.Len(iterations, n)
+	s.assert.Zero(s.runResult.Report().AuditLogDropped)
+	return s
+}
+
+func (s *RunTestStage) iterations_are_annotated_with_gc_pauses() *RunTestStage {
+	s.annotateGC = true
+	return s
+}
+
+func (s *RunTestStage) a_scenario_where_each_iteration_collects_garbage() *RunTestStage {
+	s.scenario = "scenario_where_each_iteration_collects_garbage"
+	s.f1.Add(s.scenario, func(*f1_testing.T) f1_testing.RunFn {
+		return func(*f1_testing.T) {
+			runtime.GC()
+		}
+	})
+	return s
+}
+
+func (s *RunTestStage) n_of_m_iterations_overlapped_a_collection(n, m uint64) *RunTestStage {
+	report := s.runResult.Report().GCPauses
+	s.require.NotNil(report)
+	s.assert.Equal(m, report.Iterations)
+	s.assert.Equal(n, report.OverlappedIterations)
+	s.assert.Positive(report.Collections)
+	s.assert.Contains(s.stdout.String(), fmt.Sprintf("iterations=%d overlapped_iterations=%d", m, n))
+	return s
+}
+
+func (s *RunTestStage) gc_pauses_are_not_reported() *RunTestStage {
+	s.assert.Nil(s.runResult.Report().GCPauses)
+	s.assert.NotContains(s.stdout.String(), "GC pauses")
+	return s
+}
+
+func (s *RunTestStage) a_rate_override_file_of(content string) *RunTestStage {
+	s.rateOverrideFile = filepath.Join(s.t.TempDir(), "rate-override.yaml")
+	s.require.NoError(os.WriteFile(s.rateOverrideFile, []byte(content), 0o600))
+	return s
+}
+
+func (s *RunTestStage) the_rate_override_file_is_removed_after(delay time.Duration) *RunTestStage {
+	timer := time.AfterFunc(delay, func() {
+		s.assert.NoError(os.Remove(s.rateOverrideFile))
+	})
+	s.t.Cleanup(func() { timer.Stop() })
+	return s
+}
+
+func (s *RunTestStage) some_iterations_started_after_the_override_was_removed() *RunTestStage {
+	s.assert.Positive(s.runResult.Report().IterationsStarted)
+	s.assert.Contains(s.stdout.String(),
+		fmt.Sprintf("Rate override %s removed, restoring the planned rate", s.rateOverrideFile))
+	return s
+}
+
+func (s *RunTestStage) the_rate_override_is_reported(format string) *RunTestStage {
+	s.assert.Contains(s.stdout.String(), fmt.Sprintf(format, s.rateOverrideFile))
+	return s
+}
+
+func (s *RunTestStage) an_endless_run() *RunTestStage {
+	s.endless = true
+	s.duration = run.EndlessDuration
+	return s
+}
+
+func (s *RunTestStage) an_slo_max_p95_of(maxP95 time.Duration) *RunTestStage {
+	s.sloMaxP95 = maxP95
+	return s
+}
+
+func (s *RunTestStage) an_slo_max_error_rate_of(maxErrorRate float64) *RunTestStage {
+	s.sloMaxErrorRate = &maxErrorRate
+	return s
+}
+
+func (s *RunTestStage) the_run_is_reported_as_stopped_because(reason string) *RunTestStage {
+	s.assert.Contains(s.stdout.String(), "Stopped - waiting for active tests to complete")
+	s.assert.Contains(s.stdout.String(), fmt.Sprintf("reason=%q", reason))
+	return s
+}
+
+func (s *RunTestStage) a_push_gateway_which_fails() *RunTestStage {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	s.t.Cleanup(ts.Close)
+
+	s.settings.Prometheus.PushGateway = ts.URL
+	return s
+}
+
+func (s *RunTestStage) a_push_gateway_which_counts_the_pushes() *RunTestStage {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		s.metricsPushes.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	s.t.Cleanup(ts.Close)
+
+	s.settings.Prometheus.PushGateway = ts.URL
+	return s
+}
+
+func (s *RunTestStage) an_opentelemetry_collector() *RunTestStage {
+	return s.an_opentelemetry_collector_responding(http.StatusOK)
+}
+
+func (s *RunTestStage) an_opentelemetry_collector_which_fails() *RunTestStage {
+	return s.an_opentelemetry_collector_responding(http.StatusServiceUnavailable)
+}
+
+// an_opentelemetry_collector_responding receives the exports of the metrics of the run instead of
+// the push gateway, responding with status.
+func (s *RunTestStage) an_opentelemetry_collector_responding(status int) *RunTestStage {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		s.assert.NoError(err)
+		var export collectormetrics.ExportMetricsServiceRequest
+		s.assert.NoError(proto.Unmarshal(body, &export))
+
+		s.metricsPushes.Add(1)
+		s.otlpExportsMu.Lock()
+		s.otlpExports = append(s.otlpExports, &export)
+		s.otlpExportsMu.Unlock()
+		w.WriteHeader(status)
+	}))
+	s.t.Cleanup(ts.Close)
+
+	s.settings.Prometheus.PushGateway = ""
+	s.settings.OTLP.MetricsEndpoint = ts.URL
+	return s
+}
+
+// the_metrics_were_exported_with checks the resource attributes of the last export of the metrics,
+// and that it has the metric of the iterations.
+func (s *RunTestStage) the_metrics_were_exported_with(attributes map[string]string) *RunTestStage {
+	s.otlpExportsMu.Lock()
+	defer s.otlpExportsMu.Unlock()
+
+	s.require.NotEmpty(s.otlpExports)
+	resourceMetrics := s.otlpExports[len(s.otlpExports)-1].GetResourceMetrics()
+	s.require.Len(resourceMetrics, 1)
+
+	exported := map[string]string{}
+	for _, attribute := range resourceMetrics[0].GetResource().GetAttributes() {
+		exported[attribute.GetKey()] = attribute.GetValue().GetStringValue()
+	}
+	for name, value := range attributes {
+		s.assert.Equal(value, exported[name], "attribute %s", name)
+	}
+
+	var names []string
+	for _, scope := range resourceMetrics[0].GetScopeMetrics() {
+		for _, metric := range scope.GetMetrics() {
+			names = append(names, metric.GetName())
+		}
+	}
+	s.assert.Contains(names, iterationMetricFamily)
+	return s
+}
+
+func (s *RunTestStage) metrics_pushed_every(interval time.Duration) *RunTestStage {
+	s.settings.Prometheus.PushInterval = interval.String()
+	return s
+}
+
+func (s *RunTestStage) unchanged_metrics_pushed_every(heartbeat time.Duration) *RunTestStage {
+	s.settings.Prometheus.PushHeartbeat = heartbeat.String()
+	return s
+}
+
+func (s *RunTestStage) the_metrics_were_pushed_between(minPushes, maxPushes int32) *RunTestStage {
+	pushes := s.metricsPushes.Load()
+	s.assert.GreaterOrEqual(pushes, minPushes)
+	s.assert.LessOrEqual(pushes, maxPushes)
+	return s
+}
+
+func (s *RunTestStage) metrics_required_with_at_most_failed_pushes_in_a_row(failures int) *RunTestStage {
+	s.requireMetrics = failures
+	return s
+}
+
+func (s *RunTestStage) the_failed_metrics_pushes_are_reported(failures uint64) *RunTestStage {
+	s.assert.Equal(failures, s.runResult.Report().MetricsPushFailures)
+	s.assert.Contains(s.runResult.Summary().Render(), fmt.Sprintf("Failed Metrics Pushes: %d", failures))
+	return s
+}
+
+func (s *RunTestStage) the_first_and_last_failed_iterations_are_reported() *RunTestStage {
+	report := s.runResult.Report()
+	s.require.Len(report.FirstFailures, 5)
+	s.require.Len(report.LastFailures, 5)
+	s.assert.Less(report.FirstFailures[4].Time, report.LastFailures[0].Time)
+	for _, failure := range slices.Concat(report.FirstFailures, report.LastFailures) {
+		s.assert.NotEmpty(failure.Iteration)
+		s.assert.NotEmpty(failure.Error)
+		s.assert.Positive(failure.Offset)
+	}
+
+	summary := s.runResult.Failures().Render()
+	s.assert.Contains(summary, "First failed iterations:")
+	s.assert.Contains(summary, "Last failed iterations:")
+	s.assert.Contains(summary, "iteration "+report.LastFailures[4].Iteration+" at ")
+	return s
+}
+
+func (s *RunTestStage) workers_pinned_to_the_available_cpus() *RunTestStage {
+	cpus, err := cpupin.Available()
+	if errors.Is(err, cpupin.ErrUnsupported) {
+		s.t.Skip(err)
+	}
+	s.require.NoError(err)
+
+	s.workerCPUs = cpus
+	return s
+}
+
+func (s *RunTestStage) a_scenario_which_records_the_cpus_it_runs_on() *RunTestStage {
+	s.scenario = "scenario_which_records_the_cpus_it_runs_on"
+	s.f1.Add(s.scenario, func(*f1_testing.T) f1_testing.RunFn {
+		return func(iterationT *f1_testing.T) {
+			cpus, err := cpupin.Available()
+			iterationT.Require().NoError(err)
+			s.iterationCPUs.Store(iterationT.Iteration, cpus)
+		}
+	})
+	return s
+}
+
+func (s *RunTestStage) each_iteration_ran_on_one_of_the_worker_cpus() *RunTestStage {
+	iterations := 0
+	s.iterationCPUs.Range(func(_, value any) bool {
+		iterations++
+		cpus, ok := value.([]int)
+		s.require.True(ok)
+		s.require.Len(cpus, 1)
+		s.assert.Contains(s.workerCPUs, cpus[0])
+		return true
+	})
+	s.assert.Positive(iterations)
+	return s
+}
+
+type workerValue struct {
+	closed *atomic.Int32
+}
+
+func (v *workerValue) Close() error {
+	v.closed.Add(1)
+	return nil
+}
+
+func (s *RunTestStage) a_scenario_where_each_iteration_reads_a_worker_local_value() *RunTestStage {
+	s.scenario = "scenario_where_each_iteration_reads_a_worker_local_value"
+	s.f1.Add(s.scenario, func(*f1_testing.T) f1_testing.RunFn {
+		values := f1_testing.NewWorkerLocal(func() *workerValue {
+			s.workerValuesCreated.Add(1)
+			return &workerValue{closed: &s.workerValuesClosed}
+		})
+
+		return func(iterationT *f1_testing.T) {
+			iterationT.Require().Same(values.Get(iterationT), values.Get(iterationT))
+		}
+	})
+	return s
+}
+
+func (s *RunTestStage) each_worker_created_one_value_closed_when_it_stopped() *RunTestStage {
+	created := s.workerValuesCreated.Load()
+	s.assert.Positive(created)
+	s.assert.LessOrEqual(int(created), s.concurrency)
+	s.assert.Equal(created, s.workerValuesClosed.Load())
+	return s
+}
+
+func (s *RunTestStage) workers_ramped_over(ramp time.Duration) *RunTestStage {
+	s.workerRamp = ramp
+	return s
+}
+
+func (s *RunTestStage) workers_paced_every(interval time.Duration) *RunTestStage {
+	s.workerPacing = interval
+	return s
+}
+
+func (s *RunTestStage) dropped_iterations_are_ignored() *RunTestStage {
+	s.ignoreDropped = true
+	return s
+}
+
+func (s *RunTestStage) the_concurrency_is_autotuned() *RunTestStage {
+	s.autotune = true
+	return s
+}
+
+func (s *RunTestStage) the_concurrency_was_autotuned_to_between(minimum, maximum int) *RunTestStage {
+	matches := regexp.MustCompile(`msg="autotuned concurrency" .*workers=(\d+)`).FindAllStringSubmatch(s.stdout.String(), -1)
+	s.require.NotEmpty(matches, "the concurrency was not autotuned")
+	workers, err := strconv.Atoi(matches[len(matches)-1][1])
+	s.require.NoError(err)
+	s.assert.GreaterOrEqual(workers, minimum, "autotuned workers")
+	s.assert.LessOrEqual(workers, maximum, "autotuned workers")
+	return s
+}
+
+func (s *RunTestStage) some_iterations_were_dropped() *RunTestStage {
+	s.assert.Positive(s.runResult.Snapshot().DroppedIterationCount, "no iterations were dropped")
+	return s
+}
+
+func (s *RunTestStage) a_scenario_recording_when_each_worker_starts() *RunTestStage {
+	s.scenario = "scenario_recording_when_each_worker_starts"
+	s.f1.Add(s.scenario, func(*f1_testing.T) f1_testing.RunFn {
+		s.workersSetUp = time.Now()
+		started := f1_testing.NewWorkerLocal(func() time.Time {
+			now := time.Now()
+			s.workerStartsMu.Lock()
+			s.workerStarts = append(s.workerStarts, now)
+			s.workerStartsMu.Unlock()
+			return now
+		})
+
+		return func(iterationT *f1_testing.T) {
+			started.Get(iterationT)
+			time.Sleep(10 * time.Millisecond)
+		}
+	})
+	return s
+}
+
+func (s *RunTestStage) the_workers_started_gradually_over(ramp time.Duration) *RunTestStage {
+	s.workerStartsMu.Lock()
+	defer s.workerStartsMu.Unlock()
+
+	s.require.Len(s.workerStarts, s.concurrency)
+	slices.SortFunc(s.workerStarts, func(a, b time.Time) int { return a.Compare(b) })
+	first := s.workerStarts[0]
+	last := s.workerStarts[len(s.workerStarts)-1]
+	// the last worker starts one step before the end of the ramp, which starts after the scenario is
+	// set up, while the first worker may record its start late, in its first iteration
+	s.assert.GreaterOrEqual(last.Sub(s.workersSetUp),
+		ramp*time.Duration(s.concurrency-1)/time.Duration(s.concurrency))
+	startedEarly := 0
+	for _, start := range s.workerStarts {
+		if start.Sub(first) < ramp/4 {
+			startedEarly++
+		}
+	}
+	s.assert.LessOrEqual(startedEarly, s.concurrency/4+1)
+	return s
+}
+
+func (s *RunTestStage) a_scenario_recording_the_phase_of_its_iterations() *RunTestStage {
+	s.scenario = "scenario_recording_the_phase_of_its_iterations"
+	s.f1.Add(s.scenario, func(t *f1_testing.T) f1_testing.RunFn {
+		s.assert.Equal(f1_testing.Phase{}, t.Phase())
+
+		return func(iterationT *f1_testing.T) {
+			phase := iterationT.Phase()
+			s.phasesMu.Lock()
+			s.phases = append(s.phases, phase)
+			s.phasesMu.Unlock()
+		}
+	})
+	return s
+}
+
+func (s *RunTestStage) the_iterations_ran_in_stages_starting_at(starts map[string]time.Duration) *RunTestStage {
+	s.phasesMu.Lock()
+	defer s.phasesMu.Unlock()
+
+	observed := map[string]bool{}
+	for _, phase := range s.phases {
+		start, ok := starts[phase.Stage]
+		s.require.True(ok, "unexpected stage %q", phase.Stage)
+		observed[phase.Stage] = true
+
+		s.assert.Positive(phase.RunElapsed)
+		s.assert.InDelta(start, phase.RunElapsed-phase.StageElapsed, float64(2*time.Millisecond))
+	}
+	s.assert.Len(observed, len(starts))
+	return s
+}
+
+func (s *RunTestStage) a_scenario_where_the_iterations_of_one_worker_fail() *RunTestStage {
+	s.scenario = "scenario_where_the_iterations_of_one_worker_fail"
+	s.f1.Add(s.scenario, func(*f1_testing.T) f1_testing.RunFn {
+		var poisoned atomic.Bool
+		connections := f1_testing.NewWorkerLocal(func() bool {
+			return poisoned.CompareAndSwap(false, true)
+		})
+
+		return func(iterationT *f1_testing.T) {
+			time.Sleep(5 * time.Millisecond)
+			if connections.Get(iterationT) {
+				iterationT.Errorf("connection reset")
+			}
+		}
+	})
+	return s
+}
+
+func (s *RunTestStage) the_failures_are_reported_as_isolated_to_one_worker() *RunTestStage {
+	report := s.runResult.Report()
+	s.require.Len(report.FailuresByWorker, 1)
+	s.assert.Equal(report.FailedIterationDurations.Count, report.FailuresByWorker[0].Failures)
+	s.assert.Contains(s.runResult.Failures().Render(),
+		fmt.Sprintf("failures on worker %s, suggesting a poisoned connection", report.FailuresByWorker[0].Worker))
 	return s
 }
 
-func (s *RunTestStage) a_end_rate_of(endRate string) *RunTestStage {
-	s.endRate = endRate
+func (s *RunTestStage) a_search_from(minRate, maxRate string, probeDuration time.Duration) *RunTestStage {
+	s.triggerType = Search
+	s.searchFlags = map[string]string{
+		"min-rate":       minRate,
+		"max-rate":       maxRate,
+		"probe-duration": probeDuration.String(),
+		"distribution":   "none",
+	}
 	return s
 }
 
-func (s *RunTestStage) a_ramp_duration_of(rampDuration string) *RunTestStage {
-	s.rampDuration = rampDuration
+func (s *RunTestStage) connections_ramped_from(start, end int, rampDuration time.Duration) *RunTestStage {
+	s.triggerType = Connections
+	s.connectionsFlags = map[string]string{
+		"start-connections": strconv.Itoa(start),
+		"end-connections":   strconv.Itoa(end),
+		"ramp-duration":     rampDuration.String(),
+	}
 	return s
 }
 
-func (s *RunTestStage) setupRun() {
-	printer := ui.NewPrinter(&s.stdout, &s.stderr)
-	logger := log.NewLogger(&s.stdout, logutils.NewLogConfigFromSettings(s.settings))
-	outputer := ui.NewOutput(logger, printer, s.interactive, false)
+func (s *RunTestStage) a_scenario_holding_connections_open_where_the_first_is_dropped() *RunTestStage {
+	s.scenario = "scenario_holding_connections_open"
+	s.f1.Add(s.scenario, func(*f1_testing.T) f1_testing.RunFn {
+		return func(t *f1_testing.T) {
+			open := s.openConnections.Add(1)
+			defer s.openConnections.Add(-1)
+			for peak := s.peakConnections.Load(); open > peak; peak = s.peakConnections.Load() {
+				if s.peakConnections.CompareAndSwap(peak, open) {
+					break
+				}
+			}
+			if s.runCount.Add(1) == 1 {
+				return
+			}
 
-	r, err := run.NewRun(options.RunOptions{
-		Scenario:        s.scenario,
-		MaxDuration:     s.duration,
-		Concurrency:     s.concurrency,
-		MaxIterations:   s.maxIterations,
-		MaxFailures:     s.maxFailures,
-		MaxFailuresRate: s.maxFailuresRate,
-		Verbose:         s.verbose,
-	}, s.f1.GetScenarios(), s.build_trigger(), s.waitForCompletionTimeout, s.settings, s.metrics, outputer)
+			<-t.Context().Done()
+			s.closedConnections.Add(1)
+		}
+	})
+	return s
+}
 
-	s.require.NoError(err)
-	s.runInstance = r
+func (s *RunTestStage) the_connections_were_held_open_and_closed_at_the_end_of_the_run(
+	connections int32,
+) *RunTestStage {
+	s.assert.Equal(connections, s.peakConnections.Load())
+	s.assert.Equal(connections, s.closedConnections.Load())
+	s.assert.Zero(s.openConnections.Load())
+
+	// every connection is an iteration, and the dropped connection was opened again
+	report := s.runResult.Report()
+	s.assert.Equal(uint64(connections+1), report.IterationsStarted)
+	s.assert.Equal(uint64(connections+1), report.SuccessfulIterationDurations.Count)
+	for event, expected := range map[string]float64{
+		workers.ConnectionOpened:  float64(connections + 1),
+		workers.ConnectionDropped: 1,
+		workers.ConnectionClosed:  float64(connections),
+	} {
+		metric := &io_prometheus_client.Metric{}
+		s.require.NoError(s.metrics.ConnectionEvents.WithLabelValues(s.scenario, event).Write(metric))
+		s.assert.InDelta(expected, metric.GetCounter().GetValue(), 0, event)
+	}
+	return s
 }
 
-func (s *RunTestStage) the_run_command_is_executed() *RunTestStage {
-	s.setupRun()
+func (s *RunTestStage) a_token_bucket_of(rate string, burst int) *RunTestStage {
+	s.triggerType = TokenBucket
+	s.bucketFlags = map[string]string{
+		"rate":  rate,
+		"burst": strconv.Itoa(burst),
+	}
+	return s
+}
 
-	var err error
-	s.runResult, err = s.runInstance.Do(context.TODO())
+// the_first_iterations_started_in_a_burst_of asserts that the first burst iterations started
+// within a tick of the trigger, and the next one later on.
+func (s *RunTestStage) the_first_iterations_started_in_a_burst_of(burst int) *RunTestStage {
+	var starts []time.Time
+	s.durations.Range(func(key, _ any) bool {
+		started, ok := key.(time.Time)
+		s.require.True(ok)
+		starts = append(starts, started)
+		return true
+	})
+	slices.SortFunc(starts, func(a, b time.Time) int { return a.Compare(b) })
+
+	s.require.Greater(len(starts), burst)
+	s.assert.Less(starts[burst-1].Sub(starts[0]), 50*time.Millisecond, "burst of %d iterations", burst)
+	s.assert.GreaterOrEqual(starts[burst].Sub(starts[0]), 50*time.Millisecond, "iteration after the burst")
+	return s
+}
+
+func (s *RunTestStage) the_number_of_started_iterations_should_be_between(minimum, maximum int) *RunTestStage {
+	s.assert.GreaterOrEqual(int(s.runCount.Load()), minimum, "number of started iterations")
+	s.assert.LessOrEqual(int(s.runCount.Load()), maximum, "number of started iterations")
+	return s
+}
+
+// a_scenario_failing_bursts_larger_than fails the iterations started while more than capacity
+// iterations are in progress, so that with bursts of iterations shorter than the interval between
+// them, rates above capacity per interval fail.
+func (s *RunTestStage) a_scenario_failing_bursts_larger_than(capacity int32) *RunTestStage {
+	s.scenario = "scenario_failing_bursts_larger_than"
+	s.f1.Add(s.scenario, func(*f1_testing.T) f1_testing.RunFn {
+		var inProgress atomic.Int32
+		return func(iterationT *f1_testing.T) {
+			defer inProgress.Add(-1)
+			if inProgress.Add(1) > capacity {
+				iterationT.Fail()
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+	})
+	return s
+}
+
+func (s *RunTestStage) the_search_found_a_capacity_of(capacity string) *RunTestStage {
+	s.assert.Contains(s.stdout.String(), "search completed: the capacity is "+capacity)
+	return s
+}
+
+func (s *RunTestStage) the_run_completed_within(duration time.Duration) *RunTestStage {
+	s.assert.Less(s.runResult.TestDuration, duration)
+	return s
+}
+
+func (s *RunTestStage) a_report_snapshot_file_written_every(interval time.Duration) *RunTestStage {
+	s.reportSnapshotFile = filepath.Join(s.t.TempDir(), "report.json")
+	s.reportSnapshotInterval = interval
+	return s
+}
+
+// the_report_snapshot_file_is_read_after reads the report snapshot file while the run is in
+// progress, passing the report, or nil if it couldn't be read, to reportSnapshot.
+func (s *RunTestStage) the_report_snapshot_file_is_read_after(delay time.Duration) *RunTestStage {
+	s.reportSnapshot = make(chan *run.Report, 1)
+	timer := time.AfterFunc(delay, func() {
+		data, err := os.ReadFile(s.reportSnapshotFile)
+		if !s.assert.NoError(err) {
+			s.reportSnapshot <- nil
+			return
+		}
+
+		report := run.Report{}
+		if !s.assert.NoError(json.Unmarshal(data, &report)) {
+			s.reportSnapshot <- nil
+			return
+		}
+		s.reportSnapshot <- &report
+	})
+	s.t.Cleanup(func() { timer.Stop() })
+	return s
+}
+
+func (s *RunTestStage) the_report_snapshot_was_written_during_the_run() *RunTestStage {
+	var snapshot *run.Report
+	select {
+	case snapshot = <-s.reportSnapshot:
+	case <-time.After(5 * time.Second):
+		s.require.Fail("the report snapshot file was not read")
+	}
+
+	s.require.NotNil(snapshot)
+	s.assert.Positive(snapshot.IterationsStarted)
+	s.assert.Positive(snapshot.Duration)
+	s.assert.Less(snapshot.Duration, s.runResult.TestDuration)
+	s.assert.True(snapshot.Partial)
+	return s
+}
+
+func (s *RunTestStage) the_report_snapshot_file_has_the_final_report() *RunTestStage {
+	data, err := os.ReadFile(s.reportSnapshotFile)
 	s.require.NoError(err)
 
+	report := run.Report{}
+	s.require.NoError(json.Unmarshal(data, &report))
+	s.assert.Equal(s.runResult.Report().IterationsStarted, report.IterationsStarted)
+	s.assert.Equal(s.runResult.TestDuration, report.Duration)
+	s.assert.False(report.Partial)
 	return s
 }
 
-func (s *RunTestStage) the_run_command_is_executed_and_cancelled_after(duration time.Duration) *RunTestStage {
-	s.setupRun()
+func (s *RunTestStage) the_report_has_the_stages(stages ...string) *RunTestStage {
+	report := s.runResult.Report()
+	s.require.Len(report.Stages, len(stages))
 
-	var err error
-	ctx, cancel := context.WithCancel(context.TODO())
-	go func() {
-		<-time.After(duration)
-		cancel()
-	}()
+	var started, failed uint64
+	for i, stage := range report.Stages {
+		s.assert.Equal(stages[i], stage.Stage)
+		s.assert.Positive(stage.IterationsStarted)
+		s.assert.Positive(stage.SuccessfulIterationDurations.P95)
+		s.assert.Empty(stage.SLOViolations)
+		started += stage.IterationsStarted
+		failed += stage.FailedIterationDurations.Count
+	}
+	s.assert.Equal(report.IterationsStarted, started)
+	s.assert.Equal(report.FailedIterationDurations.Count, failed)
+	return s
+}
 
-	s.runResult, err = s.runInstance.Do(ctx)
+func (s *RunTestStage) the_iterations_were_only_dropped_in_stage_between(
+	stage string, from, to time.Duration,
+) *RunTestStage {
+	report := s.runResult.Report()
+	s.require.NotEmpty(report.Drops)
+
+	var dropped uint64
+	for _, drop := range report.Drops {
+		s.assert.Equal(stage, drop.Stage)
+		s.assert.GreaterOrEqual(drop.Offset, from)
+		s.assert.Less(drop.Offset, to)
+		dropped += drop.Count
+	}
+	s.assert.Equal(report.DroppedIterationCount, dropped)
+	return s
+}
+
+func (s *RunTestStage) the_achieved_rate_is_the_target_rate_every_second() *RunTestStage {
+	report := s.runResult.Report()
+	s.require.Len(report.Rates, 2)
+
+	var achieved uint64
+	for i, rate := range report.Rates {
+		s.assert.Equal(time.Duration(i)*time.Second, rate.Offset)
+		s.assert.InDelta(100, rate.Target, 10)
+		// iterations triggered at the end of a second can start in the next one, and iterations are
+		// started late by a loaded machine
+		s.assert.InDelta(rate.Target, rate.Achieved, 20)
+		achieved += rate.Achieved
+	}
+	s.assert.Equal(report.IterationsStarted, achieved)
+	return s
+}
+
+func (s *RunTestStage) the_achieved_rate_is_below_the_target_rate_every_second() *RunTestStage {
+	report := s.runResult.Report()
+	s.require.NotEmpty(report.Rates)
+
+	for _, rate := range report.Rates {
+		s.assert.Less(rate.Achieved, rate.Target/10)
+	}
+	return s
+}
+
+func (s *RunTestStage) the_slowest_iterations_are_shown() *RunTestStage {
+	s.showSlowest = true
+	return s
+}
+
+func (s *RunTestStage) the_slowest_iterations_are_reported() *RunTestStage {
+	report := s.runResult.Report()
+	s.require.Len(report.Slowest, progress.SlowestKept)
+	s.assert.Equal(report.SuccessfulIterationDurations.Max, report.Slowest[0].Duration)
+
+	// the iterations are timed by f1 from before they start to after they complete, so each of the
+	// slowest iterations is at least as slow as the median of the durations timed by the iterations
+	s.iterationDurationsMu.Lock()
+	durations := slices.Clone(s.iterationDurations)
+	s.iterationDurationsMu.Unlock()
+	s.require.GreaterOrEqual(len(durations), 2*progress.SlowestKept)
+	slices.Sort(durations)
+	p50 := durations[len(durations)/2]
+
+	for i, iteration := range report.Slowest {
+		s.assert.GreaterOrEqual(iteration.Duration, p50)
+		s.assert.True(strings.HasPrefix(iteration.Worker, "trigger-"), iteration.Worker)
+		s.assert.NotEmpty(iteration.Iteration)
+		if i > 0 {
+			s.assert.LessOrEqual(iteration.Duration, report.Slowest[i-1].Duration)
+		}
+	}
+	s.assert.Contains(s.stdout.String(), `msg="Slow iteration" scenario=`+s.scenario+` iteration=`)
+	return s
+}
+
+func (s *RunTestStage) the_dropped_iterations_are_reported_by_stage(stage string) *RunTestStage {
+	s.assert.Contains(s.stdout.String(), `msg="Dropped iterations" scenario=`+s.scenario+` stage="`+stage+`"`)
+	return s
+}
+
+func (s *RunTestStage) a_scenario_with_parallel_cleanups_where_one_hangs_and_one_fails() *RunTestStage {
+	s.scenario = "scenario_with_parallel_cleanups"
+	// the hanging cleanup is released once the test completes, so that it doesn't leak
+	release := make(chan struct{})
+	s.t.Cleanup(func() { close(release) })
+
+	s.f1.Add(s.scenario, func(scenarioT *f1_testing.T) f1_testing.RunFn {
+		scenarioT.ParallelCleanup("hangs", 100*time.Millisecond, func(context.Context) error {
+			<-release
+			return nil
+		})
+		scenarioT.ParallelCleanup("fails", time.Second, func(context.Context) error {
+			return errors.New("resource not found")
+		})
+		scenarioT.ParallelCleanup("succeeds", time.Second, func(context.Context) error {
+			return nil
+		})
+
+		return func(*f1_testing.T) {}
+	})
+	return s
+}
+
+func (s *RunTestStage) the_teardown_errors_are_reported_without_waiting_for_the_hanging_cleanup() *RunTestStage {
+	err := s.runResult.Error()
+	s.require.Error(err)
+	s.assert.ErrorIs(err, f1_testing.ErrCleanupTimeout)
+	s.assert.ErrorContains(err, "teardown failed: ")
+	s.assert.ErrorContains(err, "cleanup hangs: cleanup timed out after 100ms")
+	s.assert.ErrorContains(err, "cleanup fails: resource not found")
+	s.assert.Less(s.runResult.TeardownDuration, time.Second)
+	return s
+}
+
+func (s *RunTestStage) a_scenario_which_summarizes_its_side_effects_in_its_cleanup() *RunTestStage {
+	s.scenario = "scenario_with_summary"
+	s.f1.Add(s.scenario, func(scenarioT *f1_testing.T) f1_testing.RunFn {
+		scenarioT.Summarize("created %d queues", 2)
+		var accounts atomic.Int64
+		scenarioT.Cleanup(func() {
+			scenarioT.Summarize("created %d accounts, deleted %d", accounts.Load(), accounts.Load())
+		})
+
+		return func(*f1_testing.T) {
+			accounts.Add(1)
+		}
+	})
+	return s
+}
+
+func (s *RunTestStage) the_summary_of_the_scenario_is_reported() *RunTestStage {
+	iterations := s.runResult.Report().IterationsStarted
+	summary := fmt.Sprintf("created %d accounts, deleted %d", iterations, iterations)
+	s.assert.Equal([]string{"created 2 queues", summary}, s.runResult.Report().ScenarioSummary)
+	s.assert.Contains(s.stdout.String(), `msg="Scenario summary" scenario=`+s.scenario+` summary="`+summary+`"`)
+	return s
+}
+
+func (s *RunTestStage) trace() string {
+	if s.traceFile == "" {
+		return tracing.Off
+	}
+	return tracing.File
+}
+
+func (s *RunTestStage) the_trace_file_has_events(names ...string) *RunTestStage {
+	content, err := os.ReadFile(s.traceFile)
 	s.require.NoError(err)
 
+	traced := map[string]bool{}
+	for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+		event := map[string]any{}
+		s.require.NoError(json.Unmarshal([]byte(line), &event))
+		name, _ := event["msg"].(string)
+		traced[name] = true
+	}
+
+	for _, name := range names {
+		s.assert.True(traced[name], "event %s not traced", name)
+	}
+	return s
+}
+
+func (s *RunTestStage) a_history_dir() *RunTestStage {
+	s.settings.History.Dir = s.t.TempDir()
+	return s
+}
+
+func (s *RunTestStage) the_history_has_n_runs(n int) *RunTestStage {
+	entries, err := history.NewStore(s.settings.History.Dir).Entries(s.scenario)
+	s.require.NoError(err)
+	s.assert.Len(entries, n)
 	return s
 }
 
@@ -312,45 +1904,276 @@ func (s *RunTestStage) a_test_scenario_that_always_fails_an_assertion() *RunTest
 		return func(iterationT *f1_testing.T) {
 			iterationT.Cleanup(s.iterationCleanup)
 
-			assert.True(iterationT, false)
-		}
+			assert.True(iterationT, false)
+		}
+	})
+	return s
+}
+
+func (s *RunTestStage) a_test_scenario_that_always_fails_setup() *RunTestStage {
+	s.scenario = "scenario_that_always_fails_setup"
+	s.f1.Add(s.scenario, func(scenarioT *f1_testing.T) f1_testing.RunFn {
+		scenarioT.Cleanup(s.scenarioCleanup)
+
+		scenarioT.FailNow()
+		return nil
+	})
+	return s
+}
+
+func (s *RunTestStage) a_scenario_capturing_goroutine_profiles() *RunTestStage {
+	s.scenario = "scenario_capturing_goroutine_profiles"
+	s.f1.Add(s.scenario, func(*f1_testing.T) f1_testing.RunFn {
+		return func(iterationT *f1_testing.T) {
+			var profile strings.Builder
+			iterationT.Require().NoError(pprof.Lookup("goroutine").WriteTo(&profile, 1))
+
+			s.goroutineProfilesMu.Lock()
+			defer s.goroutineProfilesMu.Unlock()
+			s.goroutineProfiles = append(s.goroutineProfiles, profile.String())
+		}
+	})
+	return s
+}
+
+func (s *RunTestStage) the_goroutines_are_labelled_in_the_profiles(labels ...string) *RunTestStage {
+	s.goroutineProfilesMu.Lock()
+	defer s.goroutineProfilesMu.Unlock()
+
+	s.require.NotEmpty(s.goroutineProfiles)
+	profiles := strings.Join(s.goroutineProfiles, "\n")
+	for _, label := range labels {
+		s.assert.Contains(profiles, label)
+	}
+	return s
+}
+
+func (s *RunTestStage) a_scenario_where_each_iteration_thinks_for(thinkTime time.Duration) *RunTestStage {
+	s.scenario = "scenario_where_each_iteration_thinks_for_" + thinkTime.String()
+	s.f1.Add(s.scenario, func(*f1_testing.T) f1_testing.RunFn {
+		return func(iterationT *f1_testing.T) {
+			if !iterationT.Sleep(thinkTime) {
+				s.sleepsCutShort.Add(1)
+			}
+		}
+	})
+	return s
+}
+
+func (s *RunTestStage) the_sleeps_of_the_iterations_were_cut_short_within(duration time.Duration) *RunTestStage {
+	s.assert.Positive(s.sleepsCutShort.Load())
+	s.assert.WithinDuration(s.startTime, time.Now(), duration)
+	return s
+}
+
+func (s *RunTestStage) a_scenario_where_each_iteration_takes(duration time.Duration) *RunTestStage {
+	s.scenario = "scenario_where_each_iteration_takes_" + duration.String()
+	s.f1.Add(s.scenario, func(scenarioT *f1_testing.T) f1_testing.RunFn {
+		scenarioT.Cleanup(s.scenarioCleanup)
+
+		scenarioT.Log("setup")
+		scenarioT.Logger().WithField("logger", "logrus").Info("logrus - setup")
+
+		s.runCount.Store(0)
+
+		return func(iterationT *f1_testing.T) {
+			if s.runCount.Load() == 0 {
+				scenarioT.Log("first iteration")
+				scenarioT.Logger().WithField("logger", "logrus").Info("logrus - first iteration")
+			}
+			iterationT.Cleanup(s.iterationCleanup)
+
+			s.runCount.Add(1)
+			s.durations.Store(time.Now(), time.Since(s.startTime))
+			time.Sleep(duration)
+		}
+	})
+	return s
+}
+
+func (s *RunTestStage) a_scenario_timing_each_iteration_taking(duration time.Duration) *RunTestStage {
+	s.scenario = "scenario_timing_each_iteration"
+	s.f1.Add(s.scenario, func(scenarioT *f1_testing.T) f1_testing.RunFn {
+		scenarioT.Cleanup(s.scenarioCleanup)
+
+		return func(*f1_testing.T) {
+			start := time.Now()
+			time.Sleep(duration)
+
+			s.iterationDurationsMu.Lock()
+			defer s.iterationDurationsMu.Unlock()
+			s.iterationDurations = append(s.iterationDurations, time.Since(start))
+		}
+	})
+	return s
+}
+
+func (s *RunTestStage) a_scenario_annotated_with(name, value string) *RunTestStage {
+	s.scenario = "scenario_annotated_with_" + name
+	s.f1.Add(s.scenario, func(*f1_testing.T) f1_testing.RunFn {
+		return func(*f1_testing.T) {}
+	}, scenarios.Annotation(name, value))
+	return s
+}
+
+func (s *RunTestStage) a_template_variable(name, value string) *RunTestStage {
+	if s.templateVariables == nil {
+		s.templateVariables = map[string]string{}
+	}
+	s.templateVariables[name] = value
+	return s
+}
+
+func (s *RunTestStage) a_template_dir_with(name, text string) *RunTestStage {
+	s.templateDir = s.t.TempDir()
+	s.require.NoError(os.WriteFile(filepath.Join(s.templateDir, name), []byte(text), 0o600))
+	return s
+}
+
+// the_summary_is checks the summary of the run, where {run_id} stands for the run ID of the run.
+func (s *RunTestStage) the_summary_is(expected string) *RunTestStage {
+	logFile := filepath.Base(s.runResult.LogFilePath)
+	runID := strings.TrimSuffix(logFile, filepath.Ext(logFile))
+	s.assert.Equal(strings.ReplaceAll(expected, "{run_id}", runID), s.runResult.Summary().Render())
+	return s
+}
+
+func (s *RunTestStage) a_hard_deadline_of(deadline time.Duration) *RunTestStage {
+	s.hardDeadline = deadline
+	return s
+}
+
+func (s *RunTestStage) a_scenario_where_teardown_hangs() *RunTestStage {
+	s.scenario = "scenario_where_teardown_hangs"
+	s.releaseTeardown = make(chan struct{})
+	teardownReleased := s.releaseTeardown
+	s.f1.Add(s.scenario, func(scenarioT *f1_testing.T) f1_testing.RunFn {
+		scenarioT.Cleanup(func() {
+			<-teardownReleased
+		})
+
+		return func(*f1_testing.T) {}
+	})
+	return s
+}
+
+func (s *RunTestStage) the_run_is_terminated_at_the_hard_deadline_within(duration time.Duration) *RunTestStage {
+	s.require.ErrorIs(s.runErr, run.ErrHardDeadlineExceeded)
+	s.assert.Nil(s.runResult)
+	s.assert.Less(s.runDuration, duration)
+	s.assert.Contains(s.stdout.String(), "terminating the run")
+	return s
+}
+
+func (s *RunTestStage) the_abandoned_run_is_cancelled_and_completes() *RunTestStage {
+	s.assert.Eventually(func() bool {
+		return strings.Contains(s.stdout.String(), "Interrupted - waiting for active tests to complete") &&
+			strings.Contains(s.stdout.String(), "Load Test Passed")
+	}, time.Second, 10*time.Millisecond)
+	return s
+}
+
+// the_abandoned_run_completes_once_the_teardown_is_released releases the hung teardown, so that the
+// run abandoned at the hard deadline completes rather than leaking its goroutines.
+func (s *RunTestStage) the_abandoned_run_completes_once_the_teardown_is_released() *RunTestStage {
+	close(s.releaseTeardown)
+	s.assert.Eventually(func() bool {
+		return strings.Contains(s.stdout.String(), "Load Test Passed")
+	}, time.Second, 10*time.Millisecond)
+	return s
+}
+
+func (s *RunTestStage) a_scenario_where_setup_and_teardown_take(duration time.Duration) *RunTestStage {
+	s.scenario = "scenario_where_setup_and_teardown_take_" + duration.String()
+	s.f1.Add(s.scenario, func(scenarioT *f1_testing.T) f1_testing.RunFn {
+		scenarioT.Cleanup(func() {
+			time.Sleep(duration)
+		})
+		time.Sleep(duration)
+
+		return func(*f1_testing.T) {}
 	})
 	return s
 }
 
-func (s *RunTestStage) a_test_scenario_that_always_fails_setup() *RunTestStage {
-	s.scenario = "scenario_that_always_fails_setup"
-	s.f1.Add(s.scenario, func(scenarioT *f1_testing.T) f1_testing.RunFn {
-		scenarioT.Cleanup(s.scenarioCleanup)
+func (s *RunTestStage) the_phase_durations_are_reported_separately(setup, load time.Duration) *RunTestStage {
+	report := s.runResult.Report()
 
-		scenarioT.FailNow()
-		return nil
-	})
+	s.assert.GreaterOrEqual(report.SetupDuration, setup)
+	s.assert.GreaterOrEqual(report.TeardownDuration, setup)
+	s.assert.InDelta(load, report.Duration, float64(100*time.Millisecond))
+	s.assert.Contains(s.stdout.String(), fmt.Sprintf("iterations started in %s", report.Duration))
 	return s
 }
 
-func (s *RunTestStage) a_scenario_where_each_iteration_takes(duration time.Duration) *RunTestStage {
-	s.scenario = "scenario_where_each_iteration_takes_" + duration.String()
+func (s *RunTestStage) a_scenario_where_every_other_iteration_is_slower_than(sla time.Duration) *RunTestStage {
+	s.scenario = "scenario_where_every_other_iteration_is_slower_than_" + sla.String()
 	s.f1.Add(s.scenario, func(scenarioT *f1_testing.T) f1_testing.RunFn {
 		scenarioT.Cleanup(s.scenarioCleanup)
 
-		scenarioT.Log("setup")
-		scenarioT.Logger().WithField("logger", "logrus").Info("logrus - setup")
-
 		s.runCount.Store(0)
+		return func(t *f1_testing.T) {
+			t.Cleanup(s.iterationCleanup)
 
-		return func(iterationT *f1_testing.T) {
-			if s.runCount.Load() == 0 {
-				scenarioT.Log("first iteration")
-				scenarioT.Logger().WithField("logger", "logrus").Info("logrus - first iteration")
+			if s.runCount.Add(1)%2 == 0 {
+				time.Sleep(2 * sla)
 			}
-			iterationT.Cleanup(s.iterationCleanup)
+		}
+	}, scenarios.Classifier(func(err error, duration time.Duration) f1_testing.ResultType {
+		if err != nil || duration > sla {
+			return f1_testing.FailedResult
+		}
+		return f1_testing.SuccessResult
+	}))
+	return s
+}
 
-			s.runCount.Add(1)
-			s.durations.Store(time.Now(), time.Since(s.startTime))
-			time.Sleep(duration)
+// a_scenario_failing_with_fast_server_errors_and_slow_timeouts fails every iteration, every other
+// one straight away with a server error and the others with a timeout after slowDuration.
+func (s *RunTestStage) a_scenario_failing_with_fast_server_errors_and_slow_timeouts(
+	slowDuration time.Duration,
+) *RunTestStage {
+	s.scenario = "scenario_failing_with_fast_server_errors_and_slow_timeouts"
+	s.f1.Add(s.scenario, func(*f1_testing.T) f1_testing.RunFn {
+		var iterations atomic.Int32
+		return func(t *f1_testing.T) {
+			if iterations.Add(1)%2 == 0 {
+				time.Sleep(slowDuration)
+				t.Error(fmt.Errorf("calling the target: %w", context.DeadlineExceeded))
+				return
+			}
+			t.Error(errors.New("status 503"))
 		}
-	})
+	}, scenarios.FailureCategories(func(err error, _ time.Duration) string {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return "timeout"
+		}
+		return "5xx"
+	}))
+	return s
+}
+
+// the_failures_are_summarised_by_category asserts that the failed iterations of each category, and
+// whether their p95 is at least slowDuration, are reported and logged.
+func (s *RunTestStage) the_failures_are_summarised_by_category(
+	slowDuration time.Duration, categories ...string,
+) *RunTestStage {
+	report := s.runResult.Report()
+	s.require.Len(report.FailureCategories, len(categories))
+
+	var failed uint64
+	for _, category := range report.FailureCategories {
+		s.assert.Contains(categories, category.Category)
+		s.assert.Positive(category.Durations.Count)
+		if category.Category == "timeout" {
+			s.assert.GreaterOrEqual(category.Durations.P95, slowDuration)
+		} else {
+			s.assert.Less(category.Durations.P95, slowDuration)
+		}
+		failed += category.Durations.Count
+	}
+	s.assert.Equal(report.FailedIterationDurations.Count, failed)
+	s.assert.Contains(s.stdout.String(), "Failed iterations by category")
 	return s
 }
 
@@ -452,6 +2275,16 @@ func (s *RunTestStage) an_iteration_limit_of(iterations uint64) *RunTestStage {
 	return s
 }
 
+// build_plannedTrigger builds the trigger of the run, which plans its iterations with the rates of
+// another trigger, as the run command does.
+func (s *RunTestStage) build_plannedTrigger() *api.Trigger {
+	t := s.build_trigger()
+	if t.PlannedIterations != nil {
+		t.PlannedIterations = s.build_trigger().PlannedIterations
+	}
+	return t
+}
+
 func (s *RunTestStage) build_trigger() *api.Trigger {
 	var t *api.Trigger
 	var err error
@@ -515,17 +2348,127 @@ func (s *RunTestStage) build_trigger() *api.Trigger {
 		t, err = ramp.Rate().New(flags)
 		require.NoError(s.t, err)
 	case File:
-		flags := file.Rate(s.output).Flags
+		flags := file.Rate(s.output, nil).Flags
 
 		err := flags.Parse([]string{s.configFile})
 		require.NoError(s.t, err)
 
-		t, err = file.Rate(s.output).New(flags)
+		t, err = file.Rate(s.output, nil).New(flags)
+		require.NoError(s.t, err)
+	case Search:
+		flags := search.Rate().Flags
+		for name, value := range s.searchFlags {
+			require.NoError(s.t, flags.Set(name, value))
+		}
+
+		t, err = search.Rate().New(flags)
+		require.NoError(s.t, err)
+	case TokenBucket:
+		flags := tokenbucket.Rate().Flags
+		for name, value := range s.bucketFlags {
+			require.NoError(s.t, flags.Set(name, value))
+		}
+
+		t, err = tokenbucket.Rate().New(flags)
+		require.NoError(s.t, err)
+	case Connections:
+		flags := connections.Rate().Flags
+		for name, value := range s.connectionsFlags {
+			require.NoError(s.t, flags.Set(name, value))
+		}
+
+		t, err = connections.Rate().New(flags)
 		require.NoError(s.t, err)
 	}
+
+	if s.triggerPanicAfter > 0 {
+		trigger := t.Trigger
+		t.Trigger = func(ctx context.Context, output *ui.Output, pool *workers.PoolManager, opts options.RunOptions) {
+			panicCtx, cancel := context.WithTimeout(ctx, s.triggerPanicAfter)
+			defer cancel()
+
+			trigger(panicCtx, output, pool, opts)
+			if ctx.Err() == nil {
+				panic("trigger failed")
+			}
+		}
+	}
+
+	if s.goroutinePanicAfter > 0 {
+		trigger := t.Trigger
+		t.Trigger = func(ctx context.Context, output *ui.Output, pool *workers.PoolManager, opts options.RunOptions) {
+			goroutines.Go(ctx, "failing", func(ctx context.Context) {
+				select {
+				case <-time.After(s.goroutinePanicAfter):
+					panic("trigger failed")
+				case <-ctx.Done():
+				}
+			})
+
+			trigger(ctx, output, pool, opts)
+		}
+	}
 	return t
 }
 
+func (s *RunTestStage) a_trigger_that_panics_after(duration time.Duration) *RunTestStage {
+	s.triggerPanicAfter = duration
+	return s
+}
+
+func (s *RunTestStage) a_goroutine_of_the_trigger_that_panics_after(duration time.Duration) *RunTestStage {
+	s.goroutinePanicAfter = duration
+	return s
+}
+
+func (s *RunTestStage) a_scenario_tracking_the_iterations_in_progress_taking(duration time.Duration) *RunTestStage {
+	s.scenario = "scenario_tracking_the_iterations_in_progress"
+	s.f1.Add(s.scenario, func(scenarioT *f1_testing.T) f1_testing.RunFn {
+		scenarioT.Cleanup(s.scenarioCleanup)
+		scenarioT.Cleanup(func() {
+			s.iterationsInProgressAtTeardown.Store(s.iterationsInProgress.Load())
+		})
+
+		return func(*f1_testing.T) {
+			s.iterationsInProgress.Add(1)
+			defer s.iterationsInProgress.Add(-1)
+
+			time.Sleep(duration)
+		}
+	})
+	return s
+}
+
+func (s *RunTestStage) no_iteration_was_in_progress_at_teardown() *RunTestStage {
+	s.assert.Zero(s.iterationsInProgressAtTeardown.Load())
+	return s
+}
+
+func (s *RunTestStage) the_run_reports_the_panic() *RunTestStage {
+	s.require.NotNil(s.runResult)
+	s.assert.ErrorIs(s.runResult.Error(), run.ErrPanicked)
+	s.assert.ErrorContains(s.runResult.Error(), "trigger failed")
+	s.assert.True(s.runResult.Failed())
+
+	report := s.runResult.Report()
+	s.assert.True(report.Partial)
+	s.assert.Contains(report.PanicStack, "goroutine")
+	s.assert.Positive(report.IterationsStarted)
+	return s
+}
+
+func (s *RunTestStage) the_report_snapshot_file_has_the_partial_report() *RunTestStage {
+	data, err := os.ReadFile(s.reportSnapshotFile)
+	s.require.NoError(err)
+
+	report := run.Report{}
+	s.require.NoError(json.Unmarshal(data, &report))
+	s.assert.True(report.Partial)
+	s.assert.NotEmpty(report.PanicStack)
+	s.assert.Equal(s.runResult.Report().IterationsStarted, report.IterationsStarted)
+	return s
+}
+
 func (s *RunTestStage) setup_teardown_is_called_within(duration time.Duration) *RunTestStage {
 	s.setup_teardown_is_called()
 
@@ -578,6 +2521,203 @@ func (s *RunTestStage) a_scenario_where_iteration_n_takes_100ms(n uint32) *RunTe
 	return s
 }
 
+func (s *RunTestStage) a_scenario_where_each_iteration_times_stage(stage string) *RunTestStage {
+	s.scenario = "scenario_where_each_iteration_times_stage_" + stage
+	s.f1.Add(s.scenario, func(scenarioT *f1_testing.T) f1_testing.RunFn {
+		scenarioT.Cleanup(s.scenarioCleanup)
+
+		return func(iterationT *f1_testing.T) {
+			iterationT.Cleanup(s.iterationCleanup)
+
+			iterationT.Time(stage, func() {
+				s.runCount.Add(1)
+			})
+		}
+	})
+	return s
+}
+
+func (s *RunTestStage) a_scenario_with_operations(names ...string) *RunTestStage {
+	s.scenario = "scenario_with_operations"
+	s.f1.Add(s.scenario, func(scenarioT *f1_testing.T) f1_testing.RunFn {
+		scenarioT.Cleanup(s.scenarioCleanup)
+
+		operations := f1.Operations{}
+		for _, name := range names {
+			count := &atomic.Uint32{}
+			s.operationRunCounts.Store(name, count)
+			operations[name] = func(iterationT *f1_testing.T) {
+				iterationT.Cleanup(s.iterationCleanup)
+				s.runCount.Add(1)
+				count.Add(1)
+
+				inProgress := s.operationsInProgress.Add(1)
+				defer s.operationsInProgress.Add(-1)
+				for maxInProgress := s.maxOperationsInProgress.Load(); inProgress > maxInProgress; {
+					if s.maxOperationsInProgress.CompareAndSwap(maxInProgress, inProgress) {
+						break
+					}
+					maxInProgress = s.maxOperationsInProgress.Load()
+				}
+
+				time.Sleep(s.operationDuration)
+			}
+		}
+
+		return operations.Run
+	})
+	return s
+}
+
+func (s *RunTestStage) a_scenario_with_a_rate_drop_hook() *RunTestStage {
+	s.scenario = "scenario_with_a_rate_drop_hook"
+	s.f1.Add(s.scenario, func(scenarioT *f1_testing.T) f1_testing.RunFn {
+		scenarioT.OnRateDrop(func(drop f1_testing.RateDrop) {
+			s.rateDropsMu.Lock()
+			defer s.rateDropsMu.Unlock()
+			s.rateDrops = append(s.rateDrops, drop)
+		})
+
+		return func(*f1_testing.T) {
+			s.runCount.Add(1)
+		}
+	})
+	return s
+}
+
+func (s *RunTestStage) the_rate_drop_hook_is_called_once_at_stage(stage string) *RunTestStage {
+	s.rateDropsMu.Lock()
+	defer s.rateDropsMu.Unlock()
+
+	s.require.Len(s.rateDrops, 1)
+	s.assert.Equal(stage, s.rateDrops[0].Stage)
+	s.assert.InDelta(200, s.rateDrops[0].From, 20)
+	s.assert.LessOrEqual(s.rateDrops[0].To, 100.0)
+	return s
+}
+
+func (s *RunTestStage) at_most_n_operations_were_in_progress_at_once(n int32) *RunTestStage {
+	s.assert.Positive(s.maxOperationsInProgress.Load())
+	s.assert.LessOrEqual(s.maxOperationsInProgress.Load(), n)
+	return s
+}
+
+func (s *RunTestStage) each_operation_takes(duration time.Duration) *RunTestStage {
+	s.operationDuration = duration
+	return s
+}
+
+func (s *RunTestStage) the_iterations_of_the_operation_were_dropped_by_its_quota(
+	name string, workers int,
+) *RunTestStage {
+	report := s.runResult.Report()
+	s.require.Len(report.QuotaDrops, 1)
+	s.assert.Equal(name, report.QuotaDrops[0].Operation)
+	s.assert.Equal(workers, report.QuotaDrops[0].Workers)
+	s.assert.Positive(report.QuotaDrops[0].Count)
+	s.assert.LessOrEqual(report.QuotaDrops[0].Count, report.DroppedIterationCount)
+	s.assert.Contains(s.stdout.String(), `msg="Dropped iterations by concurrency quota" scenario=`+s.scenario+
+		` operation=`+name+` workers=`+strconv.Itoa(workers))
+	return s
+}
+
+func (s *RunTestStage) the_operation_ran_at_most_n_times(name string, n uint32) *RunTestStage {
+	count, ok := s.operationRunCounts.Load(name)
+	s.require.True(ok, "operation %s not defined", name)
+	s.assert.LessOrEqual(count.(*atomic.Uint32).Load(), n, "number of iterations of operation %s", name)
+	return s
+}
+
+func (s *RunTestStage) the_operation_should_have_run_n_times(name string, n uint32) *RunTestStage {
+	count, ok := s.operationRunCounts.Load(name)
+	s.require.True(ok, "operation %s not defined", name)
+	s.assert.Equal(n, count.(*atomic.Uint32).Load(), "number of iterations of operation %s", name)
+	return s
+}
+
+func (s *RunTestStage) the_iteration_metric_has_stage(stage string) *RunTestStage {
+	err := retry(func() error {
+		metricFamily := s.metricData.GetMetricFamily(iterationMetricFamily)
+		s.require.NotNil(metricFamily, "metric family %s not found", iterationMetricFamily)
+		for _, metric := range metricFamily.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == metrics.StageLabel && label.GetValue() == stage {
+					return nil
+				}
+			}
+		}
+		return fmt.Errorf("no %s metric found with stage %s", iterationMetricFamily, stage)
+	}, 10, 50*time.Millisecond)
+	s.require.NoError(err)
+	return s
+}
+
+func (s *RunTestStage) a_scenario_where_each_iteration_labels_one_of_n_endpoints(n uint32) *RunTestStage {
+	s.scenario = "scenario_where_each_iteration_labels_an_endpoint"
+	s.f1.Add(s.scenario, func(_ *f1_testing.T) f1_testing.RunFn {
+		s.runCount.Store(0)
+		return func(t *f1_testing.T) {
+			endpoint := s.runCount.Add(1) % n
+			t.WithLabel("endpoint", fmt.Sprintf("/endpoint-%d", endpoint))
+		}
+	})
+	return s
+}
+
+func (s *RunTestStage) a_scenario_where_each_iteration_sets_one_of_n_labels(n uint32) *RunTestStage {
+	s.scenario = "scenario_where_each_iteration_sets_a_label"
+	s.f1.Add(s.scenario, func(_ *f1_testing.T) f1_testing.RunFn {
+		s.runCount.Store(0)
+		return func(t *f1_testing.T) {
+			label := s.runCount.Add(1) % n
+			t.WithLabel(fmt.Sprintf("label-%d", label), "value")
+		}
+	})
+	return s
+}
+
+func (s *RunTestStage) the_label_metric_has_n_labels(n int) *RunTestStage {
+	err := retry(func() error {
+		metricFamily := s.metricData.GetMetricFamily(labelMetricFamily)
+		s.require.NotNil(metricFamily, "metric family %s not found", labelMetricFamily)
+		labels := map[string]struct{}{}
+		for _, metric := range metricFamily.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == metrics.LabelNameLabel {
+					labels[label.GetValue()] = struct{}{}
+				}
+			}
+		}
+		if len(labels) == n {
+			return nil
+		}
+		return fmt.Errorf("expected %d labels, got %d", n, len(labels))
+	}, 10, 50*time.Millisecond)
+	s.require.NoError(err)
+	return s
+}
+
+func (s *RunTestStage) the_label_metric_has_n_values(n int) *RunTestStage {
+	err := retry(func() error {
+		metricFamily := s.metricData.GetMetricFamily(labelMetricFamily)
+		s.require.NotNil(metricFamily, "metric family %s not found", labelMetricFamily)
+		values := map[string]struct{}{}
+		for _, metric := range metricFamily.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == metrics.LabelValueLabel {
+					values[label.GetValue()] = struct{}{}
+				}
+			}
+		}
+		if len(values) == n {
+			return nil
+		}
+		return fmt.Errorf("expected %d label values, got %d", n, len(values))
+	}, 10, 50*time.Millisecond)
+	s.require.NoError(err)
+	return s
+}
+
 func (s *RunTestStage) the_100th_percentile_is_slow() *RunTestStage {
 	s.assert.GreaterOrEqual(s.metricData.GetIterationDuration(s.scenario, 1.0), float64(100*time.Millisecond))
 	return s
@@ -656,6 +2796,80 @@ func (s *RunTestStage) verbose_flag_is(verbose bool) *RunTestStage {
 	return s
 }
 
+func (s *RunTestStage) a_log_file() *RunTestStage {
+	s.settings.Log.FilePath = filepath.Join(s.t.TempDir(), "run.log")
+	return s
+}
+
+func (s *RunTestStage) a_scenario_logging_its_iterations() *RunTestStage {
+	s.scenario = "scenario_logging_its_iterations"
+	s.f1.Add(s.scenario, func(*f1_testing.T) f1_testing.RunFn {
+		return func(iterationT *f1_testing.T) {
+			iterationT.Logf("logged iteration %s", iterationT.Iteration)
+			time.Sleep(10 * time.Millisecond)
+		}
+	})
+	return s
+}
+
+func (s *RunTestStage) the_iterations_were_logged_to_the_console_and_then_to_the_log_file() *RunTestStage {
+	s.require.NotEmpty(s.runResult.LogFilePath)
+	logFile, err := os.ReadFile(s.runResult.LogFilePath)
+	s.require.NoError(err)
+
+	loggedIterations := func(logs string) []int {
+		var iterations []int
+		for _, match := range regexp.MustCompile(`logged iteration (\d+)`).FindAllStringSubmatch(logs, -1) {
+			iteration, err := strconv.Atoi(match[1])
+			s.require.NoError(err)
+			iterations = append(iterations, iteration)
+		}
+		return iterations
+	}
+	console := loggedIterations(s.stdout.String())
+	file := loggedIterations(string(logFile))
+	s.require.NotEmpty(console)
+	s.require.NotEmpty(file)
+	s.assert.Less(slices.Max(console), slices.Min(file))
+	s.assert.Contains(s.stdout.String(), "Saving logs to "+s.settings.Log.FilePath)
+	return s
+}
+
+func (s *RunTestStage) the_patterns_are_redacted(patterns ...string) *RunTestStage {
+	s.redact = patterns
+	return s
+}
+
+func (s *RunTestStage) a_scenario_failing_with_card_numbers() *RunTestStage {
+	s.scenario = "scenario_failing_with_card_numbers"
+	s.f1.Add(s.scenario, func(*f1_testing.T) f1_testing.RunFn {
+		return func(iterationT *f1_testing.T) {
+			iterationT.Logf("charging card=4111111111111111 of jane@example.com")
+			iterationT.Errorf("payment declined for card=4111111111111111")
+		}
+	})
+	return s
+}
+
+func (s *RunTestStage) the_card_numbers_are_redacted_from_the_logs_and_the_report() *RunTestStage {
+	s.require.NotEmpty(s.runResult.LogFilePath)
+	logFile, err := os.ReadFile(s.runResult.LogFilePath)
+	s.require.NoError(err)
+	s.assert.Contains(string(logFile), "charging card=[REDACTED] of [REDACTED]")
+	s.assert.Contains(string(logFile), "payment declined for card=[REDACTED]")
+
+	report, err := json.Marshal(s.runResult.Report())
+	s.require.NoError(err)
+	s.require.NotEmpty(s.runResult.Report().FirstFailures)
+	s.assert.Equal("payment declined for card=[REDACTED]", s.runResult.Report().FirstFailures[0].Error)
+
+	for _, shared := range []string{string(logFile), string(report), s.runResult.Failures().Render()} {
+		s.assert.NotContains(shared, "4111111111111111")
+		s.assert.NotContains(shared, "jane@example.com")
+	}
+	return s
+}
+
 func (s *RunTestStage) json_logging_is_enabled() *RunTestStage {
 	s.settings.Log.Format = "json"
 	return s
@@ -806,3 +3020,130 @@ func (s *syncWriter) String() string {
 
 	return s.writer.String()
 }
+
+func (s *RunTestStage) an_output_format(format string) *RunTestStage {
+	s.outputFormat = format
+	return s
+}
+
+func (s *RunTestStage) an_output_file() *RunTestStage {
+	s.outputFile = filepath.Join(s.t.TempDir(), "result.json")
+	return s
+}
+
+func (s *RunTestStage) a_junit_report() *RunTestStage {
+	s.junitReport = filepath.Join(s.t.TempDir(), "junit.xml")
+	return s
+}
+
+// the_junit_report_is_written checks that the JUnit report has the setup, iterations and teardown of
+// the run as test cases, with the failed and skipped ones.
+func (s *RunTestStage) the_junit_report_is_written(failed, skipped []string) *RunTestStage {
+	data, err := os.ReadFile(s.junitReport)
+	s.require.NoError(err)
+	s.assert.Contains(s.stdout.String(), "JUnit report written to "+s.junitReport)
+
+	report := struct {
+		Suites []struct {
+			Name      string `xml:"name,attr"`
+			Tests     int    `xml:"tests,attr"`
+			Failures  int    `xml:"failures,attr"`
+			Skipped   int    `xml:"skipped,attr"`
+			TestCases []struct {
+				Name      string    `xml:"name,attr"`
+				Failure   *struct{} `xml:"failure"`
+				Skipped   *struct{} `xml:"skipped"`
+				SystemOut string    `xml:"system-out"`
+			} `xml:"testcase"`
+		} `xml:"testsuite"`
+	}{}
+	s.require.NoError(xml.Unmarshal(data, &report))
+	s.require.Len(report.Suites, 1)
+
+	suite := report.Suites[0]
+	s.assert.Equal(s.scenario, suite.Name)
+	s.assert.Equal(3, suite.Tests)
+	s.assert.Equal(len(failed), suite.Failures)
+	s.assert.Equal(len(skipped), suite.Skipped)
+
+	var names, actualFailed, actualSkipped []string
+	for _, testCase := range suite.TestCases {
+		names = append(names, testCase.Name)
+		if testCase.Failure != nil {
+			actualFailed = append(actualFailed, testCase.Name)
+		}
+		if testCase.Skipped != nil {
+			actualSkipped = append(actualSkipped, testCase.Name)
+		} else if testCase.Name == run.JUnitIterations {
+			s.assert.Contains(testCase.SystemOut, "iterations: ")
+		}
+	}
+	s.assert.Equal([]string{run.JUnitSetup, run.JUnitIterations, run.JUnitTeardown}, names)
+	s.assert.Equal(failed, actualFailed)
+	s.assert.Equal(skipped, actualSkipped)
+	return s
+}
+
+func (s *RunTestStage) an_sla_of(sla string) *RunTestStage {
+	s.slaFile = filepath.Join(s.t.TempDir(), "sla.yaml")
+	s.require.NoError(os.WriteFile(s.slaFile, []byte(sla), 0o600))
+	return s
+}
+
+// the_sla_compliance_is_reported checks that the operations of the SLA are evaluated in the report
+// and the logged summary of the run, and that the run fails with the violations of the operations.
+func (s *RunTestStage) the_sla_compliance_is_reported(violations map[string][]string) *RunTestStage {
+	actual := map[string][]string{}
+	for _, operation := range s.runResult.Report().SLA {
+		actual[operation.Operation] = operation.Violations
+		s.assert.Equal(len(operation.Violations) == 0, operation.Compliant, operation.Operation)
+
+		message := "SLA met"
+		if !operation.Compliant {
+			message = "SLA breached"
+		}
+		s.assert.Regexp(`msg="`+message+`" .*operation=`+operation.Operation, s.stdout.String())
+	}
+	s.assert.Equal(violations, actual)
+
+	var breaches []string
+	for _, operation := range s.runResult.Report().SLA {
+		for _, violation := range operation.Violations {
+			breaches = append(breaches, operation.Operation+" "+violation)
+		}
+	}
+	if len(breaches) == 0 {
+		s.assert.False(s.runResult.Failed())
+		return s
+	}
+
+	s.assert.True(s.runResult.Failed())
+	s.require.ErrorIs(s.runResult.Error(), run.ErrSLABreached)
+	s.assert.ErrorContains(s.runResult.Error(), "sla breached: "+strings.Join(breaches, ", "))
+	return s
+}
+
+// the_json_result_is_written checks that the json result of the run is written to the output file,
+// or is the only output on stdout with the summary on stderr.
+func (s *RunTestStage) the_json_result_is_written() *RunTestStage {
+	data := []byte(s.stdout.String())
+	summary := s.stderr.String()
+	if s.outputFile != "" {
+		var err error
+		data, err = os.ReadFile(s.outputFile)
+		s.require.NoError(err)
+		summary = s.stdout.String()
+		s.assert.Contains(summary, "Result written to "+s.outputFile)
+	}
+	s.assert.Contains(summary, "Load Test Passed")
+
+	expected, err := json.Marshal(s.runResult.Report())
+	s.require.NoError(err)
+	s.assert.JSONEq(string(expected), string(data))
+
+	report := run.Report{}
+	s.require.NoError(json.Unmarshal(data, &report))
+	s.assert.Equal(uint64(25), report.IterationsStarted)
+	s.assert.Positive(report.SuccessfulIterationDurations.P95)
+	return s
+}
@@ -1,50 +1,121 @@
 package run
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"os"
+	"sync"
+	"sync/atomic"
 
 	"github.com/form3tech-oss/f1/v2/internal/log"
+	"github.com/form3tech-oss/f1/v2/internal/platform"
+	"github.com/form3tech-oss/f1/v2/internal/redact"
 	"github.com/form3tech-oss/f1/v2/internal/ui"
 )
 
+// ScenarioLogger logs the scenario of a run either to the console, when verbose, or to a log file.
+// Logging can be switched between both while the run is in progress, see SetVerbose.
 type ScenarioLogger struct {
 	Logger *slog.Logger
 	output *ui.Output
+	// redactor redacts the records of Logger, whether they are written to the console or the log file
+	redactor *redact.Redactor
 
-	logFile *os.File
+	// toFile switches the records of Logger to the log file, rather than the console
+	toFile      atomic.Bool
+	logFile     atomic.Pointer[os.File]
+	logFilePath string
+	// logFileOpened is set once logs were written to the log file, see FilePath
+	logFileOpened atomic.Bool
+	// logFileMu serialises the opening and closing of the log file
+	logFileMu sync.Mutex
 }
 
-func NewScenarioLogger(output *ui.Output) *ScenarioLogger {
+func NewScenarioLogger(output *ui.Output, redactor *redact.Redactor) *ScenarioLogger {
 	return &ScenarioLogger{
-		output: output,
+		output:   output,
+		redactor: redactor,
 	}
 }
 
 func (s *ScenarioLogger) Open(logFilePath string, logConfig *log.Config, runName string, logToFile bool) string {
+	s.logFilePath = logFilePath
+	s.Logger = slog.New(s.redactor.Handler(&switchHandler{
+		toFile:  &s.toFile,
+		console: s.output.Logger.Handler(),
+		// records are written to the log file once it is open, see Write
+		file: log.NewLogger(s, logConfig).With(log.ScenarioAttr(runName)).Handler(),
+	}))
 	if !logToFile {
-		s.Logger = s.output.Logger
 		return ""
 	}
 
-	logFile, err := s.openLogFile(logFilePath)
-	if err != nil {
-		s.Logger = s.output.Logger
+	if err := s.openLogFile(); err != nil {
 		s.output.Display(ui.ErrorMessage{Message: "Error opening log file. Using default logger", Error: err})
 		return ""
 	}
-
-	s.Logger = log.NewLogger(logFile, logConfig).With(log.ScenarioAttr(runName))
-	s.logFile = logFile
+	s.toFile.Store(true)
 	s.output.Display(ui.InfoMessage{Message: "Saving logs to " + logFilePath})
 
 	return logFilePath
 }
 
+// SetVerbose switches the logs of the scenario to the console when verbose, or to the log file,
+// opening it the first time logs are switched to it.
+func (s *ScenarioLogger) SetVerbose(verbose bool) error {
+	if verbose {
+		if s.toFile.Swap(false) {
+			s.output.Display(ui.InfoMessage{Message: "Logging to the console"})
+		}
+		return nil
+	}
+
+	if err := s.openLogFile(); err != nil {
+		return err
+	}
+	if !s.toFile.Swap(true) {
+		s.output.Display(ui.InfoMessage{Message: "Saving logs to " + s.logFilePath})
+	}
+
+	return nil
+}
+
+// Verbose returns true while the logs of the scenario are written to the console.
+func (s *ScenarioLogger) Verbose() bool {
+	return !s.toFile.Load()
+}
+
+// FilePath returns the path of the log file, or an empty string if logs were never switched to it.
+func (s *ScenarioLogger) FilePath() string {
+	if !s.logFileOpened.Load() {
+		return ""
+	}
+
+	return s.logFilePath
+}
+
+// Write writes the records of the file logger to the log file, or discards them until it is open.
+func (s *ScenarioLogger) Write(p []byte) (int, error) {
+	logFile := s.logFile.Load()
+	if logFile == nil {
+		return len(p), nil
+	}
+
+	n, err := logFile.Write(p)
+	if err != nil {
+		return n, fmt.Errorf("writing log file: %w", err)
+	}
+
+	return n, nil
+}
+
 func (s *ScenarioLogger) Close() error {
-	if s.logFile != nil {
-		if err := s.logFile.Close(); err != nil {
+	s.logFileMu.Lock()
+	defer s.logFileMu.Unlock()
+
+	if logFile := s.logFile.Swap(nil); logFile != nil {
+		if err := logFile.Close(); err != nil {
 			return fmt.Errorf("closing log file: %w", err)
 		}
 	}
@@ -52,11 +123,59 @@ func (s *ScenarioLogger) Close() error {
 	return nil
 }
 
-func (*ScenarioLogger) openLogFile(path string) (*os.File, error) {
-	logFile, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+func (s *ScenarioLogger) openLogFile() error {
+	s.logFileMu.Lock()
+	defer s.logFileMu.Unlock()
+
+	if s.logFile.Load() != nil {
+		return nil
+	}
+	if !platform.Current().LogFiles {
+		return fmt.Errorf("opening log file '%s': %w", s.logFilePath, platform.ErrUnsupported)
+	}
+
+	logFile, err := os.OpenFile(s.logFilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
 	if err != nil {
-		return nil, fmt.Errorf("opening log file '%s': %w", path, err)
+		return fmt.Errorf("opening log file '%s': %w", s.logFilePath, err)
+	}
+	s.logFile.Store(logFile)
+	s.logFileOpened.Store(true)
+
+	return nil
+}
+
+// switchHandler sends records to the console or to the log file, as switched by toFile. Handlers
+// derived with attributes or groups switch together with the handler they are derived from.
+type switchHandler struct {
+	toFile  *atomic.Bool
+	console slog.Handler
+	file    slog.Handler
+}
+
+func (h *switchHandler) current() slog.Handler {
+	if h.toFile.Load() {
+		return h.file
+	}
+
+	return h.console
+}
+
+func (h *switchHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.current().Enabled(ctx, level)
+}
+
+func (h *switchHandler) Handle(ctx context.Context, record slog.Record) error {
+	if err := h.current().Handle(ctx, record); err != nil {
+		return fmt.Errorf("handling log record: %w", err)
 	}
 
-	return logFile, nil
+	return nil
+}
+
+func (h *switchHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &switchHandler{toFile: h.toFile, console: h.console.WithAttrs(attrs), file: h.file.WithAttrs(attrs)}
+}
+
+func (h *switchHandler) WithGroup(name string) slog.Handler {
+	return &switchHandler{toFile: h.toFile, console: h.console.WithGroup(name), file: h.file.WithGroup(name)}
 }
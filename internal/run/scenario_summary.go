@@ -0,0 +1,33 @@
+package run
+
+import (
+	"slices"
+
+	"github.com/form3tech-oss/f1/v2/internal/run/views"
+)
+
+// RecordScenarioSummary records the summary added by the scenario, once it has been torn down.
+func (r *Result) RecordScenarioSummary(lines []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.scenarioSummary = slices.Clone(lines)
+	for i, line := range r.scenarioSummary {
+		r.scenarioSummary[i] = r.redactor.String(line)
+	}
+}
+
+func (r *Result) hasScenarioSummary() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.scenarioSummary) > 0
+}
+
+// ScenarioSummary returns the summary of the side effects of the run added by the scenario.
+func (r *Result) ScenarioSummary() *views.ViewContext[views.ScenarioSummaryData] {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.views.ScenarioSummary(views.ScenarioSummaryData{Lines: slices.Clone(r.scenarioSummary)})
+}
@@ -0,0 +1,215 @@
+package run
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/form3tech-oss/f1/v2/internal/progress"
+	"github.com/form3tech-oss/f1/v2/internal/run/views"
+)
+
+// ErrSLABreached fails runs whose operations didn't meet their objectives in the SLA file set by
+// the --sla flag.
+var ErrSLABreached = errors.New("sla breached")
+
+var (
+	errNoSLAOperations = errors.New("no operations")
+	errNoSLAObjectives = errors.New("no objectives")
+)
+
+// SLA is a service level agreement, read from a yaml file, with the objectives of each operation of
+// a scenario, the stages its iterations time with testing.T.Time, such as the operations of a Mix:
+//
+//	operations:
+//	  create:
+//	    max-p99: 500ms
+//	    max-error-rate: 1
+//	  list:
+//	    max-p95: 200ms
+type SLA struct {
+	Operations map[string]SLO `yaml:"operations"`
+}
+
+func ReadSLA(path string) (*SLA, error) {
+	sla := &SLA{}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading sla file '%s': %w", path, err)
+	}
+
+	// unknown keys are rejected, so that an objective with a typo isn't silently left out
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(sla); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parsing sla file '%s': %w", path, err)
+	}
+
+	if err := sla.validate(); err != nil {
+		return nil, fmt.Errorf("invalid sla file '%s': %w", path, err)
+	}
+
+	return sla, nil
+}
+
+func (s *SLA) validate() error {
+	if len(s.Operations) == 0 {
+		return errNoSLAOperations
+	}
+
+	for operation, slo := range s.Operations {
+		if !slo.isSet() {
+			return fmt.Errorf("%w for operation %s", errNoSLAObjectives, operation)
+		}
+	}
+
+	return nil
+}
+
+// OperationCompliance is an operation of a run evaluated against its objectives in the SLA of the
+// run. Operations which no iteration timed don't meet their objectives.
+type OperationCompliance struct {
+	Operation                    string          `json:"operation"`
+	SuccessfulIterationDurations DurationsReport `json:"successful_iteration_durations"`
+	FailedIterationDurations     DurationsReport `json:"failed_iteration_durations"`
+	// SLO are the objectives of the operation
+	SLO        SLO      `json:"slo"`
+	Compliant  bool     `json:"compliant"`
+	Violations []string `json:"violations,omitempty"`
+}
+
+// report returns the operation as the report of a run, to evaluate the objectives of the operation.
+func (c OperationCompliance) report() Report {
+	return Report{
+		SuccessfulIterationDurations: c.SuccessfulIterationDurations,
+		FailedIterationDurations:     c.FailedIterationDurations,
+		IterationsStarted:            c.SuccessfulIterationDurations.Count + c.FailedIterationDurations.Count,
+	}
+}
+
+// evaluate sets the objectives the operation didn't meet.
+func (c *OperationCompliance) evaluate() {
+	report := c.report()
+	if report.IterationsStarted == 0 {
+		c.Violations = []string{"not run"}
+	} else {
+		c.Violations = c.SLO.Violations(report)
+	}
+	c.Compliant = len(c.Violations) == 0
+}
+
+// slaCompliance evaluates the operations of the latest snapshot against their objectives in the SLA
+// of the run, by the name of the operation.
+func (r *Result) slaCompliance() []OperationCompliance {
+	if r.sla == nil {
+		return nil
+	}
+
+	durations := make(map[string]progress.OperationDurations, len(r.snapshot.Operations))
+	for _, operation := range r.snapshot.Operations {
+		durations[operation.Operation] = operation
+	}
+
+	compliance := make([]OperationCompliance, 0, len(r.sla.Operations))
+	for operation, slo := range r.sla.Operations {
+		operationCompliance := OperationCompliance{
+			Operation:                    operation,
+			SuccessfulIterationDurations: newDurationsReport(durations[operation].SuccessfulIterationDurations),
+			FailedIterationDurations:     newDurationsReport(durations[operation].FailedIterationDurations),
+			SLO:                          slo,
+		}
+		operationCompliance.evaluate()
+		compliance = append(compliance, operationCompliance)
+	}
+	slices.SortFunc(compliance, func(a, b OperationCompliance) int {
+		return strings.Compare(a.Operation, b.Operation)
+	})
+
+	return compliance
+}
+
+// SLACompliance returns the operations of the run evaluated against their objectives in the SLA of
+// the run, if it has one.
+func (r *Result) SLACompliance() []OperationCompliance {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.slaCompliance()
+}
+
+func (r *Result) SLA() *views.ViewContext[views.SLAData] {
+	data := views.SLAData{}
+	for _, operation := range r.SLACompliance() {
+		report := operation.report()
+		operationData := views.OperationCompliance{
+			Operation:  operation.Operation,
+			Iterations: report.IterationsStarted,
+			P95:        report.SuccessfulIterationDurations.P95,
+			P99:        report.SuccessfulIterationDurations.P99,
+			ErrorRate:  report.ErrorRate(),
+			MaxP95:     operation.SLO.MaxP95,
+			MaxP99:     operation.SLO.MaxP99,
+			Violations: operation.Violations,
+		}
+		if operation.SLO.MaxErrorRate != nil {
+			operationData.MaxErrorRate = *operation.SLO.MaxErrorRate
+			operationData.HasMaxErrorRate = true
+		}
+		data.Operations = append(data.Operations, operationData)
+	}
+
+	return r.views.SLA(data)
+}
+
+func (r *Result) hasSLA() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.sla != nil
+}
+
+// combineSLACompliance merges the operations of runs with the same name, evaluating them again
+// against their objectives.
+func combineSLACompliance(a, b []OperationCompliance) []OperationCompliance {
+	combined := slices.Clone(a)
+
+	for _, operation := range b {
+		i := slices.IndexFunc(combined, func(existing OperationCompliance) bool {
+			return existing.Operation == operation.Operation
+		})
+		if i < 0 {
+			combined = append(combined, operation)
+			continue
+		}
+
+		existing := &combined[i]
+		existing.SuccessfulIterationDurations = existing.SuccessfulIterationDurations.combine(
+			operation.SuccessfulIterationDurations)
+		existing.FailedIterationDurations = existing.FailedIterationDurations.combine(
+			operation.FailedIterationDurations)
+		existing.evaluate()
+	}
+
+	return combined
+}
+
+// checkSLA fails the run if any of its operations didn't meet its objectives in the SLA of the run.
+func (r *Run) checkSLA() {
+	var breaches []string
+	for _, operation := range r.result.SLACompliance() {
+		for _, violation := range operation.Violations {
+			breaches = append(breaches, operation.Operation+" "+violation)
+		}
+	}
+
+	if len(breaches) > 0 {
+		r.result.AddError(fmt.Errorf("%w: %s", ErrSLABreached, strings.Join(breaches, ", ")))
+	}
+}
@@ -0,0 +1,80 @@
+package run_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/run"
+)
+
+func TestReadSLA(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name, content, expectedError string
+		expected                     *run.SLA
+	}{
+		{
+			name: "valid",
+			content: `operations:
+  create:
+    max-p99: 500ms
+  list:
+    max-p95: 200ms
+`,
+			expected: &run.SLA{Operations: map[string]run.SLO{
+				"create": {MaxP99: 500 * time.Millisecond},
+				"list":   {MaxP95: 200 * time.Millisecond},
+			}},
+		},
+		{
+			name: "unknown objective",
+			content: `operations:
+  create:
+    max-p99: 500ms
+    max-p999: 1s
+`,
+			expectedError: "field max-p999 not found",
+		},
+		{
+			name: "unknown key",
+			content: `operations:
+  create:
+    max-p99: 500ms
+error-budget: 1
+`,
+			expectedError: "field error-budget not found",
+		},
+		{
+			name:          "empty",
+			expectedError: "no operations",
+		},
+		{
+			name: "no objectives",
+			content: `operations:
+  create: {}
+`,
+			expectedError: "no objectives for operation create",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "sla.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(test.content), 0o600))
+
+			sla, err := run.ReadSLA(path)
+			if test.expectedError != "" {
+				require.ErrorContains(t, err, test.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expected, sla)
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package run
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/options"
+)
+
+// SLO are the service level objectives of a run, checked by campaigns once a run completes and by
+// runs while they are in progress, when they are set with the --slo flags.
+type SLO struct {
+	// MaxErrorRate is the maximum percentage of started iterations which failed
+	MaxErrorRate *float64      `json:"max_error_rate,omitempty" yaml:"max-error-rate"`
+	MaxP95       time.Duration `json:"max_p95,omitempty"        yaml:"max-p95"`
+	MaxP99       time.Duration `json:"max_p99,omitempty"        yaml:"max-p99"`
+}
+
+// newSLO returns the objectives set by the --slo flags of a run.
+func newSLO(options options.RunOptions) SLO {
+	return SLO{
+		MaxErrorRate: options.SLOMaxErrorRate,
+		MaxP95:       options.SLOMaxP95,
+		MaxP99:       options.SLOMaxP99,
+	}
+}
+
+// Violations returns a description of each objective the run did not meet.
+func (s SLO) Violations(report Report) []string {
+	var violations []string
+
+	p95 := report.SuccessfulIterationDurations.P95
+	if s.MaxP95 > 0 && p95 > s.MaxP95 {
+		violations = append(violations, fmt.Sprintf("p95 %s above %s", p95, s.MaxP95))
+	}
+
+	p99 := report.SuccessfulIterationDurations.P99
+	if s.MaxP99 > 0 && p99 > s.MaxP99 {
+		violations = append(violations, fmt.Sprintf("p99 %s above %s", p99, s.MaxP99))
+	}
+
+	if s.MaxErrorRate != nil && report.ErrorRate() > *s.MaxErrorRate {
+		violations = append(violations,
+			fmt.Sprintf("error rate %0.2f%% above %0.2f%%", report.ErrorRate(), *s.MaxErrorRate))
+	}
+
+	return violations
+}
+
+func (s SLO) isSet() bool {
+	return s.MaxErrorRate != nil || s.MaxP95 > 0 || s.MaxP99 > 0
+}
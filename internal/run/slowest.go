@@ -0,0 +1,82 @@
+package run
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/progress"
+	"github.com/form3tech-oss/f1/v2/internal/run/views"
+)
+
+// SlowIteration is one of the slowest iterations of the run, to investigate outliers.
+type SlowIteration struct {
+	// Time is when the iteration started
+	Time time.Time `json:"time"`
+	// Iteration is the number of the iteration
+	Iteration string `json:"iteration"`
+	// Worker is the worker which ran the iteration
+	Worker   string        `json:"worker,omitempty"`
+	Duration time.Duration `json:"duration"`
+	// Offset is when the iteration started, from the start of the run
+	Offset time.Duration `json:"offset"`
+}
+
+// slowest returns the slowest iterations of the latest snapshot. The offsets of resumed runs
+// include the duration of the run before it was resumed, as for drops.
+func (r *Result) slowest() []SlowIteration {
+	if len(r.snapshot.Slowest) == 0 {
+		return nil
+	}
+
+	slowest := make([]SlowIteration, 0, len(r.snapshot.Slowest))
+	for _, iteration := range r.snapshot.Slowest {
+		slowest = append(slowest, SlowIteration{
+			Time:      iteration.Start,
+			Iteration: iteration.Iteration,
+			Worker:    iteration.Worker,
+			Duration:  iteration.Duration,
+			Offset:    r.runOptions.Elapsed + iteration.Start.Sub(r.startTime),
+		})
+	}
+
+	return slowest
+}
+
+func (r *Result) hasSlowest() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.snapshot.Slowest) > 0
+}
+
+// Slowest returns the slowest iterations of the run, for a quick look at the outliers.
+func (r *Result) Slowest() *views.ViewContext[views.SlowestData] {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var data views.SlowestData
+	for _, iteration := range r.slowest() {
+		data.Iterations = append(data.Iterations, views.SlowIteration{
+			Time:      iteration.Time,
+			Iteration: iteration.Iteration,
+			Worker:    iteration.Worker,
+			Duration:  iteration.Duration,
+			Offset:    iteration.Offset.Round(time.Millisecond),
+		})
+	}
+
+	return r.views.Slowest(data)
+}
+
+// combineSlowest keeps the slowest iterations of runs.
+func combineSlowest(a, b []SlowIteration) []SlowIteration {
+	slowest := slices.Concat(a, b)
+	if len(slowest) == 0 {
+		return nil
+	}
+
+	slices.SortStableFunc(slowest, func(a, b SlowIteration) int { return cmp.Compare(b.Duration, a.Duration) })
+
+	return slowest[:min(len(slowest), progress.SlowestKept)]
+}
@@ -0,0 +1,66 @@
+package run
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+)
+
+var (
+	// ErrStagesNotSkippable is returned when the trigger of the run can't skip or jump to its stages.
+	ErrStagesNotSkippable = errors.New("the stages of the trigger can't be skipped")
+	// ErrUnknownStage is returned when jumping to a stage the trigger doesn't have.
+	ErrUnknownStage = errors.New("unknown stage")
+	// ErrStageJumpPending is returned when a previous skip or jump hasn't been taken by the trigger yet.
+	ErrStageJumpPending = errors.New("a previous stage skip or jump is pending")
+	// ErrRunNotStarted is returned when skipping or jumping to a stage before the load of the run starts.
+	ErrRunNotStarted = errors.New("the run hasn't started")
+)
+
+// SkipStage stops the current stage of the trigger, continuing the run from the next stage. Once the
+// last stage is skipped, the run completes without waiting for its planned duration.
+func (r *Run) SkipStage() error {
+	return r.jumpToStage("")
+}
+
+// JumpToStage stops the current stage of the trigger, continuing the run from the named stage. The
+// run keeps its planned duration, so jumping back to an earlier stage is cut short by it.
+func (r *Run) JumpToStage(stage string) error {
+	if stage == "" {
+		return fmt.Errorf("%w: missing stage name", ErrUnknownStage)
+	}
+
+	return r.jumpToStage(stage)
+}
+
+func (r *Run) jumpToStage(stage string) error {
+	if len(r.trigger.Stages) == 0 {
+		return ErrStagesNotSkippable
+	}
+	if stage != "" && !slices.Contains(r.trigger.Stages, stage) {
+		return fmt.Errorf("%w %q, expected one of %q", ErrUnknownStage, stage, r.trigger.Stages)
+	}
+	poolManager := r.poolManager.Load()
+	if poolManager == nil {
+		return ErrRunNotStarted
+	}
+	if !poolManager.JumpToStage(stage) {
+		return ErrStageJumpPending
+	}
+	r.stagesJumped.Store(true)
+
+	current := r.Progress().Stage
+	if stage == "" {
+		r.tracer.Event("stage skipped", slog.String("stage", current))
+		r.output.Display(ui.InfoMessage{Message: fmt.Sprintf("Skipping %s", current)})
+		return nil
+	}
+
+	r.tracer.Event("stage jumped", slog.String("from", current), slog.String("to", stage))
+	r.output.Display(ui.InfoMessage{Message: fmt.Sprintf("Jumping from %s to %s", current, stage)})
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package run
+
+import "slices"
+
+// StageReport is the summary of the iterations started in a stage of a run, for triggers which
+// run in stages.
+type StageReport struct {
+	Stage                        string          `json:"stage"`
+	SuccessfulIterationDurations DurationsReport `json:"successful_iteration_durations"`
+	FailedIterationDurations     DurationsReport `json:"failed_iteration_durations"`
+	IterationsStarted            uint64          `json:"iterations_started"`
+	DroppedIterationCount        uint64          `json:"dropped_iteration_count"`
+	// SLOViolations are the objectives set by the --slo flags the stage did not meet
+	SLOViolations []string `json:"slo_violations,omitempty"`
+}
+
+// report returns the stage as the report of a run, to evaluate the objectives of the stage.
+func (s StageReport) report() Report {
+	return Report{
+		SuccessfulIterationDurations: s.SuccessfulIterationDurations,
+		FailedIterationDurations:     s.FailedIterationDurations,
+		IterationsStarted:            s.IterationsStarted,
+		DroppedIterationCount:        s.DroppedIterationCount,
+	}
+}
+
+// stages returns the stages of the latest snapshot, with their dropped iterations and the
+// objectives each of them did not meet.
+func (r *Result) stages(drops []Drop) []StageReport {
+	if len(r.snapshot.Stages) == 0 {
+		return nil
+	}
+
+	dropped := map[string]uint64{}
+	for _, drop := range drops {
+		dropped[drop.Stage] += drop.Count
+	}
+
+	slo := newSLO(r.runOptions)
+	stages := make([]StageReport, 0, len(r.snapshot.Stages))
+	for _, durations := range r.snapshot.Stages {
+		stage := StageReport{
+			Stage:                        durations.Stage,
+			SuccessfulIterationDurations: newDurationsReport(durations.SuccessfulIterationDurations),
+			FailedIterationDurations:     newDurationsReport(durations.FailedIterationDurations),
+			IterationsStarted: durations.SuccessfulIterationDurations.Count +
+				durations.FailedIterationDurations.Count,
+			DroppedIterationCount: dropped[durations.Stage],
+		}
+		stage.SLOViolations = slo.Violations(stage.report())
+		stages = append(stages, stage)
+	}
+
+	return stages
+}
+
+// combineStages merges the stages of runs with the same name, in the order they first appear in.
+// The objectives a stage did not meet in any of the runs are kept.
+func combineStages(a, b []StageReport) []StageReport {
+	combined := slices.Clone(a)
+
+	for _, stage := range b {
+		i := indexOfStage(combined, stage.Stage)
+		if i < 0 {
+			combined = append(combined, stage)
+			continue
+		}
+
+		existing := &combined[i]
+		existing.SuccessfulIterationDurations = existing.SuccessfulIterationDurations.combine(
+			stage.SuccessfulIterationDurations)
+		existing.FailedIterationDurations = existing.FailedIterationDurations.combine(stage.FailedIterationDurations)
+		existing.IterationsStarted += stage.IterationsStarted
+		existing.DroppedIterationCount += stage.DroppedIterationCount
+		existing.SLOViolations = slices.Concat(existing.SLOViolations, stage.SLOViolations)
+	}
+
+	return combined
+}
+
+func indexOfStage(stages []StageReport, name string) int {
+	for i, stage := range stages {
+		if stage.Stage == name {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,56 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/barrier"
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+)
+
+// ErrStartMissed fails runs whose --start-at passed before their setup completed, as they can't
+// start together with the processes they were synchronised with.
+var ErrStartMissed = errors.New("start time missed")
+
+// startTolerance is how late a synchronised run may start, for the time taken to receive the start
+// time from the barrier.
+const startTolerance = 100 * time.Millisecond
+
+// waitForStart waits after the setup until the run is due to start, at --start-at or when released
+// by the --sync-barrier, so that independent processes start triggering iterations together.
+func (r *Run) waitForStart(ctx context.Context) error {
+	startAt := r.options.StartAt
+	if r.options.SyncBarrier != "" {
+		r.output.Display(ui.InfoMessage{Message: "waiting at barrier " + barrier.URL(r.options.SyncBarrier)})
+
+		var err error
+		startAt, err = barrier.Wait(ctx, r.options.SyncBarrier)
+		if err != nil {
+			return fmt.Errorf("synchronising start: %w", err)
+		}
+	}
+	if startAt.IsZero() {
+		return nil
+	}
+
+	wait := time.Until(startAt)
+	if wait < -startTolerance {
+		return fmt.Errorf("%w: %s passed %s ago", ErrStartMissed, startAt.Format(time.RFC3339Nano), -wait)
+	}
+
+	r.output.Display(ui.InfoMessage{Message: "starting at " + startAt.Format(time.RFC3339Nano)})
+	r.tracer.Event("waiting for start")
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	case <-r.stopCh:
+	}
+
+	return nil
+}
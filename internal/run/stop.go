@@ -0,0 +1,127 @@
+package run
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+)
+
+const (
+	// EndlessDuration is the max duration of endless runs, which run until they are stopped.
+	EndlessDuration = 100 * 365 * 24 * time.Hour
+	// minSLOIterations avoids stopping runs on the objectives of their first few iterations
+	minSLOIterations = 100
+	// DefaultReportSnapshotInterval is how often the report of a run is written to the report
+	// snapshot file.
+	DefaultReportSnapshotInterval = time.Minute
+)
+
+// ErrSLOBreached fails runs which were stopped because they didn't meet the objectives set by the
+// --slo flags.
+var ErrSLOBreached = errors.New("slo breached")
+
+// Stop stops triggering new iterations, waits for the active iterations to complete and completes
+// the run as if its duration had elapsed. It can be called from any goroutine, such as the
+// handlers of the control API, and is a no-op once the run is stopping.
+func (r *Run) Stop() {
+	r.stop("Stop Requested")
+}
+
+func (r *Run) stop(reason string) {
+	r.stopOnce.Do(func() {
+		r.tracer.Event("run stopped", slog.String("reason", reason))
+		r.stopReason = reason
+		close(r.stopCh)
+	})
+}
+
+// stopping returns the reason the run was stopped for, or false if it wasn't stopped.
+func (r *Run) stopping() (string, bool) {
+	select {
+	case <-r.stopCh:
+		return r.stopReason, true
+	default:
+		return "", false
+	}
+}
+
+// checkStopConditions stops the run when it breaches its objectives, or when an endless run
+// exceeds its max failures. It is called on every progress update, once enough iterations
+// started for the objectives to be meaningful.
+func (r *Run) checkStopConditions() {
+	if _, stopping := r.stopping(); stopping {
+		return
+	}
+
+	slo := newSLO(r.options)
+	if slo.isSet() {
+		report := r.result.Report()
+		violations := slo.Violations(report)
+		if report.IterationsStarted >= minSLOIterations && len(violations) > 0 {
+			err := fmt.Errorf("%w: %s", ErrSLOBreached, strings.Join(violations, ", "))
+			r.result.AddError(err)
+			r.output.Display(ui.ErrorMessage{Message: "stopping the run", Error: err})
+			r.stop("SLO Breached")
+			return
+		}
+	}
+
+	if r.options.Endless && r.result.maxFailuresExceeded() {
+		r.stop("Max Failures Exceeded")
+	}
+}
+
+// writeReportSnapshots writes the report of the run to the report snapshot file periodically, so
+// that open-ended runs can be followed, and still be analysed if the process is killed.
+func (r *Run) writeReportSnapshots(ctx context.Context) {
+	if r.options.ReportSnapshotFile == "" {
+		return
+	}
+
+	ticker := time.NewTicker(r.options.ReportSnapshotInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.writeReportSnapshot(true)
+		}
+	}
+}
+
+// writeReportSnapshot writes the report of the run to the report snapshot file, partial while
+// the run is in progress.
+func (r *Run) writeReportSnapshot(partial bool) {
+	if r.options.ReportSnapshotFile == "" {
+		return
+	}
+
+	if err := r.result.writeReportSnapshot(r.options.ReportSnapshotFile, partial); err != nil {
+		r.output.Display(ui.ErrorMessage{Message: "unable to write the report snapshot", Error: err})
+	}
+}
+
+// writeReportSnapshot replaces the report at path, so that readers never see a partial report.
+func (r *Result) writeReportSnapshot(path string, partial bool) error {
+	report := r.Report()
+	report.Partial = partial
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("marshalling report: %w", err)
+	}
+
+	if err := replaceFile(path, data); err != nil {
+		return fmt.Errorf("writing report snapshot: %w", err)
+	}
+
+	return nil
+}
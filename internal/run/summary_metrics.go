@@ -0,0 +1,57 @@
+package run
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+)
+
+// summaryMetricsFileMode lets the textfile collector of node_exporter, which usually runs as
+// another user, read the summary metrics file.
+const summaryMetricsFileMode = 0o644
+
+// writeSummaryMetrics writes the metrics of the run to the summary metrics file option, in the
+// OpenMetrics or the classic Prometheus text format.
+func (r *Run) writeSummaryMetrics() {
+	if r.options.SummaryMetricsFile == "" {
+		return
+	}
+
+	if err := r.createSummaryMetricsFile(); err != nil {
+		r.output.Display(ui.ErrorMessage{Message: "unable to write the summary metrics", Error: err})
+		return
+	}
+
+	r.output.Display(ui.InfoMessage{Message: "Summary metrics written to " + r.options.SummaryMetricsFile})
+}
+
+// createSummaryMetricsFile writes the metrics to a temporary file first, so that collectors reading
+// the summary metrics file never read partial metrics.
+func (r *Run) createSummaryMetricsFile() error {
+	path := filepath.Clean(r.options.SummaryMetricsFile)
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return fmt.Errorf("creating summary metrics file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := r.metrics.WriteSummary(tmp, r.summaryMetricsFormat); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing summary metrics: %w", err)
+	}
+	if err := tmp.Chmod(summaryMetricsFileMode); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing summary metrics: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing summary metrics: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("replacing summary metrics file: %w", err)
+	}
+
+	return nil
+}
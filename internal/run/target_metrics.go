@@ -0,0 +1,61 @@
+package run
+
+import (
+	"context"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/run/views"
+	"github.com/form3tech-oss/f1/v2/internal/targetmetrics"
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+)
+
+const targetMetricsTimeout = 30 * time.Second
+
+type targetMetricsCapture struct {
+	prometheusURL string
+	queries       []targetmetrics.Query
+}
+
+// captureTargetMetrics queries the metrics of the target system over the run window, so that they
+// can be correlated with the iteration durations. A failure to capture them does not fail the run.
+func (r *Run) captureTargetMetrics(ctx context.Context) {
+	if r.targetMetrics == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, targetMetricsTimeout)
+	defer cancel()
+
+	r.result.mu.RLock()
+	start := r.result.startTime
+	r.result.mu.RUnlock()
+
+	metrics, err := targetmetrics.Capture(ctx, r.targetMetrics.prometheusURL, r.targetMetrics.queries, start, time.Now())
+	if err != nil {
+		r.output.Display(ui.ErrorMessage{Message: "unable to capture target metrics", Error: err})
+		return
+	}
+
+	r.result.RecordTargetMetrics(metrics)
+}
+
+func (r *Result) RecordTargetMetrics(metrics []targetmetrics.Metric) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.targetMetrics = metrics
+}
+
+func (r *Result) TargetMetrics() *views.ViewContext[views.TargetMetricsData] {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.views.TargetMetrics(views.TargetMetricsData{Metrics: r.targetMetrics})
+}
+
+func (r *Result) hasTargetMetrics() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.targetMetrics) > 0
+}
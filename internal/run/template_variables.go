@@ -0,0 +1,51 @@
+package run
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"strings"
+
+	"github.com/form3tech-oss/f1/v2/internal/options"
+	"github.com/form3tech-oss/f1/v2/internal/triggerflags"
+	"github.com/form3tech-oss/f1/v2/pkg/f1/scenarios"
+)
+
+var errInvalidTemplateVar = errors.New("invalid template variable")
+
+// templateVariables returns the variables of the run read by the templates of its output: its run
+// ID, which `f1 logs` finds the log file of the run by, its scenario and the annotations of the
+// scenario, with the environment it targets, overridden by the variables of --template-var.
+func templateVariables(
+	options options.RunOptions,
+	scenario *scenarios.Scenario,
+	logFilePath string,
+) map[string]string {
+	variables := map[string]string{
+		"run_id":      strings.TrimSuffix(filepath.Base(logFilePath), filepath.Ext(logFilePath)),
+		"scenario":    scenario.Name,
+		"environment": scenario.Annotations[scenarios.AnnotationEnvironment],
+	}
+	for name, value := range scenario.Annotations {
+		variables["annotation."+name] = value
+	}
+	maps.Copy(variables, options.TemplateVariables)
+
+	return variables
+}
+
+// parseTemplateVariables parses the name=value pairs of --template-var.
+func parseTemplateVariables(values []string) (map[string]string, error) {
+	variables := make(map[string]string, len(values))
+	for _, pair := range values {
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("%w: --%s %s, expected name=value", errInvalidTemplateVar,
+				triggerflags.FlagTemplateVar, pair)
+		}
+		variables[name] = value
+	}
+
+	return variables, nil
+}
@@ -4,30 +4,42 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/mattn/go-isatty"
 	"github.com/prometheus/client_golang/prometheus/push"
+	"github.com/prometheus/common/expfmt"
 
+	"github.com/form3tech-oss/f1/v2/internal/audit"
 	"github.com/form3tech-oss/f1/v2/internal/envsettings"
+	"github.com/form3tech-oss/f1/v2/internal/gcpause"
+	"github.com/form3tech-oss/f1/v2/internal/goroutines"
+	"github.com/form3tech-oss/f1/v2/internal/hdr"
+	"github.com/form3tech-oss/f1/v2/internal/history"
 	"github.com/form3tech-oss/f1/v2/internal/log"
 	"github.com/form3tech-oss/f1/v2/internal/logutils"
 	"github.com/form3tech-oss/f1/v2/internal/metrics"
 	"github.com/form3tech-oss/f1/v2/internal/options"
 	"github.com/form3tech-oss/f1/v2/internal/progress"
 	"github.com/form3tech-oss/f1/v2/internal/raterun"
+	"github.com/form3tech-oss/f1/v2/internal/redact"
 	"github.com/form3tech-oss/f1/v2/internal/run/views"
+	"github.com/form3tech-oss/f1/v2/internal/targetmetrics"
+	"github.com/form3tech-oss/f1/v2/internal/tracing"
 	"github.com/form3tech-oss/f1/v2/internal/trigger/api"
 	"github.com/form3tech-oss/f1/v2/internal/ui"
 	"github.com/form3tech-oss/f1/v2/internal/workers"
 	"github.com/form3tech-oss/f1/v2/internal/xcontext"
 	"github.com/form3tech-oss/f1/v2/pkg/f1/scenarios"
+	"github.com/form3tech-oss/f1/v2/pkg/f1/testing"
 )
 
-const (
-	nextIterationWindow    = 10 * time.Millisecond
-	metricsRefreshInterval = 5 * time.Second
-)
+const nextIterationWindow = 10 * time.Millisecond
 
 type Run struct {
 	pusher                   *push.Pusher
@@ -36,11 +48,49 @@ type Run struct {
 	views                    *views.Views
 	activeScenario           *workers.ActiveScenario
 	trigger                  *api.Trigger
+	readiness                testing.ReadinessFn
 	output                   *ui.Output
 	scenarioLogger           *ScenarioLogger
+	history                  *history.Store
+	targetMetrics            *targetMetricsCapture
+	tracer                   tracing.Tracer
 	result                   *Result
+	checkpoint               Checkpoint
+	failureSnapshots         failureSnapshots
+	histogram                *hdr.Histogram
+	gc                       *gcpause.Tracker
+	stopCh                   chan struct{}
+	stopReason               string
+	metricsPushes            metricsPushes
 	options                  options.RunOptions
 	waitForCompletionTimeout time.Duration
+	stopOnce                 sync.Once
+	// poolManager is set once the load of the run starts, to skip or jump to the stages of the trigger
+	poolManager atomic.Pointer[workers.PoolManager]
+	// stagesJumped completes the run when the trigger runs out of stages after a skip or a jump
+	stagesJumped atomic.Bool
+	// auditLog records every iteration of the run, if set by the audit log option
+	auditLog *audit.Log
+	// checkpointMu serialises the saves of the checkpoint, by the run and the reservations of
+	// unique iterations up to iterationsReserved
+	checkpointMu       sync.Mutex
+	iterationsReserved uint64
+	// skippedIterations are the iteration numbers skipped when the run was resumed, if any
+	skippedIterations *IterationGap
+	// summaryMetricsFormat is the format of the summary metrics file option
+	summaryMetricsFormat expfmt.Format
+	// otlpExporter exports the metrics to an OpenTelemetry collector with each push, if configured
+	otlpExporter *metrics.OTLPExporter
+	// jsonOutput is set when the result of the run is written as json, to the output file option or
+	// to jsonStdout
+	jsonOutput bool
+	jsonStdout io.Writer
+	// parentOutput is the output the run was created with, writing to stderr with the json result on
+	// stdout
+	parentOutput *ui.Output
+	// junitCases are the phases of the run recorded for the JUnit report option
+	junitCases   []junitTestCase
+	junitCasesMu sync.Mutex
 }
 
 func NewRun(
@@ -53,14 +103,54 @@ func NewRun(
 	parentOutput *ui.Output,
 ) (*Run, error) {
 	progressStats := &progress.Stats{}
-	viewsInstance := views.New()
 
 	scenario := scenarios.GetScenario(options.Scenario)
 	if scenario == nil {
 		return nil, fmt.Errorf("scenario not defined: %s", options.Scenario)
 	}
 
+	logFilePath := LogFilePathOrDefault(settings.Log.FilePath, scenario.Name)
+	viewsInstance, err := views.NewCustom(views.Custom{
+		Variables:   templateVariables(options, scenario, logFilePath),
+		TemplateDir: options.TemplateDir,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("loading templates: %w", err)
+	}
+
+	redactor, err := redact.New(options.Redact)
+	if err != nil {
+		return nil, fmt.Errorf("compiling redaction patterns: %w", err)
+	}
+
 	result := NewResult(options, viewsInstance, progressStats)
+	result.stageAt = trigger.StageAt
+	result.redactor = redactor
+	if options.SLAFile != "" {
+		result.sla, err = ReadSLA(options.SLAFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading sla: %w", err)
+		}
+	}
+	if trigger.StageAt != nil {
+		// iterations are recorded relative to the start of this run, stages to that of resumed runs
+		progressStats.TrackStages(func(elapsed time.Duration) string {
+			return trigger.StageAt(options.Elapsed + elapsed)
+		})
+	}
+
+	jsonOutput, err := jsonOutputFormat(options.OutputFormat)
+	if err != nil {
+		return nil, fmt.Errorf("resolving output format: %w", err)
+	}
+	if options.OutputFile != "" && !jsonOutput {
+		return nil, errOutputFileWithoutJSON
+	}
+	// the json result is the only output on stdout, unless it is written to the output file
+	jsonStdout := parentOutput.Printer.Writer
+	if jsonOutput && options.OutputFile == "" {
+		parentOutput = stderrOutput(parentOutput, logutils.NewLogConfigFromSettings(settings))
+	}
 
 	outputer := ui.NewOutput(
 		parentOutput.Logger.With(log.ScenarioAttr(scenario.Name)),
@@ -69,17 +159,17 @@ func NewRun(
 		options.LogToFile(),
 	)
 
-	scenarioLogger := NewScenarioLogger(outputer)
+	scenarioLogger := NewScenarioLogger(outputer, redactor)
 	result.LogFilePath = scenarioLogger.Open(
-		LogFilePathOrDefault(settings.Log.FilePath, scenario.Name),
+		logFilePath,
 		logutils.NewLogConfigFromSettings(settings),
 		scenario.Name,
 		options.LogToFile(),
 	)
 
-	progressRunner, err := newProgressRunner(result, outputer)
+	progressStyle, err := ui.ProgressStyle(options.Progress, isatty.IsTerminal(os.Stdout.Fd()))
 	if err != nil {
-		return nil, fmt.Errorf("creating progress runner: %w", err)
+		return nil, fmt.Errorf("resolving progress style: %w", err)
 	}
 
 	activeScenario := workers.NewActiveScenario(
@@ -88,13 +178,27 @@ func NewRun(
 		progressStats,
 		scenarioLogger.Logger,
 		log.NewSlogLogrusLogger(scenarioLogger.Logger),
+		options.ScenarioArgs,
+		redactor,
 	)
 
-	pusher := newMetricsPusher(settings, scenario.Name, metricsInstance)
+	// progress updates check the failure rate of the run, which is created below
+	var r *Run
+	progressRunner, err := newProgressRunner(result, outputer, progressStyle, activeScenario.ProgressGauges, func() {
+		r.checkFailureRate()
+		r.checkStopConditions()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating progress runner: %w", err)
+	}
+
+	result.build = ReadBuild()
+	pusher := newMetricsPusher(settings, scenario.Name, metricsInstance, result.build)
 
-	return &Run{
+	r = &Run{
 		options:                  options,
 		trigger:                  trigger,
+		readiness:                scenario.Readiness,
 		metrics:                  metricsInstance,
 		views:                    viewsInstance,
 		result:                   result,
@@ -104,38 +208,105 @@ func NewRun(
 		activeScenario:           activeScenario,
 		scenarioLogger:           scenarioLogger,
 		waitForCompletionTimeout: waitForCompletionTimeout,
-	}, nil
-}
+		stopCh:                   make(chan struct{}),
+		jsonOutput:               jsonOutput,
+		jsonStdout:               jsonStdout,
+		parentOutput:             parentOutput,
+	}
 
-func newMetricsPusher(
-	settings envsettings.Settings,
-	scenarioName string,
-	metricsInstance *metrics.Metrics,
-) *push.Pusher {
-	if settings.Prometheus.PushGateway == "" {
-		return nil
+	if err := r.metricsPushes.configure(settings.Prometheus); err != nil {
+		return nil, fmt.Errorf("configuring metrics pushes: %w", err)
 	}
 
-	pusher := push.New(settings.Prometheus.PushGateway, "f1-"+scenarioName).
-		Gatherer(metricsInstance.Registry)
+	r.otlpExporter, err = newOTLPExporter(settings, scenario.Name, metricsInstance, result.build)
+	if err != nil {
+		return nil, fmt.Errorf("configuring metrics export: %w", err)
+	}
 
-	if settings.Prometheus.Namespace != "" {
-		pusher = pusher.Grouping("namespace", settings.Prometheus.Namespace)
+	if options.HistogramFile != "" {
+		r.histogram = hdr.New()
+		activeScenario.RecordHistogram(r.histogram)
 	}
 
-	if settings.Prometheus.LabelID != "" {
-		pusher = pusher.Grouping("id", settings.Prometheus.LabelID)
+	if options.SummaryMetricsFile != "" {
+		r.summaryMetricsFormat, err = metrics.SummaryFormat(options.SummaryMetricsFormat)
+		if err != nil {
+			return nil, fmt.Errorf("resolving summary metrics format: %w", err)
+		}
+		// the metrics of the iterations are recorded for the summary without a push gateway too
+		metricsInstance.IterationMetricsEnabled = true
 	}
 
-	return pusher
+	if settings.History.Enabled() {
+		r.history = history.NewStore(settings.History.Dir)
+	}
+
+	if settings.TargetMetrics.Enabled() {
+		queries, err := targetmetrics.LoadQueries(settings.TargetMetrics.QueriesFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading target metrics queries: %w", err)
+		}
+
+		r.targetMetrics = &targetMetricsCapture{
+			prometheusURL: settings.TargetMetrics.PrometheusURL,
+			queries:       queries,
+		}
+	}
+
+	if options.StateFile != "" {
+		if err := r.resume(); err != nil {
+			return nil, fmt.Errorf("resuming run: %w", err)
+		}
+	}
+
+	// the audit log and the tracer are created last, as their files are only closed by Do
+	if err := r.openAuditLog(); err != nil {
+		return nil, err
+	}
+	r.tracer, err = tracing.New(options.Trace, options.TraceFile, os.Getenv)
+	if err != nil {
+		return nil, fmt.Errorf("creating tracer: %w", err)
+	}
+
+	return r, nil
 }
 
-func newProgressRunner(result *Result, output *ui.Output) (*raterun.Runner, error) {
+// progressJitter varies the intervals of progress lines printed less often than every second, so
+// that the progress of runs started together, such as those of `f1 orchestrate`, isn't printed at
+// the same time.
+const progressJitter = 10
+
+// newProgressRunner displays the progress of the run. Live progress replaces the previous line every
+// second, while progress lines are printed less often as the run goes on, so as not to flood logs,
+// and every second again for a while when iterations start failing.
+func newProgressRunner(
+	result *Result,
+	output *ui.Output,
+	style string,
+	gauges func() []progress.Gauge,
+	onProgress func(),
+) (*raterun.Runner, error) {
 	notifyDropped := sync.Once{}
 
-	r, err := raterun.New(func(rate time.Duration) {
+	display := func(progress *views.ViewContext[views.ProgressData]) { output.Display(progress) }
+	schedules := []raterun.Schedule{
+		{StartDelay: 0, Frequency: time.Second},
+		{StartDelay: time.Minute, Frequency: 10 * time.Second, Jitter: progressJitter},
+		{StartDelay: 5 * time.Minute, Frequency: 30 * time.Second, Jitter: progressJitter},
+		{StartDelay: 10 * time.Minute, Frequency: time.Minute, Jitter: progressJitter},
+	}
+	if style == ui.ProgressLive {
+		display = func(progress *views.ViewContext[views.ProgressData]) { output.DisplayLive(progress) }
+		schedules = []raterun.Schedule{{StartDelay: 0, Frequency: time.Second}}
+	}
+
+	var runner *raterun.Runner
+	var failed uint64
+	runner, err := raterun.New(func(rate time.Duration) {
 		result.SnapshotProgress(rate)
-		output.Display(result.Progress())
+		result.RecordGauges(gauges())
+		display(result.Progress())
+		onProgress()
 		if result.HasDroppedIterations() {
 			notifyDropped.Do(func() {
 				output.Display(ui.WarningMessage{
@@ -144,74 +315,147 @@ func newProgressRunner(result *Result, output *ui.Output) (*raterun.Runner, erro
 				})
 			})
 		}
-	}, []raterun.Schedule{
-		{StartDelay: 0, Frequency: time.Second},
-		{StartDelay: time.Minute, Frequency: 10 * time.Second},
-		{StartDelay: 5 * time.Minute, Frequency: 30 * time.Second},
-		{StartDelay: 10 * time.Minute, Frequency: time.Minute},
-	})
+
+		snapshot := result.Snapshot()
+		newFailures := snapshot.FailedIterationDurations.Count > failed
+		failed = snapshot.FailedIterationDurations.Count
+		if newFailures && rate > time.Second {
+			// the schedules are valid, as they are those the runner was created with
+			_ = runner.Reschedule(schedules)
+		}
+	}, schedules)
 	if err != nil {
 		return nil, fmt.Errorf("new progress runner: %w", err)
 	}
 
-	return r, nil
+	return runner, nil
 }
 
 func (r *Run) Do(ctx context.Context) (*Result, error) {
+	return r.doUntilHardDeadline(ctx, r.doRun)
+}
+
+// doRun runs the scenario, printing the summary of the run once it completes.
+func (r *Run) doRun(ctx context.Context) (*Result, error) {
 	defer r.scenarioLogger.Close()
+	defer r.closeTracer()
+	defer r.shutdownOTLPExporter()
+	defer r.closeAuditLog()
+	defer r.toggleVerboseOnSignal()()
 
 	welcomeMessage := r.views.Start(views.StartData{
 		Scenario:        r.options.Scenario,
 		MaxDuration:     r.options.MaxDuration,
 		MaxIterations:   r.options.MaxIterations,
 		RateDescription: r.trigger.Description,
+		Endless:         r.options.Endless,
 	})
 
 	r.output.Display(welcomeMessage)
+	r.displaySkippedIterations()
 
 	defer r.printSummary()
 
-	r.metrics.Reset()
+	// the goroutines of the run, started from this one, inherit the labels of the run in profiles
+	var result *Result
+	var err error
+	goroutines.Do(ctx, "run", func(ctx context.Context) {
+		result, err = r.recoverPanics(ctx, r.do)
+	}, goroutines.LabelScenario, r.options.Scenario)
 
-	r.activeScenario.Setup()
+	return result, err
+}
 
-	r.pushMetrics(ctx)
+// do runs the setup, the load and the teardown of the scenario.
+func (r *Run) do(ctx context.Context) (*Result, error) {
+	r.metrics.Reset()
 
-	// run teardown even if the context is cancelled
+	// run teardown even if the context is cancelled, or the setup panics after creating fixtures
 	teardownContext := xcontext.Detach(ctx)
 	defer r.teardownActiveScenario(teardownContext)
 
+	r.tracer.Event("setup started", slog.String("scenario", r.options.Scenario))
+	setupStart := time.Now()
+	r.activeScenario.Setup()
+	r.result.RecordSetupDuration(time.Since(setupStart))
+	r.tracer.Event("setup completed",
+		slog.Duration("duration", r.result.SetupDuration),
+		slog.Bool("failed", r.activeScenario.Failed()),
+	)
+
+	r.pushMetrics(ctx)
+
 	if r.activeScenario.Failed() {
-		return r.reportSetupFailure(ctx), nil
+		result := r.reportSetupFailure(ctx)
+		r.recordJUnitCase(JUnitSetup, r.result.SetupDuration, r.result.Error())
+		r.skipJUnitCase(JUnitIterations, "setup failed")
+		return result, nil
+	}
+	r.recordJUnitCase(JUnitSetup, r.result.SetupDuration, nil)
+
+	if err := r.waitUntilReady(ctx); err != nil {
+		r.result.AddError(err)
+		r.recordJUnitCase(JUnitIterations, 0, err)
+		return r.result, nil
+	}
+
+	if err := r.waitForStart(ctx); err != nil {
+		r.result.AddError(err)
+		r.recordJUnitCase(JUnitIterations, 0, err)
+		return r.result, nil
 	}
 
 	// set initial started timestamp so that the progress trackers work
 	r.result.RecordStarted()
 
 	metricsCloseCh := make(chan struct{})
-	go func() {
-		t := time.NewTicker(metricsRefreshInterval)
+	// stop pushing metrics when the run panics too
+	closeMetrics := sync.OnceFunc(func() { close(metricsCloseCh) })
+	defer closeMetrics()
+	goroutines.Go(ctx, "metrics-push", func(ctx context.Context) {
+		t := time.NewTicker(r.metricsPushes.interval)
 		defer t.Stop()
+		checkpointTicker := time.NewTicker(checkpointInterval)
+		defer checkpointTicker.Stop()
 
 		for {
 			select {
 			case <-t.C:
-				r.pushMetrics(ctx)
+				r.pushChangedMetrics(ctx)
+			case <-checkpointTicker.C:
+				r.saveCheckpoint()
 			case <-ctx.Done():
 				return
 			case <-metricsCloseCh:
 				return
 			}
 		}
-	}()
+	})
 
-	r.progressRunner.Start(ctx)
+	goroutines.Do(ctx, "progress", r.progressRunner.Start)
+	stopProgress := sync.OnceFunc(r.progressRunner.Stop)
+	defer stopProgress()
 
 	r.run(ctx)
+	r.recordPlannedIterations()
 
-	r.progressRunner.Stop()
-	close(metricsCloseCh)
+	stopProgress()
+	closeMetrics()
 	r.result.GetTotals()
+	r.checkSLA()
+	r.writeReportSnapshot(false)
+	r.writeHistogram()
+	r.writeSummaryMetrics()
+	r.closeAuditLog()
+	r.recordGCPauses()
+	r.captureTargetMetrics(teardownContext)
+	r.recordJUnitCase(JUnitIterations, r.result.TestDuration, r.result.iterationsFailure())
+
+	if ctx.Err() != nil {
+		r.saveCheckpoint()
+	} else {
+		r.completeCheckpoint()
+	}
 
 	return r.result, nil
 }
@@ -219,85 +463,198 @@ func (r *Run) Do(ctx context.Context) (*Result, error) {
 func (r *Run) reportSetupFailure(ctx context.Context) *Result {
 	r.fail("setup failed")
 	r.pushMetrics(ctx)
+	r.writeSummaryMetrics()
 	r.output.Display(r.result.Setup())
 	return r.result
 }
 
 func (r *Run) teardownActiveScenario(ctx context.Context) {
+	r.tracer.Event("teardown started")
+	teardownStart := time.Now()
 	r.activeScenario.Teardown()
-	if r.activeScenario.TeardownFailed() {
-		r.fail("teardown failed")
+	r.result.RecordTeardownDuration(time.Since(teardownStart))
+	r.tracer.Event("teardown completed",
+		slog.Duration("duration", r.result.TeardownDuration),
+		slog.Bool("failed", r.activeScenario.TeardownFailed()),
+	)
+	teardownErr := r.activeScenario.TeardownErr()
+	if teardownErr != nil {
+		r.result.AddError(fmt.Errorf("teardown failed: %w", teardownErr))
+	} else if r.activeScenario.TeardownFailed() {
+		teardownErr = errTeardownFailed
+		r.result.AddError(teardownErr)
 	}
+	r.recordJUnitCase(JUnitTeardown, r.result.TeardownDuration, teardownErr)
 	r.pushMetrics(ctx)
+	r.activeScenario.StopRecording()
+	r.result.RecordScenarioSummary(r.activeScenario.Summary())
 	r.output.Display(r.result.Teardown())
 }
 
 func (r *Run) printSummary() {
+	// the logs may have been switched to the log file while the run was in progress
+	r.result.LogFilePath = r.scenarioLogger.FilePath()
 	r.output.Display(r.result.Summary())
+	if r.result.hasTargetMetrics() {
+		r.output.Display(r.result.TargetMetrics())
+	}
+	if r.result.HasDroppedIterations() {
+		r.output.Display(r.result.Drops())
+	}
+	if r.result.hasFailures() {
+		r.output.Display(r.result.Failures())
+	}
+	if r.options.ShowSlowest && r.result.hasSlowest() {
+		r.output.Display(r.result.Slowest())
+	}
+	if r.result.hasScenarioSummary() {
+		r.output.Display(r.result.ScenarioSummary())
+	}
+	if r.result.hasGCPauses() {
+		r.output.Display(r.result.GCPauses())
+	}
+	if r.result.hasSLA() {
+		r.output.Display(r.result.SLA())
+	}
+	r.writeJSONResult()
+	r.writeJUnitReport()
+	r.recordHistory()
 }
 
 func (r *Run) run(ctx context.Context) {
 	// if the trigger has a limited duration, restrict the run to that duration.
-	duration := r.options.MaxDuration
-	if r.trigger.Duration > 0 && r.trigger.Duration < r.options.MaxDuration {
-		duration = r.trigger.Duration
-	}
+	duration := r.plannedDuration()
 
 	// Cancel work slightly before end of duration to avoid starting a new iteration
 	r.result.RecordStarted()
+	r.activeScenario.TrackPhase(time.Now(), r.options.Elapsed, r.trigger.StageAt)
 	defer r.result.RecordTestFinished()
 
 	triggerCtx, triggerCancel := context.WithTimeout(ctx, duration-nextIterationWindow)
 	defer triggerCancel()
 
-	poolManager := workers.New(r.options.MaxIterations, r.activeScenario)
-	r.trigger.Trigger(triggerCtx, r.output, poolManager, r.options)
+	goroutines.Go(triggerCtx, "memory-guard", func(ctx context.Context) {
+		r.guardMemory(ctx, triggerCancel)
+	})
+
+	// track the garbage collections of the load phase only, excluding those of the setup
+	if r.options.AnnotateGC {
+		r.gc = gcpause.New()
+		r.activeScenario.AnnotateGC(r.gc)
+	}
+
+	poolManager := workers.New(r.options.MaxIterations, r.activeScenario, r.tracer)
+	poolManager.PinWorkers(r.options.WorkerCPUs)
+	poolManager.RampWorkers(r.options.WorkerRamp)
+	poolManager.PaceWorkers(r.options.WorkerPacing)
+	if r.trigger.StageAt != nil {
+		start := time.Now()
+		poolManager.LabelStages(func() string { return r.trigger.StageAt(r.options.Elapsed + time.Since(start)) })
+	}
+	r.numberIterationsUniquely(poolManager)
+	r.poolManager.Store(poolManager)
+	defer func() { r.result.RecordQuotaDrops(poolManager.QuotaDrops()) }()
+	goroutines.Go(triggerCtx, "rate-drops", func(ctx context.Context) { r.watchRateDrops(ctx, poolManager) })
+	goroutines.Go(triggerCtx, "autotune", func(ctx context.Context) { r.autotuneConcurrency(ctx, poolManager) })
+	goroutines.Go(triggerCtx, "stage-gate", func(ctx context.Context) { r.gateStages(ctx, poolManager) })
+	r.overrideRates(triggerCtx, poolManager)
+
+	// stop triggering iterations once the max iterations have started, rather than running the
+	// remaining stages of the trigger without starting any iterations
+	goroutines.Go(triggerCtx, "stop-watch", func(context.Context) {
+		select {
+		case <-poolManager.MaxIterationsDone():
+			triggerCancel()
+		case <-poolManager.Aborted():
+			reason, err := poolManager.AbortReason()
+			r.result.AddError(err)
+			r.output.Display(ui.ErrorMessage{Message: "stopping the run", Error: err})
+			r.stop(reason)
+			triggerCancel()
+		case <-r.stopCh:
+			triggerCancel()
+		case <-triggerCtx.Done():
+		}
+		r.activeScenario.StopIterations()
+	})
+
+	goroutines.Go(triggerCtx, "report-snapshots", r.writeReportSnapshots)
+
+	// wait for the profiles being written, so that they are complete when the run completes
+	profilesDone := make(chan struct{})
+	goroutines.Go(triggerCtx, "profiles", func(ctx context.Context) {
+		defer close(profilesDone)
+		r.captureProfiles(ctx, poolManager)
+	})
+	defer func() { <-profilesDone }()
+
+	// should the run panic, stop the workers and wait for them before the scenario is torn down
+	completed := false
+	defer func() {
+		if !completed {
+			triggerCancel()
+			r.activeScenario.StopIterations()
+			r.waitForWorkers(poolManager)
+		}
+	}()
+
+	r.tracer.Event("trigger started", slog.Duration("duration", duration))
+	goroutines.Do(triggerCtx, "trigger", func(ctx context.Context) {
+		r.trigger.Trigger(ctx, r.output, poolManager, r.options)
+	})
+	r.tracer.Event("trigger stopped")
+	if (r.trigger.Completes || r.stagesJumped.Load()) && triggerCtx.Err() == nil {
+		r.stop("Trigger Completed")
+		triggerCancel()
+	}
 
 	select {
 	case <-ctx.Done():
 		r.output.Display(r.result.Interrupted())
 		r.progressRunner.Restart()
-		select {
-		case <-poolManager.WaitForCompletion():
-		case <-time.After(r.waitForCompletionTimeout):
-			r.output.Display(ui.WarningMessage{
-				Message: fmt.Sprintf("Active tests not completed after %s. Stopping...", r.waitForCompletionTimeout.String()),
-			})
-		}
+		r.waitForWorkers(poolManager)
 
 	case <-triggerCtx.Done():
-		if triggerCtx.Err() == context.DeadlineExceeded {
+		reason, stopped := r.stopping()
+		switch {
+		case stopped:
+			r.output.Display(r.result.Stopped(reason))
+		case poolManager.MaxIterationsReached():
+			r.output.Display(r.result.MaxIterationsReached())
+		case triggerCtx.Err() == context.DeadlineExceeded:
 			r.output.Display(r.result.MaxDurationElapsed())
-		} else {
+		default:
 			r.output.Display(r.result.Interrupted())
 		}
-		select {
-		case <-poolManager.WaitForCompletion():
-		case <-time.After(r.waitForCompletionTimeout):
-			r.output.Display(ui.WarningMessage{
-				Message: fmt.Sprintf("Active tests not completed after %s. Stopping...", r.waitForCompletionTimeout.String()),
-			})
-		}
+		r.waitForWorkers(poolManager)
 	case <-poolManager.WaitForCompletion():
 		if poolManager.MaxIterationsReached() {
 			r.output.Display(r.result.MaxIterationsReached())
 		}
 	}
+	completed = true
 }
 
-func (r *Run) fail(message string) {
-	r.result.AddError(errors.New(message))
+// waitForWorkers waits for the active iterations to complete, for up to waitForCompletionTimeout.
+func (r *Run) waitForWorkers(poolManager *workers.PoolManager) {
+	select {
+	case <-poolManager.WaitForCompletion():
+	case <-time.After(r.waitForCompletionTimeout):
+		r.output.Display(ui.WarningMessage{
+			Message: fmt.Sprintf("Active tests not completed after %s. Stopping...", r.waitForCompletionTimeout.String()),
+		})
+	}
 }
 
-func (r *Run) pushMetrics(ctx context.Context) {
-	if r.pusher == nil {
-		return
-	}
-	err := r.pusher.PushContext(ctx)
-	if err != nil {
+func (r *Run) closeTracer() {
+	if err := r.tracer.Close(); err != nil {
 		r.output.Display(ui.ErrorMessage{
-			Message: "unable to push metrics to prometheus",
+			Message: "unable to close the tracer",
 			Error:   err,
 		})
 	}
 }
+
+func (r *Run) fail(message string) {
+	r.result.AddError(errors.New(message))
+}
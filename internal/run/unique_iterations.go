@@ -0,0 +1,81 @@
+package run
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/form3tech-oss/f1/v2/internal/triggerflags"
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+	"github.com/form3tech-oss/f1/v2/internal/workers"
+)
+
+// IterationGap is a range of iteration numbers which were reserved by an interrupted run but not
+// known to have started, and which are skipped by the run resuming it.
+type IterationGap struct {
+	First uint64 `json:"first"`
+	Last  uint64 `json:"last"`
+}
+
+// resumeUniqueIterations continues the numbering of the iterations of the checkpoint after the
+// numbers it reserved, recording those not known to have started as a gap.
+func (r *Run) resumeUniqueIterations(checkpoint *Checkpoint) error {
+	if checkpoint.UniqueIterations != r.options.UniqueIterations {
+		return fmt.Errorf("--%s must be %t to resume the run in checkpoint %s",
+			triggerflags.FlagUniqueIterations, checkpoint.UniqueIterations, r.options.StateFile)
+	}
+	if checkpoint.IterationsReserved <= checkpoint.LastIteration {
+		return nil
+	}
+
+	gap := IterationGap{First: checkpoint.LastIteration + 1, Last: checkpoint.IterationsReserved}
+	checkpoint.IterationGaps = append(slices.Clip(checkpoint.IterationGaps), gap)
+	checkpoint.LastIteration = checkpoint.IterationsReserved
+	r.skippedIterations = &gap
+
+	return nil
+}
+
+// numberIterationsUniquely reserves the numbers of the iterations in the state file before they
+// start, after the numbers reserved by the runs resumed.
+func (r *Run) numberIterationsUniquely(poolManager *workers.PoolManager) {
+	if !r.options.UniqueIterations {
+		return
+	}
+
+	poolManager.UniqueIterations(r.checkpoint.IterationsReserved, r.reserveIterations)
+}
+
+// reserveIterations saves the checkpoint with the iteration numbers up to upTo reserved.
+func (r *Run) reserveIterations(upTo uint64) error {
+	r.checkpointMu.Lock()
+	defer r.checkpointMu.Unlock()
+
+	checkpoint := r.currentCheckpoint()
+	checkpoint.IterationsReserved = upTo
+	if err := WriteCheckpoint(r.options.StateFile, checkpoint); err != nil {
+		return err
+	}
+	r.iterationsReserved = upTo
+
+	return nil
+}
+
+// displaySkippedIterations warns about the iteration numbers skipped when the run was resumed.
+func (r *Run) displaySkippedIterations() {
+	if r.skippedIterations == nil {
+		return
+	}
+
+	r.output.Display(ui.WarningMessage{Message: fmt.Sprintf(
+		"skipping iterations %d to %d, which were reserved by the interrupted run but not recorded as started",
+		r.skippedIterations.First, r.skippedIterations.Last,
+	)})
+}
+
+// RecordIterationGaps records the iteration numbers skipped by the run and the runs it resumed.
+func (r *Result) RecordIterationGaps(gaps []IterationGap) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.iterationGaps = gaps
+}
@@ -0,0 +1,52 @@
+package run
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/form3tech-oss/f1/v2/internal/platform"
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+)
+
+// ToggleVerbose switches the logs of the scenario between the console and the log file while the
+// run is in progress, returning true if they are now written to the console.
+func (r *Run) ToggleVerbose() (bool, error) {
+	verbose := !r.scenarioLogger.Verbose()
+	if err := r.scenarioLogger.SetVerbose(verbose); err != nil {
+		return !verbose, fmt.Errorf("switching logs: %w", err)
+	}
+	r.tracer.Event("logs switched", slog.Bool("verbose", verbose))
+
+	return verbose, nil
+}
+
+// toggleVerboseOnSignal toggles verbose logging every time the process receives one of the verbose
+// toggle signals of the environment, until the returned function is called.
+func (r *Run) toggleVerboseOnSignal() func() {
+	toggleSignals := platform.Current().VerboseToggleSignals
+	if len(toggleSignals) == 0 {
+		return func() {}
+	}
+
+	signals := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	stop := platform.Notify(signals, toggleSignals)
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-signals:
+				if _, err := r.ToggleVerbose(); err != nil {
+					r.output.Display(ui.ErrorMessage{Message: "Unable to switch logs", Error: err})
+				}
+			}
+		}
+	}()
+
+	return func() {
+		stop()
+		close(done)
+	}
+}
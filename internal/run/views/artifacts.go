@@ -0,0 +1,32 @@
+package views
+
+import (
+	"log/slog"
+
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+)
+
+const artifactsTemplate = `{bold}Artifacts:{-}
+{{- range .URLs}}
+  {{.}}
+{{- end}}`
+
+var _ ui.Outputable = (*ViewContext[ArtifactsData])(nil)
+
+// ArtifactsData are the urls of the artifacts of the run uploaded to object storage.
+type ArtifactsData struct {
+	URLs []string
+}
+
+func (d ArtifactsData) Log(logger *slog.Logger) {
+	for _, url := range d.URLs {
+		logger.Info("Artifact uploaded", slog.String("url", url))
+	}
+}
+
+func (v *Views) Artifacts(data ArtifactsData) *ViewContext[ArtifactsData] {
+	return &ViewContext[ArtifactsData]{
+		view: v.artifacts,
+		data: data,
+	}
+}
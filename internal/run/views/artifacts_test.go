@@ -0,0 +1,29 @@
+package views_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/form3tech-oss/f1/v2/internal/log"
+	"github.com/form3tech-oss/f1/v2/internal/run/views"
+)
+
+func Test_RenderArtifacts(t *testing.T) {
+	t.Parallel()
+
+	view := views.New().Artifacts(views.ArtifactsData{
+		URLs: []string{"s3://bucket/payments/report.json", "s3://bucket/payments/report.html"},
+	})
+
+	output := view.Render()
+	var logOutput bytes.Buffer
+	view.Log(log.NewTestLogger(&logOutput))
+
+	assert.Equal(t, "Artifacts:\n"+
+		"  s3://bucket/payments/report.json\n"+
+		"  s3://bucket/payments/report.html", output)
+	assert.Equal(t, "level=INFO msg=\"Artifact uploaded\" url=s3://bucket/payments/report.json\n"+
+		"level=INFO msg=\"Artifact uploaded\" url=s3://bucket/payments/report.html\n", logOutput.String())
+}
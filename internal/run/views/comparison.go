@@ -0,0 +1,38 @@
+package views
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+)
+
+//nolint:lll // templates read better with long lines
+const comparisonTemplate = `{bold}Compared to previous run at {{.PreviousTime.Format "2006-01-02 15:04:05"}}:{-} Δp95 {{signedDuration .P95Delta}}, Δerror rate {{printf "%+0.2f" .ErrorRateDelta}}pp, Δthroughput {{printf "%+0.1f" .ThroughputDelta}}/second`
+
+var _ ui.Outputable = (*ViewContext[ComparisonData])(nil)
+
+type ComparisonData struct {
+	PreviousTime time.Time
+	P95Delta     time.Duration
+	// ErrorRateDelta is the difference of the error rates in percentage points
+	ErrorRateDelta float64
+	// ThroughputDelta is the difference of the iterations started per second
+	ThroughputDelta float64
+}
+
+func (d ComparisonData) Log(logger *slog.Logger) {
+	logger.Info("Compared to previous run",
+		slog.Time("previous_time", d.PreviousTime),
+		slog.Duration("p95_delta", d.P95Delta),
+		slog.Float64("error_rate_delta", d.ErrorRateDelta),
+		slog.Float64("throughput_delta", d.ThroughputDelta),
+	)
+}
+
+func (v *Views) Comparison(data ComparisonData) *ViewContext[ComparisonData] {
+	return &ViewContext[ComparisonData]{
+		view: v.comparison,
+		data: data,
+	}
+}
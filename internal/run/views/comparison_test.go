@@ -0,0 +1,66 @@
+package views_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/form3tech-oss/f1/v2/internal/log"
+	"github.com/form3tech-oss/f1/v2/internal/run/views"
+)
+
+func Test_RenderComparison(t *testing.T) {
+	t.Parallel()
+
+	previousTime := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name        string
+		expected    string
+		expectedLog string
+		data        views.ComparisonData
+	}{
+		{
+			name: "regression",
+			data: views.ComparisonData{
+				PreviousTime:    previousTime,
+				P95Delta:        12 * time.Millisecond,
+				ErrorRateDelta:  0.5,
+				ThroughputDelta: -3.14,
+			},
+			expected: "Compared to previous run at 2024-05-01 10:30:00: Δp95 +12ms, Δerror rate +0.50pp, Δthroughput -3.1/second",
+			expectedLog: "level=INFO msg=\"Compared to previous run\" previous_time=2024-05-01T10:30:00.000Z " +
+				"p95_delta=12ms error_rate_delta=0.5 throughput_delta=-3.14\n",
+		},
+		{
+			name: "improvement",
+			data: views.ComparisonData{
+				PreviousTime:    previousTime,
+				P95Delta:        -2 * time.Millisecond,
+				ErrorRateDelta:  -1,
+				ThroughputDelta: 10,
+			},
+			expected: "Compared to previous run at 2024-05-01 10:30:00: Δp95 -2ms, Δerror rate -1.00pp, Δthroughput +10.0/second",
+			expectedLog: "level=INFO msg=\"Compared to previous run\" previous_time=2024-05-01T10:30:00.000Z " +
+				"p95_delta=-2ms error_rate_delta=-1 throughput_delta=10\n",
+		},
+	}
+
+	v := views.New()
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			view := v.Comparison(testCase.data)
+
+			output := view.Render()
+			var logOutput bytes.Buffer
+			view.Log(log.NewTestLogger(&logOutput))
+
+			assert.Equal(t, testCase.expected, output)
+			assert.Equal(t, testCase.expectedLog, logOutput.String())
+		})
+	}
+}
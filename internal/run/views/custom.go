@@ -0,0 +1,140 @@
+package views
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// TemplateExt is the extension of the custom templates of a template directory, named after the
+// view they replace, such as result.tmpl for the summary of the run.
+const TemplateExt = ".tmpl"
+
+var errUnknownTemplate = errors.New("unknown template")
+
+// Custom customizes the views of a run.
+type Custom struct {
+	// Variables are the variables of the run read by the templates with var and vars, such as its
+	// run ID, annotations and the variables set with --template-var
+	Variables map[string]string
+	// TemplateDir is the directory of the custom templates replacing the built-in templates, if set
+	TemplateDir string
+}
+
+// NewCustom returns the views with the variables of custom, and the templates of its template
+// directory in place of the built-in templates they are named after. Custom templates use the same
+// functions and colours as the built-in templates, and fall back to them if they fail to render.
+func NewCustom(custom Custom) (*Views, error) {
+	v := newViews(custom.Variables)
+	if custom.TemplateDir == "" {
+		return v, nil
+	}
+
+	texts, err := readTemplateDir(custom.TemplateDir)
+	if err != nil {
+		return nil, err
+	}
+
+	views := v.byName()
+	for name, text := range texts {
+		view, ok := views[name]
+		if !ok {
+			names := make([]string, 0, len(views))
+			for known := range views {
+				names = append(names, known)
+			}
+			slices.Sort(names)
+			return nil, fmt.Errorf("%w '%s%s' in %s, expected one of %s", errUnknownTemplate, name, TemplateExt,
+				custom.TemplateDir, strings.Join(names, ", "))
+		}
+
+		tty, err := parseCustomTemplate(name, text, renderTermColorsEnabled, custom.Variables)
+		if err != nil {
+			return nil, err
+		}
+		notty, err := parseCustomTemplate(name, text, renderTermColorsDisabled, custom.Variables)
+		if err != nil {
+			return nil, err
+		}
+		*view = View{tty: tty, notty: notty, builtin: &View{tty: view.tty, notty: view.notty}}
+	}
+
+	return v, nil
+}
+
+// readTemplateDir returns the text of the custom templates of dir by name, ignoring other files.
+func readTemplateDir(dir string) (map[string]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading template directory: %w", err)
+	}
+
+	texts := map[string]string{}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != TemplateExt {
+			continue
+		}
+
+		text, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("reading template: %w", err)
+		}
+		texts[strings.TrimSuffix(entry.Name(), TemplateExt)] = string(text)
+	}
+
+	return texts, nil
+}
+
+func parseCustomTemplate(
+	name, text string,
+	renderTermColors renderTermColorsType,
+	variables map[string]string,
+) (*template.Template, error) {
+	t, err := template.New(name).
+		Funcs(templateFunctions(variables)).
+		Parse(applyReplacements(text, termReplacements(renderTermColors)))
+	if err != nil {
+		return nil, fmt.Errorf("parsing template '%s%s': %w", name, TemplateExt, err)
+	}
+
+	return t, nil
+}
+
+// renderCustom renders the custom template of view, or its built-in template with the error of the
+// custom template if it fails.
+func renderCustom(view *View, data any) string {
+	var builder strings.Builder
+	if err := view.Template().Execute(&builder, data); err != nil {
+		return fmt.Sprintf("rendering custom template: %s\n%s", err, render(view.builtin.Template(), data))
+	}
+
+	return builder.String()
+}
+
+// byName returns the views by the name of their template.
+func (v *Views) byName() map[string]*View {
+	return map[string]*View{
+		"start":                v.start,
+		"result":               v.result,
+		"setup":                v.setup,
+		"progress":             v.progress,
+		"teardown":             v.teardown,
+		"timeout":              v.timeout,
+		"maxIterationsReached": v.maxIterationsReached,
+		"interrupt":            v.interrupt,
+		"stopped":              v.stopped,
+		"comparison":           v.comparison,
+		"targetMetrics":        v.targetMetrics,
+		"gcPauses":             v.gcPauses,
+		"drops":                v.drops,
+		"failures":             v.failures,
+		"slowest":              v.slowest,
+		"scenarioSummary":      v.scenarioSummary,
+		"sla":                  v.sla,
+		"artifacts":            v.artifacts,
+	}
+}
@@ -0,0 +1,117 @@
+package views_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/run/views"
+)
+
+func templateDir(t *testing.T, templates map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, text := range templates {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(text), 0o600))
+	}
+
+	return dir
+}
+
+func Test_RenderCustomTemplates(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name      string
+		templates map[string]string
+		expected  string
+	}{
+		{
+			name:      "built-in templates",
+			templates: map[string]string{"README.md": "templates of the team"},
+			expected:  "Scenario summary:\n  created 10000 accounts",
+		},
+		{
+			name: "custom template with variables",
+			templates: map[string]string{
+				"scenarioSummary.tmpl": `{{var "team"}} ran {{var "run_id"}}{{range .Lines}}: {{.}}{{end}}` +
+					`{{var "missing"}}`,
+			},
+			expected: "payments ran f1-accounts-ab12: created 10000 accounts",
+		},
+		{
+			name: "custom template listing the variables",
+			templates: map[string]string{
+				"scenarioSummary.tmpl": `{{range $name, $value := vars}}{{$name}}={{$value}} {{end}}`,
+			},
+			expected: "run_id=f1-accounts-ab12 team=payments ",
+		},
+		{
+			name:      "custom template failing to render",
+			templates: map[string]string{"scenarioSummary.tmpl": `{{.Accounts}}`},
+			expected: "rendering custom template: template: scenarioSummary:1:2: executing \"scenarioSummary\" at " +
+				"<.Accounts>: can't evaluate field Accounts in type views.ScenarioSummaryData\n" +
+				"Scenario summary:\n  created 10000 accounts",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			v, err := views.NewCustom(views.Custom{
+				Variables:   map[string]string{"run_id": "f1-accounts-ab12", "team": "payments"},
+				TemplateDir: templateDir(t, test.templates),
+			})
+			require.NoError(t, err)
+
+			output := v.ScenarioSummary(views.ScenarioSummaryData{Lines: []string{"created 10000 accounts"}}).Render()
+
+			assert.Equal(t, test.expected, output)
+		})
+	}
+}
+
+func Test_InvalidCustomTemplates(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name      string
+		templates map[string]string
+		err       string
+	}{
+		{
+			name:      "unknown template",
+			templates: map[string]string{"summary.tmpl": "{{.Error}}"},
+			err:       "unknown template 'summary.tmpl' in ",
+		},
+		{
+			name:      "invalid template",
+			templates: map[string]string{"result.tmpl": "{{.Error"},
+			err:       "parsing template 'result.tmpl': ",
+		},
+		{
+			name:      "unknown function",
+			templates: map[string]string{"result.tmpl": `{{env "HOME"}}`},
+			err:       `function "env" not defined`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := views.NewCustom(views.Custom{TemplateDir: templateDir(t, test.templates)})
+
+			require.ErrorContains(t, err, test.err)
+		})
+	}
+}
+
+func Test_MissingTemplateDir(t *testing.T) {
+	t.Parallel()
+
+	_, err := views.NewCustom(views.Custom{TemplateDir: filepath.Join(t.TempDir(), "templates")})
+
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
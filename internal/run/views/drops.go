@@ -0,0 +1,66 @@
+package views
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+)
+
+const dropsTemplate = `{bold}Dropped iterations were planned to start:{-}
+{{- range .Stages}}
+  {{with .Stage}}in {{.}} {{end}}between {{duration .From}} and {{duration .To}}: {yellow}{{.Count}}{-}
+{{- end}}
+{{- with .Quotas}}
+{bold}Dropped by the concurrency quotas of operations:{-}
+{{- range .}}
+  {{.Operation}} (at most {{.Workers}} workers): {yellow}{{.Count}}{-}
+{{- end}}
+{{- end}}`
+
+var _ ui.Outputable = (*ViewContext[DropsData])(nil)
+
+// StageDrops counts the iterations dropped in a stage, planned to start between From and To.
+type StageDrops struct {
+	Stage string
+	Count uint64
+	From  time.Duration
+	To    time.Duration
+}
+
+// QuotaDrops counts the iterations of an operation dropped while capped by its concurrency quota.
+type QuotaDrops struct {
+	Operation string
+	Workers   int
+	Count     uint64
+}
+
+type DropsData struct {
+	Stages []StageDrops
+	Quotas []QuotaDrops
+}
+
+func (d DropsData) Log(logger *slog.Logger) {
+	for _, stage := range d.Stages {
+		logger.Warn("Dropped iterations",
+			slog.String("stage", stage.Stage),
+			slog.Uint64("count", stage.Count),
+			slog.Duration("from", stage.From),
+			slog.Duration("to", stage.To),
+		)
+	}
+	for _, quota := range d.Quotas {
+		logger.Warn("Dropped iterations by concurrency quota",
+			slog.String("operation", quota.Operation),
+			slog.Int("workers", quota.Workers),
+			slog.Uint64("count", quota.Count),
+		)
+	}
+}
+
+func (v *Views) Drops(data DropsData) *ViewContext[DropsData] {
+	return &ViewContext[DropsData]{
+		view: v.drops,
+		data: data,
+	}
+}
@@ -0,0 +1,57 @@
+package views_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/form3tech-oss/f1/v2/internal/log"
+	"github.com/form3tech-oss/f1/v2/internal/run/views"
+)
+
+func Test_RenderDrops(t *testing.T) {
+	t.Parallel()
+
+	view := views.New().Drops(views.DropsData{
+		Stages: []views.StageDrops{
+			{Stage: "stage 1 (constant)", Count: 120, From: 12 * time.Second, To: 19 * time.Second},
+			{Count: 3, From: time.Minute, To: time.Minute + time.Second},
+		},
+	})
+
+	output := view.Render()
+	var logOutput bytes.Buffer
+	view.Log(log.NewTestLogger(&logOutput))
+
+	assert.Equal(t, "Dropped iterations were planned to start:\n"+
+		"  in stage 1 (constant) between 12s and 19s: 120\n"+
+		"  between 1m0s and 1m1s: 3", output)
+	assert.Equal(t,
+		"level=WARN msg=\"Dropped iterations\" stage=\"stage 1 (constant)\" count=120 from=12s to=19s\n"+
+			"level=WARN msg=\"Dropped iterations\" stage=\"\" count=3 from=1m0s to=1m1s\n",
+		logOutput.String())
+}
+
+func Test_RenderQuotaDrops(t *testing.T) {
+	t.Parallel()
+
+	view := views.New().Drops(views.DropsData{
+		Stages: []views.StageDrops{{Count: 8, From: 0, To: time.Second}},
+		Quotas: []views.QuotaDrops{{Operation: "write", Workers: 2, Count: 8}},
+	})
+
+	output := view.Render()
+	var logOutput bytes.Buffer
+	view.Log(log.NewTestLogger(&logOutput))
+
+	assert.Equal(t, "Dropped iterations were planned to start:\n"+
+		"  between 0s and 1s: 8\n"+
+		"Dropped by the concurrency quotas of operations:\n"+
+		"  write (at most 2 workers): 8", output)
+	assert.Equal(t,
+		"level=WARN msg=\"Dropped iterations\" stage=\"\" count=8 from=0s to=1s\n"+
+			"level=WARN msg=\"Dropped iterations by concurrency quota\" operation=write workers=2 count=8\n",
+		logOutput.String())
+}
@@ -13,6 +13,7 @@ const (
 	timeoutTemplate              = `{cyan}[{{durationSeconds .Duration | printf "%5s"}}]  Max Duration Elapsed - waiting for active tests to complete{-}`
 	maxIterationsReachedTemplate = `{cyan}[{{durationSeconds .Duration | printf "%5s"}}]  Max Iterations Reached - waiting for active tests to complete{-}`
 	interruptTemplate            = `{cyan}[{{durationSeconds .Duration | printf "%5s"}}]  Interrupted - waiting for active tests to complete{-}`
+	stoppedTemplate              = `{cyan}[{{durationSeconds .Duration | printf "%5s"}}]  Stopped: {{.Reason}} - waiting for active tests to complete{-}`
 )
 
 type exitData struct {
@@ -23,6 +24,7 @@ var (
 	_ ui.Outputable = (*ViewContext[TimeoutData])(nil)
 	_ ui.Outputable = (*ViewContext[MaxIterationsReachedData])(nil)
 	_ ui.Outputable = (*ViewContext[InterruptData])(nil)
+	_ ui.Outputable = (*ViewContext[StoppedData])(nil)
 )
 
 type (
@@ -65,3 +67,20 @@ func (v *Views) Interrupt(data InterruptData) *ViewContext[InterruptData] {
 		data: data,
 	}
 }
+
+type StoppedData struct {
+	Reason   string
+	Duration time.Duration
+}
+
+func (d StoppedData) Log(logger *slog.Logger) {
+	logger.Info("Stopped - waiting for active tests to complete",
+		slog.String("reason", d.Reason), log.DurationAttr(d.Duration))
+}
+
+func (v *Views) Stopped(data StoppedData) *ViewContext[StoppedData] {
+	return &ViewContext[StoppedData]{
+		view: v.stopped,
+		data: data,
+	}
+}
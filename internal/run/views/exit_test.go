@@ -118,3 +118,41 @@ func Test_Interrupt(t *testing.T) {
 		})
 	}
 }
+
+func Test_Stopped(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		expected    string
+		expectedLog string
+		data        views.StoppedData
+	}{
+		{
+			name: "stopped",
+			data: views.StoppedData{
+				Reason:   "Stop Requested",
+				Duration: 1 * time.Minute,
+			},
+			expected: "[ 1m0s]  Stopped: Stop Requested - waiting for active tests to complete",
+			expectedLog: "level=INFO msg=\"Stopped - waiting for active tests to complete\" " +
+				"reason=\"Stop Requested\" duration=1m0s\n",
+		},
+	}
+
+	v := views.New()
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			view := v.Stopped(testCase.data)
+
+			output := view.Render()
+			var logOutput bytes.Buffer
+			view.Log(log.NewTestLogger(&logOutput))
+
+			assert.Equal(t, testCase.expected, output)
+			assert.Equal(t, testCase.expectedLog, logOutput.String())
+		})
+	}
+}
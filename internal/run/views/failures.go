@@ -0,0 +1,104 @@
+package views
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+)
+
+const failuresTemplate = `
+{{- with .Categories}}{bold}Failures by category:{-}
+{{- range .}}
+  {{printf "%-12s" .Category}} {red}{{printf "%8d" .Count}} failed{-}, p95 {{duration .P95}}
+{{- end}}
+{{end -}}
+{bold}First failed iterations:{-}
+{{- range .First}}
+  {{template "failure" .}}
+{{- end}}
+{{- with .Last}}
+{bold}Last failed iterations:{-}
+{{- range .}}
+  {{template "failure" .}}
+{{- end}}
+{{- end}}
+{{- with .Isolated}}
+{yellow}{{.Count}} of {{.Total}} failures on worker {{.Worker}}, suggesting a poisoned connection or worker state{-}
+{{- end}}
+{{- define "failure"}}iteration {{.Iteration}} at {{duration .Offset}} ({{.Time.Format "15:04:05"}}): {red}{{.Error}}{-}{{end}}`
+
+var _ ui.Outputable = (*ViewContext[FailuresData])(nil)
+
+// Failure is a failed iteration, which failed at Offset into the run.
+type Failure struct {
+	Time      time.Time
+	Iteration string
+	Error     string
+	Offset    time.Duration
+}
+
+// FailureCategory are the iterations which failed in a category, with the 95th percentile of
+// their durations, to tell fast rejections apart from slow timeouts.
+type FailureCategory struct {
+	Category string
+	Count    uint64
+	P95      time.Duration
+}
+
+// IsolatedFailures are the failed iterations of the worker which accounts for most of the Total
+// failures of a run.
+type IsolatedFailures struct {
+	Worker string
+	Count  uint64
+	Total  uint64
+}
+
+// FailuresData are the failed iterations of a run by category, and the first and the last failed
+// iterations, to tell when the iterations started and stopped failing.
+type FailuresData struct {
+	Categories []FailureCategory
+	First      []Failure
+	Last       []Failure
+	// Isolated are the failures of the worker accounting for most failures, if any
+	Isolated *IsolatedFailures
+}
+
+func (d FailuresData) Log(logger *slog.Logger) {
+	for _, category := range d.Categories {
+		logger.Warn("Failed iterations by category",
+			slog.String("category", category.Category),
+			slog.Uint64("count", category.Count),
+			slog.Duration("p95", category.P95),
+		)
+	}
+	for _, failure := range d.First {
+		logFailure(logger, "First failed iteration", failure)
+	}
+	for _, failure := range d.Last {
+		logFailure(logger, "Last failed iteration", failure)
+	}
+	if d.Isolated != nil {
+		logger.Warn("Failures isolated to a worker, suggesting a poisoned connection or worker state",
+			slog.String("worker", d.Isolated.Worker),
+			slog.Uint64("count", d.Isolated.Count),
+			slog.Uint64("total", d.Isolated.Total),
+		)
+	}
+}
+
+func logFailure(logger *slog.Logger, msg string, failure Failure) {
+	logger.Warn(msg,
+		slog.String("iteration", failure.Iteration),
+		slog.Duration("offset", failure.Offset),
+		slog.Time("failed_at", failure.Time),
+		slog.String("error", failure.Error),
+	)
+}
+
+func (v *Views) Failures(data FailuresData) *ViewContext[FailuresData] {
+	return &ViewContext[FailuresData]{
+		view: v.failures,
+		data: data,
+	}
+}
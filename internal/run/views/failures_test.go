@@ -0,0 +1,106 @@
+package views_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/form3tech-oss/f1/v2/internal/log"
+	"github.com/form3tech-oss/f1/v2/internal/run/views"
+)
+
+func Test_RenderFailures(t *testing.T) {
+	t.Parallel()
+
+	failedAt := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+	for _, test := range []struct {
+		name           string
+		data           views.FailuresData
+		expectedOutput string
+		expectedLog    string
+	}{
+		{
+			name: "first failures",
+			data: views.FailuresData{
+				First: []views.Failure{
+					{Iteration: "3", Offset: 1500 * time.Millisecond, Time: failedAt, Error: "timeout"},
+				},
+			},
+			expectedOutput: "First failed iterations:\n" +
+				"  iteration 3 at 1.5s (10:30:00): timeout",
+			expectedLog: "level=WARN msg=\"First failed iteration\" iteration=3 offset=1.5s " +
+				"failed_at=2024-05-01T10:30:00.000Z error=timeout\n",
+		},
+		{
+			name: "first and last failures",
+			data: views.FailuresData{
+				First: []views.Failure{
+					{Iteration: "3", Offset: 1500 * time.Millisecond, Time: failedAt, Error: "timeout"},
+				},
+				Last: []views.Failure{
+					{Iteration: "90", Offset: time.Minute, Time: failedAt.Add(time.Minute), Error: "status 500"},
+				},
+			},
+			expectedOutput: "First failed iterations:\n" +
+				"  iteration 3 at 1.5s (10:30:00): timeout\n" +
+				"Last failed iterations:\n" +
+				"  iteration 90 at 1m0s (10:31:00): status 500",
+			expectedLog: "level=WARN msg=\"First failed iteration\" iteration=3 offset=1.5s " +
+				"failed_at=2024-05-01T10:30:00.000Z error=timeout\n" +
+				"level=WARN msg=\"Last failed iteration\" iteration=90 offset=1m0s " +
+				"failed_at=2024-05-01T10:31:00.000Z error=\"status 500\"\n",
+		},
+		{
+			name: "failures by category",
+			data: views.FailuresData{
+				Categories: []views.FailureCategory{
+					{Category: "5xx", Count: 120, P95: 15 * time.Millisecond},
+					{Category: "timeout", Count: 8, P95: 5 * time.Second},
+				},
+				First: []views.Failure{
+					{Iteration: "3", Offset: 1500 * time.Millisecond, Time: failedAt, Error: "timeout"},
+				},
+			},
+			expectedOutput: "Failures by category:\n" +
+				"  5xx               120 failed, p95 15ms\n" +
+				"  timeout             8 failed, p95 5s\n" +
+				"First failed iterations:\n" +
+				"  iteration 3 at 1.5s (10:30:00): timeout",
+			expectedLog: "level=WARN msg=\"Failed iterations by category\" category=5xx count=120 p95=15ms\n" +
+				"level=WARN msg=\"Failed iterations by category\" category=timeout count=8 p95=5s\n" +
+				"level=WARN msg=\"First failed iteration\" iteration=3 offset=1.5s " +
+				"failed_at=2024-05-01T10:30:00.000Z error=timeout\n",
+		},
+		{
+			name: "failures isolated to a worker",
+			data: views.FailuresData{
+				First: []views.Failure{
+					{Iteration: "3", Offset: 1500 * time.Millisecond, Time: failedAt, Error: "connection reset"},
+				},
+				Isolated: &views.IsolatedFailures{Worker: "trigger-7", Count: 45, Total: 50},
+			},
+			expectedOutput: "First failed iterations:\n" +
+				"  iteration 3 at 1.5s (10:30:00): connection reset\n" +
+				"45 of 50 failures on worker trigger-7, suggesting a poisoned connection or worker state",
+			expectedLog: "level=WARN msg=\"First failed iteration\" iteration=3 offset=1.5s " +
+				"failed_at=2024-05-01T10:30:00.000Z error=\"connection reset\"\n" +
+				"level=WARN msg=\"Failures isolated to a worker, suggesting a poisoned connection or worker state\" " +
+				"worker=trigger-7 count=45 total=50\n",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			view := views.New().Failures(test.data)
+
+			output := view.Render()
+			var logOutput bytes.Buffer
+			view.Log(log.NewTestLogger(&logOutput))
+
+			assert.Equal(t, test.expectedOutput, output)
+			assert.Equal(t, test.expectedLog, logOutput.String())
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package views
+
+import (
+	"log/slog"
+
+	"github.com/form3tech-oss/f1/v2/internal/gcpause"
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+)
+
+const gcPausesTemplate = `{bold}GC pauses of f1:{-} {{.Collections}} collections, {{duration .PauseTotal}} paused in total (max {{duration .MaxPause}})
+{{- if .Iterations}}
+  {{.OverlappedIterations}} of {{.Iterations}} iterations ({{printf "%.2f" (percent .OverlappedIterations .Iterations)}}%) overlapped a collection
+{{- if .OverlappedIterations}}, avg {{duration .OverlappedAverage}} against {{duration .OtherAverage}} for the others{{end}}
+{{- end}}`
+
+var _ ui.Outputable = (*ViewContext[GCPausesData])(nil)
+
+type GCPausesData struct {
+	gcpause.Report
+}
+
+func (d GCPausesData) Log(logger *slog.Logger) {
+	logger.Info("GC pauses",
+		slog.Uint64("collections", uint64(d.Collections)),
+		slog.Duration("pause_total", d.PauseTotal),
+		slog.Duration("max_pause", d.MaxPause),
+		slog.Uint64("iterations", d.Iterations),
+		slog.Uint64("overlapped_iterations", d.OverlappedIterations),
+		slog.Duration("overlapped_average", d.OverlappedAverage),
+		slog.Duration("other_average", d.OtherAverage),
+	)
+}
+
+func (v *Views) GCPauses(data GCPausesData) *ViewContext[GCPausesData] {
+	return &ViewContext[GCPausesData]{
+		view: v.gcPauses,
+		data: data,
+	}
+}
@@ -0,0 +1,78 @@
+package views_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/form3tech-oss/f1/v2/internal/gcpause"
+	"github.com/form3tech-oss/f1/v2/internal/log"
+	"github.com/form3tech-oss/f1/v2/internal/run/views"
+)
+
+func Test_RenderGCPauses(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name     string
+		report   gcpause.Report
+		expected string
+	}{
+		{
+			name: "overlapped iterations",
+			report: gcpause.Report{
+				Collections:          4,
+				PauseTotal:           2 * time.Millisecond,
+				MaxPause:             800 * time.Microsecond,
+				Iterations:           200,
+				OverlappedIterations: 5,
+				OverlappedAverage:    30 * time.Millisecond,
+				OtherAverage:         10 * time.Millisecond,
+			},
+			expected: "GC pauses of f1: 4 collections, 2ms paused in total (max 800µs)\n" +
+				"  5 of 200 iterations (2.50%) overlapped a collection, avg 30ms against 10ms for the others",
+		},
+		{
+			name: "no overlapped iterations",
+			report: gcpause.Report{
+				Iterations:   10,
+				OtherAverage: 10 * time.Millisecond,
+			},
+			expected: "GC pauses of f1: 0 collections, 0s paused in total (max 0s)\n" +
+				"  0 of 10 iterations (0.00%) overlapped a collection",
+		},
+		{
+			name:     "no iterations",
+			report:   gcpause.Report{Collections: 1, PauseTotal: time.Millisecond, MaxPause: time.Millisecond},
+			expected: "GC pauses of f1: 1 collections, 1ms paused in total (max 1ms)",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, views.New().GCPauses(views.GCPausesData{Report: test.report}).Render())
+		})
+	}
+}
+
+func Test_LogGCPauses(t *testing.T) {
+	t.Parallel()
+
+	view := views.New().GCPauses(views.GCPausesData{Report: gcpause.Report{
+		Collections:          1,
+		PauseTotal:           time.Millisecond,
+		MaxPause:             time.Millisecond,
+		Iterations:           2,
+		OverlappedIterations: 1,
+		OverlappedAverage:    20 * time.Millisecond,
+		OtherAverage:         10 * time.Millisecond,
+	}})
+
+	var logOutput bytes.Buffer
+	view.Log(log.NewTestLogger(&logOutput))
+
+	assert.Equal(t, "level=INFO msg=\"GC pauses\" collections=1 pause_total=1ms max_pause=1ms "+
+		"iterations=2 overlapped_iterations=1 overlapped_average=20ms other_average=10ms\n", logOutput.String())
+}
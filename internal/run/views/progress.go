@@ -10,7 +10,7 @@ import (
 )
 
 //nolint:lll // templates read better with long lines
-const progressTemplate = `{cyan}[{{durationSeconds .Duration | printf "%5s"}}]{-}  {green}✔ {{printf "%5d" .SuccessfulIterationCount}}{-}  {{if .DroppedIterationCount}}{yellow}⦸ {{printf "%5d" .DroppedIterationCount}}{-}  {{end}}{red}✘ {{printf "%5d" .FailedIterationCount}}{-} {light_black}({{rate .Period .SuccessfulIterationDurationsForPeriod.Count}}/s){-}   {{.SuccessfulIterationDurationsForPeriod}}`
+const progressTemplate = `{cyan}[{{durationSeconds .Duration | printf "%5s"}}]{-}  {green}✔ {{printf "%5d" .SuccessfulIterationCount}}{-}  {{if .DroppedIterationCount}}{yellow}⦸ {{printf "%5d" .DroppedIterationCount}}{-}  {{end}}{red}✘ {{printf "%5d" .FailedIterationCount}}{-} {light_black}({{rate .Period .SuccessfulIterationDurationsForPeriod.Count}}/s){-}   {{.SuccessfulIterationDurationsForPeriod}}{{range .Gauges}}   {{.Name}}: {{gauge .Value}}{{end}}{{with .Stage}}   {light_black}{{.}}{-}{{end}}`
 
 var _ ui.Outputable = (*ViewContext[ProgressData])(nil)
 
@@ -21,16 +21,33 @@ type ProgressData struct {
 	DroppedIterationCount                 uint64
 	FailedIterationCount                  uint64
 	Period                                time.Duration
+	Gauges                                []progress.Gauge
+	// Stage is the name of the running stage, for triggers which run in stages
+	Stage string
 }
 
 func (d ProgressData) Log(logger *slog.Logger) {
-	logger.Info("progress", log.IterationStatsGroup(
+	attrs := []any{log.IterationStatsGroup(
 		0,
 		d.SuccessfulIterationCount,
 		d.FailedIterationCount,
 		d.DroppedIterationCount,
 		d.Period,
-	))
+	)}
+
+	if len(d.Gauges) > 0 {
+		gauges := make([]any, len(d.Gauges))
+		for i, gauge := range d.Gauges {
+			gauges[i] = slog.Float64(gauge.Name, gauge.Value)
+		}
+		attrs = append(attrs, slog.Group("gauges", gauges...))
+	}
+
+	if d.Stage != "" {
+		attrs = append(attrs, slog.String("stage", d.Stage))
+	}
+
+	logger.Info("progress", attrs...)
 }
 
 func (v *Views) Progress(data ProgressData) *ViewContext[ProgressData] {
@@ -44,6 +44,59 @@ func Test_RenderProgress(t *testing.T) {
 				"iteration_stats.dropped=3 " +
 				"iteration_stats.period=10s\n",
 		},
+		{
+			name: "gauges",
+			data: views.ProgressData{
+				Duration:                 1 * time.Minute,
+				SuccessfulIterationCount: 10,
+				FailedIterationCount:     5,
+				Period:                   10 * time.Second,
+				SuccessfulIterationDurationsForPeriod: progress.IterationDurationsSnapshot{
+					Average: 10 * time.Microsecond,
+					Min:     1 * time.Microsecond,
+					Max:     20 * time.Microsecond,
+					Count:   10,
+				},
+				Gauges: []progress.Gauge{
+					{Name: "accounts created", Value: 120},
+					{Name: "queue depth", Value: 2.345},
+				},
+			},
+			expected: "[ 1m0s]  ✔    10  ✘     5 (1/s)   avg: 10µs, min: 1µs, max: 20µs" +
+				"   accounts created: 120   queue depth: 2.35",
+			expectedLog: "level=INFO msg=progress " +
+				"iteration_stats.started=15 " +
+				"iteration_stats.successful=10 " +
+				"iteration_stats.failed=5 " +
+				"iteration_stats.dropped=0 " +
+				"iteration_stats.period=10s " +
+				"\"gauges.accounts created\"=120 " +
+				"\"gauges.queue depth\"=2.345\n",
+		},
+		{
+			name: "stage",
+			data: views.ProgressData{
+				Duration:                 1 * time.Minute,
+				SuccessfulIterationCount: 10,
+				FailedIterationCount:     5,
+				Period:                   10 * time.Second,
+				SuccessfulIterationDurationsForPeriod: progress.IterationDurationsSnapshot{
+					Average: 10 * time.Microsecond,
+					Min:     1 * time.Microsecond,
+					Max:     20 * time.Microsecond,
+					Count:   10,
+				},
+				Stage: "stage 3/7: black-friday peak",
+			},
+			expected: "[ 1m0s]  ✔    10  ✘     5 (1/s)   avg: 10µs, min: 1µs, max: 20µs   stage 3/7: black-friday peak",
+			expectedLog: "level=INFO msg=progress " +
+				"iteration_stats.started=15 " +
+				"iteration_stats.successful=10 " +
+				"iteration_stats.failed=5 " +
+				"iteration_stats.dropped=0 " +
+				"iteration_stats.period=10s " +
+				"stage=\"stage 3/7: black-friday peak\"\n",
+		},
 		{
 			name: "rate rounding",
 			data: views.ProgressData{
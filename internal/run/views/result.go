@@ -26,9 +26,21 @@ const resultTemplate = `
 {{- if .FailedIterationCount}}
 {bold}Failed Iterations:{-} {red}{{.FailedIterationCount}} ({{percent .FailedIterationCount .Iterations | printf "%0.2f"}}%, {{rate .Duration .FailedIterationCount}}){-} {{.FailedIterationDurations}}
 {{- end}}
+{{- if .PlannedIterations}}
+{bold}Planned Iterations:{-} {{.IterationsStarted}} of {{.PlannedIterations}} started ({{percent .IterationsStarted .PlannedIterations | printf "%0.2f"}}% of the plan)
+{{- end}}
 {{- if .DroppedIterationCount}}
 {bold}Dropped Iterations:{-} {yellow}{{.DroppedIterationCount}} ({{percent .DroppedIterationCount .Iterations | printf "%0.2f"}}%, {{rate .Duration .DroppedIterationCount}}){-} (consider increasing --concurrency setting)
 {{- end}}
+{{- if or .BytesSent .BytesReceived}}
+{bold}Throughput:{-} {{size .BytesSent}} sent ({{rate .Duration .BytesSent | size}}/second), {{size .BytesReceived}} received ({{rate .Duration .BytesReceived | size}}/second)
+{{- end}}
+{{- if .MetricsPushFailures}}
+{bold}Failed Metrics Pushes:{-} {yellow}{{.MetricsPushFailures}}{-} (the metrics of the run have gaps)
+{{- end}}
+{{- if or .SetupDuration .TeardownDuration}}
+{bold}Setup:{-} {{duration .SetupDuration}}, {bold}teardown:{-} {{duration .TeardownDuration}} (not included in the iteration rates)
+{{- end}}
 {bold}Full logs:{-} {{.LogFilePath}}
 `
 
@@ -40,12 +52,23 @@ type ResultData struct {
 	SuccessfulIterationDurations progress.IterationDurationsSnapshot
 	FailedIterationDurations     progress.IterationDurationsSnapshot
 	IterationsStarted            uint64
-	Duration                     time.Duration
-	SuccessfulIterationCount     uint64
-	Iterations                   uint64
-	FailedIterationCount         uint64
-	DroppedIterationCount        uint64
-	Failed                       bool
+	// Duration is the duration of the load phase, excluding setup and teardown
+	Duration                 time.Duration
+	SetupDuration            time.Duration
+	TeardownDuration         time.Duration
+	SuccessfulIterationCount uint64
+	Iterations               uint64
+	FailedIterationCount     uint64
+	DroppedIterationCount    uint64
+	// MetricsPushFailures is the number of pushes of metrics to the push gateway which failed
+	MetricsPushFailures uint64
+	Failed              bool
+	// PlannedIterations is the number of iterations the trigger planned to start over the load of
+	// the run, 0 for triggers which don't plan them
+	PlannedIterations uint64
+	// BytesSent and BytesReceived are the bytes recorded by the iterations, see testing.T.RecordBytes
+	BytesSent     uint64
+	BytesReceived uint64
 }
 
 func (d ResultData) Log(logger *slog.Logger) {
@@ -56,15 +79,25 @@ func (d ResultData) Log(logger *slog.Logger) {
 		d.DroppedIterationCount,
 		d.Duration,
 	)
+	attrs := []any{stats}
+	if d.MetricsPushFailures > 0 {
+		attrs = append(attrs, slog.Uint64("metrics_push_failures", d.MetricsPushFailures))
+	}
+	if d.PlannedIterations > 0 {
+		attrs = append(attrs, slog.Uint64("iterations_planned", d.PlannedIterations))
+	}
+	if d.BytesSent > 0 || d.BytesReceived > 0 {
+		attrs = append(attrs, slog.Uint64("bytes_sent", d.BytesSent), slog.Uint64("bytes_received", d.BytesReceived))
+	}
 
 	if d.Failed {
 		if d.Error != nil {
-			logger.Error("Load Test Failed", log.ErrorAttr(d.Error), stats)
+			logger.Error("Load Test Failed", append([]any{log.ErrorAttr(d.Error)}, attrs...)...)
 		} else {
-			logger.Error("Load Test Failed", stats)
+			logger.Error("Load Test Failed", attrs...)
 		}
 	} else {
-		logger.Info("Load Test Passed", stats)
+		logger.Info("Load Test Passed", attrs...)
 	}
 }
 
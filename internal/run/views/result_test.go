@@ -126,6 +126,39 @@ func Test_RenderResult(t *testing.T) {
 				"iteration_stats.dropped=0 " +
 				"iteration_stats.period=1s\n",
 		},
+		{
+			name: "passed with slow setup and teardown",
+			data: views.ResultData{
+				Failed:                   false,
+				IterationsStarted:        20,
+				Duration:                 1 * time.Second,
+				SetupDuration:            1500 * time.Millisecond,
+				TeardownDuration:         200 * time.Millisecond,
+				SuccessfulIterationCount: 20,
+				Iterations:               20,
+				SuccessfulIterationDurations: progress.IterationDurationsSnapshot{
+					Min:     1 * time.Microsecond,
+					Average: 2 * time.Microsecond,
+					Max:     3 * time.Microsecond,
+				},
+				FailedIterationDurations: progress.IterationDurationsSnapshot{},
+				LogFilePath:              "log/file/path.log",
+				Error:                    nil,
+				FailedIterationCount:     0,
+				DroppedIterationCount:    0,
+			},
+			expected: "\nLoad Test Passed\n" +
+				"20 iterations started in 1s (20/second)\n" +
+				"Successful Iterations: 20 (100.00%, 20/second) avg: 2µs, min: 1µs, max: 3µs\n" +
+				"Setup: 1.5s, teardown: 200ms (not included in the iteration rates)\n" +
+				"Full logs: log/file/path.log\n",
+			expectedLog: "level=INFO msg=\"Load Test Passed\" " +
+				"iteration_stats.started=20 " +
+				"iteration_stats.successful=20 " +
+				"iteration_stats.failed=0 " +
+				"iteration_stats.dropped=0 " +
+				"iteration_stats.period=1s\n",
+		},
 		{
 			name: "passed with dropped iterations",
 			data: views.ResultData{
@@ -157,6 +190,64 @@ func Test_RenderResult(t *testing.T) {
 				"iteration_stats.dropped=10 " +
 				"iteration_stats.period=1s\n",
 		},
+		{
+			name: "passed with failed metrics pushes",
+			data: views.ResultData{
+				IterationsStarted:        20,
+				Duration:                 1 * time.Second,
+				SuccessfulIterationCount: 20,
+				Iterations:               20,
+				SuccessfulIterationDurations: progress.IterationDurationsSnapshot{
+					Min:     1 * time.Microsecond,
+					Average: 2 * time.Microsecond,
+					Max:     3 * time.Microsecond,
+				},
+				MetricsPushFailures: 2,
+				LogFilePath:         "log/file/path.log",
+			},
+			expected: "\nLoad Test Passed\n" +
+				"20 iterations started in 1s (20/second)\n" +
+				"Successful Iterations: 20 (100.00%, 20/second) avg: 2µs, min: 1µs, max: 3µs\n" +
+				"Failed Metrics Pushes: 2 (the metrics of the run have gaps)\n" +
+				"Full logs: log/file/path.log\n",
+			expectedLog: "level=INFO msg=\"Load Test Passed\" " +
+				"iteration_stats.started=20 " +
+				"iteration_stats.successful=20 " +
+				"iteration_stats.failed=0 " +
+				"iteration_stats.dropped=0 " +
+				"iteration_stats.period=1s " +
+				"metrics_push_failures=2\n",
+		},
+		{
+			name: "passed with bytes sent and received",
+			data: views.ResultData{
+				IterationsStarted:        20,
+				Duration:                 2 * time.Second,
+				SuccessfulIterationCount: 20,
+				Iterations:               20,
+				SuccessfulIterationDurations: progress.IterationDurationsSnapshot{
+					Min:     1 * time.Microsecond,
+					Average: 2 * time.Microsecond,
+					Max:     3 * time.Microsecond,
+				},
+				BytesSent:     20 << 20,
+				BytesReceived: 20 * 512,
+				LogFilePath:   "log/file/path.log",
+			},
+			expected: "\nLoad Test Passed\n" +
+				"20 iterations started in 2s (10/second)\n" +
+				"Successful Iterations: 20 (100.00%, 10/second) avg: 2µs, min: 1µs, max: 3µs\n" +
+				"Throughput: 20.0MiB sent (10.0MiB/second), 10.0KiB received (5.0KiB/second)\n" +
+				"Full logs: log/file/path.log\n",
+			expectedLog: "level=INFO msg=\"Load Test Passed\" " +
+				"iteration_stats.started=20 " +
+				"iteration_stats.successful=20 " +
+				"iteration_stats.failed=0 " +
+				"iteration_stats.dropped=0 " +
+				"iteration_stats.period=2s " +
+				"bytes_sent=20971520 " +
+				"bytes_received=10240\n",
+		},
 	}
 
 	v := views.New()
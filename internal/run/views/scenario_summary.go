@@ -0,0 +1,32 @@
+package views
+
+import (
+	"log/slog"
+
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+)
+
+const scenarioSummaryTemplate = `{bold}Scenario summary:{-}
+{{- range .Lines}}
+  {{.}}
+{{- end}}`
+
+var _ ui.Outputable = (*ViewContext[ScenarioSummaryData])(nil)
+
+// ScenarioSummaryData are the lines added to the summary of the run by the scenario.
+type ScenarioSummaryData struct {
+	Lines []string
+}
+
+func (d ScenarioSummaryData) Log(logger *slog.Logger) {
+	for _, line := range d.Lines {
+		logger.Info("Scenario summary", slog.String("summary", line))
+	}
+}
+
+func (v *Views) ScenarioSummary(data ScenarioSummaryData) *ViewContext[ScenarioSummaryData] {
+	return &ViewContext[ScenarioSummaryData]{
+		view: v.scenarioSummary,
+		data: data,
+	}
+}
@@ -0,0 +1,29 @@
+package views_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/form3tech-oss/f1/v2/internal/log"
+	"github.com/form3tech-oss/f1/v2/internal/run/views"
+)
+
+func Test_RenderScenarioSummary(t *testing.T) {
+	t.Parallel()
+
+	view := views.New().ScenarioSummary(views.ScenarioSummaryData{
+		Lines: []string{"created 10000 accounts", "deleted 9998 accounts"},
+	})
+
+	output := view.Render()
+	var logOutput bytes.Buffer
+	view.Log(log.NewTestLogger(&logOutput))
+
+	assert.Equal(t, "Scenario summary:\n"+
+		"  created 10000 accounts\n"+
+		"  deleted 9998 accounts", output)
+	assert.Equal(t, "level=INFO msg=\"Scenario summary\" summary=\"created 10000 accounts\"\n"+
+		"level=INFO msg=\"Scenario summary\" summary=\"deleted 9998 accounts\"\n", logOutput.String())
+}
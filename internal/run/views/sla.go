@@ -0,0 +1,68 @@
+package views
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+)
+
+//nolint:lll // templates read better with long lines
+const slaTemplate = `{bold}SLA compliance:{-}
+{{- range .Operations}}
+  {{printf "%-*s" $.OperationWidth .Operation}}  {{if .Violations}}{red}breached{-}{{else}}{green}met     {-}{{end}}  {{.Iterations}} iterations, p95 {{duration .P95}}{{with .MaxP95}} (max {{duration .}}){{end}}, p99 {{duration .P99}}{{with .MaxP99}} (max {{duration .}}){{end}}, error rate {{printf "%0.2f" .ErrorRate}}%{{if .HasMaxErrorRate}} (max {{printf "%0.2f" .MaxErrorRate}}%){{end}}
+{{- end}}`
+
+var _ ui.Outputable = (*ViewContext[SLAData])(nil)
+
+// OperationCompliance is an operation of the run evaluated against its objectives in the SLA of
+// the run, with the objectives it did not meet as its Violations.
+type OperationCompliance struct {
+	Operation  string
+	Iterations uint64
+	P95        time.Duration
+	P99        time.Duration
+	// ErrorRate is the percentage of the iterations which failed the operation
+	ErrorRate float64
+	// MaxP95 and MaxP99 are the objectives of the operation, if set, and MaxErrorRate if
+	// HasMaxErrorRate is set
+	MaxP95          time.Duration
+	MaxP99          time.Duration
+	MaxErrorRate    float64
+	HasMaxErrorRate bool
+	Violations      []string
+}
+
+type SLAData struct {
+	Operations []OperationCompliance
+	// OperationWidth is the length of the longest operation name, to align the table
+	OperationWidth int
+}
+
+func (d SLAData) Log(logger *slog.Logger) {
+	for _, operation := range d.Operations {
+		attrs := []any{
+			slog.String("operation", operation.Operation),
+			slog.Uint64("iterations", operation.Iterations),
+			slog.Duration("p95", operation.P95),
+			slog.Duration("p99", operation.P99),
+			slog.Float64("error_rate", operation.ErrorRate),
+		}
+		if len(operation.Violations) > 0 {
+			logger.Warn("SLA breached", append(attrs, slog.Any("violations", operation.Violations))...)
+			continue
+		}
+		logger.Info("SLA met", attrs...)
+	}
+}
+
+func (v *Views) SLA(data SLAData) *ViewContext[SLAData] {
+	for _, operation := range data.Operations {
+		data.OperationWidth = max(data.OperationWidth, len(operation.Operation))
+	}
+
+	return &ViewContext[SLAData]{
+		view: v.sla,
+		data: data,
+	}
+}
@@ -0,0 +1,51 @@
+package views_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/form3tech-oss/f1/v2/internal/log"
+	"github.com/form3tech-oss/f1/v2/internal/run/views"
+)
+
+func Test_RenderSLA(t *testing.T) {
+	t.Parallel()
+
+	view := views.New().SLA(views.SLAData{
+		Operations: []views.OperationCompliance{
+			{
+				Operation:       "create",
+				Iterations:      200,
+				P95:             80 * time.Millisecond,
+				P99:             120 * time.Millisecond,
+				ErrorRate:       0.5,
+				MaxP99:          500 * time.Millisecond,
+				MaxErrorRate:    1,
+				HasMaxErrorRate: true,
+			},
+			{
+				Operation:  "list",
+				Iterations: 100,
+				P95:        400 * time.Millisecond,
+				P99:        700 * time.Millisecond,
+				MaxP95:     300 * time.Millisecond,
+				Violations: []string{"p95 400ms above 300ms"},
+			},
+		},
+	})
+
+	output := view.Render()
+	var logOutput bytes.Buffer
+	view.Log(log.NewTestLogger(&logOutput))
+
+	assert.Equal(t, "SLA compliance:\n"+
+		"  create  met       200 iterations, p95 80ms, p99 120ms (max 500ms), error rate 0.50% (max 1.00%)\n"+
+		"  list    breached  100 iterations, p95 400ms (max 300ms), p99 700ms, error rate 0.00%", output)
+	assert.Equal(t, "level=INFO msg=\"SLA met\" operation=create iterations=200 p95=80ms p99=120ms "+
+		"error_rate=0.5\n"+
+		"level=WARN msg=\"SLA breached\" operation=list iterations=100 p95=400ms p99=700ms error_rate=0 "+
+		"violations=\"[p95 400ms above 300ms]\"\n", logOutput.String())
+}
@@ -0,0 +1,49 @@
+package views
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+)
+
+const slowestTemplate = `{bold}Slowest iterations:{-}
+{{- range .Iterations}}
+  iteration {{.Iteration}} took {yellow}{{duration .Duration}}{-}, started at {{duration .Offset}} ({{.Time.Format "15:04:05"}})
+{{- with .Worker}} on {{.}}{{end}}
+{{- end}}`
+
+var _ ui.Outputable = (*ViewContext[SlowestData])(nil)
+
+// SlowIteration is one of the slowest iterations of a run, which started at Offset into the run.
+type SlowIteration struct {
+	Time      time.Time
+	Iteration string
+	Worker    string
+	Duration  time.Duration
+	Offset    time.Duration
+}
+
+// SlowestData are the slowest iterations of a run, from the slowest.
+type SlowestData struct {
+	Iterations []SlowIteration
+}
+
+func (d SlowestData) Log(logger *slog.Logger) {
+	for _, iteration := range d.Iterations {
+		logger.Info("Slow iteration",
+			slog.String("iteration", iteration.Iteration),
+			slog.Duration("duration", iteration.Duration),
+			slog.Duration("offset", iteration.Offset),
+			slog.Time("started_at", iteration.Time),
+			slog.String("worker", iteration.Worker),
+		)
+	}
+}
+
+func (v *Views) Slowest(data SlowestData) *ViewContext[SlowestData] {
+	return &ViewContext[SlowestData]{
+		view: v.slowest,
+		data: data,
+	}
+}
@@ -0,0 +1,36 @@
+package views_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/form3tech-oss/f1/v2/internal/log"
+	"github.com/form3tech-oss/f1/v2/internal/run/views"
+)
+
+func Test_RenderSlowest(t *testing.T) {
+	t.Parallel()
+
+	startedAt := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+	view := views.New().Slowest(views.SlowestData{
+		Iterations: []views.SlowIteration{
+			{Iteration: "42", Duration: 2 * time.Second, Offset: 12 * time.Second, Time: startedAt, Worker: "trigger-3"},
+			{Iteration: "7", Duration: 900 * time.Millisecond, Offset: time.Second, Time: startedAt.Add(-11 * time.Second)},
+		},
+	})
+
+	output := view.Render()
+	var logOutput bytes.Buffer
+	view.Log(log.NewTestLogger(&logOutput))
+
+	assert.Equal(t, "Slowest iterations:\n"+
+		"  iteration 42 took 2s, started at 12s (10:30:00) on trigger-3\n"+
+		"  iteration 7 took 900ms, started at 1s (10:29:49)", output)
+	assert.Equal(t, "level=INFO msg=\"Slow iteration\" iteration=42 duration=2s offset=12s "+
+		"started_at=2024-05-01T10:30:00.000Z worker=trigger-3\n"+
+		"level=INFO msg=\"Slow iteration\" iteration=7 duration=900ms offset=1s "+
+		"started_at=2024-05-01T10:29:49.000Z worker=\"\"\n", logOutput.String())
+}
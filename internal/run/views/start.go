@@ -10,7 +10,7 @@ import (
 
 //nolint:lll // templates read better with long lines
 const startTemplate = `{u}{bold}{intensive_blue}F1 Load Tester{-}
-Running {yellow}{{.Scenario}}{-} scenario for {{if .MaxIterations}}up to {{.MaxIterations}} iterations or up to {{end}}{{duration .MaxDuration}} at a rate of {{.RateDescription}}.
+Running {yellow}{{.Scenario}}{-} scenario {{if .Endless}}{{if .MaxIterations}}for up to {{.MaxIterations}} iterations or {{end}}until stopped{{else}}for {{if .MaxIterations}}up to {{.MaxIterations}} iterations or up to {{end}}{{duration .MaxDuration}}{{end}} at a rate of {{.RateDescription}}.
 `
 
 var _ ui.Outputable = (*ViewContext[StartData])(nil)
@@ -20,15 +20,25 @@ type StartData struct {
 	RateDescription string
 	MaxIterations   uint64
 	MaxDuration     time.Duration
+	Endless         bool
 }
 
 func (c StartData) Log(logger *slog.Logger) {
-	message := "Running " + c.Scenario + " for "
+	message := "Running " + c.Scenario + " "
 	if c.MaxIterations > 0 {
-		message += "up to " + strconv.FormatUint(c.MaxIterations, 10) + " iterations or up to "
+		message += "for up to " + strconv.FormatUint(c.MaxIterations, 10) + " iterations or "
+		if !c.Endless {
+			message += "up to "
+		}
+	} else if !c.Endless {
+		message += "for "
 	}
 
-	message += c.MaxDuration.String()
+	if c.Endless {
+		message += "until stopped"
+	} else {
+		message += c.MaxDuration.String()
+	}
 	message += " at a rate of " + c.RateDescription
 
 	logger.Info(message)
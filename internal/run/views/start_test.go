@@ -44,6 +44,30 @@ func Test_RenderStart(t *testing.T) {
 				"Running scenarioName scenario for 1m0s at a rate of rate-description.\n",
 			expectedLog: "level=INFO msg=\"Running scenarioName for 1m0s at a rate of rate-description\"\n",
 		},
+		{
+			name: "endless",
+			data: views.StartData{
+				Scenario:        "scenarioName",
+				MaxDuration:     1 * time.Minute,
+				RateDescription: "rate-description",
+				Endless:         true,
+			},
+			expected: "F1 Load Tester\n" +
+				"Running scenarioName scenario until stopped at a rate of rate-description.\n",
+			expectedLog: "level=INFO msg=\"Running scenarioName until stopped at a rate of rate-description\"\n",
+		},
+		{
+			name: "endless with MaxIterations",
+			data: views.StartData{
+				Scenario:        "scenarioName",
+				RateDescription: "rate-description",
+				MaxIterations:   10,
+				Endless:         true,
+			},
+			expected: "F1 Load Tester\n" +
+				"Running scenarioName scenario for up to 10 iterations or until stopped at a rate of rate-description.\n",
+			expectedLog: "level=INFO msg=\"Running scenarioName for up to 10 iterations or until stopped at a rate of rate-description\"\n",
+		},
 	}
 
 	v := views.New()
@@ -0,0 +1,38 @@
+package views
+
+import (
+	"log/slog"
+
+	"github.com/form3tech-oss/f1/v2/internal/targetmetrics"
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+)
+
+const targetMetricsTemplate = `{bold}Target metrics:{-}
+{{- range .Metrics}}
+  {{.Name}}{{with .Series}} {{.}}{{end}}: min {{printf "%.4g" .Min}}, avg {{printf "%.4g" .Avg}}, max {{printf "%.4g" .Max}}
+{{- end}}`
+
+var _ ui.Outputable = (*ViewContext[TargetMetricsData])(nil)
+
+type TargetMetricsData struct {
+	Metrics []targetmetrics.Metric
+}
+
+func (d TargetMetricsData) Log(logger *slog.Logger) {
+	for _, metric := range d.Metrics {
+		logger.Info("Target metric",
+			slog.String("name", metric.Name),
+			slog.String("series", metric.Series),
+			slog.Float64("min", metric.Min),
+			slog.Float64("avg", metric.Avg),
+			slog.Float64("max", metric.Max),
+		)
+	}
+}
+
+func (v *Views) TargetMetrics(data TargetMetricsData) *ViewContext[TargetMetricsData] {
+	return &ViewContext[TargetMetricsData]{
+		view: v.targetMetrics,
+		data: data,
+	}
+}
@@ -0,0 +1,35 @@
+package views_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/form3tech-oss/f1/v2/internal/log"
+	"github.com/form3tech-oss/f1/v2/internal/run/views"
+	"github.com/form3tech-oss/f1/v2/internal/targetmetrics"
+)
+
+func Test_RenderTargetMetrics(t *testing.T) {
+	t.Parallel()
+
+	view := views.New().TargetMetrics(views.TargetMetricsData{
+		Metrics: []targetmetrics.Metric{
+			{Name: "cpu", Series: `{pod="payments-1"}`, Min: 0.5, Avg: 1.25, Max: 2},
+			{Name: "memory", Min: 1.5e9, Avg: 1.75e9, Max: 2e9},
+		},
+	})
+
+	output := view.Render()
+	var logOutput bytes.Buffer
+	view.Log(log.NewTestLogger(&logOutput))
+
+	assert.Equal(t, "Target metrics:\n"+
+		"  cpu {pod=\"payments-1\"}: min 0.5, avg 1.25, max 2\n"+
+		"  memory: min 1.5e+09, avg 1.75e+09, max 2e+09", output)
+	assert.Equal(t,
+		"level=INFO msg=\"Target metric\" name=cpu series=\"{pod=\\\"payments-1\\\"}\" min=0.5 avg=1.25 max=2\n"+
+			"level=INFO msg=\"Target metric\" name=memory series=\"\" min=1.5e+09 avg=1.75e+09 max=2e+09\n",
+		logOutput.String())
+}
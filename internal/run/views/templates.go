@@ -2,10 +2,12 @@ package views
 
 import (
 	"math"
+	"strconv"
 	"strings"
 	"text/template"
 	"time"
 
+	"github.com/form3tech-oss/f1/v2/internal/memory"
 	"github.com/form3tech-oss/f1/v2/internal/termcolor"
 )
 
@@ -25,10 +27,22 @@ type templates struct {
 	timeout              *template.Template
 	maxIterationsReached *template.Template
 	interrupt            *template.Template
+	stopped              *template.Template
+	comparison           *template.Template
+	targetMetrics        *template.Template
+	gcPauses             *template.Template
+	drops                *template.Template
+	failures             *template.Template
+	slowest              *template.Template
+	scenarioSummary      *template.Template
+	sla                  *template.Template
+	artifacts            *template.Template
 }
 
-func parseTemplates(renderTermColors renderTermColorsType) *templates {
-	templateFunctions := template.FuncMap{
+// templateFunctions are the functions of the templates, where var and vars read the variables of the
+// run, such as its run ID.
+func templateFunctions(variables map[string]string) template.FuncMap {
+	return template.FuncMap{
 		"rate": func(duration time.Duration, count uint64) uint64 {
 			durationInSeconds := duration.Round(time.Second).Seconds()
 
@@ -47,8 +61,27 @@ func parseTemplates(renderTermColors renderTermColorsType) *templates {
 		"percent": func(val, total uint64) float64 {
 			return 100.0 * float64(val) / float64(total)
 		},
+		"size": memory.FormatSize,
+		"gauge": func(value float64) string {
+			return strconv.FormatFloat(math.Round(value*100)/100, 'f', -1, 64)
+		},
+		"signedDuration": func(d time.Duration) string {
+			if d >= 0 {
+				return "+" + d.String()
+			}
+			return d.String()
+		},
+		"var": func(name string) string {
+			return variables[name]
+		},
+		"vars": func() map[string]string {
+			return variables
+		},
 	}
+}
 
+func parseTemplates(renderTermColors renderTermColorsType, variables map[string]string) *templates {
+	templateFunctions := templateFunctions(variables)
 	replacements := termReplacements(renderTermColors)
 
 	start := template.Must(template.New("start").
@@ -83,6 +116,46 @@ func parseTemplates(renderTermColors renderTermColorsType) *templates {
 		Funcs(templateFunctions).
 		Parse(applyReplacements(interruptTemplate, replacements)))
 
+	stopped := template.Must(template.New("stopped").
+		Funcs(templateFunctions).
+		Parse(applyReplacements(stoppedTemplate, replacements)))
+
+	comparison := template.Must(template.New("comparison").
+		Funcs(templateFunctions).
+		Parse(applyReplacements(comparisonTemplate, replacements)))
+
+	targetMetrics := template.Must(template.New("targetMetrics").
+		Funcs(templateFunctions).
+		Parse(applyReplacements(targetMetricsTemplate, replacements)))
+
+	gcPauses := template.Must(template.New("gcPauses").
+		Funcs(templateFunctions).
+		Parse(applyReplacements(gcPausesTemplate, replacements)))
+
+	drops := template.Must(template.New("drops").
+		Funcs(templateFunctions).
+		Parse(applyReplacements(dropsTemplate, replacements)))
+
+	failures := template.Must(template.New("failures").
+		Funcs(templateFunctions).
+		Parse(applyReplacements(failuresTemplate, replacements)))
+
+	slowest := template.Must(template.New("slowest").
+		Funcs(templateFunctions).
+		Parse(applyReplacements(slowestTemplate, replacements)))
+
+	scenarioSummary := template.Must(template.New("scenarioSummary").
+		Funcs(templateFunctions).
+		Parse(applyReplacements(scenarioSummaryTemplate, replacements)))
+
+	sla := template.Must(template.New("sla").
+		Funcs(templateFunctions).
+		Parse(applyReplacements(slaTemplate, replacements)))
+
+	artifacts := template.Must(template.New("artifacts").
+		Funcs(templateFunctions).
+		Parse(applyReplacements(artifactsTemplate, replacements)))
+
 	return &templates{
 		start:                start,
 		result:               result,
@@ -92,6 +165,16 @@ func parseTemplates(renderTermColors renderTermColorsType) *templates {
 		timeout:              timeout,
 		maxIterationsReached: maxIterationsReached,
 		interrupt:            interrupt,
+		stopped:              stopped,
+		comparison:           comparison,
+		targetMetrics:        targetMetrics,
+		gcPauses:             gcPauses,
+		drops:                drops,
+		failures:             failures,
+		slowest:              slowest,
+		scenarioSummary:      scenarioSummary,
+		sla:                  sla,
+		artifacts:            artifacts,
 	}
 }
 
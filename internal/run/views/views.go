@@ -17,6 +17,9 @@ type ViewContext[T log.Loggable] struct {
 }
 
 func (vc *ViewContext[T]) Render() string {
+	if vc.view.builtin != nil {
+		return renderCustom(vc.view, vc.data)
+	}
 	return render(vc.view.Template(), vc.data)
 }
 
@@ -37,11 +40,23 @@ type Views struct {
 	timeout              *View
 	maxIterationsReached *View
 	interrupt            *View
+	stopped              *View
+	comparison           *View
+	targetMetrics        *View
+	gcPauses             *View
+	drops                *View
+	failures             *View
+	slowest              *View
+	scenarioSummary      *View
+	sla                  *View
+	artifacts            *View
 }
 
 type View struct {
 	tty   *template.Template
 	notty *template.Template
+	// builtin is the view replaced by a custom template, which is rendered if the custom template fails
+	builtin *View
 }
 
 func (v *View) Template() *template.Template {
@@ -52,9 +67,14 @@ func (v *View) Template() *template.Template {
 	return v.notty
 }
 
+// New returns the views with the built-in templates, see NewCustom.
 func New() *Views {
-	tty := parseTemplates(renderTermColorsEnabled)
-	notty := parseTemplates(renderTermColorsDisabled)
+	return newViews(nil)
+}
+
+func newViews(variables map[string]string) *Views {
+	tty := parseTemplates(renderTermColorsEnabled, variables)
+	notty := parseTemplates(renderTermColorsDisabled, variables)
 
 	return &Views{
 		start: &View{
@@ -89,5 +109,45 @@ func New() *Views {
 			tty:   tty.interrupt,
 			notty: notty.interrupt,
 		},
+		stopped: &View{
+			tty:   tty.stopped,
+			notty: notty.stopped,
+		},
+		comparison: &View{
+			tty:   tty.comparison,
+			notty: notty.comparison,
+		},
+		targetMetrics: &View{
+			tty:   tty.targetMetrics,
+			notty: notty.targetMetrics,
+		},
+		gcPauses: &View{
+			tty:   tty.gcPauses,
+			notty: notty.gcPauses,
+		},
+		drops: &View{
+			tty:   tty.drops,
+			notty: notty.drops,
+		},
+		failures: &View{
+			tty:   tty.failures,
+			notty: notty.failures,
+		},
+		slowest: &View{
+			tty:   tty.slowest,
+			notty: notty.slowest,
+		},
+		scenarioSummary: &View{
+			tty:   tty.scenarioSummary,
+			notty: notty.scenarioSummary,
+		},
+		sla: &View{
+			tty:   tty.sla,
+			notty: notty.sla,
+		},
+		artifacts: &View{
+			tty:   tty.artifacts,
+			notty: notty.artifacts,
+		},
 	}
 }
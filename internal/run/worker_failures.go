@@ -0,0 +1,61 @@
+package run
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/form3tech-oss/f1/v2/internal/progress"
+	"github.com/form3tech-oss/f1/v2/internal/run/views"
+)
+
+// minIsolatedFailures avoids reporting a few failures of a worker as isolated to the worker.
+const minIsolatedFailures = 10
+
+// WorkerFailuresReport is the number of iterations which failed on a worker.
+type WorkerFailuresReport struct {
+	Worker   string `json:"worker"`
+	Failures uint64 `json:"failures"`
+}
+
+// workerFailures returns the workers with the most failures of the latest snapshot, by decreasing
+// number of failures.
+func (r *Result) workerFailures() []WorkerFailuresReport {
+	if len(r.snapshot.WorkerFailures) == 0 {
+		return nil
+	}
+
+	workers := make([]WorkerFailuresReport, 0, len(r.snapshot.WorkerFailures))
+	for _, worker := range r.snapshot.WorkerFailures {
+		workers = append(workers, WorkerFailuresReport{Worker: worker.Worker, Failures: worker.Count})
+	}
+
+	return workers
+}
+
+// isolatedFailures returns the worker which accounts for most of the failures of the run, which
+// suggests a poisoned connection or state kept by the worker rather than failures of the target, or
+// nil if failures are spread across workers or the run has a single worker.
+func (r *Result) isolatedFailures() *views.IsolatedFailures {
+	failed := r.snapshot.FailedIterationDurations.Count
+	if r.runOptions.Concurrency <= 1 || len(r.snapshot.WorkerFailures) == 0 || failed < minIsolatedFailures {
+		return nil
+	}
+
+	worker := r.snapshot.WorkerFailures[0]
+	if worker.Count*2 <= failed {
+		return nil
+	}
+
+	return &views.IsolatedFailures{Worker: worker.Worker, Count: worker.Count, Total: failed}
+}
+
+// combineWorkerFailures keeps the workers with the most failures of runs. Workers of runs executed
+// in parallel are distinct even when they have the same name, so they are not merged.
+func combineWorkerFailures(a, b []WorkerFailuresReport) []WorkerFailuresReport {
+	combined := slices.Concat(a, b)
+	slices.SortStableFunc(combined, func(a, b WorkerFailuresReport) int {
+		return cmp.Compare(b.Failures, a.Failures)
+	})
+
+	return combined[:min(len(combined), progress.WorkersWithMostFailuresKept)]
+}
@@ -0,0 +1,27 @@
+package run
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/trigger/rate"
+	"github.com/form3tech-oss/f1/v2/internal/triggerflags"
+)
+
+// parseWorkerRateLimit parses the rate of --worker-rate-limit into the shortest interval between the
+// iterations of a worker, 0 if unset.
+func parseWorkerRateLimit(workerRateLimit string) (time.Duration, error) {
+	if workerRateLimit == "" {
+		return 0, nil
+	}
+
+	iterations, unit, err := rate.ParseRate(workerRateLimit)
+	if err != nil {
+		return 0, fmt.Errorf("parsing --%s: %w", triggerflags.FlagWorkerRateLimit, err)
+	}
+	if iterations == 0 {
+		return 0, fmt.Errorf("--%s %s must be positive", triggerflags.FlagWorkerRateLimit, workerRateLimit)
+	}
+
+	return unit / time.Duration(iterations), nil
+}
@@ -0,0 +1,178 @@
+// Package scenariogen generates the main.go of a scenario binary, registering all the functions
+// of a module which match the signature of scenarios, so that the registrations don't drift from
+// the scenarios as suites grow.
+package scenariogen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// ErrNoScenarios is returned when none of the packages scanned has a scenario.
+var ErrNoScenarios = errors.New("no scenarios found")
+
+var mainTemplate = template.Must(template.New("main").Parse(`// Code generated by f1gen. DO NOT EDIT.
+
+package main
+
+import (
+	"github.com/form3tech-oss/f1/v2/pkg/f1"
+{{range .Imports}}
+	{{with .Alias}}{{.}} {{end}}{{printf "%q" .Path}}
+{{- end}}
+)
+
+func main() {
+	f1.New().
+{{- range .Scenarios}}
+		Add({{printf "%q" .Name}}, {{.Ref}}).
+{{- end}}
+		Execute()
+}
+`))
+
+type generatedImport struct {
+	// Alias is the name of the import, if it isn't the last element of its path
+	Alias string
+	Path  string
+}
+
+type registration struct {
+	Name string
+	// Ref is the function of the scenario, qualified by its import name
+	Ref string
+}
+
+// Find returns the scenarios of the packages matched by patterns, relative to dir, the directory
+// of the main package they are registered in. Patterns are directories, or directories followed by
+// /... to include all the packages below them. Scenarios are sorted by name.
+func Find(dir string, patterns []string) ([]Scenario, error) {
+	mainDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("resolving %s: %w", dir, err)
+	}
+	mod, err := findModule(mainDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var scenarios []Scenario
+	scanned := map[string]bool{}
+	for _, pattern := range patterns {
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(mainDir, pattern)
+		}
+		dirs, err := packageDirs(pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, packageDir := range dirs {
+			if scanned[packageDir] {
+				continue
+			}
+			scanned[packageDir] = true
+
+			found, packageName, err := scanPackage(packageDir)
+			if err != nil {
+				return nil, err
+			}
+			if packageDir == mainDir {
+				scenarios = append(scenarios, found...)
+				continue
+			}
+			// main packages can't be imported, such as the scenario binaries of other suites
+			if packageName == "main" || len(found) == 0 {
+				continue
+			}
+
+			importPath, err := mod.importPath(packageDir)
+			if err != nil {
+				return nil, err
+			}
+			for i := range found {
+				found[i].ImportPath = importPath
+			}
+			scenarios = append(scenarios, found...)
+		}
+	}
+
+	if len(scenarios) == 0 {
+		return nil, fmt.Errorf("%w in %s", ErrNoScenarios, strings.Join(patterns, ", "))
+	}
+
+	slices.SortFunc(scenarios, func(a, b Scenario) int { return strings.Compare(a.Name, b.Name) })
+	for i := 1; i < len(scenarios); i++ {
+		if scenarios[i].Name == scenarios[i-1].Name {
+			return nil, fmt.Errorf("scenario %q is registered by both %s and %s", scenarios[i].Name,
+				scenarios[i-1].qualifiedFunc(), scenarios[i].qualifiedFunc())
+		}
+	}
+
+	return scenarios, nil
+}
+
+func (s Scenario) qualifiedFunc() string {
+	if s.ImportPath == "" {
+		return s.Func
+	}
+
+	return s.ImportPath + "." + s.Func
+}
+
+// Generate returns the formatted source of a main.go registering scenarios, as returned by Find.
+// Packages are imported with their name, suffixed with a number when names clash.
+func Generate(scenarios []Scenario) ([]byte, error) {
+	var imports []generatedImport
+	names := map[string]string{}
+	taken := map[string]bool{"f1": true}
+
+	registrations := make([]registration, 0, len(scenarios))
+	for _, scenario := range scenarios {
+		if scenario.ImportPath == "" {
+			registrations = append(registrations, registration{Name: scenario.Name, Ref: scenario.Func})
+			continue
+		}
+
+		name, ok := names[scenario.ImportPath]
+		if !ok {
+			name = scenario.Package
+			for i := 2; taken[name]; i++ {
+				name = scenario.Package + strconv.Itoa(i)
+			}
+			taken[name] = true
+			names[scenario.ImportPath] = name
+			alias := name
+			if alias == path.Base(scenario.ImportPath) {
+				alias = ""
+			}
+			imports = append(imports, generatedImport{Alias: alias, Path: scenario.ImportPath})
+		}
+		registrations = append(registrations, registration{Name: scenario.Name, Ref: name + "." + scenario.Func})
+	}
+	slices.SortFunc(imports, func(a, b generatedImport) int { return strings.Compare(a.Path, b.Path) })
+
+	var source bytes.Buffer
+	err := mainTemplate.Execute(&source, struct {
+		Imports   []generatedImport
+		Scenarios []registration
+	}{Imports: imports, Scenarios: registrations})
+	if err != nil {
+		return nil, fmt.Errorf("executing template: %w", err)
+	}
+
+	formatted, err := format.Source(source.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated source: %w", err)
+	}
+
+	return formatted, nil
+}
@@ -0,0 +1,166 @@
+package scenariogen_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/scenariogen"
+)
+
+const scenarioImports = `import (
+	"github.com/form3tech-oss/f1/v2/pkg/f1/testing"
+)
+`
+
+func writeModule(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	files["go.mod"] = "module example.com/loadtests\n\ngo 1.22.0\n"
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+
+	return dir
+}
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name     string
+		files    map[string]string
+		patterns []string
+		expected string
+	}{
+		{
+			name: "scenarios of the main package",
+			files: map[string]string{
+				"cmd/main.go": "// Code generated by f1gen. DO NOT EDIT.\n\npackage main\n\nfunc main() {}\n",
+				"cmd/scenarios.go": "package main\n\n" + scenarioImports + `
+func paymentScenario(t *testing.T) testing.RunFn { return nil }
+
+//f1:scenario create-account
+func accountScenario(t *testing.T) testing.RunFn { return nil }
+
+//f1:scenario -
+func helperScenario(t *testing.T) testing.RunFn { return nil }
+
+func notAScenario(t *testing.T) {}
+`,
+				"cmd/scenarios_test.go": "package main\n\n" + scenarioImports + `
+func testScenario(t *testing.T) testing.RunFn { return nil }
+`,
+			},
+			patterns: []string{"."},
+			expected: `// Code generated by f1gen. DO NOT EDIT.
+
+package main
+
+import (
+	"github.com/form3tech-oss/f1/v2/pkg/f1"
+)
+
+func main() {
+	f1.New().
+		Add("create-account", accountScenario).
+		Add("paymentScenario", paymentScenario).
+		Execute()
+}
+`,
+		},
+		{
+			name: "exported scenarios of packages below",
+			files: map[string]string{
+				"payments/payments.go": "package payments\n\n" + scenarioImports + `
+func CreatePayment(t *testing.T) testing.RunFn { return nil }
+
+func unexportedScenario(t *testing.T) testing.RunFn { return nil }
+`,
+				"accounts/v2/payments/payments.go": "package payments\n\nimport f1testing \"github.com/form3tech-oss/f1/v2/pkg/f1/testing\"\n" + `
+func FetchPayment(t *f1testing.T) f1testing.RunFn { return nil }
+`,
+				"other/main.go": "package main\n\n" + scenarioImports + `
+func OtherScenario(t *testing.T) testing.RunFn { return nil }
+`,
+				"payments/testdata/ignored.go": "package ignored\n\n" + scenarioImports + `
+func IgnoredScenario(t *testing.T) testing.RunFn { return nil }
+`,
+			},
+			patterns: []string{"../..."},
+			expected: `// Code generated by f1gen. DO NOT EDIT.
+
+package main
+
+import (
+	"github.com/form3tech-oss/f1/v2/pkg/f1"
+
+	payments2 "example.com/loadtests/accounts/v2/payments"
+	"example.com/loadtests/payments"
+)
+
+func main() {
+	f1.New().
+		Add("CreatePayment", payments.CreatePayment).
+		Add("FetchPayment", payments2.FetchPayment).
+		Execute()
+}
+`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := writeModule(t, test.files)
+
+			scenarios, err := scenariogen.Find(filepath.Join(dir, "cmd"), test.patterns)
+			require.NoError(t, err)
+			source, err := scenariogen.Generate(scenarios)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, string(source))
+		})
+	}
+}
+
+func TestFindFails(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name     string
+		files    map[string]string
+		expected string
+	}{
+		{
+			name:     "no scenarios",
+			files:    map[string]string{"cmd/main.go": "package main\n\nfunc main() {}\n"},
+			expected: "no scenarios found in .",
+		},
+		{
+			name: "duplicate names",
+			files: map[string]string{"cmd/scenarios.go": "package main\n\n" + scenarioImports + `
+func first(t *testing.T) testing.RunFn { return nil }
+
+//f1:scenario first
+func second(t *testing.T) testing.RunFn { return nil }
+`},
+			expected: `scenario "first" is registered by both first and second`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := writeModule(t, test.files)
+
+			_, err := scenariogen.Find(filepath.Join(dir, "cmd"), []string{"."})
+
+			require.EqualError(t, err, test.expected)
+		})
+	}
+}
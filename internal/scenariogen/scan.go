@@ -0,0 +1,281 @@
+package scenariogen
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// testingImportPath is the package of the types in the signature of scenarios
+	testingImportPath = "github.com/form3tech-oss/f1/v2/pkg/f1/testing"
+	// directive names the scenario of the function it documents, or skips it if the name is "-"
+	directive = "//f1:scenario"
+)
+
+// generatedHeader matches the header of generated files, which are not scanned so that the main.go
+// generated before isn't read back.
+var generatedHeader = regexp.MustCompile(`^// Code generated .* DO NOT EDIT\.$`)
+
+// ErrNoModule is returned when no go.mod is found in the directory scanned or its parents.
+var ErrNoModule = errors.New("no go.mod found")
+
+// Scenario is a function matching the signature of scenarios, func(*testing.T) testing.RunFn.
+type Scenario struct {
+	// Name is the name the scenario is registered with, the name of its function unless set by
+	// an f1:scenario comment
+	Name string
+	// Func is the name of the function of the scenario
+	Func string
+	// ImportPath is the package of the function, empty for functions of the main package
+	ImportPath string
+	// Package is the name of the package of the function
+	Package string
+}
+
+// module is the go.mod the scanned packages belong to.
+type module struct {
+	dir  string
+	path string
+}
+
+func findModule(dir string) (module, error) {
+	for current := dir; ; current = filepath.Dir(current) {
+		modulePath, err := readModulePath(filepath.Join(current, "go.mod"))
+		if err == nil {
+			return module{dir: current, path: modulePath}, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return module{}, err
+		}
+		if filepath.Dir(current) == current {
+			return module{}, fmt.Errorf("%w in %s or its parents", ErrNoModule, dir)
+		}
+	}
+}
+
+func readModulePath(goMod string) (string, error) {
+	file, err := os.Open(goMod)
+	if err != nil {
+		return "", fmt.Errorf("opening go.mod: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if modulePath, found := strings.CutPrefix(line, "module "); found {
+			if unquoted, err := strconv.Unquote(strings.TrimSpace(modulePath)); err == nil {
+				return unquoted, nil
+			}
+			return strings.TrimSpace(modulePath), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("reading %s: %w", goMod, err)
+	}
+
+	return "", fmt.Errorf("no module directive in %s", goMod)
+}
+
+// importPath returns the import path of a package directory of the module.
+func (m module) importPath(dir string) (string, error) {
+	rel, err := filepath.Rel(m.dir, dir)
+	if err != nil {
+		return "", fmt.Errorf("resolving %s in module %s: %w", dir, m.path, err)
+	}
+	if rel == "." {
+		return m.path, nil
+	}
+	if strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("%s is outside of module %s", dir, m.path)
+	}
+
+	return path.Join(m.path, filepath.ToSlash(rel)), nil
+}
+
+// packageDirs returns the directories matched by a pattern, a directory or a directory followed by
+// /... for it and all the directories below it, except testdata, vendor and hidden directories.
+func packageDirs(pattern string) ([]string, error) {
+	root, recursive := strings.CutSuffix(filepath.ToSlash(pattern), "/...")
+	if root == "..." {
+		root, recursive = ".", true
+	}
+	root = filepath.FromSlash(root)
+	if !recursive {
+		return []string{root}, nil
+	}
+
+	var dirs []string
+	err := filepath.WalkDir(root, func(dir string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() {
+			return nil
+		}
+		name := entry.Name()
+		if dir != root && (name == "testdata" || name == "vendor" || strings.HasPrefix(name, ".") ||
+			strings.HasPrefix(name, "_")) {
+			return filepath.SkipDir
+		}
+		dirs = append(dirs, dir)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walking %s: %w", root, err)
+	}
+
+	return dirs, nil
+}
+
+// scanPackage returns the scenarios of the package in dir, and the name of the package. Only the
+// exported functions of packages other than main can be registered.
+func scanPackage(dir string) ([]Scenario, string, error) {
+	fset := token.NewFileSet()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, "", fmt.Errorf("reading %s: %w", dir, err)
+	}
+
+	var scenarios []Scenario
+	packageName := ""
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, "", fmt.Errorf("parsing %s: %w", filepath.Join(dir, name), err)
+		}
+		if isGenerated(file) {
+			continue
+		}
+		if packageName == "" {
+			packageName = file.Name.Name
+		}
+
+		scenarios = append(scenarios, scanFile(file)...)
+	}
+
+	if packageName != "main" {
+		exported := scenarios[:0]
+		for _, scenario := range scenarios {
+			if ast.IsExported(scenario.Func) {
+				exported = append(exported, scenario)
+			}
+		}
+		scenarios = exported
+	}
+	for i := range scenarios {
+		scenarios[i].Package = packageName
+	}
+
+	return scenarios, packageName, nil
+}
+
+func isGenerated(file *ast.File) bool {
+	for _, group := range file.Comments {
+		if group.Pos() >= file.Package {
+			return false
+		}
+		for _, comment := range group.List {
+			if generatedHeader.MatchString(comment.Text) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func scanFile(file *ast.File) []Scenario {
+	testingName := importName(file, testingImportPath)
+	if testingName == "" {
+		return nil
+	}
+
+	var scenarios []Scenario
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil || fn.Type.TypeParams != nil || !isScenarioSignature(fn.Type, testingName) {
+			continue
+		}
+
+		name := scenarioName(fn)
+		if name == "-" {
+			continue
+		}
+		scenarios = append(scenarios, Scenario{Name: name, Func: fn.Name.Name})
+	}
+
+	return scenarios
+}
+
+// importName returns the name importPath is imported with in file, or an empty string if it isn't.
+func importName(file *ast.File, importPath string) string {
+	for _, spec := range file.Imports {
+		if imported, err := strconv.Unquote(spec.Path.Value); err != nil || imported != importPath {
+			continue
+		}
+		if spec.Name != nil {
+			if spec.Name.Name == "_" || spec.Name.Name == "." {
+				return ""
+			}
+			return spec.Name.Name
+		}
+		return path.Base(importPath)
+	}
+
+	return ""
+}
+
+// isScenarioSignature returns true for func(*testing.T) testing.RunFn, testing being the name of
+// the package of scenarios in the file.
+func isScenarioSignature(fn *ast.FuncType, testingName string) bool {
+	if fn.Params.NumFields() != 1 || fn.Results.NumFields() != 1 {
+		return false
+	}
+
+	param, ok := fn.Params.List[0].Type.(*ast.StarExpr)
+	if !ok || !isSelector(param.X, testingName, "T") {
+		return false
+	}
+
+	return isSelector(fn.Results.List[0].Type, testingName, "RunFn")
+}
+
+func isSelector(expr ast.Expr, packageName, name string) bool {
+	selector, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	ident, ok := selector.X.(*ast.Ident)
+
+	return ok && ident.Name == packageName && selector.Sel.Name == name
+}
+
+// scenarioName returns the name set by the f1:scenario comment of fn, or the name of fn.
+func scenarioName(fn *ast.FuncDecl) string {
+	if fn.Doc != nil {
+		for _, comment := range fn.Doc.List {
+			if name, found := strings.CutPrefix(comment.Text, directive+" "); found && strings.TrimSpace(name) != "" {
+				return strings.TrimSpace(name)
+			}
+		}
+	}
+
+	return fn.Name.Name
+}
@@ -0,0 +1,75 @@
+package sweep
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+)
+
+var (
+	_ ui.Outputable = (*runStartedMessage)(nil)
+	_ ui.Outputable = (*summaryMessage)(nil)
+)
+
+type runStartedMessage struct {
+	name  string
+	index int
+	total int
+}
+
+func (m runStartedMessage) Print(printer *ui.Printer) {
+	printer.Println(fmt.Sprintf("Sweep run %d/%d: %s", m.index+1, m.total, m.name))
+}
+
+func (m runStartedMessage) Log(logger *slog.Logger) {
+	logger.Info("sweep run started",
+		slog.String("run", m.name),
+		slog.Int("index", m.index+1),
+		slog.Int("total", m.total),
+	)
+}
+
+type summaryMessage struct {
+	report Report
+}
+
+func (m summaryMessage) Print(printer *ui.Printer) {
+	width := len("run")
+	for _, r := range m.report.Runs {
+		width = max(width, len(r.Name))
+	}
+
+	lines := []string{"", "Sweep Results", fmt.Sprintf("%-*s %-6s %10s %10s %8s %8s %10s %10s %10s",
+		width, "run", "result", "iterations", "rate", "errors", "dropped", "p50", "p95", "p99")}
+	for _, r := range m.report.Runs {
+		result := "passed"
+		if r.Report.Failed {
+			result = "failed"
+		}
+		durations := r.Report.SuccessfulIterationDurations
+		lines = append(lines, fmt.Sprintf("%-*s %-6s %10d %8.1f/s %7.2f%% %8d %10s %10s %10s",
+			width, r.Name, result, r.Report.IterationsStarted, r.Throughput(), r.Report.ErrorRate(),
+			r.Report.DroppedIterationCount, durations.P50, durations.P95, durations.P99))
+	}
+
+	printer.Println(strings.Join(lines, "\n"))
+}
+
+func (m summaryMessage) Log(logger *slog.Logger) {
+	for _, r := range m.report.Runs {
+		durations := r.Report.SuccessfulIterationDurations
+		logger.Info("sweep run summary",
+			slog.String("run", r.Name),
+			slog.Bool("failed", r.Report.Failed),
+			slog.Uint64("iterations_started", r.Report.IterationsStarted),
+			slog.Float64("throughput", r.Throughput()),
+			slog.Float64("error_rate", r.Report.ErrorRate()),
+			slog.Uint64("dropped", r.Report.DroppedIterationCount),
+			slog.Duration("p50", durations.P50),
+			slog.Duration("p95", durations.P95),
+			slog.Duration("p99", durations.P99),
+		)
+	}
+}
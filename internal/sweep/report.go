@@ -0,0 +1,42 @@
+package sweep
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/form3tech-oss/f1/v2/internal/run"
+)
+
+// Report is the comparison of the runs of a sweep.
+type Report struct {
+	Runs []RunReport `json:"runs"`
+}
+
+type RunReport struct {
+	Report   *run.Report `json:"report"`
+	Name     string      `json:"name"`
+	Settings []Setting   `json:"settings"`
+}
+
+// Throughput returns the number of iterations the run started per second.
+func (r RunReport) Throughput() float64 {
+	if r.Report.Duration <= 0 {
+		return 0
+	}
+
+	return float64(r.Report.IterationsStarted) / r.Report.Duration.Seconds()
+}
+
+func (r Report) Write(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshalling sweep report: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("writing sweep report file '%s': %w", path, err)
+	}
+
+	return nil
+}
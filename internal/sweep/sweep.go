@@ -0,0 +1,124 @@
+package sweep
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Parameter is a parameter varied by a sweep, with the values it takes in turn.
+type Parameter struct {
+	Name   string
+	Values []string
+	// Env sets the parameter as an environment variable of the scenario, rather than as a flag of
+	// `f1 run`
+	Env bool
+}
+
+// Setting is the value of a parameter in a run of a sweep.
+type Setting struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	Env   bool   `json:"env,omitempty"`
+}
+
+// ParseParameter parses a parameter of the form name=value,value,...
+func ParseParameter(parameter string, env bool) (Parameter, error) {
+	name, values, ok := strings.Cut(parameter, "=")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" {
+		return Parameter{}, fmt.Errorf("invalid parameter '%s', expected name=value,value", parameter)
+	}
+
+	p := Parameter{Name: name, Env: env}
+	for _, value := range strings.Split(values, ",") {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			return Parameter{}, fmt.Errorf("parameter '%s' has an empty value", name)
+		}
+		p.Values = append(p.Values, value)
+	}
+
+	return p, nil
+}
+
+// Grid returns the settings of every run of a sweep, one for each combination of the values of the
+// parameters, varying the last parameter the fastest.
+func Grid(parameters []Parameter) ([][]Setting, error) {
+	if len(parameters) == 0 {
+		return nil, errors.New("no parameters to sweep")
+	}
+
+	grid := [][]Setting{nil}
+	for i, p := range parameters {
+		for _, previous := range parameters[:i] {
+			if previous.Name == p.Name && previous.Env == p.Env {
+				return nil, fmt.Errorf("duplicate parameter '%s'", p.Name)
+			}
+		}
+
+		next := make([][]Setting, 0, len(grid)*len(p.Values))
+		for _, settings := range grid {
+			for _, value := range p.Values {
+				next = append(next, append(slices.Clip(settings), Setting{Name: p.Name, Value: value, Env: p.Env}))
+			}
+		}
+		grid = next
+	}
+
+	return grid, nil
+}
+
+// runName names a run after its settings, such as "rate=10/s PAYLOAD_SIZE=1024".
+func runName(settings []Setting) string {
+	parts := make([]string, len(settings))
+	for i, s := range settings {
+		parts[i] = s.Name + "=" + s.Value
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// runArgs returns the arguments of `f1 run` for a run with the settings, the flag settings
+// following, and so overriding, the arguments common to all the runs.
+func runArgs(args []string, settings []Setting) []string {
+	args = slices.Clone(args)
+	for _, s := range settings {
+		if !s.Env {
+			args = append(args, "--"+s.Name, s.Value)
+		}
+	}
+
+	return args
+}
+
+// setEnv sets the environment variables of the settings for a run, as the parameters of the stages
+// of config files are set.
+func setEnv(settings []Setting) error {
+	for _, s := range settings {
+		if !s.Env {
+			continue
+		}
+		if err := os.Setenv(s.Name, s.Value); err != nil {
+			return fmt.Errorf("setting environment variable %s: %w", s.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// unsetEnv unsets the environment variables of the settings once their run completed.
+func unsetEnv(settings []Setting) error {
+	for _, s := range settings {
+		if !s.Env {
+			continue
+		}
+		if err := os.Unsetenv(s.Name); err != nil {
+			return fmt.Errorf("unsetting environment variable %s: %w", s.Name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,212 @@
+package sweep
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/spf13/cobra"
+
+	"github.com/form3tech-oss/f1/v2/internal/run"
+	"github.com/form3tech-oss/f1/v2/internal/triggerflags"
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+)
+
+const (
+	flagVaryFlag    = "vary-flag"
+	flagVaryEnv     = "vary-env"
+	flagRunDuration = "run-duration"
+	flagReportFile  = "report-file"
+
+	defaultRunDuration = 30 * time.Second
+)
+
+// Cmd returns the sweep command. newRunCmd returns a new `f1 run` command for every run of the
+// sweep, so that flags set for a run do not leak into the next run.
+func Cmd(newRunCmd func() *cobra.Command, output *ui.Output) *cobra.Command {
+	sweepCmd := &cobra.Command{
+		Use:   "sweep [flags] -- <trigger> <scenario> [run flags]",
+		Short: "Runs a test scenario for every combination of a grid of parameters, and compares the runs",
+		Long: `Runs a test scenario repeatedly with short runs of f1 run, one for every combination of the
+values of the parameters, and prints a comparison of the throughput, errors, dropped iterations
+and latency of the runs. Parameters are either flags of f1 run, or environment variables read by
+the scenario. For example:
+
+  f1 sweep --vary-flag rate=10/s,50/s,100/s --vary-env PAYLOAD_SIZE=1024,65536 --run-duration 1m \
+    -- constant payments --distribution none`,
+		Args: cobra.MinimumNArgs(2),
+		RunE: sweepCmdExecute(newRunCmd, output),
+	}
+
+	sweepCmd.Flags().StringArray(flagVaryFlag, nil,
+		"vary a flag of f1 run over the `name=value,value` values, can be repeated")
+	sweepCmd.Flags().StringArray(flagVaryEnv, nil,
+		"vary an environment variable of the scenario over the `name=value,value` values, can be repeated")
+	sweepCmd.Flags().Duration(flagRunDuration, defaultRunDuration,
+		"the max duration of every run, overriding the --max-duration of the run flags")
+	sweepCmd.Flags().String(flagReportFile, "", "write a json report of the sweep to `file`")
+
+	return sweepCmd
+}
+
+func sweepCmdExecute(
+	newRunCmd func() *cobra.Command,
+	output *ui.Output,
+) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		varyFlags, err := cmd.Flags().GetStringArray(flagVaryFlag)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+
+		varyEnvs, err := cmd.Flags().GetStringArray(flagVaryEnv)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+
+		runDuration, err := cmd.Flags().GetDuration(flagRunDuration)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+
+		reportFile, err := cmd.Flags().GetString(flagReportFile)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+
+		parameters, err := parseParameters(varyFlags, varyEnvs)
+		if err != nil {
+			return err
+		}
+
+		grid, err := Grid(parameters)
+		if err != nil {
+			return err
+		}
+
+		dir, err := os.MkdirTemp("", "f1-sweep-")
+		if err != nil {
+			return fmt.Errorf("creating temporary directory: %w", err)
+		}
+
+		s := &sweep{
+			newRunCmd: newRunCmd,
+			output:    output,
+			dir:       dir,
+			args:      slices.Concat(args, []string{"--" + triggerflags.FlagMaxDuration, runDuration.String()}),
+		}
+
+		report, err := s.run(cmd.Context(), grid)
+		if err != nil {
+			return err
+		}
+
+		output.Display(summaryMessage{report: report})
+
+		if reportFile != "" {
+			if err := report.Write(reportFile); err != nil {
+				return fmt.Errorf("writing sweep report: %w", err)
+			}
+		}
+
+		return nil
+	}
+}
+
+// parseParameters parses the parameters varied by the flags of the sweep command, the flags of
+// f1 run before the environment variables.
+func parseParameters(varyFlags, varyEnvs []string) ([]Parameter, error) {
+	parameters := make([]Parameter, 0, len(varyFlags)+len(varyEnvs))
+	for _, env := range []bool{false, true} {
+		values := varyFlags
+		if env {
+			values = varyEnvs
+		}
+
+		for _, value := range values {
+			parameter, err := ParseParameter(value, env)
+			if err != nil {
+				return nil, err
+			}
+			parameters = append(parameters, parameter)
+		}
+	}
+
+	return parameters, nil
+}
+
+type sweep struct {
+	output    *ui.Output
+	newRunCmd func() *cobra.Command
+	dir       string
+	// args are the arguments of f1 run common to all the runs
+	args []string
+}
+
+// run runs the scenario once for every settings of the grid, in order, until ctx is done.
+func (s *sweep) run(ctx context.Context, grid [][]Setting) (Report, error) {
+	report := Report{}
+
+	for i, settings := range grid {
+		if ctx.Err() != nil {
+			break
+		}
+
+		name := runName(settings)
+		s.output.Display(runStartedMessage{name: name, index: i, total: len(grid)})
+
+		runReport, err := s.execute(ctx, i, name, settings)
+		if err != nil {
+			return report, err
+		}
+		report.Runs = append(report.Runs, runReport)
+	}
+
+	return report, nil
+}
+
+func (s *sweep) execute(ctx context.Context, index int, name string, settings []Setting) (RunReport, error) {
+	reportPath := filepath.Join(s.dir, reportFileName(index, name))
+
+	runCmd := s.newRunCmd()
+	runCmd.SilenceErrors = true
+	runCmd.SilenceUsage = true
+	runCmd.SetArgs(slices.Concat(runArgs(s.args, settings), []string{"--" + triggerflags.FlagReportFile, reportPath}))
+
+	if err := setEnv(settings); err != nil {
+		return RunReport{}, err
+	}
+	// a failed run returns an error too, its result is read from the report
+	runErr := runCmd.ExecuteContext(ctx)
+	if err := unsetEnv(settings); err != nil {
+		return RunReport{}, err
+	}
+
+	report, err := run.ReadReport(reportPath)
+	if err != nil {
+		return RunReport{}, fmt.Errorf("run '%s' did not complete: %w", name, errors.Join(runErr, err))
+	}
+
+	return RunReport{Name: name, Settings: settings, Report: &report}, nil
+}
+
+// reportFileName returns the name of the report of a run, numbered in the order of the sweep and
+// named after its settings, with characters other than letters, digits, '-', '_' and '.' replaced.
+func reportFileName(index int, name string) string {
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("-_.", r) {
+			return r
+		}
+		return '_'
+	}, name)
+
+	return fmt.Sprintf("%02d-%s.json", index+1, name)
+}
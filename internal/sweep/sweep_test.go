@@ -0,0 +1,122 @@
+package sweep_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/sweep"
+)
+
+func TestParseParameter(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name        string
+		parameter   string
+		expectedErr string
+		expected    sweep.Parameter
+		env         bool
+	}{
+		{
+			name:      "flag",
+			parameter: "rate=10/s, 50/s,100/s",
+			expected:  sweep.Parameter{Name: "rate", Values: []string{"10/s", "50/s", "100/s"}},
+		},
+		{
+			name:      "environment variable",
+			parameter: "PAYLOAD_SIZE=1024",
+			env:       true,
+			expected:  sweep.Parameter{Name: "PAYLOAD_SIZE", Values: []string{"1024"}, Env: true},
+		},
+		{
+			name:        "missing values",
+			parameter:   "rate",
+			expectedErr: "invalid parameter 'rate', expected name=value,value",
+		},
+		{
+			name:        "missing name",
+			parameter:   "=10/s",
+			expectedErr: "invalid parameter '=10/s', expected name=value,value",
+		},
+		{
+			name:        "empty value",
+			parameter:   "rate=10/s,,50/s",
+			expectedErr: "parameter 'rate' has an empty value",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			parameter, err := sweep.ParseParameter(test.parameter, test.env)
+			if test.expectedErr != "" {
+				require.EqualError(t, err, test.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, parameter)
+		})
+	}
+}
+
+func TestGrid(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name        string
+		expectedErr string
+		parameters  []sweep.Parameter
+		expected    [][]sweep.Setting
+	}{
+		{
+			name:        "no parameters",
+			expectedErr: "no parameters to sweep",
+		},
+		{
+			name:       "single parameter",
+			parameters: []sweep.Parameter{{Name: "rate", Values: []string{"10/s", "50/s"}}},
+			expected: [][]sweep.Setting{
+				{{Name: "rate", Value: "10/s"}},
+				{{Name: "rate", Value: "50/s"}},
+			},
+		},
+		{
+			name: "every combination",
+			parameters: []sweep.Parameter{
+				{Name: "rate", Values: []string{"10/s", "50/s"}},
+				{Name: "SIZE", Values: []string{"1", "2", "3"}, Env: true},
+			},
+			expected: [][]sweep.Setting{
+				{{Name: "rate", Value: "10/s"}, {Name: "SIZE", Value: "1", Env: true}},
+				{{Name: "rate", Value: "10/s"}, {Name: "SIZE", Value: "2", Env: true}},
+				{{Name: "rate", Value: "10/s"}, {Name: "SIZE", Value: "3", Env: true}},
+				{{Name: "rate", Value: "50/s"}, {Name: "SIZE", Value: "1", Env: true}},
+				{{Name: "rate", Value: "50/s"}, {Name: "SIZE", Value: "2", Env: true}},
+				{{Name: "rate", Value: "50/s"}, {Name: "SIZE", Value: "3", Env: true}},
+			},
+		},
+		{
+			name: "duplicate parameter",
+			parameters: []sweep.Parameter{
+				{Name: "rate", Values: []string{"10/s"}},
+				{Name: "rate", Values: []string{"50/s"}},
+			},
+			expectedErr: "duplicate parameter 'rate'",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			grid, err := sweep.Grid(test.parameters)
+			if test.expectedErr != "" {
+				require.EqualError(t, err, test.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, grid)
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package targetmetrics
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/prometheus/client_golang/api"
+	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"gopkg.in/yaml.v3"
+)
+
+// maxSamples caps the number of samples queried for each series, whatever the length of the run.
+const maxSamples = 250
+
+var errUnexpectedResult = errors.New("unexpected query result type")
+
+// Query is a named PromQL expression evaluated over the run window.
+type Query struct {
+	Name string
+	Expr string
+}
+
+// Metric summarises a series returned by a query over the run window.
+type Metric struct {
+	Name   string  `json:"name"`
+	Series string  `json:"series,omitempty"`
+	Min    float64 `json:"min"`
+	Avg    float64 `json:"avg"`
+	Max    float64 `json:"max"`
+}
+
+// LoadQueries reads queries from a yaml file mapping metric names to PromQL expressions, e.g.
+//
+//	cpu: sum(rate(container_cpu_usage_seconds_total{namespace="payments"}[1m]))
+//	memory: sum(container_memory_working_set_bytes{namespace="payments"})
+func LoadQueries(path string) ([]Query, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading target metrics queries '%s': %w", path, err)
+	}
+
+	expressions := map[string]string{}
+	if err := yaml.Unmarshal(data, &expressions); err != nil {
+		return nil, fmt.Errorf("parsing target metrics queries '%s': %w", path, err)
+	}
+
+	queries := make([]Query, 0, len(expressions))
+	for name, expr := range expressions {
+		queries = append(queries, Query{Name: name, Expr: expr})
+	}
+	slices.SortFunc(queries, func(a, b Query) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	return queries, nil
+}
+
+// Capture evaluates the queries against the Prometheus server at address between start and end.
+func Capture(ctx context.Context, address string, queries []Query, start, end time.Time) ([]Metric, error) {
+	client, err := api.NewClient(api.Config{Address: address})
+	if err != nil {
+		return nil, fmt.Errorf("creating prometheus client: %w", err)
+	}
+	promAPI := promv1.NewAPI(client)
+
+	window := promv1.Range{
+		Start: start,
+		End:   end,
+		Step:  max(end.Sub(start)/maxSamples, time.Second),
+	}
+
+	var metrics []Metric
+	for _, query := range queries {
+		value, _, err := promAPI.QueryRange(ctx, query.Expr, window)
+		if err != nil {
+			return nil, fmt.Errorf("querying target metric '%s': %w", query.Name, err)
+		}
+
+		matrix, ok := value.(model.Matrix)
+		if !ok {
+			return nil, fmt.Errorf("querying target metric '%s': %w: %s", query.Name, errUnexpectedResult, value.Type())
+		}
+
+		for _, series := range matrix {
+			if len(series.Values) == 0 {
+				continue
+			}
+			metrics = append(metrics, summarise(query.Name, series))
+		}
+	}
+
+	return metrics, nil
+}
+
+func summarise(name string, series *model.SampleStream) Metric {
+	metric := Metric{
+		Name: name,
+		Min:  math.Inf(1),
+		Max:  math.Inf(-1),
+	}
+	if len(series.Metric) > 0 {
+		metric.Series = series.Metric.String()
+	}
+
+	sum := 0.0
+	for _, sample := range series.Values {
+		value := float64(sample.Value)
+		sum += value
+		metric.Min = min(metric.Min, value)
+		metric.Max = max(metric.Max, value)
+	}
+	metric.Avg = sum / float64(len(series.Values))
+
+	return metric
+}
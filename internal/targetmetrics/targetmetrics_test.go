@@ -0,0 +1,98 @@
+package targetmetrics_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/targetmetrics"
+)
+
+const queryRangeResponse = `{
+  "status": "success",
+  "data": {
+    "resultType": "matrix",
+    "result": [
+      {"metric": {"pod": "payments-1"}, "values": [[1714559400, "0.5"], [1714559401, "1.5"], [1714559402, "1"]]},
+      {"metric": {"pod": "payments-2"}, "values": []}
+    ]
+  }
+}`
+
+func TestLoadQueries(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "queries.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(
+		"memory: sum(container_memory_working_set_bytes)\n"+
+			"cpu: sum(rate(container_cpu_usage_seconds_total[1m]))\n",
+	), 0o600))
+
+	queries, err := targetmetrics.LoadQueries(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, []targetmetrics.Query{
+		{Name: "cpu", Expr: "sum(rate(container_cpu_usage_seconds_total[1m]))"},
+		{Name: "memory", Expr: "sum(container_memory_working_set_bytes)"},
+	}, queries)
+}
+
+func TestLoadQueriesFromMissingFile(t *testing.T) {
+	t.Parallel()
+
+	_, err := targetmetrics.LoadQueries(filepath.Join(t.TempDir(), "missing.yaml"))
+	require.Error(t, err)
+}
+
+func TestCapture(t *testing.T) {
+	t.Parallel()
+
+	start := time.Unix(1714559400, 0)
+	end := start.Add(10 * time.Minute)
+
+	var query, step string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/query_range", r.URL.Path)
+		require.NoError(t, r.ParseForm())
+		query = r.Form.Get("query")
+		step = r.Form.Get("step")
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(queryRangeResponse))
+	}))
+	t.Cleanup(server.Close)
+
+	metrics, err := targetmetrics.Capture(context.Background(), server.URL, []targetmetrics.Query{
+		{Name: "cpu", Expr: "sum by (pod) (rate(container_cpu_usage_seconds_total[1m]))"},
+	}, start, end)
+	require.NoError(t, err)
+
+	assert.Equal(t, "sum by (pod) (rate(container_cpu_usage_seconds_total[1m]))", query)
+	assert.Equal(t, "2.4", step)
+	assert.Equal(t, []targetmetrics.Metric{
+		{Name: "cpu", Series: `{pod="payments-1"}`, Min: 0.5, Avg: 1, Max: 1.5},
+	}, metrics)
+}
+
+func TestCaptureFailedQuery(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"status": "error", "errorType": "bad_data", "error": "parse error"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	_, err := targetmetrics.Capture(context.Background(), server.URL, []targetmetrics.Query{
+		{Name: "cpu", Expr: "sum("},
+	}, time.Now().Add(-time.Minute), time.Now())
+	require.ErrorContains(t, err, "querying target metric 'cpu'")
+}
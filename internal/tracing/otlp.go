@@ -0,0 +1,281 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultOTLPEndpoint = "http://localhost:4318"
+	otlpFlushInterval   = time.Second
+	otlpExportTimeout   = 10 * time.Second
+	// maxBufferedEvents bounds the memory used by events waiting to be exported; further events
+	// are dropped until the next export
+	maxBufferedEvents = 10000
+)
+
+// otlpEndpoint returns the url traces are exported to, following the conventions of the
+// OpenTelemetry SDKs for the OTLP/HTTP exporter.
+func otlpEndpoint(getenv func(string) string) string {
+	if endpoint := getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"); endpoint != "" {
+		return endpoint
+	}
+
+	endpoint := getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+	if endpoint == "" {
+		endpoint = defaultOTLPEndpoint
+	}
+
+	return strings.TrimSuffix(endpoint, "/") + "/v1/traces"
+}
+
+// otlpTracer exports events as spans of a single trace per run, using the json encoding of
+// OTLP/HTTP. Events are buffered and exported in the background, so that recording an event
+// never waits for the collector.
+type otlpTracer struct {
+	client   *http.Client
+	err      error
+	stop     chan struct{}
+	done     chan struct{}
+	endpoint string
+	traceID  string
+	events   []otlpEvent
+	mu       sync.Mutex
+	dropped  int
+}
+
+type otlpEvent struct {
+	time  time.Time
+	name  string
+	attrs []slog.Attr
+}
+
+func newOTLPTracer(endpoint string) *otlpTracer {
+	t := &otlpTracer{
+		client:   http.DefaultClient,
+		endpoint: endpoint,
+		traceID:  randomHex(16),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+
+	go t.exportPeriodically()
+
+	return t
+}
+
+func (t *otlpTracer) Event(name string, attrs ...slog.Attr) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.events) >= maxBufferedEvents {
+		t.dropped++
+		return
+	}
+
+	t.events = append(t.events, otlpEvent{time: time.Now(), name: name, attrs: attrs})
+}
+
+func (t *otlpTracer) Close() error {
+	close(t.stop)
+	<-t.done
+
+	t.export()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.err != nil {
+		return t.err
+	}
+	if t.dropped > 0 {
+		return fmt.Errorf("dropped %d trace events exceeding the export buffer", t.dropped)
+	}
+
+	return nil
+}
+
+func (t *otlpTracer) exportPeriodically() {
+	defer close(t.done)
+
+	ticker := time.NewTicker(otlpFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-t.stop:
+			return
+		case <-ticker.C:
+			t.export()
+		}
+	}
+}
+
+// export sends the buffered events, keeping the first error to be returned by Close.
+func (t *otlpTracer) export() {
+	t.mu.Lock()
+	events := t.events
+	t.events = nil
+	t.mu.Unlock()
+
+	if len(events) == 0 {
+		return
+	}
+
+	if err := t.send(events); err != nil {
+		t.mu.Lock()
+		if t.err == nil {
+			t.err = err
+		}
+		t.mu.Unlock()
+	}
+}
+
+func (t *otlpTracer) send(events []otlpEvent) error {
+	body, err := json.Marshal(t.request(events))
+	if err != nil {
+		return fmt.Errorf("encoding trace events: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), otlpExportTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating trace export request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("exporting trace events: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("exporting trace events: unexpected status %s", resp.Status)
+	}
+
+	return nil
+}
+
+func (t *otlpTracer) request(events []otlpEvent) otlpRequest {
+	spans := make([]otlpSpan, len(events))
+	for i, event := range events {
+		timestamp := strconv.FormatInt(event.time.UnixNano(), 10)
+		spans[i] = otlpSpan{
+			TraceID:           t.traceID,
+			SpanID:            randomHex(8),
+			Name:              event.name,
+			Kind:              1,
+			StartTimeUnixNano: timestamp,
+			EndTimeUnixNano:   timestamp,
+			Attributes:        otlpAttributes(event.attrs),
+		}
+	}
+
+	return otlpRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource: otlpResource{Attributes: []otlpAttribute{
+			{Key: "service.name", Value: otlpValue{StringValue: ptr("f1")}},
+		}},
+		ScopeSpans: []otlpScopeSpans{{
+			Scope: otlpScope{Name: "f1"},
+			Spans: spans,
+		}},
+	}}}
+}
+
+func otlpAttributes(attrs []slog.Attr) []otlpAttribute {
+	attributes := make([]otlpAttribute, 0, len(attrs))
+	for _, attr := range attrs {
+		value := attr.Value.Resolve()
+
+		var v otlpValue
+		switch value.Kind() {
+		case slog.KindBool:
+			v.BoolValue = ptr(value.Bool())
+		case slog.KindInt64:
+			v.IntValue = ptr(strconv.FormatInt(value.Int64(), 10))
+		case slog.KindUint64:
+			v.IntValue = ptr(strconv.FormatUint(value.Uint64(), 10))
+		case slog.KindFloat64:
+			v.DoubleValue = ptr(value.Float64())
+		case slog.KindDuration:
+			v.IntValue = ptr(strconv.FormatInt(value.Duration().Nanoseconds(), 10))
+		default:
+			v.StringValue = ptr(value.String())
+		}
+
+		attributes = append(attributes, otlpAttribute{Key: attr.Key, Value: v})
+	}
+
+	return attributes
+}
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	Name              string          `json:"name"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes"`
+	Kind              int             `json:"kind"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	// crypto/rand.Read never returns an error on supported platforms
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,96 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+)
+
+// Backends of the tracer, selected with the --trace flag.
+const (
+	Off     = "off"
+	Console = "console"
+	File    = "file"
+	OTLP    = "otlp"
+)
+
+// DefaultFile is the file the file backend writes to when no file is given.
+const DefaultFile = "f1-trace.jsonl"
+
+var errUnknownBackend = errors.New("unknown trace backend")
+
+// Tracer records events of the internals of a run, such as iterations being triggered, workers
+// waiting for and receiving jobs, and the run being stopped, to diagnose how iterations are
+// scheduled. Events may be recorded from multiple goroutines.
+type Tracer interface {
+	Event(name string, attrs ...slog.Attr)
+	// Close flushes the recorded events and releases the resources of the tracer.
+	Close() error
+}
+
+// New returns a tracer for the given backend. The file backend writes json lines to file, and
+// the otlp backend exports to the endpoint of the standard OTEL_EXPORTER_OTLP_ENDPOINT or
+// OTEL_EXPORTER_OTLP_TRACES_ENDPOINT variables, read with getenv.
+func New(backend, file string, getenv func(string) string) (Tracer, error) {
+	switch backend {
+	case Off, "":
+		return Noop(), nil
+	case Console:
+		return newSlogTracer(slog.NewTextHandler(os.Stderr, nil), nil), nil
+	case File:
+		if file == "" {
+			file = DefaultFile
+		}
+		f, err := os.Create(filepath.Clean(file))
+		if err != nil {
+			return nil, fmt.Errorf("creating trace file: %w", err)
+		}
+		return newSlogTracer(slog.NewJSONHandler(f, nil), f), nil
+	case OTLP:
+		return newOTLPTracer(otlpEndpoint(getenv)), nil
+	default:
+		return nil, fmt.Errorf("%w: %s, expected one of %s, %s, %s or %s",
+			errUnknownBackend, backend, Console, File, OTLP, Off)
+	}
+}
+
+// Noop returns a tracer which discards all events.
+func Noop() Tracer {
+	return noopTracer{}
+}
+
+type noopTracer struct{}
+
+func (noopTracer) Event(string, ...slog.Attr) {}
+
+func (noopTracer) Close() error { return nil }
+
+// slogTracer writes events as log records, the event name being the message of the record.
+type slogTracer struct {
+	logger *slog.Logger
+	closer io.Closer
+}
+
+func newSlogTracer(handler slog.Handler, closer io.Closer) *slogTracer {
+	return &slogTracer{logger: slog.New(handler), closer: closer}
+}
+
+func (t *slogTracer) Event(name string, attrs ...slog.Attr) {
+	t.logger.LogAttrs(context.Background(), slog.LevelInfo, name, attrs...)
+}
+
+func (t *slogTracer) Close() error {
+	if t.closer == nil {
+		return nil
+	}
+
+	if err := t.closer.Close(); err != nil {
+		return fmt.Errorf("closing trace file: %w", err)
+	}
+
+	return nil
+}
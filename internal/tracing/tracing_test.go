@@ -0,0 +1,157 @@
+package tracing_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/tracing"
+)
+
+func TestFileTracerWritesJSONLines(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "trace.jsonl")
+
+	tracer, err := tracing.New(tracing.File, file, noEnv)
+	require.NoError(t, err)
+
+	tracer.Event("jobs triggered", slog.Int("jobs", 10), slog.String("operation", "read"))
+	tracer.Event("worker woken", slog.String("pool", "trigger"))
+	require.NoError(t, tracer.Close())
+
+	f, err := os.Open(file)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var events []map[string]any
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		event := map[string]any{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		events = append(events, event)
+	}
+
+	require.Len(t, events, 2)
+	assert.Equal(t, "jobs triggered", events[0]["msg"])
+	assert.InDelta(t, 10, events[0]["jobs"], 0)
+	assert.Equal(t, "read", events[0]["operation"])
+	assert.Equal(t, "worker woken", events[1]["msg"])
+	assert.Equal(t, "trigger", events[1]["pool"])
+}
+
+func TestNewRejectsUnknownBackends(t *testing.T) {
+	t.Parallel()
+
+	tracer, err := tracing.New("jaeger", "", noEnv)
+
+	require.Nil(t, tracer)
+	require.EqualError(t, err, "unknown trace backend: jaeger, expected one of console, file, otlp or off")
+}
+
+func TestOffTracerIsNoop(t *testing.T) {
+	t.Parallel()
+
+	tracer, err := tracing.New(tracing.Off, "", noEnv)
+
+	require.NoError(t, err)
+	assert.Equal(t, tracing.Noop(), tracer)
+}
+
+func TestOTLPTracerExportsEventsAsSpans(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var requests []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/traces", r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		body := map[string]any{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		mu.Lock()
+		requests = append(requests, body)
+		mu.Unlock()
+	}))
+	t.Cleanup(server.Close)
+
+	tracer, err := tracing.New(tracing.OTLP, "", func(name string) string {
+		if name == "OTEL_EXPORTER_OTLP_ENDPOINT" {
+			return server.URL + "/"
+		}
+		return ""
+	})
+	require.NoError(t, err)
+
+	tracer.Event("iteration started", slog.Uint64("iteration", 7), slog.Duration("duration", time.Millisecond))
+	require.NoError(t, tracer.Close())
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, requests, 1)
+
+	var export struct {
+		ResourceSpans []struct {
+			ScopeSpans []struct {
+				Spans []struct {
+					TraceID    string `json:"traceId"`
+					SpanID     string `json:"spanId"`
+					Name       string `json:"name"`
+					Attributes []struct {
+						Key   string            `json:"key"`
+						Value map[string]string `json:"value"`
+					} `json:"attributes"`
+				} `json:"spans"`
+			} `json:"scopeSpans"`
+		} `json:"resourceSpans"`
+	}
+	encoded, err := json.Marshal(requests[0])
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(encoded, &export))
+
+	spans := export.ResourceSpans[0].ScopeSpans[0].Spans
+	require.Len(t, spans, 1)
+	assert.Equal(t, "iteration started", spans[0].Name)
+	assert.Len(t, spans[0].TraceID, 32)
+	assert.Len(t, spans[0].SpanID, 16)
+	require.Len(t, spans[0].Attributes, 2)
+	assert.Equal(t, "iteration", spans[0].Attributes[0].Key)
+	assert.Equal(t, map[string]string{"intValue": "7"}, spans[0].Attributes[0].Value)
+	assert.Equal(t, map[string]string{"intValue": "1000000"}, spans[0].Attributes[1].Value)
+}
+
+func TestOTLPTracerReportsExportErrors(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+
+	tracer, err := tracing.New(tracing.OTLP, "", func(name string) string {
+		if name == "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT" {
+			return server.URL + "/custom"
+		}
+		return ""
+	})
+	require.NoError(t, err)
+
+	tracer.Event("setup started")
+
+	require.EqualError(t, tracer.Close(), "exporting trace events: unexpected status 503 Service Unavailable")
+}
+
+func noEnv(string) string {
+	return ""
+}
@@ -34,11 +34,26 @@ type Builder struct {
 type Constructor func(*pflag.FlagSet) (*Trigger, error)
 
 type Trigger struct {
-	Trigger     WorkTriggerer
-	DryRun      RateFunction
+	Trigger WorkTriggerer
+	DryRun  RateFunction
+	// StageAt optionally returns the name of the stage running after the given duration of the run
+	StageAt func(elapsed time.Duration) string
+	// Stages optionally names the stages which can be skipped or jumped to while the run is in
+	// progress, for triggers taking the requests of workers.PoolManager.JumpToStage
+	Stages      []string
 	Description string
 	Options     Options
 	Duration    time.Duration
+	// Completes ends the run when Trigger returns before Duration, for triggers which decide when
+	// they are done, rather than waiting for Duration to elapse
+	Completes bool
+	// PeakRate optionally returns the most iterations the trigger plans to start in a second of a
+	// run of the given duration. It runs the rates of the trigger, which must not be run afterwards
+	PeakRate func(duration time.Duration) int
+	// PlannedIterations optionally returns the number of iterations the trigger plans to start
+	// between from and to since the start of a run. Like PeakRate, it runs the rates of the trigger,
+	// which must not be run afterwards
+	PlannedIterations func(from, to time.Duration) uint64
 }
 
 type Options struct {
@@ -57,4 +72,6 @@ type Rates struct {
 	Rate              RateFunction
 	IterationDuration time.Duration
 	Duration          time.Duration
+	// StageAt optionally returns the name of the stage running after the given duration of the run
+	StageAt func(elapsed time.Duration) string
 }
@@ -2,7 +2,6 @@ package api
 
 import (
 	"fmt"
-	"math"
 	"math/rand"
 	"time"
 )
@@ -15,8 +14,16 @@ const (
 	RandomDistribution  DistributionType = "random"
 )
 
+// DefaultDistributionWindow is the duration of the steps the regular and random distributions
+// spread the iterations of an iteration duration over.
+const DefaultDistributionWindow = 100 * time.Millisecond
+
+// NewDistribution spreads the iterations started every iterationDuration over steps of window,
+// returning the duration between steps and the rate of every step. Shorter windows start the
+// iterations more smoothly, while longer windows start them in bigger batches.
 func NewDistribution(
 	distributionTypeArg DistributionType,
+	window time.Duration,
 	iterationDuration time.Duration,
 	rateFn RateFunction,
 	randomFnArg func(int) int,
@@ -26,62 +33,63 @@ func NewDistribution(
 		randomFn = rand.Intn
 	}
 
+	if window <= 0 && distributionTypeArg != NoneDistribution {
+		return iterationDuration, rateFn, fmt.Errorf("distribution window %s must be positive", window)
+	}
+
 	switch distributionTypeArg {
 	case NoneDistribution:
 		return iterationDuration, rateFn, nil
 	case RegularDistribution:
-		distributedIterationDuration, distributedRateFn := withRegularDistribution(iterationDuration, rateFn)
+		distributedIterationDuration, distributedRateFn := withRegularDistribution(window, iterationDuration, rateFn)
 		return distributedIterationDuration, distributedRateFn, nil
 	case RandomDistribution:
-		distributedIterationDuration, distributedRateFn := withRandomDistribution(iterationDuration, rateFn, randomFn)
+		distributedIterationDuration, distributedRateFn := withRandomDistribution(window, iterationDuration, rateFn, randomFn)
 		return distributedIterationDuration, distributedRateFn, nil
 	default:
 		return iterationDuration, rateFn, fmt.Errorf("unable to parse distribution %s", distributionTypeArg)
 	}
 }
 
-func withRegularDistribution(iterationDuration time.Duration, rateFn RateFunction) (time.Duration, RateFunction) {
-	distributedIterationDuration := 100 * time.Millisecond
+func withRegularDistribution(
+	window time.Duration,
+	iterationDuration time.Duration,
+	rateFn RateFunction,
+) (time.Duration, RateFunction) {
+	distributedIterationDuration := window
 
 	if iterationDuration <= distributedIterationDuration {
 		return iterationDuration, rateFn
 	}
 
 	rate := 0
-	accRate := 0.0
-	remainingSteps := 0
-	tickSteps := int(iterationDuration.Milliseconds() / distributedIterationDuration.Milliseconds())
+	step := 0
+	tickSteps := int(iterationDuration / distributedIterationDuration)
 
+	// the iterations started by each step are counted with integers, so that rates far below one
+	// iteration per step, such as 1/5m, still start their iteration in the last step
 	distributedRateFn := func(time time.Time) int {
-		if remainingSteps == 0 {
-			rate = rateFn(time)
-			accRate = 0.0
-			remainingSteps = tickSteps
+		if step == tickSteps {
+			step = 0
 		}
-
-		accRate += float64(rate) / float64(tickSteps)
-		accRate = math.Ceil(accRate*10_000_000) / 10_000_000
-		remainingSteps--
-
-		if accRate < 1 {
-			return 0
+		if step == 0 {
+			rate = rateFn(time)
 		}
+		step++
 
-		roundedAccRate := int(accRate)
-		accRate -= float64(roundedAccRate)
-
-		return roundedAccRate
+		return rate*step/tickSteps - rate*(step-1)/tickSteps
 	}
 
 	return distributedIterationDuration, distributedRateFn
 }
 
 func withRandomDistribution(
+	window time.Duration,
 	iterationDuration time.Duration,
 	rateFn RateFunction,
 	randFn func(int) int,
 ) (time.Duration, RateFunction) {
-	distributedIterationDuration := 100 * time.Millisecond
+	distributedIterationDuration := window
 
 	if iterationDuration <= distributedIterationDuration {
 		return iterationDuration, rateFn
@@ -89,7 +97,7 @@ func withRandomDistribution(
 
 	remainingSteps := 0
 	remainingRate := 0
-	tickSteps := int(iterationDuration.Milliseconds() / distributedIterationDuration.Milliseconds())
+	tickSteps := int(iterationDuration / distributedIterationDuration)
 
 	distributedRateFn := func(time time.Time) int {
 		if remainingSteps == 0 {
@@ -114,6 +114,16 @@ func TestRegularRateDistribution(t *testing.T) {
 			rate:                     10,
 			expectedDistributedRates: repeatSlice(append(repeatValue(0, 599), 1), 10),
 		},
+		{
+			iterationDuration:        5 * time.Minute,
+			rate:                     1,
+			expectedDistributedRates: repeatSlice(append(repeatValue(0, 2_999), 1), 2),
+		},
+		{
+			iterationDuration:        24 * time.Hour,
+			rate:                     1,
+			expectedDistributedRates: append(repeatValue(0, 863_999), 1),
+		},
 		{
 			iterationDuration:        1 * time.Minute,
 			rate:                     600,
@@ -160,7 +170,7 @@ func TestRegularRateDistribution(t *testing.T) {
 
 			rateFn := func(time.Time) int { return test.rate }
 
-			distributedIterationDuration, distributedRate, err := api.NewDistribution(api.RegularDistribution, test.iterationDuration, rateFn, nil)
+			distributedIterationDuration, distributedRate, err := api.NewDistribution(api.RegularDistribution, api.DefaultDistributionWindow, test.iterationDuration, rateFn, nil)
 			require.NoError(t, err)
 
 			var result []int
@@ -174,13 +184,73 @@ func TestRegularRateDistribution(t *testing.T) {
 	}
 }
 
+func TestRegularRateDistributionWindow(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		window                    time.Duration
+		expectedIterationDuration time.Duration
+		expectedDistributedRates  []int
+	}{
+		{
+			window:                    10 * time.Millisecond,
+			expectedIterationDuration: 10 * time.Millisecond,
+			expectedDistributedRates:  repeatSlice(append(repeatValue(0, 9), 1), 10),
+		},
+		{
+			window:                    50 * time.Millisecond,
+			expectedIterationDuration: 50 * time.Millisecond,
+			expectedDistributedRates:  repeatSlice([]int{0, 1}, 10),
+		},
+		{
+			window:                    500 * time.Millisecond,
+			expectedIterationDuration: 500 * time.Millisecond,
+			expectedDistributedRates:  []int{5, 5},
+		},
+		{
+			window:                    2 * time.Second,
+			expectedIterationDuration: 1 * time.Second,
+			expectedDistributedRates:  []int{10},
+		},
+	} {
+		t.Run(test.window.String(), func(t *testing.T) {
+			t.Parallel()
+
+			rateFn := func(time.Time) int { return 10 }
+
+			distributedIterationDuration, distributedRate, err := api.NewDistribution(api.RegularDistribution, test.window, 1*time.Second, rateFn, nil)
+			require.NoError(t, err)
+
+			var result []int
+			for range len(test.expectedDistributedRates) {
+				result = append(result, distributedRate(time.Now()))
+			}
+
+			require.Equal(t, test.expectedIterationDuration, distributedIterationDuration)
+			require.Equal(t, test.expectedDistributedRates, result)
+		})
+	}
+}
+
+func TestRateDistributionWindowMustBePositive(t *testing.T) {
+	t.Parallel()
+
+	rateFn := func(time.Time) int { return 10 }
+
+	_, _, err := api.NewDistribution(api.RegularDistribution, 0, 1*time.Second, rateFn, nil)
+	require.EqualError(t, err, "distribution window 0s must be positive")
+
+	_, _, err = api.NewDistribution(api.NoneDistribution, 0, 1*time.Second, rateFn, nil)
+	require.NoError(t, err)
+}
+
 func TestRegularRateDistributionWithSmallIterationDuration(t *testing.T) {
 	t.Parallel()
 
 	iterationDuration := 10 * time.Millisecond
 	rateFn := func(time.Time) int { return 10_000 }
 
-	distributedIterationDuration, distributedRate, err := api.NewDistribution(api.RegularDistribution, iterationDuration, rateFn, nil)
+	distributedIterationDuration, distributedRate, err := api.NewDistribution(api.RegularDistribution, api.DefaultDistributionWindow, iterationDuration, rateFn, nil)
 	require.NoError(t, err)
 
 	require.Equal(t, 10*time.Millisecond, distributedIterationDuration)
@@ -201,7 +271,7 @@ func TestRegularRateDistributionWithVariableRate(t *testing.T) {
 		0, 1, 1, 1, 1, 0, 1, 1, 1, 1,
 	}
 
-	distributedIterationDuration, distributedRate, err := api.NewDistribution(api.RegularDistribution, iterationDuration, rateFn, nil)
+	distributedIterationDuration, distributedRate, err := api.NewDistribution(api.RegularDistribution, api.DefaultDistributionWindow, iterationDuration, rateFn, nil)
 	require.NoError(t, err)
 
 	result := make([]int, len(expectedDistributedRates))
@@ -272,7 +342,7 @@ func TestRandomRateDistribution(t *testing.T) {
 			idx := -1
 			randFn := func(int) int { idx++; return test.randomValues[idx] }
 
-			distributedIterationDuration, distributedRate, err := api.NewDistribution(api.RandomDistribution, test.iterationDuration, rateFn, randFn)
+			distributedIterationDuration, distributedRate, err := api.NewDistribution(api.RandomDistribution, api.DefaultDistributionWindow, test.iterationDuration, rateFn, randFn)
 			require.NoError(t, err)
 
 			var result []int
@@ -308,7 +378,7 @@ func TestRandomRateDistributionWithVariableRate(t *testing.T) {
 		0, 1, 1, 1, 1, 0, 1, 1, 1, 1,
 	}
 
-	distributedIterationDuration, distributedRate, err := api.NewDistribution(api.RandomDistribution, iterationDuration, rateFn, randFn)
+	distributedIterationDuration, distributedRate, err := api.NewDistribution(api.RandomDistribution, api.DefaultDistributionWindow, iterationDuration, rateFn, randFn)
 	require.NoError(t, err)
 
 	result := make([]int, len(expectedDistributedRates))
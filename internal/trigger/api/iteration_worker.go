@@ -9,15 +9,35 @@ import (
 	"github.com/form3tech-oss/f1/v2/internal/workers"
 )
 
+// NewRatesTrigger returns a trigger starting iterations at the given rates with the iteration
+// worker, limited to the duration of the rates if it is set.
+func NewRatesTrigger(rates *Rates, description string) *Trigger {
+	return &Trigger{
+		Trigger:     NewIterationWorker(rates.IterationDuration, rates.Rate),
+		DryRun:      rates.Rate,
+		Description: description,
+		Duration:    rates.Duration,
+		StageAt:     rates.StageAt,
+		PeakRate:    NewPeakRate(rates.Rate, rates.IterationDuration),
+
+		PlannedIterations: NewPlannedIterations(rates.Rate, rates.IterationDuration),
+	}
+}
+
 // NewIterationWorker produces a WorkTriggerer which triggers work at fixed intervals.
 func NewIterationWorker(iterationDuration time.Duration, rate RateFunction) WorkTriggerer {
 	return func(ctx context.Context, _ *ui.Output, workers *workers.PoolManager, opts options.RunOptions) {
-		startRate := rate(time.Now())
+		now := time.Now()
+		if opts.Elapsed > 0 {
+			// start the rate from the point a resumed run was interrupted
+			rate(now.Add(-opts.Elapsed))
+		}
+		startRate := rate(now)
 
 		pool := workers.NewTriggerPool(opts.Concurrency)
 		workerCtx := pool.Start(ctx)
 
-		pool.Trigger(workerCtx, startRate)
+		pool.Trigger(workerCtx, workers.OverrideRate(startRate, iterationDuration))
 
 		// start ticker to trigger subsequent iterations.
 		iterationTicker := time.NewTicker(iterationDuration)
@@ -29,7 +49,7 @@ func NewIterationWorker(iterationDuration time.Duration, rate RateFunction) Work
 			case <-workerCtx.Done():
 				return
 			case start := <-iterationTicker.C:
-				iterationRate := rate(start)
+				iterationRate := workers.OverrideRate(rate(start), iterationDuration)
 				pool.Trigger(workerCtx, iterationRate)
 			}
 		}
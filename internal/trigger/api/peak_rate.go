@@ -0,0 +1,33 @@
+package api
+
+import "time"
+
+// maxPeakRateDuration bounds the part of the run whose rates are run to find their peak, so that
+// endless runs can be planned too.
+const maxPeakRateDuration = 24 * time.Hour
+
+// NewPeakRate returns the PeakRate of a trigger calling rate every iterationDuration, which sums
+// the iterations started in every second of the run, and in its first second for shorter runs.
+func NewPeakRate(rate RateFunction, iterationDuration time.Duration) func(time.Duration) int {
+	return func(duration time.Duration) int {
+		if iterationDuration <= 0 {
+			return 0
+		}
+
+		start := time.Now()
+		end := start.Add(min(max(duration, time.Second), maxPeakRateDuration))
+		peak := 0
+		second := 0
+		secondStart := start
+		for tick := start; tick.Before(end); tick = tick.Add(iterationDuration) {
+			if tick.Sub(secondStart) >= time.Second {
+				peak = max(peak, second)
+				second = 0
+				secondStart = tick
+			}
+			second += rate(tick)
+		}
+
+		return max(peak, second)
+	}
+}
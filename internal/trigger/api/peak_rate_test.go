@@ -0,0 +1,65 @@
+package api_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/form3tech-oss/f1/v2/internal/trigger/api"
+)
+
+func TestPeakRate(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name              string
+		rate              api.RateFunction
+		iterationDuration time.Duration
+		duration          time.Duration
+		expected          int
+	}{
+		{
+			name:              "constant rate",
+			rate:              func(time.Time) int { return 5 },
+			iterationDuration: 100 * time.Millisecond,
+			duration:          time.Minute,
+			expected:          50,
+		},
+		{
+			name:              "run shorter than a second",
+			rate:              func(time.Time) int { return 5 },
+			iterationDuration: 100 * time.Millisecond,
+			duration:          200 * time.Millisecond,
+			expected:          50,
+		},
+		{
+			name:              "iterations started less than once a second",
+			rate:              func(time.Time) int { return 20 },
+			iterationDuration: 10 * time.Second,
+			duration:          time.Minute,
+			expected:          20,
+		},
+		{
+			name: "increasing rate",
+			rate: func() api.RateFunction {
+				calls := 0
+				return func(time.Time) int {
+					calls++
+					return calls
+				}
+			}(),
+			iterationDuration: 500 * time.Millisecond,
+			duration:          3 * time.Second,
+			expected:          11,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			peakRate := api.NewPeakRate(test.rate, test.iterationDuration)
+
+			assert.Equal(t, test.expected, peakRate(test.duration))
+		})
+	}
+}
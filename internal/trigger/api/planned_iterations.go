@@ -0,0 +1,24 @@
+package api
+
+import "time"
+
+// NewPlannedIterations returns the PlannedIterations of a trigger calling rate every
+// iterationDuration, from the start of the run, as the iteration worker does.
+func NewPlannedIterations(rate RateFunction, iterationDuration time.Duration) func(from, to time.Duration) uint64 {
+	return func(from, to time.Duration) uint64 {
+		if iterationDuration <= 0 {
+			return 0
+		}
+
+		start := time.Now()
+		var planned uint64
+		for elapsed := time.Duration(0); elapsed < to; elapsed += iterationDuration {
+			iterations := rate(start.Add(elapsed))
+			if elapsed >= from {
+				planned += uint64(max(iterations, 0))
+			}
+		}
+
+		return planned
+	}
+}
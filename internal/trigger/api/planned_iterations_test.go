@@ -0,0 +1,68 @@
+package api_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/form3tech-oss/f1/v2/internal/trigger/api"
+)
+
+func TestPlannedIterations(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name              string
+		rate              api.RateFunction
+		iterationDuration time.Duration
+		from              time.Duration
+		to                time.Duration
+		expected          uint64
+	}{
+		{
+			name:              "constant rate",
+			rate:              func(time.Time) int { return 5 },
+			iterationDuration: 100 * time.Millisecond,
+			to:                10 * time.Second,
+			expected:          500,
+		},
+		{
+			name:              "part of the run",
+			rate:              func(time.Time) int { return 5 },
+			iterationDuration: 100 * time.Millisecond,
+			from:              2 * time.Second,
+			to:                3 * time.Second,
+			expected:          50,
+		},
+		{
+			name: "increasing rate",
+			rate: func() api.RateFunction {
+				calls := 0
+				return func(time.Time) int {
+					calls++
+					return calls
+				}
+			}(),
+			iterationDuration: time.Second,
+			from:              2 * time.Second,
+			to:                4 * time.Second,
+			expected:          7,
+		},
+		{
+			name:              "no iteration duration",
+			rate:              func(time.Time) int { return 5 },
+			iterationDuration: 0,
+			to:                10 * time.Second,
+			expected:          0,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			plannedIterations := api.NewPlannedIterations(test.rate, test.iterationDuration)
+
+			assert.Equal(t, test.expected, plannedIterations(test.from, test.to))
+		})
+	}
+}
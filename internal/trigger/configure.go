@@ -1,23 +1,31 @@
 package trigger
 
 import (
+	"io/fs"
+
 	"github.com/form3tech-oss/f1/v2/internal/trigger/api"
+	"github.com/form3tech-oss/f1/v2/internal/trigger/connections"
 	"github.com/form3tech-oss/f1/v2/internal/trigger/constant"
 	"github.com/form3tech-oss/f1/v2/internal/trigger/file"
 	"github.com/form3tech-oss/f1/v2/internal/trigger/gaussian"
 	"github.com/form3tech-oss/f1/v2/internal/trigger/ramp"
+	"github.com/form3tech-oss/f1/v2/internal/trigger/search"
 	"github.com/form3tech-oss/f1/v2/internal/trigger/staged"
+	"github.com/form3tech-oss/f1/v2/internal/trigger/tokenbucket"
 	"github.com/form3tech-oss/f1/v2/internal/trigger/users"
 	"github.com/form3tech-oss/f1/v2/internal/ui"
 )
 
-func GetBuilders(output *ui.Output) []api.Builder {
+func GetBuilders(output *ui.Output, profiles fs.FS) []api.Builder {
 	return []api.Builder{
 		constant.Rate(),
 		staged.Rate(),
 		gaussian.Rate(output),
 		users.Rate(),
 		ramp.Rate(),
-		file.Rate(output),
+		file.Rate(output, profiles),
+		search.Rate(),
+		tokenbucket.Rate(),
+		connections.Rate(),
 	}
 }
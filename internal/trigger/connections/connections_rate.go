@@ -0,0 +1,131 @@
+package connections
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/form3tech-oss/f1/v2/internal/options"
+	"github.com/form3tech-oss/f1/v2/internal/trigger/api"
+	"github.com/form3tech-oss/f1/v2/internal/triggerflags"
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+	"github.com/form3tech-oss/f1/v2/internal/workers"
+)
+
+const (
+	flagStartConnections = "start-connections"
+	flagEndConnections   = "end-connections"
+	flagRampDuration     = "ramp-duration"
+)
+
+// adjustInterval is how often the number of open connections is adjusted to the ramp
+const adjustInterval = 100 * time.Millisecond
+
+func Rate() api.Builder {
+	flags := pflag.NewFlagSet("connections", pflag.ContinueOnError)
+	flags.IntP(flagStartConnections, "s", 0, "number of connections open at the start of the ramp")
+	flags.IntP(flagEndConnections, "e", 1,
+		"number of connections open at the end of the ramp, and until the end of the run")
+	flags.DurationP(flagRampDuration, "r", 0, "ramp duration, if not provided then --max-duration will be used")
+
+	return api.Builder{
+		Name: "connections <scenario>",
+		Description: "ramps up or down the number of persistent connections, each held open by an iteration " +
+			"until its context is cancelled",
+		Flags: flags,
+		New: func(flags *pflag.FlagSet) (*api.Trigger, error) {
+			start, err := flags.GetInt(flagStartConnections)
+			if err != nil {
+				return nil, fmt.Errorf("getting flag: %w", err)
+			}
+			end, err := flags.GetInt(flagEndConnections)
+			if err != nil {
+				return nil, fmt.Errorf("getting flag: %w", err)
+			}
+			duration, err := flags.GetDuration(flagRampDuration)
+			if err != nil {
+				return nil, fmt.Errorf("getting flag: %w", err)
+			}
+			if duration == 0 {
+				duration, err = flags.GetDuration(triggerflags.FlagMaxDuration)
+				if err != nil {
+					return nil, fmt.Errorf("getting flag: %w", err)
+				}
+			}
+
+			ramp, err := NewRamp(start, end, duration)
+			if err != nil {
+				return nil, fmt.Errorf("creating connections ramp: %w", err)
+			}
+
+			return &api.Trigger{
+				Trigger: ramp.Trigger,
+				Description: fmt.Sprintf("ramping from %d to %d connections during %v",
+					start, end, duration),
+				DryRun: ramp.DryRun(),
+			}, nil
+		},
+	}
+}
+
+// Ramp plans the number of connections open during a run, from Start to End linearly over
+// Duration, and then End until the end of the run.
+type Ramp struct {
+	Start    int
+	End      int
+	Duration time.Duration
+}
+
+func NewRamp(start, end int, duration time.Duration) (Ramp, error) {
+	if start < 0 || end < 0 {
+		return Ramp{}, errors.New("start-connections and end-connections can't be negative")
+	}
+	if duration < 0 {
+		return Ramp{}, errors.New("ramp duration can't be negative")
+	}
+
+	return Ramp{Start: start, End: end, Duration: duration}, nil
+}
+
+// At returns the number of connections planned after elapsed.
+func (r Ramp) At(elapsed time.Duration) int {
+	if elapsed >= r.Duration {
+		return r.End
+	}
+
+	return r.Start + int(int64(r.End-r.Start)*int64(elapsed)/int64(r.Duration))
+}
+
+// Trigger keeps the planned number of connections open until ctx is done, which closes them all.
+func (r Ramp) Trigger(ctx context.Context, _ *ui.Output, workers *workers.PoolManager, _ options.RunOptions) {
+	pool := workers.NewConnectionPool(ctx)
+	start := time.Now()
+	pool.SetConnections(r.At(0))
+
+	ticker := time.NewTicker(adjustInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			pool.SetConnections(r.At(now.Sub(start)))
+		}
+	}
+}
+
+// DryRun returns the number of connections planned at the times it is called with, from the first.
+func (r Ramp) DryRun() api.RateFunction {
+	var start time.Time
+
+	return func(now time.Time) int {
+		if start.IsZero() {
+			start = now
+		}
+
+		return r.At(now.Sub(start))
+	}
+}
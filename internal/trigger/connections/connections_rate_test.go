@@ -0,0 +1,64 @@
+package connections_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/trigger/connections"
+)
+
+func TestRamp(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name     string
+		start    int
+		end      int
+		duration time.Duration
+		expected map[time.Duration]int
+	}{
+		{
+			name:     "ramps up and holds",
+			start:    0,
+			end:      100,
+			duration: 10 * time.Second,
+			expected: map[time.Duration]int{0: 0, 2500 * time.Millisecond: 25, 10 * time.Second: 100, time.Minute: 100},
+		},
+		{
+			name:     "ramps down",
+			start:    100,
+			end:      10,
+			duration: 9 * time.Second,
+			expected: map[time.Duration]int{0: 100, 3 * time.Second: 70, 9 * time.Second: 10},
+		},
+		{
+			name:     "holds without a ramp",
+			start:    0,
+			end:      50,
+			duration: 0,
+			expected: map[time.Duration]int{0: 50, time.Second: 50},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			ramp, err := connections.NewRamp(test.start, test.end, test.duration)
+			require.NoError(t, err)
+
+			for elapsed, expected := range test.expected {
+				assert.Equal(t, expected, ramp.At(elapsed), "connections after %s", elapsed)
+			}
+		})
+	}
+}
+
+func TestRampRejectsNegativeConnections(t *testing.T) {
+	t.Parallel()
+
+	_, err := connections.NewRamp(-1, 10, time.Second)
+
+	require.EqualError(t, err, "start-connections and end-connections can't be negative")
+}
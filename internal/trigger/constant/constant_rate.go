@@ -18,7 +18,8 @@ const (
 func Rate() api.Builder {
 	flags := pflag.NewFlagSet("constant", pflag.ContinueOnError)
 	flags.StringP(flagRate, "r", "1/s",
-		"number of iterations to start per interval, in the form <request>/<duration>")
+		"number of iterations to start per interval, in the form <request>/<duration>, "+
+			"where <request> can be fractional for rates below one iteration per interval, e.g. 0.2/s")
 
 	triggerflags.JitterFlag(flags)
 	triggerflags.DistributionFlag(flags)
@@ -40,23 +41,28 @@ func Rate() api.Builder {
 			if err != nil {
 				return nil, fmt.Errorf("getting flag: %w", err)
 			}
+			distributionWindow, err := params.GetDuration(triggerflags.FlagDistributionWindow)
+			if err != nil {
+				return nil, fmt.Errorf("getting flag: %w", err)
+			}
 
-			rates, err := CalculateConstantRate(jitterArg, rateArg, distributionTypeArg)
+			rates, err := CalculateConstantRate(jitterArg, rateArg, distributionTypeArg, distributionWindow)
 			if err != nil {
 				return nil, fmt.Errorf("calculating constant rate: %w", err)
 			}
 
-			return &api.Trigger{
-					Trigger:     api.NewIterationWorker(rates.IterationDuration, rates.Rate),
-					Description: fmt.Sprintf("%s constant rate, using distribution %s", rateArg, distributionTypeArg),
-					DryRun:      rates.Rate,
-				},
-				nil
+			description := fmt.Sprintf("%s constant rate, using distribution %s", rateArg, distributionTypeArg)
+
+			return api.NewRatesTrigger(rates, description), nil
 		},
 	}
 }
 
-func CalculateConstantRate(jitterArg float64, rateArg, distributionTypeArg string) (*api.Rates, error) {
+func CalculateConstantRate(
+	jitterArg float64,
+	rateArg, distributionTypeArg string,
+	distributionWindow time.Duration,
+) (*api.Rates, error) {
 	rate, iterationDuration, err := rate.ParseRate(rateArg)
 	if err != nil {
 		return nil, fmt.Errorf("unable to parse rate %s: %w", rateArg, err)
@@ -64,7 +70,7 @@ func CalculateConstantRate(jitterArg float64, rateArg, distributionTypeArg strin
 
 	rateFn := api.WithJitter(func(time.Time) int { return rate }, jitterArg)
 	distributedIterationDuration, distributedRateFn, err := api.NewDistribution(
-		api.DistributionType(distributionTypeArg), iterationDuration, rateFn, nil,
+		api.DistributionType(distributionTypeArg), distributionWindow, iterationDuration, rateFn, nil,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("new distribution: %w", err)
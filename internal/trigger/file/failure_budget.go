@@ -0,0 +1,68 @@
+package file
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/progress"
+)
+
+// ErrFailureBudgetExceeded fails runs in which the iterations of a stage failed more than allowed by
+// the max-failures or the max-failures-rate of the stage.
+var ErrFailureBudgetExceeded = errors.New("stage failure budget exceeded")
+
+// failureBudgetCheckInterval is how often the failures of a stage are checked against its budget
+const failureBudgetCheckInterval = 100 * time.Millisecond
+
+// failureBudget is how many, or which percentage, of the iterations completed during a stage may
+// fail before the run is stopped and failed. A max-failures of 0 allows no failures at all.
+type failureBudget struct {
+	maxFailures     *uint64
+	maxFailuresRate *int
+}
+
+func (b failureBudget) isSet() bool {
+	return b.maxFailures != nil || b.maxFailuresRate != nil
+}
+
+// exceeded returns why the iterations completed between the stats before and after exceeded the
+// budget, or an empty string if they didn't. The rate is only checked at the end of the stage, as
+// the rate of its first iterations is meaningless.
+func (b failureBudget) exceeded(before, after progress.Snapshot, endOfStage bool) string {
+	failed := after.FailedIterationDurations.Count - before.FailedIterationDurations.Count
+	if b.maxFailures != nil && failed > *b.maxFailures {
+		return fmt.Sprintf("%d failed iterations, more than max-failures %d", failed, *b.maxFailures)
+	}
+
+	if b.maxFailuresRate == nil || !endOfStage {
+		return ""
+	}
+	iterations := after.Iterations() - before.Iterations()
+	if iterations == 0 {
+		return ""
+	}
+	rate := 100 * float64(failed) / float64(iterations)
+	if rate > float64(*b.maxFailuresRate) {
+		return fmt.Sprintf("%.2f%% failed iterations, more than max-failures-rate %d%%", rate, *b.maxFailuresRate)
+	}
+
+	return ""
+}
+
+// failureBudget returns the failure budget of the stage, or of the default stage if it has none.
+func (s *Stage) failureBudget(idx int, defaults Stage) (failureBudget, error) {
+	budget := failureBudget{maxFailures: s.MaxFailures, maxFailuresRate: s.MaxFailuresRate}
+	if budget.maxFailures == nil {
+		budget.maxFailures = defaults.MaxFailures
+	}
+	if budget.maxFailuresRate == nil {
+		budget.maxFailuresRate = defaults.MaxFailuresRate
+	}
+
+	if rate := budget.maxFailuresRate; rate != nil && (*rate < 0 || *rate > 100) {
+		return failureBudget{}, fmt.Errorf("max-failures-rate must be between 0 and 100 at stage %d", idx)
+	}
+
+	return budget, nil
+}
@@ -3,10 +3,12 @@ package file
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	"gopkg.in/yaml.v3"
 
+	"github.com/form3tech-oss/f1/v2/internal/trigger/api"
 	"github.com/form3tech-oss/f1/v2/internal/trigger/constant"
 	"github.com/form3tech-oss/f1/v2/internal/trigger/gaussian"
 	"github.com/form3tech-oss/f1/v2/internal/trigger/ramp"
@@ -14,11 +16,21 @@ import (
 )
 
 type ConfigFile struct {
-	Scenario *string  `yaml:"scenario"`
-	Default  Stage    `yaml:"default"`
-	Limits   Limits   `yaml:"limits"`
-	Schedule Schedule `yaml:"schedule"`
-	Stages   []Stage  `yaml:"stages"`
+	Scenario        *string                    `yaml:"scenario"`
+	ParameterSchema map[string]ParameterSchema `yaml:"parameter-schema"`
+	Default         Stage                      `yaml:"default"`
+	Limits          Limits                     `yaml:"limits"`
+	Schedule        Schedule                   `yaml:"schedule"`
+	Stages          []Stage                    `yaml:"stages"`
+	Operations      map[string]Operation       `yaml:"operations"`
+}
+
+// Operation is a named operation of the scenario, triggered by its own stages at the same time
+// as the other operations.
+type Operation struct {
+	// ConcurrencyQuota optionally caps the workers the operation may use, out of the concurrency of the run
+	ConcurrencyQuota *int    `yaml:"concurrency-quota"`
+	Stages           []Stage `yaml:"stages"`
 }
 
 type Schedule struct {
@@ -40,6 +52,7 @@ type Stage struct {
 	EndRate            *string            `yaml:"end-rate"`
 	Rate               *string            `yaml:"rate"`
 	Distribution       *string            `yaml:"distribution"`
+	DistributionWindow *time.Duration     `yaml:"distribution-window"`
 	Weights            *string            `yaml:"weights"`
 	Stages             *string            `yaml:"stages"`
 	Concurrency        *int               `yaml:"concurrency"`
@@ -51,6 +64,11 @@ type Stage struct {
 	Peak               *time.Duration     `yaml:"peak"`
 	StandardDeviation  *time.Duration     `yaml:"standard-deviation"`
 	Parameters         *map[string]string `yaml:"parameters"`
+	MaxFailures        *uint64            `yaml:"max-failures"`
+	MaxFailuresRate    *int               `yaml:"max-failures-rate"`
+	// Name and Description optionally tell operators what the stage is for, in the progress of the run
+	Name        *string `yaml:"name"`
+	Description *string `yaml:"description"`
 }
 
 func ParseConfigFile(fileContent []byte, now time.Time) (*RunnableStages, error) {
@@ -63,29 +81,27 @@ func ParseConfigFile(fileContent []byte, now time.Time) (*RunnableStages, error)
 	if err != nil {
 		return nil, err
 	}
+	if err := validatedConfigFile.validateParameterSchema(); err != nil {
+		return nil, err
+	}
 
-	var stages []runnableStage
-	stagesTotalDuration := 0 * time.Second
-	for idx, stageConfig := range validatedConfigFile.Stages {
-		validatedStage, err := stageConfig.validateCommonFieldsOfStage(idx, validatedConfigFile.Default)
-		if err != nil {
-			return nil, err
-		}
-		stagesTotalDuration += *validatedStage.Duration
+	stages, stagesTotalDuration, err := validatedConfigFile.parseStages(validatedConfigFile.Stages, now)
+	if err != nil {
+		return nil, err
+	}
 
-		stageStart := validatedConfigFile.Schedule.StageStart
-		if stageStart == nil || stageStart.Add(stagesTotalDuration).After(now) {
-			parsedStage, err := validatedStage.parseStage(idx, validatedConfigFile.Default)
-			if err != nil {
-				return nil, err
-			}
-			stages = append(stages, *parsedStage)
-		}
+	operations, err := validatedConfigFile.parseOperations(now)
+	if err != nil {
+		return nil, err
+	}
+	for _, operation := range operations {
+		stagesTotalDuration = max(stagesTotalDuration, operation.stagesTotalDuration)
 	}
 
 	return &RunnableStages{
 		Scenario:            *validatedConfigFile.Scenario,
 		Stages:              stages,
+		Operations:          operations,
 		stagesTotalDuration: stagesTotalDuration,
 		MaxDuration:         *validatedConfigFile.Limits.MaxDuration,
 		Concurrency:         *validatedConfigFile.Limits.Concurrency,
@@ -96,6 +112,92 @@ func ParseConfigFile(fileContent []byte, now time.Time) (*RunnableStages, error)
 	}, nil
 }
 
+// parseStages parses the stages which have not completed before now, when the config has a
+// schedule, returning them with the total duration of all the stages.
+func (c *ConfigFile) parseStages(stageConfigs []Stage, now time.Time) ([]runnableStage, time.Duration, error) {
+	if err := validateStageNames(stageConfigs); err != nil {
+		return nil, 0, err
+	}
+
+	var stages []runnableStage
+	stagesTotalDuration := 0 * time.Second
+	for idx, stageConfig := range stageConfigs {
+		validatedStage, err := stageConfig.validateCommonFieldsOfStage(idx, c.Default)
+		if err != nil {
+			return nil, 0, err
+		}
+		stagesTotalDuration += *validatedStage.Duration
+
+		err = c.validateStageParameters(idx, validatedStage.parameters(c.Default))
+		if err != nil {
+			return nil, 0, err
+		}
+
+		stageStart := c.Schedule.StageStart
+		if stageStart == nil || stageStart.Add(stagesTotalDuration).After(now) {
+			parsedStage, err := validatedStage.parseStage(idx, c.Default)
+			if err != nil {
+				return nil, 0, err
+			}
+			parsedStage.Name = validatedStage.name(idx, len(stageConfigs))
+			if validatedStage.Description != nil {
+				parsedStage.Description = *validatedStage.Description
+			}
+			parsedStage.FailureBudget, err = validatedStage.failureBudget(idx, c.Default)
+			if err != nil {
+				return nil, 0, err
+			}
+			stages = append(stages, *parsedStage)
+		}
+	}
+
+	return stages, stagesTotalDuration, nil
+}
+
+// parseOperations parses the stages of every operation, sorted by the name of the operation.
+func (c *ConfigFile) parseOperations(now time.Time) ([]runnableOperation, error) {
+	names := make([]string, 0, len(c.Operations))
+	for name := range c.Operations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	operations := make([]runnableOperation, 0, len(names))
+	for _, name := range names {
+		stageConfigs := c.Operations[name].Stages
+
+		// the parameters of a stage are set as environment variables of the process, which
+		// would be shared by the operations running at the same time
+		for idx, stageConfig := range stageConfigs {
+			if len(stageConfig.parameters(c.Default)) > 0 {
+				return nil, fmt.Errorf("operation %s: parameters are not supported by operations, at stage %d", name, idx)
+			}
+			// the failures of the operations running at the same time are counted together
+			if budget, _ := stageConfig.failureBudget(idx, c.Default); budget.isSet() {
+				return nil, fmt.Errorf("operation %s: failure budgets are not supported by operations, at stage %d",
+					name, idx)
+			}
+		}
+
+		stages, stagesTotalDuration, err := c.parseStages(stageConfigs, now)
+		if err != nil {
+			return nil, fmt.Errorf("operation %s: %w", name, err)
+		}
+
+		operation := runnableOperation{
+			Name:                name,
+			Stages:              stages,
+			stagesTotalDuration: stagesTotalDuration,
+		}
+		if quota := c.Operations[name].ConcurrencyQuota; quota != nil {
+			operation.ConcurrencyQuota = *quota
+		}
+		operations = append(operations, operation)
+	}
+
+	return operations, nil
+}
+
 func (s *Stage) parseStage(stageIdx int, defaults Stage) (*runnableStage, error) {
 	switch *s.Mode {
 	case "constant":
@@ -107,6 +209,7 @@ func (s *Stage) parseStage(stageIdx int, defaults Stage) (*runnableStage, error)
 			*validatedConstantStage.Jitter,
 			*validatedConstantStage.Rate,
 			*validatedConstantStage.Distribution,
+			*validatedConstantStage.DistributionWindow,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("calculating constant rate: %w", err)
@@ -127,6 +230,7 @@ func (s *Stage) parseStage(stageIdx int, defaults Stage) (*runnableStage, error)
 			*validatedRampStage.StartRate,
 			*validatedRampStage.EndRate,
 			*validatedRampStage.Distribution,
+			*validatedRampStage.DistributionWindow,
 			*validatedRampStage.Duration,
 			*validatedRampStage.Jitter,
 		)
@@ -150,6 +254,7 @@ func (s *Stage) parseStage(stageIdx int, defaults Stage) (*runnableStage, error)
 			*validatedStagedStage.IterationFrequency,
 			*validatedStagedStage.Stages,
 			*validatedStagedStage.Distribution,
+			*validatedStagedStage.DistributionWindow,
 			nil,
 		)
 		if err != nil {
@@ -171,6 +276,7 @@ func (s *Stage) parseStage(stageIdx int, defaults Stage) (*runnableStage, error)
 			*validatedGaussianStage.Volume, *validatedGaussianStage.Jitter, *validatedGaussianStage.Repeat,
 			*validatedGaussianStage.IterationFrequency, *validatedGaussianStage.Peak, *validatedGaussianStage.StandardDeviation,
 			*validatedGaussianStage.Weights, *validatedGaussianStage.Distribution,
+			*validatedGaussianStage.DistributionWindow,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("calculating gaussian rate: %w", err)
@@ -213,9 +319,24 @@ func (c *ConfigFile) validateCommonFields() (*ConfigFile, error) {
 	if c.Limits.IgnoreDropped == nil {
 		return nil, errors.New("missing ignore-dropped")
 	}
-	if len(c.Stages) == 0 {
+	if len(c.Stages) == 0 && len(c.Operations) == 0 {
 		return nil, errors.New("missing stages")
 	}
+	if len(c.Stages) > 0 && len(c.Operations) > 0 {
+		return nil, errors.New("stages and operations can't be used together")
+	}
+	for name, operation := range c.Operations {
+		if len(operation.Stages) == 0 {
+			return nil, fmt.Errorf("missing stages of operation %s", name)
+		}
+		if operation.ConcurrencyQuota != nil && *operation.ConcurrencyQuota <= 0 {
+			return nil, fmt.Errorf("concurrency-quota of operation %s must be positive", name)
+		}
+		if operation.ConcurrencyQuota != nil && *operation.ConcurrencyQuota > *c.Limits.Concurrency {
+			return nil, fmt.Errorf("concurrency-quota of operation %s can't exceed the concurrency %d of the run",
+				name, *c.Limits.Concurrency)
+		}
+	}
 
 	if c.Limits.MaxFailures == nil {
 		maxFailures := uint64(0)
@@ -228,6 +349,10 @@ func (c *ConfigFile) validateCommonFields() (*ConfigFile, error) {
 	if c.Default.Concurrency == nil {
 		c.Default.Concurrency = c.Limits.Concurrency
 	}
+	if c.Default.DistributionWindow == nil {
+		distributionWindow := api.DefaultDistributionWindow
+		c.Default.DistributionWindow = &distributionWindow
+	}
 
 	return c, nil
 }
@@ -249,6 +374,47 @@ func (s *Stage) validateCommonFieldsOfStage(idx int, defaults Stage) (*Stage, er
 	return s, nil
 }
 
+// validateStageNames returns an error if a stage name is empty or used by more than one stage, as
+// stages are jumped to by name.
+func validateStageNames(stageConfigs []Stage) error {
+	names := map[string]int{}
+	for idx, stageConfig := range stageConfigs {
+		if stageConfig.Name == nil {
+			continue
+		}
+		if *stageConfig.Name == "" {
+			return fmt.Errorf("empty name at stage %d", idx)
+		}
+		if previous, ok := names[*stageConfig.Name]; ok {
+			return fmt.Errorf("name %q of stage %d is already used by stage %d", *stageConfig.Name, idx, previous)
+		}
+		names[*stageConfig.Name] = idx
+	}
+
+	return nil
+}
+
+// name returns the name of the stage at idx of count stages, counted from 1 for the stages named in
+// the config, such as "stage 3/7: black-friday peak", and from 0 with their mode for the others.
+func (s *Stage) name(idx, count int) string {
+	if s.Name == nil {
+		return fmt.Sprintf("stage %d (%s)", idx, *s.Mode)
+	}
+
+	return fmt.Sprintf("stage %d/%d: %s", idx+1, count, *s.Name)
+}
+
+func (s *Stage) parameters(defaults Stage) map[string]string {
+	if s.Parameters != nil {
+		return *s.Parameters
+	}
+	if defaults.Parameters != nil {
+		return *defaults.Parameters
+	}
+
+	return map[string]string{}
+}
+
 func (s *Stage) validateConstantStage(idx int, defaults Stage) (*Stage, error) {
 	if s.Rate == nil {
 		if defaults.Rate == nil {
@@ -262,6 +428,9 @@ func (s *Stage) validateConstantStage(idx int, defaults Stage) (*Stage, error) {
 		}
 		s.Distribution = defaults.Distribution
 	}
+	if s.DistributionWindow == nil {
+		s.DistributionWindow = defaults.DistributionWindow
+	}
 	if s.Jitter == nil {
 		s.Jitter = defaults.Jitter
 	}
@@ -295,6 +464,9 @@ func (s *Stage) validateRampStage(idx int, defaults Stage) (*Stage, error) {
 		}
 		s.Distribution = defaults.Distribution
 	}
+	if s.DistributionWindow == nil {
+		s.DistributionWindow = defaults.DistributionWindow
+	}
 	if s.Jitter == nil {
 		s.Jitter = defaults.Jitter
 	}
@@ -328,6 +500,9 @@ func (s *Stage) validateStagedStage(idx int, defaults Stage) (*Stage, error) {
 		}
 		s.Distribution = defaults.Distribution
 	}
+	if s.DistributionWindow == nil {
+		s.DistributionWindow = defaults.DistributionWindow
+	}
 	if s.Jitter == nil {
 		s.Jitter = defaults.Jitter
 	}
@@ -385,6 +560,9 @@ func (s *Stage) validateGaussianStage(idx int, defaults Stage) (*Stage, error) {
 		}
 		s.Distribution = defaults.Distribution
 	}
+	if s.DistributionWindow == nil {
+		s.DistributionWindow = defaults.DistributionWindow
+	}
 	if s.Jitter == nil {
 		s.Jitter = defaults.Jitter
 	}
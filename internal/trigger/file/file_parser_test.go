@@ -382,6 +382,87 @@ stages:
 			expectedRates:             []int{6, 6, 6, 6, 6, 6},
 			expectedParameters:        map[string]string{"FOO": "bar"},
 		},
+		{
+			testName: "Constant mode with a regular distribution",
+			fileContent: `
+scenario: template
+limits:
+  max-duration: 1m
+  concurrency: 50
+  max-iterations: 100
+  ignore-dropped: true
+stages:
+- duration: 5s
+  mode: constant
+  rate: 10/s
+  jitter: 0
+  distribution: regular
+`,
+			expectedScenario:          "template",
+			expectedMaxDuration:       1 * time.Minute,
+			expectedConcurrency:       50,
+			expectedMaxIterations:     100,
+			expectedIgnoreDropped:     true,
+			expectedTotalDuration:     5 * time.Second,
+			expectedIterationDuration: 100 * time.Millisecond,
+			expectedRates:             []int{1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
+			expectedParameters:        map[string]string{},
+		},
+		{
+			testName: "Constant mode with a distribution window",
+			fileContent: `
+scenario: template
+limits:
+  max-duration: 1m
+  concurrency: 50
+  max-iterations: 100
+  ignore-dropped: true
+stages:
+- duration: 5s
+  mode: constant
+  rate: 10/s
+  jitter: 0
+  distribution: regular
+  distribution-window: 500ms
+`,
+			expectedScenario:          "template",
+			expectedMaxDuration:       1 * time.Minute,
+			expectedConcurrency:       50,
+			expectedMaxIterations:     100,
+			expectedIgnoreDropped:     true,
+			expectedTotalDuration:     5 * time.Second,
+			expectedIterationDuration: 500 * time.Millisecond,
+			expectedRates:             []int{5, 5, 5, 5},
+			expectedParameters:        map[string]string{},
+		},
+		{
+			testName: "Constant mode using a default distribution window",
+			fileContent: `
+scenario: template
+default:
+  distribution-window: 250ms
+limits:
+  max-duration: 1m
+  concurrency: 50
+  max-iterations: 100
+  ignore-dropped: true
+stages:
+- duration: 5s
+  mode: constant
+  rate: 10/s
+  jitter: 0
+  distribution: regular
+`,
+			expectedScenario:          "template",
+			expectedMaxDuration:       1 * time.Minute,
+			expectedConcurrency:       50,
+			expectedMaxIterations:     100,
+			expectedIgnoreDropped:     true,
+			expectedTotalDuration:     5 * time.Second,
+			expectedIterationDuration: 250 * time.Millisecond,
+			expectedRates:             []int{2, 3, 2, 3},
+			expectedParameters:        map[string]string{},
+		},
 	} {
 		t.Run(test.testName, func(t *testing.T) {
 			t.Parallel()
@@ -414,6 +495,90 @@ stages:
 	}
 }
 
+func TestFileRate_Operations(t *testing.T) {
+	t.Parallel()
+
+	now, _ := time.Parse(time.RFC3339, "2020-12-10T10:00:00+00:00")
+
+	runnableStages, err := file.ParseConfigFile([]byte(`
+scenario: template
+default:
+  mode: constant
+  jitter: 0
+  distribution: none
+limits:
+  max-duration: 1m
+  concurrency: 50
+  max-iterations: 100
+  ignore-dropped: true
+operations:
+  write:
+    concurrency-quota: 10
+    stages:
+    - duration: 10s
+      rate: 20/s
+  read:
+    stages:
+    - duration: 5s
+      rate: 500/s
+    - duration: 20s
+      mode: users
+      concurrency: 5
+`), now)
+
+	require.NoError(t, err)
+	require.Empty(t, runnableStages.Stages)
+	require.Len(t, runnableStages.Operations, 2)
+
+	read := runnableStages.Operations[0]
+	require.Equal(t, "read", read.Name)
+	require.Len(t, read.Stages, 2)
+	require.Equal(t, 500, read.Stages[0].Rate(now))
+	require.Equal(t, 5, read.Stages[1].UsersConcurrency)
+	require.Zero(t, read.ConcurrencyQuota)
+
+	write := runnableStages.Operations[1]
+	require.Equal(t, "write", write.Name)
+	require.Len(t, write.Stages, 1)
+	require.Equal(t, 20, write.Stages[0].Rate(now))
+	require.Equal(t, 10, write.ConcurrencyQuota)
+}
+
+func TestFileRate_StageNames(t *testing.T) {
+	t.Parallel()
+
+	now, _ := time.Parse(time.RFC3339, "2020-12-10T10:00:00+00:00")
+
+	runnableStages, err := file.ParseConfigFile([]byte(`
+scenario: template
+default:
+  mode: constant
+  rate: 1/s
+  jitter: 0
+  distribution: none
+limits:
+  max-duration: 1m
+  concurrency: 50
+  max-iterations: 100
+  ignore-dropped: true
+stages:
+- duration: 10s
+  name: warm-up
+- duration: 10s
+- duration: 10s
+  name: black-friday peak
+  description: 10 times the usual traffic
+`), now)
+
+	require.NoError(t, err)
+	require.Len(t, runnableStages.Stages, 3)
+	require.Equal(t, "stage 1/3: warm-up", runnableStages.Stages[0].Name)
+	require.Empty(t, runnableStages.Stages[0].Description)
+	require.Equal(t, "stage 1 (constant)", runnableStages.Stages[1].Name)
+	require.Equal(t, "stage 3/3: black-friday peak", runnableStages.Stages[2].Name)
+	require.Equal(t, "10 times the usual traffic", runnableStages.Stages[2].Description)
+}
+
 func TestFileRate_FileErrors(t *testing.T) {
 	t.Parallel()
 
@@ -645,6 +810,192 @@ stages:
 		},
 		{
 			fileContent: `
+scenario: template
+limits:
+  max-duration: 1m
+  concurrency: 50
+  max-iterations: 100
+  ignore-dropped: true
+stages:
+- duration: 1s
+  mode: constant
+  rate: 1/s
+  distribution: none
+operations:
+  read:
+    stages:
+    - duration: 1s
+      mode: constant
+      rate: 1/s
+      distribution: none
+`,
+			expectedError: "stages and operations can't be used together",
+		},
+		{
+			fileContent: `
+scenario: template
+limits:
+  max-duration: 1m
+  concurrency: 50
+  max-iterations: 100
+  ignore-dropped: true
+operations:
+  read:
+    stages: []
+`,
+			expectedError: "missing stages of operation read",
+		},
+		{
+			fileContent: `
+scenario: template
+limits:
+  max-duration: 1m
+  concurrency: 50
+  max-iterations: 100
+  ignore-dropped: true
+operations:
+  read:
+    concurrency-quota: 0
+    stages:
+    - duration: 1s
+      mode: constant
+      rate: 1/s
+`,
+			expectedError: "concurrency-quota of operation read must be positive",
+		},
+		{
+			fileContent: `
+scenario: template
+limits:
+  max-duration: 1m
+  concurrency: 50
+  max-iterations: 100
+  ignore-dropped: true
+operations:
+  read:
+    concurrency-quota: 51
+    stages:
+    - duration: 1s
+      mode: constant
+      rate: 1/s
+`,
+			expectedError: "concurrency-quota of operation read can't exceed the concurrency 50 of the run",
+		},
+		{
+			fileContent: `
+scenario: template
+limits:
+  max-duration: 1m
+  concurrency: 50
+  max-iterations: 100
+  ignore-dropped: true
+operations:
+  read:
+    stages:
+    - duration: 1s
+      mode: constant
+      distribution: none
+`,
+			expectedError: "operation read: validating constant stage: missing rate at stage 0",
+		},
+		{
+			fileContent: `
+scenario: template
+limits:
+  max-duration: 1m
+  concurrency: 50
+  max-iterations: 100
+  ignore-dropped: true
+operations:
+  read:
+    stages:
+    - duration: 1s
+      mode: constant
+      rate: 1/s
+      distribution: none
+      parameters:
+        FOO: bar
+`,
+			expectedError: "operation read: parameters are not supported by operations, at stage 0",
+		},
+		{
+			fileContent: `
+scenario: template
+limits:
+  max-duration: 1m
+  concurrency: 50
+  max-iterations: 100
+  ignore-dropped: true
+stages:
+- duration: 1s
+  mode: constant
+  rate: 1/s
+  jitter: 0
+  distribution: none
+  max-failures-rate: 101
+`,
+			expectedError: "max-failures-rate must be between 0 and 100 at stage 0",
+		},
+		{
+			fileContent: `
+scenario: template
+limits:
+  max-duration: 1m
+  concurrency: 50
+  max-iterations: 100
+  ignore-dropped: true
+default:
+  max-failures: 0
+operations:
+  read:
+    stages:
+    - duration: 1s
+      mode: constant
+      rate: 1/s
+      distribution: none
+`,
+			expectedError: "operation read: failure budgets are not supported by operations, at stage 0",
+		},
+		{
+			fileContent: `
+scenario: template
+limits:
+  max-duration: 1m
+  concurrency: 50
+  max-iterations: 100
+  ignore-dropped: true
+default:
+  mode: constant
+  rate: 1/s
+  distribution: none
+stages:
+- duration: 1s
+  name: peak
+- duration: 1s
+  name: peak
+`,
+			expectedError: "name \"peak\" of stage 1 is already used by stage 0",
+		},
+		{
+			fileContent: `
+scenario: template
+limits:
+  max-duration: 1m
+  concurrency: 50
+  max-iterations: 100
+  ignore-dropped: true
+default:
+  mode: constant
+  rate: 1/s
+  distribution: none
+stages:
+- duration: 1s
+  name: ""
+`,
+			expectedError: "empty name at stage 0",
+		},
+		{
+			fileContent: `
 invalid file content
 `,
 			expectedError: "yaml: unmarshal errors:\n  line 2: cannot unmarshal !!str `invalid...` into file.ConfigFile",
@@ -663,6 +1014,215 @@ invalid file content
 	}
 }
 
+func TestFileRate_ParameterSchema(t *testing.T) {
+	t.Parallel()
+
+	const header = `
+scenario: template
+limits:
+  max-duration: 1m
+  concurrency: 50
+  max-iterations: 100
+  ignore-dropped: true
+parameter-schema:
+  PAYMENT_SIZE:
+    type: int
+    min: 1
+    max: 100
+    required: true
+  SCHEME:
+    allowed: [fps, sepa]
+  LATENCY:
+    type: duration
+    max: 1s
+  RATIO:
+    type: float
+    allowed: [1.0, 2.5]
+  TIMEOUT:
+    type: duration
+    allowed: [1m, 5m]
+  ACCOUNT:
+    type: int
+    allowed: [9007199254740993]
+stages:
+- duration: 1s
+  mode: constant
+  rate: 1/s
+  jitter: 0
+  distribution: none
+`
+
+	for _, test := range []struct {
+		testName, parameters, expectedError string
+	}{
+		{
+			testName: "valid parameters",
+			parameters: `
+  parameters:
+    PAYMENT_SIZE: 10
+    SCHEME: fps
+    LATENCY: 500ms
+`,
+		},
+		{
+			testName: "invalid type",
+			parameters: `
+  parameters:
+    PAYMENT_SIZE: ten
+`,
+			expectedError: "invalid parameter PAYMENT_SIZE at stage 0: ten is not an int",
+		},
+		{
+			testName: "less than min",
+			parameters: `
+  parameters:
+    PAYMENT_SIZE: 0
+`,
+			expectedError: "invalid parameter PAYMENT_SIZE at stage 0: 0 is less than min 1",
+		},
+		{
+			testName: "greater than max",
+			parameters: `
+  parameters:
+    PAYMENT_SIZE: 1
+    LATENCY: 2s
+`,
+			expectedError: "invalid parameter LATENCY at stage 0: 2s is greater than max 1s",
+		},
+		{
+			testName: "not allowed",
+			parameters: `
+  parameters:
+    PAYMENT_SIZE: 1
+    SCHEME: bacs
+`,
+			expectedError: "invalid parameter SCHEME at stage 0: bacs is not one of fps, sepa",
+		},
+		{
+			testName: "allowed once parsed",
+			parameters: `
+  parameters:
+    PAYMENT_SIZE: 1
+    RATIO: 1
+    TIMEOUT: 60s
+`,
+		},
+		{
+			testName: "not allowed once parsed",
+			parameters: `
+  parameters:
+    PAYMENT_SIZE: 1
+    TIMEOUT: 90s
+`,
+			expectedError: "invalid parameter TIMEOUT at stage 0: 90s is not one of 1m, 5m",
+		},
+		{
+			testName: "int allowed beyond the precision of a float",
+			parameters: `
+  parameters:
+    PAYMENT_SIZE: 1
+    ACCOUNT: 9007199254740993
+`,
+		},
+		{
+			testName: "int not allowed beyond the precision of a float",
+			parameters: `
+  parameters:
+    PAYMENT_SIZE: 1
+    ACCOUNT: 9007199254740992
+`,
+			expectedError: "invalid parameter ACCOUNT at stage 0: 9007199254740992 is not one of 9007199254740993",
+		},
+		{
+			testName: "not declared",
+			parameters: `
+  parameters:
+    PAYMENT_SIZE: 1
+    FOO: bar
+`,
+			expectedError: "parameter FOO at stage 0 is not declared in parameter-schema",
+		},
+		{
+			testName:      "missing required",
+			parameters:    "",
+			expectedError: "missing required parameter PAYMENT_SIZE at stage 0",
+		},
+	} {
+		t.Run(test.testName, func(t *testing.T) {
+			t.Parallel()
+
+			now, _ := time.Parse(time.RFC3339, "2020-12-10T10:00:00+00:00")
+
+			runnableStages, err := file.ParseConfigFile([]byte(header+test.parameters), now)
+
+			if test.expectedError == "" {
+				require.NoError(t, err)
+				require.NotNil(t, runnableStages)
+				return
+			}
+
+			require.Nil(t, runnableStages)
+			require.EqualError(t, err, test.expectedError)
+		})
+	}
+}
+
+func TestFileRate_InvalidParameterSchema(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		schema, expectedError string
+	}{
+		{
+			schema: `
+  FOO:
+    type: number
+`,
+			expectedError: "unknown type number of parameter FOO",
+		},
+		{
+			schema: `
+  FOO:
+    type: bool
+    min: 1
+`,
+			expectedError: "min and max are not supported by bool parameter FOO",
+		},
+		{
+			schema: `
+  FOO:
+    type: float
+    max: high
+`,
+			expectedError: "invalid limit of parameter FOO: high is not a float",
+		},
+	} {
+		t.Run(test.expectedError, func(t *testing.T) {
+			t.Parallel()
+
+			now, _ := time.Parse(time.RFC3339, "2020-12-10T10:00:00+00:00")
+
+			runnableStages, err := file.ParseConfigFile([]byte(`
+scenario: template
+limits:
+  max-duration: 1m
+  concurrency: 50
+  max-iterations: 100
+  ignore-dropped: true
+stages:
+- duration: 1s
+  mode: constant
+  rate: 1/s
+  jitter: 0
+  distribution: none
+parameter-schema:`+test.schema), now)
+
+			require.Nil(t, runnableStages)
+			require.ErrorContains(t, err, test.expectedError)
+		})
+	}
+}
+
 type testData struct {
 	testName                  string
 	fileContent               string
@@ -1,8 +1,10 @@
 package file
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"time"
@@ -16,6 +18,7 @@ import (
 type RunnableStages struct {
 	Scenario            string
 	Stages              []runnableStage
+	Operations          []runnableOperation
 	stagesTotalDuration time.Duration
 	MaxDuration         time.Duration
 	Concurrency         int
@@ -25,15 +28,36 @@ type RunnableStages struct {
 	IgnoreDropped       bool
 }
 
+// runnableOperation is a named operation of the scenario, whose stages run at the same time as
+// the stages of the other operations.
+type runnableOperation struct {
+	Name                string
+	Stages              []runnableStage
+	stagesTotalDuration time.Duration
+	// ConcurrencyQuota caps the workers of the operation, if set
+	ConcurrencyQuota int
+}
+
 type runnableStage struct {
 	Rate              api.RateFunction
+	Name              string
 	Params            map[string]string
 	StageDuration     time.Duration
 	IterationDuration time.Duration
 	UsersConcurrency  int
+	// FailureBudget stops and fails the run when the iterations of the stage fail more than it allows
+	FailureBudget failureBudget
+	// Description optionally tells operators what the stage is for, displayed when it starts
+	Description string
 }
 
-func Rate(output *ui.Output) api.Builder {
+// EmbeddedPrefix is the prefix of config file names which are read from the profiles embedded
+// in the scenario binary rather than from the file system.
+const EmbeddedPrefix = "embedded://"
+
+// Rate builds the file trigger. Profiles are the config files embedded in the scenario binary,
+// which are run with "embedded://<name>"; it may be nil.
+func Rate(output *ui.Output, profiles fs.FS) api.Builder {
 	flags := pflag.NewFlagSet("file", pflag.ContinueOnError)
 
 	return api.Builder{
@@ -41,21 +65,29 @@ func Rate(output *ui.Output) api.Builder {
 		Description: "triggers test iterations from a yaml config file",
 		Flags:       flags,
 		New: func(flags *pflag.FlagSet) (*api.Trigger, error) {
-			filename := flags.Arg(0)
-			fileContent, err := readFile(filename, output)
+			fileContent, err := ReadConfigFile(flags.Arg(0), profiles, output)
 			if err != nil {
 				return nil, err
 			}
-			runnableStages, err := ParseConfigFile(*fileContent, time.Now())
+			runnableStages, err := ParseConfigFile(fileContent, time.Now())
 			if err != nil {
 				return nil, err
 			}
 
-			return &api.Trigger{
-				Trigger:     newStagesWorker(runnableStages.Stages),
+			plan := newStagePlan(runnableStages.Stages)
+			trigger := &api.Trigger{
+				Trigger:     newStagesWorker(runnableStages.Stages, plan),
 				DryRun:      newDryRun(runnableStages.Stages),
 				Description: fmt.Sprintf("%d different stages", len(runnableStages.Stages)),
 				Duration:    runnableStages.stagesTotalDuration,
+				StageAt:     plan.stageAt,
+				Stages:      plan.names(),
+				PeakRate: func(time.Duration) int {
+					return runnableStages.Profile(time.Now()).PeakRate()
+				},
+				PlannedIterations: func(from, to time.Duration) uint64 {
+					return runnableStages.Profile(time.Now()).IterationsBetween(from, to)
+				},
 				Options: api.Options{
 					Scenario:        runnableStages.Scenario,
 					MaxDuration:     runnableStages.MaxDuration,
@@ -65,12 +97,44 @@ func Rate(output *ui.Output) api.Builder {
 					MaxFailuresRate: runnableStages.maxFailuresRate,
 					IgnoreDropped:   runnableStages.IgnoreDropped,
 				},
-			}, nil
+			}
+
+			if len(runnableStages.Operations) > 0 {
+				trigger.Trigger = newOperationsWorker(runnableStages.Operations)
+				trigger.DryRun = newOperationsDryRun(runnableStages.Operations)
+				trigger.Description = operationsDescription(runnableStages.Operations)
+				trigger.StageAt = newOperationsStageAt(runnableStages.Operations)
+				// the stages of operations run at the same time, and can't be skipped
+				trigger.Stages = nil
+			}
+
+			return trigger, nil
 		},
 		IgnoreCommonFlags: true,
 	}
 }
 
+// readProfile reads the embedded profile with the given name, with or without a yaml extension.
+func readProfile(profiles fs.FS, name string) (*[]byte, error) {
+	if profiles == nil {
+		return nil, fmt.Errorf("reading embedded profile %s: no profiles are embedded", name)
+	}
+
+	for _, filename := range []string{name, name + ".yaml", name + ".yml"} {
+		fileContent, err := fs.ReadFile(profiles, filename)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading embedded profile %s: %w", name, err)
+		}
+
+		return &fileContent, nil
+	}
+
+	return nil, fmt.Errorf("reading embedded profile %s: %w", name, fs.ErrNotExist)
+}
+
 func readFile(filename string, output *ui.Output) (*[]byte, error) {
 	file, err := os.Open(filepath.Clean(filename))
 	if err != nil {
@@ -93,6 +157,20 @@ func readFile(filename string, output *ui.Output) (*[]byte, error) {
 	return &fileContent, nil
 }
 
+// newStageAt returns the name of the stage running after the given duration of the run.
+func newStageAt(stages []runnableStage) func(time.Duration) string {
+	return func(elapsed time.Duration) string {
+		for _, stage := range stages {
+			if elapsed < stage.StageDuration {
+				return stage.Name
+			}
+			elapsed -= stage.StageDuration
+		}
+
+		return ""
+	}
+}
+
 func newDryRun(stagesToRun []runnableStage) api.RateFunction {
 	var startTime time.Time
 	started := false
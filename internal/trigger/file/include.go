@@ -0,0 +1,179 @@
+package file
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+)
+
+// includeTag is the yaml tag of a value read from another file, given by its path relative to the
+// including file: `!include ramps/warm-up.yaml`.
+const includeTag = "!include"
+
+var errEmptyIncludedFile = errors.New("the file is empty")
+
+// configSource reads config files and the files they include.
+type configSource struct {
+	read func(name string) ([]byte, error)
+	// resolve returns the name of the file included as name by the file from
+	resolve func(from, name string) string
+}
+
+// ReadConfigFile reads a config file, from the file system or from the embedded profiles when its
+// name has the embedded prefix, and replaces the values tagged with !include by the content of the
+// files they name. Included files are read from the same place as the config file.
+func ReadConfigFile(filename string, profiles fs.FS, output *ui.Output) ([]byte, error) {
+	source := configSource{
+		read: func(name string) ([]byte, error) {
+			fileContent, err := readFile(name, output)
+			if err != nil {
+				return nil, err
+			}
+			return *fileContent, nil
+		},
+		resolve: func(from, name string) string {
+			if filepath.IsAbs(name) {
+				return name
+			}
+			return filepath.Join(filepath.Dir(from), name)
+		},
+	}
+
+	if name, ok := strings.CutPrefix(filename, EmbeddedPrefix); ok {
+		source = configSource{
+			read: func(name string) ([]byte, error) {
+				fileContent, err := readProfile(profiles, name)
+				if err != nil {
+					return nil, err
+				}
+				return *fileContent, nil
+			},
+			resolve: func(from, name string) string {
+				return path.Join(path.Dir(from), name)
+			},
+		}
+		filename = name
+	}
+
+	fileContent, err := source.read(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return source.resolveIncludes(fileContent, filename)
+}
+
+// resolveIncludes returns the content of the config file filename with the values it includes
+// replaced by the content of the files they name.
+func (s configSource) resolveIncludes(fileContent []byte, filename string) ([]byte, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(fileContent, &document); err != nil {
+		return nil, fmt.Errorf("parsing config file as yaml: %w", err)
+	}
+	if !hasInclude(&document) {
+		return fileContent, nil
+	}
+
+	if err := s.include(&document, []string{filename}); err != nil {
+		return nil, err
+	}
+
+	resolved, err := yaml.Marshal(&document)
+	if err != nil {
+		return nil, fmt.Errorf("writing config file with its included files: %w", err)
+	}
+
+	return resolved, nil
+}
+
+// include replaces the values of node tagged with !include by the content of the files they
+// name. Included sequences which are items of a sequence are spliced into it, so that a file of
+// common stages can be included among other stages. includedBy are the names of the files
+// including node, from the config file.
+func (s configSource) include(node *yaml.Node, includedBy []string) error {
+	if node.Tag == includeTag {
+		included, err := s.includedFile(node, includedBy)
+		if err != nil {
+			return err
+		}
+		*node = *included
+		return nil
+	}
+
+	if node.Kind == yaml.AliasNode {
+		return nil
+	}
+
+	content := make([]*yaml.Node, 0, len(node.Content))
+	for _, child := range node.Content {
+		if node.Kind == yaml.SequenceNode && child.Tag == includeTag {
+			included, err := s.includedFile(child, includedBy)
+			if err != nil {
+				return err
+			}
+			if included.Kind == yaml.SequenceNode {
+				content = append(content, included.Content...)
+				continue
+			}
+			*child = *included
+		} else if err := s.include(child, includedBy); err != nil {
+			return err
+		}
+		content = append(content, child)
+	}
+	node.Content = content
+
+	return nil
+}
+
+// includedFile reads the file named by the !include node, with the values it includes replaced.
+func (s configSource) includedFile(node *yaml.Node, includedBy []string) (*yaml.Node, error) {
+	from := includedBy[len(includedBy)-1]
+	if node.Kind != yaml.ScalarNode || node.Value == "" {
+		return nil, fmt.Errorf("%s at line %d of %s must be followed by the path of a file",
+			includeTag, node.Line, from)
+	}
+
+	name := s.resolve(from, node.Value)
+	if slices.Contains(includedBy, name) {
+		cycle := strings.Join(append(slices.Clone(includedBy), name), " -> ")
+		return nil, fmt.Errorf("including %s: include cycle: %s", name, cycle)
+	}
+
+	fileContent, err := s.read(name)
+	if err != nil {
+		return nil, fmt.Errorf("including %s from %s: %w", name, from, err)
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal(fileContent, &document); err != nil {
+		return nil, fmt.Errorf("parsing included file %s as yaml: %w", name, err)
+	}
+	if len(document.Content) == 0 {
+		return nil, fmt.Errorf("including %s from %s: %w", name, from, errEmptyIncludedFile)
+	}
+
+	included := document.Content[0]
+	if err := s.include(included, append(slices.Clone(includedBy), name)); err != nil {
+		return nil, err
+	}
+
+	return included, nil
+}
+
+// hasInclude returns true if a value of node is tagged with !include.
+func hasInclude(node *yaml.Node) bool {
+	if node.Tag == includeTag {
+		return true
+	}
+
+	return slices.ContainsFunc(node.Content, hasInclude)
+}
@@ -0,0 +1,125 @@
+package file_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/trigger/file"
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+)
+
+const includingConfig = `
+scenario: template
+default: !include defaults.yaml
+limits:
+  max-duration: 1m
+  concurrency: 50
+  max-iterations: 100
+  ignore-dropped: true
+stages:
+- !include ramps/warm-up.yaml
+- duration: 10s
+  rate: 50/s
+- !include ramps/cool-down.yaml
+`
+
+var includedFiles = map[string]string{
+	"defaults.yaml": `
+mode: constant
+jitter: 0
+distribution: none
+`,
+	"ramps/warm-up.yaml": `
+- &step
+  duration: 5s
+  rate: 10/s
+- <<: *step
+  rate: 20/s
+`,
+	"ramps/cool-down.yaml": `!include last-step.yaml`,
+	"ramps/last-step.yaml": `
+duration: 5s
+rate: 5/s
+`,
+}
+
+func TestReadConfigFile_Includes(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	profiles := fstest.MapFS{"profiles/plan.yaml": &fstest.MapFile{Data: []byte(includingConfig)}}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "plan.yaml"), []byte(includingConfig), 0o600))
+	for name, content := range includedFiles {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0o700))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+		profiles["profiles/"+name] = &fstest.MapFile{Data: []byte(content)}
+	}
+
+	for _, filename := range []string{filepath.Join(dir, "plan.yaml"), file.EmbeddedPrefix + "profiles/plan"} {
+		t.Run(filename, func(t *testing.T) {
+			t.Parallel()
+
+			fileContent, err := file.ReadConfigFile(filename, profiles, ui.NewDiscardOutput())
+			require.NoError(t, err)
+
+			now := time.Now()
+			runnableStages, err := file.ParseConfigFile(fileContent, now)
+			require.NoError(t, err)
+
+			rates := make([]int, 0, len(runnableStages.Stages))
+			for _, stage := range runnableStages.Stages {
+				rates = append(rates, stage.Rate(now))
+			}
+			require.Equal(t, []int{10, 20, 50, 5}, rates)
+		})
+	}
+}
+
+func TestReadConfigFile_IncludeErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name          string
+		files         map[string]string
+		expectedError string
+	}{
+		{
+			name:          "missing file",
+			files:         map[string]string{"plan.yaml": "stages: !include missing.yaml"},
+			expectedError: "including missing.yaml from plan.yaml",
+		},
+		{
+			name:          "include cycle",
+			files:         map[string]string{"plan.yaml": "stages: !include a.yaml", "a.yaml": "!include plan.yaml"},
+			expectedError: "include cycle: plan.yaml -> a.yaml -> plan.yaml",
+		},
+		{
+			name:          "no path",
+			files:         map[string]string{"plan.yaml": "stages: !include [a.yaml]"},
+			expectedError: "!include at line 1 of plan.yaml must be followed by the path of a file",
+		},
+		{
+			name:          "empty file",
+			files:         map[string]string{"plan.yaml": "stages: !include a.yaml", "a.yaml": ""},
+			expectedError: "including a.yaml from plan.yaml: the file is empty",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			profiles := fstest.MapFS{}
+			for name, content := range test.files {
+				profiles[name] = &fstest.MapFile{Data: []byte(content)}
+			}
+
+			_, err := file.ReadConfigFile(file.EmbeddedPrefix+"plan.yaml", profiles, ui.NewDiscardOutput())
+
+			require.ErrorContains(t, err, test.expectedError)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package file
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/goroutines"
+	"github.com/form3tech-oss/f1/v2/internal/options"
+	"github.com/form3tech-oss/f1/v2/internal/trigger/api"
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+	"github.com/form3tech-oss/f1/v2/internal/workers"
+)
+
+// newOperationsWorker runs the stages of every operation at the same time, triggering the
+// iterations of each operation in its own worker pools, capped by its concurrency quota if set. The
+// operations share the concurrency of the run: their iterations in progress never exceed it together.
+func newOperationsWorker(operations []runnableOperation) api.WorkTriggerer {
+	return func(ctx context.Context, output *ui.Output, poolManager *workers.PoolManager, options options.RunOptions) {
+		poolManager.ShareConcurrency(options.Concurrency)
+
+		wg := sync.WaitGroup{}
+		wg.Add(len(operations))
+
+		for _, operation := range operations {
+			goroutines.Go(ctx, "operation", func(ctx context.Context) {
+				defer wg.Done()
+
+				operationPoolManager := poolManager.ForOperation(operation.Name)
+				if operation.ConcurrencyQuota > 0 {
+					operationPoolManager.LimitConcurrency(operation.ConcurrencyQuota)
+				}
+
+				doWork := newStagesWorker(operation.Stages, nil)
+				doWork(ctx, output, operationPoolManager, options)
+			})
+		}
+
+		wg.Wait()
+	}
+}
+
+// newOperationsDryRun returns the combined rate of all the operations.
+func newOperationsDryRun(operations []runnableOperation) api.RateFunction {
+	dryRuns := make([]api.RateFunction, len(operations))
+	for i, operation := range operations {
+		dryRuns[i] = newDryRun(operation.Stages)
+	}
+
+	return func(time time.Time) int {
+		rate := 0
+		for _, dryRun := range dryRuns {
+			rate += dryRun(time)
+		}
+
+		return rate
+	}
+}
+
+// newOperationsStageAt returns the stages of the operations still running after the given
+// duration of the run.
+func newOperationsStageAt(operations []runnableOperation) func(time.Duration) string {
+	stagesAt := make([]func(time.Duration) string, len(operations))
+	for i, operation := range operations {
+		stagesAt[i] = newStageAt(operation.Stages)
+	}
+
+	return func(elapsed time.Duration) string {
+		var stages []string
+		for i, stageAt := range stagesAt {
+			if stage := stageAt(elapsed); stage != "" {
+				stages = append(stages, operations[i].Name+": "+stage)
+			}
+		}
+
+		return strings.Join(stages, ", ")
+	}
+}
+
+func operationsDescription(operations []runnableOperation) string {
+	names := make([]string, len(operations))
+	for i, operation := range operations {
+		names[i] = operation.Name
+	}
+
+	return fmt.Sprintf("%d operations (%s)", len(operations), strings.Join(names, ", "))
+}
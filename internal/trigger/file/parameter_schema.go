@@ -0,0 +1,185 @@
+package file
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	parameterTypeString   = "string"
+	parameterTypeInt      = "int"
+	parameterTypeFloat    = "float"
+	parameterTypeBool     = "bool"
+	parameterTypeDuration = "duration"
+)
+
+// ParameterSchema declares the type and the allowed values of a stage parameter.
+type ParameterSchema struct {
+	Type     *string  `yaml:"type"`
+	Min      *string  `yaml:"min"`
+	Max      *string  `yaml:"max"`
+	Required *bool    `yaml:"required"`
+	Allowed  []string `yaml:"allowed"`
+}
+
+func (c *ConfigFile) validateParameterSchema() error {
+	for name, schema := range c.ParameterSchema {
+		if schema.Type == nil {
+			parameterType := parameterTypeString
+			schema.Type = &parameterType
+		}
+
+		switch *schema.Type {
+		case parameterTypeString, parameterTypeBool:
+			if schema.Min != nil || schema.Max != nil {
+				return fmt.Errorf("min and max are not supported by %s parameter %s", *schema.Type, name)
+			}
+		case parameterTypeInt, parameterTypeFloat, parameterTypeDuration:
+			for _, limit := range []*string{schema.Min, schema.Max} {
+				if limit == nil {
+					continue
+				}
+				if _, err := parseParameter(*schema.Type, *limit); err != nil {
+					return fmt.Errorf("invalid limit of parameter %s: %w", name, err)
+				}
+			}
+		default:
+			return fmt.Errorf("unknown type %s of parameter %s", *schema.Type, name)
+		}
+
+		for _, allowed := range schema.Allowed {
+			if _, err := parseParameter(*schema.Type, allowed); err != nil {
+				return fmt.Errorf("invalid allowed value of parameter %s: %w", name, err)
+			}
+		}
+
+		c.ParameterSchema[name] = schema
+	}
+
+	return nil
+}
+
+func (c *ConfigFile) validateStageParameters(idx int, parameters map[string]string) error {
+	if len(c.ParameterSchema) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(parameters))
+	for name := range parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		schema, ok := c.ParameterSchema[name]
+		if !ok {
+			return fmt.Errorf("parameter %s at stage %d is not declared in parameter-schema", name, idx)
+		}
+
+		if err := schema.validate(parameters[name]); err != nil {
+			return fmt.Errorf("invalid parameter %s at stage %d: %w", name, idx, err)
+		}
+	}
+
+	for name, schema := range c.ParameterSchema {
+		if _, ok := parameters[name]; !ok && schema.Required != nil && *schema.Required {
+			return fmt.Errorf("missing required parameter %s at stage %d", name, idx)
+		}
+	}
+
+	return nil
+}
+
+func (p ParameterSchema) validate(value string) error {
+	parsed, err := parseParameter(*p.Type, value)
+	if err != nil {
+		return err
+	}
+
+	if len(p.Allowed) > 0 && !slices.ContainsFunc(p.Allowed, func(allowed string) bool {
+		return sameParameter(*p.Type, allowed, value)
+	}) {
+		return fmt.Errorf("%s is not one of %s", value, strings.Join(p.Allowed, ", "))
+	}
+
+	if p.Min != nil {
+		minValue, _ := parseParameter(*p.Type, *p.Min)
+		if parsed < minValue {
+			return fmt.Errorf("%s is less than min %s", value, *p.Min)
+		}
+	}
+
+	if p.Max != nil {
+		maxValue, _ := parseParameter(*p.Type, *p.Max)
+		if parsed > maxValue {
+			return fmt.Errorf("%s is greater than max %s", value, *p.Max)
+		}
+	}
+
+	return nil
+}
+
+// sameParameter returns whether the values are the same value of the given type, such as 1m and
+// 60s for durations, once parsed. The values have already been validated.
+func sameParameter(parameterType, a, b string) bool {
+	switch parameterType {
+	case parameterTypeString:
+		return a == b
+	case parameterTypeBool:
+		parsedA, _ := strconv.ParseBool(a)
+		parsedB, _ := strconv.ParseBool(b)
+		return parsedA == parsedB
+	// ints and durations are compared as int64, as float64 loses the precision of values above 2^53
+	case parameterTypeInt:
+		parsedA, _ := strconv.ParseInt(a, 10, 64)
+		parsedB, _ := strconv.ParseInt(b, 10, 64)
+		return parsedA == parsedB
+	case parameterTypeDuration:
+		parsedA, _ := time.ParseDuration(a)
+		parsedB, _ := time.ParseDuration(b)
+		return parsedA == parsedB
+	default:
+		parsedA, _ := parseParameter(parameterType, a)
+		parsedB, _ := parseParameter(parameterType, b)
+		return parsedA == parsedB
+	}
+}
+
+// parseParameter parses value as the given type, returning a numeric representation used
+// to compare it against the min and max limits.
+func parseParameter(parameterType, value string) (float64, error) {
+	switch parameterType {
+	case parameterTypeInt:
+		v, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%s is not an int", value)
+		}
+		return float64(v), nil
+	case parameterTypeFloat:
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%s is not a float", value)
+		}
+		return v, nil
+	case parameterTypeDuration:
+		v, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, fmt.Errorf("%s is not a duration", value)
+		}
+		return float64(v), nil
+	case parameterTypeBool:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return 0, fmt.Errorf("%s is not a bool", value)
+		}
+		return 0, nil
+	case parameterTypeString:
+		return 0, nil
+	default:
+		return 0, errors.New("unknown parameter type " + parameterType)
+	}
+}
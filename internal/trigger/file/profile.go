@@ -0,0 +1,101 @@
+package file
+
+import (
+	"slices"
+	"time"
+)
+
+// Profile is the load planned by a config file, as the iterations started in every second of the
+// run. Users stages start no iterations at a rate, their concurrency is given by PeakUsers.
+type Profile struct {
+	Scenario  string
+	Duration  time.Duration
+	Rates     []int
+	PeakUsers int
+}
+
+// PeakRate returns the most iterations started in a second of the run.
+func (p Profile) PeakRate() int {
+	if len(p.Rates) == 0 {
+		return 0
+	}
+
+	return slices.Max(p.Rates)
+}
+
+// TotalIterations returns the number of iterations started by all the stages of the run.
+func (p Profile) TotalIterations() uint64 {
+	var total uint64
+	for _, rate := range p.Rates {
+		total += uint64(max(rate, 0))
+	}
+
+	return total
+}
+
+// IterationsBetween returns the number of iterations started in the seconds of the run from from
+// up to to.
+func (p Profile) IterationsBetween(from, to time.Duration) uint64 {
+	var total uint64
+	for second, rate := range p.Rates {
+		offset := time.Duration(second) * time.Second
+		if offset >= from && offset < to {
+			total += uint64(max(rate, 0))
+		}
+	}
+
+	return total
+}
+
+// Profile runs the rate functions of the stages from start, as the file trigger would, to return
+// the load they plan. The rate functions of the stages are left started, so the stages must not be
+// run afterwards.
+func (r *RunnableStages) Profile(start time.Time) Profile {
+	profile := Profile{Scenario: r.Scenario, Duration: r.stagesTotalDuration}
+
+	if len(r.Operations) == 0 {
+		profile.Rates, profile.PeakUsers = stagesProfile(r.Stages, start)
+		return profile
+	}
+
+	for _, operation := range r.Operations {
+		rates, users := stagesProfile(operation.Stages, start)
+		for len(profile.Rates) < len(rates) {
+			profile.Rates = append(profile.Rates, 0)
+		}
+		for i, rate := range rates {
+			profile.Rates[i] += rate
+		}
+		// the users of operations run at the same time
+		profile.PeakUsers += users
+	}
+
+	return profile
+}
+
+// stagesProfile returns the iterations started in every second of the stages, and their highest
+// users concurrency.
+func stagesProfile(stages []runnableStage, start time.Time) ([]int, int) {
+	var total time.Duration
+	for _, stage := range stages {
+		total += stage.StageDuration
+	}
+	rates := make([]int, int((total+time.Second-1)/time.Second))
+	peakUsers := 0
+
+	stageStart := start
+	for _, stage := range stages {
+		peakUsers = max(peakUsers, stage.UsersConcurrency)
+
+		if stage.Rate != nil && stage.IterationDuration > 0 {
+			stageEnd := stageStart.Add(stage.StageDuration)
+			for tick := stageStart; tick.Before(stageEnd); tick = tick.Add(stage.IterationDuration) {
+				rates[int(tick.Sub(start)/time.Second)] += stage.Rate(tick)
+			}
+		}
+
+		stageStart = stageStart.Add(stage.StageDuration)
+	}
+
+	return rates, peakUsers
+}
@@ -0,0 +1,76 @@
+package file
+
+import (
+	"sync"
+	"time"
+)
+
+// stagePlan returns the stage running after a duration of the run, following the stages skipped or
+// jumped to while the run is in progress rather than only the planned durations of the stages.
+type stagePlan struct {
+	stages []runnableStage
+
+	mu sync.RWMutex
+	// jumps are the stages jumped to, in the order they were jumped to
+	jumps []planJump
+}
+
+type planJump struct {
+	// at is the duration of the run when the stage was jumped to
+	at time.Duration
+	// start is the duration of the stages planned before the stage jumped to
+	start time.Duration
+}
+
+func newStagePlan(stages []runnableStage) *stagePlan {
+	return &stagePlan{stages: stages}
+}
+
+// stageAt returns the name of the stage running after the given duration of the run.
+func (p *stagePlan) stageAt(elapsed time.Duration) string {
+	p.mu.RLock()
+	planned := elapsed
+	for i := len(p.jumps) - 1; i >= 0; i-- {
+		if jump := p.jumps[i]; elapsed >= jump.at {
+			planned = jump.start + elapsed - jump.at
+			break
+		}
+	}
+	p.mu.RUnlock()
+
+	return newStageAt(p.stages)(planned)
+}
+
+// jumped records that the stage at index was jumped to after the given duration of the run. The
+// index of the stage after the last one records that the stages were completed early.
+func (p *stagePlan) jumped(elapsed time.Duration, index int) {
+	start := time.Duration(0)
+	for _, stage := range p.stages[:index] {
+		start += stage.StageDuration
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.jumps = append(p.jumps, planJump{at: elapsed, start: start})
+}
+
+// index returns the index of the named stage, or -1 if there is no stage with that name.
+func (p *stagePlan) index(name string) int {
+	for i, stage := range p.stages {
+		if stage.Name == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// names returns the names of the stages, in the order they are planned in.
+func (p *stagePlan) names() []string {
+	names := make([]string, len(p.stages))
+	for i, stage := range p.stages {
+		names[i] = stage.Name
+	}
+
+	return names
+}
@@ -2,10 +2,13 @@ package file
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"time"
 
+	"github.com/form3tech-oss/f1/v2/internal/goroutines"
 	"github.com/form3tech-oss/f1/v2/internal/options"
+	"github.com/form3tech-oss/f1/v2/internal/progress"
 	"github.com/form3tech-oss/f1/v2/internal/trigger/api"
 	"github.com/form3tech-oss/f1/v2/internal/trigger/users"
 	"github.com/form3tech-oss/f1/v2/internal/ui"
@@ -14,24 +17,89 @@ import (
 
 const safeDurationBeforeNextStage = 20 * time.Millisecond
 
-func newStagesWorker(stages []runnableStage) api.WorkTriggerer {
+// stageEnd is how a stage ended.
+type stageEnd int
+
+const (
+	stageCompleted stageEnd = iota
+	// stageJumped ends the stage early, on a request to skip it or to jump to another stage
+	stageJumped
+	// stageAborted ends the run, as the iterations of the stage exceeded its failure budget
+	stageAborted
+)
+
+// newStagesWorker runs the stages in order. When plan is set, the stages can be skipped or jumped to
+// while the run is in progress, see workers.PoolManager.JumpToStage, and the jumps are recorded in
+// the plan.
+func newStagesWorker(stages []runnableStage, plan *stagePlan) api.WorkTriggerer {
 	return func(ctx context.Context, output *ui.Output, workers *workers.PoolManager, options options.RunOptions) {
-		for _, stage := range stages {
+		start := time.Now()
+		var jumps <-chan string
+		if plan != nil {
+			jumps = workers.StageJumps()
+		}
+
+		elapsed := options.Elapsed
+		for i := 0; i < len(stages); {
 			if ctx.Err() != nil {
 				return
 			}
-			runStage(ctx, output, workers, stage, options)
+
+			stage := stages[i]
+			// skip the stages completed before a run was resumed
+			if elapsed >= stage.StageDuration {
+				elapsed -= stage.StageDuration
+				i++
+				continue
+			}
+			stageOptions := options
+			stageOptions.Elapsed = elapsed
+			stage.StageDuration -= elapsed
+			elapsed = 0
+
+			if stage.Description != "" {
+				output.Display(ui.InfoMessage{Message: fmt.Sprintf("Starting %s (%s)", stage.Name, stage.Description)})
+			}
+			end, target := runStage(ctx, output, workers, stage, stageOptions, jumps)
+			switch end {
+			case stageAborted:
+				return
+			case stageJumped:
+				i = nextStage(output, plan, i, target)
+				plan.jumped(options.Elapsed+time.Since(start), i)
+			case stageCompleted:
+				i++
+			}
 		}
 	}
 }
 
+// nextStage returns the index of the stage to jump to from the stage at current, the next stage if
+// target is empty or isn't a stage of the plan.
+func nextStage(output *ui.Output, plan *stagePlan, current int, target string) int {
+	if target == "" {
+		return current + 1
+	}
+
+	next := plan.index(target)
+	if next < 0 {
+		output.Display(ui.WarningMessage{Message: fmt.Sprintf("unknown stage %q, skipping to the next stage", target)})
+		return current + 1
+	}
+
+	return next
+}
+
+// runStage runs the stage until it completes, the run is aborted because the iterations of the stage
+// exceeded its failure budget, or a stage is received from jumps, which is returned with stageJumped.
 func runStage(
 	ctx context.Context,
 	output *ui.Output,
 	workers *workers.PoolManager,
 	stage runnableStage,
 	options options.RunOptions,
-) {
+	jumps <-chan string,
+) (stageEnd, string) {
 	setEnvs(stage.Params, output)
 	defer unsetEnvs(stage.Params, output)
 
@@ -39,9 +107,13 @@ func runStage(
 	stageCtx, stageCancel := context.WithTimeout(ctx, stage.StageDuration-safeDurationBeforeNextStage)
 	defer stageCancel()
 
+	var statsBefore progress.Snapshot
+	if stage.FailureBudget.isSet() {
+		statsBefore = workers.IterationStats()
+	}
 	stageDone := make(chan struct{})
 
-	go func() {
+	goroutines.Go(stageCtx, "stage", func(stageCtx context.Context) {
 		defer close(stageDone)
 
 		if stage.UsersConcurrency == 0 {
@@ -51,15 +123,65 @@ func runStage(
 			doWork := users.NewWorker(stage.UsersConcurrency)
 			doWork(stageCtx, output, workers, options)
 		}
-	}()
-
-	select {
-	case <-ctx.Done():
-		<-stageDone
-		return
-	case <-stageDone:
-		time.Sleep(safeDurationBeforeNextStage)
+	})
+
+	// stages without a failure budget have no ticks, as nil channels never receive
+	var budgetTicks <-chan time.Time
+	if stage.FailureBudget.isSet() {
+		ticker := time.NewTicker(failureBudgetCheckInterval)
+		defer ticker.Stop()
+		budgetTicks = ticker.C
 	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			<-stageDone
+			return stageCompleted, ""
+		case <-budgetTicks:
+			if !checkFailureBudget(workers, stage, statsBefore, false) {
+				stageCancel()
+				<-stageDone
+				return stageAborted, ""
+			}
+		case target := <-jumps:
+			stageCancel()
+			<-stageDone
+			if !checkFailureBudget(workers, stage, statsBefore, false) {
+				return stageAborted, ""
+			}
+			return stageJumped, target
+		case <-stageDone:
+			time.Sleep(safeDurationBeforeNextStage)
+			if !checkFailureBudget(workers, stage, statsBefore, true) {
+				return stageAborted, ""
+			}
+			return stageCompleted, ""
+		}
+	}
+}
+
+// checkFailureBudget aborts the run and returns false if the iterations completed since the stage
+// started exceeded its failure budget.
+func checkFailureBudget(
+	workers *workers.PoolManager,
+	stage runnableStage,
+	statsBefore progress.Snapshot,
+	endOfStage bool,
+) bool {
+	if !stage.FailureBudget.isSet() {
+		return true
+	}
+
+	exceeded := stage.FailureBudget.exceeded(statsBefore, workers.IterationStats(), endOfStage)
+	if exceeded == "" {
+		return true
+	}
+
+	workers.Abort("Stage Failure Budget Exceeded", fmt.Errorf("%w in %s: %s", ErrFailureBudgetExceeded,
+		stage.Name, exceeded))
+
+	return false
 }
 
 func setEnvs(envs map[string]string, output *ui.Output) {
@@ -90,6 +90,10 @@ func Rate(output *ui.Output) api.Builder {
 			if err != nil {
 				return nil, fmt.Errorf("getting flag: %w", err)
 			}
+			distributionWindow, err := flags.GetDuration(triggerflags.FlagDistributionWindow)
+			if err != nil {
+				return nil, fmt.Errorf("getting flag: %w", err)
+			}
 			peakRate, err := flags.GetString(flagPeakRate)
 			if err != nil {
 				return nil, fmt.Errorf("getting flag: %w", err)
@@ -115,6 +119,7 @@ func Rate(output *ui.Output) api.Builder {
 				stddevDuration,
 				weights,
 				distributionTypeArg,
+				distributionWindow,
 			)
 			if err != nil {
 				return nil, err
@@ -136,13 +141,7 @@ func Rate(output *ui.Output) api.Builder {
 				distributionTypeArg,
 			)
 
-			return &api.Trigger{
-					Trigger:     api.NewIterationWorker(rates.IterationDuration, rates.Rate),
-					DryRun:      rates.Rate,
-					Description: description,
-					Duration:    rates.Duration,
-				},
-				nil
+			return api.NewRatesTrigger(rates, description), nil
 		},
 	}
 }
@@ -161,6 +160,7 @@ func CalculateGaussianRate(
 	volume, jitter float64,
 	repeat, frequency, peak, stddev time.Duration,
 	weightsArg, distributionTypeArg string,
+	distributionWindow time.Duration,
 ) (*api.Rates, error) {
 	weights := strings.Split(weightsArg, ",")
 	weightsSlice := make([]float64, 0, len(weights))
@@ -183,7 +183,7 @@ func CalculateGaussianRate(
 
 	rateFn := api.WithJitter(calculator.For, jitter)
 	distributedIterationDuration, distributedRateFn, err := api.NewDistribution(
-		api.DistributionType(distributionTypeArg), frequency, rateFn, nil,
+		api.DistributionType(distributionTypeArg), distributionWindow, frequency, rateFn, nil,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("new distribution: %w", err)
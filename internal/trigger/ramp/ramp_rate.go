@@ -61,8 +61,14 @@ func Rate() api.Builder {
 			if err != nil {
 				return nil, fmt.Errorf("getting flag: %w", err)
 			}
+			distributionWindow, err := flags.GetDuration(triggerflags.FlagDistributionWindow)
+			if err != nil {
+				return nil, fmt.Errorf("getting flag: %w", err)
+			}
 
-			rates, err := CalculateRampRate(startRateArg, endRateArg, distributionTypeArg, duration, jitterArg)
+			rates, err := CalculateRampRate(
+				startRateArg, endRateArg, distributionTypeArg, distributionWindow, duration, jitterArg,
+			)
 			if err != nil {
 				return nil, fmt.Errorf("calculating ramp rate: %w", err)
 			}
@@ -71,7 +77,10 @@ func Rate() api.Builder {
 				Trigger: api.NewIterationWorker(rates.IterationDuration, rates.Rate),
 				Description: fmt.Sprintf("starting iterations from %s to %s during %v, using distribution %s",
 					startRateArg, endRateArg, duration, distributionTypeArg),
-				DryRun: rates.Rate,
+				DryRun:   rates.Rate,
+				PeakRate: api.NewPeakRate(rates.Rate, rates.IterationDuration),
+
+				PlannedIterations: api.NewPlannedIterations(rates.Rate, rates.IterationDuration),
 			}, nil
 		},
 	}
@@ -81,6 +90,7 @@ func CalculateRampRate(
 	startRateArg string,
 	endRateArg string,
 	distributionTypeArg string,
+	distributionWindow time.Duration,
 	duration time.Duration,
 	jitterArg float64,
 ) (*api.Rates, error) {
@@ -123,7 +133,7 @@ func CalculateRampRate(
 
 	jitterRateFn := api.WithJitter(rateFn, jitterArg)
 	distributedIterationDuration, distributedRateFn, err := api.NewDistribution(
-		api.DistributionType(distributionTypeArg), startUnit, jitterRateFn, nil,
+		api.DistributionType(distributionTypeArg), distributionWindow, startUnit, jitterRateFn, nil,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("new distribution: %w", err)
@@ -2,46 +2,78 @@ package rate
 
 import (
 	"fmt"
+	"math"
 	"regexp"
 	"strconv"
 	"strings"
 	"time"
 )
 
+// maxFractionDigits is the precision of fractional rates, such as 0.25/s
+const maxFractionDigits = 6
+
+// ParseRate parses a rate in the form <iterations>/<duration>, or <iterations> per second, into the
+// number of iterations started every interval. Fractional rates are turned into a whole number of
+// iterations over a longer interval, e.g. 0.2/s into 1 every 5s and 1.5/s into 3 every 2s, so that
+// low rates start single iterations rather than none.
 func ParseRate(rateArg string) (int, time.Duration, error) {
-	var rate int
-	var unit time.Duration
+	iterationsArg, unitArg, found := strings.Cut(rateArg, "/")
 
-	if strings.Contains(rateArg, "/") {
-		var err error
-		rate, err = strconv.Atoi((rateArg)[0:strings.Index(rateArg, "/")])
-		if err != nil {
-			return rate, unit, fmt.Errorf("unable to parse rate %s: %w", rateArg, err)
-		}
-		if rate < 0 {
-			return rate, unit, fmt.Errorf("rate %s can't be negative", rateArg)
-		}
-		unitArg := (rateArg)[strings.Index(rateArg, "/")+1:]
-		if !isNumeric(unitArg[0:1]) {
+	digits, scale, err := splitFraction(iterationsArg)
+	if err != nil {
+		return 0, 0, fmt.Errorf("unable to parse rate %s: %w", rateArg, err)
+	}
+	iterations, err := strconv.Atoi(digits)
+	if err != nil {
+		return 0, 0, fmt.Errorf("unable to parse rate %s: %w", rateArg, err)
+	}
+	if iterations < 0 {
+		return 0, 0, fmt.Errorf("rate %s can't be negative", rateArg)
+	}
+
+	unit := time.Second
+	if found {
+		if !isNumeric(unitArg[0:min(1, len(unitArg))]) {
 			unitArg = "1" + unitArg
 		}
 		unit, err = time.ParseDuration(unitArg)
 		if err != nil {
-			return rate, unit, fmt.Errorf("unable to parse unit %s: %w", rateArg, err)
-		}
-	} else {
-		var err error
-		rate, err = strconv.Atoi(rateArg)
-		if err != nil {
-			return rate, unit, fmt.Errorf("unable to parse rate %s: %w", rateArg, err)
-		}
-		if rate < 0 {
-			return rate, unit, fmt.Errorf("rate %s can't be negative", rateArg)
+			return 0, 0, fmt.Errorf("unable to parse unit %s: %w", rateArg, err)
 		}
-		unit = 1 * time.Second
 	}
 
-	return rate, unit, nil
+	divisor := gcd(iterations, scale)
+	iterations, scale = iterations/divisor, scale/divisor
+	if unit > math.MaxInt64/time.Duration(scale) {
+		return 0, 0, fmt.Errorf("rate %s is too low", rateArg)
+	}
+
+	return iterations, unit * time.Duration(scale), nil
+}
+
+// splitFraction returns the digits of a whole or fractional number of iterations, and the power of
+// ten they are scaled by, e.g. 15 and 10 for 1.5.
+func splitFraction(value string) (string, int, error) {
+	whole, fraction, found := strings.Cut(value, ".")
+	if !found {
+		return value, 1, nil
+	}
+	if !isNumeric(fraction) {
+		return "", 0, fmt.Errorf("invalid fraction in %s", value)
+	}
+	if len(fraction) > maxFractionDigits {
+		return "", 0, fmt.Errorf("%s has more than %d decimals", value, maxFractionDigits)
+	}
+
+	return whole + fraction, int(math.Pow10(len(fraction))), nil
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+
+	return a
 }
 
 func isNumeric(value string) bool {
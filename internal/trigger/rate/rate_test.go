@@ -0,0 +1,68 @@
+package rate_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/trigger/rate"
+)
+
+func TestParseRate(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		rate       string
+		iterations int
+		interval   time.Duration
+	}{
+		{rate: "10", iterations: 10, interval: time.Second},
+		{rate: "10/s", iterations: 10, interval: time.Second},
+		{rate: "5/100ms", iterations: 5, interval: 100 * time.Millisecond},
+		{rate: "1/5m", iterations: 1, interval: 5 * time.Minute},
+		{rate: "0.2/s", iterations: 1, interval: 5 * time.Second},
+		{rate: "0.2", iterations: 1, interval: 5 * time.Second},
+		{rate: "1.5/s", iterations: 3, interval: 2 * time.Second},
+		{rate: "0.25/m", iterations: 1, interval: 4 * time.Minute},
+		{rate: ".5/10s", iterations: 1, interval: 20 * time.Second},
+		{rate: "2.0/s", iterations: 2, interval: time.Second},
+		{rate: "0.0/s", iterations: 0, interval: time.Second},
+	} {
+		t.Run(test.rate, func(t *testing.T) {
+			t.Parallel()
+
+			iterations, interval, err := rate.ParseRate(test.rate)
+
+			require.NoError(t, err)
+			assert.Equal(t, test.iterations, iterations)
+			assert.Equal(t, test.interval, interval)
+		})
+	}
+}
+
+func TestParseRateFails(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		rate     string
+		expected string
+	}{
+		{rate: "fast", expected: `unable to parse rate fast: strconv.Atoi: parsing "fast": invalid syntax`},
+		{rate: "-1/s", expected: "rate -1/s can't be negative"},
+		{rate: "-0.5/s", expected: "rate -0.5/s can't be negative"},
+		{rate: "1./s", expected: "unable to parse rate 1./s: invalid fraction in 1."},
+		{rate: "0.1234567/s", expected: "unable to parse rate 0.1234567/s: 0.1234567 has more than 6 decimals"},
+		{rate: "1/x", expected: `unable to parse unit 1/x: time: unknown unit "x" in duration "1x"`},
+		{rate: "0.000001/2562047h", expected: "rate 0.000001/2562047h is too low"},
+	} {
+		t.Run(test.rate, func(t *testing.T) {
+			t.Parallel()
+
+			_, _, err := rate.ParseRate(test.rate)
+
+			require.EqualError(t, err, test.expected)
+		})
+	}
+}
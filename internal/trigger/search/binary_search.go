@@ -0,0 +1,70 @@
+package search
+
+// binarySearch searches the highest rate between minRate and maxRate which passes its probe,
+// assuming that rates above a failing rate fail too. It probes minRate first, then maxRate, and
+// then halves the interval between the highest passing and the lowest failing rates, until they
+// are at most tolerance apart.
+type binarySearch struct {
+	minRate   int
+	maxRate   int
+	tolerance int
+
+	next    int
+	passing int
+	failing int
+	found   bool
+	done    bool
+}
+
+func newBinarySearch(minRate, maxRate, tolerance int) *binarySearch {
+	return &binarySearch{
+		minRate:   minRate,
+		maxRate:   maxRate,
+		tolerance: tolerance,
+		next:      minRate,
+	}
+}
+
+// rate returns the rate to probe next.
+func (s *binarySearch) rate() int {
+	return s.next
+}
+
+// record records whether the rate returned by rate passed its probe, and returns false once the
+// search completed.
+func (s *binarySearch) record(passed bool) bool {
+	probed := s.next
+	if passed {
+		s.passing, s.found = probed, true
+	} else {
+		s.failing = probed
+	}
+
+	switch {
+	case probed == s.minRate && !passed, probed == s.maxRate && passed:
+		s.done = true
+	case probed == s.minRate:
+		s.next = s.maxRate
+	case s.failing-s.passing <= s.tolerance:
+		s.done = true
+	default:
+		s.next = s.passing + (s.failing-s.passing)/2
+	}
+
+	return !s.done
+}
+
+// capacity returns the highest rate which passed its probe, or false if even minRate failed.
+func (s *binarySearch) capacity() (int, bool) {
+	return s.passing, s.found
+}
+
+// maxProbes returns the number of probes the search runs at most.
+func maxProbes(minRate, maxRate, tolerance int) int {
+	probes := 2
+	for interval := maxRate - minRate; interval > tolerance; interval -= interval / 2 {
+		probes++
+	}
+
+	return probes
+}
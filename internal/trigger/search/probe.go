@@ -0,0 +1,59 @@
+package search
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/progress"
+)
+
+// target is the latency and error rate a probed rate must meet to pass.
+type target struct {
+	// maxLatency is the highest latencyQuantile of the durations of successful iterations, if set
+	maxLatency      time.Duration
+	latencyQuantile float64
+	// maxErrorRate is the highest percentage of failed or dropped iterations
+	maxErrorRate float64
+}
+
+func (t target) String() string {
+	if t.maxLatency == 0 {
+		return fmt.Sprintf("errors <= %g%%", t.maxErrorRate)
+	}
+
+	return fmt.Sprintf("p%g <= %s and errors <= %g%%", t.latencyQuantile*100, t.maxLatency, t.maxErrorRate)
+}
+
+// probe is the outcome of the iterations which completed while a rate was probed.
+type probe struct {
+	iterations uint64
+	latency    time.Duration
+	errorRate  float64
+}
+
+// newProbe returns the outcome of the iterations completed between the stats before and after.
+// Dropped iterations count as errors, as the rate wasn't sustained.
+func newProbe(before, after progress.Snapshot, latencyQuantile float64) probe {
+	successful := after.SuccessfulIterationDurations.Count - before.SuccessfulIterationDurations.Count
+	failed := after.FailedIterationDurations.Count - before.FailedIterationDurations.Count
+	dropped := after.DroppedIterationCount - before.DroppedIterationCount
+
+	p := probe{iterations: successful + failed + dropped}
+	if p.iterations > 0 {
+		p.errorRate = 100 * float64(failed+dropped) / float64(p.iterations)
+	}
+	p.latency = after.SuccessfulIterationDurations.Histogram.
+		Sub(before.SuccessfulIterationDurations.Histogram).
+		Quantile(latencyQuantile)
+
+	return p
+}
+
+// meets returns true if the probe met the target. A probe in which no iteration completed doesn't.
+func (p probe) meets(t target) bool {
+	if p.iterations == 0 || p.errorRate > t.maxErrorRate {
+		return false
+	}
+
+	return t.maxLatency == 0 || p.latency <= t.maxLatency
+}
@@ -0,0 +1,249 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/form3tech-oss/f1/v2/internal/options"
+	"github.com/form3tech-oss/f1/v2/internal/trigger/api"
+	"github.com/form3tech-oss/f1/v2/internal/trigger/rate"
+	"github.com/form3tech-oss/f1/v2/internal/triggerflags"
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+	"github.com/form3tech-oss/f1/v2/internal/workers"
+)
+
+const (
+	flagMinRate           = "min-rate"
+	flagMaxRate           = "max-rate"
+	flagMaxLatency        = "max-latency"
+	flagLatencyPercentile = "latency-percentile"
+	flagMaxErrorRate      = "max-error-rate"
+	flagProbeDuration     = "probe-duration"
+	flagTolerance         = "tolerance"
+)
+
+func Rate() api.Builder {
+	flags := pflag.NewFlagSet("search", pflag.ContinueOnError)
+	flags.String(flagMinRate, "1/s",
+		"lowest rate to search, in the form <request>/<duration>")
+	flags.String(flagMaxRate, "100/s",
+		"highest rate to search, in the form <request>/<duration>")
+	flags.Duration(flagMaxLatency, 0,
+		"highest --latency-percentile of successful iterations a rate may have, not checked if 0")
+	flags.Float64(flagLatencyPercentile, 95, "percentile of the iteration durations checked against --max-latency")
+	flags.Float64(flagMaxErrorRate, 1,
+		"highest percentage of failed or dropped iterations a rate may have")
+	flags.Duration(flagProbeDuration, 30*time.Second, "how long each rate is probed for")
+	flags.Int(flagTolerance, 1,
+		"the search completes once the highest passing and lowest failing rates are at most this many "+
+			"requests per interval apart")
+
+	triggerflags.DistributionFlag(flags)
+
+	return api.Builder{
+		Name:        "search <scenario>",
+		Description: "binary-searches the highest rate meeting a latency and error rate target",
+		Flags:       flags,
+		New: func(params *pflag.FlagSet) (*api.Trigger, error) {
+			minRateArg, err := params.GetString(flagMinRate)
+			if err != nil {
+				return nil, fmt.Errorf("getting flag: %w", err)
+			}
+			maxRateArg, err := params.GetString(flagMaxRate)
+			if err != nil {
+				return nil, fmt.Errorf("getting flag: %w", err)
+			}
+			maxLatency, err := params.GetDuration(flagMaxLatency)
+			if err != nil {
+				return nil, fmt.Errorf("getting flag: %w", err)
+			}
+			latencyPercentile, err := params.GetFloat64(flagLatencyPercentile)
+			if err != nil {
+				return nil, fmt.Errorf("getting flag: %w", err)
+			}
+			maxErrorRate, err := params.GetFloat64(flagMaxErrorRate)
+			if err != nil {
+				return nil, fmt.Errorf("getting flag: %w", err)
+			}
+			probeDuration, err := params.GetDuration(flagProbeDuration)
+			if err != nil {
+				return nil, fmt.Errorf("getting flag: %w", err)
+			}
+			tolerance, err := params.GetInt(flagTolerance)
+			if err != nil {
+				return nil, fmt.Errorf("getting flag: %w", err)
+			}
+			distributionTypeArg, err := params.GetString(triggerflags.FlagDistribution)
+			if err != nil {
+				return nil, fmt.Errorf("getting flag: %w", err)
+			}
+			distributionWindow, err := params.GetDuration(triggerflags.FlagDistributionWindow)
+			if err != nil {
+				return nil, fmt.Errorf("getting flag: %w", err)
+			}
+
+			if latencyPercentile <= 0 || latencyPercentile >= 100 {
+				return nil, fmt.Errorf("--%s must be between 0 and 100", flagLatencyPercentile)
+			}
+			if maxErrorRate < 0 || maxErrorRate > 100 {
+				return nil, fmt.Errorf("--%s must be between 0 and 100", flagMaxErrorRate)
+			}
+			if probeDuration <= 0 {
+				return nil, fmt.Errorf("--%s must be positive", flagProbeDuration)
+			}
+			if tolerance < 1 {
+				return nil, fmt.Errorf("--%s must be at least 1", flagTolerance)
+			}
+
+			s, err := newSearch(minRateArg, maxRateArg, tolerance, probeDuration, target{
+				maxLatency:      maxLatency,
+				latencyQuantile: latencyPercentile / 100,
+				maxErrorRate:    maxErrorRate,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("creating search: %w", err)
+			}
+
+			iterationDuration, rateFn, err := api.NewDistribution(
+				api.DistributionType(distributionTypeArg), distributionWindow, s.unit, s.currentRate, nil,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("new distribution: %w", err)
+			}
+
+			probes := maxProbes(s.minRate, s.maxRate, tolerance)
+
+			return &api.Trigger{
+				Trigger: s.trigger(iterationDuration, rateFn),
+				Description: fmt.Sprintf("searching the highest rate from %s to %s with %s, probing each rate for %s",
+					minRateArg, maxRateArg, s.target, probeDuration),
+				// the rates probed depend on how the system under test copes with them, so the
+				// dry run shows the lowest rate, which is always probed first
+				DryRun: func(time.Time) int { return s.minRate },
+				// the search completes the run after its last probe, which the duration leaves time for
+				Duration:  time.Duration(probes)*probeDuration + iterationDuration,
+				Completes: true,
+			}, nil
+		},
+	}
+}
+
+// search probes rates for a while each, binary-searching the highest rate meeting its target.
+type search struct {
+	target        target
+	search        *binarySearch
+	unitLabel     string
+	minRate       int
+	maxRate       int
+	unit          time.Duration
+	probeDuration time.Duration
+}
+
+func newSearch(minRateArg, maxRateArg string, tolerance int, probeDuration time.Duration, t target) (*search, error) {
+	minRate, minUnit, err := rate.ParseRate(minRateArg)
+	if err != nil {
+		return nil, fmt.Errorf("parsing min rate: %w", err)
+	}
+	maxRate, maxUnit, err := rate.ParseRate(maxRateArg)
+	if err != nil {
+		return nil, fmt.Errorf("parsing max rate: %w", err)
+	}
+	if minUnit != maxUnit {
+		return nil, errors.New("min-rate and max-rate are not using the same unit")
+	}
+	if minRate < 1 || minRate >= maxRate {
+		return nil, errors.New("min-rate should be at least 1 and lower than max-rate")
+	}
+
+	unitLabel := "s"
+	if _, unit, found := strings.Cut(minRateArg, "/"); found {
+		unitLabel = unit
+	}
+
+	return &search{
+		target:        t,
+		search:        newBinarySearch(minRate, maxRate, tolerance),
+		unitLabel:     unitLabel,
+		minRate:       minRate,
+		maxRate:       maxRate,
+		unit:          minUnit,
+		probeDuration: probeDuration,
+	}, nil
+}
+
+// currentRate is the rate function of the search, which starts the rate being probed.
+func (s *search) currentRate(time.Time) int {
+	return s.search.rate()
+}
+
+func (s *search) formatRate(r int) string {
+	return fmt.Sprintf("%d/%s", r, s.unitLabel)
+}
+
+func (s *search) trigger(iterationDuration time.Duration, rateFn api.RateFunction) api.WorkTriggerer {
+	// probes end on a tick of the trigger, before the iterations of the next rate are started
+	ticksPerProbe := max(int(s.probeDuration/iterationDuration), 1)
+
+	return func(ctx context.Context, output *ui.Output, workers *workers.PoolManager, opts options.RunOptions) {
+		pool := workers.NewTriggerPool(opts.Concurrency)
+		workerCtx := pool.Start(ctx)
+
+		probeStart := workers.IterationStats()
+		pool.Trigger(workerCtx, workers.OverrideRate(rateFn(time.Now()), iterationDuration))
+
+		iterationTicker := time.NewTicker(iterationDuration)
+		defer iterationTicker.Stop()
+
+		for probes, ticks := 1, 0; ; {
+			select {
+			case <-workerCtx.Done():
+				return
+			case start := <-iterationTicker.C:
+				ticks++
+				if ticks == ticksPerProbe {
+					probeEnd := workers.IterationStats()
+					probed := s.search.rate()
+					result := newProbe(probeStart, probeEnd, s.target.latencyQuantile)
+					passed := result.meets(s.target)
+					output.Display(s.probeMessage(probes, probed, result, passed))
+
+					if !s.search.record(passed) {
+						output.Display(s.capacityMessage())
+						return
+					}
+					probeStart = probeEnd
+					probes, ticks = probes+1, 0
+				}
+
+				pool.Trigger(workerCtx, workers.OverrideRate(rateFn(start), iterationDuration))
+			}
+		}
+	}
+}
+
+func (s *search) probeMessage(probes, probed int, result probe, passed bool) ui.InfoMessage {
+	outcome := "failed"
+	if passed {
+		outcome = "passed"
+	}
+
+	return ui.InfoMessage{Message: fmt.Sprintf("probe %d at %s: p%g %s, %.2f%% errors over %d iterations - %s",
+		probes, s.formatRate(probed), s.target.latencyQuantile*100, result.latency, result.errorRate,
+		result.iterations, outcome)}
+}
+
+func (s *search) capacityMessage() ui.Outputable {
+	capacity, found := s.search.capacity()
+	if !found {
+		return ui.WarningMessage{Message: fmt.Sprintf("search completed: even the min rate %s didn't meet %s",
+			s.formatRate(s.minRate), s.target)}
+	}
+
+	return ui.InfoMessage{Message: fmt.Sprintf("search completed: the capacity is %s, the highest rate meeting %s",
+		s.formatRate(capacity), s.target)}
+}
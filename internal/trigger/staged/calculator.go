@@ -1,6 +1,7 @@
 package staged
 
 import (
+	"fmt"
 	"time"
 )
 
@@ -68,3 +69,16 @@ func (s *RateCalculator) MaxDuration() time.Duration {
 	}
 	return maxDuration
 }
+
+// StageAt returns the name of the stage running after the given duration of the run, or an empty
+// string once all the stages have completed.
+func (s *RateCalculator) StageAt(elapsed time.Duration) string {
+	for i, stage := range s.stages {
+		if elapsed < stage.Duration {
+			return fmt.Sprintf("stage %d (%d to %d)", i, stage.StartTarget, stage.EndTarget)
+		}
+		elapsed -= stage.Duration
+	}
+
+	return ""
+}
@@ -70,3 +70,24 @@ func TestCalculatorWithSetStartTimeOutOfRange(t *testing.T) {
 
 	assert.Equal(t, 0, rate)
 }
+
+func TestCalculatorStageAt(t *testing.T) {
+	t.Parallel()
+
+	calculator := staged.NewRateCalculator([]staged.Stage{
+		{EndTarget: 10, Duration: time.Minute},
+		{EndTarget: 5, Duration: 10 * time.Minute},
+	}, nil)
+
+	for _, test := range []struct {
+		elapsed  time.Duration
+		expected string
+	}{
+		{elapsed: 0, expected: "stage 0 (0 to 10)"},
+		{elapsed: 59 * time.Second, expected: "stage 0 (0 to 10)"},
+		{elapsed: time.Minute, expected: "stage 1 (10 to 5)"},
+		{elapsed: 11 * time.Minute, expected: ""},
+	} {
+		assert.Equal(t, test.expected, calculator.StageAt(test.elapsed), test.elapsed)
+	}
+}
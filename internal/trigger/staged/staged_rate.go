@@ -49,6 +49,10 @@ func Rate() api.Builder {
 			if err != nil {
 				return nil, fmt.Errorf("getting flag: %w", err)
 			}
+			distributionWindow, err := params.GetDuration(triggerflags.FlagDistributionWindow)
+			if err != nil {
+				return nil, fmt.Errorf("getting flag: %w", err)
+			}
 			var startTime *time.Time
 			startTimeStr, err := params.GetString(flagStartTime)
 			if err != nil {
@@ -58,20 +62,16 @@ func Rate() api.Builder {
 				startTime = &parsedStartTime
 			}
 
-			rates, err := CalculateStagedRate(jitterArg, frequency, stg, distributionTypeArg, startTime)
+			rates, err := CalculateStagedRate(jitterArg, frequency, stg, distributionTypeArg, distributionWindow, startTime)
 			if err != nil {
 				return nil, err
 			}
 
-			return &api.Trigger{
-					Trigger: api.NewIterationWorker(rates.IterationDuration, rates.Rate),
-					DryRun:  rates.Rate,
-					Description: fmt.Sprintf(
-						"Starting iterations every %s in numbers varying by time: %s, using distribution %s",
-						frequency, stg, distributionTypeArg),
-					Duration: rates.Duration,
-				},
-				nil
+			description := fmt.Sprintf(
+				"Starting iterations every %s in numbers varying by time: %s, using distribution %s",
+				frequency, stg, distributionTypeArg)
+
+			return api.NewRatesTrigger(rates, description), nil
 		},
 	}
 }
@@ -81,6 +81,7 @@ func CalculateStagedRate(
 	frequency time.Duration,
 	stg string,
 	distributionTypeArg string,
+	distributionWindow time.Duration,
 	startTime *time.Time,
 ) (*api.Rates, error) {
 	stages, err := ParseStages(stg)
@@ -91,7 +92,7 @@ func CalculateStagedRate(
 	calculator := NewRateCalculator(stages, startTime)
 	rateFn := api.WithJitter(calculator.Rate, jitterArg)
 	distributedIterationDuration, distributedRateFn, err := api.NewDistribution(
-		api.DistributionType(distributionTypeArg), frequency, rateFn, nil,
+		api.DistributionType(distributionTypeArg), distributionWindow, frequency, rateFn, nil,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("new distribution: %w", err)
@@ -101,5 +102,6 @@ func CalculateStagedRate(
 		IterationDuration: distributedIterationDuration,
 		Rate:              distributedRateFn,
 		Duration:          calculator.MaxDuration(),
+		StageAt:           calculator.StageAt,
 	}, nil
 }
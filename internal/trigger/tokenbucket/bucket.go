@@ -0,0 +1,67 @@
+package tokenbucket
+
+import (
+	"time"
+)
+
+// Bucket admits iterations with tokens, which are added at a steady rate up to the size of the
+// bucket, so that bursts of up to that size above the rate are admitted after quieter periods.
+type Bucket struct {
+	last time.Time
+	// nextBurst is when the bucket starts holding its tokens for the next burst, if burstEvery is set
+	nextBurst  time.Time
+	tokens     float64
+	perNano    float64
+	size       float64
+	burstEvery time.Duration
+	holding    bool
+}
+
+// NewBucket returns a full bucket of size tokens, refilled with rate tokens every unit. If
+// burstEvery is set, the bucket admits no iterations from the start of every burstEvery until it is
+// full again, so that a burst of its size is admitted every burstEvery.
+func NewBucket(rate int, unit time.Duration, size int, burstEvery time.Duration) *Bucket {
+	return &Bucket{
+		tokens:     float64(size),
+		perNano:    float64(rate) / float64(unit),
+		size:       float64(size),
+		burstEvery: burstEvery,
+	}
+}
+
+// Take returns the number of iterations admitted at now, at most demand, and takes their tokens.
+func (b *Bucket) Take(now time.Time, demand int) int {
+	b.refill(now)
+
+	if b.burstEvery > 0 && !now.Before(b.nextBurst) {
+		b.holding = true
+		b.nextBurst = now.Add(b.burstEvery)
+	}
+	if b.holding {
+		if b.tokens < b.size {
+			return 0
+		}
+		b.holding = false
+	}
+
+	admitted := min(int(b.tokens), max(demand, 0))
+	b.tokens -= float64(admitted)
+
+	return admitted
+}
+
+func (b *Bucket) refill(now time.Time) {
+	if b.last.IsZero() {
+		// the bucket starts full, and the first burst is admitted straight away
+		b.last = now
+		b.nextBurst = now.Add(b.burstEvery)
+		return
+	}
+
+	elapsed := now.Sub(b.last)
+	if elapsed <= 0 {
+		return
+	}
+	b.last = now
+	b.tokens = min(b.tokens+float64(elapsed)*b.perNano, b.size)
+}
@@ -0,0 +1,81 @@
+package tokenbucket_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/form3tech-oss/f1/v2/internal/trigger/tokenbucket"
+)
+
+func TestBucket(t *testing.T) {
+	t.Parallel()
+
+	start := time.Now()
+	at := func(d time.Duration) time.Time { return start.Add(d) }
+
+	type take struct {
+		at       time.Duration
+		demand   int
+		admitted int
+	}
+
+	for _, test := range []struct {
+		name       string
+		burstEvery time.Duration
+		takes      []take
+	}{
+		{
+			name: "starts full",
+			takes: []take{
+				{at: 0, demand: 100, admitted: 10},
+				{at: 0, demand: 100, admitted: 0},
+			},
+		},
+		{
+			name: "refills at the rate",
+			takes: []take{
+				{at: 0, demand: 100, admitted: 10},
+				{at: 500 * time.Millisecond, demand: 100, admitted: 2},
+				{at: time.Second, demand: 100, admitted: 3},
+			},
+		},
+		{
+			name: "admits at most the demand",
+			takes: []take{
+				{at: 0, demand: 4, admitted: 4},
+				{at: 0, demand: 100, admitted: 6},
+			},
+		},
+		{
+			name: "keeps the tokens up to the size of the bucket",
+			takes: []take{
+				{at: 0, demand: 0, admitted: 0},
+				{at: time.Second, demand: 100, admitted: 10},
+				{at: 10 * time.Second, demand: 100, admitted: 10},
+			},
+		},
+		{
+			name:       "holds back iterations until full every burst",
+			burstEvery: 5 * time.Second,
+			takes: []take{
+				{at: 0, demand: 100, admitted: 10},
+				{at: 2 * time.Second, demand: 100, admitted: 10},
+				{at: 4 * time.Second, demand: 100, admitted: 10},
+				{at: 5 * time.Second, demand: 100, admitted: 0},
+				{at: 6 * time.Second, demand: 100, admitted: 10},
+				{at: 7 * time.Second, demand: 100, admitted: 5},
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			bucket := tokenbucket.NewBucket(5, time.Second, 10, test.burstEvery)
+			for _, take := range test.takes {
+				assert.Equal(t, take.admitted, bucket.Take(at(take.at), take.demand), "at %s", take.at)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package tokenbucket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/form3tech-oss/f1/v2/internal/options"
+	"github.com/form3tech-oss/f1/v2/internal/trigger/api"
+	"github.com/form3tech-oss/f1/v2/internal/trigger/rate"
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+	"github.com/form3tech-oss/f1/v2/internal/workers"
+)
+
+const (
+	flagRate       = "rate"
+	flagBurst      = "burst"
+	flagBurstEvery = "burst-every"
+
+	// tickInterval is how often the bucket is refilled and iterations are admitted
+	tickInterval = 10 * time.Millisecond
+)
+
+func Rate() api.Builder {
+	flags := pflag.NewFlagSet("token-bucket", pflag.ContinueOnError)
+	flags.StringP(flagRate, "r", "1/s",
+		"number of tokens added to the bucket per interval, in the form <request>/<duration>")
+	flags.Int(flagBurst, 1, "size of the bucket, the largest burst of iterations started at once")
+	flags.Duration(flagBurstEvery, 0,
+		"hold back iterations at the start of every interval of this duration until the bucket is full, "+
+			"to start a burst of --burst iterations every interval, not done if 0")
+
+	return api.Builder{
+		Name:        "token-bucket <scenario>",
+		Description: "triggers test iterations with a token bucket, allowing bursts above the rate up to the bucket size",
+		Flags:       flags,
+		New: func(params *pflag.FlagSet) (*api.Trigger, error) {
+			rateArg, err := params.GetString(flagRate)
+			if err != nil {
+				return nil, fmt.Errorf("getting flag: %w", err)
+			}
+			burst, err := params.GetInt(flagBurst)
+			if err != nil {
+				return nil, fmt.Errorf("getting flag: %w", err)
+			}
+			burstEvery, err := params.GetDuration(flagBurstEvery)
+			if err != nil {
+				return nil, fmt.Errorf("getting flag: %w", err)
+			}
+
+			tokens, unit, err := rate.ParseRate(rateArg)
+			if err != nil {
+				return nil, fmt.Errorf("unable to parse rate %s: %w", rateArg, err)
+			}
+			if tokens < 1 {
+				return nil, errors.New("rate should be at least 1")
+			}
+			if burst < 1 {
+				return nil, fmt.Errorf("--%s must be at least 1", flagBurst)
+			}
+			if burstEvery < 0 {
+				return nil, fmt.Errorf("--%s must not be negative", flagBurstEvery)
+			}
+
+			newBucket := func() *Bucket { return NewBucket(tokens, unit, burst, burstEvery) }
+
+			description := fmt.Sprintf("%s token bucket rate, with bursts of up to %d iterations", rateArg, burst)
+			if burstEvery > 0 {
+				description += fmt.Sprintf(" every %s", burstEvery)
+			}
+
+			return &api.Trigger{
+				Trigger:     trigger(newBucket),
+				DryRun:      dryRun(newBucket()),
+				Description: description,
+			}, nil
+		},
+	}
+}
+
+// trigger starts the iterations admitted by the bucket. Only iterations which free workers can start
+// are admitted, so the tokens of the others are kept for a burst once workers are free again.
+func trigger(newBucket func() *Bucket) api.WorkTriggerer {
+	return func(ctx context.Context, _ *ui.Output, workers *workers.PoolManager, opts options.RunOptions) {
+		pool := workers.NewTriggerPool(opts.Concurrency)
+		workerCtx := pool.Start(ctx)
+
+		bucket := newBucket()
+		admit := func(now time.Time) {
+			free := pool.FreeWorkers()
+			// rate overrides, such as pausing the triggers, lower the demand rather than the tokens
+			demand := min(free, workers.OverrideRate(free, tickInterval))
+			pool.Admit(workerCtx, bucket.Take(now, demand))
+		}
+		admit(time.Now())
+
+		ticker := time.NewTicker(tickInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-workerCtx.Done():
+				return
+			case now := <-ticker.C:
+				admit(now)
+			}
+		}
+	}
+}
+
+// dryRun returns the iterations admitted by bucket since the previous call if there were always
+// free workers, simulating the ticks of the trigger in between.
+func dryRun(bucket *Bucket) api.RateFunction {
+	var last time.Time
+	return func(now time.Time) int {
+		if last.IsZero() {
+			last = now
+			return bucket.Take(now, math.MaxInt)
+		}
+
+		admitted := 0
+		for ; !last.Add(tickInterval).After(now); last = last.Add(tickInterval) {
+			admitted += bucket.Take(last.Add(tickInterval), math.MaxInt)
+		}
+
+		return admitted
+	}
+}
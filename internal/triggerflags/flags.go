@@ -17,9 +17,71 @@ const (
 	FlagConcurrency     = "concurrency"
 	FlagMaxFailures     = "max-failures"
 	FlagMaxFailuresRate = "max-failures-rate"
+	FlagReportFile      = "report-file"
+	FlagResume          = "resume"
+	FlagMaxMemory       = "max-memory"
+	FlagControlAddr     = "control-addr"
+	FlagTrace           = "trace"
+	FlagTraceFile       = "trace-file"
+	FlagProgress        = "progress"
+	FlagSnapshotFailure = "snapshot-failure-rate"
+	FlagSnapshotDir     = "snapshot-dir"
+	FlagHistogramFile   = "hgrm-file"
+	FlagAuditLog        = "audit-log"
+	FlagAnnotateGC      = "annotate-gc"
+	FlagShowSlowest     = "show-slowest"
+	FlagRateOverride    = "rate-override-file"
+	FlagEndless         = "endless"
+	FlagSLOMaxP95       = "slo-max-p95"
+	FlagSLOMaxP99       = "slo-max-p99"
+	FlagSLOMaxErrorRate = "slo-max-error-rate"
+	FlagReportSnapshot  = "report-snapshot-file"
+	FlagReportInterval  = "report-snapshot-interval"
+	FlagPprofPort       = "pprof-port"
+	FlagPprofCapture    = "pprof-capture"
+	FlagRequireMetrics  = "require-metrics"
+	FlagPinWorkers      = "pin-workers"
+	FlagStartAt         = "start-at"
+	FlagSyncBarrier     = "sync-barrier"
+	FlagReadyURL        = "ready-url"
+	FlagReadyTimeout    = "ready-timeout"
+	FlagReadyInterval   = "ready-interval"
+
+	FlagRaceCheck         = "race-check"
+	FlagRaceCheckDuration = "race-check-duration"
+
+	FlagUniqueIterations = "unique-iterations"
+
+	FlagWorkerRamp      = "worker-ramp"
+	FlagWorkerRateLimit = "worker-rate-limit"
+
+	FlagYes          = "yes"
+	FlagConfirmAbove = "confirm-above"
+
+	FlagAutotuneConcurrency = "autotune-concurrency"
+
+	FlagRedact = "redact"
+
+	FlagSummaryMetricsFile   = "summary-metrics-file"
+	FlagSummaryMetricsFormat = "summary-metrics-format"
+
+	FlagHardDeadline = "hard-deadline"
+
+	FlagTemplateVar = "template-var"
+	FlagTemplateDir = "template-dir"
+
+	FlagOutputFormat = "output-format"
+	FlagOutputFile   = "output-file"
+
+	FlagJUnitReport = "junit-report"
+
+	FlagSLA = "sla"
 )
 
-const FlagDistribution = "distribution"
+const (
+	FlagDistribution       = "distribution"
+	FlagDistributionWindow = "distribution-window"
+)
 
 func DistributionFlag(flagSet *pflag.FlagSet) {
 	distributionTypes := []string{
@@ -30,7 +92,10 @@ func DistributionFlag(flagSet *pflag.FlagSet) {
 
 	distributions := strings.Join(distributionTypes, "|")
 	flagSet.String(FlagDistribution, string(api.RegularDistribution),
-		"optional parameter to distribute the rate over steps of 100ms, which can be "+distributions)
+		"optional parameter to distribute the rate over steps of --distribution-window, which can be "+distributions)
+	flagSet.Duration(FlagDistributionWindow, api.DefaultDistributionWindow,
+		"duration of the steps the rate is distributed over, shorter windows start iterations more smoothly "+
+			"and longer windows in bigger batches")
 }
 
 const FlagJitter = "jitter"
@@ -41,6 +41,23 @@ func (o *Output) Display(outputable Outputable) {
 	outputable.Log(o.Logger)
 }
 
+// Renderable is an [Outputable] which can be rendered as a single line, to be updated in place.
+type Renderable interface {
+	Outputable
+	Render() string
+}
+
+// DisplayLive prints renderable in place of the previous live line, or logs it when printing is
+// not allowed.
+func (o *Output) DisplayLive(renderable Renderable) {
+	if o.AllowPrinting && o.Interactive {
+		o.Printer.Live(renderable.Render())
+		return
+	}
+
+	renderable.Log(o.Logger)
+}
+
 func NewDiscardOutput() *Output {
 	printer := NewDiscardPrinter()
 	logger := log.NewDiscardLogger()
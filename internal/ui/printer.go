@@ -4,11 +4,16 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sync"
 )
 
 type Printer struct {
 	Writer    io.Writer
 	ErrWriter io.Writer
+	mu        sync.Mutex
+	// live is set while the last line written is updated in place, and must be ended before
+	// printing anything else
+	live bool
 }
 
 func NewDefaultPrinter() *Printer {
@@ -27,13 +32,43 @@ func NewPrinter(writer io.Writer, errWriter io.Writer) *Printer {
 }
 
 func (t *Printer) Println(a ...any) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.endLive()
 	fmt.Fprintln(t.Writer, a...)
 }
 
 func (t *Printer) Error(a ...any) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.endLive()
 	fmt.Fprintln(t.ErrWriter, a...)
 }
 
 func (t *Printer) Warn(a ...any) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.endLive()
 	fmt.Fprintln(t.ErrWriter, a...)
 }
+
+// Live replaces the current line with line, using a carriage return, so that it can be updated in
+// place by the next call to Live.
+func (t *Printer) Live(line string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// \x1b[K clears the rest of the line, in case the previous line was longer
+	fmt.Fprint(t.Writer, "\r"+line+"\x1b[K")
+	t.live = true
+}
+
+func (t *Printer) endLive() {
+	if t.live {
+		fmt.Fprintln(t.Writer)
+		t.live = false
+	}
+}
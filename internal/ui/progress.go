@@ -0,0 +1,35 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Styles of the progress of a run, selected with the --progress flag.
+const (
+	// ProgressAuto updates the progress live when stdout is a terminal, and prints it as lines otherwise.
+	ProgressAuto = "auto"
+	// ProgressLive updates the progress in place on a single line.
+	ProgressLive = "live"
+	// ProgressLines prints the progress as periodic full lines, which suits CI logs.
+	ProgressLines = "lines"
+)
+
+var errUnknownProgressStyle = errors.New("unknown progress style")
+
+// ProgressStyle resolves the auto progress style to live or lines, depending on whether stdout is
+// a terminal.
+func ProgressStyle(style string, stdoutIsTerminal bool) (string, error) {
+	switch style {
+	case ProgressAuto, "":
+		if stdoutIsTerminal {
+			return ProgressLive, nil
+		}
+		return ProgressLines, nil
+	case ProgressLive, ProgressLines:
+		return style, nil
+	default:
+		return "", fmt.Errorf("%w: %s, expected one of %s, %s or %s",
+			errUnknownProgressStyle, style, ProgressAuto, ProgressLive, ProgressLines)
+	}
+}
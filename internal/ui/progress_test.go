@@ -0,0 +1,59 @@
+package ui_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+)
+
+func TestProgressStyle(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name             string
+		style            string
+		expected         string
+		stdoutIsTerminal bool
+	}{
+		{name: "auto on a terminal", style: ui.ProgressAuto, stdoutIsTerminal: true, expected: ui.ProgressLive},
+		{name: "auto in CI", style: ui.ProgressAuto, stdoutIsTerminal: false, expected: ui.ProgressLines},
+		{name: "default in CI", style: "", stdoutIsTerminal: false, expected: ui.ProgressLines},
+		{name: "live in CI", style: ui.ProgressLive, stdoutIsTerminal: false, expected: ui.ProgressLive},
+		{name: "lines on a terminal", style: ui.ProgressLines, stdoutIsTerminal: true, expected: ui.ProgressLines},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			style, err := ui.ProgressStyle(test.style, test.stdoutIsTerminal)
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, style)
+		})
+	}
+}
+
+func TestProgressStyleRejectsUnknownStyles(t *testing.T) {
+	t.Parallel()
+
+	_, err := ui.ProgressStyle("fancy", true)
+
+	require.EqualError(t, err, "unknown progress style: fancy, expected one of auto, live or lines")
+}
+
+func TestPrinterEndsLiveLineBeforePrinting(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	printer := ui.NewPrinter(&out, &out)
+
+	printer.Live("[ 1s] 10")
+	printer.Live("[ 2s] 20")
+	printer.Println("done")
+	printer.Println("bye")
+
+	assert.Equal(t, "\r[ 1s] 10\x1b[K\r[ 2s] 20\x1b[K\ndone\nbye\n", out.String())
+}
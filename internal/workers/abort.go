@@ -0,0 +1,41 @@
+package workers
+
+import (
+	"sync"
+)
+
+// abort is set by triggers which stop and fail the run on conditions of their own, and is shared
+// by the pool managers of all the operations of a run.
+type abort struct {
+	reason string
+	err    error
+	// ch is closed when the run is aborted
+	ch   chan struct{}
+	once sync.Once
+}
+
+// Abort stops the run and fails it with err, displaying reason as why it stopped, for triggers
+// which evaluate conditions of their own, such as the failure budgets of stages. Only the first
+// abort of a run is kept.
+func (m *PoolManager) Abort(reason string, err error) {
+	m.abort.once.Do(func() {
+		m.abort.reason = reason
+		m.abort.err = err
+		close(m.abort.ch)
+	})
+}
+
+// Aborted is closed when the run is aborted by its trigger, see Abort.
+func (m *PoolManager) Aborted() <-chan struct{} {
+	return m.abort.ch
+}
+
+// AbortReason returns the reason and the error the run was aborted with, once Aborted is closed.
+func (m *PoolManager) AbortReason() (string, error) {
+	select {
+	case <-m.abort.ch:
+		return m.abort.reason, m.abort.err
+	default:
+		return "", nil
+	}
+}
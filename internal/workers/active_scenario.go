@@ -2,11 +2,19 @@ package workers
 
 import (
 	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
+	"github.com/form3tech-oss/f1/v2/internal/audit"
+	"github.com/form3tech-oss/f1/v2/internal/gcpause"
+	"github.com/form3tech-oss/f1/v2/internal/hdr"
 	"github.com/form3tech-oss/f1/v2/internal/metrics"
 	"github.com/form3tech-oss/f1/v2/internal/progress"
+	"github.com/form3tech-oss/f1/v2/internal/redact"
 	"github.com/form3tech-oss/f1/v2/internal/xtime"
 	"github.com/form3tech-oss/f1/v2/pkg/f1/scenarios"
 	"github.com/form3tech-oss/f1/v2/pkg/f1/testing"
@@ -20,6 +28,28 @@ type ActiveScenario struct {
 	Teardown     func()
 	logger       *slog.Logger
 	logrusLogger *logrus.Logger
+	// args are the arguments passed to the scenario after `--`, see testing.T.Args
+	args []string
+	// histogram optionally records the durations of all iterations
+	histogram *hdr.Histogram
+	// gc optionally tracks the iterations which overlap garbage collections
+	gc *gcpause.Tracker
+	// audit optionally records every iteration which completes
+	audit *audit.Log
+	// stopped disables recording of metrics by iterations which outlive the run, so that
+	// they don't leak into the metrics of the following runs
+	stopped atomic.Bool
+	// openConnections is the number of connections held open by the iterations of connection
+	// pools, displayed in the progress once a pool opened connections
+	openConnections  atomic.Int64
+	opensConnections atomic.Bool
+	// phase is where the run is, once it triggers iterations, see TrackPhase
+	phase atomic.Pointer[runPhase]
+	// stopping is closed once the run stops triggering iterations, see StopIterations
+	stopping     chan struct{}
+	stoppingOnce sync.Once
+	// redactor redacts the values of the secrets fetched by the scenario
+	redactor *redact.Redactor
 }
 
 const instantDuration = 0
@@ -30,11 +60,17 @@ func NewActiveScenario(
 	stats *progress.Stats,
 	logger *slog.Logger,
 	logrusLogger *logrus.Logger,
+	args []string,
+	redactor *redact.Redactor,
 ) *ActiveScenario {
 	t, teardown := testing.NewTWithOptions(scenario.Name,
 		testing.WithIteration("setup"),
+		testing.WithArgs(args),
 		testing.WithLogger(logger),
 		testing.WithLogrusLogger(logrusLogger),
+		testing.WithMetrics(metricsInstance),
+		testing.WithSecrets(scenario.Secrets),
+		testing.WithSecretRedaction(redactor.AddSecret),
 	)
 
 	s := &ActiveScenario{
@@ -45,6 +81,9 @@ func NewActiveScenario(
 		progress:     stats,
 		logger:       logger,
 		logrusLogger: logrusLogger,
+		args:         args,
+		stopping:     make(chan struct{}),
+		redactor:     redactor,
 	}
 
 	return s
@@ -63,22 +102,88 @@ func (s *ActiveScenario) Setup() {
 	s.m.RecordSetupResult(s.scenario.Name, metrics.Result(s.t.Failed()), duration)
 }
 
-func (s *ActiveScenario) newIterationState() *iterationState {
-	t, teardown := testing.NewTWithOptions(s.scenario.Name,
+func (s *ActiveScenario) newIterationState(operation string, options ...testing.TOption) *iterationState {
+	t, teardown := testing.NewTWithOptions(s.scenario.Name, append([]testing.TOption{
+		testing.WithOperation(operation),
+		testing.WithArgs(s.args),
 		testing.WithLogger(s.logger),
 		testing.WithLogrusLogger(s.logrusLogger),
-	)
+		testing.WithMetrics(s.m),
+		testing.WithPhase(s.currentPhase),
+		testing.WithSecrets(s.scenario.Secrets),
+		testing.WithSecretRedaction(s.redactor.AddSecret),
+		testing.WithStopping(s.stopping),
+		testing.WithStageRecorder(s.recordStage),
+	}, options...)...)
 
-	return &iterationState{
+	state := &iterationState{
 		t:        t,
 		teardown: teardown,
 	}
+	if s.gc != nil {
+		state.gcCycles = gcpause.NewCycles()
+	}
+
+	return state
+}
+
+// RecordHistogram records the durations of all the iterations which complete in the histogram.
+// It must be called before the iterations start.
+func (s *ActiveScenario) RecordHistogram(histogram *hdr.Histogram) {
+	s.histogram = histogram
+}
+
+// RecordAudit records all the iterations which complete in the audit log.
+// It must be called before the iterations start.
+func (s *ActiveScenario) RecordAudit(log *audit.Log) {
+	s.audit = log
+}
+
+// AnnotateGC tracks the iterations which overlap garbage collections with the tracker.
+// It must be called before the iterations start.
+func (s *ActiveScenario) AnnotateGC(tracker *gcpause.Tracker) {
+	s.gc = tracker
+}
+
+// ObservesRateDrops returns true if the setup of the scenario registered rate drop hooks.
+func (s *ActiveScenario) ObservesRateDrops() bool {
+	return s.t.HasRateDropHooks()
+}
+
+// RateDropped calls the rate drop hooks registered by the setup of the scenario.
+func (s *ActiveScenario) RateDropped(drop testing.RateDrop) {
+	s.t.RateDropped(drop)
+}
+
+// ProgressGauges reads the progress gauges registered by the setup of the scenario.
+func (s *ActiveScenario) ProgressGauges() []progress.Gauge {
+	gauges := s.t.ProgressGauges()
+	var values []progress.Gauge
+	if s.opensConnections.Load() {
+		values = append(values, progress.Gauge{Name: "connections", Value: float64(s.openConnections.Load())})
+	}
+	for _, gauge := range gauges {
+		values = append(values, progress.Gauge{Name: gauge.Name, Value: gauge.Value})
+	}
+
+	return values
+}
+
+// Summary returns the summary added by the setup and the cleanups of the scenario, see
+// testing.T.Summarize.
+func (s *ActiveScenario) Summary() []string {
+	return s.t.Summary()
 }
 
 func (s *ActiveScenario) TeardownFailed() bool {
 	return s.t.TeardownFailed()
 }
 
+// TeardownErr returns the errors of the parallel cleanups of the scenario, see testing.T.ParallelCleanup.
+func (s *ActiveScenario) TeardownErr() error {
+	return s.t.TeardownErr()
+}
+
 func (s *ActiveScenario) Failed() bool {
 	return s.t.Failed()
 }
@@ -87,6 +192,13 @@ func (s *ActiveScenario) Failed() bool {
 func (s *ActiveScenario) Run(state *iterationState) {
 	defer state.teardown()
 
+	var cyclesAtStart uint64
+	if state.gcCycles != nil {
+		cyclesAtStart = state.gcCycles.Read()
+	}
+
+	startTime := time.Now()
+	s.recordIterationStarted(startTime)
 	start := xtime.NanoTime()
 	func() {
 		defer testing.CheckResults(state.t, nil)
@@ -95,12 +207,170 @@ func (s *ActiveScenario) Run(state *iterationState) {
 
 	failed := state.t.Failed()
 	duration := xtime.NanoTime() - start
+	if s.scenario.Classifier != nil {
+		failed = s.scenario.Classifier(state.t.Err(), time.Duration(duration)) != testing.SuccessResult
+	}
 
-	s.m.RecordIterationResult(s.scenario.Name, metrics.Result(failed), duration)
+	s.recordIterationResult(metrics.Result(failed), duration)
+	s.recordIterationLabels(state.t.Labels(), metrics.Result(failed), duration)
+	s.recordIterationBytes(state.t.Bytes())
 	s.progress.Record(metrics.Result(failed), duration)
+	s.progress.RecordSlowest(progress.SlowIteration{
+		Start:     startTime,
+		Iteration: state.t.Iteration,
+		Worker:    state.worker,
+		Duration:  time.Duration(duration),
+	})
+	if failed {
+		s.progress.RecordFailure(progress.Failure{
+			Iteration: state.t.Iteration,
+			Time:      time.Now(),
+			Error:     failureSummary(state.t.Err(), time.Duration(duration)),
+			Category:  s.failureCategory(state.t.Err(), time.Duration(duration)),
+			Duration:  time.Duration(duration),
+			Worker:    state.worker,
+		})
+	}
+	if s.histogram != nil {
+		s.histogram.Record(time.Duration(duration))
+	}
+	if state.gcCycles != nil {
+		s.gc.Record(time.Duration(duration), cyclesAtStart, state.gcCycles.Read())
+	}
+	if s.audit != nil {
+		s.audit.Record(audit.Entry{
+			Iteration: state.t.Iteration,
+			Operation: state.t.Operation(),
+			Worker:    state.worker,
+			Start:     startTime,
+			Duration:  time.Duration(duration),
+			Result:    metrics.Result(failed).String(),
+			Fields:    state.t.AuditFields(),
+		})
+	}
+}
+
+// failureCategory returns the category of a failed iteration, with the function of the scenario or
+// with testing.DefaultFailureCategory.
+func (s *ActiveScenario) failureCategory(err error, duration time.Duration) string {
+	if s.scenario.FailureCategory != nil {
+		return s.scenario.FailureCategory(err, duration)
+	}
+
+	return testing.DefaultFailureCategory(err, duration)
+}
+
+// maxFailureSummaryLength is the length errors are truncated to in the failures kept by the
+// progress stats, as they are only meant to tell failures apart.
+const maxFailureSummaryLength = 120
+
+// failureSummary summarises why an iteration failed, with the first line of its error or, for
+// iterations failed by the classifier without an error, with their duration.
+func failureSummary(err error, duration time.Duration) string {
+	if err == nil {
+		return "classified as failed after " + duration.Round(time.Millisecond).String()
+	}
+
+	summary, _, _ := strings.Cut(err.Error(), "\n")
+	if len(summary) > maxFailureSummaryLength {
+		summary = summary[:maxFailureSummaryLength] + "..."
+	}
+
+	return summary
+}
+
+// RecordTriggeredIterations records iterations the trigger planned to start, the target rate of
+// triggers which start iterations at a rate.
+func (s *ActiveScenario) RecordTriggeredIterations(iterations int) {
+	if iterations <= 0 {
+		return
+	}
+
+	s.progress.RecordTriggered(time.Now(), iterations)
+	if s.stopped.Load() {
+		return
+	}
+
+	s.m.RecordIterationsTriggered(s.scenario.Name, iterations)
+}
+
+func (s *ActiveScenario) recordIterationStarted(at time.Time) {
+	s.progress.RecordStarted(at)
+	if s.stopped.Load() {
+		return
+	}
+
+	s.m.RecordIterationStarted(s.scenario.Name)
+}
+
+// RecordDroppedIteration records an iteration which was planned to start at the given time, but was
+// dropped because all the workers were busy.
+func (s *ActiveScenario) RecordDroppedIteration(planned time.Time) {
+	s.recordIterationResult(metrics.DroppedResult, instantDuration)
+	s.progress.RecordDropped(planned)
+}
+
+// StopRecording stops recording iteration metrics. It is called once the run has completed and
+// the final metrics have been pushed.
+func (s *ActiveScenario) StopRecording() {
+	s.stopped.Store(true)
+}
+
+// recordConnectionEvent counts an event of the connections of connection pools.
+func (s *ActiveScenario) recordConnectionEvent(event string) {
+	if s.stopped.Load() {
+		return
+	}
+	s.m.RecordConnectionEvent(s.scenario.Name, event)
 }
 
-func (s *ActiveScenario) RecordDroppedIteration() {
-	s.m.RecordIterationResult(s.scenario.Name, metrics.DroppedResult, instantDuration)
-	s.progress.Record(metrics.DroppedResult, instantDuration)
+// StopIterations cuts the delays of the iterations still running short, see testing.T.Sleep, once
+// the run stops triggering iterations.
+func (s *ActiveScenario) StopIterations() {
+	s.stoppingOnce.Do(func() { close(s.stopping) })
+}
+
+func (s *ActiveScenario) recordIterationResult(result metrics.ResultType, nanoseconds int64) {
+	if s.stopped.Load() {
+		return
+	}
+
+	s.m.RecordIterationResult(s.scenario.Name, result, nanoseconds)
+}
+
+// recordIterationBytes records the bytes sent and received by an iteration, see testing.T.RecordBytes.
+func (s *ActiveScenario) recordIterationBytes(sent, received uint64) {
+	if sent == 0 && received == 0 {
+		return
+	}
+
+	s.progress.RecordBytes(sent, received)
+	if s.stopped.Load() {
+		return
+	}
+
+	s.m.RecordIterationBytes(s.scenario.Name, sent, received)
+}
+
+// recordStage records the duration of a stage of an iteration, timed with testing.T.Time, as an
+// operation so that the operations of the run can be evaluated individually, and as the stage label
+// of the iteration metric.
+func (s *ActiveScenario) recordStage(stage string, failed bool, duration time.Duration) {
+	result := metrics.Result(failed)
+	s.progress.RecordOperation(stage, result, duration.Nanoseconds())
+	if s.stopped.Load() {
+		return
+	}
+
+	s.m.RecordIterationStage(s.scenario.Name, stage, result, duration.Nanoseconds())
+}
+
+func (s *ActiveScenario) recordIterationLabels(labels map[string]string, result metrics.ResultType, nanoseconds int64) {
+	if s.stopped.Load() {
+		return
+	}
+
+	for label, value := range labels {
+		s.m.RecordIterationLabel(s.scenario.Name, label, value, result, nanoseconds)
+	}
 }
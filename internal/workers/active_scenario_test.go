@@ -0,0 +1,91 @@
+package workers_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	io_prometheus_client "github.com/prometheus/client_model/go"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/log"
+	"github.com/form3tech-oss/f1/v2/internal/metrics"
+	"github.com/form3tech-oss/f1/v2/internal/progress"
+	"github.com/form3tech-oss/f1/v2/internal/tracing"
+	"github.com/form3tech-oss/f1/v2/internal/workers"
+	"github.com/form3tech-oss/f1/v2/pkg/f1/scenarios"
+	f1testing "github.com/form3tech-oss/f1/v2/pkg/f1/testing"
+)
+
+// runOnce triggers a single iteration of runFn, recording its metrics in m, once the iteration
+// started. It returns the scenario of the run, and a function stopping the run and waiting for its
+// iteration to complete.
+func runOnce(t *testing.T, m *metrics.Metrics, runFn f1testing.RunFn) (*workers.ActiveScenario, func()) {
+	t.Helper()
+
+	started := make(chan struct{})
+	scenario := workers.NewActiveScenario(
+		&scenarios.Scenario{
+			Name: "scenario",
+			RunFn: func(t *f1testing.T) {
+				close(started)
+				runFn(t)
+			},
+		},
+		m,
+		&progress.Stats{},
+		log.NewDiscardLogger(),
+		logrus.New(),
+		nil,
+		nil,
+	)
+	manager := workers.New(1, scenario, tracing.Noop())
+	pool := manager.NewTriggerPool(1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	pool.Start(ctx)
+	pool.Trigger(ctx, 1)
+	<-started
+
+	return scenario, func() {
+		cancel()
+		select {
+		case <-manager.WaitForCompletion():
+		case <-time.After(time.Second):
+			t.Fatal("the workers did not complete")
+		}
+	}
+}
+
+func TestStagesOfIterationsOutlivingTheirRunAreNotRecordedByTheNextRun(t *testing.T) {
+	t.Parallel()
+
+	// the metrics are shared by the runs of a process
+	m := metrics.NewInstance(prometheus.NewRegistry(), true)
+
+	release := make(chan struct{})
+	first, completeFirst := runOnce(t, m, func(t *f1testing.T) {
+		<-release
+		t.Time("checkout", func() {})
+	})
+	// the first run completes without waiting for its iteration any longer
+	first.StopRecording()
+
+	_, completeSecond := runOnce(t, m, func(t *f1testing.T) {
+		t.Time("checkout", func() {})
+	})
+	completeSecond()
+
+	close(release)
+	completeFirst()
+
+	metric := &io_prometheus_client.Metric{}
+	summary, ok := m.Iteration.WithLabelValues("scenario", "checkout", metrics.SucessResult.String()).(prometheus.Summary)
+	require.True(t, ok)
+	require.NoError(t, summary.Write(metric))
+	assert.Equal(t, uint64(1), metric.GetSummary().GetSampleCount())
+}
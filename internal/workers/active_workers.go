@@ -0,0 +1,38 @@
+package workers
+
+import (
+	"log/slog"
+	"sync/atomic"
+)
+
+// activeWorkers limits the workers of trigger pools which take jobs, see SetActiveWorkers.
+type activeWorkers struct {
+	// limit is the number of workers of each pool which take jobs, all of them if 0
+	limit atomic.Int64
+}
+
+// SetActiveWorkers limits the workers of each trigger pool which take jobs to the first workers,
+// or lets all of them take jobs if workers is 0, so that the concurrency of a run can be adjusted
+// while it is in progress. Workers left idle finish the iteration they are running, and workers
+// let back in take jobs once the trigger triggers jobs again.
+func (m *PoolManager) SetActiveWorkers(workers int) {
+	previous := m.activeLimit.limit.Swap(int64(workers))
+	if previous != int64(workers) {
+		m.trace("active workers set", slog.Int("workers", workers))
+	}
+}
+
+// active returns true if the worker at index takes jobs.
+func (a *activeWorkers) active(index int) bool {
+	limit := a.limit.Load()
+	return limit == 0 || int64(index) < limit
+}
+
+// available returns the number of the started workers which take jobs.
+func (a *activeWorkers) available(started int64) int64 {
+	if limit := a.limit.Load(); limit > 0 {
+		return min(started, limit)
+	}
+
+	return started
+}
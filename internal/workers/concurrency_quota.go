@@ -0,0 +1,84 @@
+package workers
+
+import (
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// concurrencyQuota caps the workers of the pools of an operation, see LimitConcurrency.
+type concurrencyQuota struct {
+	operation string
+	workers   int
+	// dropped is the number of iterations dropped by pools which were capped by the quota
+	dropped atomic.Uint64
+}
+
+// concurrencyQuotas are the quotas of the operations of a run, shared by their pool managers.
+type concurrencyQuotas struct {
+	quotas []*concurrencyQuota
+	mu     sync.Mutex
+}
+
+// QuotaDrops counts the iterations of an operation dropped while its pools were capped by its
+// concurrency quota.
+type QuotaDrops struct {
+	Operation string
+	Workers   int
+	Count     uint64
+}
+
+// LimitConcurrency caps the workers of the pools started afterwards by m at workers, so that an
+// operation can't take more than its share of the concurrency of a run mixing operations. The
+// iterations the capped pools drop are counted, see QuotaDrops.
+func (m *PoolManager) LimitConcurrency(workers int) {
+	quota := &concurrencyQuota{operation: m.operation, workers: workers}
+	m.quota = quota
+
+	m.quotas.mu.Lock()
+	defer m.quotas.mu.Unlock()
+	m.quotas.quotas = append(m.quotas.quotas, quota)
+}
+
+// QuotaDrops returns the iterations dropped by the operations of the run while they were capped by
+// their concurrency quotas, by operation.
+func (m *PoolManager) QuotaDrops() []QuotaDrops {
+	m.quotas.mu.Lock()
+	defer m.quotas.mu.Unlock()
+
+	var drops []QuotaDrops
+	for _, quota := range m.quotas.quotas {
+		if dropped := quota.dropped.Load(); dropped > 0 {
+			drops = append(drops, QuotaDrops{Operation: quota.operation, Workers: quota.workers, Count: dropped})
+		}
+	}
+	slices.SortFunc(drops, func(a, b QuotaDrops) int {
+		return strings.Compare(a.Operation, b.Operation)
+	})
+
+	return drops
+}
+
+// poolWorkers returns the number of workers of a pool of numWorkers workers, with the quota which
+// capped it, if any.
+func (m *PoolManager) poolWorkers(pool string, numWorkers int) (int, *concurrencyQuota) {
+	if m.quota == nil || numWorkers <= m.quota.workers {
+		return numWorkers, nil
+	}
+
+	m.trace("pool capped by concurrency quota", slog.String("pool", pool),
+		slog.Int("workers", numWorkers), slog.Int("quota", m.quota.workers))
+
+	return m.quota.workers, m.quota
+}
+
+// recordDropped counts the iterations dropped by a pool capped by q, if it is capped.
+func (q *concurrencyQuota) recordDropped(dropped int64) {
+	if q == nil || dropped <= 0 {
+		return
+	}
+
+	q.dropped.Add(uint64(dropped))
+}
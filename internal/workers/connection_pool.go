@@ -0,0 +1,128 @@
+package workers
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/goroutines"
+	"github.com/form3tech-oss/f1/v2/pkg/f1/testing"
+)
+
+const connectionPoolName = "connection"
+
+// ReconnectDelay is how long a dropped connection waits before it is opened again, so that a target
+// refusing connections isn't flooded with attempts.
+const ReconnectDelay = 100 * time.Millisecond
+
+// Events of the connections of a ConnectionPool, counted by the connection events metric.
+const (
+	ConnectionOpened  = "opened"
+	ConnectionClosed  = "closed"
+	ConnectionDropped = "dropped"
+)
+
+// ConnectionPool keeps a number of connections open, for capacity tests of persistent connections
+// such as websockets. Every connection is maintained by a worker, running an iteration which holds
+// the connection open until the context of its T is cancelled, when the connection is closed by
+// SetConnections or at the end of the run. A connection whose iteration returns before then is
+// dropped, and opened again by a new iteration after ReconnectDelay.
+type ConnectionPool struct {
+	manager *PoolManager
+	ctx     context.Context
+	// closers close the open connections, in the order they were opened
+	closers []context.CancelFunc
+	// opened is the number of connections opened so far, to name the worker of the next one
+	opened int
+	mu     sync.Mutex
+}
+
+// NewConnectionPool returns a pool of connections which are all closed when ctx is done.
+func (m *PoolManager) NewConnectionPool(ctx context.Context) *ConnectionPool {
+	m.activeScenario.opensConnections.Store(true)
+	m.trace("pool started", slog.String("pool", connectionPoolName))
+
+	return &ConnectionPool{manager: m, ctx: ctx}
+}
+
+// SetConnections opens or closes connections so that n are open, closing the most recently opened
+// connections first.
+func (p *ConnectionPool) SetConnections(n int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.ctx.Err() != nil {
+		return
+	}
+
+	for len(p.closers) < n {
+		p.closers = append(p.closers, p.open())
+	}
+	for len(p.closers) > n {
+		last := len(p.closers) - 1
+		p.closers[last]()
+		p.closers = p.closers[:last]
+	}
+}
+
+// Connections returns the number of connections the pool keeps open.
+func (p *ConnectionPool) Connections() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.closers)
+}
+
+func (p *ConnectionPool) open() context.CancelFunc {
+	ctx, cancel := context.WithCancel(p.ctx)
+	state := p.manager.activeScenario.newIterationState(p.manager.operation, testing.WithContext(ctx))
+	state.worker = connectionPoolName + "-" + strconv.Itoa(p.opened)
+	p.opened++
+
+	p.manager.iterations.runningWorkers.Add(1)
+	goroutines.Go(ctx, goroutines.Worker, func(ctx context.Context) {
+		p.maintain(ctx, state)
+	}, p.manager.workerLabels(state)...)
+
+	return cancel
+}
+
+// maintain holds a connection open with iterations of the scenario until ctx is done, opening it
+// again whenever it is dropped.
+func (p *ConnectionPool) maintain(ctx context.Context, state *iterationState) {
+	defer p.manager.iterations.runningWorkers.Done()
+	defer state.t.ReleaseWorkerValues()
+
+	scenario := p.manager.activeScenario
+	scenario.openConnections.Add(1)
+	defer scenario.openConnections.Add(-1)
+
+	stage := p.manager.newStageLabel()
+	for ctx.Err() == nil {
+		iteration, err := p.manager.NextIteration()
+		if err != nil {
+			return
+		}
+
+		state.t.Reset(strconv.FormatUint(iteration, 10))
+		stage.update(ctx)
+		scenario.recordConnectionEvent(ConnectionOpened)
+		p.manager.traceIteration("connection opened", iteration)
+		scenario.Run(state)
+
+		if ctx.Err() != nil {
+			scenario.recordConnectionEvent(ConnectionClosed)
+			p.manager.traceIteration("connection closed", iteration)
+			return
+		}
+
+		scenario.recordConnectionEvent(ConnectionDropped)
+		p.manager.traceIteration("connection dropped", iteration)
+		select {
+		case <-ctx.Done():
+		case <-time.After(ReconnectDelay):
+		}
+	}
+}
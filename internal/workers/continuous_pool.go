@@ -2,15 +2,22 @@ package workers
 
 import (
 	"context"
+	"log/slog"
 	"strconv"
 	"sync"
 	"sync/atomic"
+
+	"github.com/form3tech-oss/f1/v2/internal/goroutines"
 )
 
+const continuousPoolName = "continuous"
+
 func newContinuousPool(m *PoolManager, numWorkers int) *ContinuousPool {
+	numWorkers, _ = m.poolWorkers(continuousPoolName, numWorkers)
+
 	return &ContinuousPool{
 		numWorkers:         numWorkers,
-		iterationStatePool: m.makeIterationStatePool(numWorkers),
+		iterationStatePool: m.makeIterationStatePool(continuousPoolName, numWorkers),
 		manager:            m,
 	}
 }
@@ -30,18 +37,22 @@ func (p *ContinuousPool) Start(ctx context.Context) {
 	workersStarted := sync.WaitGroup{}
 
 	workersStarted.Add(p.numWorkers)
-	p.manager.runningWorkers.Add(p.numWorkers)
-	for _, iterationState := range p.iterationStatePool {
-		go p.startWorker(iterationState, &workersStarted)
+	p.manager.iterations.runningWorkers.Add(p.numWorkers)
+	for i, iterationState := range p.iterationStatePool {
+		goroutines.Go(workerCtx, goroutines.Worker, func(ctx context.Context) {
+			p.startWorker(ctx, i, iterationState, &workersStarted)
+		}, p.manager.workerLabels(iterationState)...)
 	}
+	p.manager.trace("pool started", slog.String("pool", continuousPoolName), slog.Int("workers", p.numWorkers))
 
 	// context.Done() and context.Err() for context that can be cancelled use a Lock.
 	// To avoid frequent locking - use an atomic.Bool for cancellation instead of checking the
 	// context on each iteration
-	go func() {
+	goroutines.Go(workerCtx, "pool-stop", func(context.Context) {
 		<-workerCtx.Done()
+		p.manager.trace("pool stopped", slog.String("pool", continuousPoolName))
 		p.stopWorkers.Store(true)
-	}()
+	})
 }
 
 func (p *ContinuousPool) maxIterationsReached() {
@@ -49,18 +60,31 @@ func (p *ContinuousPool) maxIterationsReached() {
 }
 
 func (p *ContinuousPool) startWorker(
+	ctx context.Context,
+	index int,
 	iterationState *iterationState,
 	workersStarted *sync.WaitGroup,
 ) {
-	defer p.manager.runningWorkers.Done()
+	defer p.manager.iterations.runningWorkers.Done()
+	defer iterationState.t.ReleaseWorkerValues()
+	p.manager.pinWorker(continuousPoolName, index)
 
 	// wait for all workers to start before execution to make sure we're executing at the
 	// concurrency requested
 	workersStarted.Done()
 	workersStarted.Wait()
+	if !p.manager.waitToStartWorker(ctx, continuousPoolName, p.manager.workerStartDelay(index, p.numWorkers)) {
+		return
+	}
 
+	pacer := p.manager.newWorkerPacer()
+	stage := p.manager.newStageLabel()
 	// use and atomic.Bool to control execution to avoid mutex usage in channels and context.Context
 	for !p.stopWorkers.Load() {
+		if !pacer.wait(ctx, p.manager, continuousPoolName) {
+			return
+		}
+
 		iteration, err := p.manager.NextIteration()
 		if err != nil {
 			p.maxIterationsReached()
@@ -68,6 +92,10 @@ func (p *ContinuousPool) startWorker(
 		}
 
 		iterationState.t.Reset(strconv.FormatUint(iteration, 10))
+		stage.update(ctx)
+		pacer.started()
+		p.manager.traceIteration("iteration started", iteration)
 		p.manager.activeScenario.Run(iterationState)
+		p.manager.traceIteration("iteration completed", iteration)
 	}
 }
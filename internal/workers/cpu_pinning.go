@@ -0,0 +1,41 @@
+package workers
+
+import (
+	"log/slog"
+	"sync"
+
+	"github.com/form3tech-oss/f1/v2/internal/cpupin"
+	"github.com/form3tech-oss/f1/v2/internal/log"
+)
+
+// cpuPinning partitions the workers of the pools of a run across CPUs, see PinWorkers.
+type cpuPinning struct {
+	cpus []int
+	// failed warns once that workers couldn't be pinned, rather than once per worker
+	failed sync.Once
+}
+
+// PinWorkers pins each worker of the pools started afterwards to one of cpus, in turn, so that the
+// scheduler doesn't move workers between CPUs. Workers which can't be pinned run unpinned.
+func (m *PoolManager) PinWorkers(cpus []int) {
+	m.cpuPinning.cpus = cpus
+}
+
+// pinWorker pins the calling worker, the index-th worker of the pool, if workers are pinned.
+func (m *PoolManager) pinWorker(pool string, index int) {
+	pinning := m.cpuPinning
+	if len(pinning.cpus) == 0 {
+		return
+	}
+
+	cpu := pinning.cpus[index%len(pinning.cpus)]
+	if err := cpupin.Pin(cpu); err != nil {
+		pinning.failed.Do(func() {
+			m.activeScenario.logger.Warn("unable to pin workers to cpus", log.ErrorAttr(err))
+		})
+		return
+	}
+	if m.tracing {
+		m.trace("worker pinned", slog.String("pool", pool), slog.Int("cpu", cpu))
+	}
+}
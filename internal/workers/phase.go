@@ -0,0 +1,92 @@
+package workers
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/pkg/f1/testing"
+)
+
+// stageStartPrecision is how precisely the start of a stage is searched for, see runPhase.stage.
+const stageStartPrecision = time.Millisecond
+
+// runPhase tells iterations where the run is, see testing.T.Phase.
+type runPhase struct {
+	start time.Time
+	// offset is the duration of the runs resumed by the run
+	offset  time.Duration
+	stageAt func(elapsed time.Duration) string
+	// current is the last stage observed, with when it started
+	current atomic.Pointer[observedStage]
+}
+
+type observedStage struct {
+	name    string
+	started time.Duration
+	// observed is when the stage was first observed, which the next stage started after
+	observed time.Duration
+}
+
+// TrackPhase sets the phase of the run returned to iterations by testing.T.Phase, from the start of
+// the load of the run, the duration of the runs it resumed and the stages of its trigger, if any.
+func (s *ActiveScenario) TrackPhase(
+	start time.Time,
+	offset time.Duration,
+	stageAt func(elapsed time.Duration) string,
+) {
+	s.phase.Store(&runPhase{start: start, offset: offset, stageAt: stageAt})
+}
+
+func (s *ActiveScenario) currentPhase() testing.Phase {
+	phase := s.phase.Load()
+	if phase == nil {
+		return testing.Phase{}
+	}
+
+	elapsed := phase.offset + time.Since(phase.start)
+	if phase.stageAt == nil {
+		return testing.Phase{RunElapsed: elapsed}
+	}
+	stage := phase.stage(elapsed)
+
+	return testing.Phase{Stage: stage.name, StageElapsed: elapsed - stage.started, RunElapsed: elapsed}
+}
+
+// stage returns the stage running after elapsed. When the stage changed since it was last observed,
+// its start is searched for between then and elapsed, so that it doesn't depend on when iterations
+// happen to observe it.
+func (p *runPhase) stage(elapsed time.Duration) *observedStage {
+	name := p.stageAt(elapsed)
+	for {
+		current := p.current.Load()
+		if current != nil && current.name == name {
+			return current
+		}
+
+		var after time.Duration
+		if current != nil {
+			after = current.observed
+		}
+		next := &observedStage{name: name, started: p.stageStart(name, after, elapsed), observed: elapsed}
+		if p.current.CompareAndSwap(current, next) {
+			return next
+		}
+	}
+}
+
+// stageStart returns when the named stage, running at before, started after the given duration.
+func (p *runPhase) stageStart(name string, after, before time.Duration) time.Duration {
+	if p.stageAt(after) == name {
+		return after
+	}
+	for before-after > stageStartPrecision {
+		middle := after + (before-after)/2
+		if p.stageAt(middle) == name {
+			before = middle
+		} else {
+			after = middle
+		}
+	}
+
+	return before
+}
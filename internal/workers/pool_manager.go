@@ -2,37 +2,123 @@ package workers
 
 import (
 	"errors"
+	"log/slog"
+	"strconv"
 	"sync"
 	"sync/atomic"
+	"time"
 
+	"github.com/form3tech-oss/f1/v2/internal/gcpause"
+	"github.com/form3tech-oss/f1/v2/internal/progress"
+	"github.com/form3tech-oss/f1/v2/internal/tracing"
 	"github.com/form3tech-oss/f1/v2/pkg/f1/testing"
 )
 
 type iterationState struct {
 	teardown func()
 	t        *testing.T
+	// worker names the worker of the pool the iterations run on, as recorded in the audit log
+	worker string
+	// gcCycles reads the garbage collection cycles when the iterations are annotated with them
+	gcCycles *gcpause.Cycles
 }
 
 type PoolManager struct {
 	activeScenario *ActiveScenario
+	tracer         tracing.Tracer
+	// tracing avoids building the attributes of the events of every iteration when tracing is off
+	tracing bool
+	// iterations is shared by the pool managers of all the operations of a run
+	iterations *iterations
+	// operation is the name of the operation the iterations of the pools are triggered for
+	operation string
+	// rateOverride replaces the rates planned by the trigger, see SetRateOverride
+	rateOverride *rateOverride
+	// cpuPinning is shared by the pool managers of all the operations of a run
+	cpuPinning *cpuPinning
+	abort      *abort
+	stageJumps *stageJumps
+	// workerRamp is how long the workers of each pool take to start, see RampWorkers
+	workerRamp time.Duration
+	// workerPacing is the shortest interval between the iterations of a worker, see PaceWorkers
+	workerPacing time.Duration
+	// quota optionally caps the workers of the pools of the operation, see LimitConcurrency
+	quota *concurrencyQuota
+	// quotas is shared by the pool managers of all the operations of a run
+	quotas *concurrencyQuotas
+	// activeLimit limits the workers which take jobs, see SetActiveWorkers, and is shared by the pool
+	// managers of all the operations of a run
+	activeLimit *activeWorkers
+	// stageAt returns the stage the goroutines of the workers are labelled with, see LabelStages
+	stageAt func() string
+	// shared optionally limits the iterations in progress of all the operations of a run, see
+	// ShareConcurrency
+	shared *sharedConcurrency
+}
+
+type iterations struct {
 	runningWorkers sync.WaitGroup
 	iteration      atomic.Uint64
 	maxIterations  uint64
+	// maxReached is set once the last of maxIterations started
+	maxReached atomic.Bool
+	// maxReachedCh is closed when maxReached is set
+	maxReachedCh   chan struct{}
+	maxReachedOnce sync.Once
+	// offset is added to the count of iterations to number them, see UniqueIterations
+	offset uint64
+	// reservation optionally reserves the numbers of iterations before they start
+	reservation *reservation
 }
 
-func New(maxIterations uint64, activeScenario *ActiveScenario) *PoolManager {
+func New(maxIterations uint64, activeScenario *ActiveScenario, tracer tracing.Tracer) *PoolManager {
 	w := &PoolManager{
 		activeScenario: activeScenario,
-		maxIterations:  maxIterations,
+		tracer:         tracer,
+		tracing:        tracer != tracing.Noop(),
+		iterations: &iterations{
+			maxIterations: maxIterations,
+			maxReachedCh:  make(chan struct{}),
+		},
+		rateOverride: &rateOverride{current: &atomic.Pointer[RateOverride]{}, paused: &atomic.Bool{}},
+		cpuPinning:   &cpuPinning{},
+		abort:        &abort{ch: make(chan struct{})},
+		stageJumps:   &stageJumps{ch: make(chan string, 1)},
+		quotas:       &concurrencyQuotas{},
+		activeLimit:  &activeWorkers{},
 	}
 
 	return w
 }
 
-func (m *PoolManager) makeIterationStatePool(numWorkers int) []*iterationState {
+// ForOperation returns a pool manager whose pools trigger iterations of the named operation,
+// see testing.T.Operation. The iterations are counted and waited for together with the
+// iterations of m.
+func (m *PoolManager) ForOperation(operation string) *PoolManager {
+	return &PoolManager{
+		activeScenario: m.activeScenario,
+		tracer:         m.tracer,
+		tracing:        m.tracing,
+		iterations:     m.iterations,
+		operation:      operation,
+		rateOverride:   &rateOverride{current: m.rateOverride.current, paused: m.rateOverride.paused},
+		cpuPinning:     m.cpuPinning,
+		abort:          m.abort,
+		stageJumps:     m.stageJumps,
+		workerRamp:     m.workerRamp,
+		workerPacing:   m.workerPacing,
+		quotas:         m.quotas,
+		activeLimit:    m.activeLimit,
+		stageAt:        m.stageAt,
+		shared:         m.shared,
+	}
+}
+
+func (m *PoolManager) makeIterationStatePool(pool string, numWorkers int) []*iterationState {
 	statePool := make([]*iterationState, numWorkers)
 	for i := range numWorkers {
-		statePool[i] = m.activeScenario.newIterationState()
+		statePool[i] = m.activeScenario.newIterationState(m.operation)
+		statePool[i].worker = pool + "-" + strconv.Itoa(i)
 	}
 
 	return statePool
@@ -42,30 +128,107 @@ func (m *PoolManager) WaitForCompletion() <-chan struct{} {
 	done := make(chan struct{})
 	go func() {
 		defer close(done)
-		m.runningWorkers.Wait()
+		m.iterations.runningWorkers.Wait()
 	}()
 	return done
 }
 
 func (m *PoolManager) MaxIterationsReached() bool {
-	if m.maxIterations > 0 && m.iteration.Load() > m.maxIterations {
-		return true
-	}
+	return m.iterations.maxReached.Load()
+}
 
-	return false
+// MaxIterationsDone returns a channel which is closed once all the max iterations have started,
+// so that the run can stop triggering iterations and wait for the active ones to complete.
+func (m *PoolManager) MaxIterationsDone() <-chan struct{} {
+	return m.iterations.maxReachedCh
+}
+
+// IterationStats returns the durations of the iterations completed so far and the number of
+// dropped iterations, so that triggers can adapt the rate to how the system under test copes.
+func (m *PoolManager) IterationStats() progress.Snapshot {
+	return m.activeScenario.progress.Total()
+}
+
+// IterationsStarted returns the number of iterations started by the pools of the run.
+func (m *PoolManager) IterationsStarted() uint64 {
+	return m.iterations.iteration.Load()
 }
 
 var errMaxIterationsReached = errors.New("max iterations reached")
 
 func (m *PoolManager) NextIteration() (uint64, error) {
-	iteration := m.iteration.Add(1)
-	if m.maxIterations > 0 && iteration > m.maxIterations {
-		return 0, errMaxIterationsReached
+	counter := m.iterations
+	if counter.maxIterations == 0 {
+		// the counter can't realistically overflow, at a billion iterations per second it would take 584 years
+		return m.number(counter.iteration.Add(1))
+	}
+
+	// the counter stops at maxIterations rather than counting every attempt to start an
+	// iteration after the limit was reached, so that it can't overflow however long the run is.
+	for {
+		current := counter.iteration.Load()
+		if current >= counter.maxIterations {
+			return 0, errMaxIterationsReached
+		}
+
+		if counter.iteration.CompareAndSwap(current, current+1) {
+			if current+1 == counter.maxIterations {
+				m.maxIterationsStarted()
+			}
+			return m.number(current + 1)
+		}
+	}
+}
+
+// maxIterationsStarted records that the last of the max iterations started, so that the run stops
+// triggering iterations rather than waiting for one to be refused.
+func (m *PoolManager) maxIterationsStarted() {
+	counter := m.iterations
+	counter.maxReachedOnce.Do(func() {
+		counter.maxReached.Store(true)
+		close(counter.maxReachedCh)
+		m.tracer.Event("max iterations reached", slog.Uint64("max_iterations", counter.maxIterations))
+	})
+}
+
+// number returns the number of the nth iteration of the run, once it is reserved.
+func (m *PoolManager) number(n uint64) (uint64, error) {
+	counter := m.iterations
+	iteration := counter.offset + n
+	if counter.reservation == nil {
+		return iteration, nil
+	}
+
+	if err := counter.reservation.take(iteration); err != nil {
+		m.Abort("unable to reserve iteration numbers", err)
+		return 0, err
 	}
 
 	return iteration, nil
 }
 
+// trace records an event of the pools of the manager, with the name of their operation.
+func (m *PoolManager) trace(name string, attrs ...slog.Attr) {
+	if m.operation != "" {
+		attrs = append(attrs, slog.String("operation", m.operation))
+	}
+	m.tracer.Event(name, attrs...)
+}
+
+// traceIteration records an event of an iteration, if tracing is on.
+func (m *PoolManager) traceIteration(name string, iteration uint64) {
+	if m.tracing {
+		m.trace(name, slog.Uint64("iteration", iteration))
+	}
+}
+
+// traceWorker records an event of a worker interacting with its pool, if tracing is on.
+func (m *PoolManager) traceWorker(name string, pool string) {
+	if m.tracing {
+		m.trace(name, slog.String("pool", pool))
+	}
+}
+
 func (m *PoolManager) NewTriggerPool(numWorkers int) *TriggerPool {
 	return newTriggerPool(m, numWorkers)
 }
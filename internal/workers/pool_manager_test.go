@@ -0,0 +1,251 @@
+package workers_test
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/tracing"
+	"github.com/form3tech-oss/f1/v2/internal/workers"
+)
+
+func TestNextIterationStopsAtMaxIterations(t *testing.T) {
+	t.Parallel()
+
+	const maxIterations = 1000
+
+	manager := workers.New(maxIterations, nil, tracing.Noop())
+
+	var started atomic.Uint64
+	var wg sync.WaitGroup
+	for range 16 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// every goroutine keeps trying well past the limit
+			for range maxIterations {
+				if _, err := manager.NextIteration(); err == nil {
+					started.Add(1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, uint64(maxIterations), started.Load())
+	assert.True(t, manager.MaxIterationsReached())
+	requireClosed(t, manager.MaxIterationsDone())
+}
+
+func requireClosed(t *testing.T, ch <-chan struct{}) {
+	t.Helper()
+
+	select {
+	case <-ch:
+	case <-time.After(time.Second):
+		t.Fatal("the channel was not closed")
+	}
+}
+
+func TestMaxIterationsDoneIsSharedByOperations(t *testing.T) {
+	t.Parallel()
+
+	manager := workers.New(2, nil, tracing.Noop())
+	reads := manager.ForOperation("read")
+	writes := manager.ForOperation("write")
+
+	_, err := reads.NextIteration()
+	assert.NoError(t, err)
+
+	select {
+	case <-manager.MaxIterationsDone():
+		t.Fatal("max iterations done before the last iteration started")
+	default:
+	}
+	assert.False(t, manager.MaxIterationsReached())
+
+	_, err = writes.NextIteration()
+	assert.NoError(t, err)
+
+	requireClosed(t, reads.MaxIterationsDone())
+	assert.True(t, manager.MaxIterationsReached())
+
+	_, err = reads.NextIteration()
+	assert.Error(t, err)
+}
+
+func TestNextIterationWithoutMaxIterations(t *testing.T) {
+	t.Parallel()
+
+	manager := workers.New(0, nil, tracing.Noop())
+
+	for i := range uint64(100) {
+		iteration, err := manager.NextIteration()
+		assert.NoError(t, err)
+		assert.Equal(t, i+1, iteration)
+	}
+	assert.False(t, manager.MaxIterationsReached())
+}
+
+func TestOverrideRate(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name              string
+		override          *workers.RateOverride
+		rate              int
+		iterationDuration time.Duration
+		expected          []int
+	}{
+		{
+			name:              "no override",
+			rate:              3,
+			iterationDuration: time.Second,
+			expected:          []int{3, 3, 3, 3},
+		},
+		{
+			name:              "multiplier",
+			override:          &workers.RateOverride{Multiplier: 2},
+			rate:              3,
+			iterationDuration: time.Second,
+			expected:          []int{6, 6, 6, 6},
+		},
+		{
+			name:              "fractional multiplier",
+			override:          &workers.RateOverride{Multiplier: 1.5},
+			rate:              1,
+			iterationDuration: time.Second,
+			expected:          []int{1, 2, 1, 2},
+		},
+		{
+			name:              "zero multiplier",
+			override:          &workers.RateOverride{},
+			rate:              3,
+			iterationDuration: time.Second,
+			expected:          []int{0, 0, 0, 0},
+		},
+		{
+			name:              "absolute rate",
+			override:          &workers.RateOverride{Rate: 50, Interval: time.Second},
+			rate:              3,
+			iterationDuration: 100 * time.Millisecond,
+			expected:          []int{5, 5, 5, 5},
+		},
+		{
+			name:              "absolute rate spread over ticks",
+			override:          &workers.RateOverride{Rate: 10, Interval: time.Second},
+			rate:              3,
+			iterationDuration: 50 * time.Millisecond,
+			expected:          []int{0, 1, 0, 1},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			manager := workers.New(0, nil, tracing.Noop())
+			manager.SetRateOverride(test.override)
+
+			rates := make([]int, 0, len(test.expected))
+			for range test.expected {
+				rates = append(rates, manager.OverrideRate(test.rate, test.iterationDuration))
+			}
+
+			assert.Equal(t, test.expected, rates)
+		})
+	}
+}
+
+func TestRateOverrideIsSharedByOperations(t *testing.T) {
+	t.Parallel()
+
+	manager := workers.New(0, nil, tracing.Noop())
+	reads := manager.ForOperation("read")
+
+	manager.SetRateOverride(&workers.RateOverride{Multiplier: 2})
+	assert.Equal(t, 4, reads.OverrideRate(2, time.Second))
+
+	manager.SetRateOverride(nil)
+	assert.Equal(t, 2, reads.OverrideRate(2, time.Second))
+}
+
+func TestPausedPoolsStartNoIterations(t *testing.T) {
+	t.Parallel()
+
+	manager := workers.New(0, nil, tracing.Noop())
+	reads := manager.ForOperation("read")
+	manager.SetRateOverride(&workers.RateOverride{Multiplier: 2})
+
+	manager.Pause(true)
+	assert.Zero(t, manager.OverrideRate(2, time.Second))
+	assert.Zero(t, reads.OverrideRate(2, time.Second))
+
+	manager.Pause(false)
+	assert.Equal(t, 4, reads.OverrideRate(2, time.Second))
+}
+
+func TestAbortKeepsTheFirstReason(t *testing.T) {
+	t.Parallel()
+
+	manager := workers.New(0, nil, tracing.Noop())
+	operation := manager.ForOperation("read")
+
+	reason, err := manager.AbortReason()
+	assert.Empty(t, reason)
+	require.NoError(t, err)
+
+	operation.Abort("first", errFirst)
+	manager.Abort("second", errors.New("second"))
+
+	select {
+	case <-manager.Aborted():
+	default:
+		t.Fatal("the run was not aborted")
+	}
+	reason, err = manager.AbortReason()
+	assert.Equal(t, "first", reason)
+	require.ErrorIs(t, err, errFirst)
+}
+
+var errFirst = errors.New("first")
+
+func TestUniqueIterationsAreReservedBeforeTheyStart(t *testing.T) {
+	t.Parallel()
+
+	manager := workers.New(0, nil, tracing.Noop())
+	operation := manager.ForOperation("read")
+	var reserved []uint64
+	manager.UniqueIterations(2000, func(upTo uint64) error {
+		reserved = append(reserved, upTo)
+		return nil
+	})
+
+	for i := range uint64(1500) {
+		iteration, err := operation.NextIteration()
+		require.NoError(t, err)
+		assert.Equal(t, 2001+i, iteration)
+	}
+
+	assert.Equal(t, []uint64{3000, 4000}, reserved)
+	assert.Equal(t, uint64(3500), manager.LastIteration())
+	assert.Equal(t, uint64(1500), manager.IterationsStarted())
+}
+
+func TestFailingReservationOfUniqueIterationsAbortsTheRun(t *testing.T) {
+	t.Parallel()
+
+	manager := workers.New(10, nil, tracing.Noop())
+	errDiskFull := errors.New("disk full")
+	manager.UniqueIterations(0, func(uint64) error { return errDiskFull })
+
+	_, err := manager.NextIteration()
+
+	require.ErrorIs(t, err, errDiskFull)
+	reason, err := manager.AbortReason()
+	assert.Equal(t, "unable to reserve iteration numbers", reason)
+	require.ErrorIs(t, err, errDiskFull)
+}
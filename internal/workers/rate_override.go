@@ -0,0 +1,86 @@
+package workers
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RateOverride replaces the rate planned by the trigger while the run is in progress.
+type RateOverride struct {
+	// Multiplier scales the planned rate, when Interval isn't set
+	Multiplier float64
+	// Rate iterations are started every Interval instead of the planned rate, when Interval is set
+	Rate     int
+	Interval time.Duration
+}
+
+func (o *RateOverride) String() string {
+	if o.Interval > 0 {
+		return fmt.Sprintf("%d/%s", o.Rate, o.Interval)
+	}
+
+	return fmt.Sprintf("x%g", o.Multiplier)
+}
+
+// rate returns the number of iterations to start instead of rate, for a tick of the trigger
+// every iterationDuration. It can be fractional, as fractions are carried over to the next ticks.
+func (o *RateOverride) rate(rate int, iterationDuration time.Duration) float64 {
+	if o.Interval > 0 {
+		return float64(o.Rate) * float64(iterationDuration) / float64(o.Interval)
+	}
+
+	return float64(rate) * o.Multiplier
+}
+
+// rateOverride is the rate override shared by the pool managers of all the operations of a run,
+// with the fraction of an iteration carried over between the ticks of the trigger of an operation.
+type rateOverride struct {
+	current *atomic.Pointer[RateOverride]
+	// paused is shared like current, see Pause
+	paused *atomic.Bool
+
+	mu        sync.Mutex
+	applied   *RateOverride
+	remainder float64
+}
+
+// SetRateOverride replaces the rate planned by the trigger with the override, from the next tick
+// of the trigger, or restores the planned rate if override is nil.
+func (m *PoolManager) SetRateOverride(override *RateOverride) {
+	m.rateOverride.current.Store(override)
+}
+
+// Pause stops the rate based triggers from starting iterations from their next tick, until it is
+// called again with false. The iterations which aren't started while paused aren't dropped.
+func (m *PoolManager) Pause(paused bool) {
+	m.rateOverride.paused.Store(paused)
+}
+
+// OverrideRate returns the number of iterations to start instead of rate, the number planned by a
+// trigger ticking every iterationDuration, when the rate is overridden or the pools are paused.
+func (m *PoolManager) OverrideRate(rate int, iterationDuration time.Duration) int {
+	if m.rateOverride.paused.Load() {
+		return 0
+	}
+	override := m.rateOverride.current.Load()
+
+	m.rateOverride.mu.Lock()
+	defer m.rateOverride.mu.Unlock()
+
+	if override != m.rateOverride.applied {
+		m.rateOverride.applied = override
+		m.rateOverride.remainder = 0
+	}
+	if override == nil {
+		return rate
+	}
+
+	overridden := override.rate(rate, iterationDuration) + m.rateOverride.remainder
+	iterations := math.Floor(overridden)
+	m.rateOverride.remainder = overridden - iterations
+
+	return int(iterations)
+}
@@ -0,0 +1,53 @@
+package workers
+
+import (
+	"log/slog"
+	"sync/atomic"
+)
+
+// sharedConcurrency limits the iterations in progress of the trigger pools of all the operations of
+// a run, see ShareConcurrency.
+type sharedConcurrency struct {
+	limit int64
+	busy  atomic.Int64
+}
+
+// ShareConcurrency limits the iterations in progress of the trigger pools of all the operations of
+// m, see ForOperation, to workers in total rather than to the workers of each pool, so that the
+// operations of a run share its concurrency. Iterations triggered while all the operations together
+// are at the limit are dropped, as those triggered while all the workers of a pool are busy are.
+func (m *PoolManager) ShareConcurrency(workers int) {
+	m.shared = &sharedConcurrency{limit: int64(workers)}
+}
+
+// acquire takes one of the shared workers for an iteration, and returns whether one was free.
+func (c *sharedConcurrency) acquire() bool {
+	if c == nil {
+		return true
+	}
+
+	if c.busy.Add(1) > c.limit {
+		c.busy.Add(-1)
+		return false
+	}
+
+	return true
+}
+
+// release returns a shared worker taken by acquire.
+func (c *sharedConcurrency) release() {
+	if c != nil {
+		c.busy.Add(-1)
+	}
+}
+
+// dropShared drops a job of the pool which could not start because the operations of the run were
+// at their shared concurrency.
+func (p *TriggerPool) dropShared() {
+	p.jobsAvailableCond.L.Lock()
+	planned := p.jobsTriggeredAt
+	p.jobsAvailableCond.L.Unlock()
+
+	p.manager.trace("iteration dropped by shared concurrency", slog.String("pool", triggerPoolName))
+	p.manager.activeScenario.RecordDroppedIteration(planned)
+}
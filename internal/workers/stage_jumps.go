@@ -0,0 +1,25 @@
+package workers
+
+// stageJumps carries the requests to move the trigger on from its current stage while the run is in
+// progress, and is shared by the pool managers of all the operations of a run.
+type stageJumps struct {
+	// ch holds the name of the stage to jump to, or an empty name to skip to the next stage
+	ch chan string
+}
+
+// JumpToStage requests the trigger to stop its current stage and continue from the named stage, or
+// from the next stage if stage is empty. It returns false if a previous request wasn't taken by the
+// trigger yet. Only triggers listing their stages in api.Trigger.Stages take the requests.
+func (m *PoolManager) JumpToStage(stage string) bool {
+	select {
+	case m.stageJumps.ch <- stage:
+		return true
+	default:
+		return false
+	}
+}
+
+// StageJumps receives the stages requested by JumpToStage, an empty name skipping to the next stage.
+func (m *PoolManager) StageJumps() <-chan string {
+	return m.stageJumps.ch
+}
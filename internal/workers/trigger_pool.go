@@ -2,17 +2,26 @@ package workers
 
 import (
 	"context"
+	"log/slog"
 	"strconv"
 	"sync"
 	"sync/atomic"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/goroutines"
 )
 
+const triggerPoolName = "trigger"
+
 func newTriggerPool(m *PoolManager, numWorkers int) *TriggerPool {
+	numWorkers, quota := m.poolWorkers(triggerPoolName, numWorkers)
+
 	return &TriggerPool{
 		numWorkers:         numWorkers,
-		iterationStatePool: m.makeIterationStatePool(numWorkers),
+		iterationStatePool: m.makeIterationStatePool(triggerPoolName, numWorkers),
 		manager:            m,
 		jobsAvailableCond:  sync.NewCond(&sync.Mutex{}),
+		quota:              quota,
 	}
 }
 
@@ -25,7 +34,15 @@ type TriggerPool struct {
 	numWorkers         int
 	// jobsToExecute holds a number of pending work to execute
 	jobsToExecute jobCounter
-	stopWorkers   atomic.Bool
+	// jobsTriggeredAt is when the pending jobs were triggered, guarded by jobsAvailableCond
+	jobsTriggeredAt time.Time
+	stopWorkers     atomic.Bool
+	// busyWorkers is the number of workers running an iteration
+	busyWorkers atomic.Int64
+	// startedWorkers is the number of workers taking jobs, fewer than numWorkers while they ramp up
+	startedWorkers atomic.Int64
+	// quota is the concurrency quota which capped the workers of the pool, if any
+	quota *concurrencyQuota
 }
 
 // Trigger will trigger the execution of a numJobs in the worker pool,
@@ -37,8 +54,37 @@ func (p *TriggerPool) Trigger(ctx context.Context, numJobs int) {
 	p.sendJobsForExecution(numJobs)
 }
 
+// Admit adds numJobs to the jobs pending execution, rather than replacing them like Trigger, for
+// triggers which only start as many iterations as there are free workers, see FreeWorkers.
+func (p *TriggerPool) Admit(ctx context.Context, numJobs int) {
+	if ctx.Err() != nil || numJobs <= 0 {
+		return
+	}
+
+	p.jobsAvailableCond.L.Lock()
+	p.jobsToExecute.num.Add(int64(numJobs))
+	p.jobsTriggeredAt = time.Now()
+	p.jobsAvailableCond.Broadcast()
+	p.jobsAvailableCond.L.Unlock()
+
+	if p.manager.tracing {
+		p.manager.trace("jobs admitted", slog.Int("jobs", numJobs))
+	}
+	p.manager.activeScenario.RecordTriggeredIterations(numJobs)
+}
+
+// FreeWorkers returns the number of workers which are neither running an iteration nor about to.
+func (p *TriggerPool) FreeWorkers() int {
+	return max(int(p.availableWorkers())-int(p.busyWorkers.Load())-int(p.jobsToExecute.num.Load()), 0)
+}
+
+// availableWorkers returns the number of started workers which take jobs, see SetActiveWorkers.
+func (p *TriggerPool) availableWorkers() int64 {
+	return p.manager.activeLimit.available(p.startedWorkers.Load())
+}
+
 func (p *TriggerPool) Start(ctx context.Context) context.Context {
-	p.manager.runningWorkers.Add(p.numWorkers)
+	p.manager.iterations.runningWorkers.Add(p.numWorkers)
 
 	startedWg := sync.WaitGroup{}
 	startedWg.Add(p.numWorkers)
@@ -46,21 +92,24 @@ func (p *TriggerPool) Start(ctx context.Context) context.Context {
 	workerCtx, cancel := context.WithCancel(ctx)
 	p.workerCtxCancel = cancel
 
-	for _, statePool := range p.iterationStatePool {
-		go p.run(statePool, &startedWg)
+	for i, statePool := range p.iterationStatePool {
+		goroutines.Go(workerCtx, goroutines.Worker, func(ctx context.Context) {
+			p.run(ctx, i, statePool, &startedWg)
+		}, p.manager.workerLabels(statePool)...)
 	}
 
 	// wait for all workers to start, to make sure we have the concurrency requested,
-	// and work is not dropped
+	// and work is not dropped, unless the workers ramp up
 	startedWg.Wait()
+	p.manager.trace("pool started", slog.String("pool", triggerPoolName), slog.Int("workers", p.numWorkers))
 
 	// context.Done() and context.Err() for context that can be cancelled use a Lock.
 	// To avoid frequent locking - use an atomic.Bool for cancellation instead of checking the
 	// context on each iteration
-	go func() {
+	goroutines.Go(workerCtx, "pool-stop", func(context.Context) {
 		<-workerCtx.Done()
 		p.stop()
-	}()
+	})
 
 	return workerCtx
 }
@@ -70,8 +119,22 @@ func (p *TriggerPool) running() bool {
 }
 
 func (p *TriggerPool) stop() {
+	p.manager.trace("pool stopped", slog.String("pool", triggerPoolName))
 	p.stopWorkers.Store(true)
-	p.sendJobsForExecution(0)
+
+	p.jobsAvailableCond.L.Lock()
+	jobsDiscarded := p.jobsToExecute.set(0)
+	planned := p.jobsTriggeredAt
+	p.jobsAvailableCond.Broadcast()
+	p.jobsAvailableCond.L.Unlock()
+
+	// the pending jobs free workers were about to start are discarded rather than dropped, as
+	// they were left pending by the end of the run rather than by busy workers
+	freeWorkers := p.availableWorkers() - p.busyWorkers.Load()
+	for range max(jobsDiscarded-freeWorkers, 0) {
+		p.manager.activeScenario.RecordDroppedIteration(planned)
+	}
+	p.quota.recordDropped(jobsDiscarded - freeWorkers)
 }
 
 func (p *TriggerPool) maxIterationsReached() {
@@ -83,45 +146,98 @@ func (p *TriggerPool) sendJobsForExecution(numJobs int) {
 	p.jobsAvailableCond.L.Lock()
 
 	jobsDiscarded := p.jobsToExecute.set(numJobs)
+	// the discarded jobs were planned to start when they were triggered
+	planned := p.jobsTriggeredAt
+	p.jobsTriggeredAt = time.Now()
 	p.jobsAvailableCond.Broadcast()
 
 	p.jobsAvailableCond.L.Unlock()
 
+	if p.manager.tracing {
+		p.manager.trace("jobs triggered", slog.Int("jobs", numJobs), slog.Int64("discarded", max(jobsDiscarded, 0)))
+	}
+	p.manager.activeScenario.RecordTriggeredIterations(numJobs)
+
 	for range jobsDiscarded {
-		p.manager.activeScenario.RecordDroppedIteration()
+		p.manager.activeScenario.RecordDroppedIteration(planned)
 	}
+	p.quota.recordDropped(jobsDiscarded)
 }
 
-func (p *TriggerPool) waitForNewJobs() {
+// waitForNewJobs waits until there are jobs to execute and the worker at index takes jobs, see
+// SetActiveWorkers, or the pool stops. Idle workers are woken with the others when jobs are triggered.
+func (p *TriggerPool) waitForNewJobs(index int) {
+	p.manager.traceWorker("worker waiting for jobs", triggerPoolName)
 	p.jobsAvailableCond.L.Lock()
 
-	for p.jobsToExecute.none() && p.running() {
+	for (p.jobsToExecute.none() || !p.manager.activeLimit.active(index)) && p.running() {
 		p.jobsAvailableCond.Wait()
 	}
 	p.jobsAvailableCond.L.Unlock()
+	p.manager.traceWorker("worker woken", triggerPoolName)
 }
 
 func (p *TriggerPool) run(
+	ctx context.Context,
+	index int,
 	iterationState *iterationState,
 	startWg *sync.WaitGroup,
 ) {
-	defer p.manager.runningWorkers.Done()
+	defer p.manager.iterations.runningWorkers.Done()
+	defer iterationState.t.ReleaseWorkerValues()
+	p.manager.pinWorker(triggerPoolName, index)
+
+	delay := p.manager.workerStartDelay(index, p.numWorkers)
+	if delay == 0 {
+		p.startedWorkers.Add(1)
+	}
 	startWg.Done()
+	if delay > 0 {
+		if !p.manager.waitToStartWorker(ctx, triggerPoolName, delay) {
+			return
+		}
+		p.startedWorkers.Add(1)
+	}
 
+	pacer := p.manager.newWorkerPacer()
+	stage := p.manager.newStageLabel()
 	for p.running() {
-		if p.jobsToExecute.none() {
-			p.waitForNewJobs()
+		if pacer.pending() {
+			// the worker is busy while it waits for its pace, so that the jobs triggered meanwhile are dropped
+			p.busyWorkers.Add(1)
+			paced := pacer.wait(ctx, p.manager, triggerPoolName)
+			p.busyWorkers.Add(-1)
+			if !paced {
+				return
+			}
 		}
 
-		if p.jobsToExecute.take() {
+		if p.jobsToExecute.none() || !p.manager.activeLimit.active(index) {
+			p.waitForNewJobs(index)
+		}
+
+		if p.manager.activeLimit.active(index) && p.jobsToExecute.take() {
+			if !p.manager.shared.acquire() {
+				p.dropShared()
+				continue
+			}
+
 			iteration, err := p.manager.NextIteration()
 			if err != nil {
+				p.manager.shared.release()
 				p.maxIterationsReached()
 				return
 			}
 
 			iterationState.t.Reset(strconv.FormatUint(iteration, 10))
+			stage.update(ctx)
+			pacer.started()
+			p.busyWorkers.Add(1)
+			p.manager.traceIteration("iteration started", iteration)
 			p.manager.activeScenario.Run(iterationState)
+			p.manager.traceIteration("iteration completed", iteration)
+			p.busyWorkers.Add(-1)
+			p.manager.shared.release()
 		}
 	}
 }
@@ -0,0 +1,70 @@
+package workers_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/log"
+	"github.com/form3tech-oss/f1/v2/internal/metrics"
+	"github.com/form3tech-oss/f1/v2/internal/progress"
+	"github.com/form3tech-oss/f1/v2/internal/tracing"
+	"github.com/form3tech-oss/f1/v2/internal/workers"
+	"github.com/form3tech-oss/f1/v2/pkg/f1/scenarios"
+	f1testing "github.com/form3tech-oss/f1/v2/pkg/f1/testing"
+)
+
+// newBlockingScenario returns a scenario whose iterations signal started and block until release is
+// closed, with the stats it records its iterations in.
+func newBlockingScenario(started chan<- struct{}, release <-chan struct{}) (*workers.ActiveScenario, *progress.Stats) {
+	stats := &progress.Stats{}
+	scenario := workers.NewActiveScenario(
+		&scenarios.Scenario{
+			Name: "scenario",
+			RunFn: func(*f1testing.T) {
+				started <- struct{}{}
+				<-release
+			},
+		},
+		metrics.NewInstance(prometheus.NewRegistry(), true),
+		stats,
+		log.NewDiscardLogger(),
+		logrus.New(),
+		nil,
+		nil,
+	)
+
+	return scenario, stats
+}
+
+func TestPendingJobsOfBusyWorkersAreDroppedWhenThePoolStops(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	scenario, stats := newBlockingScenario(started, release)
+	manager := workers.New(0, scenario, tracing.Noop())
+	pool := manager.NewTriggerPool(1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pool.Start(ctx)
+	pool.Trigger(ctx, 3)
+	<-started
+
+	// the only worker is busy, so the 2 pending jobs were left by busy workers, not by the stop
+	cancel()
+	require.Eventually(t, func() bool { return stats.Dropped() == 2 }, time.Second, time.Millisecond)
+
+	close(release)
+	select {
+	case <-manager.WaitForCompletion():
+	case <-time.After(time.Second):
+		t.Fatal("the workers did not complete")
+	}
+	require.Equal(t, uint64(2), stats.Dropped())
+}
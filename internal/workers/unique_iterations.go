@@ -0,0 +1,60 @@
+package workers
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// ReservedIterationsBlock is how many iteration numbers are reserved at once by UniqueIterations,
+// trading the number of reservations for the numbers lost when the run is killed.
+const ReservedIterationsBlock = 1000
+
+// reservation hands out iteration numbers only once they are durably reserved, see
+// UniqueIterations.
+type reservation struct {
+	reserve func(upTo uint64) error
+	// upTo is the highest iteration number reserved
+	upTo atomic.Uint64
+	mu   sync.Mutex
+}
+
+// UniqueIterations numbers the iterations of the run after the number after, and only starts an
+// iteration once reserve has recorded that its number is taken, so that a run resumed after being
+// interrupted or killed never starts an iteration with the number of an iteration of the runs
+// before. Numbers are reserved ReservedIterationsBlock at a time, and the run is aborted when a
+// reservation fails. It must be called before the iterations start.
+func (m *PoolManager) UniqueIterations(after uint64, reserve func(upTo uint64) error) {
+	m.iterations.offset = after
+	m.iterations.reservation = &reservation{reserve: reserve}
+	m.iterations.reservation.upTo.Store(after)
+}
+
+// LastIteration returns the highest iteration number handed out to the pools of the run.
+func (m *PoolManager) LastIteration() uint64 {
+	return m.iterations.offset + m.iterations.iteration.Load()
+}
+
+// take reserves the numbers up to iteration, if they aren't already.
+func (r *reservation) take(iteration uint64) error {
+	if iteration <= r.upTo.Load() {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	upTo := r.upTo.Load()
+	if iteration <= upTo {
+		return nil
+	}
+	for upTo < iteration {
+		upTo += ReservedIterationsBlock
+	}
+	if err := r.reserve(upTo); err != nil {
+		return fmt.Errorf("reserving iterations up to %d: %w", upTo, err)
+	}
+	r.upTo.Store(upTo)
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package workers
+
+import (
+	"context"
+
+	"github.com/form3tech-oss/f1/v2/internal/goroutines"
+)
+
+// LabelStages labels the goroutines of the workers of the pools started afterwards with the stage
+// returned by stageAt, see goroutines.LabelStage, updated before each iteration as the stages of
+// the run change.
+func (m *PoolManager) LabelStages(stageAt func() string) {
+	m.stageAt = stageAt
+}
+
+// workerLabels returns the labels of the goroutine of the worker running the iterations of state.
+func (m *PoolManager) workerLabels(state *iterationState) []string {
+	labels := []string{goroutines.LabelWorker, state.worker}
+	if m.operation != "" {
+		labels = append(labels, goroutines.LabelOperation, m.operation)
+	}
+
+	return labels
+}
+
+// stageLabel labels the goroutine of a worker with the current stage of the run.
+type stageLabel struct {
+	stageAt func() string
+	// stage is the stage the goroutine is labelled with
+	stage string
+}
+
+func (m *PoolManager) newStageLabel() *stageLabel {
+	return &stageLabel{stageAt: m.stageAt}
+}
+
+// update labels the goroutine of the worker with the current stage, when it changed. ctx has the
+// labels the goroutine was started with.
+func (l *stageLabel) update(ctx context.Context) {
+	if l.stageAt == nil {
+		return
+	}
+
+	stage := l.stageAt()
+	if stage == l.stage {
+		return
+	}
+	l.stage = stage
+	goroutines.Relabel(ctx, goroutines.LabelStage, stage)
+}
@@ -0,0 +1,62 @@
+package workers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// PaceWorkers limits every worker of the pools started afterwards to starting an iteration every
+// interval at most, whatever the rate of the trigger, to emulate clients which are rate limited by
+// the target on their own. Iterations triggered while all the workers wait for their pace are
+// dropped, as when all the workers are busy.
+func (m *PoolManager) PaceWorkers(interval time.Duration) {
+	m.workerPacing = interval
+}
+
+// workerPacer spaces the iterations started by a worker by the pacing of its pool manager.
+type workerPacer struct {
+	// next is the earliest time the worker may start its next iteration
+	next     time.Time
+	interval time.Duration
+}
+
+func (m *PoolManager) newWorkerPacer() *workerPacer {
+	return &workerPacer{interval: m.workerPacing}
+}
+
+// pending returns true while the worker has to wait before it starts its next iteration.
+func (p *workerPacer) pending() bool {
+	return p.interval > 0 && time.Now().Before(p.next)
+}
+
+// wait waits until the worker may start its next iteration, and returns false if ctx is done first.
+func (p *workerPacer) wait(ctx context.Context, m *PoolManager, pool string) bool {
+	if p.interval <= 0 {
+		return true
+	}
+
+	delay := time.Until(p.next)
+	if delay <= 0 {
+		return true
+	}
+
+	if m.tracing {
+		m.trace("worker paced", slog.String("pool", pool), slog.Duration("delay", delay))
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// started records that the worker started an iteration, from which its next iteration is paced.
+func (p *workerPacer) started() {
+	if p.interval > 0 {
+		p.next = time.Now().Add(p.interval)
+	}
+}
@@ -0,0 +1,44 @@
+package workers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// RampWorkers starts the workers of the pools started afterwards gradually over ramp, rather than
+// all at once, so that the setup of their connections doesn't distort the first seconds of the run.
+// The first worker of each pool starts straight away and the others at even intervals.
+func (m *PoolManager) RampWorkers(ramp time.Duration) {
+	m.workerRamp = ramp
+}
+
+// workerStartDelay is how long the index-th of numWorkers workers waits before it starts.
+func (m *PoolManager) workerStartDelay(index, numWorkers int) time.Duration {
+	if m.workerRamp <= 0 || numWorkers == 0 {
+		return 0
+	}
+
+	return m.workerRamp * time.Duration(index) / time.Duration(numWorkers)
+}
+
+// waitToStartWorker waits for the delay of a worker, see workerStartDelay, and returns false if
+// ctx is done first.
+func (m *PoolManager) waitToStartWorker(ctx context.Context, pool string, delay time.Duration) bool {
+	if delay <= 0 {
+		return true
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+	}
+	if m.tracing {
+		m.trace("worker ramped up", slog.String("pool", pool), slog.Duration("delay", delay))
+	}
+
+	return true
+}
@@ -0,0 +1,127 @@
+package f1
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/pkg/f1/testing"
+)
+
+// ErrBudgetExhausted fails the iterations of a Limit whose operation was skipped because the budget
+// didn't allow it, so that skipped operations aren't counted as successful.
+var ErrBudgetExhausted = errors.New("budget exhausted")
+
+// Budget is a token bucket which caps the combined rate of operations of every scenario or
+// operation sharing it, for example to respect the rate limits of the target environment when
+// running a mixed workload:
+//
+//	budget := f1.NewBudget(100, time.Second)
+//
+//	f1.New().
+//		Add("payments", f1.CombineScenarios(
+//			budget.LimitScenario(submitPayment),
+//			budget.LimitScenario(fetchPayment),
+//		)).
+//		Execute()
+//
+// Iterations waiting for the budget keep occupying a worker, so iterations may be dropped if the
+// budget is lower than the rate of the trigger. At most one interval worth of operations wait for
+// the budget at once, further operations are skipped rather than queued, see Wait.
+type Budget struct {
+	last     time.Time
+	tokens   float64
+	capacity float64
+	// rate is the number of tokens added per nanosecond
+	rate float64
+	mu   sync.Mutex
+}
+
+// NewBudget returns a budget allowing up to limit operations per interval. It panics if limit
+// or interval are not positive.
+func NewBudget(limit int, interval time.Duration) *Budget {
+	if limit <= 0 || interval <= 0 {
+		panic(fmt.Sprintf("budget of %d per %s must be positive", limit, interval))
+	}
+
+	return &Budget{
+		last:     time.Now(),
+		tokens:   float64(limit),
+		capacity: float64(limit),
+		rate:     float64(limit) / float64(interval),
+	}
+}
+
+// Wait waits until the budget allows another operation of the iteration t, and returns whether it
+// did. It returns false without waiting when the operations already waiting for the budget would
+// use up the next interval of it, and returns false early when the run stops triggering iterations
+// or the context of t is cancelled, as testing.T.Sleep does. The iteration should skip the
+// operation when Wait returns false.
+func (b *Budget) Wait(t *testing.T) bool {
+	delay, ok := b.reserve(time.Now())
+	if !ok {
+		return false
+	}
+	if delay == 0 {
+		return true
+	}
+
+	if !t.Sleep(delay) {
+		b.cancel()
+		return false
+	}
+
+	return true
+}
+
+// Limit returns a RunFn which waits for the budget before running fn, and skips fn if the budget
+// didn't allow it, see Wait, failing the iteration with ErrBudgetExhausted.
+func (b *Budget) Limit(fn testing.RunFn) testing.RunFn {
+	return func(t *testing.T) {
+		if !b.Wait(t) {
+			t.Error(ErrBudgetExhausted)
+			return
+		}
+
+		fn(t)
+	}
+}
+
+// LimitScenario returns a ScenarioFn whose iterations wait for the budget before running.
+func (b *Budget) LimitScenario(fn testing.ScenarioFn) testing.ScenarioFn {
+	return func(t *testing.T) testing.RunFn {
+		return b.Limit(fn(t))
+	}
+}
+
+// reserve takes a token from the budget, returning how long to wait until the token is available,
+// or false if the debt of the budget would exceed one interval worth of tokens.
+func (b *Budget) reserve(now time.Time) (time.Duration, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = min(b.capacity, b.tokens+float64(elapsed)*b.rate)
+		b.last = now
+	}
+
+	// tokens become negative when operations are waiting for the budget to refill
+	if b.tokens-1 < -b.capacity {
+		return 0, false
+	}
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0, true
+	}
+
+	return time.Duration(-b.tokens / b.rate), true
+}
+
+// cancel returns the token of an operation which gave up waiting for it.
+func (b *Budget) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens = min(b.capacity, b.tokens+1)
+}
@@ -0,0 +1,102 @@
+package f1_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/log"
+	"github.com/form3tech-oss/f1/v2/pkg/f1"
+	f1testing "github.com/form3tech-oss/f1/v2/pkg/f1/testing"
+)
+
+func TestBudgetCapsCombinedRate(t *testing.T) {
+	t.Parallel()
+
+	budget := f1.NewBudget(10, 100*time.Millisecond)
+
+	start := time.Now()
+	wg := sync.WaitGroup{}
+	for range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			iteration, teardown := f1testing.NewTWithOptions("scenario")
+			defer teardown()
+			for range 10 {
+				for !budget.Wait(iteration) {
+					time.Sleep(time.Millisecond)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// the first 10 operations use the initial budget, the remaining 20 wait for it to refill
+	require.InDelta(t, 200*time.Millisecond, time.Since(start), float64(50*time.Millisecond))
+}
+
+func TestBudgetSkipsOperationsBeyondOneIntervalOfDebt(t *testing.T) {
+	t.Parallel()
+
+	budget := f1.NewBudget(1, time.Second)
+	iteration, teardown := f1testing.NewTWithOptions("scenario")
+	defer teardown()
+	require.True(t, budget.Wait(iteration))
+
+	stopping := make(chan struct{})
+	waiting, waitingTeardown := f1testing.NewTWithOptions("scenario", f1testing.WithStopping(stopping))
+	defer waitingTeardown()
+	waited := make(chan bool)
+	go func() { waited <- budget.Wait(waiting) }()
+	time.Sleep(50 * time.Millisecond)
+
+	// the next interval is already reserved by the waiting operation
+	start := time.Now()
+	require.False(t, budget.Wait(iteration))
+	require.Less(t, time.Since(start), 50*time.Millisecond)
+
+	// operations waiting for the budget give up when the run stops
+	close(stopping)
+	select {
+	case ok := <-waited:
+		require.False(t, ok)
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("the operation kept waiting for the budget once the run stopped")
+	}
+}
+
+func TestLimitFailsIterationsSkippedByTheBudget(t *testing.T) {
+	t.Parallel()
+
+	budget := f1.NewBudget(1, time.Second)
+	calls := 0
+	limited := budget.Limit(func(*f1testing.T) { calls++ })
+
+	stopping := make(chan struct{})
+	close(stopping)
+	allowed, allowedTeardown := f1testing.NewTWithOptions("scenario")
+	defer allowedTeardown()
+	limited(allowed)
+	require.Equal(t, 1, calls)
+	require.False(t, allowed.Failed())
+
+	// the budget is used up, and the stopped run doesn't wait for it to refill
+	skipped, skippedTeardown := f1testing.NewTWithOptions("scenario",
+		f1testing.WithStopping(stopping), f1testing.WithLogger(log.NewDiscardLogger()))
+	defer skippedTeardown()
+	limited(skipped)
+	require.Equal(t, 1, calls)
+	require.True(t, skipped.Failed())
+	require.ErrorIs(t, skipped.Err(), f1.ErrBudgetExhausted)
+}
+
+func TestBudgetRejectsNonPositiveLimits(t *testing.T) {
+	t.Parallel()
+
+	require.PanicsWithValue(t, "budget of 0 per 1s must be positive", func() {
+		f1.NewBudget(0, time.Second)
+	})
+}
@@ -4,15 +4,18 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io/fs"
 	"log/slog"
 	"os"
 	"os/signal"
-	"syscall"
 
 	"github.com/form3tech-oss/f1/v2/internal/envsettings"
+	"github.com/form3tech-oss/f1/v2/internal/platform"
+	"github.com/form3tech-oss/f1/v2/internal/run"
 	"github.com/form3tech-oss/f1/v2/internal/ui"
 	"github.com/form3tech-oss/f1/v2/pkg/f1/scenarios"
 	"github.com/form3tech-oss/f1/v2/pkg/f1/testing"
+	"github.com/form3tech-oss/f1/v2/pkg/f1/trigger"
 )
 
 const (
@@ -30,7 +33,11 @@ type F1 struct {
 	output    *ui.Output
 	scenarios *scenarios.Scenarios
 	profiling *profiling
-	settings  envsettings.Settings
+	profiles  fs.FS
+	tracker   *run.Tracker
+	triggers  []trigger.Builder
+	// customLogger is set by WithLogger, in which case the log settings don't apply
+	customLogger bool
 }
 
 // New instantiates a new instance of an F1 CLI.
@@ -40,7 +47,7 @@ func New() *F1 {
 	return &F1{
 		scenarios: scenarios.New(),
 		profiling: &profiling{},
-		settings:  settings,
+		tracker:   run.NewTracker(),
 		output:    ui.NewDefaultOutput(settings.Log.SlogLevel(), settings.Log.IsFormatJSON()),
 	}
 }
@@ -53,6 +60,28 @@ func New() *F1 {
 // The logger will be used for non-interactive output, file logs or when `--verbose` is specified.
 func (f *F1) WithLogger(logger *slog.Logger) *F1 {
 	f.output = ui.NewDefaultOutputWithLogger(logger)
+	f.customLogger = true
+	return f
+}
+
+// WithProfiles registers load profiles embedded in the scenario binary, so that they ship with
+// the scenarios and can be run by name with the file trigger. Profiles are file trigger config
+// files, and are run with "embedded://" followed by the file name with or without the yaml
+// extension. For example, with the following profiles:
+//
+//	//go:embed profiles
+//	var profiles embed.FS
+//
+//	func main() {
+//		sub, _ := fs.Sub(profiles, "profiles")
+//		f1.New().WithProfiles(sub).Add("myTest", myScenario).Execute()
+//	}
+//
+// the profile "profiles/soak.yaml" can be run from the command line:
+//
+//	f1 run file embedded://soak
+func (f *F1) WithProfiles(profiles fs.FS) *F1 {
+	f.profiles = profiles
 	return f
 }
 
@@ -79,13 +108,13 @@ func (f *F1) Add(name string, scenarioFn testing.ScenarioFn, options ...scenario
 }
 
 // NewSignalContext returns a context.Context that is cancelled whenever
-// 'SIGINT' or 'SIGTERM' are received.
+// 'SIGINT' or 'SIGTERM' are received, on the targets which have signals.
 // If one of these two signals is received a second time, the application exits.
 func newSignalContext(stopCh <-chan struct{}) context.Context {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	c := make(chan os.Signal, signalChanBufferSize)
-	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	platform.Notify(c, platform.Current().InterruptSignals)
 
 	go func() {
 		select {
@@ -107,7 +136,19 @@ func newSignalContext(stopCh <-chan struct{}) context.Context {
 }
 
 func (f *F1) execute(args []string) error {
-	rootCmd, err := buildRootCmd(f.scenarios, f.settings, f.profiling, f.output)
+	cmdArgs := args
+	if len(cmdArgs) == 0 {
+		cmdArgs = os.Args[1:]
+	}
+	settings, err := envsettings.Load(settingsFile(cmdArgs))
+	if err != nil {
+		return fmt.Errorf("loading settings: %w", err)
+	}
+	if settings.File != "" && !f.customLogger {
+		f.output = ui.NewDefaultOutput(settings.Log.SlogLevel(), settings.Log.IsFormatJSON())
+	}
+
+	rootCmd, err := buildRootCmd(f.scenarios, settings, f.profiling, f.profiles, f.triggers, f.tracker, f.output)
 	if err != nil {
 		return fmt.Errorf("building root command: %w", err)
 	}
@@ -2,21 +2,27 @@ package f1_test
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"sync/atomic"
 	"syscall"
 	"testing"
+	"testing/fstest"
 	"time"
 
+	"github.com/spf13/pflag"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/goleak"
 
 	"github.com/form3tech-oss/f1/v2/internal/log"
 	"github.com/form3tech-oss/f1/v2/pkg/f1"
+	"github.com/form3tech-oss/f1/v2/pkg/f1/scenarios"
 	f1_testing "github.com/form3tech-oss/f1/v2/pkg/f1/testing"
+	"github.com/form3tech-oss/f1/v2/pkg/f1/trigger"
 )
 
 type f1Stage struct {
@@ -29,6 +35,15 @@ type f1Stage struct {
 	scenario   string
 	logOutput  bytes.Buffer
 	runCount   atomic.Uint32
+	campaign   string
+	reportFile string
+	// setupArgs and iterationArgs are the arguments the scenario was passed, see f1_testing.T.Args
+	setupArgs     []string
+	iterationArgs atomic.Pointer[[]string]
+	// setupEnvs are the values of an environment variable at the setup of every run of the scenario
+	setupEnvs []string
+	// settingsFile is the settings file passed with --config
+	settingsFile string
 }
 
 func newF1Stage(t *testing.T) (*f1Stage, *f1Stage, *f1Stage) {
@@ -84,6 +99,31 @@ func (s *f1Stage) a_scenario_where_each_iteration_takes(duration time.Duration)
 	return s
 }
 
+func (s *f1Stage) a_scenario_targeting(environment string) *f1Stage {
+	s.scenario = "scenario_targeting_" + environment
+	s.f1.Add(s.scenario, func(*f1_testing.T) f1_testing.RunFn {
+		return func(*f1_testing.T) {
+			s.runCount.Add(1)
+		}
+	}, scenarios.Annotation(scenarios.AnnotationEnvironment, environment))
+
+	return s
+}
+
+func (s *f1Stage) a_scenario_that_records_its_args() *f1Stage {
+	s.scenario = "scenario_that_records_its_args"
+	s.f1.Add(s.scenario, func(scenarioT *f1_testing.T) f1_testing.RunFn {
+		s.setupArgs = scenarioT.Args()
+
+		return func(iterationT *f1_testing.T) {
+			args := iterationT.Args()
+			s.iterationArgs.Store(&args)
+		}
+	})
+
+	return s
+}
+
 func (s *f1Stage) a_scenario_that_logs() *f1Stage {
 	s.scenario = "logging_scenario"
 	s.f1.Add(s.scenario, func(sceanrioT *f1_testing.T) f1_testing.RunFn {
@@ -107,6 +147,233 @@ func (s *f1Stage) the_f1_scenario_is_executed_with_constant_rate_and_args(args .
 	return s
 }
 
+func (s *f1Stage) an_embedded_profile_running_the_scenario(name string, rate string) *f1Stage {
+	s.f1.WithProfiles(fstest.MapFS{
+		name + ".yaml": &fstest.MapFile{Data: []byte(fmt.Sprintf(`
+scenario: %s
+limits:
+  max-duration: 1s
+  concurrency: 10
+  max-iterations: 100
+  ignore-dropped: true
+stages:
+- duration: 500ms
+  mode: constant
+  rate: %s
+  jitter: 0
+  distribution: none
+`, s.scenario, rate))},
+	})
+
+	return s
+}
+
+func (s *f1Stage) the_scenario_is_executed_with_constant_rate_and_args(args ...string) *f1Stage {
+	s.executeErr = s.f1.ExecuteWithArgs(append([]string{
+		"run", "constant", s.scenario,
+	}, args...))
+
+	return s
+}
+
+func (s *f1Stage) a_settings_file(name, content string) *f1Stage {
+	s.settingsFile = filepath.Join(s.t.TempDir(), name)
+	s.require.NoError(os.WriteFile(s.settingsFile, []byte(content), 0o600))
+
+	return s
+}
+
+func (s *f1Stage) the_scenario_is_executed_with_the_settings_file_and_args(args ...string) *f1Stage {
+	s.executeErr = s.f1.ExecuteWithArgs(append([]string{
+		"--config", s.settingsFile, "run", "constant", s.scenario,
+	}, args...))
+
+	return s
+}
+
+func (s *f1Stage) the_embedded_profile_is_executed(name string) *f1Stage {
+	s.executeErr = s.f1.ExecuteWithArgs([]string{
+		"run", "file", "embedded://" + name,
+	})
+
+	return s
+}
+
+func (s *f1Stage) a_custom_trigger_starting_iterations_every(name string, iterationDuration time.Duration) *f1Stage {
+	flags := pflag.NewFlagSet(name, pflag.ContinueOnError)
+	flags.Int("iterations", 1, "number of iterations to start")
+	flags.Duration("duration", time.Second, "duration of the run")
+
+	s.f1.WithTriggers(trigger.Builder{
+		Name:        name + " <scenario>",
+		Description: "starts a fixed number of iterations",
+		Flags:       flags,
+		New: func(flags *pflag.FlagSet) (*trigger.Trigger, error) {
+			iterations, err := flags.GetInt("iterations")
+			if err != nil {
+				return nil, err
+			}
+			duration, err := flags.GetDuration("duration")
+			if err != nil {
+				return nil, err
+			}
+
+			return &trigger.Trigger{
+				Rate:              func(time.Time) int { return iterations },
+				IterationDuration: iterationDuration,
+				Duration:          duration,
+				Description:       fmt.Sprintf("%d iterations every %s", iterations, iterationDuration),
+			}, nil
+		},
+	})
+
+	return s
+}
+
+func (s *f1Stage) the_custom_trigger_is_executed_with_args(name string, args ...string) *f1Stage {
+	s.executeErr = s.f1.ExecuteWithArgs(append([]string{
+		"run", name, s.scenario,
+	}, args...))
+
+	return s
+}
+
+func (s *f1Stage) a_campaign_where_run_n_misses_its_slo(runs int, failing int) *f1Stage {
+	var manifest strings.Builder
+	manifest.WriteString("runs:\n")
+	for i := range runs {
+		fmt.Fprintf(&manifest, `- name: run-%d
+  args: [constant, %s, --rate, 5/100ms, --max-duration, 200ms, --distribution, none]
+`, i, s.scenario)
+		if i == failing {
+			manifest.WriteString("  slo:\n    max-p95: 1ns\n")
+		}
+	}
+
+	dir := s.t.TempDir()
+	s.campaign = filepath.Join(dir, "campaign.yaml")
+	s.reportFile = filepath.Join(dir, "campaign.json")
+	s.require.NoError(os.WriteFile(s.campaign, []byte(manifest.String()), 0o600))
+
+	return s
+}
+
+func (s *f1Stage) the_campaign_is_executed() *f1Stage {
+	s.executeErr = s.f1.ExecuteWithArgs([]string{
+		"campaign", s.campaign, "--report-file", s.reportFile,
+	})
+
+	return s
+}
+
+func (s *f1Stage) the_campaign_is_executed_with_a_report_dir(reportDir string) *f1Stage {
+	s.executeErr = s.f1.ExecuteWithArgs([]string{
+		"campaign", s.campaign, "--report-file", s.reportFile, "--report-dir", reportDir,
+	})
+
+	return s
+}
+
+func (s *f1Stage) the_campaign_runs_an_unknown_scenario() *f1Stage {
+	s.require.NoError(os.WriteFile(s.campaign, []byte(`runs:
+- name: run-0
+  args: [constant, unknown_scenario, --rate, 5/100ms, --max-duration, 200ms]
+`), 0o600))
+
+	return s
+}
+
+func (s *f1Stage) expect_the_campaign_runs_to_have_status(statuses ...string) *f1Stage {
+	data, err := os.ReadFile(s.reportFile)
+	s.require.NoError(err)
+
+	report := struct {
+		Runs []struct {
+			Status string `json:"status"`
+		} `json:"runs"`
+	}{}
+	s.require.NoError(json.Unmarshal(data, &report))
+
+	actual := make([]string, 0, len(report.Runs))
+	for _, r := range report.Runs {
+		actual = append(actual, r.Status)
+	}
+	s.assert.Equal(statuses, actual)
+
+	return s
+}
+
+func (s *f1Stage) a_scenario_recording_the_env_variable(name string) *f1Stage {
+	s.scenario = "scenario_recording_" + name
+	s.f1.Add(s.scenario, func(*f1_testing.T) f1_testing.RunFn {
+		s.setupEnvs = append(s.setupEnvs, os.Getenv(name))
+		return func(*f1_testing.T) {
+			s.runCount.Add(1)
+		}
+	})
+
+	return s
+}
+
+func (s *f1Stage) the_sweep_is_executed(sweepArgs []string, triggerName string, runArgs ...string) *f1Stage {
+	s.reportFile = filepath.Join(s.t.TempDir(), "sweep.json")
+	args := append([]string{"sweep", "--report-file", s.reportFile}, sweepArgs...)
+	args = append(args, "--", triggerName, s.scenario)
+	s.executeErr = s.f1.ExecuteWithArgs(append(args, runArgs...))
+
+	return s
+}
+
+func (s *f1Stage) expect_the_sweep_runs_to_be(names ...string) *f1Stage {
+	data, err := os.ReadFile(s.reportFile)
+	s.require.NoError(err)
+
+	report := struct {
+		Runs []struct {
+			Name   string `json:"name"`
+			Report struct {
+				IterationsStarted uint64 `json:"iterations_started"`
+			} `json:"report"`
+		} `json:"runs"`
+	}{}
+	s.require.NoError(json.Unmarshal(data, &report))
+
+	actual := make([]string, 0, len(report.Runs))
+	for _, r := range report.Runs {
+		actual = append(actual, r.Name)
+		s.assert.Positive(r.Report.IterationsStarted, r.Name)
+	}
+	s.assert.Equal(names, actual)
+
+	return s
+}
+
+func (s *f1Stage) expect_the_scenario_to_have_been_set_up_with_the_env_values(values ...string) *f1Stage {
+	s.assert.Equal(values, s.setupEnvs)
+
+	return s
+}
+
+func (s *f1Stage) expect_the_scenario_to_have_been_passed_the_args(args ...string) *f1Stage {
+	s.assert.Equal(args, s.setupArgs)
+	s.require.NotNil(s.iterationArgs.Load())
+	s.assert.Equal(args, *s.iterationArgs.Load())
+
+	return s
+}
+
+func (s *f1Stage) the_execute_command_succeeds() *f1Stage {
+	s.require.NoError(s.executeErr)
+
+	return s
+}
+
+func (s *f1Stage) expect_the_scenario_iterations_to_have_run(count uint32) *f1Stage {
+	s.assert.Equal(count, s.runCount.Load())
+
+	return s
+}
+
 func (s *f1Stage) an_unknown_f1_scenario_is_executed() *f1Stage {
 	s.executeErr = s.f1.ExecuteWithArgs([]string{
 		"run", "constant", "unknownScenario",
@@ -62,3 +62,217 @@ func TestWithCustomLogger(t *testing.T) {
 	then.
 		expect_all_log_lines_to_contain_attr("custom", "value")
 }
+
+func TestRunCustomTrigger(t *testing.T) {
+	given, when, then := newF1Stage(t)
+
+	given.
+		a_scenario_where_each_iteration_takes(time.Millisecond).and().
+		a_custom_trigger_starting_iterations_every("fixed", 100*time.Millisecond)
+
+	when.
+		the_custom_trigger_is_executed_with_args("fixed",
+			"--iterations", "5",
+			"--duration", "500ms",
+			"--max-duration", "1s",
+		)
+
+	then.
+		the_execute_command_succeeds().and().
+		expect_the_scenario_iterations_to_have_run(25)
+}
+
+func TestScenarioArgsArePassedAfterDash(t *testing.T) {
+	given, when, then := newF1Stage(t)
+
+	given.
+		a_scenario_that_records_its_args()
+
+	when.
+		the_f1_scenario_is_executed_with_constant_rate_and_args(
+			"--rate", "1/s",
+			"--max-iterations", "1",
+			"--",
+			"--custom-flag=value", "--rate", "5",
+		)
+
+	then.
+		expect_the_scenario_to_have_been_passed_the_args("--custom-flag=value", "--rate", "5")
+}
+
+func TestRunEmbeddedProfile(t *testing.T) {
+	given, when, then := newF1Stage(t)
+
+	given.
+		a_scenario_where_each_iteration_takes(time.Millisecond).and().
+		an_embedded_profile_running_the_scenario("soak-profile", "5/100ms")
+
+	when.
+		the_embedded_profile_is_executed("soak-profile")
+
+	then.
+		the_execute_command_succeeds().and().
+		expect_the_scenario_iterations_to_have_run(25)
+}
+
+func TestRunMissingEmbeddedProfile(t *testing.T) {
+	given, when, then := newF1Stage(t)
+
+	given.
+		a_scenario_where_each_iteration_takes(time.Millisecond).and().
+		an_embedded_profile_running_the_scenario("soak-profile", "5/100ms")
+
+	when.
+		the_embedded_profile_is_executed("spike-profile")
+
+	then.
+		the_execute_command_returns_an_error("reading embedded profile spike-profile: file does not exist")
+}
+
+func TestCampaignStopsAfterMissedSLO(t *testing.T) {
+	given, when, then := newF1Stage(t)
+
+	given.
+		a_scenario_where_each_iteration_takes(time.Millisecond).and().
+		a_campaign_where_run_n_misses_its_slo(3, 1)
+
+	when.
+		the_campaign_is_executed()
+
+	then.
+		the_execute_command_returns_an_error("campaign failed").and().
+		expect_the_campaign_runs_to_have_status("passed", "failed", "skipped").and().
+		expect_the_scenario_iterations_to_have_run(20)
+}
+
+func TestCampaignPasses(t *testing.T) {
+	given, when, then := newF1Stage(t)
+
+	given.
+		a_scenario_where_each_iteration_takes(time.Millisecond).and().
+		a_campaign_where_run_n_misses_its_slo(2, -1)
+
+	when.
+		the_campaign_is_executed()
+
+	then.
+		the_execute_command_succeeds().and().
+		expect_the_campaign_runs_to_have_status("passed", "passed")
+}
+
+func TestCampaignDoesNotReadTheReportsOfAPreviousCampaign(t *testing.T) {
+	given, when, then := newF1Stage(t)
+
+	reportDir := t.TempDir()
+	given.
+		a_scenario_where_each_iteration_takes(time.Millisecond).and().
+		a_campaign_where_run_n_misses_its_slo(1, -1).and().
+		the_campaign_is_executed_with_a_report_dir(reportDir).and().
+		the_execute_command_succeeds().and().
+		the_campaign_runs_an_unknown_scenario()
+
+	when.
+		the_campaign_is_executed_with_a_report_dir(reportDir)
+
+	then.
+		the_execute_command_returns_an_error("run 'run-0' did not complete")
+}
+
+func TestSweepRunsEveryCombinationOfTheParameters(t *testing.T) {
+	given, when, then := newF1Stage(t)
+
+	given.
+		a_scenario_recording_the_env_variable("F1_SWEEP_PAYLOAD_SIZE")
+
+	when.
+		the_sweep_is_executed([]string{
+			"--vary-flag", "rate=5/100ms,10/100ms",
+			"--vary-env", "F1_SWEEP_PAYLOAD_SIZE=1024,65536",
+			"--run-duration", "200ms",
+		}, "constant", "--distribution", "none")
+
+	then.
+		the_execute_command_succeeds().and().
+		expect_the_sweep_runs_to_be(
+			"rate=5/100ms F1_SWEEP_PAYLOAD_SIZE=1024",
+			"rate=5/100ms F1_SWEEP_PAYLOAD_SIZE=65536",
+			"rate=10/100ms F1_SWEEP_PAYLOAD_SIZE=1024",
+			"rate=10/100ms F1_SWEEP_PAYLOAD_SIZE=65536",
+		).and().
+		expect_the_scenario_to_have_been_set_up_with_the_env_values("1024", "65536", "1024", "65536").and().
+		expect_the_scenario_iterations_to_have_run(60)
+}
+
+func TestProductionRunIsNotStartedWithoutConfirmation(t *testing.T) {
+	given, when, then := newF1Stage(t)
+
+	given.
+		a_scenario_targeting("production")
+
+	when.
+		the_scenario_is_executed_with_constant_rate_and_args("--rate", "5/100ms", "--max-duration", "500ms")
+
+	then.
+		the_execute_command_returns_an_error("the run was not confirmed: confirm it with --yes").and().
+		expect_the_scenario_iterations_to_have_run(0)
+}
+
+func TestProductionRunIsStartedWhenConfirmedWithYes(t *testing.T) {
+	given, when, then := newF1Stage(t)
+
+	given.
+		a_scenario_targeting("production")
+
+	when.
+		the_scenario_is_executed_with_constant_rate_and_args("--rate", "5/100ms", "--max-duration", "500ms",
+			"--distribution", "none", "--yes")
+
+	then.
+		the_execute_command_succeeds().and().
+		expect_the_scenario_iterations_to_have_run(25)
+}
+
+func TestRunAbovePeakRateIsNotStartedWithoutConfirmation(t *testing.T) {
+	given, when, then := newF1Stage(t)
+
+	given.
+		a_scenario_targeting("staging")
+
+	when.
+		the_scenario_is_executed_with_constant_rate_and_args("--rate", "5/100ms", "--max-duration", "500ms",
+			"--confirm-above", "40/s")
+
+	then.
+		the_execute_command_returns_an_error("the run was not confirmed").and().
+		expect_the_scenario_iterations_to_have_run(0)
+}
+
+func TestRunAbovePeakRateOfTheSettingsFileIsNotStartedWithoutConfirmation(t *testing.T) {
+	given, when, then := newF1Stage(t)
+
+	given.
+		a_scenario_targeting("staging").and().
+		a_settings_file("f1.toml", "[confirm]\nabove-rate = \"40/s\"\n")
+
+	when.
+		the_scenario_is_executed_with_the_settings_file_and_args("--rate", "5/100ms", "--max-duration", "500ms")
+
+	then.
+		the_execute_command_returns_an_error("the run was not confirmed").and().
+		expect_the_scenario_iterations_to_have_run(0)
+}
+
+func TestRunBelowPeakRateIsStartedWithoutConfirmation(t *testing.T) {
+	given, when, then := newF1Stage(t)
+
+	given.
+		a_scenario_targeting("staging")
+
+	when.
+		the_scenario_is_executed_with_constant_rate_and_args("--rate", "5/100ms", "--max-duration", "500ms",
+			"--distribution", "none", "--confirm-above", "60/s")
+
+	then.
+		the_execute_command_succeeds().and().
+		expect_the_scenario_iterations_to_have_run(25)
+}
@@ -0,0 +1,95 @@
+// Package f1auth provides helpers for scenarios authenticating against the system under test.
+package f1auth
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// Fresh is a value refreshed in the background, such as an OAuth access token.
+type Fresh[V any] struct {
+	value atomic.Pointer[V]
+	err   atomic.Pointer[error]
+	done  chan struct{}
+}
+
+// KeepFresh calls refreshFn for an initial value, then keeps calling it every interval in the
+// background until ctx is cancelled. Scenarios should pass the context of their setup t, so that
+// the refresher is stopped when the scenario completes, before its teardown:
+//
+//	func scenario(t *testing.T) testing.RunFn {
+//		token, err := f1auth.KeepFresh(t.Context(), fetchToken, 5*time.Minute)
+//		t.Require().NoError(err)
+//
+//		return func(t *testing.T) {
+//			req.Header.Set("Authorization", "Bearer "+token.Get())
+//		}
+//	}
+//
+// It returns an error if the initial value can't be fetched. When a later refresh fails, the
+// previous value is kept and the error is available from Err until the next successful refresh.
+func KeepFresh[V any](
+	ctx context.Context,
+	refreshFn func(context.Context) (V, error),
+	interval time.Duration,
+) (*Fresh[V], error) {
+	value, err := refreshFn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("initial refresh: %w", err)
+	}
+
+	f := &Fresh[V]{done: make(chan struct{})}
+	f.value.Store(&value)
+
+	go f.refresh(ctx, refreshFn, interval)
+
+	return f, nil
+}
+
+// Get returns the latest value.
+func (f *Fresh[V]) Get() V {
+	return *f.value.Load()
+}
+
+// Err returns the error of the latest refresh, or nil if it succeeded.
+func (f *Fresh[V]) Err() error {
+	if err := f.err.Load(); err != nil {
+		return *err
+	}
+
+	return nil
+}
+
+// Done returns a channel closed once the refresher has stopped.
+func (f *Fresh[V]) Done() <-chan struct{} {
+	return f.done
+}
+
+func (f *Fresh[V]) refresh(ctx context.Context, refreshFn func(context.Context) (V, error), interval time.Duration) {
+	defer close(f.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		value, err := refreshFn(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			f.err.Store(&err)
+			continue
+		}
+
+		f.value.Store(&value)
+		f.err.Store(nil)
+	}
+}
@@ -0,0 +1,93 @@
+package f1auth_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/pkg/f1/f1auth"
+	f1_testing "github.com/form3tech-oss/f1/v2/pkg/f1/testing"
+)
+
+var errUnavailable = errors.New("token endpoint unavailable")
+
+func TestKeepFreshRefreshesValue(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	refresh := func(context.Context) (string, error) {
+		return "token-" + strconv.Itoa(int(calls.Add(1))), nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	token, err := f1auth.KeepFresh(ctx, refresh, 10*time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", token.Get())
+
+	assert.Eventually(t, func() bool {
+		return token.Get() != "token-1"
+	}, time.Second, time.Millisecond)
+	require.NoError(t, token.Err())
+}
+
+func TestKeepFreshFailsOnInitialRefresh(t *testing.T) {
+	t.Parallel()
+
+	_, err := f1auth.KeepFresh(context.Background(), func(context.Context) (string, error) {
+		return "", errUnavailable
+	}, time.Minute)
+
+	require.ErrorIs(t, err, errUnavailable)
+}
+
+func TestKeepFreshKeepsPreviousValueOnError(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	refresh := func(context.Context) (string, error) {
+		if calls.Add(1) > 1 {
+			return "", errUnavailable
+		}
+		return "token-1", nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	token, err := f1auth.KeepFresh(ctx, refresh, 10*time.Millisecond)
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		return errors.Is(token.Err(), errUnavailable)
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, "token-1", token.Get())
+}
+
+func TestKeepFreshStopsBeforeScenarioTeardown(t *testing.T) {
+	t.Parallel()
+
+	scenarioT, teardown := f1_testing.NewT("setup", "scenario")
+
+	token, err := f1auth.KeepFresh(scenarioT.Context(), func(context.Context) (string, error) {
+		return "token", nil
+	}, time.Millisecond)
+	require.NoError(t, err)
+
+	stopped := false
+	scenarioT.Cleanup(func() {
+		<-token.Done()
+		stopped = true
+	})
+
+	teardown()
+
+	require.True(t, stopped)
+}
@@ -0,0 +1,180 @@
+package f1secrets
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AWSSecretsManagerOptions configures the AWS Secrets Manager provider.
+type AWSSecretsManagerOptions struct {
+	// Client sends the requests to Secrets Manager, http.DefaultClient by default
+	Client *http.Client
+	// Region is the region of the secrets, AWS_REGION or AWS_DEFAULT_REGION by default
+	Region string
+	// Endpoint overrides the endpoint of Secrets Manager in the region, such as a VPC endpoint
+	Endpoint string
+	// AccessKeyID, SecretAccessKey and SessionToken are the credentials signing the requests,
+	// AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN by default
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+const awsSecretsManagerService = "secretsmanager"
+
+// AWSSecretsManager provides the string secrets of AWS Secrets Manager, by their name or ARN.
+// Secrets holding a json object are read by key, such as "payments/db#password".
+func AWSSecretsManager(options AWSSecretsManagerOptions) Provider {
+	if options.Client == nil {
+		options.Client = http.DefaultClient
+	}
+	if options.Region == "" {
+		options.Region = cmpEnv("AWS_REGION", "AWS_DEFAULT_REGION")
+	}
+	if options.Endpoint == "" {
+		options.Endpoint = fmt.Sprintf("https://%s.%s.amazonaws.com/", awsSecretsManagerService, options.Region)
+	}
+	if options.AccessKeyID == "" {
+		options.AccessKeyID = os.Getenv("AWS_ACCESS_KEY_ID")
+		options.SecretAccessKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+		options.SessionToken = os.Getenv("AWS_SESSION_TOKEN")
+	}
+
+	return ProviderFunc(func(ctx context.Context, name string) (Secret, error) {
+		return options.secret(ctx, name)
+	})
+}
+
+// cmpEnv returns the value of the first of the environment variables which is set.
+func cmpEnv(names ...string) string {
+	for _, name := range names {
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
+
+func (o AWSSecretsManagerOptions) secret(ctx context.Context, name string) (Secret, error) {
+	id, key := splitKey(name)
+
+	body, err := json.Marshal(map[string]string{"SecretId": id})
+	if err != nil {
+		return Secret{}, fmt.Errorf("aws secret %s: %w", id, err)
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, o.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return Secret{}, fmt.Errorf("aws secret %s: %w", id, err)
+	}
+	request.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	request.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	if o.SessionToken != "" {
+		request.Header.Set("X-Amz-Security-Token", o.SessionToken)
+	}
+	o.sign(request, body, time.Now())
+
+	response, err := o.Client.Do(request)
+	if err != nil {
+		return Secret{}, fmt.Errorf("fetching aws secret %s: %w", id, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		failure := struct {
+			Type string `json:"__type"`
+		}{}
+		_ = json.NewDecoder(response.Body).Decode(&failure)
+		if strings.HasSuffix(failure.Type, "ResourceNotFoundException") {
+			return Secret{}, fmt.Errorf("aws secret %s: %w", id, ErrNotFound)
+		}
+		return Secret{}, fmt.Errorf("fetching aws secret %s: %s %s", id, response.Status, failure.Type)
+	}
+
+	value := struct {
+		SecretString *string `json:"SecretString"`
+	}{}
+	if err := json.NewDecoder(response.Body).Decode(&value); err != nil {
+		return Secret{}, fmt.Errorf("decoding aws secret %s: %w", id, err)
+	}
+	if value.SecretString == nil {
+		return Secret{}, fmt.Errorf("aws secret %s is not a string secret", id)
+	}
+	if key == "" {
+		return NewSecret(*value.SecretString), nil
+	}
+
+	values := map[string]any{}
+	if err := json.Unmarshal([]byte(*value.SecretString), &values); err != nil {
+		return Secret{}, fmt.Errorf("aws secret %s is not a json object, reading key %s", id, key)
+	}
+	text, ok := values[key].(string)
+	if !ok {
+		return Secret{}, fmt.Errorf("key %s of aws secret %s: %w", key, id, ErrNotFound)
+	}
+
+	return NewSecret(text), nil
+}
+
+// sign signs the request with the AWS signature version 4, from its host and headers.
+func (o AWSSecretsManagerOptions) sign(request *http.Request, body []byte, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	scope := strings.Join([]string{now.Format("20060102"), o.Region, awsSecretsManagerService, "aws4_request"}, "/")
+	request.Header.Set("X-Amz-Date", amzDate)
+
+	headers := map[string]string{"host": request.URL.Host}
+	for name, values := range request.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := request.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		request.Method, path, request.URL.RawQuery, canonicalHeaders.String(), signedHeaders, sha256Hex(body),
+	}, "\n")
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	key := []byte("AWS4" + o.SecretAccessKey)
+	for _, part := range strings.Split(scope, "/") {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	request.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		o.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
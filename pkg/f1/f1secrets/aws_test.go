@@ -0,0 +1,92 @@
+package f1secrets_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/pkg/f1/f1secrets"
+)
+
+func TestAWSSecretsManager(t *testing.T) {
+	t.Parallel()
+
+	secrets := map[string]string{
+		"api-token":   "token",
+		"payments/db": `{"user": "payments", "password": "hunter2"}`,
+	}
+	authorization := regexp.MustCompile(`^AWS4-HMAC-SHA256 ` +
+		`Credential=AKIDEXAMPLE/\d{8}/eu-west-1/secretsmanager/aws4_request, ` +
+		`SignedHeaders=content-type;host;x-amz-date;x-amz-security-token;x-amz-target, ` +
+		`Signature=[0-9a-f]{64}$`)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !authorization.MatchString(r.Header.Get("Authorization")) ||
+			r.Header.Get("X-Amz-Security-Token") != "session-token" ||
+			r.Header.Get("X-Amz-Target") != "secretsmanager.GetSecretValue" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		request := struct{ SecretId string }{}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		value, ok := secrets[request.SecretId]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"__type": "ResourceNotFoundException"}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"SecretString": value})
+	}))
+	t.Cleanup(server.Close)
+
+	provider := f1secrets.AWSSecretsManager(f1secrets.AWSSecretsManagerOptions{
+		Region:          "eu-west-1",
+		Endpoint:        server.URL,
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		SessionToken:    "session-token",
+	})
+
+	for _, test := range []struct {
+		name          string
+		secret        string
+		expected      string
+		expectedError string
+		notFound      bool
+	}{
+		{name: "string secret", secret: "api-token", expected: "token"},
+		{name: "key of a json secret", secret: "payments/db#password", expected: "hunter2"},
+		{name: "unknown secret", secret: "payments/queue", notFound: true},
+		{name: "unknown key", secret: "payments/db#port", notFound: true},
+		{
+			name:          "key of a string secret",
+			secret:        "api-token#value",
+			expectedError: "aws secret api-token is not a json object, reading key value",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			secret, err := provider.Secret(context.Background(), test.secret)
+			switch {
+			case test.notFound:
+				require.ErrorIs(t, err, f1secrets.ErrNotFound)
+			case test.expectedError != "":
+				require.EqualError(t, err, test.expectedError)
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, test.expected, secret.Reveal())
+			}
+		})
+	}
+}
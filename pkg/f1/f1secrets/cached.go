@@ -0,0 +1,83 @@
+package f1secrets
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Cached caches the secrets of provider for ttl, so that iterations under load don't fetch a secret
+// from its store every time, while secrets rotated in the store are picked up within ttl. A secret
+// is fetched once at a time, by the first iteration to miss it, while the others wait for it. A
+// secret which can't be fetched again once it expired is not cached, and the error is returned.
+func Cached(provider Provider, ttl time.Duration) Provider {
+	return &cache{
+		provider: provider,
+		ttl:      ttl,
+		secrets:  map[string]cachedSecret{},
+		fetches:  map[string]*secretFetch{},
+	}
+}
+
+type cache struct {
+	provider Provider
+	secrets  map[string]cachedSecret
+	// fetches are the secrets being fetched, which iterations missing them wait for
+	fetches map[string]*secretFetch
+	ttl     time.Duration
+	mu      sync.Mutex
+}
+
+type cachedSecret struct {
+	expires time.Time
+	secret  Secret
+}
+
+// secretFetch is a secret being fetched, with its result once done is closed.
+type secretFetch struct {
+	done   chan struct{}
+	secret Secret
+	err    error
+}
+
+func (c *cache) Secret(ctx context.Context, name string) (Secret, error) {
+	c.mu.Lock()
+	if cached, ok := c.secrets[name]; ok && time.Now().Before(cached.expires) {
+		c.mu.Unlock()
+		return cached.secret, nil
+	}
+	fetch, fetching := c.fetches[name]
+	if !fetching {
+		fetch = &secretFetch{done: make(chan struct{})}
+		c.fetches[name] = fetch
+	}
+	c.mu.Unlock()
+
+	if !fetching {
+		c.fetch(ctx, name, fetch)
+	}
+
+	select {
+	case <-fetch.done:
+		return fetch.secret, fetch.err
+	case <-ctx.Done():
+		return Secret{}, fmt.Errorf("waiting for secret %s: %w", name, ctx.Err())
+	}
+}
+
+// fetch fetches the secret from the provider, caching it unless it fails.
+func (c *cache) fetch(ctx context.Context, name string, fetch *secretFetch) {
+	fetch.secret, fetch.err = c.provider.Secret(ctx, name)
+
+	c.mu.Lock()
+	if fetch.err != nil {
+		delete(c.secrets, name)
+	} else {
+		c.secrets[name] = cachedSecret{secret: fetch.secret, expires: time.Now().Add(c.ttl)}
+	}
+	delete(c.fetches, name)
+	c.mu.Unlock()
+
+	close(fetch.done)
+}
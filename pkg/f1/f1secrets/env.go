@@ -0,0 +1,20 @@
+package f1secrets
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// Env provides the secrets set as environment variables, named after the secrets with the prefix,
+// such as PAYMENTS_DB_PASSWORD for the secret DB_PASSWORD with the prefix PAYMENTS_.
+func Env(prefix string) Provider {
+	return ProviderFunc(func(_ context.Context, name string) (Secret, error) {
+		value, ok := os.LookupEnv(prefix + name)
+		if !ok {
+			return Secret{}, fmt.Errorf("environment variable %s%s: %w", prefix, name, ErrNotFound)
+		}
+
+		return NewSecret(value), nil
+	})
+}
@@ -0,0 +1,32 @@
+package f1secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Files provides the secrets written to the files of dir, named after the secrets, such as the
+// secrets mounted as volumes in Kubernetes. A trailing newline of a file is not part of the secret.
+func Files(dir string) Provider {
+	return ProviderFunc(func(_ context.Context, name string) (Secret, error) {
+		if !filepath.IsLocal(name) {
+			return Secret{}, fmt.Errorf("secret %s is not a file of %s", name, dir)
+		}
+
+		path := filepath.Join(dir, name)
+		data, err := os.ReadFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			return Secret{}, fmt.Errorf("secret file %s: %w", path, ErrNotFound)
+		}
+		if err != nil {
+			return Secret{}, fmt.Errorf("reading secret file %s: %w", path, err)
+		}
+
+		return NewSecret(strings.TrimSuffix(strings.TrimSuffix(string(data), "\n"), "\r")), nil
+	})
+}
@@ -0,0 +1,78 @@
+// Package f1secrets provides the credentials scenarios use under load from secret providers, such
+// as environment variables, files, Vault or AWS Secrets Manager, as secrets which are never logged.
+package f1secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// ErrNotFound is returned by providers which have no secret with the requested name.
+var ErrNotFound = errors.New("secret not found")
+
+const redacted = "[REDACTED]"
+
+// Secret is the value of a secret. It is redacted when it is formatted, logged or marshalled, so
+// that credentials used by a scenario can't leak to its logs or reports; Reveal returns the value.
+type Secret struct {
+	value string
+}
+
+var (
+	_ fmt.Formatter  = Secret{}
+	_ slog.LogValuer = Secret{}
+	_ json.Marshaler = Secret{}
+)
+
+// NewSecret returns a secret with the given value.
+func NewSecret(value string) Secret {
+	return Secret{value: value}
+}
+
+// Reveal returns the value of the secret.
+func (s Secret) Reveal() string {
+	return s.value
+}
+
+func (s Secret) String() string {
+	return redacted
+}
+
+// Format redacts the secret whatever the verb it is formatted with.
+func (s Secret) Format(f fmt.State, _ rune) {
+	_, _ = io.WriteString(f, redacted)
+}
+
+func (s Secret) LogValue() slog.Value {
+	return slog.StringValue(redacted)
+}
+
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return json.Marshal(redacted)
+}
+
+// Provider fetches secrets by name.
+type Provider interface {
+	Secret(ctx context.Context, name string) (Secret, error)
+}
+
+// ProviderFunc is a function providing secrets, such as a client of a secret store not provided by
+// f1secrets.
+type ProviderFunc func(ctx context.Context, name string) (Secret, error)
+
+func (f ProviderFunc) Secret(ctx context.Context, name string) (Secret, error) {
+	return f(ctx, name)
+}
+
+// splitKey splits the name of a secret of a store holding several values in a secret, such as
+// "payments/db#password", into the secret and the key of the value, which is empty if the name
+// has no key.
+func splitKey(name string) (string, string) {
+	secret, key, _ := strings.Cut(name, "#")
+	return secret, key
+}
@@ -0,0 +1,138 @@
+package f1secrets_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/pkg/f1/f1secrets"
+)
+
+func TestSecretIsRedacted(t *testing.T) {
+	t.Parallel()
+
+	secret := f1secrets.NewSecret("hunter2")
+
+	for _, format := range []string{"%s", "%v", "%+v", "%#v", "%q", "%x", "%d"} {
+		assert.Equal(t, "[REDACTED]", fmt.Sprintf(format, secret), format)
+	}
+	assert.Equal(t, "[REDACTED]", secret.String())
+
+	data, err := json.Marshal(struct{ Password f1secrets.Secret }{secret})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"Password": "[REDACTED]"}`, string(data))
+
+	var logs bytes.Buffer
+	slog.New(slog.NewTextHandler(&logs, nil)).Info("authenticating", "password", secret)
+	assert.NotContains(t, logs.String(), "hunter2")
+	assert.Contains(t, logs.String(), "password=[REDACTED]")
+
+	assert.Equal(t, "hunter2", secret.Reveal())
+}
+
+func TestEnv(t *testing.T) {
+	t.Setenv("F1SECRETS_TEST_API_TOKEN", "token")
+
+	secret, err := f1secrets.Env("F1SECRETS_TEST_").Secret(context.Background(), "API_TOKEN")
+	require.NoError(t, err)
+	assert.Equal(t, "token", secret.Reveal())
+
+	_, err = f1secrets.Env("F1SECRETS_TEST_").Secret(context.Background(), "MISSING")
+	require.ErrorIs(t, err, f1secrets.ErrNotFound)
+}
+
+func TestFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "api-token"), []byte("token\n"), 0o600))
+	provider := f1secrets.Files(dir)
+
+	secret, err := provider.Secret(context.Background(), "api-token")
+	require.NoError(t, err)
+	assert.Equal(t, "token", secret.Reveal())
+
+	_, err = provider.Secret(context.Background(), "missing")
+	require.ErrorIs(t, err, f1secrets.ErrNotFound)
+
+	_, err = provider.Secret(context.Background(), "../api-token")
+	require.ErrorContains(t, err, "secret ../api-token is not a file of "+dir)
+}
+
+func TestCachedSecretsAreFetchedAgainOnceExpired(t *testing.T) {
+	t.Parallel()
+
+	var fetches atomic.Int32
+	provider := f1secrets.Cached(f1secrets.ProviderFunc(func(context.Context, string) (f1secrets.Secret, error) {
+		return f1secrets.NewSecret(fmt.Sprint("token-", fetches.Add(1))), nil
+	}), 50*time.Millisecond)
+
+	for range 3 {
+		secret, err := provider.Secret(context.Background(), "api-token")
+		require.NoError(t, err)
+		assert.Equal(t, "token-1", secret.Reveal())
+	}
+
+	assert.Eventually(t, func() bool {
+		secret, err := provider.Secret(context.Background(), "api-token")
+		return err == nil && secret.Reveal() == "token-2"
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestCachedSecretsAreFetchedOnceByConcurrentMisses(t *testing.T) {
+	t.Parallel()
+
+	var fetches atomic.Int32
+	release := make(chan struct{})
+	provider := f1secrets.Cached(f1secrets.ProviderFunc(func(context.Context, string) (f1secrets.Secret, error) {
+		fetches.Add(1)
+		<-release
+		return f1secrets.NewSecret("token"), nil
+	}), time.Minute)
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			secret, err := provider.Secret(context.Background(), "api-token")
+			assert.NoError(t, err)
+			assert.Equal(t, "token", secret.Reveal())
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), fetches.Load())
+}
+
+func TestCachedSecretsAreNotCachedOnError(t *testing.T) {
+	t.Parallel()
+
+	var fetches atomic.Int32
+	provider := f1secrets.Cached(f1secrets.ProviderFunc(func(context.Context, string) (f1secrets.Secret, error) {
+		if fetches.Add(1) == 1 {
+			return f1secrets.Secret{}, f1secrets.ErrNotFound
+		}
+		return f1secrets.NewSecret("token"), nil
+	}), time.Minute)
+
+	_, err := provider.Secret(context.Background(), "api-token")
+	require.ErrorIs(t, err, f1secrets.ErrNotFound)
+
+	secret, err := provider.Secret(context.Background(), "api-token")
+	require.NoError(t, err)
+	assert.Equal(t, "token", secret.Reveal())
+}
@@ -0,0 +1,97 @@
+package f1secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// VaultOptions configures the Vault provider.
+type VaultOptions struct {
+	// Client sends the requests to Vault, http.DefaultClient by default
+	Client *http.Client
+	// Address is the address of Vault, VAULT_ADDR by default
+	Address string
+	// Token authenticates the requests to Vault, VAULT_TOKEN by default
+	Token string
+	// Mount is the path the KV secrets engine is mounted at, "secret" by default
+	Mount string
+}
+
+// defaultVaultKey is the key of the value of secrets named without a key.
+const defaultVaultKey = "value"
+
+// Vault provides the secrets of a version 2 KV secrets engine of HashiCorp Vault. Secrets are
+// named after their path and the key of their value, such as "payments/db#password", and the
+// key defaults to "value".
+func Vault(options VaultOptions) Provider {
+	if options.Client == nil {
+		options.Client = http.DefaultClient
+	}
+	if options.Address == "" {
+		options.Address = os.Getenv("VAULT_ADDR")
+	}
+	if options.Token == "" {
+		options.Token = os.Getenv("VAULT_TOKEN")
+	}
+	if options.Mount == "" {
+		options.Mount = "secret"
+	}
+
+	return ProviderFunc(func(ctx context.Context, name string) (Secret, error) {
+		return options.secret(ctx, name)
+	})
+}
+
+func (o VaultOptions) secret(ctx context.Context, name string) (Secret, error) {
+	path, key := splitKey(name)
+	if key == "" {
+		key = defaultVaultKey
+	}
+
+	address, err := url.JoinPath(o.Address, "v1", o.Mount, "data", path)
+	if err != nil {
+		return Secret{}, fmt.Errorf("vault secret %s: %w", path, err)
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, address, nil)
+	if err != nil {
+		return Secret{}, fmt.Errorf("vault secret %s: %w", path, err)
+	}
+	request.Header.Set("X-Vault-Token", o.Token)
+
+	response, err := o.Client.Do(request)
+	if err != nil {
+		return Secret{}, fmt.Errorf("fetching vault secret %s: %w", path, err)
+	}
+	defer response.Body.Close()
+
+	switch {
+	case response.StatusCode == http.StatusNotFound:
+		return Secret{}, fmt.Errorf("vault secret %s: %w", path, ErrNotFound)
+	case response.StatusCode != http.StatusOK:
+		return Secret{}, fmt.Errorf("fetching vault secret %s: %s", path, response.Status)
+	}
+
+	body := struct {
+		Data struct {
+			Data map[string]any `json:"data"`
+		} `json:"data"`
+	}{}
+	if err := json.NewDecoder(response.Body).Decode(&body); err != nil {
+		return Secret{}, fmt.Errorf("decoding vault secret %s: %w", path, err)
+	}
+
+	value, ok := body.Data.Data[key]
+	if !ok {
+		return Secret{}, fmt.Errorf("key %s of vault secret %s: %w", key, path, ErrNotFound)
+	}
+	text, ok := value.(string)
+	if !ok {
+		return Secret{}, fmt.Errorf("key %s of vault secret %s is not a string", key, path)
+	}
+
+	return NewSecret(text), nil
+}
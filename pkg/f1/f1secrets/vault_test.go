@@ -0,0 +1,75 @@
+package f1secrets_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/pkg/f1/f1secrets"
+)
+
+func TestVault(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "vault-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.URL.Path != "/v1/kv/data/payments/db" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data": {"data": {"value": "default", "password": "hunter2", "port": 5432}}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	provider := f1secrets.Vault(f1secrets.VaultOptions{Address: server.URL, Token: "vault-token", Mount: "kv"})
+
+	for _, test := range []struct {
+		name          string
+		secret        string
+		expected      string
+		expectedError string
+		notFound      bool
+	}{
+		{name: "key", secret: "payments/db#password", expected: "hunter2"},
+		{name: "default key", secret: "payments/db", expected: "default"},
+		{name: "unknown secret", secret: "payments/queue", notFound: true},
+		{name: "unknown key", secret: "payments/db#user", notFound: true},
+		{name: "not a string", secret: "payments/db#port", expectedError: "key port of vault secret payments/db is not a string"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			secret, err := provider.Secret(context.Background(), test.secret)
+			switch {
+			case test.notFound:
+				require.ErrorIs(t, err, f1secrets.ErrNotFound)
+			case test.expectedError != "":
+				require.EqualError(t, err, test.expectedError)
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, test.expected, secret.Reveal())
+			}
+		})
+	}
+}
+
+func TestVaultFailsWithoutAccess(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	t.Cleanup(server.Close)
+
+	provider := f1secrets.Vault(f1secrets.VaultOptions{Address: server.URL, Token: "expired"})
+
+	_, err := provider.Secret(context.Background(), "payments/db")
+	require.EqualError(t, err, "fetching vault secret payments/db: 403 Forbidden")
+}
@@ -0,0 +1,72 @@
+package f1
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/form3tech-oss/f1/v2/pkg/f1/testing"
+)
+
+// Mix runs one of a weighted set of operations on every iteration, simplifying scenarios which
+// simulate a realistic mix of traffic:
+//
+//	mix := f1.NewMix().
+//		Add("create", 0.2, createFn).
+//		Add("fetch", 0.8, fetchFn)
+//
+//	return mix.Run
+//
+// The duration of every operation is recorded in the iteration metrics with the operation
+// name as the stage label.
+type Mix struct {
+	operations  []mixOperation
+	totalWeight float64
+}
+
+type mixOperation struct {
+	fn     testing.RunFn
+	name   string
+	weight float64
+}
+
+// NewMix returns an empty operation mix.
+func NewMix() *Mix {
+	return &Mix{}
+}
+
+// Add registers an operation with the given name and weight. The probability of the operation
+// running on an iteration is its weight divided by the sum of the weights of all operations.
+// It panics if the weight is not positive.
+func (m *Mix) Add(name string, weight float64, fn testing.RunFn) *Mix {
+	if weight <= 0 {
+		panic(fmt.Sprintf("weight %v of operation %s must be positive", weight, name))
+	}
+
+	m.operations = append(m.operations, mixOperation{name: name, weight: weight, fn: fn})
+	m.totalWeight += weight
+
+	return m
+}
+
+// Run runs a randomly chosen operation. It can be returned by a ScenarioFn as the RunFn.
+func (m *Mix) Run(t *testing.T) {
+	if len(m.operations) == 0 {
+		t.Fatalf("operation mix is empty")
+	}
+
+	operation := m.pick(rand.Float64() * m.totalWeight)
+	t.Time(operation.name, func() {
+		operation.fn(t)
+	})
+}
+
+func (m *Mix) pick(value float64) mixOperation {
+	for _, operation := range m.operations {
+		if value < operation.weight {
+			return operation
+		}
+		value -= operation.weight
+	}
+
+	return m.operations[len(m.operations)-1]
+}
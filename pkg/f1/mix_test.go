@@ -0,0 +1,39 @@
+package f1_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/log"
+	"github.com/form3tech-oss/f1/v2/pkg/f1"
+	f1testing "github.com/form3tech-oss/f1/v2/pkg/f1/testing"
+)
+
+func TestMixRunsOperationsByWeight(t *testing.T) {
+	t.Parallel()
+
+	counts := map[string]int{}
+	mix := f1.NewMix().
+		Add("create", 0.2, func(*f1testing.T) { counts["create"]++ }).
+		Add("fetch", 0.8, func(*f1testing.T) { counts["fetch"]++ })
+
+	iterationT, teardown := f1testing.NewTWithOptions("mix", f1testing.WithLogger(log.NewDiscardLogger()))
+	defer teardown()
+
+	for range 10000 {
+		mix.Run(iterationT)
+	}
+
+	require.InDelta(t, 2000, counts["create"], 300)
+	require.InDelta(t, 8000, counts["fetch"], 300)
+	require.False(t, iterationT.Failed())
+}
+
+func TestMixRejectsNonPositiveWeights(t *testing.T) {
+	t.Parallel()
+
+	require.PanicsWithValue(t, "weight 0 of operation create must be positive", func() {
+		f1.NewMix().Add("create", 0, func(*f1testing.T) {})
+	})
+}
@@ -0,0 +1,32 @@
+package f1
+
+import (
+	"github.com/form3tech-oss/f1/v2/pkg/f1/testing"
+)
+
+// Operations runs the named operation each iteration was triggered for, when the operations of
+// a scenario are triggered with their own rates by the operations of a file config:
+//
+//	return f1.Operations{
+//		"read":  readFn,
+//		"write": writeFn,
+//	}.Run
+//
+// Unlike a Mix, which picks an operation at random on every iteration, the rate of every
+// operation is set by the config. The duration of every operation is recorded in the iteration
+// metrics with the operation name as the stage label.
+type Operations map[string]testing.RunFn
+
+// Run runs the operation the iteration was triggered for. It can be returned by a ScenarioFn
+// as the RunFn. The iteration fails if the scenario has no such operation.
+func (o Operations) Run(t *testing.T) {
+	name := t.Operation()
+	fn, ok := o[name]
+	if !ok {
+		t.Fatalf("operation %q is not defined by the scenario", name)
+	}
+
+	t.Time(name, func() {
+		fn(t)
+	})
+}
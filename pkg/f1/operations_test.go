@@ -0,0 +1,53 @@
+package f1_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/log"
+	"github.com/form3tech-oss/f1/v2/pkg/f1"
+	f1testing "github.com/form3tech-oss/f1/v2/pkg/f1/testing"
+)
+
+func TestOperationsRunTheTriggeredOperation(t *testing.T) {
+	t.Parallel()
+
+	counts := map[string]int{}
+	operations := f1.Operations{
+		"read":  func(*f1testing.T) { counts["read"]++ },
+		"write": func(*f1testing.T) { counts["write"]++ },
+	}
+
+	iterationT, teardown := f1testing.NewTWithOptions("operations",
+		f1testing.WithLogger(log.NewDiscardLogger()),
+		f1testing.WithOperation("write"),
+	)
+	defer teardown()
+
+	operations.Run(iterationT)
+
+	require.Equal(t, map[string]int{"write": 1}, counts)
+	require.False(t, iterationT.Failed())
+}
+
+func TestOperationsFailUndefinedOperations(t *testing.T) {
+	t.Parallel()
+
+	operations := f1.Operations{
+		"read": func(*f1testing.T) {},
+	}
+
+	iterationT, teardown := f1testing.NewTWithOptions("operations",
+		f1testing.WithLogger(log.NewDiscardLogger()),
+		f1testing.WithOperation("delete"),
+	)
+	defer teardown()
+
+	func() {
+		defer f1testing.CheckResults(iterationT, nil)
+		operations.Run(iterationT)
+	}()
+
+	require.True(t, iterationT.Failed())
+}
@@ -0,0 +1,58 @@
+package f1
+
+import (
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/pkg/f1/testing"
+)
+
+// ProgressSnapshot is the progress of a run in progress, for embedding f1 in other tools and UIs.
+type ProgressSnapshot struct {
+	// Scenario is the name of the running scenario
+	Scenario string
+	// Stage is the name of the running stage, for triggers which run in stages
+	Stage   string
+	Elapsed time.Duration
+	// ETA is the remaining duration of the run, unless it completes early by reaching its max iterations
+	ETA time.Duration
+	// Rate is the number of successful iterations per second during the latest progress period
+	Rate                 float64
+	SuccessfulIterations uint64
+	FailedIterations     uint64
+	DroppedIterations    uint64
+	// P50, P95 and P99 are estimated quantiles of the durations of successful iterations
+	P50 time.Duration
+	P95 time.Duration
+	P99 time.Duration
+	// Gauges are the latest values of the gauges registered by the scenario with T.ProgressGauge
+	Gauges []testing.ProgressGauge
+}
+
+// Progress returns the progress of the run in progress, as of the latest progress update. It can
+// be called concurrently with Execute or ExecuteWithArgs, and returns false if no run is in progress.
+func (f *F1) Progress() (ProgressSnapshot, bool) {
+	progress, ok := f.tracker.Progress()
+	if !ok {
+		return ProgressSnapshot{}, false
+	}
+
+	var gauges []testing.ProgressGauge
+	for _, gauge := range progress.Gauges {
+		gauges = append(gauges, testing.ProgressGauge{Name: gauge.Name, Value: gauge.Value})
+	}
+
+	return ProgressSnapshot{
+		Scenario:             progress.Scenario,
+		Stage:                progress.Stage,
+		Elapsed:              progress.Elapsed,
+		ETA:                  progress.ETA,
+		Rate:                 progress.Rate,
+		SuccessfulIterations: progress.SuccessfulIterations,
+		FailedIterations:     progress.FailedIterations,
+		DroppedIterations:    progress.DroppedIterations,
+		P50:                  progress.P50,
+		P95:                  progress.P95,
+		P99:                  progress.P99,
+		Gauges:               gauges,
+	}, true
+}
@@ -0,0 +1,49 @@
+// Package raterun executes a function periodically, at frequencies which change over time. It is
+// the utility f1 prints the progress of runs with, every second at first and less often as the
+// run goes on, and can be used by scenarios for their own periodic tasks, such as polling the
+// state of the target:
+//
+//	runner, err := raterun.New(func(interval time.Duration) {
+//		pollQueueDepth()
+//	}, []raterun.Schedule{
+//		{StartDelay: 0, Frequency: time.Second, Jitter: 10},
+//		{StartDelay: time.Minute, Frequency: 10 * time.Second, Jitter: 10},
+//	})
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	runner.Start(t.Context())
+//	t.Cleanup(runner.Stop)
+//
+// The schedules can be replaced at runtime with (*Runner).Reschedule, for example to poll more
+// often while iterations are failing.
+package raterun
+
+import (
+	"fmt"
+
+	"github.com/form3tech-oss/f1/v2/internal/raterun"
+)
+
+// RunFunction is executed by the Runner, with the interval since its previous execution.
+type RunFunction = raterun.RunFunction
+
+// Schedule configures when and how often the Runner executes its function. Each Schedule starts
+// StartDelay after the previous one, and executes the function every Frequency, randomly varied by
+// up to Jitter percent of the Frequency.
+type Schedule = raterun.Schedule
+
+// Runner executes a function as defined by its schedules.
+type Runner = raterun.Runner
+
+// New creates a new runner that will execute fn as defined by the provided schedules. It returns an
+// error if there are no schedules, or a schedule has a frequency which isn't positive or a jitter
+// outside of [0, 100).
+func New(fn RunFunction, schedules []Schedule) (*Runner, error) {
+	runner, err := raterun.New(fn, schedules)
+	if err != nil {
+		return nil, fmt.Errorf("new runner: %w", err)
+	}
+
+	return runner, nil
+}
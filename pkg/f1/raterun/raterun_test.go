@@ -0,0 +1,34 @@
+package raterun_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/pkg/f1/raterun"
+)
+
+func TestRunnerExecutesTheFunctionPeriodically(t *testing.T) {
+	t.Parallel()
+
+	var runs atomic.Int32
+	runner, err := raterun.New(func(time.Duration) {
+		runs.Add(1)
+	}, []raterun.Schedule{{Frequency: 10 * time.Millisecond, Jitter: 10}})
+	require.NoError(t, err)
+
+	runner.Start(context.Background())
+	assert.Eventually(t, func() bool { return runs.Load() >= 3 }, time.Second, 10*time.Millisecond)
+	runner.Stop()
+}
+
+func TestInvalidSchedules(t *testing.T) {
+	t.Parallel()
+
+	_, err := raterun.New(func(time.Duration) {}, []raterun.Schedule{{Frequency: time.Second, Jitter: 100}})
+	require.Error(t, err)
+}
@@ -2,29 +2,45 @@ package f1
 
 import (
 	"fmt"
+	"io/fs"
 	"os"
 	"path"
+	"strings"
 
 	"github.com/spf13/cobra"
 
+	"github.com/form3tech-oss/f1/v2/internal/barrier"
+	"github.com/form3tech-oss/f1/v2/internal/calibrate"
+	"github.com/form3tech-oss/f1/v2/internal/campaign"
+	"github.com/form3tech-oss/f1/v2/internal/catalog"
 	"github.com/form3tech-oss/f1/v2/internal/chart"
+	"github.com/form3tech-oss/f1/v2/internal/configdiff"
 	"github.com/form3tech-oss/f1/v2/internal/envsettings"
+	"github.com/form3tech-oss/f1/v2/internal/logs"
 	"github.com/form3tech-oss/f1/v2/internal/metrics"
+	"github.com/form3tech-oss/f1/v2/internal/orchestrate"
+	"github.com/form3tech-oss/f1/v2/internal/reportindex"
 	"github.com/form3tech-oss/f1/v2/internal/run"
+	"github.com/form3tech-oss/f1/v2/internal/sweep"
 	"github.com/form3tech-oss/f1/v2/internal/trigger"
 	"github.com/form3tech-oss/f1/v2/internal/ui"
 	"github.com/form3tech-oss/f1/v2/pkg/f1/scenarios"
+	f1trigger "github.com/form3tech-oss/f1/v2/pkg/f1/trigger"
 )
 
 const (
 	flagCPUProfile = "cpuprofile"
 	flagMemProfile = "memprofile"
+	flagConfig     = "config"
 )
 
 func buildRootCmd(
 	scenarioList *scenarios.Scenarios,
 	settings envsettings.Settings,
 	p *profiling,
+	profiles fs.FS,
+	customTriggers []f1trigger.Builder,
+	tracker *run.Tracker,
 	output *ui.Output,
 ) (*cobra.Command, error) {
 	rootCmd := &cobra.Command{
@@ -43,20 +59,51 @@ func buildRootCmd(
 		return nil, fmt.Errorf("marking flag as filename: %w", err)
 	}
 
-	metrics.Init(settings.PrometheusEnabled())
+	rootCmd.PersistentFlags().String(flagConfig, "",
+		"read settings from the settings `file`, rather than from f1.toml or f1.yaml in the working directory, "+
+			"environment variables which are set override its settings")
+	if err := rootCmd.MarkPersistentFlagFilename(flagConfig, "toml", "yaml", "yml"); err != nil {
+		return nil, fmt.Errorf("marking flag as filename: %w", err)
+	}
+
+	metricsNaming, err := metrics.NewNaming(settings.Prometheus.MetricPrefix, settings.Prometheus.ConstLabels)
+	if err != nil {
+		return nil, fmt.Errorf("configuring metrics: %w", err)
+	}
+	metrics.Init(settings.MetricsEnabled(), metricsNaming)
 	metricsInstance := metrics.Instance()
 
-	builders := trigger.GetBuilders(output)
+	builders := append(trigger.GetBuilders(output, profiles), customBuilders(customTriggers)...)
 
 	rootCmd.AddCommand(run.Cmd(
 		scenarioList,
 		builders,
 		settings,
 		metricsInstance,
+		tracker,
 		output,
 	))
+	newRunCmd := func() *cobra.Command {
+		return run.Cmd(
+			scenarioList,
+			append(trigger.GetBuilders(output, profiles), customBuilders(customTriggers)...),
+			settings,
+			metricsInstance,
+			tracker,
+			output,
+		)
+	}
+	rootCmd.AddCommand(campaign.Cmd(newRunCmd, output))
+	rootCmd.AddCommand(sweep.Cmd(newRunCmd, output))
+	rootCmd.AddCommand(calibrate.Cmd(output))
+	rootCmd.AddCommand(barrier.Cmd(output))
+	rootCmd.AddCommand(logs.Cmd(settings))
 	rootCmd.AddCommand(chart.Cmd(builders, output))
+	rootCmd.AddCommand(configdiff.Cmd(profiles, output))
+	rootCmd.AddCommand(orchestrate.Cmd(settings, output))
 	rootCmd.AddCommand(scenarios.Cmd(scenarioList))
+	rootCmd.AddCommand(catalog.Cmd())
+	rootCmd.AddCommand(reportindex.Cmd())
 	rootCmd.AddCommand(completionsCmd(rootCmd))
 	return rootCmd, nil
 }
@@ -82,6 +129,23 @@ func startProfiling(p *profiling) func(*cobra.Command, []string) error {
 	}
 }
 
+// settingsFile returns the value of the --config flag in args. The settings are needed to build
+// the commands, so the flag is looked up before the commands parse args.
+func settingsFile(args []string) string {
+	for i, arg := range args {
+		switch {
+		case arg == "--":
+			return ""
+		case arg == "--"+flagConfig && i+1 < len(args):
+			return args[i+1]
+		case strings.HasPrefix(arg, "--"+flagConfig+"="):
+			return strings.TrimPrefix(arg, "--"+flagConfig+"=")
+		}
+	}
+
+	return ""
+}
+
 func getCmdName() string {
 	return path.Base(os.Args[0])
 }
@@ -2,7 +2,9 @@ package scenarios
 
 import (
 	"sort"
+	"strings"
 
+	"github.com/form3tech-oss/f1/v2/pkg/f1/f1secrets"
 	"github.com/form3tech-oss/f1/v2/pkg/f1/testing"
 )
 
@@ -20,8 +22,22 @@ type Scenario struct {
 	ScenarioFn  testing.ScenarioFn
 	// The function that is invoked on each iteration of the test scenario.
 	RunFn testing.RunFn
+	// The optional function that determines the result of each iteration.
+	Classifier testing.ClassifierFn
+	// The optional function that checks whether the target is ready before load is started.
+	Readiness testing.ReadinessFn
+	// The optional function that determines the category of each failed iteration.
+	FailureCategory testing.FailureCategoryFn
+	// Annotations describe the scenario to f1, such as the environment it targets.
+	Annotations map[string]string
+	// The optional provider of the secrets of the scenario, see testing.T.Secret.
+	Secrets f1secrets.Provider
 }
 
+// AnnotationEnvironment is the annotation of the environment targeted by a scenario. Runs of
+// scenarios targeting production must be confirmed before they start, see IsProduction.
+const AnnotationEnvironment = "environment"
+
 type ScenarioParameter struct {
 	Name        string
 	Description string
@@ -42,6 +58,61 @@ func Parameter(parameter ScenarioParameter) ScenarioOption {
 	}
 }
 
+// Classifier sets the function that determines the result of each iteration, for example to
+// fail iterations which are slower than expected even though they did not fail.
+func Classifier(fn testing.ClassifierFn) ScenarioOption {
+	return func(i *Scenario) {
+		i.Classifier = fn
+	}
+}
+
+// Readiness sets the function that checks whether the target of the scenario is ready, before
+// iterations are started and between the stages of the run, see the --ready-timeout flag.
+func Readiness(fn testing.ReadinessFn) ScenarioOption {
+	return func(i *Scenario) {
+		i.Readiness = fn
+	}
+}
+
+// FailureCategories sets the function that determines the category of each failed iteration,
+// whose count and latency are summarised by category at the end of the run.
+func FailureCategories(fn testing.FailureCategoryFn) ScenarioOption {
+	return func(i *Scenario) {
+		i.FailureCategory = fn
+	}
+}
+
+// Secrets sets the provider of the secrets the scenario fetches with testing.T.Secret, such as
+// f1secrets.Vault, so that credentials used under load are fetched consistently and never logged:
+//
+//	secrets := f1secrets.Cached(f1secrets.Vault(f1secrets.VaultOptions{}), time.Minute)
+//	f.Add("myTest", myScenario, scenarios.Secrets(secrets))
+func Secrets(provider f1secrets.Provider) ScenarioOption {
+	return func(i *Scenario) {
+		i.Secrets = provider
+	}
+}
+
+// Annotation annotates the scenario with a value, for example to mark that it targets production:
+//
+//	f.Add("myTest", myScenario, scenarios.Annotation(scenarios.AnnotationEnvironment, "production"))
+func Annotation(key, value string) ScenarioOption {
+	return func(i *Scenario) {
+		if i.Annotations == nil {
+			i.Annotations = map[string]string{}
+		}
+		i.Annotations[key] = value
+	}
+}
+
+// IsProduction returns true if the scenario is annotated as targeting a production environment,
+// named "production" or "prod".
+func (s *Scenario) IsProduction() bool {
+	environment := s.Annotations[AnnotationEnvironment]
+
+	return strings.EqualFold(environment, "production") || strings.EqualFold(environment, "prod")
+}
+
 func New() *Scenarios {
 	return &Scenarios{
 		scenarios: make(map[string]*Scenario),
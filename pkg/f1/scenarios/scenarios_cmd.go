@@ -1,13 +1,17 @@
 package scenarios
 
 import (
+	"encoding/json"
 	"fmt"
-	"os"
 	"sort"
 
 	"github.com/spf13/cobra"
+
+	"github.com/form3tech-oss/f1/v2/internal/catalog"
 )
 
+const flagJSON = "json"
+
 func Cmd(s *Scenarios) *cobra.Command {
 	scenariosCmd := &cobra.Command{
 		Use:   "scenarios",
@@ -20,18 +24,53 @@ func Cmd(s *Scenarios) *cobra.Command {
 
 func lsCmd(s *Scenarios) *cobra.Command {
 	lsCmd := &cobra.Command{
-		Use: "ls",
-		Run: lsCmdExecute(s),
+		Use:  "ls",
+		RunE: lsCmdExecute(s),
 	}
+	lsCmd.Flags().Bool(flagJSON, false,
+		"print the scenarios with their description and parameters as a json array, as read by f1 catalog")
 	return lsCmd
 }
 
-func lsCmdExecute(s *Scenarios) func(*cobra.Command, []string) {
-	return func(*cobra.Command, []string) {
+func lsCmdExecute(s *Scenarios) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, _ []string) error {
+		asJSON, err := cmd.Flags().GetBool(flagJSON)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+
 		scenarios := s.GetScenarioNames()
 		sort.Strings(scenarios)
-		for _, scenario := range scenarios {
-			fmt.Fprintln(os.Stdout, scenario)
+		if !asJSON {
+			for _, scenario := range scenarios {
+				fmt.Fprintln(cmd.OutOrStdout(), scenario)
+			}
+			return nil
+		}
+
+		entries := make([]catalog.Scenario, 0, len(scenarios))
+		for _, name := range scenarios {
+			entries = append(entries, s.GetScenario(name).catalogEntry())
 		}
+		encoder := json.NewEncoder(cmd.OutOrStdout())
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(entries); err != nil {
+			return fmt.Errorf("writing scenarios: %w", err)
+		}
+
+		return nil
+	}
+}
+
+func (s *Scenario) catalogEntry() catalog.Scenario {
+	entry := catalog.Scenario{Name: s.Name, Description: s.Description}
+	for _, parameter := range s.Parameters {
+		entry.Parameters = append(entry.Parameters, catalog.Parameter{
+			Name:        parameter.Name,
+			Description: parameter.Description,
+			Default:     parameter.Default,
+		})
 	}
+
+	return entry
 }
@@ -0,0 +1,38 @@
+package scenarios_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/pkg/f1/scenarios"
+)
+
+func TestLsPrintsTheScenariosAsJSON(t *testing.T) {
+	t.Parallel()
+
+	list := scenarios.New()
+	list.Add(&scenarios.Scenario{Name: "b-scenario"})
+	list.Add(&scenarios.Scenario{
+		Name:        "a-scenario",
+		Description: "creates payments",
+		Parameters:  []scenarios.ScenarioParameter{{Name: "region", Description: "region to pay in", Default: "eu"}},
+	})
+
+	var stdout bytes.Buffer
+	cmd := scenarios.Cmd(list)
+	cmd.SetOut(&stdout)
+	cmd.SetArgs([]string{"ls", "--json"})
+
+	require.NoError(t, cmd.Execute())
+	assert.JSONEq(t, `[
+		{
+			"name": "a-scenario",
+			"description": "creates payments",
+			"parameters": [{"name": "region", "description": "region to pay in", "default": "eu"}]
+		},
+		{"name": "b-scenario"}
+	]`, stdout.String())
+}
@@ -0,0 +1,125 @@
+package f1
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/form3tech-oss/f1/v2/pkg/f1/testing"
+)
+
+// SetupGraph combines scenarios whose setups run in parallel, reducing the time to prepare
+// workloads made of several independent scenarios. A scenario may depend on other scenarios, in
+// which case its setup starts once their setups have completed:
+//
+//	graph := f1.NewSetupGraph().
+//		Add("accounts", setupAccounts).
+//		Add("payments", setupPayments, "accounts").
+//		Add("reports", setupReports)
+//
+//	f1.New().Add("suite", graph.Scenario()).Execute()
+//
+// Setups share the *testing.T of the combined scenario, so a failed setup fails the setup of the
+// combined scenario, and the setups depending on it are skipped.
+type SetupGraph struct {
+	index     map[string]int
+	scenarios []graphScenario
+}
+
+type graphScenario struct {
+	scenario  testing.ScenarioFn
+	name      string
+	dependsOn []int
+}
+
+// NewSetupGraph returns an empty setup graph.
+func NewSetupGraph() *SetupGraph {
+	return &SetupGraph{index: map[string]int{}}
+}
+
+// Add registers a scenario whose setup starts once the setups of the scenarios it depends on
+// have completed. It panics if the name is already registered or if a dependency hasn't been
+// registered yet, which also keeps the graph free of cycles.
+func (g *SetupGraph) Add(name string, scenario testing.ScenarioFn, dependsOn ...string) *SetupGraph {
+	if _, ok := g.index[name]; ok {
+		panic(fmt.Sprintf("scenario %s is already in the setup graph", name))
+	}
+
+	dependencies := make([]int, len(dependsOn))
+	for i, dependency := range dependsOn {
+		index, ok := g.index[dependency]
+		if !ok {
+			panic(fmt.Sprintf("scenario %s depends on %s, which must be added first", name, dependency))
+		}
+		dependencies[i] = index
+	}
+
+	g.index[name] = len(g.scenarios)
+	g.scenarios = append(g.scenarios, graphScenario{name: name, scenario: scenario, dependsOn: dependencies})
+
+	return g
+}
+
+// Scenario returns a scenario which sets up every scenario of the graph, then runs the RunFn of
+// each of them on every iteration, in the order they were added.
+func (g *SetupGraph) Scenario() testing.ScenarioFn {
+	return func(t *testing.T) testing.RunFn {
+		runFns := g.setup(t)
+
+		return func(t *testing.T) {
+			for _, run := range runFns {
+				run(t)
+			}
+		}
+	}
+}
+
+// Operations returns a scenario which sets up every scenario of the graph, then runs the RunFn of
+// the scenario named after the operation of every iteration, see Operations.
+func (g *SetupGraph) Operations() testing.ScenarioFn {
+	return func(t *testing.T) testing.RunFn {
+		runFns := g.setup(t)
+
+		operations := make(Operations, len(runFns))
+		for i, scenario := range g.scenarios {
+			operations[scenario.name] = runFns[i]
+		}
+
+		return operations.Run
+	}
+}
+
+// setup runs the setups of the graph in parallel, each in its own goroutine waiting for the
+// setups it depends on. It returns the RunFn of every scenario, which is nil for failed setups.
+func (g *SetupGraph) setup(t *testing.T) []testing.RunFn {
+	runFns := make([]testing.RunFn, len(g.scenarios))
+	// completed is written by the goroutine of each setup before closing its done channel
+	completed := make([]bool, len(g.scenarios))
+	done := make([]chan struct{}, len(g.scenarios))
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+
+	var wg sync.WaitGroup
+	for i, scenario := range g.scenarios {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer close(done[i])
+
+			for _, dependency := range scenario.dependsOn {
+				<-done[dependency]
+				if !completed[dependency] {
+					t.Errorf("skipping setup of %s: setup of %s failed", scenario.name, g.scenarios[dependency].name)
+					return
+				}
+			}
+
+			defer testing.CheckResults(t, nil)
+			runFns[i] = scenario.scenario(t)
+			completed[i] = true
+		}()
+	}
+	wg.Wait()
+
+	return runFns
+}
@@ -0,0 +1,151 @@
+package f1_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/log"
+	"github.com/form3tech-oss/f1/v2/pkg/f1"
+	f1testing "github.com/form3tech-oss/f1/v2/pkg/f1/testing"
+)
+
+func TestSetupGraphRunsIndependentSetupsInParallel(t *testing.T) {
+	t.Parallel()
+
+	// each setup waits for the other one to start, so they only complete if they run in parallel
+	accountsStarted := make(chan struct{})
+	reportsStarted := make(chan struct{})
+	waitFor := func(started chan struct{}) {
+		select {
+		case <-started:
+		case <-time.After(5 * time.Second):
+		}
+	}
+
+	var runs []string
+	graph := f1.NewSetupGraph().
+		Add("accounts", func(*f1testing.T) f1testing.RunFn {
+			close(accountsStarted)
+			waitFor(reportsStarted)
+			return func(*f1testing.T) { runs = append(runs, "accounts") }
+		}).
+		Add("reports", func(*f1testing.T) f1testing.RunFn {
+			close(reportsStarted)
+			waitFor(accountsStarted)
+			return func(*f1testing.T) { runs = append(runs, "reports") }
+		})
+
+	scenarioT, teardown := f1testing.NewTWithOptions("suite", f1testing.WithLogger(log.NewDiscardLogger()))
+	defer teardown()
+
+	start := time.Now()
+	run := graph.Scenario()(scenarioT)
+	require.Less(t, time.Since(start), 5*time.Second)
+	require.False(t, scenarioT.Failed())
+
+	run(scenarioT)
+	require.Equal(t, []string{"accounts", "reports"}, runs)
+}
+
+func TestSetupGraphWaitsForDependencies(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var setups []string
+	setup := func(name string, duration time.Duration) f1testing.ScenarioFn {
+		return func(*f1testing.T) f1testing.RunFn {
+			time.Sleep(duration)
+			mu.Lock()
+			defer mu.Unlock()
+			setups = append(setups, name)
+			return func(*f1testing.T) {}
+		}
+	}
+
+	graph := f1.NewSetupGraph().
+		Add("accounts", setup("accounts", 50*time.Millisecond)).
+		Add("payments", setup("payments", 0), "accounts").
+		Add("refunds", setup("refunds", 0), "accounts", "payments")
+
+	scenarioT, teardown := f1testing.NewTWithOptions("suite", f1testing.WithLogger(log.NewDiscardLogger()))
+	defer teardown()
+
+	graph.Scenario()(scenarioT)
+
+	require.False(t, scenarioT.Failed())
+	require.Equal(t, []string{"accounts", "payments", "refunds"}, setups)
+}
+
+func TestSetupGraphSkipsSetupsDependingOnFailedSetups(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var setups []string
+	graph := f1.NewSetupGraph().
+		Add("accounts", func(t *f1testing.T) f1testing.RunFn {
+			t.Require().Fail("accounts unavailable")
+			return nil
+		}).
+		Add("payments", func(*f1testing.T) f1testing.RunFn {
+			mu.Lock()
+			defer mu.Unlock()
+			setups = append(setups, "payments")
+			return func(*f1testing.T) {}
+		}, "accounts").
+		Add("reports", func(*f1testing.T) f1testing.RunFn {
+			mu.Lock()
+			defer mu.Unlock()
+			setups = append(setups, "reports")
+			return func(*f1testing.T) {}
+		})
+
+	scenarioT, teardown := f1testing.NewTWithOptions("suite", f1testing.WithLogger(log.NewDiscardLogger()))
+	defer teardown()
+
+	graph.Scenario()(scenarioT)
+
+	require.True(t, scenarioT.Failed())
+	require.Equal(t, []string{"reports"}, setups)
+}
+
+func TestSetupGraphOperationsRunTheScenarioOfTheOperation(t *testing.T) {
+	t.Parallel()
+
+	counts := map[string]int{}
+	graph := f1.NewSetupGraph().
+		Add("read", func(*f1testing.T) f1testing.RunFn {
+			return func(*f1testing.T) { counts["read"]++ }
+		}).
+		Add("write", func(*f1testing.T) f1testing.RunFn {
+			return func(*f1testing.T) { counts["write"]++ }
+		}, "read")
+
+	scenarioT, teardown := f1testing.NewTWithOptions("suite", f1testing.WithLogger(log.NewDiscardLogger()))
+	defer teardown()
+	run := graph.Operations()(scenarioT)
+
+	iterationT, iterationTeardown := f1testing.NewTWithOptions("suite",
+		f1testing.WithLogger(log.NewDiscardLogger()),
+		f1testing.WithOperation("write"),
+	)
+	defer iterationTeardown()
+	run(iterationT)
+
+	require.Equal(t, map[string]int{"write": 1}, counts)
+}
+
+func TestSetupGraphRejectsInvalidScenarios(t *testing.T) {
+	t.Parallel()
+
+	noop := func(*f1testing.T) f1testing.RunFn { return func(*f1testing.T) {} }
+
+	require.PanicsWithValue(t, "scenario payments depends on accounts, which must be added first", func() {
+		f1.NewSetupGraph().Add("payments", noop, "accounts")
+	})
+	require.PanicsWithValue(t, "scenario accounts is already in the setup graph", func() {
+		f1.NewSetupGraph().Add("accounts", noop).Add("accounts", noop)
+	})
+}
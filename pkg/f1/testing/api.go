@@ -1,5 +1,10 @@
 package testing
 
+import (
+	"context"
+	"time"
+)
+
 // ScenarioFn initialises a scenario and returns the iteration function (RunFn) to be invoked for every iteration
 // of the tests.
 type ScenarioFn func(t *T) RunFn
@@ -7,3 +12,24 @@ type ScenarioFn func(t *T) RunFn
 // RunFn performs a single iteration of the scenario. 't' may be used for asserting
 // results or failing the scenario.
 type RunFn func(t *T)
+
+// ResultType is the result of an iteration.
+type ResultType string
+
+const (
+	SuccessResult ResultType = "success"
+	FailedResult  ResultType = "fail"
+)
+
+// ClassifierFn determines the result of an iteration from the error it failed with, or nil
+// if it did not fail, and its duration. Any result other than SuccessResult counts as a failure.
+type ClassifierFn func(err error, duration time.Duration) ResultType
+
+// FailureCategoryFn determines the category a failed iteration is reported under, such as
+// "timeout", "5xx" or "validation", from the error it failed with, or nil if it was failed by the
+// classifier, and its duration. See DefaultFailureCategory.
+type FailureCategoryFn func(err error, duration time.Duration) string
+
+// ReadinessFn checks whether the system under test is ready for load, such as once it completed
+// deploying or restarting. It returns an error describing why it isn't ready.
+type ReadinessFn func(ctx context.Context) error
@@ -0,0 +1,17 @@
+package testing
+
+import "slices"
+
+// WithArgs sets the arguments passed to the scenario, see Args.
+func WithArgs(args []string) TOption {
+	return func(t *T) {
+		t.args = args
+	}
+}
+
+// Args returns the arguments passed to the scenario after `--` on the command line, such as
+// `--custom-flag=value` in `f1 run constant myscenario -- --custom-flag=value`. Scenarios can
+// parse them with their own flag set, without colliding with the flags of the trigger.
+func (t *T) Args() []string {
+	return slices.Clone(t.args)
+}
@@ -0,0 +1,211 @@
+package testing
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// WithStopping sets the channel closed once the run stops triggering iterations, which cuts the
+// delays of the iterations still running short, see Sleep.
+func WithStopping(stopping <-chan struct{}) TOption {
+	return func(t *T) {
+		t.stopping = stopping
+	}
+}
+
+// Sleep pauses the iteration for d, such as to think between two requests like a user would. It
+// returns false without waiting for the rest of d once the run stops triggering iterations, as it
+// completes or is interrupted, or once the context of T is cancelled, in which case the iteration
+// should return rather than carry on:
+//
+//	if !t.Sleep(time.Second) {
+//		return
+//	}
+//
+// so that delays don't hold the end of runs back, unlike time.Sleep.
+func (t *T) Sleep(d time.Duration) bool {
+	if t.stopped() {
+		return false
+	}
+	if d <= 0 {
+		return true
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-t.stopping:
+		return false
+	case <-t.Context().Done():
+		return false
+	}
+}
+
+// SleepFor pauses the iteration for a duration drawn from delay, like Sleep:
+//
+//	thinkTime := testing.LogNormal(2*time.Second, 0.5).Max(10 * time.Second)
+//
+//	return func(t *testing.T) {
+//		browse(t)
+//		if !t.SleepFor(thinkTime) {
+//			return
+//		}
+//		checkout(t)
+//	}
+func (t *T) SleepFor(delay Delay) bool {
+	return t.Sleep(delay())
+}
+
+func (t *T) stopped() bool {
+	select {
+	case <-t.stopping:
+		return true
+	default:
+		return false
+	}
+}
+
+// Delay returns a duration to pause an iteration for, drawn at random for realistic delays, see
+// SleepFor. Delays are safe to use from the iterations of all the workers at once.
+type Delay func() time.Duration
+
+// Constant is a delay of d every time.
+func Constant(d time.Duration) Delay {
+	return func() time.Duration { return d }
+}
+
+// Uniform is a delay uniformly distributed between minimum and maximum.
+func Uniform(minimum, maximum time.Duration) Delay {
+	if maximum < minimum {
+		panic(fmt.Sprintf("maximum delay %s is less than the minimum delay %s", maximum, minimum))
+	}
+
+	return func() time.Duration {
+		jitter := rand.Int63n(int64(maximum-minimum) + 1) //nolint:gosec // delays aren't security sensitive
+		return minimum + time.Duration(jitter)
+	}
+}
+
+// LogNormal is a delay following a log-normal distribution, the usual model of the think times of
+// users: half of the delays are shorter than median, and the others have a long tail, longer as
+// sigma, the standard deviation of the logarithm of the delays, grows. A sigma of 0.5 puts 95% of
+// the delays between 0.38 and 2.7 times the median. Delays are best capped with Max.
+func LogNormal(median time.Duration, sigma float64) Delay {
+	if median <= 0 || sigma < 0 {
+		panic(fmt.Sprintf("median %s must be positive and sigma %v can't be negative", median, sigma))
+	}
+
+	mu := math.Log(float64(median))
+	return func() time.Duration {
+		return durationOf(math.Exp(mu + sigma*rand.NormFloat64())) //nolint:gosec // delays aren't security sensitive
+	}
+}
+
+// WeightedDelay is a delay of Weighted with its weight.
+type WeightedDelay struct {
+	Weight float64
+	Delay  Delay
+}
+
+// Weighted is a delay drawn from one of delays, chosen with the probability of its weight divided
+// by the sum of the weights, such as to mix quick and slow users:
+//
+//	testing.Weighted(
+//		testing.WeightedDelay{Weight: 0.8, Delay: testing.LogNormal(time.Second, 0.5)},
+//		testing.WeightedDelay{Weight: 0.2, Delay: testing.LogNormal(10*time.Second, 0.5)},
+//	)
+//
+// It panics if there are no delays or a weight is not positive.
+func Weighted(delays ...WeightedDelay) Delay {
+	if len(delays) == 0 {
+		panic("weighted delay without delays")
+	}
+
+	total := 0.0
+	for _, delay := range delays {
+		if delay.Weight <= 0 {
+			panic(fmt.Sprintf("weight %v of delay must be positive", delay.Weight))
+		}
+		total += delay.Weight
+	}
+
+	return func() time.Duration {
+		pick := rand.Float64() * total //nolint:gosec // delays aren't security sensitive
+		for _, delay := range delays[:len(delays)-1] {
+			if pick < delay.Weight {
+				return delay.Delay()
+			}
+			pick -= delay.Weight
+		}
+
+		return delays[len(delays)-1].Delay()
+	}
+}
+
+// Max caps the delay at maximum.
+func (d Delay) Max(maximum time.Duration) Delay {
+	return func() time.Duration {
+		return min(d(), maximum)
+	}
+}
+
+// Backoff is how long to wait between the attempts of Retry: exponentially longer, starting from
+// Initial and multiplied by Multiplier, 2 if not set, after every attempt, capped at Max if set.
+// The waits are drawn at random up to the backoff, with full jitter, so that iterations failing
+// together don't retry together.
+type Backoff struct {
+	Initial    time.Duration
+	Max        time.Duration
+	Multiplier float64
+	// Attempts is the number of times fn is called at most, including the first attempt
+	Attempts int
+}
+
+// delay returns the wait after the attempt, counted from 0.
+func (b Backoff) delay(attempt int) time.Duration {
+	multiplier := b.Multiplier
+	if multiplier == 0 {
+		multiplier = 2
+	}
+
+	backoff := float64(b.Initial) * math.Pow(multiplier, float64(attempt))
+	if b.Max > 0 {
+		backoff = min(backoff, float64(b.Max))
+	}
+
+	return durationOf(rand.Float64() * backoff) //nolint:gosec // delays aren't security sensitive
+}
+
+// Retry calls fn until it succeeds or it was called backoff.Attempts times, waiting between the
+// attempts as set by backoff, and returns the error of the last attempt. It stops retrying early
+// when the run stops triggering iterations, as Sleep does.
+//
+//	err := t.Retry(testing.Backoff{Initial: 100 * time.Millisecond, Max: 2 * time.Second, Attempts: 5},
+//		func() error { return fetchPayment(id) })
+func (t *T) Retry(backoff Backoff, fn func() error) error {
+	var err error
+	for attempt := range max(backoff.Attempts, 1) {
+		if attempt > 0 && !t.Sleep(backoff.delay(attempt-1)) {
+			return err
+		}
+		if err = fn(); err == nil {
+			return nil
+		}
+	}
+
+	return err
+}
+
+// durationOf converts nanoseconds to a duration, capped at the longest duration.
+func durationOf(nanoseconds float64) time.Duration {
+	if nanoseconds >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+
+	return time.Duration(nanoseconds)
+}
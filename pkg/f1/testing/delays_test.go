@@ -0,0 +1,195 @@
+package testing_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	f1testing "github.com/form3tech-oss/f1/v2/pkg/f1/testing"
+)
+
+const samples = 10000
+
+func TestSleepIsCutShortWhenTheRunStops(t *testing.T) {
+	t.Parallel()
+
+	stopping := make(chan struct{})
+	iteration, teardown := f1testing.NewTWithOptions("scenario", f1testing.WithStopping(stopping))
+	defer teardown()
+
+	start := time.Now()
+	require.True(t, iteration.Sleep(10*time.Millisecond))
+	require.GreaterOrEqual(t, time.Since(start), 10*time.Millisecond)
+
+	time.AfterFunc(10*time.Millisecond, func() { close(stopping) })
+	start = time.Now()
+	require.False(t, iteration.Sleep(time.Minute))
+	require.Less(t, time.Since(start), time.Second)
+
+	require.False(t, iteration.Sleep(0))
+}
+
+func TestSleepIsCutShortWhenTheContextIsCancelled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	iteration, teardown := f1testing.NewTWithOptions("scenario", f1testing.WithContext(ctx))
+	defer teardown()
+
+	time.AfterFunc(10*time.Millisecond, cancel)
+	start := time.Now()
+
+	require.False(t, iteration.Sleep(time.Minute))
+	require.Less(t, time.Since(start), time.Second)
+}
+
+func TestDelays(t *testing.T) {
+	t.Parallel()
+
+	for name, test := range map[string]struct {
+		delay          f1testing.Delay
+		minimum        time.Duration
+		maximum        time.Duration
+		expectedMedian time.Duration
+	}{
+		"constant": {
+			delay:          f1testing.Constant(time.Second),
+			minimum:        time.Second,
+			maximum:        time.Second,
+			expectedMedian: time.Second,
+		},
+		"uniform": {
+			delay:          f1testing.Uniform(time.Second, 3*time.Second),
+			minimum:        time.Second,
+			maximum:        3 * time.Second,
+			expectedMedian: 2 * time.Second,
+		},
+		"log-normal": {
+			delay:          f1testing.LogNormal(2*time.Second, 0.5),
+			minimum:        0,
+			maximum:        time.Hour,
+			expectedMedian: 2 * time.Second,
+		},
+		"capped log-normal": {
+			delay:          f1testing.LogNormal(2*time.Second, 0.5).Max(3 * time.Second),
+			minimum:        0,
+			maximum:        3 * time.Second,
+			expectedMedian: 2 * time.Second,
+		},
+		"weighted": {
+			delay: f1testing.Weighted(
+				f1testing.WeightedDelay{Weight: 3, Delay: f1testing.Constant(time.Second)},
+				f1testing.WeightedDelay{Weight: 1, Delay: f1testing.Constant(time.Minute)},
+			),
+			minimum:        time.Second,
+			maximum:        time.Minute,
+			expectedMedian: time.Second,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			delays := make([]time.Duration, samples)
+			for i := range delays {
+				delays[i] = test.delay()
+			}
+			slices.Sort(delays)
+
+			assert.GreaterOrEqual(t, delays[0], test.minimum)
+			assert.LessOrEqual(t, delays[samples-1], test.maximum)
+			assert.InEpsilon(t, test.expectedMedian, delays[samples/2], 0.1)
+		})
+	}
+}
+
+func TestWeightedDelaysAreChosenByWeight(t *testing.T) {
+	t.Parallel()
+
+	delay := f1testing.Weighted(
+		f1testing.WeightedDelay{Weight: 3, Delay: f1testing.Constant(time.Second)},
+		f1testing.WeightedDelay{Weight: 1, Delay: f1testing.Constant(time.Minute)},
+	)
+
+	long := 0
+	for range samples {
+		if delay() == time.Minute {
+			long++
+		}
+	}
+
+	assert.InEpsilon(t, samples/4, long, 0.1)
+}
+
+func TestInvalidDelaysPanic(t *testing.T) {
+	t.Parallel()
+
+	assert.Panics(t, func() { f1testing.Uniform(time.Second, time.Millisecond) })
+	assert.Panics(t, func() { f1testing.LogNormal(0, 0.5) })
+	assert.Panics(t, func() { f1testing.LogNormal(time.Second, -1) })
+	assert.Panics(t, func() { f1testing.Weighted() })
+	assert.Panics(t, func() {
+		f1testing.Weighted(f1testing.WeightedDelay{Weight: 0, Delay: f1testing.Constant(time.Second)})
+	})
+}
+
+func TestRetry(t *testing.T) {
+	t.Parallel()
+
+	errUnavailable := errors.New("unavailable")
+	backoff := f1testing.Backoff{Initial: time.Millisecond, Max: 5 * time.Millisecond, Attempts: 4}
+
+	for name, test := range map[string]struct {
+		failures         int
+		expectedAttempts int
+		expectedErr      error
+	}{
+		"first attempt succeeds": {failures: 0, expectedAttempts: 1},
+		"retry succeeds":         {failures: 2, expectedAttempts: 3},
+		"all attempts fail":      {failures: 10, expectedAttempts: 4, expectedErr: errUnavailable},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			iteration, teardown := f1testing.NewTWithOptions("scenario")
+			defer teardown()
+
+			attempts := 0
+			start := time.Now()
+			err := iteration.Retry(backoff, func() error {
+				attempts++
+				if attempts <= test.failures {
+					return errUnavailable
+				}
+				return nil
+			})
+
+			assert.Equal(t, test.expectedAttempts, attempts)
+			assert.Equal(t, test.expectedErr, err)
+			assert.Less(t, time.Since(start), time.Duration(attempts)*backoff.Max+time.Second)
+		})
+	}
+}
+
+func TestRetryStopsWhenTheRunStops(t *testing.T) {
+	t.Parallel()
+
+	stopping := make(chan struct{})
+	iteration, teardown := f1testing.NewTWithOptions("scenario", f1testing.WithStopping(stopping))
+	defer teardown()
+
+	errUnavailable := errors.New("unavailable")
+	attempts := 0
+	err := iteration.Retry(f1testing.Backoff{Initial: time.Hour, Attempts: 5}, func() error {
+		attempts++
+		close(stopping)
+		return errUnavailable
+	})
+
+	assert.Equal(t, 1, attempts)
+	assert.Equal(t, errUnavailable, err)
+}
@@ -0,0 +1,33 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Categories of failed iterations given by DefaultFailureCategory.
+const (
+	// TimeoutFailure is the category of iterations which failed with a timeout
+	TimeoutFailure = "timeout"
+	// ClassifiedFailure is the category of iterations failed by the classifier without an error
+	ClassifiedFailure = "classified"
+	// ErrorFailure is the category of all the other failed iterations
+	ErrorFailure = "error"
+)
+
+// DefaultFailureCategory is the category of failed iterations for scenarios without a
+// FailureCategoryFn: TimeoutFailure for errors which are context.DeadlineExceeded or which report
+// a timeout, like net.Error, ClassifiedFailure without an error, and ErrorFailure otherwise.
+func DefaultFailureCategory(err error, _ time.Duration) string {
+	if err == nil {
+		return ClassifiedFailure
+	}
+
+	var timeout interface{ Timeout() bool }
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &timeout) && timeout.Timeout()) {
+		return TimeoutFailure
+	}
+
+	return ErrorFailure
+}
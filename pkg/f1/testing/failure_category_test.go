@@ -0,0 +1,38 @@
+package testing_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	f1testing "github.com/form3tech-oss/f1/v2/pkg/f1/testing"
+)
+
+func TestDefaultFailureCategory(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{name: "no error", expected: f1testing.ClassifiedFailure},
+		{name: "deadline exceeded", err: fmt.Errorf("calling: %w", context.DeadlineExceeded),
+			expected: f1testing.TimeoutFailure},
+		{name: "network timeout", err: &net.OpError{Op: "dial", Err: os.ErrDeadlineExceeded},
+			expected: f1testing.TimeoutFailure},
+		{name: "other error", err: errors.New("status 503"), expected: f1testing.ErrorFailure},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, f1testing.DefaultFailureCategory(test.err, time.Second))
+		})
+	}
+}
@@ -0,0 +1,304 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/goroutines"
+	"github.com/form3tech-oss/f1/v2/internal/log"
+	"github.com/form3tech-oss/f1/v2/internal/metrics"
+	"github.com/form3tech-oss/f1/v2/internal/xcontext"
+)
+
+// FixturePoolRetryDelay is how long a fixture pool waits to create an entity again after failing to
+// replace a discarded entity.
+const FixturePoolRetryDelay = time.Second
+
+// ErrFixturePoolStopping is returned by FixturePool.Lease when the run stops triggering iterations
+// while the iteration waits for an entity. The iteration should return without failing, as it
+// does when T.Sleep returns false.
+var ErrFixturePoolStopping = errors.New("run stopping")
+
+var errInvalidFixturePoolSize = errors.New("invalid fixture pool size")
+
+// FixturePool is a pool of entities created by the setup of a scenario, such as accounts or
+// mandates, which iterations lease and return rather than creating their own on every iteration:
+//
+//	accounts, err := testing.NewFixturePool(t, "accounts", 100, createAccount)
+//	require.NoError(t, err)
+//	return func(t *testing.T) {
+//		account, err := accounts.Lease(t)
+//		if errors.Is(err, testing.ErrFixturePoolStopping) {
+//			return
+//		}
+//		require.NoError(t, err)
+//		pay(account.Value)
+//	}
+//
+// A leased entity is only used by one iteration at a time, and is returned to the pool when the
+// iteration ends. Entities which iterations discard, because they used them up, and those leased by
+// failed iterations, which may have left them in any state, are replaced by new entities in the
+// background, so that the pool keeps its size. Entities can be deleted from the target once they
+// are discarded, and when the scenario is torn down, with DestroyFixtures.
+type FixturePool[V any] struct {
+	name      string
+	scenario  string
+	create    func(ctx context.Context) (V, error)
+	available chan V
+	// replenish has every discarded entity which hasn't been replaced yet
+	replenish chan V
+	leased    atomic.Int64
+	created   atomic.Uint64
+	discarded atomic.Uint64
+	failures  atomic.Uint64
+	logger    *slog.Logger
+	metrics   *metrics.Metrics
+	// destroy optionally deletes the discarded entities, and the others at teardown
+	destroy         func(ctx context.Context, value V) error
+	destroyed       atomic.Uint64
+	destroyFailures atomic.Uint64
+}
+
+// FixturePoolOption configures a fixture pool, see NewFixturePool.
+type FixturePoolOption[V any] func(*FixturePool[V])
+
+// DestroyFixtures sets the function deleting the entities of a fixture pool from the target. It is
+// called with every discarded entity before it is replaced, and with the entities left in the pool
+// when the scenario is torn down. Entities which fail to be destroyed are logged and counted.
+func DestroyFixtures[V any](destroy func(ctx context.Context, value V) error) FixturePoolOption[V] {
+	return func(p *FixturePool[V]) {
+		p.destroy = destroy
+	}
+}
+
+// FixturePoolStats are the entities of a fixture pool.
+type FixturePoolStats struct {
+	// Size is the number of entities the pool keeps
+	Size int
+	// Available and Leased are the entities which can be leased and those leased by iterations,
+	// the others are being replaced
+	Available int
+	Leased    int
+	// Created counts the entities created, by the setup and to replace discarded entities, and
+	// Discarded the entities discarded by iterations
+	Created   uint64
+	Discarded uint64
+	// CreateFailures counts the entities which failed to be created in place of discarded entities
+	CreateFailures uint64
+	// Destroyed and DestroyFailures count the entities destroyed, see DestroyFixtures, and those
+	// which failed to be
+	Destroyed       uint64
+	DestroyFailures uint64
+}
+
+// NewFixturePool creates size entities with create for the iterations of the scenario to lease. It
+// must be called from the setup of a scenario, t, and fails if an entity fails to be created.
+// Discarded entities are replaced until the teardown of the scenario, retrying every
+// FixturePoolRetryDelay when create fails, and destroyed if the pool has DestroyFixtures.
+//
+// The available and leased entities of the pool are displayed in the progress of the run as the
+// "<name> available" and "<name> leased" gauges, and recorded by the fixture_pool_entities metric.
+func NewFixturePool[V any](
+	t *T,
+	name string,
+	size int,
+	create func(ctx context.Context) (V, error),
+	options ...FixturePoolOption[V],
+) (*FixturePool[V], error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("%w %d for pool %s, expected a positive size", errInvalidFixturePoolSize, size, name)
+	}
+
+	p := &FixturePool[V]{
+		name:      name,
+		scenario:  t.Scenario,
+		create:    create,
+		available: make(chan V, size),
+		replenish: make(chan V, size),
+		logger:    t.logger.With(slog.String("fixture_pool", name)),
+		metrics:   t.metricsInstance(),
+	}
+	for _, option := range options {
+		option(p)
+	}
+
+	ctx := t.Context()
+	for i := range size {
+		value, err := create(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("creating entity %d of fixture pool %s: %w", i+1, name, err)
+		}
+		p.available <- value
+		p.created.Add(1)
+	}
+	p.record()
+
+	// the context of the setup is cancelled by the teardown, before its cleanups run
+	done := make(chan struct{})
+	goroutines.Go(ctx, "fixture-pool", func(ctx context.Context) {
+		defer close(done)
+		p.replenishDiscarded(ctx)
+	})
+	t.Cleanup(func() {
+		<-done
+		p.destroyRemaining(xcontext.Detach(ctx))
+	})
+
+	t.ProgressGauge(name+" available", func() float64 { return float64(len(p.available)) })
+	t.ProgressGauge(name+" leased", func() float64 { return float64(p.leased.Load()) })
+
+	return p, nil
+}
+
+// Lease leases an entity of the pool to the iteration t, waiting until one is available. It returns
+// ErrFixturePoolStopping rather than waiting once the run stops triggering iterations, as it
+// completes or is interrupted, or an error once the context of t is cancelled, which only happens
+// while the iteration runs if t was created with a parent context. The entity is returned to the pool
+// when the iteration ends, or replaced if the iteration fails or discards it.
+func (p *FixturePool[V]) Lease(t *T) (*Lease[V], error) {
+	select {
+	case value := <-p.available:
+		p.leased.Add(1)
+		p.record()
+
+		lease := &Lease[V]{Value: value, pool: p}
+		t.Cleanup(func() { lease.release(t.Failed()) })
+		return lease, nil
+	case <-t.stopping:
+		return nil, fmt.Errorf("leasing entity of fixture pool %s: %w", p.name, ErrFixturePoolStopping)
+	case <-t.Context().Done():
+		return nil, fmt.Errorf("leasing entity of fixture pool %s: %w", p.name, t.Context().Err())
+	}
+}
+
+// Stats returns the entities of the pool. It may be called from any goroutine.
+func (p *FixturePool[V]) Stats() FixturePoolStats {
+	return FixturePoolStats{
+		Size:            cap(p.available),
+		Available:       len(p.available),
+		Leased:          int(p.leased.Load()),
+		Created:         p.created.Load(),
+		Discarded:       p.discarded.Load(),
+		CreateFailures:  p.failures.Load(),
+		Destroyed:       p.destroyed.Load(),
+		DestroyFailures: p.destroyFailures.Load(),
+	}
+}
+
+// replenishDiscarded destroys every discarded entity and creates an entity in its place, until ctx
+// is done.
+func (p *FixturePool[V]) replenishDiscarded(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case discarded := <-p.replenish:
+			p.destroyEntity(ctx, discarded)
+		}
+
+		for {
+			value, err := p.create(ctx)
+			if err == nil {
+				p.available <- value
+				p.created.Add(1)
+				p.record()
+				break
+			}
+			if ctx.Err() != nil {
+				return
+			}
+
+			p.failures.Add(1)
+			p.logger.Error("replacing discarded entity of fixture pool failed", log.ErrorAttr(err))
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(FixturePoolRetryDelay):
+			}
+		}
+	}
+}
+
+// destroyRemaining destroys the entities left in the pool, available or discarded without having
+// been destroyed, once the scenario is torn down.
+func (p *FixturePool[V]) destroyRemaining(ctx context.Context) {
+	if p.destroy == nil {
+		return
+	}
+
+	for {
+		select {
+		case value := <-p.available:
+			p.destroyEntity(ctx, value)
+		case value := <-p.replenish:
+			p.destroyEntity(ctx, value)
+		default:
+			p.record()
+			return
+		}
+	}
+}
+
+// destroyEntity destroys an entity with the destroy function of the pool, if it has one.
+func (p *FixturePool[V]) destroyEntity(ctx context.Context, value V) {
+	if p.destroy == nil {
+		return
+	}
+
+	if err := p.destroy(ctx, value); err != nil {
+		p.destroyFailures.Add(1)
+		p.logger.Error("destroying entity of fixture pool failed", log.ErrorAttr(err))
+		return
+	}
+	p.destroyed.Add(1)
+}
+
+func (p *FixturePool[V]) record() {
+	if p.metrics == nil {
+		return
+	}
+
+	p.metrics.RecordFixturePool(p.scenario, p.name, len(p.available), int(p.leased.Load()))
+}
+
+// Lease is an entity of a fixture pool leased by an iteration, see FixturePool.Lease.
+type Lease[V any] struct {
+	Value    V
+	pool     *FixturePool[V]
+	released atomic.Bool
+}
+
+// Return returns the entity to the pool before the iteration ends, for other iterations to lease.
+// The entity must not be used by the iteration afterwards.
+func (l *Lease[V]) Return() {
+	l.release(false)
+}
+
+// Discard replaces the entity with a new one, when the iteration used it up, such as by closing
+// an account, or left it in a state other iterations can't use. The entity must not be used by the
+// iteration afterwards.
+func (l *Lease[V]) Discard() {
+	l.release(true)
+}
+
+// release returns or discards the entity, once.
+func (l *Lease[V]) release(discard bool) {
+	if !l.released.CompareAndSwap(false, true) {
+		return
+	}
+
+	p := l.pool
+	p.leased.Add(-1)
+	if discard {
+		p.discarded.Add(1)
+		// the pool has room for every entity, so neither blocks
+		p.replenish <- l.Value
+	} else {
+		p.available <- l.Value
+	}
+	p.record()
+}
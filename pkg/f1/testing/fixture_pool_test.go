@@ -0,0 +1,231 @@
+package testing_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	io_prometheus_client "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/log"
+	"github.com/form3tech-oss/f1/v2/internal/metrics"
+	f1testing "github.com/form3tech-oss/f1/v2/pkg/f1/testing"
+)
+
+// newFixturePool returns a pool of size numbered entities created by the setup of a scenario, and
+// the teardown of the scenario.
+func newFixturePool(
+	t *testing.T,
+	size int,
+	options ...f1testing.TOption,
+) (*f1testing.FixturePool[int64], func()) {
+	t.Helper()
+
+	var created atomic.Int64
+	setup, teardown := f1testing.NewTWithOptions("scenario",
+		append([]f1testing.TOption{f1testing.WithLogger(log.NewDiscardLogger())}, options...)...)
+	pool, err := f1testing.NewFixturePool(setup, "accounts", size, func(context.Context) (int64, error) {
+		return created.Add(1), nil
+	})
+	require.NoError(t, err)
+
+	return pool, teardown
+}
+
+func TestFixturePoolEntitiesAreReturnedWhenIterationsEnd(t *testing.T) {
+	t.Parallel()
+
+	pool, teardown := newFixturePool(t, 1)
+	defer teardown()
+
+	iteration, iterationTeardown := newT()
+	lease, err := pool.Lease(iteration)
+	require.NoError(t, err)
+	assert.Equal(t, f1testing.FixturePoolStats{Size: 1, Leased: 1, Created: 1}, pool.Stats())
+
+	iterationTeardown()
+	iteration.Reset("1")
+
+	again, err := pool.Lease(iteration)
+	require.NoError(t, err)
+	assert.Equal(t, lease.Value, again.Value)
+
+	again.Return()
+	assert.Equal(t, f1testing.FixturePoolStats{Size: 1, Available: 1, Created: 1}, pool.Stats())
+	iterationTeardown()
+	assert.Equal(t, f1testing.FixturePoolStats{Size: 1, Available: 1, Created: 1}, pool.Stats())
+}
+
+func TestFixturePoolEntitiesAreReplacedWhenDiscarded(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name    string
+		discard func(t *f1testing.T, lease *f1testing.Lease[int64])
+	}{
+		{
+			name:    "discarded by the iteration",
+			discard: func(_ *f1testing.T, lease *f1testing.Lease[int64]) { lease.Discard() },
+		},
+		{
+			name:    "leased by a failed iteration",
+			discard: func(t *f1testing.T, _ *f1testing.Lease[int64]) { t.Fail() },
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			pool, teardown := newFixturePool(t, 1)
+			defer teardown()
+
+			iteration, iterationTeardown := newT()
+			lease, err := pool.Lease(iteration)
+			require.NoError(t, err)
+			test.discard(iteration, lease)
+			iterationTeardown()
+
+			iteration.Reset("1")
+			replacement, err := pool.Lease(iteration)
+			require.NoError(t, err)
+			assert.NotEqual(t, lease.Value, replacement.Value)
+			assert.Equal(t, f1testing.FixturePoolStats{Size: 1, Leased: 1, Created: 2, Discarded: 1}, pool.Stats())
+		})
+	}
+}
+
+func TestFixturePoolLeaseWaitsForAnEntity(t *testing.T) {
+	t.Parallel()
+
+	pool, teardown := newFixturePool(t, 1)
+	defer teardown()
+
+	first, firstTeardown := newT()
+	_, err := pool.Lease(first)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	second, secondTeardown := f1testing.NewTWithOptions("scenario", f1testing.WithContext(ctx))
+	defer secondTeardown()
+
+	_, err = pool.Lease(second)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.ErrorContains(t, err, "leasing entity of fixture pool accounts")
+
+	leased := make(chan *f1testing.Lease[int64])
+	third, thirdTeardown := newT()
+	defer thirdTeardown()
+	go func() {
+		lease, err := pool.Lease(third)
+		assert.NoError(t, err)
+		leased <- lease
+	}()
+
+	firstTeardown()
+	assert.NotNil(t, <-leased)
+}
+
+func TestFixturePoolLeaseReturnsWhenTheRunStops(t *testing.T) {
+	t.Parallel()
+
+	pool, teardown := newFixturePool(t, 1)
+	defer teardown()
+
+	first, firstTeardown := newT()
+	defer firstTeardown()
+	_, err := pool.Lease(first)
+	require.NoError(t, err)
+
+	stopping := make(chan struct{})
+	second, secondTeardown := f1testing.NewTWithOptions("scenario", f1testing.WithStopping(stopping))
+	defer secondTeardown()
+
+	leaseErr := make(chan error)
+	go func() {
+		_, err := pool.Lease(second)
+		leaseErr <- err
+	}()
+
+	close(stopping)
+	select {
+	case err := <-leaseErr:
+		require.ErrorIs(t, err, f1testing.ErrFixturePoolStopping)
+	case <-time.After(time.Second):
+		t.Fatal("the lease did not return when the run stopped")
+	}
+}
+
+func TestFixturePoolEntitiesAreDestroyed(t *testing.T) {
+	t.Parallel()
+
+	var created atomic.Int64
+	destroyed := make(chan int64, 3)
+	setup, teardown := f1testing.NewTWithOptions("scenario", f1testing.WithLogger(log.NewDiscardLogger()))
+	pool, err := f1testing.NewFixturePool(setup, "accounts", 2,
+		func(context.Context) (int64, error) { return created.Add(1), nil },
+		f1testing.DestroyFixtures(func(_ context.Context, value int64) error {
+			destroyed <- value
+			return nil
+		}),
+	)
+	require.NoError(t, err)
+
+	iteration, iterationTeardown := newT()
+	lease, err := pool.Lease(iteration)
+	require.NoError(t, err)
+	lease.Discard()
+	iterationTeardown()
+
+	// the discarded entity is destroyed before it is replaced
+	assert.Equal(t, lease.Value, <-destroyed)
+	require.Eventually(t, func() bool { return pool.Stats().Available == 2 }, time.Second, time.Millisecond)
+
+	teardown()
+	close(destroyed)
+	remaining := []int64{}
+	for value := range destroyed {
+		remaining = append(remaining, value)
+	}
+	assert.ElementsMatch(t, []int64{2, 3}, remaining)
+	assert.Equal(t, uint64(3), pool.Stats().Destroyed)
+	assert.Zero(t, pool.Stats().Available)
+}
+
+func TestFixturePoolRecordsItsEntities(t *testing.T) {
+	t.Parallel()
+
+	m := metrics.NewInstance(prometheus.NewRegistry(), true)
+	pool, teardown := newFixturePool(t, 3, f1testing.WithMetrics(m))
+	defer teardown()
+
+	iteration, iterationTeardown := newT()
+	defer iterationTeardown()
+	_, err := pool.Lease(iteration)
+	require.NoError(t, err)
+
+	for state, expected := range map[string]float64{metrics.FixturesAvailable: 2, metrics.FixturesLeased: 1} {
+		metric := &io_prometheus_client.Metric{}
+		require.NoError(t, m.FixturePools.WithLabelValues("scenario", "accounts", state).Write(metric))
+		assert.InDelta(t, expected, metric.GetGauge().GetValue(), 0, state)
+	}
+}
+
+func TestFixturePoolFailsWhenTheSetupFailsToCreateEntities(t *testing.T) {
+	t.Parallel()
+
+	setup, teardown := newT()
+	defer teardown()
+
+	_, err := f1testing.NewFixturePool(setup, "accounts", 0, func(context.Context) (int, error) { return 0, nil })
+	require.ErrorContains(t, err, "invalid fixture pool size 0 for pool accounts")
+
+	errCreate := errors.New("account rejected")
+	_, err = f1testing.NewFixturePool(setup, "accounts", 2, func(context.Context) (int, error) { return 0, errCreate })
+	require.ErrorIs(t, err, errCreate)
+	require.ErrorContains(t, err, "creating entity 1 of fixture pool accounts")
+}
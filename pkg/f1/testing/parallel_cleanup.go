@@ -0,0 +1,93 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/log"
+)
+
+// ErrCleanupTimeout is the error of a function registered with ParallelCleanup which didn't
+// return within its timeout.
+var ErrCleanupTimeout = errors.New("cleanup timed out")
+
+type parallelCleanup struct {
+	fn      func(ctx context.Context) error
+	name    string
+	timeout time.Duration
+}
+
+// ParallelCleanup registers a function to be called when the scenario or the iteration completes,
+// in parallel with the other functions registered with ParallelCleanup and before the functions
+// registered with Cleanup. The function is given a context which is cancelled after timeout, and
+// the teardown stops waiting for it then, so that a slow cleanup can't hold up the report of the
+// run. Errors, panics and timeouts of the functions fail the teardown, and are returned by
+// TeardownErr. It may be called from multiple goroutines.
+func (t *T) ParallelCleanup(name string, timeout time.Duration, f func(ctx context.Context) error) {
+	t.teardownMu.Lock()
+	defer t.teardownMu.Unlock()
+
+	t.parallelCleanups = append(t.parallelCleanups, parallelCleanup{fn: f, name: name, timeout: timeout})
+}
+
+// TeardownErr returns the errors of the functions registered with ParallelCleanup, joined, or nil
+// if they all succeeded.
+func (t *T) TeardownErr() error {
+	t.teardownMu.Lock()
+	defer t.teardownMu.Unlock()
+
+	return errors.Join(t.teardownErrs...)
+}
+
+func (t *T) runParallelCleanups() {
+	t.teardownMu.Lock()
+	cleanups := t.parallelCleanups
+	t.teardownMu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, cleanup := range cleanups {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if err := cleanup.run(); err != nil {
+				t.logger.Error("cleanup failed", log.IterationAttr(t.Iteration), log.ErrorAttr(err))
+				t.teardownFailed.Store(true)
+
+				t.teardownMu.Lock()
+				t.teardownErrs = append(t.teardownErrs, err)
+				t.teardownMu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func (c parallelCleanup) run() error {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	// the result is buffered, so that a cleanup returning after its timeout doesn't leak
+	result := make(chan error, 1)
+	go func() {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				result <- fmt.Errorf("panic: %v", recovered)
+			}
+		}()
+		result <- c.fn(ctx)
+	}()
+
+	select {
+	case err := <-result:
+		if err != nil {
+			return fmt.Errorf("cleanup %s: %w", c.name, err)
+		}
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("cleanup %s: %w after %s", c.name, ErrCleanupTimeout, c.timeout)
+	}
+}
@@ -0,0 +1,58 @@
+package testing
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// Parameter returns the value of a stage parameter. Stage parameters are provided by the
+// `parameters` of a `file` trigger stage and are validated against the `parameter-schema` of the
+// config file before the run starts.
+func (t *T) Parameter(name string) string {
+	return os.Getenv(name)
+}
+
+// IntParameter returns the value of a stage parameter declared with type `int`.
+// The iteration fails if the value is not a valid int.
+func (t *T) IntParameter(name string) int {
+	value, err := strconv.Atoi(t.Parameter(name))
+	if err != nil {
+		t.Fatalf("parameter %s is not an int: %s", name, err)
+	}
+
+	return value
+}
+
+// FloatParameter returns the value of a stage parameter declared with type `float`.
+// The iteration fails if the value is not a valid float.
+func (t *T) FloatParameter(name string) float64 {
+	value, err := strconv.ParseFloat(t.Parameter(name), 64)
+	if err != nil {
+		t.Fatalf("parameter %s is not a float: %s", name, err)
+	}
+
+	return value
+}
+
+// BoolParameter returns the value of a stage parameter declared with type `bool`.
+// The iteration fails if the value is not a valid bool.
+func (t *T) BoolParameter(name string) bool {
+	value, err := strconv.ParseBool(t.Parameter(name))
+	if err != nil {
+		t.Fatalf("parameter %s is not a bool: %s", name, err)
+	}
+
+	return value
+}
+
+// DurationParameter returns the value of a stage parameter declared with type `duration`.
+// The iteration fails if the value is not a valid duration.
+func (t *T) DurationParameter(name string) time.Duration {
+	value, err := time.ParseDuration(t.Parameter(name))
+	if err != nil {
+		t.Fatalf("parameter %s is not a duration: %s", name, err)
+	}
+
+	return value
+}
@@ -0,0 +1,101 @@
+package testing_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	f1testing "github.com/form3tech-oss/f1/v2/pkg/f1/testing"
+)
+
+//nolint:paralleltest // parameters are environment variables, set with t.Setenv
+func TestTypedParameters(t *testing.T) {
+	for _, test := range []struct {
+		name, value, expectedError string
+		get                        func(*f1testing.T) any
+		expected                   any
+	}{
+		{
+			name:     "int",
+			value:    "-42",
+			get:      func(t *f1testing.T) any { return t.IntParameter("F1_TESTING_PARAMETER") },
+			expected: -42,
+		},
+		{
+			name:          "invalid int",
+			value:         "1.5",
+			get:           func(t *f1testing.T) any { return t.IntParameter("F1_TESTING_PARAMETER") },
+			expectedError: "parameter F1_TESTING_PARAMETER is not an int",
+		},
+		{
+			name:          "unset int",
+			get:           func(t *f1testing.T) any { return t.IntParameter("F1_TESTING_PARAMETER") },
+			expectedError: "parameter F1_TESTING_PARAMETER is not an int",
+		},
+		{
+			name:     "float",
+			value:    "2.5",
+			get:      func(t *f1testing.T) any { return t.FloatParameter("F1_TESTING_PARAMETER") },
+			expected: 2.5,
+		},
+		{
+			name:          "invalid float",
+			value:         "two",
+			get:           func(t *f1testing.T) any { return t.FloatParameter("F1_TESTING_PARAMETER") },
+			expectedError: "parameter F1_TESTING_PARAMETER is not a float",
+		},
+		{
+			name:     "bool",
+			value:    "true",
+			get:      func(t *f1testing.T) any { return t.BoolParameter("F1_TESTING_PARAMETER") },
+			expected: true,
+		},
+		{
+			name:          "invalid bool",
+			value:         "yes",
+			get:           func(t *f1testing.T) any { return t.BoolParameter("F1_TESTING_PARAMETER") },
+			expectedError: "parameter F1_TESTING_PARAMETER is not a bool",
+		},
+		{
+			name:     "duration",
+			value:    "1m30s",
+			get:      func(t *f1testing.T) any { return t.DurationParameter("F1_TESTING_PARAMETER") },
+			expected: 90 * time.Second,
+		},
+		{
+			name:          "invalid duration",
+			value:         "90",
+			get:           func(t *f1testing.T) any { return t.DurationParameter("F1_TESTING_PARAMETER") },
+			expectedError: "parameter F1_TESTING_PARAMETER is not a duration",
+		},
+		{
+			name:          "unset duration",
+			get:           func(t *f1testing.T) any { return t.DurationParameter("F1_TESTING_PARAMETER") },
+			expectedError: "parameter F1_TESTING_PARAMETER is not a duration",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("F1_TESTING_PARAMETER", test.value)
+			newT, teardown := newT()
+			defer teardown()
+
+			var value any
+			done := make(chan struct{})
+			go func() {
+				defer catchPanics(done)
+				value = test.get(newT)
+			}()
+			<-done
+
+			if test.expectedError != "" {
+				require.True(t, newT.Failed())
+				require.ErrorContains(t, newT.Err(), test.expectedError)
+				return
+			}
+
+			require.False(t, newT.Failed())
+			require.Equal(t, test.expected, value)
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package testing
+
+import "time"
+
+// Phase is where the run is when an iteration reads it, so that scenarios can adapt to the phase of
+// the load, such as only verifying deep consistency during low rate stages.
+type Phase struct {
+	// Stage is the name of the stage of the trigger, as named in the progress, or empty for
+	// triggers which don't run in stages
+	Stage string
+	// StageElapsed is how long the stage has been running
+	StageElapsed time.Duration
+	// RunElapsed is how long the run has been triggering iterations, including the runs it resumed
+	RunElapsed time.Duration
+}
+
+// WithPhase sets the function returning the phase of the run, see Phase.
+func WithPhase(phase func() Phase) TOption {
+	return func(t *T) {
+		t.phase = phase
+	}
+}
+
+// Phase returns the stage of the run and how long it and the run have been running, as of when it
+// is called. It is the zero Phase in the setup of a scenario, before the run triggers iterations.
+func (t *T) Phase() Phase {
+	if t.phase == nil {
+		return Phase{}
+	}
+
+	return t.phase()
+}
@@ -0,0 +1,59 @@
+package testing
+
+import (
+	"slices"
+
+	"github.com/form3tech-oss/f1/v2/internal/log"
+)
+
+// ProgressGauge is the value of a gauge registered with T.ProgressGauge.
+type ProgressGauge struct {
+	Name  string
+	Value float64
+}
+
+type progressGauge struct {
+	value func() float64
+	name  string
+}
+
+// ProgressGauge registers a gauge displayed alongside the iteration counts in the progress of the
+// run, such as the number of accounts created or the depth of a queue, so that the throughput of the
+// system under test is visible live. It must be called from the setup of a scenario, and
+// registering a name again replaces its gauge. value is called on every progress update, from a
+// goroutine of f1, concurrently with iterations.
+func (t *T) ProgressGauge(name string, value func() float64) {
+	t.hooksMu.Lock()
+	defer t.hooksMu.Unlock()
+
+	gauge := progressGauge{name: name, value: value}
+	idx := slices.IndexFunc(t.progressGauges, func(g progressGauge) bool { return g.name == name })
+	if idx >= 0 {
+		t.progressGauges[idx] = gauge
+		return
+	}
+
+	t.progressGauges = append(t.progressGauges, gauge)
+}
+
+// ProgressGauges reads the gauges registered with ProgressGauge, in the order they were registered.
+// It is called by f1 on every progress update; a panicking gauge is logged and left out.
+func (t *T) ProgressGauges() []ProgressGauge {
+	t.hooksMu.Lock()
+	gauges := slices.Clone(t.progressGauges)
+	t.hooksMu.Unlock()
+
+	values := make([]ProgressGauge, 0, len(gauges))
+	for _, gauge := range gauges {
+		func() {
+			defer func() {
+				if recovered := recover(); recovered != nil {
+					t.logger.Error("recovered panic in progress gauge", log.ErrorAnyAttr(recovered))
+				}
+			}()
+			values = append(values, ProgressGauge{Name: gauge.name, Value: gauge.value()})
+		}()
+	}
+
+	return values
+}
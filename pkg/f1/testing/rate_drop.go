@@ -0,0 +1,56 @@
+package testing
+
+import (
+	"slices"
+
+	"github.com/form3tech-oss/f1/v2/internal/log"
+)
+
+// RateDrop describes a significant drop of the rate iterations are started at, typically between
+// the stages of a run.
+type RateDrop struct {
+	// Stage is the name of the stage running after the drop, for triggers which run in stages
+	Stage string
+	// From is the highest rate, in iterations per second, since the previous drop
+	From float64
+	// To is the rate, in iterations per second, after the drop
+	To float64
+}
+
+// OnRateDrop registers a hook called when the rate of the run drops to half of its highest rate or
+// less, so that the scenario can trim pools warmed up by a peak, such as idle HTTP connections or
+// prepared statements, and low rate stages measure realistic behaviour. It must be called from the
+// setup of a scenario. Hooks are called from a goroutine of f1, concurrently with iterations.
+func (t *T) OnRateDrop(hook func(RateDrop)) {
+	t.hooksMu.Lock()
+	defer t.hooksMu.Unlock()
+
+	t.rateDropHooks = append(t.rateDropHooks, hook)
+}
+
+// HasRateDropHooks returns true if hooks were registered with OnRateDrop.
+func (t *T) HasRateDropHooks() bool {
+	t.hooksMu.Lock()
+	defer t.hooksMu.Unlock()
+
+	return len(t.rateDropHooks) > 0
+}
+
+// RateDropped calls the hooks registered with OnRateDrop. It is called by f1 while the run is in
+// progress; a panicking hook is logged without failing the run.
+func (t *T) RateDropped(drop RateDrop) {
+	t.hooksMu.Lock()
+	hooks := slices.Clone(t.rateDropHooks)
+	t.hooksMu.Unlock()
+
+	for _, hook := range hooks {
+		func() {
+			defer func() {
+				if recovered := recover(); recovered != nil {
+					t.logger.Error("recovered panic in rate drop hook", log.ErrorAnyAttr(recovered))
+				}
+			}()
+			hook(drop)
+		}()
+	}
+}
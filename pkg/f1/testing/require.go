@@ -0,0 +1,148 @@
+package testing
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrUnexpectedStatus is the error of iterations failed by RequireStatus.
+	ErrUnexpectedStatus = errors.New("unexpected status")
+	// ErrJSONNotEqual is the error of iterations failed by RequireJSONEq.
+	ErrJSONNotEqual = errors.New("json not equal")
+)
+
+const (
+	// maxJSONDifferences is the number of differences listed by RequireJSONEq, so that the error
+	// stays short enough to tell failures apart in the failures of the run
+	maxJSONDifferences = 3
+	// maxJSONValueLength is the length values are truncated to in the differences
+	maxJSONValueLength = 40
+)
+
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// RequireStatus fails the iteration and stops it, as FailNow, unless status is one of expected,
+// such as the status code of an HTTP response. The error wraps ErrUnexpectedStatus.
+func (t *T) RequireStatus(status int, expected ...int) {
+	if slices.Contains(expected, status) {
+		return
+	}
+
+	codes := make([]string, len(expected))
+	for i, code := range expected {
+		codes[i] = strconv.Itoa(code)
+	}
+	t.Fatal(fmt.Errorf("%w %d, expected %s", ErrUnexpectedStatus, status, strings.Join(codes, " or ")))
+}
+
+// RequireJSONEq fails the iteration and stops it, as FailNow, unless actual is the same json
+// document as expected, regardless of formatting and of the order of object keys. The error wraps
+// ErrJSONNotEqual and lists the first differences on a single line, by their path in the document,
+// so that failures with different differences are told apart in the failures of the run:
+//
+//	json not equal: $.status expected "accepted", got "rejected"; $.fee missing
+func (t *T) RequireJSONEq(expected, actual string) {
+	var expectedValue, actualValue any
+	if err := json.Unmarshal([]byte(expected), &expectedValue); err != nil {
+		t.Fatal(fmt.Errorf("%w: invalid expected json: %w", ErrJSONNotEqual, err))
+	}
+	if err := json.Unmarshal([]byte(actual), &actualValue); err != nil {
+		t.Fatal(fmt.Errorf("%w: invalid json: %w", ErrJSONNotEqual, err))
+	}
+
+	differences := jsonDifferences("$", expectedValue, actualValue, nil)
+	if len(differences) == 0 {
+		return
+	}
+
+	summary := strings.Join(differences[:min(len(differences), maxJSONDifferences)], "; ")
+	if more := len(differences) - maxJSONDifferences; more > 0 {
+		summary += fmt.Sprintf(" and %d more", more)
+	}
+	t.Fatal(fmt.Errorf("%w: %s", ErrJSONNotEqual, summary))
+}
+
+// jsonDifferences appends the differences between the json values at path to differences.
+func jsonDifferences(path string, expected, actual any, differences []string) []string {
+	switch expected := expected.(type) {
+	case map[string]any:
+		actual, ok := actual.(map[string]any)
+		if !ok {
+			break
+		}
+
+		keys := make([]string, 0, len(expected)+len(actual))
+		for key := range expected {
+			keys = append(keys, key)
+		}
+		for key := range actual {
+			if _, ok := expected[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		slices.Sort(keys)
+
+		for _, key := range keys {
+			expectedValue, inExpected := expected[key]
+			actualValue, inActual := actual[key]
+			switch {
+			case !inActual:
+				differences = append(differences, jsonKeyPath(path, key)+" missing")
+			case !inExpected:
+				differences = append(differences, jsonKeyPath(path, key)+" unexpected")
+			default:
+				differences = jsonDifferences(jsonKeyPath(path, key), expectedValue, actualValue, differences)
+			}
+		}
+		return differences
+	case []any:
+		actual, ok := actual.([]any)
+		if !ok {
+			break
+		}
+
+		if len(expected) != len(actual) {
+			differences = append(differences,
+				fmt.Sprintf("%s expected %d elements, got %d", path, len(expected), len(actual)))
+		}
+		for i := range min(len(expected), len(actual)) {
+			differences = jsonDifferences(fmt.Sprintf("%s[%d]", path, i), expected[i], actual[i], differences)
+		}
+		return differences
+	}
+
+	if reflect.DeepEqual(expected, actual) {
+		return differences
+	}
+
+	return append(differences,
+		fmt.Sprintf("%s expected %s, got %s", path, compactJSON(expected), compactJSON(actual)))
+}
+
+func jsonKeyPath(path, key string) string {
+	if identifier.MatchString(key) {
+		return path + "." + key
+	}
+
+	return fmt.Sprintf("%s[%q]", path, key)
+}
+
+// compactJSON returns value as compact json, truncated to maxJSONValueLength.
+func compactJSON(value any) string {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	if len(encoded) > maxJSONValueLength {
+		return string(encoded[:maxJSONValueLength]) + "..."
+	}
+
+	return string(encoded)
+}
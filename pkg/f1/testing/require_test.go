@@ -0,0 +1,108 @@
+package testing_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	f1testing "github.com/form3tech-oss/f1/v2/pkg/f1/testing"
+)
+
+// runIteration runs fn as an iteration, which may stop with FailNow, and returns its T.
+func runIteration(fn func(t *f1testing.T)) *f1testing.T {
+	newT, teardown := newT()
+	defer teardown()
+
+	done := make(chan struct{})
+	go func() {
+		defer catchPanics(done)
+		fn(newT)
+	}()
+	<-done
+
+	return newT
+}
+
+func TestRequireStatus(t *testing.T) {
+	t.Parallel()
+
+	passed := runIteration(func(t *f1testing.T) {
+		t.RequireStatus(http.StatusCreated, http.StatusOK, http.StatusCreated)
+	})
+	require.False(t, passed.Failed())
+
+	failed := runIteration(func(t *f1testing.T) {
+		t.RequireStatus(http.StatusServiceUnavailable, http.StatusOK, http.StatusCreated)
+	})
+	require.True(t, failed.Failed())
+	require.ErrorIs(t, failed.Err(), f1testing.ErrUnexpectedStatus)
+	require.EqualError(t, failed.Err(), "unexpected status 503, expected 200 or 201")
+}
+
+func TestRequireJSONEq(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name     string
+		expected string
+		actual   string
+		err      string
+	}{
+		{
+			name:     "equal documents formatted differently",
+			expected: `{"id": 1, "status": "accepted", "tags": ["a", "b"]}`,
+			actual:   `{"tags":["a","b"],"status":"accepted","id":1}`,
+		},
+		{
+			name:     "different values",
+			expected: `{"status": "accepted", "amount": {"value": 10, "currency": "GBP"}}`,
+			actual:   `{"status": "rejected", "amount": {"value": 10.5, "currency": "GBP"}}`,
+			err:      `json not equal: $.amount.value expected 10, got 10.5; $.status expected "accepted", got "rejected"`,
+		},
+		{
+			name:     "missing and unexpected keys",
+			expected: `{"id": 1, "fee": 2}`,
+			actual:   `{"id": 1, "fee-type": "flat"}`,
+			err:      `json not equal: $.fee missing; $["fee-type"] unexpected`,
+		},
+		{
+			name:     "arrays of different lengths",
+			expected: `[1, 2, 3]`,
+			actual:   `[1, 4]`,
+			err:      `json not equal: $ expected 3 elements, got 2; $[1] expected 2, got 4`,
+		},
+		{
+			name:     "different types",
+			expected: `{"items": []}`,
+			actual:   `{"items": null}`,
+			err:      `json not equal: $.items expected [], got null`,
+		},
+		{
+			name:     "more differences than listed",
+			expected: `{"a": 1, "b": 2, "c": 3, "d": 4, "e": 5}`,
+			actual:   `{}`,
+			err:      `json not equal: $.a missing; $.b missing; $.c missing and 2 more`,
+		},
+		{
+			name:     "invalid json",
+			expected: `{}`,
+			actual:   `<html>`,
+			err:      `json not equal: invalid json: invalid character '<' looking for beginning of value`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			iteration := runIteration(func(t *f1testing.T) { t.RequireJSONEq(test.expected, test.actual) })
+
+			if test.err == "" {
+				require.False(t, iteration.Failed())
+				return
+			}
+			require.True(t, iteration.Failed())
+			require.ErrorIs(t, iteration.Err(), f1testing.ErrJSONNotEqual)
+			require.EqualError(t, iteration.Err(), test.err)
+		})
+	}
+}
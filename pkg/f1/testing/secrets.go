@@ -0,0 +1,42 @@
+package testing
+
+import "github.com/form3tech-oss/f1/v2/pkg/f1/f1secrets"
+
+// WithSecrets sets the provider of the secrets of the scenario, see Secret.
+func WithSecrets(provider f1secrets.Provider) TOption {
+	return func(t *T) {
+		t.secrets = provider
+	}
+}
+
+// WithSecretRedaction sets the function redacting the values of the secrets fetched with Secret
+// from the logs, errors and reports of the run.
+func WithSecretRedaction(redact func(value string)) TOption {
+	return func(t *T) {
+		t.redactSecret = redact
+	}
+}
+
+// Secret returns the named secret from the secret provider of the scenario, set with
+// scenarios.Secrets, or from the environment variable with the name of the secret if the scenario
+// has no provider. The iteration fails if the secret can't be fetched. The secret is redacted
+// when it is logged or formatted, its value is redacted from the logs, errors and reports of the
+// run, and it is returned by Reveal:
+//
+//	req.Header.Set("Authorization", "Bearer "+t.Secret("api-token").Reveal())
+func (t *T) Secret(name string) f1secrets.Secret {
+	provider := t.secrets
+	if provider == nil {
+		provider = f1secrets.Env("")
+	}
+
+	secret, err := provider.Secret(t.Context(), name)
+	if err != nil {
+		t.Fatalf("fetching secret %s: %s", name, err)
+	}
+	if t.redactSecret != nil {
+		t.redactSecret(secret.Reveal())
+	}
+
+	return secret
+}
@@ -0,0 +1,69 @@
+package testing_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/log"
+	"github.com/form3tech-oss/f1/v2/internal/redact"
+	"github.com/form3tech-oss/f1/v2/pkg/f1/f1secrets"
+	f1testing "github.com/form3tech-oss/f1/v2/pkg/f1/testing"
+)
+
+func TestSecretIsFetchedFromTheProviderOfTheScenario(t *testing.T) {
+	t.Parallel()
+
+	provider := f1secrets.ProviderFunc(func(_ context.Context, name string) (f1secrets.Secret, error) {
+		if name != "api-token" {
+			return f1secrets.Secret{}, f1secrets.ErrNotFound
+		}
+		return f1secrets.NewSecret("token"), nil
+	})
+	newT, teardown := f1testing.NewTWithOptions("test",
+		f1testing.WithLogger(log.NewDiscardLogger()),
+		f1testing.WithSecrets(provider),
+	)
+	defer teardown()
+
+	require.Equal(t, "token", newT.Secret("api-token").Reveal())
+	require.False(t, newT.Failed())
+}
+
+func TestSecretIsRedacted(t *testing.T) {
+	t.Parallel()
+
+	redactor, err := redact.New(nil)
+	require.NoError(t, err)
+	provider := f1secrets.ProviderFunc(func(context.Context, string) (f1secrets.Secret, error) {
+		return f1secrets.NewSecret("s3cr3t-token"), nil
+	})
+	newT, teardown := f1testing.NewTWithOptions("test",
+		f1testing.WithLogger(log.NewDiscardLogger()),
+		f1testing.WithSecrets(provider),
+		f1testing.WithSecretRedaction(redactor.AddSecret),
+	)
+	defer teardown()
+
+	token := newT.Secret("api-token").Reveal()
+
+	require.Equal(t, "unauthorized token [REDACTED]", redactor.String("unauthorized token "+token))
+}
+
+func TestSecretFailsTheIterationWhenItIsMissing(t *testing.T) {
+	t.Parallel()
+
+	newT, teardown := newT()
+	defer teardown()
+
+	done := make(chan struct{})
+	go func() {
+		defer catchPanics(done)
+		newT.Secret("F1_TESTING_MISSING_SECRET")
+	}()
+	<-done
+
+	require.True(t, newT.Failed())
+	require.ErrorContains(t, newT.Err(), "fetching secret F1_TESTING_MISSING_SECRET: environment variable F1_TESTING_MISSING_SECRET: secret not found")
+}
@@ -0,0 +1,59 @@
+package testing
+
+import "time"
+
+// Stopwatch times consecutive segments of an iteration, such as authenticating, sending a request
+// and verifying its response, recording the duration of each segment like Time does.
+//
+//	sw := t.Stopwatch()
+//	token := authenticate()
+//	sw.Lap("auth")
+//	response := send(token)
+//	sw.Lap("request")
+//	verify(response)
+//	sw.Lap("verify")
+//
+// A Stopwatch must be used by a single goroutine.
+type Stopwatch struct {
+	t     *T
+	start time.Time
+}
+
+// Stopwatch returns a stopwatch started now.
+func (t *T) Stopwatch() *Stopwatch {
+	return &Stopwatch{t: t, start: time.Now()}
+}
+
+// Lap records the duration of the segment since the stopwatch was started or since the previous
+// lap, and starts the next segment. It returns the duration of the segment.
+func (s *Stopwatch) Lap(segment string) time.Duration {
+	now := time.Now()
+	duration := now.Sub(s.start)
+	s.start = now
+
+	s.t.recordSegment(segment, duration)
+
+	return duration
+}
+
+// Restart starts the next segment now, leaving the time since the previous lap out of the
+// segments, for example to skip waiting between segments.
+func (s *Stopwatch) Restart() {
+	s.start = time.Now()
+}
+
+// StartTimer starts timing a segment of the iteration, recorded like Time when the returned
+// function is called, for segments which don't fit in a function:
+//
+//	defer t.StartTimer("verify")()
+//
+// The returned function returns the duration of the segment.
+func (t *T) StartTimer(segment string) func() time.Duration {
+	start := time.Now()
+
+	return func() time.Duration {
+		duration := time.Since(start)
+		t.recordSegment(segment, duration)
+		return duration
+	}
+}
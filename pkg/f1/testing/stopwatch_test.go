@@ -0,0 +1,64 @@
+package testing_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	io_prometheus_client "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/metrics"
+	f1testing "github.com/form3tech-oss/f1/v2/pkg/f1/testing"
+)
+
+func TestSegmentsOfIterationsAreTimed(t *testing.T) {
+	t.Parallel()
+
+	m := metrics.NewInstance(prometheus.NewRegistry(), true)
+	newT, teardown := f1testing.NewTWithOptions("scenario", f1testing.WithMetrics(m))
+	defer teardown()
+
+	stopwatch := newT.Stopwatch()
+	time.Sleep(10 * time.Millisecond)
+	auth := stopwatch.Lap("auth")
+	time.Sleep(5 * time.Millisecond)
+	stopwatch.Restart()
+	request := stopwatch.Lap("request")
+	stopTimer := newT.StartTimer("verify")
+	newT.Fail()
+	verify := stopTimer()
+	newT.Time("verify", func() {})
+
+	assert.GreaterOrEqual(t, auth, 10*time.Millisecond)
+	assert.Less(t, request, 5*time.Millisecond)
+	assert.Positive(t, verify)
+
+	require.Equal(t, 3, collectedCount(m.Iteration))
+	assert.InDelta(t, float64(auth), sampleSum(t, m, "auth", metrics.SucessResult), 0)
+	assert.InDelta(t, float64(request), sampleSum(t, m, "request", metrics.SucessResult), 0)
+	assert.GreaterOrEqual(t, sampleSum(t, m, "verify", metrics.FailedResult), float64(verify))
+}
+
+func sampleSum(t *testing.T, m *metrics.Metrics, segment string, result metrics.ResultType) float64 {
+	t.Helper()
+
+	observer, err := m.Iteration.GetMetricWithLabelValues("scenario", segment, result.String())
+	require.NoError(t, err)
+	summary, ok := observer.(prometheus.Summary)
+	require.True(t, ok)
+
+	metric := &io_prometheus_client.Metric{}
+	require.NoError(t, summary.Write(metric))
+
+	return metric.GetSummary().GetSampleSum()
+}
+
+func collectedCount(collector prometheus.Collector) int {
+	collected := make(chan prometheus.Metric, 16)
+	collector.Collect(collected)
+	close(collected)
+
+	return len(collected)
+}
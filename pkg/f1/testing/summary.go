@@ -0,0 +1,26 @@
+package testing
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Summarize adds a line to the summary of the scenario, such as "created 10000 accounts, deleted
+// 10000", which is displayed at the end of the run and included in its report, to document the
+// side effects of the run on the system under test. It must be called on the T of the setup of
+// the scenario, either from the setup or from the functions registered with Cleanup or
+// ParallelCleanup, and may be called from multiple goroutines.
+func (t *T) Summarize(format string, args ...any) {
+	t.hooksMu.Lock()
+	defer t.hooksMu.Unlock()
+
+	t.summary = append(t.summary, fmt.Sprintf(format, args...))
+}
+
+// Summary returns the lines added with Summarize, in the order they were added.
+func (t *T) Summary() []string {
+	t.hooksMu.Lock()
+	defer t.hooksMu.Unlock()
+
+	return slices.Clone(t.summary)
+}
@@ -1,10 +1,12 @@
 package testing
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log/slog"
 	"runtime/debug"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -13,9 +15,13 @@ import (
 
 	"github.com/form3tech-oss/f1/v2/internal/log"
 	"github.com/form3tech-oss/f1/v2/internal/metrics"
+	"github.com/form3tech-oss/f1/v2/pkg/f1/f1secrets"
 )
 
-var errFailNow = errors.New("FailNow")
+var (
+	errFailNow = errors.New("FailNow")
+	errFailed  = errors.New("iteration failed")
+)
 
 // T is a type passed to Scenario functions to manage test state and support formatted test logs. A
 // test ends when its Scenario function returns or calls any of the methods FailNow, Fatal, Fatalf.
@@ -23,15 +29,53 @@ var errFailNow = errors.New("FailNow")
 // reporting methods, such as the variations of Log and Error, may be called simultaneously from
 // multiple goroutines.
 type T struct {
+	ctx            context.Context
+	cancel         context.CancelFunc
 	logrusLogger   *logrus.Logger
 	logger         *slog.Logger
+	metrics        *metrics.Metrics
 	require        *require.Assertions
 	Iteration      string // iteration number or "setup"
 	Scenario       string
+	operation      string
+	args           []string
 	teardownStack  []func()
+	rateDropHooks  []func(RateDrop)
+	progressGauges []progressGauge
+	labels         map[string]string
+	auditFields    map[string]string
+	labelsMu       sync.Mutex
+	teardownMu     sync.Mutex
+	hooksMu        sync.Mutex
+	ctxMu          sync.Mutex
+	err            atomic.Pointer[error]
 	failed         atomic.Bool
 	teardownFailed atomic.Bool
 	tearingDown    bool
+	// parallelCleanups are run together, with the errors they fail with in teardownErrs
+	parallelCleanups []parallelCleanup
+	teardownErrs     []error
+	// workerValues are kept across iterations, see WorkerLocal
+	workerValues   map[any]any
+	workerValuesMu sync.Mutex
+	// summary are the lines added with Summarize
+	summary []string
+	// parentCtx is the parent of the context of T, see WithContext
+	parentCtx context.Context
+	// phase returns the phase of the run, see Phase
+	phase func() Phase
+	// secrets optionally provides the secrets of the scenario, see Secret
+	secrets f1secrets.Provider
+	// stopping is closed once the run stops triggering iterations, see Sleep
+	stopping <-chan struct{}
+	// bytesSent and bytesReceived are the bytes recorded by the iteration, see RecordBytes
+	bytesSent     atomic.Uint64
+	bytesReceived atomic.Uint64
+	// recordStage optionally records the stages timed with Time, see WithStageRecorder
+	recordStage func(stage string, failed bool, duration time.Duration)
+	// redactSecret optionally redacts the values of the secrets fetched with Secret, see
+	// WithSecretRedaction
+	redactSecret func(value string)
 }
 
 type TOption func(*T)
@@ -51,6 +95,37 @@ func WithLogger(logger *slog.Logger) TOption {
 	}
 }
 
+// WithMetrics sets the metrics instance used to record stage durations with Time. Defaults
+// to the global metrics instance.
+func WithMetrics(m *metrics.Metrics) TOption {
+	return func(t *T) {
+		t.metrics = m
+	}
+}
+
+// WithStageRecorder sets a function called with the result and the duration of every stage timed
+// with Time, which records them instead of T recording them in the metrics.
+func WithStageRecorder(record func(stage string, failed bool, duration time.Duration)) TOption {
+	return func(t *T) {
+		t.recordStage = record
+	}
+}
+
+// WithOperation sets the name of the operation the iterations are triggered for, see Operation.
+func WithOperation(operation string) TOption {
+	return func(t *T) {
+		t.operation = operation
+	}
+}
+
+// WithContext sets the parent of the context returned by Context, so that the iterations of T are
+// cancelled with ctx, such as the connections of the connections trigger when they are closed.
+func WithContext(ctx context.Context) TOption {
+	return func(t *T) {
+		t.parentCtx = ctx
+	}
+}
+
 func WithIteration(iteration string) TOption {
 	return func(t *T) {
 		t.Iteration = iteration
@@ -88,10 +163,28 @@ func NewTWithOptions(scenarioName string, options ...TOption) (*T, func()) {
 
 func (t *T) Reset(iter string) {
 	t.Iteration = iter
+	t.labelsMu.Lock()
+	t.labels = nil
+	t.auditFields = nil
+	t.labelsMu.Unlock()
+	t.bytesSent.Store(0)
+	t.bytesReceived.Store(0)
+	t.err.Store(nil)
 	t.failed.Store(false)
 	t.teardownFailed.Store(false)
 	t.tearingDown = false
 	t.teardownStack = []func(){}
+	t.parallelCleanups = nil
+	t.teardownErrs = nil
+	t.ctxMu.Lock()
+	t.ctx, t.cancel = nil, nil
+	t.ctxMu.Unlock()
+}
+
+// Operation returns the name of the operation the iteration was triggered for, when the run
+// triggers the named operations of the scenario with their own rates. It is empty otherwise.
+func (t *T) Operation() string {
+	return t.operation
 }
 
 // Logger returns a logrus logger, needed for backwards compatibility. Use StandardLogger
@@ -122,46 +215,52 @@ func (t *T) Name() string {
 // the goroutine running the Scenario, not from other goroutines created during the Scenario.
 // Calling FailNow does not stop those other goroutines.
 func (t *T) FailNow() {
-	if t.tearingDown {
-		t.teardownFailed.Store(true)
-	} else {
-		t.failed.Store(true)
-	}
-
+	t.fail(errFailed)
 	panic(errFailNow)
 }
 
 // Fail marks the function as having failed but continues execution.
 func (t *T) Fail() {
+	t.fail(errFailed)
+}
+
+// fail marks the function as having failed, keeping the first error it failed with.
+func (t *T) fail(err error) {
 	if t.tearingDown {
 		t.teardownFailed.Store(true)
-	} else {
-		t.failed.Store(true)
+		return
 	}
+
+	t.err.CompareAndSwap(nil, &err)
+	t.failed.Store(true)
 }
 
 // Errorf is equivalent to Logf followed by Fail.
 func (t *T) Errorf(format string, args ...interface{}) {
-	t.logger.Error(fmt.Sprintf(format, args...))
-	t.Fail()
+	err := fmt.Errorf(format, args...)
+	t.logger.Error(err.Error())
+	t.fail(err)
 }
 
 // Error is equivalent to Log followed by Fail.
 func (t *T) Error(err error) {
 	t.logger.Error("iteration failed", log.IterationAttr(t.Iteration), log.ErrorAttr(err))
-	t.Fail()
+	t.fail(err)
 }
 
 // Fatalf is equivalent to Logf followed by FailNow.
 func (t *T) Fatalf(format string, args ...interface{}) {
-	t.logger.Error(fmt.Sprintf(format, args...))
-	t.FailNow()
+	err := fmt.Errorf(format, args...)
+	t.logger.Error(err.Error())
+	t.fail(err)
+	panic(errFailNow)
 }
 
 // Fatal is equivalent to Log followed by FailNow.
 func (t *T) Fatal(err error) {
 	t.logger.Error("iteration failed", log.IterationAttr(t.Iteration), log.ErrorAttr(err))
-	t.FailNow()
+	t.fail(err)
+	panic(errFailNow)
 }
 
 // Log formats its arguments using default formatting, analogous to Println, and records the text in the error log.
@@ -181,20 +280,149 @@ func (t *T) Failed() bool {
 	return t.failed.Load()
 }
 
+// Err returns the first error the function has failed with, or nil if it has not failed.
+func (t *T) Err() error {
+	if err := t.err.Load(); err != nil {
+		return *err
+	}
+
+	return nil
+}
+
 func (t *T) TeardownFailed() bool {
 	return t.teardownFailed.Load()
 }
 
-// Time records a metric for the duration of the given function
+// MaxLabels is the number of custom labels an iteration can set with WithLabel.
+const MaxLabels = 5
+
+// WithLabel attaches a custom label to the duration metric of the iteration, so that latency
+// can be broken down by, for example, the endpoint called by the iteration. Labels should have
+// a small set of values: only the first metrics.MaxLabelValues distinct values of a label are
+// recorded, only the first metrics.MaxLabelNames distinct labels of the scenario are recorded, and
+// an iteration can set at most MaxLabels labels.
+func (t *T) WithLabel(name, value string) {
+	t.labelsMu.Lock()
+	defer t.labelsMu.Unlock()
+
+	if _, ok := t.labels[name]; !ok && len(t.labels) >= MaxLabels {
+		t.logger.Warn(fmt.Sprintf("ignoring label %s, iterations can set at most %d labels", name, MaxLabels))
+		return
+	}
+
+	if t.labels == nil {
+		t.labels = make(map[string]string)
+	}
+	t.labels[name] = value
+}
+
+// Labels returns the custom labels set by the iteration with WithLabel.
+func (t *T) Labels() map[string]string {
+	t.labelsMu.Lock()
+	defer t.labelsMu.Unlock()
+
+	labels := make(map[string]string, len(t.labels))
+	for name, value := range t.labels {
+		labels[name] = value
+	}
+
+	return labels
+}
+
+// AuditField sets a custom field of the entry of the iteration in the audit log of the run, such as
+// the id of the resource the iteration created. Fields are only written when the run has an audit
+// log, see the --audit-log flag, and can have any number of values, unlike labels.
+func (t *T) AuditField(name, value string) {
+	t.labelsMu.Lock()
+	defer t.labelsMu.Unlock()
+
+	if t.auditFields == nil {
+		t.auditFields = make(map[string]string)
+	}
+	t.auditFields[name] = value
+}
+
+// AuditFields returns the custom fields set by the iteration with AuditField.
+func (t *T) AuditFields() map[string]string {
+	t.labelsMu.Lock()
+	defer t.labelsMu.Unlock()
+
+	if len(t.auditFields) == 0 {
+		return nil
+	}
+
+	fields := make(map[string]string, len(t.auditFields))
+	for name, value := range t.auditFields {
+		fields[name] = value
+	}
+
+	return fields
+}
+
+// RecordBytes records bytes sent and received by the iteration, such as the size of the requests
+// and responses of a file upload API, for the throughput of the run in its metrics and summary. It
+// can be called any number of times, and from multiple goroutines, adding to the bytes of the
+// iteration. Negative values are ignored.
+func (t *T) RecordBytes(sent, received int) {
+	if sent > 0 {
+		t.bytesSent.Add(uint64(sent))
+	}
+	if received > 0 {
+		t.bytesReceived.Add(uint64(received))
+	}
+}
+
+// Bytes returns the bytes sent and received by the iteration, as recorded with RecordBytes.
+func (t *T) Bytes() (uint64, uint64) {
+	return t.bytesSent.Load(), t.bytesReceived.Load()
+}
+
+// Time records a metric for the duration of the given function, as a segment of the iteration
+// named stageName. Segments which don't fit in a function can be timed with StartTimer or a
+// Stopwatch.
 func (t *T) Time(stageName string, f func()) {
 	start := time.Now()
-	defer recordTime(t, stageName, start)
+	defer func() { t.recordSegment(stageName, time.Since(start)) }()
 	f()
 }
 
+// Context returns a context which is cancelled when the scenario or the iteration completes, just
+// before the functions registered with Cleanup are called. It can be used to stop background work
+// started by the scenario, such as refreshing credentials. With the connections trigger, it is also
+// cancelled when the connection held open by the iteration is closed.
+func (t *T) Context() context.Context {
+	t.ctxMu.Lock()
+	defer t.ctxMu.Unlock()
+
+	// the context is created on demand, so that iterations which don't use it don't allocate it
+	if t.ctx == nil {
+		parent := t.parentCtx
+		if parent == nil {
+			parent = context.Background()
+		}
+		t.ctx, t.cancel = context.WithCancel(parent)
+	}
+
+	return t.ctx
+}
+
+func (t *T) cancelContext() {
+	t.ctxMu.Lock()
+	defer t.ctxMu.Unlock()
+
+	if t.cancel != nil {
+		t.cancel()
+	}
+}
+
 // Cleanup registers a function to be called when the scenario or the iteration completes.
-// Cleanup functions will be called in last added, first called order.
+// Cleanup functions will be called in last added, first called order, after the functions
+// registered with ParallelCleanup. It may be called from multiple goroutines, such as the setups
+// of an f1.SetupGraph.
 func (t *T) Cleanup(f func()) {
+	t.teardownMu.Lock()
+	defer t.teardownMu.Unlock()
+
 	t.teardownStack = append(t.teardownStack, f)
 }
 
@@ -222,7 +450,7 @@ func handlePanic(t *T, recovered any) {
 			log.IterationAttr(t.Iteration),
 			log.ErrorAttr(err),
 		)
-		t.Fail()
+		t.fail(err)
 	default:
 		stack := debug.Stack()
 		t.logger.Error("recovered panic in scenario",
@@ -230,12 +458,14 @@ func handlePanic(t *T, recovered any) {
 			log.IterationAttr(t.Iteration),
 			log.ErrorAnyAttr(recovered),
 		)
-		t.Fail()
+		t.fail(fmt.Errorf("panic: %v", recovered))
 	}
 }
 
 func (t *T) teardown() {
 	t.tearingDown = true
+	t.cancelContext()
+	t.runParallelCleanups()
 
 	for i := len(t.teardownStack) - 1; i >= 0; i-- {
 		func() {
@@ -245,11 +475,33 @@ func (t *T) teardown() {
 	}
 }
 
-func recordTime(t *T, stageName string, start time.Time) {
-	metrics.Instance().RecordIterationStage(
+// metricsInstance returns the metrics instance set with WithMetrics, or the global instance, which
+// is nil before the metrics are initialised.
+func (t *T) metricsInstance() *metrics.Metrics {
+	if t.metrics != nil {
+		return t.metrics
+	}
+
+	return metrics.Instance()
+}
+
+// recordSegment records the duration of a segment of the iteration as the stage label of the
+// iteration metric, with the result of the iteration so far.
+func (t *T) recordSegment(segment string, duration time.Duration) {
+	if t.recordStage != nil {
+		t.recordStage(segment, t.Failed(), duration)
+		return
+	}
+
+	m := t.metricsInstance()
+	if m == nil {
+		return
+	}
+
+	m.RecordIterationStage(
 		t.Scenario,
-		stageName,
+		segment,
 		metrics.Result(t.Failed()),
-		time.Since(start).Nanoseconds(),
+		duration.Nanoseconds(),
 	)
 }
@@ -2,10 +2,13 @@ package testing_test
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"log/slog"
 	"regexp"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -77,6 +80,35 @@ func TestCleanupCalledInReverseOrder(t *testing.T) {
 	require.Equal(t, expected, actual)
 }
 
+func TestContextIsCancelledBeforeCleanup(t *testing.T) {
+	t.Parallel()
+
+	newT, teardown := newT()
+	ctx := newT.Context()
+
+	var errOnCleanup error
+	newT.Cleanup(func() {
+		errOnCleanup = ctx.Err()
+	})
+
+	require.NoError(t, ctx.Err())
+	teardown()
+	require.ErrorIs(t, errOnCleanup, context.Canceled)
+}
+
+func TestResetRenewsContext(t *testing.T) {
+	t.Parallel()
+
+	newT, teardown := newT()
+	ctx := newT.Context()
+	teardown()
+
+	newT.Reset("1")
+
+	require.ErrorIs(t, ctx.Err(), context.Canceled)
+	require.NoError(t, newT.Context().Err())
+}
+
 func TestFailNowSetsTheFailedState(t *testing.T) {
 	t.Parallel()
 
@@ -186,3 +218,228 @@ func newT() (*f1testing.T, func()) {
 		f1testing.WithLogrusLogger(logrus),
 	)
 }
+
+func TestErrReturnsFirstError(t *testing.T) {
+	t.Parallel()
+
+	newT, teardown := newT()
+	defer teardown()
+
+	require.NoError(t, newT.Err())
+
+	newT.Error(errors.New("first"))
+	newT.Errorf("second")
+
+	require.EqualError(t, newT.Err(), "first")
+
+	newT.Reset("1")
+
+	require.NoError(t, newT.Err())
+}
+
+func TestRateDropHooksAreCalledDespitePanics(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+	newT, teardown := f1testing.NewTWithOptions("test", f1testing.WithLogger(logger))
+	defer teardown()
+
+	var drops []f1testing.RateDrop
+	newT.OnRateDrop(func(f1testing.RateDrop) {
+		panic("boom")
+	})
+	newT.OnRateDrop(func(drop f1testing.RateDrop) {
+		drops = append(drops, drop)
+	})
+	require.True(t, newT.HasRateDropHooks())
+
+	newT.RateDropped(f1testing.RateDrop{Stage: "cool down", From: 100, To: 10})
+
+	require.Equal(t, []f1testing.RateDrop{{Stage: "cool down", From: 100, To: 10}}, drops)
+	require.Contains(t, buf.String(), "recovered panic in rate drop hook")
+	require.False(t, newT.Failed())
+}
+
+func TestProgressGauges(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+	newT, teardown := f1testing.NewTWithOptions("test", f1testing.WithLogger(logger))
+	defer teardown()
+
+	require.Empty(t, newT.ProgressGauges())
+
+	newT.ProgressGauge("accounts created", func() float64 { return 10 })
+	newT.ProgressGauge("broken", func() float64 { panic("boom") })
+	newT.ProgressGauge("queue depth", func() float64 { return 3 })
+	newT.ProgressGauge("accounts created", func() float64 { return 12 })
+
+	require.Equal(t, []f1testing.ProgressGauge{
+		{Name: "accounts created", Value: 12},
+		{Name: "queue depth", Value: 3},
+	}, newT.ProgressGauges())
+	require.Contains(t, buf.String(), "recovered panic in progress gauge")
+	require.False(t, newT.Failed())
+}
+
+func TestSummaryIsAddedFromSetupAndCleanups(t *testing.T) {
+	t.Parallel()
+
+	newT, teardown := f1testing.NewTWithOptions("test")
+	require.Empty(t, newT.Summary())
+
+	newT.Summarize("created %d queues", 2)
+	newT.Cleanup(func() { newT.Summarize("deleted %d accounts", 10) })
+	newT.ParallelCleanup("accounts", time.Second, func(context.Context) error {
+		newT.Summarize("deleted %d queues", 2)
+		return nil
+	})
+	teardown()
+
+	require.Equal(t, []string{"created 2 queues", "deleted 2 queues", "deleted 10 accounts"}, newT.Summary())
+}
+
+func TestParallelCleanupsRunConcurrentlyBeforeCleanup(t *testing.T) {
+	t.Parallel()
+
+	newT, teardown := newT()
+
+	var parallelDone atomic.Int32
+	var doneBeforeCleanup int32
+	newT.Cleanup(func() {
+		doneBeforeCleanup = parallelDone.Load()
+	})
+	for _, name := range []string{"queues", "accounts", "payments"} {
+		newT.ParallelCleanup(name, time.Second, func(context.Context) error {
+			time.Sleep(100 * time.Millisecond)
+			parallelDone.Add(1)
+			return nil
+		})
+	}
+
+	start := time.Now()
+	teardown()
+
+	require.Less(t, time.Since(start), 250*time.Millisecond)
+	require.Equal(t, int32(3), doneBeforeCleanup)
+	require.False(t, newT.TeardownFailed())
+	require.NoError(t, newT.TeardownErr())
+}
+
+func TestParallelCleanupErrorsAreAggregated(t *testing.T) {
+	t.Parallel()
+
+	newT, teardown := newT()
+
+	errDelete := errors.New("delete failed")
+	newT.ParallelCleanup("succeeds", time.Second, func(context.Context) error { return nil })
+	newT.ParallelCleanup("fails", time.Second, func(context.Context) error { return errDelete })
+	newT.ParallelCleanup("panics", time.Second, func(context.Context) error { panic("boom") })
+
+	teardown()
+
+	require.True(t, newT.TeardownFailed())
+	require.False(t, newT.Failed())
+	err := newT.TeardownErr()
+	require.ErrorIs(t, err, errDelete)
+	require.ErrorContains(t, err, "cleanup fails: delete failed")
+	require.ErrorContains(t, err, "cleanup panics: panic: boom")
+	require.NotContains(t, err.Error(), "succeeds")
+}
+
+func TestParallelCleanupTimesOut(t *testing.T) {
+	t.Parallel()
+
+	newT, teardown := newT()
+
+	ctxErr := make(chan error, 1)
+	newT.ParallelCleanup("respects context", 50*time.Millisecond, func(ctx context.Context) error {
+		<-ctx.Done()
+		ctxErr <- ctx.Err()
+		return ctx.Err()
+	})
+	newT.ParallelCleanup("ignores context", 50*time.Millisecond, func(context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	})
+
+	start := time.Now()
+	teardown()
+
+	require.Less(t, time.Since(start), 500*time.Millisecond)
+	require.ErrorIs(t, newT.TeardownErr(), f1testing.ErrCleanupTimeout)
+	require.ErrorContains(t, newT.TeardownErr(), "cleanup ignores context: cleanup timed out after 50ms")
+	require.ErrorIs(t, <-ctxErr, context.DeadlineExceeded)
+}
+
+func TestResetClearsParallelCleanups(t *testing.T) {
+	t.Parallel()
+
+	newT, teardown := newT()
+
+	calls := 0
+	newT.ParallelCleanup("fails", time.Second, func(context.Context) error {
+		calls++
+		return errors.New("failed")
+	})
+	teardown()
+	require.Error(t, newT.TeardownErr())
+
+	newT.Reset("1")
+	teardown()
+
+	require.Equal(t, 1, calls)
+	require.NoError(t, newT.TeardownErr())
+	require.False(t, newT.TeardownFailed())
+}
+
+func TestRecordBytesAddsUpUntilReset(t *testing.T) {
+	t.Parallel()
+
+	newT, teardown := newT()
+	defer teardown()
+
+	newT.RecordBytes(1024, 64)
+	newT.RecordBytes(512, -1)
+	newT.RecordBytes(-1, 32)
+
+	sent, received := newT.Bytes()
+	require.Equal(t, uint64(1536), sent)
+	require.Equal(t, uint64(96), received)
+
+	newT.Reset("1")
+
+	sent, received = newT.Bytes()
+	require.Zero(t, sent)
+	require.Zero(t, received)
+}
+
+func TestTimedStagesAreRecordedWithTheirResult(t *testing.T) {
+	t.Parallel()
+
+	type stage struct {
+		name   string
+		failed bool
+	}
+	var stages []stage
+	newT, teardown := f1testing.NewTWithOptions("test",
+		f1testing.WithLogger(log.NewDiscardLogger()),
+		f1testing.WithStageRecorder(func(name string, failed bool, duration time.Duration) {
+			require.Positive(t, duration)
+			stages = append(stages, stage{name: name, failed: failed})
+		}),
+	)
+	defer teardown()
+
+	newT.Time("create", func() { time.Sleep(time.Millisecond) })
+	newT.Time("fetch", func() {
+		time.Sleep(time.Millisecond)
+		newT.Fail()
+	})
+
+	require.Equal(t, []stage{{name: "create"}, {name: "fetch", failed: true}}, stages)
+}
@@ -0,0 +1,69 @@
+package testing
+
+import (
+	"io"
+
+	"github.com/form3tech-oss/f1/v2/internal/log"
+)
+
+// WorkerLocal is a value kept by each worker of a run for all the iterations it runs, such as a
+// buffer or a client, so that high rate scenarios don't allocate it on every iteration. It is
+// created in the setup of a scenario and read by the iterations with Get:
+//
+//	buffers := testing.NewWorkerLocal(func() *bytes.Buffer { return new(bytes.Buffer) })
+//	return func(t *testing.T) {
+//		buf := buffers.Get(t)
+//		buf.Reset()
+//		...
+//	}
+//
+// Values aren't shared between workers, so they need no locking, but an iteration gets the value
+// as the previous iteration of its worker left it, even if that iteration failed. Values which
+// implement io.Closer are closed when their worker stops.
+type WorkerLocal[V any] struct {
+	newValue func() V
+}
+
+// NewWorkerLocal returns a value kept by each worker, which newValue creates on the first
+// iteration of the worker reading it.
+func NewWorkerLocal[V any](newValue func() V) *WorkerLocal[V] {
+	return &WorkerLocal[V]{newValue: newValue}
+}
+
+// Get returns the value of the worker running the iteration t, creating it on the first
+// iteration of the worker. It must be called from iterations rather than from the setup.
+func (l *WorkerLocal[V]) Get(t *T) V {
+	t.workerValuesMu.Lock()
+	defer t.workerValuesMu.Unlock()
+
+	if value, ok := t.workerValues[l]; ok {
+		return value.(V) //nolint:forcetypeassert // values are stored by their WorkerLocal
+	}
+
+	value := l.newValue()
+	if t.workerValues == nil {
+		t.workerValues = map[any]any{}
+	}
+	t.workerValues[l] = value
+
+	return value
+}
+
+// ReleaseWorkerValues closes the values read with WorkerLocal.Get which implement io.Closer, and
+// forgets all of them. It is called by f1 when the worker running the iterations of t stops.
+func (t *T) ReleaseWorkerValues() {
+	t.workerValuesMu.Lock()
+	values := t.workerValues
+	t.workerValues = nil
+	t.workerValuesMu.Unlock()
+
+	for _, value := range values {
+		closer, ok := value.(io.Closer)
+		if !ok {
+			continue
+		}
+		if err := closer.Close(); err != nil {
+			t.logger.Error("closing worker value failed", log.ErrorAttr(err))
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package testing_test
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	f1testing "github.com/form3tech-oss/f1/v2/pkg/f1/testing"
+)
+
+type closer struct {
+	closed int
+	err    error
+}
+
+func (c *closer) Close() error {
+	c.closed++
+	return c.err
+}
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestWorkerLocalValuesAreKeptAcrossIterations(t *testing.T) {
+	t.Parallel()
+
+	created := 0
+	values := f1testing.NewWorkerLocal(func() *closer {
+		created++
+		return &closer{}
+	})
+	worker, teardown := f1testing.NewTWithOptions("scenario", f1testing.WithLogger(discardLogger()))
+	defer teardown()
+
+	first := values.Get(worker)
+	worker.Reset("2")
+	second := values.Get(worker)
+
+	assert.Same(t, first, second)
+	assert.Equal(t, 1, created)
+
+	otherWorker, otherTeardown := f1testing.NewTWithOptions("scenario")
+	defer otherTeardown()
+
+	assert.NotSame(t, first, values.Get(otherWorker))
+	assert.Equal(t, 2, created)
+}
+
+func TestWorkerLocalValuesAreClosedWhenReleased(t *testing.T) {
+	t.Parallel()
+
+	buffers := f1testing.NewWorkerLocal(func() []byte { return make([]byte, 0, 1024) })
+	closers := f1testing.NewWorkerLocal(func() *closer { return &closer{} })
+	failingClosers := f1testing.NewWorkerLocal(func() *closer { return &closer{err: errors.New("close failed")} })
+	worker, teardown := f1testing.NewTWithOptions("scenario", f1testing.WithLogger(discardLogger()))
+	defer teardown()
+
+	buffers.Get(worker)
+	value := closers.Get(worker)
+	failing := failingClosers.Get(worker)
+	worker.ReleaseWorkerValues()
+	worker.ReleaseWorkerValues()
+
+	assert.Equal(t, 1, value.closed)
+	assert.Equal(t, 1, failing.closed)
+	assert.NotSame(t, value, closers.Get(worker))
+}
@@ -0,0 +1,102 @@
+// Package trigger is the API for building custom trigger modes, which start the iterations of
+// a scenario at rates not covered by the trigger modes built into f1.
+//
+// A trigger mode is described by a Builder, registered with (*f1.F1).WithTriggers, and runs as
+// a subcommand of `f1 run`. It returns a Trigger with a RateFunction, called by the iteration
+// worker of f1 every IterationDuration to get the number of iterations to start. For example, a
+// trigger mode whose rate follows a sine wave:
+//
+//	flags := pflag.NewFlagSet("sine", pflag.ContinueOnError)
+//	flags.Int("peak", 10, "highest number of iterations to start per second")
+//
+//	sine := trigger.Builder{
+//		Name:        "sine <scenario>",
+//		Description: "starts iterations at a rate following a sine wave",
+//		Flags:       flags,
+//		New: func(flags *pflag.FlagSet) (*trigger.Trigger, error) {
+//			peak, err := flags.GetInt("peak")
+//			if err != nil {
+//				return nil, err
+//			}
+//			start := time.Now()
+//			rate := func(now time.Time) int {
+//				return int(float64(peak) * (1 + math.Sin(now.Sub(start).Seconds())) / 2)
+//			}
+//			return &trigger.Trigger{
+//				Rate:              trigger.WithJitter(rate, 10),
+//				IterationDuration: time.Second,
+//				Description:       fmt.Sprintf("sine wave peaking at %d/s", peak),
+//			}, nil
+//		},
+//	}
+//
+//	f1.New().WithTriggers(sine).Add("myTest", myScenario).Execute()
+package trigger
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/form3tech-oss/f1/v2/internal/trigger/api"
+)
+
+// RateFunction returns the number of iterations to start at the given time.
+type RateFunction func(time.Time) int
+
+// Distribution spreads the iterations started every IterationDuration over smaller steps.
+type Distribution string
+
+const (
+	// NoDistribution starts all the iterations of an IterationDuration at once.
+	NoDistribution Distribution = "none"
+	// RegularDistribution starts the iterations of an IterationDuration evenly over steps of 100ms.
+	RegularDistribution Distribution = "regular"
+	// RandomDistribution starts the iterations of an IterationDuration at random steps of 100ms.
+	RandomDistribution Distribution = "random"
+)
+
+// Builder describes a custom trigger mode.
+type Builder struct {
+	// New returns the trigger configured by the flags of the command line.
+	New func(flags *pflag.FlagSet) (*Trigger, error)
+	// Flags are the flags specific to the trigger mode, in addition to the flags common to all
+	// trigger modes such as --max-duration and --concurrency.
+	Flags *pflag.FlagSet
+	// Name is the name of the `f1 run` subcommand, followed by its arguments, e.g. "sine <scenario>".
+	Name        string
+	Description string
+}
+
+// Trigger starts iterations at the rate returned by Rate every IterationDuration.
+type Trigger struct {
+	Rate              RateFunction
+	Description       string
+	IterationDuration time.Duration
+	// Duration optionally limits the duration of the run, if it is shorter than --max-duration.
+	Duration time.Duration
+}
+
+// WithJitter varies the rate randomly by up to jitter percent, while keeping the total number of
+// iterations close to the original rate.
+func WithJitter(rate RateFunction, jitter float64) RateFunction {
+	return RateFunction(api.WithJitter(api.RateFunction(rate), jitter))
+}
+
+// WithDistribution spreads the iterations started every iterationDuration with the given
+// distribution, returning the duration between steps and the rate of every step.
+func WithDistribution(
+	distribution Distribution,
+	iterationDuration time.Duration,
+	rate RateFunction,
+) (time.Duration, RateFunction, error) {
+	stepDuration, stepRate, err := api.NewDistribution(
+		api.DistributionType(distribution), api.DefaultDistributionWindow, iterationDuration, api.RateFunction(rate), nil,
+	)
+	if err != nil {
+		return 0, nil, fmt.Errorf("distributing rate: %w", err)
+	}
+
+	return stepDuration, RateFunction(stepRate), nil
+}
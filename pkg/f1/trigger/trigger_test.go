@@ -0,0 +1,84 @@
+package trigger_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/pkg/f1/trigger"
+)
+
+func TestWithDistribution(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		distribution         trigger.Distribution
+		iterationDuration    time.Duration
+		expectedStepDuration time.Duration
+		expectedTotal        int
+	}{
+		{
+			distribution:         trigger.NoDistribution,
+			iterationDuration:    time.Second,
+			expectedStepDuration: time.Second,
+			expectedTotal:        10,
+		},
+		{
+			distribution:         trigger.RegularDistribution,
+			iterationDuration:    time.Second,
+			expectedStepDuration: 100 * time.Millisecond,
+			expectedTotal:        10,
+		},
+		{
+			distribution:         trigger.RandomDistribution,
+			iterationDuration:    time.Second,
+			expectedStepDuration: 100 * time.Millisecond,
+			expectedTotal:        10,
+		},
+	} {
+		t.Run(string(test.distribution), func(t *testing.T) {
+			t.Parallel()
+
+			stepDuration, rate, err := trigger.WithDistribution(
+				test.distribution, test.iterationDuration, func(time.Time) int { return 10 },
+			)
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedStepDuration, stepDuration)
+
+			now := time.Now()
+			total := 0
+			for i := time.Duration(0); i < test.iterationDuration; i += stepDuration {
+				total += rate(now.Add(i))
+			}
+			assert.Equal(t, test.expectedTotal, total)
+		})
+	}
+}
+
+func TestWithDistributionRejectsUnknownDistributions(t *testing.T) {
+	t.Parallel()
+
+	_, _, err := trigger.WithDistribution("bursty", time.Second, func(time.Time) int { return 10 })
+
+	require.Error(t, err)
+}
+
+func TestWithJitter(t *testing.T) {
+	t.Parallel()
+
+	rate := trigger.WithJitter(func(time.Time) int { return 100 }, 10)
+
+	now := time.Now()
+	total := 0
+	distinct := map[int]struct{}{}
+	for i := range 100 {
+		r := rate(now.Add(time.Duration(i) * time.Second))
+		total += r
+		distinct[r] = struct{}{}
+	}
+
+	assert.InDelta(t, 100*100, total, 20)
+	assert.Greater(t, len(distinct), 1)
+}
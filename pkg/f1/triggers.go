@@ -0,0 +1,60 @@
+package f1
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	"github.com/form3tech-oss/f1/v2/internal/trigger/api"
+	"github.com/form3tech-oss/f1/v2/pkg/f1/trigger"
+)
+
+// WithTriggers registers custom trigger modes, which run as subcommands of `f1 run` alongside the
+// trigger modes built into f1. See the trigger package for how to build them.
+func (f *F1) WithTriggers(builders ...trigger.Builder) *F1 {
+	f.triggers = append(f.triggers, builders...)
+	return f
+}
+
+// customBuilders adapts custom trigger modes to the builders of the built in trigger modes.
+func customBuilders(builders []trigger.Builder) []api.Builder {
+	apiBuilders := make([]api.Builder, len(builders))
+	for i, builder := range builders {
+		flags := builder.Flags
+		if flags == nil {
+			flags = pflag.NewFlagSet(builder.Name, pflag.ContinueOnError)
+		}
+
+		apiBuilders[i] = api.Builder{
+			Name:        builder.Name,
+			Description: builder.Description,
+			Flags:       flags,
+			New: func(flags *pflag.FlagSet) (*api.Trigger, error) {
+				t, err := builder.New(flags)
+				if err != nil {
+					return nil, fmt.Errorf("creating trigger %s: %w", builder.Name, err)
+				}
+				if t.Rate == nil {
+					return nil, fmt.Errorf("creating trigger %s: %w", builder.Name, errMissingRate)
+				}
+				if t.IterationDuration <= 0 {
+					return nil, fmt.Errorf("creating trigger %s: %w", builder.Name, errInvalidIterationDuration)
+				}
+
+				return api.NewRatesTrigger(&api.Rates{
+					Rate:              api.RateFunction(t.Rate),
+					IterationDuration: t.IterationDuration,
+					Duration:          t.Duration,
+				}, t.Description), nil
+			},
+		}
+	}
+
+	return apiBuilders
+}
+
+var (
+	errMissingRate              = errors.New("missing rate function")
+	errInvalidIterationDuration = errors.New("iteration duration must be positive")
+)